@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Signer(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := []byte(strings.Repeat("s", MinSecretLen))
+	s, err := NewSigner(secret, func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewSigner([]byte(strings.Repeat("o", MinSecretLen)), func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSigner(secret[:MinSecretLen-1], nil); err != ErrShortSecret {
+		t.Errorf("NewSigner() short secret error = %v, want %v", err, ErrShortSecret)
+	}
+
+	token := s.Sign(7, ScopeAPI, time.Hour)
+	body, _, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name    string
+		verify  *Signer
+		token   string
+		scope   string
+		want    Claims
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			verify: s,
+			token:  token,
+			scope:  ScopeAPI,
+			want:   Claims{UserID: 7, Scope: ScopeAPI, Expiry: now.Add(time.Hour).Unix()},
+		},
+		{
+			name:    "wrong scope",
+			verify:  s,
+			token:   token,
+			scope:   ScopeStream,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other secret",
+			verify:  other,
+			token:   token,
+			scope:   ScopeAPI,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "tampered",
+			verify:  s,
+			token:   other.Sign(8, ScopeAPI, time.Hour)[:len(body)] + token[len(body):],
+			scope:   ScopeAPI,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "malformed",
+			verify:  s,
+			token:   "not-a-token",
+			scope:   ScopeAPI,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "expired",
+			verify:  s,
+			token:   s.Sign(7, ScopeAPI, 0),
+			scope:   ScopeAPI,
+			wantErr: ErrExpiredToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verify.Verify(tt.token, tt.scope)
+			if err != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UserID(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := UserID(ctx); ok {
+		t.Errorf("UserID() on a bare context reported a user")
+	}
+	if id, ok := UserID(WithUserID(ctx, 7)); !ok || id != 7 {
+		t.Errorf("UserID() = %d, %v, want 7, true", id, ok)
+	}
+}
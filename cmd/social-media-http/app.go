@@ -1,26 +1,279 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
 	"github.com/gadhittana01/socialmedia/config"
 	"github.com/gadhittana01/socialmedia/db"
 	"github.com/gadhittana01/socialmedia/handler/resthttp"
+	"github.com/gadhittana01/socialmedia/helper"
+	"github.com/gadhittana01/socialmedia/migration"
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/pkg/message"
+	"github.com/gadhittana01/socialmedia/pkg/notification"
 	"github.com/gadhittana01/socialmedia/pkg/post"
 	"github.com/gadhittana01/socialmedia/pkg/post_tags"
+	"github.com/gadhittana01/socialmedia/pkg/stream"
 	"github.com/gadhittana01/socialmedia/pkg/tag"
+	"github.com/gadhittana01/socialmedia/pkg/user"
 	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/storage"
+	"github.com/lib/pq"
 )
 
 func initApp(c *config.GlobalConfig) error {
-	db := db.InitDB()
+	tp, shutdown, err := helper.InitTracer(context.Background(), c.Tracing)
+	if err != nil {
+		return err
+	}
+	defer shutdown(context.Background())
+
+	sqlDB := db.InitDB(c.DB)
+	if c.DB.AutoMigrate {
+		m, err := migration.NewMigrator(sqlDB)
+		if err != nil {
+			return err
+		}
+		from, to, err := m.Up(context.Background(), 0)
+		if err != nil {
+			return err
+		}
+		slog.Info("migrated", "from", from, "to", to)
+	}
+
+	listener, err := db.NewListener(c.DB, services.StreamChannel)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	db := db.NewTracedDB(sqlDB, tp)
 	postPkg := post.New(db)
 	tagPkg := tag.New(db)
 	postTagPkg := post_tags.New(db)
+	userPkg := user.New(db)
+	bookmarkPkg := bookmark.New(db)
+	mediaPkg := media.New(db)
+
+	ps, err := services.NewPostService(postPkg, tagPkg, postTagPkg, bookmarkPkg, mediaPkg, services.NewTxRunner(sqlDB, tp))
+	if err != nil {
+		return err
+	}
+	us, err := services.NewUserService(userPkg)
+	if err != nil {
+		return err
+	}
+	prs, err := services.NewProfileService(userPkg, services.NewTxRunner(sqlDB, tp),
+		time.Duration(c.Users.HandleChangeCooldownHours)*time.Hour,
+		time.Duration(c.Users.HandleRedirectHours)*time.Hour, nil)
+	if err != nil {
+		return err
+	}
+	ts, err := services.NewTagService(tagPkg)
+	if err != nil {
+		return err
+	}
+	bs, err := services.NewBookmarkService(bookmarkPkg)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.NewLocal(c.Media.Dir)
+	if err != nil {
+		return err
+	}
+	ms, err := services.NewMediaService(mediaPkg, store, c.Media.MaxUploadBytes, c.Media.ThumbnailSize)
+	if err != nil {
+		return err
+	}
+
+	is, err := services.NewIdempotencyService(sqlDB, c.HTTP.IdempotencyTTLHours)
+	if err != nil {
+		return err
+	}
+	is = services.NewTracedIdempotencyService(is, tp)
+	go purgeIdempotencyKeys(is, time.Hour)
+
+	trs, err := services.NewTrendingService(tagPkg, nil)
+	if err != nil {
+		return err
+	}
+	trs = services.NewTracedTrendingService(trs, tp)
+	go refreshTrendingTags(trs, time.Duration(c.Jobs.TrendingRefreshSeconds)*time.Second)
+
+	ss, err := services.NewSchedulerService(postPkg, nil)
+	if err != nil {
+		return err
+	}
+	ss = services.NewTracedSchedulerService(ss, tp)
+	go publishScheduledPosts(ss, time.Duration(c.Jobs.PublishIntervalSeconds)*time.Second)
 
-	ps, err := services.NewPostService(postPkg, tagPkg, postTagPkg)
+	ns, err := services.NewNotificationService(notification.New(db), services.NewTxRunner(sqlDB, tp))
 	if err != nil {
 		return err
 	}
+	ns = services.NewTracedNotificationService(ns, tp)
+	go processNotificationEvents(ns, time.Duration(c.Jobs.NotificationIntervalSeconds)*time.Second)
+
+	mgs, err := services.NewMessageService(message.New(db), services.NewTxRunner(sqlDB, tp))
+	if err != nil {
+		return err
+	}
+
+	sts, err := services.NewStreamService(stream.New(db), c.Stream.BufferSize, c.Stream.RetentionMinutes)
+	if err != nil {
+		return err
+	}
+	sts = services.NewTracedStreamService(sts, tp)
+	go dispatchStreamEvents(listener, sts)
+	go purgeStreamEvents(sts, time.Minute)
+
+	var signer *auth.Signer
+	if c.Auth.Secret != "" {
+		signer, err = auth.NewSigner([]byte(c.Auth.Secret), nil)
+		if err != nil {
+			return err
+		}
+	} else {
+		slog.Warn("auth.secret is not set, endpoints that need a caller will answer 401")
+	}
+
 	return startHTTPServer(resthttp.NewRoutes(resthttp.RouterDependencies{
-		PR: ps,
+		PR: services.NewTracedPostService(ps, tp),
+		UR: services.NewTracedUserService(us, tp),
+		TR: services.NewTracedTagService(ts, tp),
+		TS: trs,
+		BS: services.NewTracedBookmarkService(bs, tp),
+		MS: services.NewTracedMediaService(ms, tp),
+		PS: services.NewTracedProfileService(prs, tp),
+		NS: ns,
+		MG: services.NewTracedMessageService(mgs, tp),
+		SS: sts,
+		TP: tp,
+		IS: is,
+
+		Auth: signer,
+
+		MaxBodyBytes:    c.HTTP.MaxBodyBytes,
+		MaxUploadBytes:  c.Media.MaxUploadBytes,
+		StreamHeartbeat: time.Duration(c.Stream.HeartbeatSeconds) * time.Second,
+		StreamTokenTTL:  time.Duration(c.Auth.StreamTokenTTLSeconds) * time.Second,
 	}), c)
 }
+
+func purgeIdempotencyKeys(is services.IdempotencyService, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for range ticker.C {
+		n, err := is.PurgeExpired(context.Background())
+		if err != nil {
+			slog.Error("purge idempotency keys", "err", err)
+			continue
+		}
+		slog.Debug("purged idempotency keys", "count", n)
+	}
+}
+
+func refreshTrendingTags(trs services.TrendingService, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		if err := trs.RefreshTrendingTags(context.Background()); err != nil {
+			slog.Error("refresh trending tags", "err", err)
+		}
+		<-ticker.C
+	}
+}
+
+func publishScheduledPosts(ss services.SchedulerService, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		n, err := ss.PublishDuePosts(context.Background())
+		if err != nil {
+			slog.Error("publish scheduled posts", "err", err)
+		} else if n > 0 {
+			slog.Info("published scheduled posts", "count", n)
+		}
+		<-ticker.C
+	}
+}
+
+func processNotificationEvents(ns services.NotificationService, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		n, err := ns.ProcessEvents(context.Background())
+		if err != nil {
+			slog.Error("process notification events", "err", err)
+		} else if n > 0 {
+			slog.Info("processed notification events", "count", n)
+		}
+		<-ticker.C
+	}
+}
+
+// streamDispatchBatch caps the announced events loaded and pushed at once.
+const streamDispatchBatch = 100
+
+func dispatchStreamEvents(l *pq.Listener, sts services.StreamService) {
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ping.C:
+			if err := l.Ping(); err != nil {
+				slog.Error("ping stream listener", "err", err)
+			}
+		case n, ok := <-l.Notify:
+			if !ok {
+				return
+			}
+			if n == nil {
+				// The listener reconnected and what was announced meanwhile
+				// is lost, so clients reconnect and replay it instead.
+				sts.DropAll()
+				continue
+			}
+			ids := appendStreamEventID(nil, n)
+			for len(ids) < streamDispatchBatch && len(l.Notify) > 0 {
+				n = <-l.Notify
+				if n == nil {
+					sts.DropAll()
+					break
+				}
+				ids = appendStreamEventID(ids, n)
+			}
+			if err := sts.Dispatch(context.Background(), ids); err != nil {
+				slog.Error("dispatch stream events", "err", err)
+			}
+		}
+	}
+}
+
+func appendStreamEventID(ids []int64, n *pq.Notification) []int64 {
+	id, err := strconv.ParseInt(n.Extra, 10, 64)
+	if err != nil {
+		slog.Error("parse stream event id", "payload", n.Extra, "err", err)
+		return ids
+	}
+	return append(ids, id)
+}
+
+func purgeStreamEvents(sts services.StreamService, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for range ticker.C {
+		n, err := sts.PurgeExpired(context.Background())
+		if err != nil {
+			slog.Error("purge stream events", "err", err)
+			continue
+		}
+		slog.Debug("purged stream events", "count", n)
+	}
+}
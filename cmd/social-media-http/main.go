@@ -1,17 +1,56 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
 
 	"github.com/gadhittana01/socialmedia/config"
 	"github.com/gadhittana01/socialmedia/helper"
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run returns the process exit code, so the deferred log close still runs
+// before main exits.
+func run() int {
 	config := &config.GlobalConfig{}
-	helper.LoadConfig(config)
-	err := initApp(config)
+	args, err := helper.LoadConfig(config, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	_, closeLog, err := helper.InitLogger(config.Log)
+	if err != nil {
+		slog.Error("init logger", "err", err)
+		return 1
+	}
+	defer closeLog()
+	slog.Info("effective config", "config", config.String())
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(config, args[1:]); err != nil {
+			slog.Error("migrate", "err", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(args) > 0 && args[0] == "token" {
+		if err := runToken(config, args[1:]); err != nil {
+			slog.Error("token", "err", err)
+			return 1
+		}
+		return 0
+	}
+
+	err = initApp(config)
 	if err != nil {
-		log.Println(err)
+		slog.Error("app stopped", "err", err)
+		return 1
 	}
+	return 0
 }
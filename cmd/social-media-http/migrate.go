@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gadhittana01/socialmedia/config"
+	"github.com/gadhittana01/socialmedia/db"
+	"github.com/gadhittana01/socialmedia/migration"
+)
+
+const migrateUsage = `usage: social-media-http [flags] migrate <command>
+  up            runs all pending migrations
+  up [target]   runs pending migrations up to the target version
+  down          reverts the last migration
+  reset         reverts all migrations
+  version       prints the current schema version`
+
+func runMigrate(c *config.GlobalConfig, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	sqlDB := db.InitDB(c.DB)
+	defer sqlDB.Close()
+
+	m, err := migration.NewMigrator(sqlDB)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var from, to int64
+	switch args[0] {
+	case "up":
+		var target int64
+		if len(args) > 1 {
+			target, err = strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid target version %q", args[1])
+			}
+		}
+		from, to, err = m.Up(ctx, target)
+	case "down":
+		from, to, err = m.Down(ctx)
+	case "reset":
+		from, to, err = m.Reset(ctx)
+	case "version":
+		to, err = m.Version(ctx)
+		from = to
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	if from != to {
+		slog.Info("migrated", "from", from, "to", to)
+	} else {
+		slog.Info("schema version", "version", to)
+	}
+	return nil
+}
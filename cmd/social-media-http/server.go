@@ -2,15 +2,14 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
 
 	"github.com/gadhittana01/socialmedia/config"
 )
 
 func startHTTPServer(handler http.Handler, c *config.GlobalConfig) error {
-	log.Println("Serving HTTP on ports :" + strconv.Itoa(c.HTTP.Port))
+	slog.Info("serving HTTP", "port", c.HTTP.Port)
 	port := fmt.Sprintf(":%d", c.HTTP.Port)
 	return http.ListenAndServe(port, handler)
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/config"
+)
+
+const tokenUsage = `usage: social-media-http [flags] token <user_id>
+  prints a bearer token for the user, valid for auth.token_ttl_hours`
+
+// runToken mints an API token with the configured secret. There is no login
+// endpoint, so whatever signs users in issues tokens the same way.
+func runToken(c *config.GlobalConfig, args []string) error {
+	if len(args) != 1 {
+		return errors.New(tokenUsage)
+	}
+	userID, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil || userID <= 0 {
+		return fmt.Errorf("invalid user id %q", args[0])
+	}
+
+	signer, err := auth.NewSigner([]byte(c.Auth.Secret), nil)
+	if err != nil {
+		return fmt.Errorf("auth.secret: %w", err)
+	}
+	fmt.Println(signer.Sign(int32(userID), auth.ScopeAPI, time.Duration(c.Auth.TokenTTLHours)*time.Hour))
+	return nil
+}
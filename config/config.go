@@ -1,18 +1,308 @@
 package config
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
 type GlobalConfig struct {
-	HTTP HTTPConfig `yaml:"http"`
-	DB   DBConfig   `yaml:"db"`
+	HTTP    HTTPConfig    `yaml:"http"`
+	DB      DBConfig      `yaml:"db"`
+	Log     LogConfig     `yaml:"log"`
+	Tracing TracingConfig `yaml:"tracing"`
+	Jobs    JobsConfig    `yaml:"jobs"`
+	Media   MediaConfig   `yaml:"media"`
+	Users   UsersConfig   `yaml:"users"`
+	Stream  StreamConfig  `yaml:"stream"`
+	Auth    AuthConfig    `yaml:"auth"`
 }
 
 type HTTPConfig struct {
 	Port int `yaml:"port"`
+	// MaxBodyBytes caps the size of request bodies.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// IdempotencyTTLHours is how long a stored Idempotency-Key response
+	// can be replayed.
+	IdempotencyTTLHours int32 `yaml:"idempotency_ttl_hours"`
 }
 
 type DBConfig struct {
 	Host     string `yaml:"host"`
 	Port     int32  `yaml:"port"`
 	User     string `yaml:"user"`
-	Password string `yaml:"password"`
+	Password string `yaml:"password" secret:"true"`
 	Name     string `yaml:"name"`
+	// AutoMigrate applies pending migrations before the server starts.
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level"`
+	// Format is either json or text.
+	Format string `yaml:"format"`
+	// Output is stdout, stderr or a file path.
+	Output string `yaml:"output"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of otlp, stdout or none.
+	Exporter    string `yaml:"exporter"`
+	Endpoint    string `yaml:"endpoint"`
+	Insecure    bool   `yaml:"insecure"`
+	ServiceName string `yaml:"service_name"`
+}
+
+type JobsConfig struct {
+	// TrendingRefreshSeconds is how often tag trend scores are recomputed.
+	TrendingRefreshSeconds int32 `yaml:"trending_refresh_seconds"`
+	// PublishIntervalSeconds is how often scheduled posts that are due get
+	// published.
+	PublishIntervalSeconds int32 `yaml:"publish_interval_seconds"`
+	// NotificationIntervalSeconds is how often recorded events are turned
+	// into notifications.
+	NotificationIntervalSeconds int32 `yaml:"notification_interval_seconds"`
+}
+
+type MediaConfig struct {
+	// Storage is where uploaded files are kept. Only local is supported.
+	Storage string `yaml:"storage"`
+	// Dir is the directory the local storage writes to.
+	Dir string `yaml:"dir"`
+	// MaxUploadBytes caps the size of an uploaded file. Upload requests may
+	// exceed http.max_body_bytes by up to this much.
+	MaxUploadBytes int64 `yaml:"max_upload_bytes"`
+	// ThumbnailSize is the largest width or height of an image thumbnail.
+	ThumbnailSize int `yaml:"thumbnail_size"`
+}
+
+type UsersConfig struct {
+	// HandleChangeCooldownHours is how long a user waits between handle
+	// changes.
+	HandleChangeCooldownHours int32 `yaml:"handle_change_cooldown_hours"`
+	// HandleRedirectHours is how long a given up handle keeps leading to its
+	// user's profile, during which nobody else can take it.
+	HandleRedirectHours int32 `yaml:"handle_redirect_hours"`
+}
+
+type StreamConfig struct {
+	// HeartbeatSeconds is how often an idle stream gets a comment line, so
+	// proxies keep it open and clients notice when it is gone.
+	HeartbeatSeconds int32 `yaml:"heartbeat_seconds"`
+	// BufferSize is how many events may wait for a slow client before its
+	// stream is dropped.
+	BufferSize int `yaml:"buffer_size"`
+	// RetentionMinutes is how long events stay available to clients
+	// resuming with Last-Event-ID.
+	RetentionMinutes int32 `yaml:"retention_minutes"`
+}
+
+type AuthConfig struct {
+	// Secret signs and verifies bearer tokens. It must be at least 32 bytes.
+	// Left empty, every endpoint that needs a caller answers 401.
+	Secret string `yaml:"secret" secret:"true"`
+	// TokenTTLHours is how long a token minted with the token command is
+	// valid.
+	TokenTTLHours int32 `yaml:"token_ttl_hours"`
+	// StreamTokenTTLSeconds is how long a token from POST /stream/token can
+	// open a stream. It travels in the query string, so keep it short.
+	StreamTokenTTLSeconds int32 `yaml:"stream_token_ttl_seconds"`
+}
+
+// Default returns the configuration used before any file, environment
+// variable or flag is applied.
+func Default() GlobalConfig {
+	return GlobalConfig{
+		HTTP: HTTPConfig{
+			Port:                8000,
+			MaxBodyBytes:        1 << 20,
+			IdempotencyTTLHours: 24,
+		},
+		DB: DBConfig{
+			Host: "localhost",
+			Port: 5432,
+			User: "postgres",
+			Name: "socialMediaDB",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+			Output: "stdout",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "social-media-http",
+		},
+		Jobs: JobsConfig{
+			TrendingRefreshSeconds:      300,
+			PublishIntervalSeconds:      30,
+			NotificationIntervalSeconds: 5,
+		},
+		Media: MediaConfig{
+			Storage:        "local",
+			Dir:            "./data/media",
+			MaxUploadBytes: 10 << 20,
+			ThumbnailSize:  320,
+		},
+		Users: UsersConfig{
+			HandleChangeCooldownHours: 168,
+			HandleRedirectHours:       720,
+		},
+		Stream: StreamConfig{
+			HeartbeatSeconds: 15,
+			BufferSize:       64,
+			RetentionMinutes: 60,
+		},
+		Auth: AuthConfig{
+			TokenTTLHours:         24,
+			StreamTokenTTLSeconds: 300,
+		},
+	}
+}
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError []string
+
+func (v ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(v, "\n  - ")
+}
+
+func (c GlobalConfig) Validate() error {
+	var problems ValidationError
+
+	if c.HTTP.Port <= 0 || c.HTTP.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("http.port must be between 1 and 65535, got %d", c.HTTP.Port))
+	}
+	if c.HTTP.MaxBodyBytes <= 0 {
+		problems = append(problems, "http.max_body_bytes must be positive")
+	}
+	if c.HTTP.IdempotencyTTLHours <= 0 {
+		problems = append(problems, "http.idempotency_ttl_hours must be positive")
+	}
+
+	if c.DB.Host == "" {
+		problems = append(problems, "db.host is required")
+	}
+	if c.DB.Port <= 0 || c.DB.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("db.port must be between 1 and 65535, got %d", c.DB.Port))
+	}
+	if c.DB.User == "" {
+		problems = append(problems, "db.user is required")
+	}
+	if c.DB.Name == "" {
+		problems = append(problems, "db.name is required")
+	}
+
+	if !oneOf(c.Log.Level, "debug", "info", "warn", "error") {
+		problems = append(problems, fmt.Sprintf("log.level must be one of debug, info, warn, error, got %q", c.Log.Level))
+	}
+	if !oneOf(c.Log.Format, "json", "text") {
+		problems = append(problems, fmt.Sprintf("log.format must be one of json, text, got %q", c.Log.Format))
+	}
+	if c.Log.Output == "" {
+		problems = append(problems, "log.output is required")
+	}
+
+	if !oneOf(c.Tracing.Exporter, "otlp", "stdout", "none") {
+		problems = append(problems, fmt.Sprintf("tracing.exporter must be one of otlp, stdout, none, got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.Exporter == "otlp" && c.Tracing.Endpoint == "" {
+		problems = append(problems, "tracing.endpoint is required when tracing.exporter is otlp")
+	}
+
+	if c.Jobs.TrendingRefreshSeconds <= 0 {
+		problems = append(problems, "jobs.trending_refresh_seconds must be positive")
+	}
+	if c.Jobs.PublishIntervalSeconds <= 0 {
+		problems = append(problems, "jobs.publish_interval_seconds must be positive")
+	}
+	if c.Jobs.NotificationIntervalSeconds <= 0 {
+		problems = append(problems, "jobs.notification_interval_seconds must be positive")
+	}
+
+	if !oneOf(c.Media.Storage, "local") {
+		problems = append(problems, fmt.Sprintf("media.storage must be local, got %q", c.Media.Storage))
+	}
+	if c.Media.Storage == "local" && c.Media.Dir == "" {
+		problems = append(problems, "media.dir is required when media.storage is local")
+	}
+	if c.Media.MaxUploadBytes <= 0 {
+		problems = append(problems, "media.max_upload_bytes must be positive")
+	}
+	if c.Media.ThumbnailSize <= 0 {
+		problems = append(problems, "media.thumbnail_size must be positive")
+	}
+
+	if c.Users.HandleChangeCooldownHours < 0 {
+		problems = append(problems, "users.handle_change_cooldown_hours must not be negative")
+	}
+	if c.Users.HandleRedirectHours < 0 {
+		problems = append(problems, "users.handle_redirect_hours must not be negative")
+	}
+
+	if c.Stream.HeartbeatSeconds <= 0 {
+		problems = append(problems, "stream.heartbeat_seconds must be positive")
+	}
+	if c.Stream.BufferSize <= 0 {
+		problems = append(problems, "stream.buffer_size must be positive")
+	}
+	if c.Stream.RetentionMinutes <= 0 {
+		problems = append(problems, "stream.retention_minutes must be positive")
+	}
+
+	if c.Auth.Secret != "" && len(c.Auth.Secret) < 32 {
+		problems = append(problems, "auth.secret must be at least 32 bytes")
+	}
+	if c.Auth.TokenTTLHours <= 0 {
+		problems = append(problems, "auth.token_ttl_hours must be positive")
+	}
+	if c.Auth.StreamTokenTTLSeconds <= 0 {
+		problems = append(problems, "auth.stream_token_ttl_seconds must be positive")
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// Redacted returns a copy of c with every field tagged secret:"true"
+// masked, safe to print or log.
+func (c GlobalConfig) Redacted() GlobalConfig {
+	redact(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+// String renders the redacted configuration as YAML.
+func (c GlobalConfig) String() string {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
+
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() == reflect.Struct {
+			redact(f)
+			continue
+		}
+		if v.Type().Field(i).Tag.Get("secret") == "true" && f.Kind() == reflect.String && f.String() != "" {
+			f.SetString("****")
+		}
+	}
+}
+
+func oneOf(s string, values ...string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func() GlobalConfig
+		wantErr []string
+	}{
+		{
+			name:   "default config is valid",
+			config: Default,
+		},
+		{
+			name: "report every problem",
+			config: func() GlobalConfig {
+				c := Default()
+				c.HTTP.Port = 0
+				c.DB.Host = ""
+				c.Log.Format = "xml"
+				c.Tracing.Exporter = "otlp"
+				c.Jobs.TrendingRefreshSeconds = 0
+				c.Jobs.PublishIntervalSeconds = -1
+				c.Jobs.NotificationIntervalSeconds = 0
+				c.Media.Storage = "s3"
+				c.Media.ThumbnailSize = 0
+				c.Users.HandleRedirectHours = -1
+				c.Stream.BufferSize = 0
+				c.Auth.Secret = "short"
+				c.Auth.StreamTokenTTLSeconds = 0
+				return c
+			},
+			wantErr: []string{
+				"http.port must be between 1 and 65535, got 0",
+				"db.host is required",
+				`log.format must be one of json, text, got "xml"`,
+				"tracing.endpoint is required when tracing.exporter is otlp",
+				"jobs.trending_refresh_seconds must be positive",
+				"jobs.publish_interval_seconds must be positive",
+				"jobs.notification_interval_seconds must be positive",
+				`media.storage must be local, got "s3"`,
+				"media.thumbnail_size must be positive",
+				"users.handle_redirect_hours must not be negative",
+				"stream.buffer_size must be positive",
+				"auth.secret must be at least 32 bytes",
+				"auth.stream_token_ttl_seconds must be positive",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config().Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			got, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ValidationError", err)
+			}
+			if !reflect.DeepEqual([]string(got), tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Redacted(t *testing.T) {
+	c := Default()
+	c.DB.Password = "hunter2"
+	c.Auth.Secret = "correct horse battery staple 1234"
+
+	if got := c.Redacted().DB.Password; got != "****" {
+		t.Errorf("Redacted() password = %v, want ****", got)
+	}
+	if got := c.Redacted().Auth.Secret; got != "****" {
+		t.Errorf("Redacted() auth secret = %v, want ****", got)
+	}
+	if c.DB.Password != "hunter2" {
+		t.Errorf("Redacted() modified the original config")
+	}
+	if strings.Contains(c.String(), "hunter2") {
+		t.Errorf("String() leaks the password: %s", c.String())
+	}
+}
@@ -3,37 +3,55 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/gadhittana01/socialmedia/config"
-	"github.com/gadhittana01/socialmedia/helper"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-func InitDB() *sql.DB {
+func InitDB(dbConn config.DBConfig) *sql.DB {
 	var db *sql.DB
 	var err error
 
-	config := &config.GlobalConfig{}
-	helper.LoadConfig(config)
-
-	dbConn := config.DB
-	connString := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		dbConn.Host, dbConn.Port, dbConn.User, dbConn.Password, dbConn.Name,
-	)
-
-	db, err = sql.Open("postgres", connString)
+	db, err = sql.Open("postgres", connString(dbConn))
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("open db", "err", err)
+		os.Exit(1)
 	}
 
 	err = db.Ping()
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("ping db", "host", dbConn.Host, "name", dbConn.Name, "err", err)
+		os.Exit(1)
 	}
 
-	log.Println("DB " + dbConn.Name + " connected Successfully!")
+	slog.Info("db connected", "host", dbConn.Host, "name", dbConn.Name)
 
 	return db
 }
+
+// NewListener opens a connection listening on channel. It reconnects by
+// itself, sending nil on Notify once it is back, since notifications sent
+// in the meantime are lost.
+func NewListener(dbConn config.DBConfig, channel string) (*pq.Listener, error) {
+	l := pq.NewListener(connString(dbConn), time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("db listener", "channel", channel, "event", ev, "err", err)
+		}
+	})
+	if err := l.Listen(channel); err != nil {
+		l.Close()
+		return nil, err
+	}
+	slog.Info("db listening", "channel", channel)
+	return l, nil
+}
+
+func connString(dbConn config.DBConfig) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		dbConn.Host, dbConn.Port, dbConn.User, dbConn.Password, dbConn.Name,
+	)
+}
@@ -2,14 +2,17 @@ package resthttp
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
+
+	"github.com/gadhittana01/socialmedia/validation"
 )
 
 type baseResp struct {
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Status  string                  `json:"status"`
+	Message string                  `json:"message"`
+	Data    interface{}             `json:"data,omitempty"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
 }
 
 func NewResponse() baseResp {
@@ -24,7 +27,7 @@ func (br *baseResp) SetBadRequest(msg string, w http.ResponseWriter) {
 	br.Message = msg
 	respBytes, err := json.Marshal(br)
 	if err != nil {
-		log.Println(br.Data, "setInternalServerError error : %+v", err)
+		slog.Error("setBadRequest: marshal response", "err", err)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusBadRequest)
@@ -39,7 +42,7 @@ func (br *baseResp) SetInternalServerError(msg string, w http.ResponseWriter) {
 	br.Message = msg
 	respBytes, err := json.Marshal(br)
 	if err != nil {
-		log.Println(br.Data, "setInternalServerError error : %+v", err)
+		slog.Error("setInternalServerError: marshal response", "err", err)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusInternalServerError)
@@ -53,7 +56,7 @@ func (br *baseResp) SetOK(data interface{}, w http.ResponseWriter) {
 
 	respBytes, err := json.Marshal(br)
 	if err != nil {
-		log.Println(br.Data, "setOK error : %+v", err)
+		slog.Error("setOK: marshal response", "err", err)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -67,13 +70,44 @@ func (br *baseResp) SetCreated(data interface{}, w http.ResponseWriter) {
 
 	respBytes, err := json.Marshal(br)
 	if err != nil {
-		log.Println(br.Data, "setOK error : %+v", err)
+		slog.Error("setCreated: marshal response", "err", err)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	w.Write(respBytes)
 }
 
+func (br *baseResp) SetUnauthorized(msg string, w http.ResponseWriter) {
+	if msg == "" {
+		msg = "Unauthorized"
+	}
+	br.Status = "Unauthorized"
+	br.Message = msg
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setUnauthorized: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write(respBytes)
+}
+
+func (br *baseResp) SetForbidden(msg string, w http.ResponseWriter) {
+	if msg == "" {
+		msg = "Forbidden"
+	}
+	br.Status = "Forbidden"
+	br.Message = msg
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setForbidden: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	w.Write(respBytes)
+}
+
 func (br *baseResp) SetNotFound(msg string, w http.ResponseWriter) {
 	if msg == "" {
 		msg = "Not Found"
@@ -82,9 +116,86 @@ func (br *baseResp) SetNotFound(msg string, w http.ResponseWriter) {
 	br.Message = msg
 	respBytes, err := json.Marshal(br)
 	if err != nil {
-		log.Println(br.Data, "setInternalServerError error : %+v", err)
+		slog.Error("setNotFound: marshal response", "err", err)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNotFound)
 	w.Write(respBytes)
 }
+
+func (br *baseResp) SetUnprocessableEntity(errs validation.Errors, w http.ResponseWriter) {
+	br.Status = "Unprocessable Entity"
+	br.Message = "Validation failed"
+	br.Errors = errs
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setUnprocessableEntity: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	w.Write(respBytes)
+}
+
+func (br *baseResp) SetRequestEntityTooLarge(msg string, w http.ResponseWriter) {
+	if msg == "" {
+		msg = "Request Entity Too Large"
+	}
+	br.Status = "Request Entity Too Large"
+	br.Message = msg
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setRequestEntityTooLarge: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	w.Write(respBytes)
+}
+
+func (br *baseResp) SetPreconditionFailed(msg string, w http.ResponseWriter) {
+	if msg == "" {
+		msg = "Precondition Failed"
+	}
+	br.Status = "Precondition Failed"
+	br.Message = msg
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setPreconditionFailed: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPreconditionFailed)
+	w.Write(respBytes)
+}
+
+func (br *baseResp) SetConflict(msg string, w http.ResponseWriter) {
+	if msg == "" {
+		msg = "Conflict"
+	}
+	br.Status = "Conflict"
+	br.Message = msg
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setConflict: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	w.Write(respBytes)
+}
+
+func (br *baseResp) SetTooManyRequests(msg string, w http.ResponseWriter) {
+	if msg == "" {
+		msg = "Too Many Requests"
+	}
+	br.Status = "Too Many Requests"
+	br.Message = msg
+	respBytes, err := json.Marshal(br)
+	if err != nil {
+		slog.Error("setTooManyRequests: marshal response", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write(respBytes)
+}
+
+func (br *baseResp) SetNotModified(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotModified)
+}
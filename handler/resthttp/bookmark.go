@@ -0,0 +1,229 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gadhittana01/socialmedia/services"
+)
+
+type BookmarkHandler struct {
+	bookmarkService BookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+func (p BookmarkHandler) GetCollections(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := p.bookmarkService.GetCollections(r.Context(), userID)
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p BookmarkHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type CreateCollectionReq struct {
+		Name string `json:"name" validate:"required,max=100"`
+	}
+
+	reqBody := CreateCollectionReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	res, err := p.bookmarkService.CreateCollection(r.Context(), services.CreateCollectionParams{
+		UserID: userID,
+		Name:   reqBody.Name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if errors.Is(err, services.ErrCollectionExists) {
+		resp.SetConflict("collection already exists", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetCreated(res, w)
+	return
+}
+
+func (p BookmarkHandler) RenameCollection(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type RenameCollectionQuery struct {
+		ID int32 `json:"id" validate:"required,gte=1"`
+	}
+	type RenameCollectionReq struct {
+		Name string `json:"name" validate:"required,max=100"`
+	}
+
+	query := RenameCollectionQuery{}
+	if !decodeQuery(w, r, &query) {
+		return
+	}
+	reqBody := RenameCollectionReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	res, err := p.bookmarkService.RenameCollection(r.Context(), services.RenameCollectionParams{
+		ID:     query.ID,
+		UserID: userID,
+		Name:   reqBody.Name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("collection not found", w)
+		return
+	}
+	if errors.Is(err, services.ErrCollectionExists) {
+		resp.SetConflict("collection already exists", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p BookmarkHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type DeleteCollectionReq struct {
+		ID int32 `json:"id" validate:"required,gte=1"`
+	}
+
+	req := DeleteCollectionReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.bookmarkService.DeleteCollection(r.Context(), services.DeleteCollectionParams{
+		ID:     req.ID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("collection not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p BookmarkHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type AddBookmarkReq struct {
+		PostID       int32 `json:"post_id" validate:"required,gte=1"`
+		CollectionID int32 `json:"collection_id" validate:"gte=0"`
+	}
+
+	reqBody := AddBookmarkReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	err := p.bookmarkService.AddBookmark(r.Context(), services.AddBookmarkParams{
+		UserID:       userID,
+		PostID:       reqBody.PostID,
+		CollectionID: reqBody.CollectionID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post or collection not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p BookmarkHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type RemoveBookmarkReq struct {
+		PostID       int32 `json:"post_id" validate:"required,gte=1"`
+		CollectionID int32 `json:"collection_id" validate:"gte=0"`
+	}
+
+	req := RemoveBookmarkReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.bookmarkService.RemoveBookmark(r.Context(), services.RemoveBookmarkParams{
+		UserID:       userID,
+		PostID:       req.PostID,
+		CollectionID: req.CollectionID,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import "github.com/google/wire"
+
+func InitializedBookmarkHandler(bs BookmarkService) (*BookmarkHandler, error) {
+	wire.Build(NewBookmarkHandler)
+	return nil, nil
+}
@@ -0,0 +1,319 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_NewBookmarkHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookmarkMock := NewMockBookmarkService(ctrl)
+
+	want := &BookmarkHandler{
+		bookmarkService: bookmarkMock,
+	}
+	if got := NewBookmarkHandler(bookmarkMock); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBookmarkHandler() = %v, want %v", got, want)
+	}
+}
+
+func Test_BookmarkHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		method     string
+		url        string
+		body       string
+		handler    func(h BookmarkHandler) http.HandlerFunc
+		initMock   func(m *MockBookmarkService)
+		wantStatus int
+	}{
+		{
+			name:    "get collections",
+			userID:  1,
+			method:  "GET",
+			url:     "http://localhost:8000/collections",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.GetCollections },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().GetCollections(gomock.Any(), int32(1)).Return([]services.GetCollectionsRow{{ID: 3}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "get collections unauthenticated",
+			method:     "GET",
+			url:        "http://localhost:8000/collections",
+			handler:    func(h BookmarkHandler) http.HandlerFunc { return h.GetCollections },
+			initMock:   func(m *MockBookmarkService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "get collections internal server error",
+			userID:  1,
+			method:  "GET",
+			url:     "http://localhost:8000/collections",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.GetCollections },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().GetCollections(gomock.Any(), gomock.Any()).Return([]services.GetCollectionsRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "create collection",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/collection",
+			body:    `{"name": "recipes"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.CreateCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().CreateCollection(gomock.Any(), services.CreateCollectionParams{
+					UserID: 1,
+					Name:   "recipes",
+				}).Return(services.CreateCollectionRow{ID: 3, UserID: 1, Name: "recipes"}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "create collection without name",
+			userID:     1,
+			method:     "POST",
+			url:        "http://localhost:8000/collection",
+			body:       `{}`,
+			handler:    func(h BookmarkHandler) http.HandlerFunc { return h.CreateCollection },
+			initMock:   func(m *MockBookmarkService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "create collection for a missing user",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/collection",
+			body:    `{"name": "recipes"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.CreateCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).Return(services.CreateCollectionRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "create collection with a taken name",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/collection",
+			body:    `{"name": "recipes"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.CreateCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).Return(services.CreateCollectionRow{}, services.ErrCollectionExists)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "create collection internal server error",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/collection",
+			body:    `{"name": "recipes"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.CreateCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).Return(services.CreateCollectionRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "rename collection",
+			userID:  1,
+			method:  "PUT",
+			url:     "http://localhost:8000/collection?id=3",
+			body:    `{"name": "desserts"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.RenameCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().RenameCollection(gomock.Any(), services.RenameCollectionParams{
+					ID:     3,
+					UserID: 1,
+					Name:   "desserts",
+				}).Return(services.RenameCollectionRow{ID: 3, UserID: 1, Name: "desserts"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "rename collection without id",
+			userID:     1,
+			method:     "PUT",
+			url:        "http://localhost:8000/collection",
+			body:       `{"name": "desserts"}`,
+			handler:    func(h BookmarkHandler) http.HandlerFunc { return h.RenameCollection },
+			initMock:   func(m *MockBookmarkService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "rename missing collection",
+			userID:  1,
+			method:  "PUT",
+			url:     "http://localhost:8000/collection?id=3",
+			body:    `{"name": "desserts"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.RenameCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().RenameCollection(gomock.Any(), gomock.Any()).Return(services.RenameCollectionRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "rename collection to a taken name",
+			userID:  1,
+			method:  "PUT",
+			url:     "http://localhost:8000/collection?id=3",
+			body:    `{"name": "desserts"}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.RenameCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().RenameCollection(gomock.Any(), gomock.Any()).Return(services.RenameCollectionRow{}, services.ErrCollectionExists)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "delete collection",
+			userID:  1,
+			method:  "DELETE",
+			url:     "http://localhost:8000/collection?id=3",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.DeleteCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().DeleteCollection(gomock.Any(), services.DeleteCollectionParams{ID: 3, UserID: 1}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "delete missing collection",
+			userID:  1,
+			method:  "DELETE",
+			url:     "http://localhost:8000/collection?id=3",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.DeleteCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().DeleteCollection(gomock.Any(), services.DeleteCollectionParams{ID: 3, UserID: 1}).Return(sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "delete collection internal server error",
+			userID:  1,
+			method:  "DELETE",
+			url:     "http://localhost:8000/collection?id=3",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.DeleteCollection },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().DeleteCollection(gomock.Any(), services.DeleteCollectionParams{ID: 3, UserID: 1}).Return(errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "add bookmark",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/bookmark",
+			body:    `{"post_id": 2, "collection_id": 3}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.AddBookmark },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().AddBookmark(gomock.Any(), services.AddBookmarkParams{
+					UserID:       1,
+					PostID:       2,
+					CollectionID: 3,
+				}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "add bookmark without post id",
+			userID:     1,
+			method:     "POST",
+			url:        "http://localhost:8000/bookmark",
+			body:       `{}`,
+			handler:    func(h BookmarkHandler) http.HandlerFunc { return h.AddBookmark },
+			initMock:   func(m *MockBookmarkService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "add bookmark to a missing collection",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/bookmark",
+			body:    `{"post_id": 2, "collection_id": 3}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.AddBookmark },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().AddBookmark(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "add bookmark internal server error",
+			userID:  1,
+			method:  "POST",
+			url:     "http://localhost:8000/bookmark",
+			body:    `{"post_id": 2}`,
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.AddBookmark },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().AddBookmark(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "remove bookmark",
+			userID:  1,
+			method:  "DELETE",
+			url:     "http://localhost:8000/bookmark?post_id=2",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.RemoveBookmark },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().RemoveBookmark(gomock.Any(), services.RemoveBookmarkParams{
+					UserID: 1,
+					PostID: 2,
+				}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "remove bookmark unauthenticated",
+			method:     "DELETE",
+			url:        "http://localhost:8000/bookmark?post_id=2",
+			handler:    func(h BookmarkHandler) http.HandlerFunc { return h.RemoveBookmark },
+			initMock:   func(m *MockBookmarkService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "remove bookmark internal server error",
+			userID:  1,
+			method:  "DELETE",
+			url:     "http://localhost:8000/bookmark?post_id=2&collection_id=3",
+			handler: func(h BookmarkHandler) http.HandlerFunc { return h.RemoveBookmark },
+			initMock: func(m *MockBookmarkService) {
+				m.EXPECT().RemoveBookmark(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockBookmarkService(ctrl)
+			tt.initMock(m)
+			h := BookmarkHandler{
+				bookmarkService: m,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			tt.handler(h)(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %v, want %v", tt.method, tt.url, w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
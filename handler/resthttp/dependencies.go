@@ -10,21 +10,101 @@ type (
 	UserService interface {
 		CreateUser(ctx context.Context, fullname string) (services.CreateUserRow, error)
 		GetUsers(ctx context.Context) ([]services.GetUsersRow, error)
+		SearchUsers(ctx context.Context, arg services.SearchUsersParams) ([]services.SearchUsersRow, error)
 		UpdateUser(ctx context.Context, arg services.UpdateUserParams) (services.UpdateUserRow, error)
-		DeleteUser(ctx context.Context, id int32) error
+		DeleteUser(ctx context.Context, arg services.DeleteUserParams) error
+		GetUser(ctx context.Context, id int32) (services.GetUserRow, error)
+		PatchUser(ctx context.Context, arg services.PatchUserParams) (services.PatchUserRow, error)
+		FollowUser(ctx context.Context, arg services.FollowUserParams) error
+		UnfollowUser(ctx context.Context, arg services.UnfollowUserParams) error
+		BlockUser(ctx context.Context, arg services.BlockUserParams) error
+		UnblockUser(ctx context.Context, arg services.UnblockUserParams) error
+		GetBlockedUsers(ctx context.Context, userID int32) ([]services.GetUsersRow, error)
+		MuteUser(ctx context.Context, arg services.MuteUserParams) error
+		UnmuteUser(ctx context.Context, arg services.UnmuteUserParams) error
+		GetMutedUsers(ctx context.Context, userID int32) ([]services.GetUsersRow, error)
 	}
 
 	TagService interface {
 		GetTags(ctx context.Context) ([]services.GetTagsRow, error)
+		SearchTags(ctx context.Context, arg services.SearchTagsParams) ([]services.SearchTagsRow, error)
 		CreateTag(ctx context.Context, tagname string) (services.CreateTagRow, error)
 		UpdateTag(ctx context.Context, arg services.UpdateTagParams) (services.UpdateTagRow, error)
-		DeleteTag(ctx context.Context, id int32) error
+		DeleteTag(ctx context.Context, arg services.DeleteTagParams) error
+		GetTag(ctx context.Context, id int32) (services.GetTagRow, error)
+		PatchTag(ctx context.Context, arg services.PatchTagParams) (services.PatchTagRow, error)
+		FollowTag(ctx context.Context, arg services.FollowTagParams) error
+		UnfollowTag(ctx context.Context, arg services.UnfollowTagParams) error
+		GetFollowedTags(ctx context.Context, userID int32) ([]services.GetTagsRow, error)
 	}
 
 	PostService interface {
 		CreatePost(ctx context.Context, arg services.CreatePostParams) (services.CreatePostRow, error)
-		GetPosts(ctx context.Context) ([]services.GetPostsRow, error)
+		GetPosts(ctx context.Context, arg services.GetPostsParams) ([]services.GetPostsRow, error)
+		SearchPosts(ctx context.Context, arg services.SearchPostsParams) ([]services.SearchPostsRow, error)
+		GetTagFeed(ctx context.Context, arg services.GetTagFeedParams) ([]services.GetPostsRow, error)
+		GetBookmarks(ctx context.Context, arg services.GetBookmarksParams) ([]services.GetPostsRow, error)
 		UpdatePost(ctx context.Context, arg services.UpdatePostParams) (services.UpdatePostRow, error)
-		DeletePost(ctx context.Context, id int32) error
+		DeletePost(ctx context.Context, arg services.DeletePostParams) error
+		GetPost(ctx context.Context, arg services.GetPostParams) (services.GetPostRow, error)
+		PatchPost(ctx context.Context, arg services.PatchPostParams) (services.PatchPostRow, error)
+		Repost(ctx context.Context, arg services.RepostParams) (services.RepostRow, error)
+		Unrepost(ctx context.Context, arg services.UnrepostParams) error
+		React(ctx context.Context, arg services.ReactParams) error
+		Unreact(ctx context.Context, arg services.UnreactParams) error
+		GetThread(ctx context.Context, arg services.GetThreadParams) (services.GetThreadRow, error)
+		GetPostRevisions(ctx context.Context, arg services.GetPostRevisionsParams) ([]services.PostRevision, error)
+		GetPostRevisionDiff(ctx context.Context, arg services.GetPostRevisionDiffParams) (services.PostRevisionDiff, error)
+		RevertPost(ctx context.Context, arg services.RevertPostParams) (services.UpdatePostRow, error)
+	}
+
+	BookmarkService interface {
+		CreateCollection(ctx context.Context, arg services.CreateCollectionParams) (services.CreateCollectionRow, error)
+		RenameCollection(ctx context.Context, arg services.RenameCollectionParams) (services.RenameCollectionRow, error)
+		DeleteCollection(ctx context.Context, arg services.DeleteCollectionParams) error
+		GetCollections(ctx context.Context, userID int32) ([]services.GetCollectionsRow, error)
+		AddBookmark(ctx context.Context, arg services.AddBookmarkParams) error
+		RemoveBookmark(ctx context.Context, arg services.RemoveBookmarkParams) error
+	}
+
+	MediaService interface {
+		UploadMedia(ctx context.Context, arg services.UploadMediaParams) (services.MediaRow, error)
+		GetMediaContent(ctx context.Context, arg services.GetMediaContentParams) (services.MediaContent, error)
+	}
+
+	NotificationService interface {
+		GetNotifications(ctx context.Context, arg services.GetNotificationsParams) ([]services.NotificationRow, error)
+		MarkRead(ctx context.Context, arg services.MarkNotificationReadParams) error
+		MarkAllRead(ctx context.Context, userID int32) (int64, error)
+		GetPreferences(ctx context.Context, userID int32) (services.NotificationPreferences, error)
+		SetPreferences(ctx context.Context, arg services.SetNotificationPreferencesParams) (services.NotificationPreferences, error)
+	}
+
+	MessageService interface {
+		StartConversation(ctx context.Context, arg services.StartConversationParams) (services.ConversationRow, error)
+		GetConversations(ctx context.Context, arg services.GetConversationsParams) ([]services.ConversationRow, error)
+		GetConversation(ctx context.Context, arg services.GetConversationParams) (services.ConversationRow, error)
+		SendMessage(ctx context.Context, arg services.SendMessageParams) (services.Message, error)
+		GetMessage(ctx context.Context, arg services.GetMessageParams) (services.Message, error)
+		GetMessages(ctx context.Context, arg services.GetMessagesParams) ([]services.Message, error)
+		MarkRead(ctx context.Context, arg services.MarkConversationReadParams) (services.ReadReceipt, error)
+	}
+
+	StreamService interface {
+		Subscribe(ctx context.Context, arg services.SubscribeParams) (*services.Subscription, error)
+		Unsubscribe(sub *services.Subscription)
+	}
+
+	ProfileService interface {
+		GetProfile(ctx context.Context, arg services.GetProfileParams) (services.ProfileRow, error)
+		SetProfile(ctx context.Context, arg services.SetProfileParams) (services.SetProfileRow, error)
+	}
+
+	TrendingService interface {
+		GetTrendingTags(ctx context.Context, arg services.GetTrendingTagsParams) ([]services.GetTrendingTagsRow, error)
+	}
+
+	IdempotencyService interface {
+		Do(ctx context.Context, arg services.IdempotencyParams, fn func(ctx context.Context) services.IdempotentResponse) (services.IdempotentResponse, bool, error)
 	}
 )
@@ -35,6 +35,20 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// BlockUser mocks base method.
+func (m *MockUserService) BlockUser(ctx context.Context, arg services.BlockUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockUserServiceMockRecorder) BlockUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockUserService)(nil).BlockUser), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserService) CreateUser(ctx context.Context, fullname string) (services.CreateUserRow, error) {
 	m.ctrl.T.Helper()
@@ -51,17 +65,76 @@ func (mr *MockUserServiceMockRecorder) CreateUser(ctx, fullname interface{}) *go
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserService) DeleteUser(ctx context.Context, id int32) error {
+func (m *MockUserService) DeleteUser(ctx context.Context, arg services.DeleteUserParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, arg)
+}
+
+// FollowUser mocks base method.
+func (m *MockUserService) FollowUser(ctx context.Context, arg services.FollowUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowUser indicates an expected call of FollowUser.
+func (mr *MockUserServiceMockRecorder) FollowUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowUser", reflect.TypeOf((*MockUserService)(nil).FollowUser), ctx, arg)
+}
+
+// GetBlockedUsers mocks base method.
+func (m *MockUserService) GetBlockedUsers(ctx context.Context, userID int32) ([]services.GetUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsers", ctx, userID)
+	ret0, _ := ret[0].([]services.GetUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsers indicates an expected call of GetBlockedUsers.
+func (mr *MockUserServiceMockRecorder) GetBlockedUsers(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsers", reflect.TypeOf((*MockUserService)(nil).GetBlockedUsers), ctx, userID)
+}
+
+// GetMutedUsers mocks base method.
+func (m *MockUserService) GetMutedUsers(ctx context.Context, userID int32) ([]services.GetUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutedUsers", ctx, userID)
+	ret0, _ := ret[0].([]services.GetUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMutedUsers indicates an expected call of GetMutedUsers.
+func (mr *MockUserServiceMockRecorder) GetMutedUsers(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutedUsers", reflect.TypeOf((*MockUserService)(nil).GetMutedUsers), ctx, userID)
+}
+
+// GetUser mocks base method.
+func (m *MockUserService) GetUser(ctx context.Context, id int32) (services.GetUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, id)
+	ret0, _ := ret[0].(services.GetUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserServiceMockRecorder) GetUser(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserService)(nil).GetUser), ctx, id)
 }
 
 // GetUsers mocks base method.
@@ -79,6 +152,92 @@ func (mr *MockUserServiceMockRecorder) GetUsers(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserService)(nil).GetUsers), ctx)
 }
 
+// MuteUser mocks base method.
+func (m *MockUserService) MuteUser(ctx context.Context, arg services.MuteUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MuteUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MuteUser indicates an expected call of MuteUser.
+func (mr *MockUserServiceMockRecorder) MuteUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MuteUser", reflect.TypeOf((*MockUserService)(nil).MuteUser), ctx, arg)
+}
+
+// PatchUser mocks base method.
+func (m *MockUserService) PatchUser(ctx context.Context, arg services.PatchUserParams) (services.PatchUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", ctx, arg)
+	ret0, _ := ret[0].(services.PatchUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchUser indicates an expected call of PatchUser.
+func (mr *MockUserServiceMockRecorder) PatchUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockUserService)(nil).PatchUser), ctx, arg)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserService) SearchUsers(ctx context.Context, arg services.SearchUsersParams) ([]services.SearchUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, arg)
+	ret0, _ := ret[0].([]services.SearchUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserServiceMockRecorder) SearchUsers(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserService)(nil).SearchUsers), ctx, arg)
+}
+
+// UnblockUser mocks base method.
+func (m *MockUserService) UnblockUser(ctx context.Context, arg services.UnblockUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockUserServiceMockRecorder) UnblockUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockUserService)(nil).UnblockUser), ctx, arg)
+}
+
+// UnfollowUser mocks base method.
+func (m *MockUserService) UnfollowUser(ctx context.Context, arg services.UnfollowUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowUser indicates an expected call of UnfollowUser.
+func (mr *MockUserServiceMockRecorder) UnfollowUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowUser", reflect.TypeOf((*MockUserService)(nil).UnfollowUser), ctx, arg)
+}
+
+// UnmuteUser mocks base method.
+func (m *MockUserService) UnmuteUser(ctx context.Context, arg services.UnmuteUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmuteUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmuteUser indicates an expected call of UnmuteUser.
+func (mr *MockUserServiceMockRecorder) UnmuteUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmuteUser", reflect.TypeOf((*MockUserService)(nil).UnmuteUser), ctx, arg)
+}
+
 // UpdateUser mocks base method.
 func (m *MockUserService) UpdateUser(ctx context.Context, arg services.UpdateUserParams) (services.UpdateUserRow, error) {
 	m.ctrl.T.Helper()
@@ -133,17 +292,61 @@ func (mr *MockTagServiceMockRecorder) CreateTag(ctx, tagname interface{}) *gomoc
 }
 
 // DeleteTag mocks base method.
-func (m *MockTagService) DeleteTag(ctx context.Context, id int32) error {
+func (m *MockTagService) DeleteTag(ctx context.Context, arg services.DeleteTagParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTag indicates an expected call of DeleteTag.
-func (mr *MockTagServiceMockRecorder) DeleteTag(ctx, id interface{}) *gomock.Call {
+func (mr *MockTagServiceMockRecorder) DeleteTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagService)(nil).DeleteTag), ctx, arg)
+}
+
+// FollowTag mocks base method.
+func (m *MockTagService) FollowTag(ctx context.Context, arg services.FollowTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowTag indicates an expected call of FollowTag.
+func (mr *MockTagServiceMockRecorder) FollowTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowTag", reflect.TypeOf((*MockTagService)(nil).FollowTag), ctx, arg)
+}
+
+// GetFollowedTags mocks base method.
+func (m *MockTagService) GetFollowedTags(ctx context.Context, userID int32) ([]services.GetTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowedTags", ctx, userID)
+	ret0, _ := ret[0].([]services.GetTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowedTags indicates an expected call of GetFollowedTags.
+func (mr *MockTagServiceMockRecorder) GetFollowedTags(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowedTags", reflect.TypeOf((*MockTagService)(nil).GetFollowedTags), ctx, userID)
+}
+
+// GetTag mocks base method.
+func (m *MockTagService) GetTag(ctx context.Context, id int32) (services.GetTagRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, id)
+	ret0, _ := ret[0].(services.GetTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockTagServiceMockRecorder) GetTag(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagService)(nil).DeleteTag), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockTagService)(nil).GetTag), ctx, id)
 }
 
 // GetTags mocks base method.
@@ -161,6 +364,50 @@ func (mr *MockTagServiceMockRecorder) GetTags(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTagService)(nil).GetTags), ctx)
 }
 
+// PatchTag mocks base method.
+func (m *MockTagService) PatchTag(ctx context.Context, arg services.PatchTagParams) (services.PatchTagRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTag", ctx, arg)
+	ret0, _ := ret[0].(services.PatchTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTag indicates an expected call of PatchTag.
+func (mr *MockTagServiceMockRecorder) PatchTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTag", reflect.TypeOf((*MockTagService)(nil).PatchTag), ctx, arg)
+}
+
+// SearchTags mocks base method.
+func (m *MockTagService) SearchTags(ctx context.Context, arg services.SearchTagsParams) ([]services.SearchTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTags", ctx, arg)
+	ret0, _ := ret[0].([]services.SearchTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTags indicates an expected call of SearchTags.
+func (mr *MockTagServiceMockRecorder) SearchTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTags", reflect.TypeOf((*MockTagService)(nil).SearchTags), ctx, arg)
+}
+
+// UnfollowTag mocks base method.
+func (m *MockTagService) UnfollowTag(ctx context.Context, arg services.UnfollowTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowTag indicates an expected call of UnfollowTag.
+func (mr *MockTagServiceMockRecorder) UnfollowTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowTag", reflect.TypeOf((*MockTagService)(nil).UnfollowTag), ctx, arg)
+}
+
 // UpdateTag mocks base method.
 func (m *MockTagService) UpdateTag(ctx context.Context, arg services.UpdateTagParams) (services.UpdateTagRow, error) {
 	m.ctrl.T.Helper()
@@ -215,32 +462,224 @@ func (mr *MockPostServiceMockRecorder) CreatePost(ctx, arg interface{}) *gomock.
 }
 
 // DeletePost mocks base method.
-func (m *MockPostService) DeletePost(ctx context.Context, id int32) error {
+func (m *MockPostService) DeletePost(ctx context.Context, arg services.DeletePostParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", ctx, id)
+	ret := m.ctrl.Call(m, "DeletePost", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePost indicates an expected call of DeletePost.
-func (mr *MockPostServiceMockRecorder) DeletePost(ctx, id interface{}) *gomock.Call {
+func (mr *MockPostServiceMockRecorder) DeletePost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPostService)(nil).DeletePost), ctx, arg)
+}
+
+// GetBookmarks mocks base method.
+func (m *MockPostService) GetBookmarks(ctx context.Context, arg services.GetBookmarksParams) ([]services.GetPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarks", ctx, arg)
+	ret0, _ := ret[0].([]services.GetPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookmarks indicates an expected call of GetBookmarks.
+func (mr *MockPostServiceMockRecorder) GetBookmarks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarks", reflect.TypeOf((*MockPostService)(nil).GetBookmarks), ctx, arg)
+}
+
+// GetPost mocks base method.
+func (m *MockPostService) GetPost(ctx context.Context, arg services.GetPostParams) (services.GetPostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPost", ctx, arg)
+	ret0, _ := ret[0].(services.GetPostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPost indicates an expected call of GetPost.
+func (mr *MockPostServiceMockRecorder) GetPost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockPostService)(nil).GetPost), ctx, arg)
+}
+
+// GetPostRevisionDiff mocks base method.
+func (m *MockPostService) GetPostRevisionDiff(ctx context.Context, arg services.GetPostRevisionDiffParams) (services.PostRevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisionDiff", ctx, arg)
+	ret0, _ := ret[0].(services.PostRevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevisionDiff indicates an expected call of GetPostRevisionDiff.
+func (mr *MockPostServiceMockRecorder) GetPostRevisionDiff(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisionDiff", reflect.TypeOf((*MockPostService)(nil).GetPostRevisionDiff), ctx, arg)
+}
+
+// GetPostRevisions mocks base method.
+func (m *MockPostService) GetPostRevisions(ctx context.Context, arg services.GetPostRevisionsParams) ([]services.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisions", ctx, arg)
+	ret0, _ := ret[0].([]services.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockPostServiceMockRecorder) GetPostRevisions(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPostService)(nil).DeletePost), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockPostService)(nil).GetPostRevisions), ctx, arg)
 }
 
 // GetPosts mocks base method.
-func (m *MockPostService) GetPosts(ctx context.Context) ([]services.GetPostsRow, error) {
+func (m *MockPostService) GetPosts(ctx context.Context, arg services.GetPostsParams) ([]services.GetPostsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, arg)
 	ret0, _ := ret[0].([]services.GetPostsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockPostServiceMockRecorder) GetPosts(ctx interface{}) *gomock.Call {
+func (mr *MockPostServiceMockRecorder) GetPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPostService)(nil).GetPosts), ctx, arg)
+}
+
+// GetTagFeed mocks base method.
+func (m *MockPostService) GetTagFeed(ctx context.Context, arg services.GetTagFeedParams) ([]services.GetPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagFeed", ctx, arg)
+	ret0, _ := ret[0].([]services.GetPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagFeed indicates an expected call of GetTagFeed.
+func (mr *MockPostServiceMockRecorder) GetTagFeed(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagFeed", reflect.TypeOf((*MockPostService)(nil).GetTagFeed), ctx, arg)
+}
+
+// GetThread mocks base method.
+func (m *MockPostService) GetThread(ctx context.Context, arg services.GetThreadParams) (services.GetThreadRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThread", ctx, arg)
+	ret0, _ := ret[0].(services.GetThreadRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThread indicates an expected call of GetThread.
+func (mr *MockPostServiceMockRecorder) GetThread(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThread", reflect.TypeOf((*MockPostService)(nil).GetThread), ctx, arg)
+}
+
+// PatchPost mocks base method.
+func (m *MockPostService) PatchPost(ctx context.Context, arg services.PatchPostParams) (services.PatchPostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPost", ctx, arg)
+	ret0, _ := ret[0].(services.PatchPostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchPost indicates an expected call of PatchPost.
+func (mr *MockPostServiceMockRecorder) PatchPost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPost", reflect.TypeOf((*MockPostService)(nil).PatchPost), ctx, arg)
+}
+
+// React mocks base method.
+func (m *MockPostService) React(ctx context.Context, arg services.ReactParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// React indicates an expected call of React.
+func (mr *MockPostServiceMockRecorder) React(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*MockPostService)(nil).React), ctx, arg)
+}
+
+// Repost mocks base method.
+func (m *MockPostService) Repost(ctx context.Context, arg services.RepostParams) (services.RepostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repost", ctx, arg)
+	ret0, _ := ret[0].(services.RepostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Repost indicates an expected call of Repost.
+func (mr *MockPostServiceMockRecorder) Repost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repost", reflect.TypeOf((*MockPostService)(nil).Repost), ctx, arg)
+}
+
+// RevertPost mocks base method.
+func (m *MockPostService) RevertPost(ctx context.Context, arg services.RevertPostParams) (services.UpdatePostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertPost", ctx, arg)
+	ret0, _ := ret[0].(services.UpdatePostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertPost indicates an expected call of RevertPost.
+func (mr *MockPostServiceMockRecorder) RevertPost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertPost", reflect.TypeOf((*MockPostService)(nil).RevertPost), ctx, arg)
+}
+
+// SearchPosts mocks base method.
+func (m *MockPostService) SearchPosts(ctx context.Context, arg services.SearchPostsParams) ([]services.SearchPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, arg)
+	ret0, _ := ret[0].([]services.SearchPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockPostServiceMockRecorder) SearchPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockPostService)(nil).SearchPosts), ctx, arg)
+}
+
+// Unreact mocks base method.
+func (m *MockPostService) Unreact(ctx context.Context, arg services.UnreactParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unreact indicates an expected call of Unreact.
+func (mr *MockPostServiceMockRecorder) Unreact(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*MockPostService)(nil).Unreact), ctx, arg)
+}
+
+// Unrepost mocks base method.
+func (m *MockPostService) Unrepost(ctx context.Context, arg services.UnrepostParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unrepost", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unrepost indicates an expected call of Unrepost.
+func (mr *MockPostServiceMockRecorder) Unrepost(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPostService)(nil).GetPosts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unrepost", reflect.TypeOf((*MockPostService)(nil).Unrepost), ctx, arg)
 }
 
 // UpdatePost mocks base method.
@@ -257,3 +696,571 @@ func (mr *MockPostServiceMockRecorder) UpdatePost(ctx, arg interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockPostService)(nil).UpdatePost), ctx, arg)
 }
+
+// MockBookmarkService is a mock of BookmarkService interface.
+type MockBookmarkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookmarkServiceMockRecorder
+}
+
+// MockBookmarkServiceMockRecorder is the mock recorder for MockBookmarkService.
+type MockBookmarkServiceMockRecorder struct {
+	mock *MockBookmarkService
+}
+
+// NewMockBookmarkService creates a new mock instance.
+func NewMockBookmarkService(ctrl *gomock.Controller) *MockBookmarkService {
+	mock := &MockBookmarkService{ctrl: ctrl}
+	mock.recorder = &MockBookmarkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookmarkService) EXPECT() *MockBookmarkServiceMockRecorder {
+	return m.recorder
+}
+
+// AddBookmark mocks base method.
+func (m *MockBookmarkService) AddBookmark(ctx context.Context, arg services.AddBookmarkParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookmark", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBookmark indicates an expected call of AddBookmark.
+func (mr *MockBookmarkServiceMockRecorder) AddBookmark(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookmark", reflect.TypeOf((*MockBookmarkService)(nil).AddBookmark), ctx, arg)
+}
+
+// CreateCollection mocks base method.
+func (m *MockBookmarkService) CreateCollection(ctx context.Context, arg services.CreateCollectionParams) (services.CreateCollectionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", ctx, arg)
+	ret0, _ := ret[0].(services.CreateCollectionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockBookmarkServiceMockRecorder) CreateCollection(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockBookmarkService)(nil).CreateCollection), ctx, arg)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockBookmarkService) DeleteCollection(ctx context.Context, arg services.DeleteCollectionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockBookmarkServiceMockRecorder) DeleteCollection(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockBookmarkService)(nil).DeleteCollection), ctx, arg)
+}
+
+// GetCollections mocks base method.
+func (m *MockBookmarkService) GetCollections(ctx context.Context, userID int32) ([]services.GetCollectionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", ctx, userID)
+	ret0, _ := ret[0].([]services.GetCollectionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockBookmarkServiceMockRecorder) GetCollections(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockBookmarkService)(nil).GetCollections), ctx, userID)
+}
+
+// RemoveBookmark mocks base method.
+func (m *MockBookmarkService) RemoveBookmark(ctx context.Context, arg services.RemoveBookmarkParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBookmark", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBookmark indicates an expected call of RemoveBookmark.
+func (mr *MockBookmarkServiceMockRecorder) RemoveBookmark(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBookmark", reflect.TypeOf((*MockBookmarkService)(nil).RemoveBookmark), ctx, arg)
+}
+
+// RenameCollection mocks base method.
+func (m *MockBookmarkService) RenameCollection(ctx context.Context, arg services.RenameCollectionParams) (services.RenameCollectionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCollection", ctx, arg)
+	ret0, _ := ret[0].(services.RenameCollectionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCollection indicates an expected call of RenameCollection.
+func (mr *MockBookmarkServiceMockRecorder) RenameCollection(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCollection", reflect.TypeOf((*MockBookmarkService)(nil).RenameCollection), ctx, arg)
+}
+
+// MockMediaService is a mock of MediaService interface.
+type MockMediaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaServiceMockRecorder
+}
+
+// MockMediaServiceMockRecorder is the mock recorder for MockMediaService.
+type MockMediaServiceMockRecorder struct {
+	mock *MockMediaService
+}
+
+// NewMockMediaService creates a new mock instance.
+func NewMockMediaService(ctrl *gomock.Controller) *MockMediaService {
+	mock := &MockMediaService{ctrl: ctrl}
+	mock.recorder = &MockMediaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaService) EXPECT() *MockMediaServiceMockRecorder {
+	return m.recorder
+}
+
+// GetMediaContent mocks base method.
+func (m *MockMediaService) GetMediaContent(ctx context.Context, arg services.GetMediaContentParams) (services.MediaContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaContent", ctx, arg)
+	ret0, _ := ret[0].(services.MediaContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaContent indicates an expected call of GetMediaContent.
+func (mr *MockMediaServiceMockRecorder) GetMediaContent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaContent", reflect.TypeOf((*MockMediaService)(nil).GetMediaContent), ctx, arg)
+}
+
+// UploadMedia mocks base method.
+func (m *MockMediaService) UploadMedia(ctx context.Context, arg services.UploadMediaParams) (services.MediaRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadMedia", ctx, arg)
+	ret0, _ := ret[0].(services.MediaRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadMedia indicates an expected call of UploadMedia.
+func (mr *MockMediaServiceMockRecorder) UploadMedia(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadMedia", reflect.TypeOf((*MockMediaService)(nil).UploadMedia), ctx, arg)
+}
+
+// MockNotificationService is a mock of NotificationService interface.
+type MockNotificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationServiceMockRecorder
+}
+
+// MockNotificationServiceMockRecorder is the mock recorder for MockNotificationService.
+type MockNotificationServiceMockRecorder struct {
+	mock *MockNotificationService
+}
+
+// NewMockNotificationService creates a new mock instance.
+func NewMockNotificationService(ctrl *gomock.Controller) *MockNotificationService {
+	mock := &MockNotificationService{ctrl: ctrl}
+	mock.recorder = &MockNotificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationService) EXPECT() *MockNotificationServiceMockRecorder {
+	return m.recorder
+}
+
+// GetNotifications mocks base method.
+func (m *MockNotificationService) GetNotifications(ctx context.Context, arg services.GetNotificationsParams) ([]services.NotificationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, arg)
+	ret0, _ := ret[0].([]services.NotificationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockNotificationServiceMockRecorder) GetNotifications(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockNotificationService)(nil).GetNotifications), ctx, arg)
+}
+
+// GetPreferences mocks base method.
+func (m *MockNotificationService) GetPreferences(ctx context.Context, userID int32) (services.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userID)
+	ret0, _ := ret[0].(services.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockNotificationServiceMockRecorder) GetPreferences(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockNotificationService)(nil).GetPreferences), ctx, userID)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockNotificationService) MarkAllRead(ctx context.Context, userID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockNotificationServiceMockRecorder) MarkAllRead(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockNotificationService)(nil).MarkAllRead), ctx, userID)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotificationService) MarkRead(ctx context.Context, arg services.MarkNotificationReadParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationServiceMockRecorder) MarkRead(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotificationService)(nil).MarkRead), ctx, arg)
+}
+
+// SetPreferences mocks base method.
+func (m *MockNotificationService) SetPreferences(ctx context.Context, arg services.SetNotificationPreferencesParams) (services.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPreferences", ctx, arg)
+	ret0, _ := ret[0].(services.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPreferences indicates an expected call of SetPreferences.
+func (mr *MockNotificationServiceMockRecorder) SetPreferences(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreferences", reflect.TypeOf((*MockNotificationService)(nil).SetPreferences), ctx, arg)
+}
+
+// MockMessageService is a mock of MessageService interface.
+type MockMessageService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageServiceMockRecorder
+}
+
+// MockMessageServiceMockRecorder is the mock recorder for MockMessageService.
+type MockMessageServiceMockRecorder struct {
+	mock *MockMessageService
+}
+
+// NewMockMessageService creates a new mock instance.
+func NewMockMessageService(ctrl *gomock.Controller) *MockMessageService {
+	mock := &MockMessageService{ctrl: ctrl}
+	mock.recorder = &MockMessageServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageService) EXPECT() *MockMessageServiceMockRecorder {
+	return m.recorder
+}
+
+// GetConversation mocks base method.
+func (m *MockMessageService) GetConversation(ctx context.Context, arg services.GetConversationParams) (services.ConversationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversation", ctx, arg)
+	ret0, _ := ret[0].(services.ConversationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversation indicates an expected call of GetConversation.
+func (mr *MockMessageServiceMockRecorder) GetConversation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversation", reflect.TypeOf((*MockMessageService)(nil).GetConversation), ctx, arg)
+}
+
+// GetConversations mocks base method.
+func (m *MockMessageService) GetConversations(ctx context.Context, arg services.GetConversationsParams) ([]services.ConversationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversations", ctx, arg)
+	ret0, _ := ret[0].([]services.ConversationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversations indicates an expected call of GetConversations.
+func (mr *MockMessageServiceMockRecorder) GetConversations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversations", reflect.TypeOf((*MockMessageService)(nil).GetConversations), ctx, arg)
+}
+
+// GetMessage mocks base method.
+func (m *MockMessageService) GetMessage(ctx context.Context, arg services.GetMessageParams) (services.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, arg)
+	ret0, _ := ret[0].(services.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockMessageServiceMockRecorder) GetMessage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockMessageService)(nil).GetMessage), ctx, arg)
+}
+
+// GetMessages mocks base method.
+func (m *MockMessageService) GetMessages(ctx context.Context, arg services.GetMessagesParams) ([]services.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", ctx, arg)
+	ret0, _ := ret[0].([]services.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockMessageServiceMockRecorder) GetMessages(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockMessageService)(nil).GetMessages), ctx, arg)
+}
+
+// MarkRead mocks base method.
+func (m *MockMessageService) MarkRead(ctx context.Context, arg services.MarkConversationReadParams) (services.ReadReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, arg)
+	ret0, _ := ret[0].(services.ReadReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockMessageServiceMockRecorder) MarkRead(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockMessageService)(nil).MarkRead), ctx, arg)
+}
+
+// SendMessage mocks base method.
+func (m *MockMessageService) SendMessage(ctx context.Context, arg services.SendMessageParams) (services.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", ctx, arg)
+	ret0, _ := ret[0].(services.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockMessageServiceMockRecorder) SendMessage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessageService)(nil).SendMessage), ctx, arg)
+}
+
+// StartConversation mocks base method.
+func (m *MockMessageService) StartConversation(ctx context.Context, arg services.StartConversationParams) (services.ConversationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartConversation", ctx, arg)
+	ret0, _ := ret[0].(services.ConversationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartConversation indicates an expected call of StartConversation.
+func (mr *MockMessageServiceMockRecorder) StartConversation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartConversation", reflect.TypeOf((*MockMessageService)(nil).StartConversation), ctx, arg)
+}
+
+// MockStreamService is a mock of StreamService interface.
+type MockStreamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamServiceMockRecorder
+}
+
+// MockStreamServiceMockRecorder is the mock recorder for MockStreamService.
+type MockStreamServiceMockRecorder struct {
+	mock *MockStreamService
+}
+
+// NewMockStreamService creates a new mock instance.
+func NewMockStreamService(ctrl *gomock.Controller) *MockStreamService {
+	mock := &MockStreamService{ctrl: ctrl}
+	mock.recorder = &MockStreamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService) EXPECT() *MockStreamServiceMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockStreamService) Subscribe(ctx context.Context, arg services.SubscribeParams) (*services.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, arg)
+	ret0, _ := ret[0].(*services.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockStreamServiceMockRecorder) Subscribe(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockStreamService)(nil).Subscribe), ctx, arg)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockStreamService) Unsubscribe(sub *services.Subscription) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unsubscribe", sub)
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockStreamServiceMockRecorder) Unsubscribe(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockStreamService)(nil).Unsubscribe), sub)
+}
+
+// MockProfileService is a mock of ProfileService interface.
+type MockProfileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileServiceMockRecorder
+}
+
+// MockProfileServiceMockRecorder is the mock recorder for MockProfileService.
+type MockProfileServiceMockRecorder struct {
+	mock *MockProfileService
+}
+
+// NewMockProfileService creates a new mock instance.
+func NewMockProfileService(ctrl *gomock.Controller) *MockProfileService {
+	mock := &MockProfileService{ctrl: ctrl}
+	mock.recorder = &MockProfileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileService) EXPECT() *MockProfileServiceMockRecorder {
+	return m.recorder
+}
+
+// GetProfile mocks base method.
+func (m *MockProfileService) GetProfile(ctx context.Context, arg services.GetProfileParams) (services.ProfileRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, arg)
+	ret0, _ := ret[0].(services.ProfileRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfileServiceMockRecorder) GetProfile(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileService)(nil).GetProfile), ctx, arg)
+}
+
+// SetProfile mocks base method.
+func (m *MockProfileService) SetProfile(ctx context.Context, arg services.SetProfileParams) (services.SetProfileRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProfile", ctx, arg)
+	ret0, _ := ret[0].(services.SetProfileRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProfile indicates an expected call of SetProfile.
+func (mr *MockProfileServiceMockRecorder) SetProfile(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProfile", reflect.TypeOf((*MockProfileService)(nil).SetProfile), ctx, arg)
+}
+
+// MockTrendingService is a mock of TrendingService interface.
+type MockTrendingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrendingServiceMockRecorder
+}
+
+// MockTrendingServiceMockRecorder is the mock recorder for MockTrendingService.
+type MockTrendingServiceMockRecorder struct {
+	mock *MockTrendingService
+}
+
+// NewMockTrendingService creates a new mock instance.
+func NewMockTrendingService(ctrl *gomock.Controller) *MockTrendingService {
+	mock := &MockTrendingService{ctrl: ctrl}
+	mock.recorder = &MockTrendingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrendingService) EXPECT() *MockTrendingServiceMockRecorder {
+	return m.recorder
+}
+
+// GetTrendingTags mocks base method.
+func (m *MockTrendingService) GetTrendingTags(ctx context.Context, arg services.GetTrendingTagsParams) ([]services.GetTrendingTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrendingTags", ctx, arg)
+	ret0, _ := ret[0].([]services.GetTrendingTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrendingTags indicates an expected call of GetTrendingTags.
+func (mr *MockTrendingServiceMockRecorder) GetTrendingTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrendingTags", reflect.TypeOf((*MockTrendingService)(nil).GetTrendingTags), ctx, arg)
+}
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockIdempotencyService) Do(ctx context.Context, arg services.IdempotencyParams, fn func(context.Context) services.IdempotentResponse) (services.IdempotentResponse, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, arg, fn)
+	ret0, _ := ret[0].(services.IdempotentResponse)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockIdempotencyServiceMockRecorder) Do(ctx, arg, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockIdempotencyService)(nil).Do), ctx, arg, fn)
+}
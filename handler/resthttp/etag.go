@@ -0,0 +1,46 @@
+package resthttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// etag renders a row version as a strong entity tag.
+func etag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// ifMatchVersion reads the If-Match header. It returns 0 when the header is
+// absent or "*", meaning no version check, and false when the header names
+// nothing that a single row version could satisfy.
+func ifMatchVersion(r *http.Request) (int32, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 32)
+	if err != nil || version <= 0 || header != etag(int32(version)) {
+		return 0, false
+	}
+	return int32(version), true
+}
+
+// notModified reports whether the If-None-Match header already names the
+// given version. Weak tags compare equal to their strong counterpart.
+func notModified(r *http.Request, version int32) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
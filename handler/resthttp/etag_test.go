@@ -0,0 +1,62 @@
+package resthttp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_ifMatchVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   int32
+		wantOK bool
+	}{
+		{name: "absent", header: "", want: 0, wantOK: true},
+		{name: "any", header: "*", want: 0, wantOK: true},
+		{name: "strong tag", header: `"3"`, want: 3, wantOK: true},
+		{name: "weak tag", header: `W/"3"`, wantOK: false},
+		{name: "unquoted", header: `3`, wantOK: false},
+		{name: "not a version", header: `"abc"`, wantOK: false},
+		{name: "list", header: `"3", "4"`, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			got, ok := ifMatchVersion(r)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ifMatchVersion() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_notModified(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int32
+		want    bool
+	}{
+		{name: "absent", header: "", version: 3, want: false},
+		{name: "same version", header: `"3"`, version: 3, want: true},
+		{name: "weak same version", header: `W/"3"`, version: 3, want: true},
+		{name: "in list", header: `"1", "3"`, version: 3, want: true},
+		{name: "stale version", header: `"2"`, version: 3, want: false},
+		{name: "any", header: "*", version: 3, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://localhost:8000/post?id=1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-None-Match", tt.header)
+			}
+			if got := notModified(r, tt.version); got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
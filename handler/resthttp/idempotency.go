@@ -0,0 +1,132 @@
+package resthttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/validation"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	codeIdempotencyKeyReused = "idempotency_key_reused"
+)
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to
+// retry. The first response for a key is stored and replayed for later
+// requests with the same key and body from the same caller; requests sharing
+// a key run one at a time.
+func Idempotency(svc IdempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			resp := NewResponse()
+			if len(key) > maxIdempotencyKeyLength {
+				resp.SetBadRequest("Idempotency-Key must not be longer than 255 characters", w)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			sum := sha256.Sum256(body)
+			// Anonymous callers share user 0.
+			userID, _ := auth.UserID(r.Context())
+
+			var rec *responseRecorder
+			res, replayed, err := svc.Do(r.Context(), services.IdempotencyParams{
+				Key:         key,
+				Scope:       strconv.Itoa(int(userID)) + " " + r.Method + " " + r.URL.Path,
+				RequestHash: hex.EncodeToString(sum[:]),
+			}, func(ctx context.Context) services.IdempotentResponse {
+				rec = &responseRecorder{header: http.Header{}}
+				rr := r.WithContext(ctx)
+				rr.Body = io.NopCloser(bytes.NewReader(body))
+				next.ServeHTTP(rec, rr)
+				return rec.result()
+			})
+			if errors.Is(err, services.ErrIdempotencyKeyReused) {
+				resp.SetUnprocessableEntity(validation.Errors{{
+					Field:   idempotencyKeyHeader,
+					Code:    codeIdempotencyKeyReused,
+					Message: "Idempotency-Key was already used with a different request body",
+				}}, w)
+				return
+			}
+			if err != nil {
+				slog.ErrorContext(r.Context(), "idempotency", "err", err)
+				resp.SetInternalServerError("", w)
+				return
+			}
+
+			if rec != nil {
+				for k, v := range rec.header {
+					w.Header()[k] = v
+				}
+			}
+			if res.ContentType != "" {
+				w.Header().Set("Content-Type", res.ContentType)
+			}
+			if replayed {
+				w.Header().Set(idempotentReplayedHeader, "true")
+			}
+			w.WriteHeader(res.StatusCode)
+			w.Write(res.Body)
+		})
+	}
+}
+
+// responseRecorder buffers a handler's response, headers included, so it can
+// be stored before being written out.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+func (rec *responseRecorder) result() services.IdempotentResponse {
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return services.IdempotentResponse{
+		StatusCode:  status,
+		ContentType: rec.header.Get("Content-Type"),
+		Body:        rec.body.Bytes(),
+	}
+}
@@ -0,0 +1,178 @@
+package resthttp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_Idempotency(t *testing.T) {
+	body := `{"fullname":"John Doe"}`
+	sum := sha256.Sum256([]byte(body))
+	params := services.IdempotencyParams{
+		Key:         "abc",
+		Scope:       "0 POST /user",
+		RequestHash: hex.EncodeToString(sum[:]),
+	}
+
+	tests := []struct {
+		name         string
+		key          string
+		userID       int32
+		initMock     func(is *MockIdempotencyService)
+		wantStatus   int
+		wantBody     string
+		wantCalls    int
+		wantReplayed bool
+		wantETag     string
+	}{
+		{
+			name:       "no key passes through",
+			initMock:   func(is *MockIdempotencyService) {},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":1}`,
+			wantCalls:  1,
+		},
+		{
+			name:       "key too long",
+			key:        strings.Repeat("a", 256),
+			initMock:   func(is *MockIdempotencyService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "first request runs the handler",
+			key:  "abc",
+			initMock: func(is *MockIdempotencyService) {
+				is.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, arg services.IdempotencyParams, fn func(ctx context.Context) services.IdempotentResponse) (services.IdempotentResponse, bool, error) {
+						if arg != params {
+							t.Errorf("Do() arg = %v, want %v", arg, params)
+						}
+						return fn(ctx), false, nil
+					})
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":1}`,
+			wantCalls:  1,
+			wantETag:   `"1"`,
+		},
+		{
+			name:   "key is scoped to the caller",
+			key:    "abc",
+			userID: 42,
+			initMock: func(is *MockIdempotencyService) {
+				is.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, arg services.IdempotencyParams, fn func(ctx context.Context) services.IdempotentResponse) (services.IdempotentResponse, bool, error) {
+						if arg.Scope != "42 POST /user" {
+							t.Errorf("Do() scope = %v, want %v", arg.Scope, "42 POST /user")
+						}
+						return fn(ctx), false, nil
+					})
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":1}`,
+			wantCalls:  1,
+			wantETag:   `"1"`,
+		},
+		{
+			name: "replay returns the stored response",
+			key:  "abc",
+			initMock: func(is *MockIdempotencyService) {
+				is.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).Return(services.IdempotentResponse{
+					StatusCode:  http.StatusCreated,
+					ContentType: "application/json",
+					Body:        []byte(`{"id":1}`),
+				}, true, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantBody:     `{"id":1}`,
+			wantReplayed: true,
+		},
+		{
+			name: "key reused with a different body",
+			key:  "abc",
+			initMock: func(is *MockIdempotencyService) {
+				is.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).Return(services.IdempotentResponse{}, false, services.ErrIdempotencyKeyReused)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error from the store",
+			key:  "abc",
+			initMock: func(is *MockIdempotencyService) {
+				is.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).Return(services.IdempotentResponse{}, false, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "error after the handler ran drops its headers",
+			key:  "abc",
+			initMock: func(is *MockIdempotencyService) {
+				is.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, arg services.IdempotencyParams, fn func(ctx context.Context) services.IdempotentResponse) (services.IdempotentResponse, bool, error) {
+						fn(ctx)
+						return services.IdempotentResponse{}, false, errors.New("error")
+					})
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			is := NewMockIdempotencyService(ctrl)
+			tt.initMock(is)
+
+			calls := 0
+			h := Idempotency(is)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if got, _ := io.ReadAll(r.Body); string(got) != body {
+					t.Errorf("handler body = %q, want %q", got, body)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("ETag", `"1"`)
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":1}`))
+			}))
+
+			req := httptest.NewRequest("POST", "http://localhost:8000/user", strings.NewReader(body))
+			if tt.key != "" {
+				req.Header.Set(idempotencyKeyHeader, tt.key)
+			}
+			if tt.userID != 0 {
+				req = req.WithContext(auth.WithUserID(req.Context(), tt.userID))
+			}
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			if resp.Code != tt.wantStatus {
+				t.Errorf("Idempotency() status = %v, want %v", resp.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && resp.Body.String() != tt.wantBody {
+				t.Errorf("Idempotency() body = %v, want %v", resp.Body.String(), tt.wantBody)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Idempotency() ran handler %d times, want %d", calls, tt.wantCalls)
+			}
+			if got := resp.Header().Get(idempotentReplayedHeader) == "true"; got != tt.wantReplayed {
+				t.Errorf("Idempotency() replayed = %v, want %v", got, tt.wantReplayed)
+			}
+			if got := resp.Header().Get("ETag"); tt.key != "" && got != tt.wantETag {
+				t.Errorf("Idempotency() ETag = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
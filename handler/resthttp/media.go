@@ -0,0 +1,186 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/validation"
+	"github.com/go-chi/chi"
+)
+
+const codeUnsupportedMedia = "unsupported_media"
+
+type MediaHandler struct {
+	mediaService MediaService
+}
+
+func NewMediaHandler(mediaService MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// UploadMedia takes a multipart/form-data body with a user_id field and a
+// file part.
+func (p MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		resp.SetBadRequest("request body must be multipart/form-data", w)
+		return
+	}
+
+	var userID string
+	var data []byte
+	var hasFile bool
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeMultipartError(w, err)
+			return
+		}
+		switch part.FormName() {
+		case "user_id":
+			b, err := io.ReadAll(io.LimitReader(part, 32))
+			if err != nil {
+				writeMultipartError(w, err)
+				return
+			}
+			userID = strings.TrimSpace(string(b))
+		case "file":
+			data, err = io.ReadAll(part)
+			if err != nil {
+				writeMultipartError(w, err)
+				return
+			}
+			hasFile = true
+		}
+		part.Close()
+	}
+
+	var errs validation.Errors
+	uid, err := strconv.ParseInt(userID, 10, 32)
+	if userID == "" {
+		errs = append(errs, validation.FieldError{
+			Field:   "user_id",
+			Code:    validation.CodeRequired,
+			Message: "user_id is required",
+		})
+	} else if err != nil || uid < 1 {
+		errs = append(errs, validation.FieldError{
+			Field:   "user_id",
+			Code:    validation.CodeInvalidType,
+			Message: "user_id must be a positive integer",
+		})
+	}
+	if !hasFile || len(data) == 0 {
+		errs = append(errs, validation.FieldError{
+			Field:   "file",
+			Code:    validation.CodeRequired,
+			Message: "file is required",
+		})
+	}
+	if len(errs) > 0 {
+		resp.SetUnprocessableEntity(errs, w)
+		return
+	}
+
+	res, err := p.mediaService.UploadMedia(r.Context(), services.UploadMediaParams{
+		UserID: int32(uid),
+		Data:   data,
+	})
+	if errors.Is(err, services.ErrMediaTooLarge) {
+		resp.SetRequestEntityTooLarge("file is too large", w)
+		return
+	}
+	if errors.Is(err, services.ErrUnsupportedMedia) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "file",
+			Code:    codeUnsupportedMedia,
+			Message: "file must be a JPEG, PNG, GIF or WebP image or a PDF",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetCreated(res, w)
+	return
+}
+
+func (p MediaHandler) GetMedia(w http.ResponseWriter, r *http.Request) {
+	p.getMediaContent(w, r, false)
+}
+
+func (p MediaHandler) GetMediaThumbnail(w http.ResponseWriter, r *http.Request) {
+	p.getMediaContent(w, r, true)
+}
+
+func (p MediaHandler) getMediaContent(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	resp := NewResponse()
+
+	mid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	res, err := p.mediaService.GetMediaContent(r.Context(), services.GetMediaContentParams{
+		ID:        int32(mid),
+		ViewerID:  viewerID(r),
+		Thumbnail: thumbnail,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("media not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+	defer res.Body.Close()
+
+	h := w.Header()
+	h.Set("Content-Type", res.ContentType)
+	// Browsers must not second-guess the sniffed type, or an upload could be
+	// rendered as something more dangerous than it was accepted as.
+	h.Set("X-Content-Type-Options", "nosniff")
+	if !strings.HasPrefix(res.ContentType, "image/") {
+		h.Set("Content-Disposition", "attachment")
+	}
+	if res.Size > 0 {
+		h.Set("Content-Length", strconv.FormatInt(res.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, res.Body); err != nil {
+		slog.ErrorContext(r.Context(), "getMediaContent: write body", "err", err)
+	}
+}
+
+func writeMultipartError(w http.ResponseWriter, err error) {
+	resp := NewResponse()
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		resp.SetRequestEntityTooLarge(fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit), w)
+		return
+	}
+	resp.SetBadRequest(err.Error(), w)
+}
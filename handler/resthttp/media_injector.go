@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import "github.com/google/wire"
+
+func InitializedMediaHandler(ms MediaService) (*MediaHandler, error) {
+	wire.Build(NewMediaHandler)
+	return nil, nil
+}
@@ -0,0 +1,285 @@
+package resthttp
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_NewMediaHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mediaMock := NewMockMediaService(ctrl)
+
+	want := &MediaHandler{
+		mediaService: mediaMock,
+	}
+	if got := NewMediaHandler(mediaMock); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewMediaHandler() = %v, want %v", got, want)
+	}
+}
+
+// multipartBody encodes fields as a multipart/form-data body, sending the
+// "file" field as a file part.
+func multipartBody(t *testing.T, fields map[string]string) (io.Reader, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		var w io.Writer
+		var err error
+		if name == "file" {
+			w, err = mw.CreateFormFile(name, "upload.bin")
+		} else {
+			w, err = mw.CreateFormField(name)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, value)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf, mw.FormDataContentType()
+}
+
+func Test_UploadMedia(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name        string
+		fields      map[string]string
+		contentType string
+		maxBody     int64
+		initMock    func(m *MockMediaService)
+		wantStatus  int
+	}{
+		{
+			name:   "test normal flow",
+			fields: map[string]string{"user_id": "1", "file": "%PDF-1.4"},
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().UploadMedia(gomock.Any(), services.UploadMediaParams{
+					UserID: 1,
+					Data:   []byte("%PDF-1.4"),
+				}).Return(services.MediaRow{ID: 7, UserID: 1, ContentType: "application/pdf"}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:        "test not multipart",
+			contentType: "application/json",
+			initMock:    func(m *MockMediaService) {},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:       "test missing file and user id",
+			fields:     map[string]string{},
+			initMock:   func(m *MockMediaService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test invalid user id",
+			fields:     map[string]string{"user_id": "abc", "file": "%PDF-1.4"},
+			initMock:   func(m *MockMediaService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test body over the limit",
+			fields:     map[string]string{"user_id": "1", "file": strings.Repeat("x", 2048)},
+			maxBody:    1024,
+			initMock:   func(m *MockMediaService) {},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "test file too large",
+			fields: map[string]string{"user_id": "1", "file": "%PDF-1.4"},
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().UploadMedia(gomock.Any(), gomock.Any()).Return(services.MediaRow{}, services.ErrMediaTooLarge)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "test unsupported file",
+			fields: map[string]string{"user_id": "1", "file": "<html></html>"},
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().UploadMedia(gomock.Any(), gomock.Any()).Return(services.MediaRow{}, services.ErrUnsupportedMedia)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test user not found",
+			fields: map[string]string{"user_id": "1", "file": "%PDF-1.4"},
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().UploadMedia(gomock.Any(), gomock.Any()).Return(services.MediaRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			fields: map[string]string{"user_id": "1", "file": "%PDF-1.4"},
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().UploadMedia(gomock.Any(), gomock.Any()).Return(services.MediaRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMediaService(ctrl)
+			tt.initMock(m)
+			h := MediaHandler{mediaService: m}
+
+			body, contentType := multipartBody(t, tt.fields)
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+			r := httptest.NewRequest("POST", "http://localhost:8000/media", body)
+			r.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			if tt.maxBody > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, tt.maxBody)
+			}
+
+			h.UploadMedia(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("UploadMedia() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_GetMedia(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name            string
+		viewer          int32
+		id              string
+		thumbnail       bool
+		initMock        func(m *MockMediaService)
+		wantStatus      int
+		wantBody        string
+		wantType        string
+		wantDisposition string
+	}{
+		{
+			name:   "test normal flow",
+			viewer: 2,
+			id:     "7",
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().GetMediaContent(gomock.Any(), services.GetMediaContentParams{ID: 7, ViewerID: 2}).Return(services.MediaContent{
+					ContentType: "image/png",
+					Size:        3,
+					Body:        io.NopCloser(strings.NewReader("png")),
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "png",
+			wantType:   "image/png",
+		},
+		{
+			name:      "test thumbnail",
+			id:        "7",
+			thumbnail: true,
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().GetMediaContent(gomock.Any(), services.GetMediaContentParams{ID: 7, Thumbnail: true}).Return(services.MediaContent{
+					ContentType: "image/jpeg",
+					Body:        io.NopCloser(strings.NewReader("jpg")),
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "jpg",
+			wantType:   "image/jpeg",
+		},
+		{
+			name: "test document is downloaded",
+			id:   "8",
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().GetMediaContent(gomock.Any(), gomock.Any()).Return(services.MediaContent{
+					ContentType: "application/pdf",
+					Size:        3,
+					Body:        io.NopCloser(strings.NewReader("pdf")),
+				}, nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        "pdf",
+			wantType:        "application/pdf",
+			wantDisposition: "attachment",
+		},
+		{
+			name:       "test invalid id",
+			id:         "abc",
+			initMock:   func(m *MockMediaService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "test not found",
+			viewer: 2,
+			id:     "7",
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().GetMediaContent(gomock.Any(), gomock.Any()).Return(services.MediaContent{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "7",
+			initMock: func(m *MockMediaService) {
+				m.EXPECT().GetMediaContent(gomock.Any(), gomock.Any()).Return(services.MediaContent{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMediaService(ctrl)
+			tt.initMock(m)
+			h := MediaHandler{mediaService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/media/"+tt.id, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			if tt.thumbnail {
+				h.GetMediaThumbnail(w, r)
+			} else {
+				h.GetMedia(w, r)
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetMedia() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("GetMedia() body = %q, want %q", w.Body, tt.wantBody)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("GetMedia() Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("GetMedia() X-Content-Type-Options = %q, want nosniff", got)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+				t.Errorf("GetMedia() Content-Disposition = %q, want %q", got, tt.wantDisposition)
+			}
+		})
+	}
+}
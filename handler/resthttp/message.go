@@ -0,0 +1,316 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/validation"
+	"github.com/go-chi/chi"
+)
+
+const codeNoParticipants = "no_participants"
+
+type MessageHandler struct {
+	messageService MessageService
+}
+
+func NewMessageHandler(messageService MessageService) *MessageHandler {
+	return &MessageHandler{
+		messageService: messageService,
+	}
+}
+
+// StartConversation takes the other participants. One makes a 1:1
+// conversation, and starting it again returns the existing one. Groups hold
+// up to 10 people.
+func (p MessageHandler) StartConversation(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type StartConversationReq struct {
+		ParticipantIDs []int32 `json:"participant_ids" validate:"required,min=1,max=9"`
+	}
+
+	reqBody := StartConversationReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	res, err := p.messageService.StartConversation(r.Context(), services.StartConversationParams{
+		UserID:         userID,
+		ParticipantIDs: reqBody.ParticipantIDs,
+	})
+	if errors.Is(err, services.ErrNoParticipants) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "participant_ids",
+			Code:    codeNoParticipants,
+			Message: "participant_ids must name someone other than the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrBlocked) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "participant_ids",
+			Code:    codeBlocked,
+			Message: "participant_ids must not block or be blocked by each other or the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetCreated(res, w)
+	return
+}
+
+func (p MessageHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type GetConversationsReq struct {
+		Limit  int32 `json:"limit" validate:"gte=1,lte=100"`
+		Offset int32 `json:"offset" validate:"gte=0"`
+	}
+
+	req := GetConversationsReq{Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.messageService.GetConversations(r.Context(), services.GetConversationsParams{
+		UserID: userID,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p MessageHandler) GetConversation(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	cid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := p.messageService.GetConversation(r.Context(), services.GetConversationParams{
+		ID:     int32(cid),
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("conversation not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	cid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	type SendMessageReq struct {
+		Body string `json:"body" validate:"required,max=5000"`
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	reqBody := SendMessageReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	res, err := p.messageService.SendMessage(r.Context(), services.SendMessageParams{
+		ConversationID: int32(cid),
+		UserID:         userID,
+		Body:           reqBody.Body,
+	})
+	if errors.Is(err, services.ErrBlocked) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "id",
+			Code:    codeBlocked,
+			Message: "conversation must not have a participant who blocks or is blocked by the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("conversation not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetCreated(res, w)
+	return
+}
+
+// GetMessage returns one message, for clients following up on a message
+// stream event.
+func (p MessageHandler) GetMessage(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	cid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+	mid, err := strconv.ParseInt(chi.URLParam(r, "message_id"), 10, 64)
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := p.messageService.GetMessage(r.Context(), services.GetMessageParams{
+		ConversationID: int32(cid),
+		ID:             mid,
+		UserID:         userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("message not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+// GetMessages pages back through a conversation, newest first. Pass the
+// oldest id received as before_id to get the page before it.
+func (p MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	cid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type GetMessagesReq struct {
+		BeforeID int64 `json:"before_id" validate:"gte=0"`
+		Limit    int32 `json:"limit" validate:"gte=1,lte=100"`
+	}
+
+	req := GetMessagesReq{Limit: 50}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.messageService.GetMessages(r.Context(), services.GetMessagesParams{
+		ConversationID: int32(cid),
+		UserID:         userID,
+		BeforeID:       req.BeforeID,
+		Limit:          req.Limit,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("conversation not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+// MarkRead marks the conversation read up to message_id, or entirely when
+// it is left out.
+func (p MessageHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	cid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type MarkReadReq struct {
+		MessageID int64 `json:"message_id" validate:"gte=0"`
+	}
+
+	req := MarkReadReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.messageService.MarkRead(r.Context(), services.MarkConversationReadParams{
+		ConversationID: int32(cid),
+		UserID:         userID,
+		MessageID:      req.MessageID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("conversation not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import "github.com/google/wire"
+
+func InitializedMessageHandler(mgs MessageService) (*MessageHandler, error) {
+	wire.Build(NewMessageHandler)
+	return nil, nil
+}
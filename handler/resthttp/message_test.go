@@ -0,0 +1,538 @@
+package resthttp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_NewMessageHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageMock := NewMockMessageService(ctrl)
+
+	want := &MessageHandler{
+		messageService: messageMock,
+	}
+	if got := NewMessageHandler(messageMock); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewMessageHandler() = %v, want %v", got, want)
+	}
+}
+
+// withConversationID routes r as if its path named conversation id.
+func withConversationID(r *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func Test_StartConversation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		query      string
+		body       string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			body: `{"participant_ids":[2,3]}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().StartConversation(gomock.Any(), services.StartConversationParams{UserID: 1, ParticipantIDs: []int32{2, 3}}).Return(services.ConversationRow{ID: 4}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "test missing participants",
+			body:       `{"participant_ids":[]}`,
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test group too large",
+			body:       `{"participant_ids":[2,3,4,5,6,7,8,9,10,11]}`,
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test unauthenticated",
+			anonymous:  true,
+			body:       `{"participant_ids":[2]}`,
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "test only the user",
+			body: `{"participant_ids":[1]}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().StartConversation(gomock.Any(), gomock.Any()).Return(services.ConversationRow{}, services.ErrNoParticipants)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test blocked",
+			body: `{"participant_ids":[2]}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().StartConversation(gomock.Any(), gomock.Any()).Return(services.ConversationRow{}, services.ErrBlocked)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test user not found",
+			body: `{"participant_ids":[2]}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().StartConversation(gomock.Any(), gomock.Any()).Return(services.ConversationRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			body: `{"participant_ids":[2]}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().StartConversation(gomock.Any(), gomock.Any()).Return(services.ConversationRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/conversations"+tt.query, strings.NewReader(tt.body))
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			h.StartConversation(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("StartConversation() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_GetConversations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		query      string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetConversations(gomock.Any(), services.GetConversationsParams{UserID: 1, Limit: 20}).Return([]services.ConversationRow{{ID: 4}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "test paging",
+			query: "?limit=5&offset=10",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetConversations(gomock.Any(), services.GetConversationsParams{UserID: 1, Limit: 5, Offset: 10}).Return([]services.ConversationRow{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unauthenticated",
+			anonymous:  true,
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "test internal server error",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetConversations(gomock.Any(), gomock.Any()).Return([]services.ConversationRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/conversations"+tt.query, nil)
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			h.GetConversations(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetConversations() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_GetConversation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		id         string
+		query      string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetConversation(gomock.Any(), services.GetConversationParams{ID: 4, UserID: 1}).Return(services.ConversationRow{ID: 4}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test invalid id",
+			id:         "abc",
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "test not found",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetConversation(gomock.Any(), gomock.Any()).Return(services.ConversationRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetConversation(gomock.Any(), gomock.Any()).Return(services.ConversationRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/conversations/"+tt.id+tt.query, nil)
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			h.GetConversation(w, withConversationID(r, tt.id))
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetConversation() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		id         string
+		query      string
+		body       string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			id:   "4",
+			body: `{"body":"hi"}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().SendMessage(gomock.Any(), services.SendMessageParams{ConversationID: 4, UserID: 1, Body: "hi"}).Return(services.Message{ID: 10}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "test invalid id",
+			id:         "abc",
+			body:       `{"body":"hi"}`,
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test empty body",
+			id:         "4",
+			body:       `{"body":""}`,
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test blocked",
+			id:   "4",
+			body: `{"body":"hi"}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(services.Message{}, services.ErrBlocked)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test not a participant",
+			id:   "4",
+			body: `{"body":"hi"}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(services.Message{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "4",
+			body: `{"body":"hi"}`,
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(services.Message{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/conversations/"+tt.id+"/messages"+tt.query, strings.NewReader(tt.body))
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			h.SendMessage(w, withConversationID(r, tt.id))
+			if w.Code != tt.wantStatus {
+				t.Errorf("SendMessage() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_GetMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		id         string
+		messageID  string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name:      "test normal flow",
+			id:        "4",
+			messageID: "10",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessage(gomock.Any(), services.GetMessageParams{ConversationID: 4, ID: 10, UserID: 1}).Return(services.Message{ID: 10}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test invalid message id",
+			id:         "4",
+			messageID:  "abc",
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test unauthenticated",
+			anonymous:  true,
+			id:         "4",
+			messageID:  "10",
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "test not found",
+			id:        "4",
+			messageID: "10",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessage(gomock.Any(), gomock.Any()).Return(services.Message{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:      "test internal server error",
+			id:        "4",
+			messageID: "10",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessage(gomock.Any(), gomock.Any()).Return(services.Message{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/conversations/"+tt.id+"/messages/"+tt.messageID, nil)
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			rctx.URLParams.Add("message_id", tt.messageID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			h.GetMessage(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetMessage() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_GetMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		id         string
+		query      string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessages(gomock.Any(), services.GetMessagesParams{ConversationID: 4, UserID: 1, Limit: 50}).Return([]services.Message{{ID: 10}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "test paging back",
+			id:    "4",
+			query: "?before_id=10&limit=2",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessages(gomock.Any(), services.GetMessagesParams{ConversationID: 4, UserID: 1, BeforeID: 10, Limit: 2}).Return([]services.Message{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test limit too high",
+			id:         "4",
+			query:      "?limit=101",
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test not a participant",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessages(gomock.Any(), gomock.Any()).Return([]services.Message{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().GetMessages(gomock.Any(), gomock.Any()).Return([]services.Message{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/conversations/"+tt.id+"/messages"+tt.query, nil)
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			h.GetMessages(w, withConversationID(r, tt.id))
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetMessages() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_MarkConversationRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		anonymous  bool
+		id         string
+		query      string
+		initMock   func(m *MockMessageService)
+		wantStatus int
+	}{
+		{
+			name:  "test normal flow",
+			id:    "4",
+			query: "?message_id=10",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().MarkRead(gomock.Any(), services.MarkConversationReadParams{ConversationID: 4, UserID: 1, MessageID: 10}).Return(services.ReadReceipt{LastReadMessageID: 10}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unauthenticated",
+			anonymous:  true,
+			id:         "4",
+			initMock:   func(m *MockMessageService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "test not a participant",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().MarkRead(gomock.Any(), gomock.Any()).Return(services.ReadReceipt{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "4",
+			initMock: func(m *MockMessageService) {
+				m.EXPECT().MarkRead(gomock.Any(), gomock.Any()).Return(services.ReadReceipt{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockMessageService(ctrl)
+			tt.initMock(m)
+			h := MessageHandler{messageService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/conversations/"+tt.id+"/read"+tt.query, nil)
+			if !tt.anonymous {
+				r = r.WithContext(auth.WithUserID(r.Context(), 1))
+			}
+			h.MarkRead(w, withConversationID(r, tt.id))
+			if w.Code != tt.wantStatus {
+				t.Errorf("MarkRead() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package resthttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/helper"
+	"github.com/go-chi/chi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_RequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantEcho bool
+	}{
+		{
+			name:     "use caller request id",
+			header:   "abc-123",
+			wantEcho: true,
+		},
+		{
+			name:   "generate request id",
+			header: "",
+		},
+		{
+			name:   "replace request id with invalid characters",
+			header: "abc\" injected=1",
+		},
+		{
+			name:   "replace request id that is too long",
+			header: strings.Repeat("a", 129),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCtxID string
+			h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotCtxID = helper.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "http://localhost:8000/posts", nil)
+			if tt.header != "" {
+				req.Header.Set(requestIDHeader, tt.header)
+			}
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			got := resp.Header().Get(requestIDHeader)
+			if got == "" {
+				t.Fatalf("RequestID() response header is empty")
+			}
+			if tt.wantEcho && got != tt.header {
+				t.Errorf("RequestID() header = %v, want %v", got, tt.header)
+			}
+			if !tt.wantEcho && got == tt.header {
+				t.Errorf("RequestID() header = %v, want a new id", got)
+			}
+			if gotCtxID != got {
+				t.Errorf("RequestID() context id = %v, want %v", gotCtxID, got)
+			}
+		})
+	}
+}
+
+func Test_Authenticate(t *testing.T) {
+	signer, err := auth.NewSigner([]byte(strings.Repeat("s", auth.MinSecretLen)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		signer     *auth.Signer
+		header     string
+		wantStatus int
+		wantUserID int32
+	}{
+		{
+			name:       "valid token",
+			signer:     signer,
+			header:     "Bearer " + signer.Sign(7, auth.ScopeAPI, time.Hour),
+			wantStatus: http.StatusOK,
+			wantUserID: 7,
+		},
+		{
+			name:       "missing header",
+			signer:     signer,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "stream token",
+			signer:     signer,
+			header:     "Bearer " + signer.Sign(7, auth.ScopeStream, time.Hour),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired token",
+			signer:     signer,
+			header:     "Bearer " + signer.Sign(7, auth.ScopeAPI, -time.Minute),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no signer configured",
+			header:     "Bearer " + signer.Sign(7, auth.ScopeAPI, time.Hour),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID int32
+			h := Authenticate(tt.signer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserID, _ = auth.UserID(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "http://localhost:8000/posts", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			if resp.Code != tt.wantStatus {
+				t.Errorf("Authenticate() status = %v, want %v", resp.Code, tt.wantStatus)
+			}
+			if gotUserID != tt.wantUserID {
+				t.Errorf("Authenticate() user id = %v, want %v", gotUserID, tt.wantUserID)
+			}
+		})
+	}
+}
+
+func Test_OptionalAuthenticate(t *testing.T) {
+	signer, err := auth.NewSigner([]byte(strings.Repeat("s", auth.MinSecretLen)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		signer     *auth.Signer
+		header     string
+		wantStatus int
+		wantUserID int32
+	}{
+		{
+			name:       "valid token",
+			signer:     signer,
+			header:     "Bearer " + signer.Sign(7, auth.ScopeAPI, time.Hour),
+			wantStatus: http.StatusOK,
+			wantUserID: 7,
+		},
+		{
+			name:       "anonymous",
+			signer:     signer,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "anonymous without signer",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid token",
+			signer:     signer,
+			header:     "Bearer nope",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID int32
+			h := OptionalAuthenticate(tt.signer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserID = viewerID(r)
+			}))
+
+			req := httptest.NewRequest("GET", "http://localhost:8000/posts", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			if resp.Code != tt.wantStatus {
+				t.Errorf("OptionalAuthenticate() status = %v, want %v", resp.Code, tt.wantStatus)
+			}
+			if gotUserID != tt.wantUserID {
+				t.Errorf("OptionalAuthenticate() viewer = %v, want %v", gotUserID, tt.wantUserID)
+			}
+		})
+	}
+}
+
+func Test_RequestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(helper.NewContextHandler(slog.NewJSONHandler(buf, nil))))
+	defer slog.SetDefault(prev)
+
+	router := chi.NewRouter()
+	router.Use(RequestID, RequestLogger)
+	router.Get("/posts", func(w http.ResponseWriter, r *http.Request) {
+		slog.ErrorContext(r.Context(), "sql error")
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequest("GET", "http://localhost:8000/posts", nil)
+	req.Header.Set(requestIDHeader, "abc-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("RequestLogger() wrote %d lines, want 2", len(lines))
+	}
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["request_id"] != "abc-123" {
+			t.Errorf("request_id = %v, want abc-123", entry["request_id"])
+		}
+		if entry["route"] != "/posts" {
+			t.Errorf("route = %v, want /posts", entry["route"])
+		}
+	}
+
+	access := map[string]interface{}{}
+	json.Unmarshal(lines[1], &access)
+	if access["status"] != float64(http.StatusTeapot) {
+		t.Errorf("status = %v, want %v", access["status"], http.StatusTeapot)
+	}
+	if _, ok := access["duration_ms"]; !ok {
+		t.Errorf("duration_ms is missing")
+	}
+}
+
+func Test_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	router := chi.NewRouter()
+	router.Use(RequestID, Tracing(tp))
+	router.Get("/posts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest("GET", "http://localhost:8000/posts", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /posts" {
+		t.Errorf("span name = %v, want GET /posts", span.Name)
+	}
+	if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %v, want caller trace id", span.SpanContext.TraceID())
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("status = %v, want error", span.Status.Code)
+	}
+}
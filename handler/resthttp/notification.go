@@ -0,0 +1,182 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/go-chi/chi"
+)
+
+type NotificationHandler struct {
+	notificationService NotificationService
+}
+
+func NewNotificationHandler(notificationService NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (p NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type GetNotificationsReq struct {
+		Unread bool  `json:"unread"`
+		Limit  int32 `json:"limit" validate:"gte=1,lte=100"`
+		Offset int32 `json:"offset" validate:"gte=0"`
+	}
+
+	req := GetNotificationsReq{Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.notificationService.GetNotifications(r.Context(), services.GetNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: req.Unread,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	nid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	err = p.notificationService.MarkRead(r.Context(), services.MarkNotificationReadParams{
+		ID:     int32(nid),
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("notification not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := p.notificationService.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+		"count":  n,
+	}, w)
+	return
+}
+
+func (p NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := p.notificationService.GetPreferences(r.Context(), userID)
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+// SetPreferences takes an object of notification kinds to turn on or off.
+// Kinds left out keep their setting.
+func (p NotificationHandler) SetPreferences(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type SetPreferencesReq struct {
+		Follow   *bool `json:"follow"`
+		Reply    *bool `json:"reply"`
+		Mention  *bool `json:"mention"`
+		Reaction *bool `json:"reaction"`
+		Repost   *bool `json:"repost"`
+		Quote    *bool `json:"quote"`
+	}
+
+	reqBody := SetPreferencesReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	prefs := services.NotificationPreferences{}
+	for kind, enabled := range map[string]*bool{
+		services.NotificationKindFollow:   reqBody.Follow,
+		services.NotificationKindReply:    reqBody.Reply,
+		services.NotificationKindMention:  reqBody.Mention,
+		services.NotificationKindReaction: reqBody.Reaction,
+		services.NotificationKindRepost:   reqBody.Repost,
+		services.NotificationKindQuote:    reqBody.Quote,
+	} {
+		if enabled != nil {
+			prefs[kind] = *enabled
+		}
+	}
+
+	res, err := p.notificationService.SetPreferences(r.Context(), services.SetNotificationPreferencesParams{
+		UserID:      userID,
+		Preferences: prefs,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import "github.com/google/wire"
+
+func InitializedNotificationHandler(ns NotificationService) (*NotificationHandler, error) {
+	wire.Build(NewNotificationHandler)
+	return nil, nil
+}
@@ -0,0 +1,346 @@
+package resthttp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_NewNotificationHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	notificationMock := NewMockNotificationService(ctrl)
+
+	want := &NotificationHandler{
+		notificationService: notificationMock,
+	}
+	if got := NewNotificationHandler(notificationMock); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewNotificationHandler() = %v, want %v", got, want)
+	}
+}
+
+func Test_GetNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		query      string
+		initMock   func(m *MockNotificationService)
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().GetNotifications(gomock.Any(), services.GetNotificationsParams{UserID: 1, Limit: 20}).Return([]services.NotificationRow{{ID: 3}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test unread only",
+			userID: 1,
+			query:  "?unread=true&limit=5&offset=10",
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().GetNotifications(gomock.Any(), services.GetNotificationsParams{UserID: 1, UnreadOnly: true, Limit: 5, Offset: 10}).Return([]services.NotificationRow{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unauthenticated",
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "test limit too high",
+			userID:     1,
+			query:      "?limit=101",
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().GetNotifications(gomock.Any(), gomock.Any()).Return([]services.NotificationRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockNotificationService(ctrl)
+			tt.initMock(m)
+			h := NotificationHandler{notificationService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/notifications"+tt.query, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.GetNotifications(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetNotifications() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_MarkNotificationRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		id         string
+		initMock   func(m *MockNotificationService)
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			id:     "3",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().MarkRead(gomock.Any(), services.MarkNotificationReadParams{ID: 3, UserID: 1}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test invalid id",
+			id:         "abc",
+			userID:     1,
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test unauthenticated",
+			id:         "3",
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test not found",
+			id:     "3",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().MarkRead(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			id:     "3",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().MarkRead(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockNotificationService(ctrl)
+			tt.initMock(m)
+			h := NotificationHandler{notificationService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/notifications/"+tt.id+"/read", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.MarkRead(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("MarkRead() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_MarkAllNotificationsRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		initMock   func(m *MockNotificationService)
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().MarkAllRead(gomock.Any(), int32(1)).Return(int64(4), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unauthenticated",
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().MarkAllRead(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockNotificationService(ctrl)
+			tt.initMock(m)
+			h := NotificationHandler{notificationService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/notifications/read", nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.MarkAllRead(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("MarkAllRead() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_GetNotificationPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		initMock   func(m *MockNotificationService)
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().GetPreferences(gomock.Any(), int32(1)).Return(services.NotificationPreferences{services.NotificationKindFollow: true}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unauthenticated",
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().GetPreferences(gomock.Any(), gomock.Any()).Return(services.NotificationPreferences{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockNotificationService(ctrl)
+			tt.initMock(m)
+			h := NotificationHandler{notificationService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/notifications/preferences", nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.GetPreferences(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetPreferences() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func Test_SetNotificationPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		body       string
+		initMock   func(m *MockNotificationService)
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			body:   `{"reaction":false,"follow":true}`,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().SetPreferences(gomock.Any(), services.SetNotificationPreferencesParams{
+					UserID: 1,
+					Preferences: services.NotificationPreferences{
+						services.NotificationKindReaction: false,
+						services.NotificationKindFollow:   true,
+					},
+				}).Return(services.NotificationPreferences{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unknown kind",
+			userID:     1,
+			body:       `{"likes":false}`,
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test unauthenticated",
+			body:       `{"reaction":false}`,
+			initMock:   func(m *MockNotificationService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test user not found",
+			userID: 1,
+			body:   `{"reaction":false}`,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().SetPreferences(gomock.Any(), gomock.Any()).Return(services.NotificationPreferences{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			body:   `{"reaction":false}`,
+			initMock: func(m *MockNotificationService) {
+				m.EXPECT().SetPreferences(gomock.Any(), gomock.Any()).Return(services.NotificationPreferences{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockNotificationService(ctrl)
+			tt.initMock(m)
+			h := NotificationHandler{notificationService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "http://localhost:8000/notifications/preferences", strings.NewReader(tt.body))
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.SetPreferences(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("SetPreferences() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
@@ -1,13 +1,23 @@
 package resthttp
 
 import (
-	"context"
-	"encoding/json"
-	"io/ioutil"
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/validation"
+	"github.com/go-chi/chi"
+)
+
+const (
+	codeOwnPost          = "own_post"
+	codeAlreadyPublished = "already_published"
+	codeTagGone          = "tag_gone"
+	codeMediaNotOwned    = "media_not_owned"
+	codeCursorNotAllowed = "cursor_not_allowed"
 )
 
 type PostHandler struct {
@@ -20,10 +30,57 @@ func NewPostHandler(postService PostService) *PostHandler {
 	}
 }
 
+// GetPosts lists posts a page at a time. Pass the created_at and id of the
+// last post received as cursor_created_at and cursor_id to get the next page.
+// most_reacted has no cursor and returns only the top posts.
 func (p PostHandler) GetPosts(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	res, err := p.postService.GetPosts(context.Background())
+	type GetPostsReq struct {
+		UserID          int32     `json:"user_id" validate:"gte=0"`
+		TagIDs          []int32   `json:"tag_ids" validate:"max=20"`
+		TagMatch        string    `json:"tag_match" validate:"oneof=any all"`
+		From            time.Time `json:"from"`
+		To              time.Time `json:"to"`
+		Sort            string    `json:"sort" validate:"oneof=newest oldest most_reacted"`
+		CursorCreatedAt time.Time `json:"cursor_created_at"`
+		CursorID        int32     `json:"cursor_id" validate:"gte=0"`
+		Limit           int32     `json:"limit" validate:"gte=1,lte=100"`
+	}
+
+	req := GetPostsReq{TagMatch: "any", Sort: services.PostSortNewest, Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+	if req.CursorCreatedAt.IsZero() != (req.CursorID == 0) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "cursor_id",
+			Code:    validation.CodeRequired,
+			Message: "cursor_created_at and cursor_id must be passed together",
+		}}, w)
+		return
+	}
+	if req.CursorID != 0 && req.Sort == services.PostSortMostReacted {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "cursor_id",
+			Code:    codeCursorNotAllowed,
+			Message: "cursor_id cannot be used with sort=most_reacted",
+		}}, w)
+		return
+	}
+
+	res, err := p.postService.GetPosts(r.Context(), services.GetPostsParams{
+		UserID:          req.UserID,
+		CreatedFrom:     req.From,
+		CreatedTo:       req.To,
+		TagIDs:          req.TagIDs,
+		MatchAllTags:    req.TagMatch == "all",
+		Sort:            req.Sort,
+		CursorCreatedAt: req.CursorCreatedAt,
+		CursorID:        req.CursorID,
+		Limit:           req.Limit,
+		ViewerID:        viewerID(r),
+	})
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -33,50 +90,217 @@ func (p PostHandler) GetPosts(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
+func (p PostHandler) GetTagFeed(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	body, err := ioutil.ReadAll(r.Body)
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type GetTagFeedReq struct {
+		Limit  int32 `json:"limit" validate:"gte=1,lte=100"`
+		Offset int32 `json:"offset" validate:"gte=0"`
+	}
+
+	req := GetTagFeedReq{Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.postService.GetTagFeed(r.Context(), services.GetTagFeedParams{
+		UserID: userID,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
 	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
-	type CreatePostReq struct {
-		Userid      int32   `json:"user_id"`
-		Title       string  `json:"title"`
-		Description string  `json:"description"`
-		TagIDs      []int32 `json:"tag_ids"`
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
 	}
 
-	reqBody := CreatePostReq{}
-	err = json.Unmarshal(body, &reqBody)
+	type GetBookmarksReq struct {
+		CollectionID int32 `json:"collection_id" validate:"gte=0"`
+		Limit        int32 `json:"limit" validate:"gte=1,lte=100"`
+		Offset       int32 `json:"offset" validate:"gte=0"`
+	}
+
+	req := GetBookmarksReq{Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.postService.GetBookmarks(r.Context(), services.GetBookmarksParams{
+		UserID:       userID,
+		CollectionID: req.CollectionID,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+	})
 	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
-	if reqBody.Userid == 0 {
-		resp.SetBadRequest("user_id cannot be null", w)
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	pid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
 		return
 	}
 
-	if reqBody.Title == "" {
-		resp.SetBadRequest("title cannot be null", w)
+	type GetThreadReq struct {
+		Depth  int32 `json:"depth" validate:"gte=1,lte=10"`
+		Limit  int32 `json:"limit" validate:"gte=1,lte=100"`
+		Offset int32 `json:"offset" validate:"gte=0"`
+	}
+
+	req := GetThreadReq{Depth: 3, Limit: 20}
+	if !decodeQuery(w, r, &req) {
 		return
 	}
 
-	if reqBody.Description == "" {
-		resp.SetBadRequest("description cannot be null", w)
+	res, err := p.postService.GetThread(r.Context(), services.GetThreadParams{
+		ID:       int32(pid),
+		ViewerID: viewerID(r),
+		Depth:    req.Depth,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
-	res, err := p.postService.CreatePost(context.Background(), services.CreatePostParams{
-		Userid:      reqBody.Userid,
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) SearchPosts(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type SearchPostsReq struct {
+		Query  string    `json:"q" validate:"required,max=500"`
+		TagID  int32     `json:"tag_id" validate:"gte=0"`
+		UserID int32     `json:"user_id" validate:"gte=0"`
+		From   time.Time `json:"from"`
+		To     time.Time `json:"to"`
+		Limit  int32     `json:"limit" validate:"gte=1,lte=100"`
+		Offset int32     `json:"offset" validate:"gte=0"`
+	}
+
+	req := SearchPostsReq{Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.postService.SearchPosts(r.Context(), services.SearchPostsParams{
+		Query:       req.Query,
+		TagID:       req.TagID,
+		UserID:      req.UserID,
+		CreatedFrom: req.From,
+		CreatedTo:   req.To,
+		ViewerID:    viewerID(r),
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type CreatePostReq struct {
+		Title       string     `json:"title" validate:"required,max=255"`
+		Description string     `json:"description" validate:"required,max=5000"`
+		TagIDs      []int32    `json:"tag_ids" validate:"max=20"`
+		InReplyToID int32      `json:"in_reply_to_id" validate:"gte=0"`
+		Visibility  string     `json:"visibility" validate:"oneof=public followers private unlisted"`
+		Status      string     `json:"status" validate:"oneof=draft scheduled published"`
+		PublishAt   *time.Time `json:"publish_at"`
+		MediaIDs    []int32    `json:"media_ids" validate:"max=10"`
+	}
+
+	reqBody := CreatePostReq{Visibility: services.PostVisibilityPublic, Status: services.PostStatusPublished}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	var publishAt time.Time
+	if reqBody.PublishAt != nil {
+		publishAt = *reqBody.PublishAt
+	}
+
+	res, err := p.postService.CreatePost(r.Context(), services.CreatePostParams{
+		Userid:      userID,
 		Title:       reqBody.Title,
 		Description: reqBody.Description,
 		TagID:       reqBody.TagIDs,
+		InReplyToID: reqBody.InReplyToID,
+		Visibility:  reqBody.Visibility,
+		Status:      reqBody.Status,
+		PublishAt:   publishAt,
+		MediaIDs:    reqBody.MediaIDs,
 	})
+	if errors.Is(err, services.ErrPublishAtRequired) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "publish_at",
+			Code:    validation.CodeRequired,
+			Message: "publish_at is required for a scheduled post",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrBlocked) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "in_reply_to_id",
+			Code:    codeBlocked,
+			Message: "in_reply_to_id must not be a post by a user blocking or blocked by the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrMediaNotOwned) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "media_ids",
+			Code:    codeMediaNotOwned,
+			Message: "media_ids must be media uploaded by the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user or replied-to post not found", w)
+		return
+	}
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -86,26 +310,61 @@ func (p PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+func (p PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	body, err := ioutil.ReadAll(r.Body)
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	pid, err := strconv.Atoi(id)
 	if err != nil {
 		resp.SetBadRequest(err.Error(), w)
 		return
 	}
 
+	res, err := p.postService.GetPost(r.Context(), services.GetPostParams{
+		ID:       int32(pid),
+		ViewerID: viewerID(r),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	if notModified(r, res.Version) {
+		resp.SetNotModified(w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
 	type UpdatePostReq struct {
 		ID          int32   `json:"id"`
-		Title       string  `json:"title"`
-		Description string  `json:"description"`
-		TagIDs      []int32 `json:"tag_ids"`
+		Title       string  `json:"title" validate:"required,max=255"`
+		Description string  `json:"description" validate:"required,max=5000"`
+		TagIDs      []int32 `json:"tag_ids" validate:"max=20"`
 	}
 
 	reqBody := UpdatePostReq{}
-	err = json.Unmarshal(body, &reqBody)
-	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+	if !decodeRequest(w, r, &reqBody) {
 		return
 	}
 
@@ -121,27 +380,38 @@ func (p PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if reqBody.Title == "" {
-		resp.SetBadRequest("title cannot be null", w)
-		return
-	}
-
-	if reqBody.Description == "" {
-		resp.SetBadRequest("description cannot be null", w)
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
 		return
 	}
 
-	res, err := p.postService.UpdatePost(context.Background(), services.UpdatePostParams{
+	res, err := p.postService.UpdatePost(r.Context(), services.UpdatePostParams{
 		ID:          int32(pid),
 		Title:       reqBody.Title,
 		Description: reqBody.Description,
 		TagID:       reqBody.TagIDs,
+		EditorID:    userID,
+		Version:     version,
 	})
+	if errors.Is(err, services.ErrNotAuthor) {
+		resp.SetForbidden("only the author can edit an unpublished post or change its status", w)
+		return
+	}
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("post has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post or editor not found", w)
+		return
+	}
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
+	w.Header().Set("ETag", etag(res.Version))
 	resp.SetCreated(res, w)
 	return
 }
@@ -161,7 +431,402 @@ func (p PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = p.postService.DeletePost(context.Background(), int32(pid))
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	err = p.postService.DeletePost(r.Context(), services.DeletePostParams{
+		ID:      int32(pid),
+		Version: version,
+	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("post has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p PostHandler) PatchPost(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type PatchPostReq struct {
+		Title       *string    `json:"title" validate:"min=1,max=255"`
+		Description *string    `json:"description" validate:"min=1,max=5000"`
+		TagIDs      []int32    `json:"tag_ids" validate:"max=20"`
+		Visibility  *string    `json:"visibility" validate:"oneof=public followers private unlisted"`
+		Status      *string    `json:"status" validate:"oneof=draft scheduled published"`
+		PublishAt   *time.Time `json:"publish_at"`
+	}
+
+	reqBody := PatchPostReq{}
+	present, ok := decodeMergePatch(w, r, &reqBody)
+	if !ok {
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	pid, err := strconv.Atoi(id)
+	if err != nil {
+		resp.SetBadRequest(err.Error(), w)
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	res, err := p.postService.PatchPost(r.Context(), services.PatchPostParams{
+		ID:          int32(pid),
+		Title:       reqBody.Title,
+		Description: reqBody.Description,
+		ReplaceTags: present["tag_ids"],
+		TagID:       reqBody.TagIDs,
+		Visibility:  reqBody.Visibility,
+		Status:      reqBody.Status,
+		PublishAt:   reqBody.PublishAt,
+		EditorID:    userID,
+		Version:     version,
+	})
+	if errors.Is(err, services.ErrNotAuthor) {
+		resp.SetForbidden("only the author can edit an unpublished post or change its status", w)
+		return
+	}
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("post has been modified", w)
+		return
+	}
+	if errors.Is(err, services.ErrAlreadyPublished) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "status",
+			Code:    codeAlreadyPublished,
+			Message: "status of a published post cannot change",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrPublishAtRequired) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "publish_at",
+			Code:    validation.CodeRequired,
+			Message: "publish_at is required for a scheduled post",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post or editor not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) GetPostRevisions(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	pid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	type GetPostRevisionsReq struct {
+		Limit  int32 `json:"limit" validate:"gte=1,lte=100"`
+		Offset int32 `json:"offset" validate:"gte=0"`
+	}
+
+	req := GetPostRevisionsReq{Limit: 20}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.postService.GetPostRevisions(r.Context(), services.GetPostRevisionsParams{
+		ID:       int32(pid),
+		ViewerID: viewerID(r),
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) GetPostRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	pid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	type GetPostRevisionDiffReq struct {
+		From int32 `json:"from" validate:"required,gte=1"`
+		To   int32 `json:"to" validate:"required,gte=1"`
+	}
+
+	req := GetPostRevisionDiffReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.postService.GetPostRevisionDiff(r.Context(), services.GetPostRevisionDiffParams{
+		ID:       int32(pid),
+		ViewerID: viewerID(r),
+		From:     req.From,
+		To:       req.To,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post or revision not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) RevertPost(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	pid, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type RevertPostReq struct {
+		Revision int32 `json:"revision" validate:"required,gte=1"`
+	}
+
+	reqBody := RevertPostReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	res, err := p.postService.RevertPost(r.Context(), services.RevertPostParams{
+		ID:       int32(pid),
+		Revision: reqBody.Revision,
+		UserID:   userID,
+		Version:  version,
+	})
+	if errors.Is(err, services.ErrNotAdmin) {
+		resp.SetForbidden("only admins can revert posts", w)
+		return
+	}
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("post has been modified", w)
+		return
+	}
+	if errors.Is(err, services.ErrTagGone) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "revision",
+			Code:    codeTagGone,
+			Message: "revision must not carry a tag that has been deleted",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("post or revision not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	resp.SetOK(res, w)
+	return
+}
+
+func (p PostHandler) Repost(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type RepostReq struct {
+		UserID int32  `json:"user_id" validate:"required,gte=1"`
+		PostID int32  `json:"post_id" validate:"required,gte=1"`
+		Quote  string `json:"quote" validate:"max=5000"`
+	}
+
+	reqBody := RepostReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	res, err := p.postService.Repost(r.Context(), services.RepostParams{
+		UserID: reqBody.UserID,
+		PostID: reqBody.PostID,
+		Quote:  reqBody.Quote,
+	})
+	if errors.Is(err, services.ErrOwnPost) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "post_id",
+			Code:    codeOwnPost,
+			Message: "post_id must not be one of your own posts",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrBlocked) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "post_id",
+			Code:    codeBlocked,
+			Message: "post_id must not be a post by a user blocking or blocked by user_id",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user or post not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetCreated(res, w)
+	return
+}
+
+func (p PostHandler) Unrepost(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type UnrepostReq struct {
+		UserID int32 `json:"user_id" validate:"required,gte=1"`
+		PostID int32 `json:"post_id" validate:"required,gte=1"`
+	}
+
+	req := UnrepostReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.postService.Unrepost(r.Context(), services.UnrepostParams{
+		UserID: req.UserID,
+		PostID: req.PostID,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p PostHandler) React(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type ReactReq struct {
+		UserID int32  `json:"user_id" validate:"required,gte=1"`
+		PostID int32  `json:"post_id" validate:"required,gte=1"`
+		Kind   string `json:"kind" validate:"required,oneof=like love laugh wow sad angry"`
+	}
+
+	reqBody := ReactReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	err := p.postService.React(r.Context(), services.ReactParams{
+		UserID: reqBody.UserID,
+		PostID: reqBody.PostID,
+		Kind:   reqBody.Kind,
+	})
+	if errors.Is(err, services.ErrBlocked) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "post_id",
+			Code:    codeBlocked,
+			Message: "post_id must not be a post by a user blocking or blocked by user_id",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user or post not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p PostHandler) Unreact(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type UnreactReq struct {
+		UserID int32 `json:"user_id" validate:"required,gte=1"`
+		PostID int32 `json:"post_id" validate:"required,gte=1"`
+	}
+
+	req := UnreactReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.postService.Unreact(r.Context(), services.UnreactParams{
+		UserID: req.UserID,
+		PostID: req.PostID,
+	})
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -1,14 +1,19 @@
 package resthttp
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gadhittana01/socialmedia/auth"
 	"github.com/gadhittana01/socialmedia/services"
+	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 )
 
@@ -66,7 +71,7 @@ func Test_GetPosts(t *testing.T) {
 			name: "test normal flow",
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
-				postMock.EXPECT().GetPosts(gomock.Any()).Return([]services.GetPostsRow{
+				postMock.EXPECT().GetPosts(gomock.Any(), services.GetPostsParams{Sort: services.PostSortNewest, Limit: 20}).Return([]services.GetPostsRow{
 					{
 						ID:          1,
 						Userid:      1,
@@ -94,7 +99,7 @@ func Test_GetPosts(t *testing.T) {
 			name: "test internal server error",
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
-				postMock.EXPECT().GetPosts(gomock.Any()).Return([]services.GetPostsRow{}, errors.New("error"))
+				postMock.EXPECT().GetPosts(gomock.Any(), services.GetPostsParams{Sort: services.PostSortNewest, Limit: 20}).Return([]services.GetPostsRow{}, errors.New("error"))
 
 				return PostHandler{
 					postService: postMock,
@@ -114,10 +119,287 @@ func Test_GetPosts(t *testing.T) {
 	}
 }
 
+func Test_GetPosts_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		viewer     int32
+		url        string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name: "test all filters",
+			url:  "http://localhost:8000/posts?user_id=1&tag_ids=1,2&tag_ids=3&tag_match=all&from=2024-01-01&to=2024-02-01&sort=most_reacted",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPosts(gomock.Any(), services.GetPostsParams{
+					UserID:       1,
+					CreatedFrom:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					CreatedTo:    time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					TagIDs:       []int32{1, 2, 3},
+					MatchAllTags: true,
+					Sort:         services.PostSortMostReacted,
+					Limit:        20,
+				}).Return([]services.GetPostsRow{}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test bookmarked flags for a viewer",
+			viewer: 5,
+			url:    "http://localhost:8000/posts",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPosts(gomock.Any(), services.GetPostsParams{
+					Sort:     services.PostSortNewest,
+					Limit:    20,
+					ViewerID: 5,
+				}).Return([]services.GetPostsRow{}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test any tags sorted oldest first",
+			url:  "http://localhost:8000/posts?tag_ids=4&sort=oldest",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPosts(gomock.Any(), services.GetPostsParams{
+					TagIDs: []int32{4},
+					Sort:   services.PostSortOldest,
+					Limit:  20,
+				}).Return([]services.GetPostsRow{}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test next page",
+			url:  "http://localhost:8000/posts?cursor_created_at=2024-01-02T03:04:05.123456Z&cursor_id=9&limit=50",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPosts(gomock.Any(), services.GetPostsParams{
+					Sort:            services.PostSortNewest,
+					CursorCreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC),
+					CursorID:        9,
+					Limit:           50,
+				}).Return([]services.GetPostsRow{}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test limit too large",
+			url:  "http://localhost:8000/posts?limit=101",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test cursor id without created at",
+			url:  "http://localhost:8000/posts?cursor_id=9",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test cursor with most reacted",
+			url:  "http://localhost:8000/posts?sort=most_reacted&cursor_created_at=2024-01-02&cursor_id=9",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test unknown sort",
+			url:  "http://localhost:8000/posts?sort=popular",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test unknown tag match",
+			url:  "http://localhost:8000/posts?tag_ids=1&tag_match=none",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test invalid tag id",
+			url:  "http://localhost:8000/posts?tag_ids=1,x",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			field := tt.fields()
+			field.GetPosts(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetPosts() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_SearchPosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		viewer     int32
+		url        string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test search with filters",
+			viewer: 3,
+			url:    "http://localhost:8000/search/posts?q=holiday&tag_id=2&user_id=1&from=2024-01-01&to=2024-02-01T00:00:00Z&limit=10&offset=10",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().SearchPosts(gomock.Any(), services.SearchPostsParams{
+					Query:       "holiday",
+					TagID:       2,
+					UserID:      1,
+					CreatedFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					CreatedTo:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					ViewerID:    3,
+					Limit:       10,
+					Offset:      10,
+				}).Return([]services.SearchPostsRow{
+					{
+						GetPostsRow: services.GetPostsRow{
+							ID:    1,
+							Title: "holiday yay",
+						},
+						Snippet: "<mark>holiday</mark> yay",
+					},
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test default limit",
+			url:  "http://localhost:8000/search/posts?q=holiday",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().SearchPosts(gomock.Any(), services.SearchPostsParams{
+					Query: "holiday",
+					Limit: 20,
+				}).Return([]services.SearchPostsRow{}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing query",
+			url:  "http://localhost:8000/search/posts",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test invalid date",
+			url:  "http://localhost:8000/search/posts?q=holiday&from=yesterday",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test limit out of range",
+			url:  "http://localhost:8000/search/posts?q=holiday&limit=1000",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/search/posts?q=holiday",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().SearchPosts(gomock.Any(), gomock.Any()).Return([]services.SearchPostsRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			field := tt.fields()
+			field.SearchPosts(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("SearchPosts() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func Test_CreatePost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
-		"user_id" : 1,
 		"title" : "Upa",
 		"description" : "Dayo",
 		"tag_ids" : [3, 4]
@@ -125,34 +407,89 @@ func Test_CreatePost(t *testing.T) {
 	sampleResp := httptest.NewRecorder()
 
 	internalServerErrReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
-		"user_id" : 1,
 		"title" : "Upa",
 		"description" : "Dayo",
 		"tag_ids" : [3, 4]
 	}`))
 	internalServerErrResp := httptest.NewRecorder()
 
-	emptyUserIDReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+	anonReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
 		"title" : "Upa",
 		"description" : "Dayo",
 		"tag_ids" : [3, 4]
 	}`))
-	emptyUserIDResp := httptest.NewRecorder()
+	anonResp := httptest.NewRecorder()
 
 	emptyTitleReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
-		"user_id" : 1,
 		"description" : "Dayo",
 		"tag_ids" : [3, 4]
 	}`))
 	emptyTitleResp := httptest.NewRecorder()
 
 	emptyDescriptionReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
-		"user_id" : 1,
 		"title" : "Upa",
 		"tag_ids" : [3, 4]
 	}`))
 	emptyDescriptionResp := httptest.NewRecorder()
 
+	replyNotFoundReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"in_reply_to_id" : 9,
+		"visibility" : "followers"
+	}`))
+	replyNotFoundResp := httptest.NewRecorder()
+
+	replyBlockedReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"in_reply_to_id" : 9
+	}`))
+	replyBlockedResp := httptest.NewRecorder()
+
+	badVisibilityReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"visibility" : "friends"
+	}`))
+	badVisibilityResp := httptest.NewRecorder()
+
+	scheduledReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"status" : "scheduled",
+		"publish_at" : "2023-05-01T09:00:00+07:00"
+	}`))
+	scheduledResp := httptest.NewRecorder()
+
+	scheduledNoTimeReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"status" : "scheduled"
+	}`))
+	scheduledNoTimeResp := httptest.NewRecorder()
+
+	badStatusReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"status" : "archived"
+	}`))
+	badStatusResp := httptest.NewRecorder()
+
+	mediaReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"media_ids" : [7, 8]
+	}`))
+	mediaResp := httptest.NewRecorder()
+
+	mediaNotOwnedReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "Upa",
+		"description" : "Dayo",
+		"media_ids" : [9]
+	}`))
+	mediaNotOwnedResp := httptest.NewRecorder()
+
 	badReq := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(""))
 	badResp := httptest.NewRecorder()
 
@@ -165,11 +502,13 @@ func Test_CreatePost(t *testing.T) {
 	}
 	tests := []struct {
 		name   string
+		userID int32
 		fields func() PostHandler
 		args   args
 	}{
 		{
-			name: "test normal flow",
+			name:   "test normal flow",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -178,6 +517,8 @@ func Test_CreatePost(t *testing.T) {
 					Title:       "Upa",
 					Description: "Dayo",
 					TagID:       []int32{3, 4},
+					Visibility:  services.PostVisibilityPublic,
+					Status:      services.PostStatusPublished,
 				}).Return(services.CreatePostRow{
 					ID:          1,
 					Userid:      1,
@@ -196,7 +537,8 @@ func Test_CreatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test bad request",
+			name:   "test bad request",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -210,7 +552,7 @@ func Test_CreatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test empty userid",
+			name: "test unauthenticated",
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -219,12 +561,13 @@ func Test_CreatePost(t *testing.T) {
 				}
 			},
 			args: args{
-				w:   emptyUserIDResp,
-				req: emptyUserIDReq,
+				w:   anonResp,
+				req: anonReq,
 			},
 		},
 		{
-			name: "test empty title",
+			name:   "test empty title",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -238,7 +581,8 @@ func Test_CreatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test empty description",
+			name:   "test empty description",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -252,7 +596,8 @@ func Test_CreatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test internal server error",
+			name:   "test internal server error",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -261,6 +606,8 @@ func Test_CreatePost(t *testing.T) {
 					Title:       "Upa",
 					Description: "Dayo",
 					TagID:       []int32{3, 4},
+					Visibility:  services.PostVisibilityPublic,
+					Status:      services.PostStatusPublished,
 				}).Return(services.CreatePostRow{}, errors.New("error"))
 
 				return PostHandler{
@@ -272,49 +619,207 @@ func Test_CreatePost(t *testing.T) {
 				req: internalServerErrReq,
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			field := tt.fields()
-			field.CreatePost(tt.args.w, tt.args.req)
-		})
-	}
-}
+		{
+			name:   "test reply to missing post",
+			userID: 1,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
 
-func Test_UpdatePost(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	sampleReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
-		"title" : "uaya",
-		"description" : "Yoyooy",
-		"tag_ids" : [1, 2]
-	}`))
-	sampleResp := httptest.NewRecorder()
+				postMock.EXPECT().CreatePost(gomock.Any(), services.CreatePostParams{
+					Userid:      1,
+					Title:       "Upa",
+					Description: "Dayo",
+					InReplyToID: 9,
+					Visibility:  services.PostVisibilityFollowers,
+					Status:      services.PostStatusPublished,
+				}).Return(services.CreatePostRow{}, sql.ErrNoRows)
 
-	internalServerErrReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
-		"title" : "uaya",
-		"description" : "Yoyooy",
-		"tag_ids" : [1, 2]
-	}`))
-	internalServerErrResp := httptest.NewRecorder()
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   replyNotFoundResp,
+				req: replyNotFoundReq,
+			},
+		},
+		{
+			name:   "test reply to a blocked user",
+			userID: 1,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
 
-	idErrParseReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id='error'", strings.NewReader(`{
-		"title" : "uaya",
-		"description" : "Yoyooy",
-		"tag_ids" : [1, 2]
-	}`))
-	idErrParseResp := httptest.NewRecorder()
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(services.CreatePostRow{}, services.ErrBlocked)
 
-	idErrReq := httptest.NewRequest("PUT", "http://localhost:8000/post", strings.NewReader(`{
-		"title" : "uaya",
-		"description" : "Yoyooy",
-		"tag_ids" : [1, 2]
-	}`))
-	idErrResp := httptest.NewRecorder()
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   replyBlockedResp,
+				req: replyBlockedReq,
+			},
+		},
+		{
+			name:   "test invalid visibility",
+			userID: 1,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			args: args{
+				w:   badVisibilityResp,
+				req: badVisibilityReq,
+			},
+		},
+		{
+			name:   "test scheduled post",
+			userID: 1,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
 
-	emptyTitleReq := httptest.NewRequest("POST", "http://localhost:8000/post?id=1", strings.NewReader(`{
-		"description" : "Yoyooy",
-		"tag_ids" : [1, 2]
-	}`))
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg services.CreatePostParams) (services.CreatePostRow, error) {
+					want := time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC)
+					if arg.Status != services.PostStatusScheduled || !arg.PublishAt.Equal(want) {
+						t.Errorf("CreatePost() status = %q, publish_at = %v, want %q, %v", arg.Status, arg.PublishAt, services.PostStatusScheduled, want)
+					}
+					return services.CreatePostRow{ID: 1, Status: services.PostStatusScheduled, PublishAt: &want}, nil
+				})
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   scheduledResp,
+				req: scheduledReq,
+			},
+		},
+		{
+			name:   "test scheduled post without publish_at",
+			userID: 1,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(services.CreatePostRow{}, services.ErrPublishAtRequired)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   scheduledNoTimeResp,
+				req: scheduledNoTimeReq,
+			},
+		},
+		{
+			name:   "test invalid status",
+			userID: 1,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			args: args{
+				w:   badStatusResp,
+				req: badStatusReq,
+			},
+		},
+		{
+			name:   "test post with media",
+			userID: 1,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), services.CreatePostParams{
+					Userid:      1,
+					Title:       "Upa",
+					Description: "Dayo",
+					Visibility:  services.PostVisibilityPublic,
+					Status:      services.PostStatusPublished,
+					MediaIDs:    []int32{7, 8},
+				}).Return(services.CreatePostRow{ID: 1, MediaIDs: []int32{7, 8}}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   mediaResp,
+				req: mediaReq,
+			},
+		},
+		{
+			name:   "test media of another user",
+			userID: 1,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(services.CreatePostRow{}, services.ErrMediaNotOwned)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   mediaNotOwnedResp,
+				req: mediaNotOwnedReq,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			r := tt.args.req
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field.CreatePost(tt.args.w, r)
+		})
+	}
+	if anonResp.Code != http.StatusUnauthorized {
+		t.Errorf("CreatePost() without a token = %d, want %d", anonResp.Code, http.StatusUnauthorized)
+	}
+	if mediaNotOwnedResp.Code != http.StatusUnprocessableEntity || !strings.Contains(mediaNotOwnedResp.Body.String(), codeMediaNotOwned) {
+		t.Errorf("CreatePost() with media of another user = %d %s, want 422 %s", mediaNotOwnedResp.Code, mediaNotOwnedResp.Body, codeMediaNotOwned)
+	}
+}
+
+func Test_UpdatePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	sampleReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
+		"title" : "uaya",
+		"description" : "Yoyooy",
+		"tag_ids" : [1, 2]
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	internalServerErrReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
+		"title" : "uaya",
+		"description" : "Yoyooy",
+		"tag_ids" : [1, 2]
+	}`))
+	internalServerErrResp := httptest.NewRecorder()
+
+	idErrParseReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id='error'", strings.NewReader(`{
+		"title" : "uaya",
+		"description" : "Yoyooy",
+		"tag_ids" : [1, 2]
+	}`))
+	idErrParseResp := httptest.NewRecorder()
+
+	idErrReq := httptest.NewRequest("PUT", "http://localhost:8000/post", strings.NewReader(`{
+		"title" : "uaya",
+		"description" : "Yoyooy",
+		"tag_ids" : [1, 2]
+	}`))
+	idErrResp := httptest.NewRecorder()
+
+	emptyTitleReq := httptest.NewRequest("POST", "http://localhost:8000/post?id=1", strings.NewReader(`{
+		"description" : "Yoyooy",
+		"tag_ids" : [1, 2]
+	}`))
 	emptyTitleResp := httptest.NewRecorder()
 
 	emptyDescriptionReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
@@ -326,6 +831,18 @@ func Test_UpdatePost(t *testing.T) {
 	badReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(""))
 	badResp := httptest.NewRecorder()
 
+	editorReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
+		"title" : "uaya",
+		"description" : "Yoyooy"
+	}`))
+	editorResp := httptest.NewRecorder()
+
+	anonReq := httptest.NewRequest("PUT", "http://localhost:8000/post?id=1", strings.NewReader(`{
+		"title" : "uaya",
+		"description" : "Yoyooy"
+	}`))
+	anonResp := httptest.NewRecorder()
+
 	type fields struct {
 		postService PostService
 	}
@@ -335,11 +852,13 @@ func Test_UpdatePost(t *testing.T) {
 	}
 	tests := []struct {
 		name   string
+		userID int32
 		fields func() PostHandler
 		args   args
 	}{
 		{
-			name: "test normal flow",
+			name:   "test normal flow",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -348,6 +867,7 @@ func Test_UpdatePost(t *testing.T) {
 					Title:       "uaya",
 					Description: "Yoyooy",
 					TagID:       []int32{1, 2},
+					EditorID:    1,
 				}).Return(services.UpdatePostRow{
 					Title:       "uaya",
 					Description: "Yoyooy",
@@ -364,7 +884,8 @@ func Test_UpdatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test bad request",
+			name:   "test bad request",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -378,7 +899,8 @@ func Test_UpdatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test empty title",
+			name:   "test empty title",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -392,7 +914,8 @@ func Test_UpdatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test empty description",
+			name:   "test empty description",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -406,7 +929,8 @@ func Test_UpdatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test internal server error",
+			name:   "test internal server error",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -415,6 +939,7 @@ func Test_UpdatePost(t *testing.T) {
 					Title:       "uaya",
 					Description: "Yoyooy",
 					TagID:       []int32{1, 2},
+					EditorID:    1,
 				}).Return(services.UpdatePostRow{}, errors.New("error"))
 
 				return PostHandler{
@@ -427,7 +952,8 @@ func Test_UpdatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test id not provided",
+			name:   "test id not provided",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -441,7 +967,44 @@ func Test_UpdatePost(t *testing.T) {
 			},
 		},
 		{
-			name: "test error parsed id",
+			name:   "test edit by another user",
+			userID: 3,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+
+				postMock.EXPECT().UpdatePost(gomock.Any(), services.UpdatePostParams{
+					ID:          1,
+					Title:       "uaya",
+					Description: "Yoyooy",
+					EditorID:    3,
+				}).Return(services.UpdatePostRow{}, services.ErrNotAuthor)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   editorResp,
+				req: editorReq,
+			},
+		},
+		{
+			name: "test unauthenticated",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			args: args{
+				w:   anonResp,
+				req: anonReq,
+			},
+		},
+		{
+			name:   "test error parsed id",
+			userID: 1,
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
@@ -458,9 +1021,19 @@ func Test_UpdatePost(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			field := tt.fields()
-			field.UpdatePost(tt.args.w, tt.args.req)
+			r := tt.args.req
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field.UpdatePost(tt.args.w, r)
 		})
 	}
+	if editorResp.Code != http.StatusForbidden {
+		t.Errorf("UpdatePost() by another user = %d, want %d", editorResp.Code, http.StatusForbidden)
+	}
+	if anonResp.Code != http.StatusUnauthorized {
+		t.Errorf("UpdatePost() without a token = %d, want %d", anonResp.Code, http.StatusUnauthorized)
+	}
 }
 
 func Test_DeletePost(t *testing.T) {
@@ -494,7 +1067,9 @@ func Test_DeletePost(t *testing.T) {
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
-				postMock.EXPECT().DeletePost(gomock.Any(), int32(1)).Return(nil)
+				postMock.EXPECT().DeletePost(gomock.Any(), services.DeletePostParams{
+					ID: 1,
+				}).Return(nil)
 
 				return PostHandler{
 					postService: postMock,
@@ -510,7 +1085,9 @@ func Test_DeletePost(t *testing.T) {
 			fields: func() PostHandler {
 				postMock := NewMockPostService(ctrl)
 
-				postMock.EXPECT().DeletePost(gomock.Any(), int32(1)).Return(errors.New("error"))
+				postMock.EXPECT().DeletePost(gomock.Any(), services.DeletePostParams{
+					ID: 1,
+				}).Return(errors.New("error"))
 
 				return PostHandler{
 					postService: postMock,
@@ -557,3 +1134,1440 @@ func Test_DeletePost(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	title := "holiday yay"
+	unlisted := services.PostVisibilityUnlisted
+	published := services.PostStatusPublished
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		body       string
+		ifMatch    string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test patch title keeps tags",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"title": "holiday yay"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:       1,
+					EditorID: 1,
+					Title:    &title,
+				}).Return(services.PatchPostRow{
+					ID:    1,
+					Title: "holiday yay",
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test patch visibility",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"visibility": "unlisted"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:         1,
+					EditorID:   1,
+					Visibility: &unlisted,
+				}).Return(services.PatchPostRow{
+					ID:         1,
+					Visibility: services.PostVisibilityUnlisted,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test publish draft",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"status": "published"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:       1,
+					EditorID: 1,
+					Status:   &published,
+				}).Return(services.PatchPostRow{
+					ID:     1,
+					Status: services.PostStatusPublished,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test un-publish a published post",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"status": "draft"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), gomock.Any()).Return(services.PatchPostRow{}, services.ErrAlreadyPublished)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test schedule without publish_at",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"status": "scheduled"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), gomock.Any()).Return(services.PatchPostRow{}, services.ErrPublishAtRequired)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test invalid status",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"status": "archived"}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test invalid visibility",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"visibility": "friends"}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test empty tag ids clears tags",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"tag_ids": []}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:          1,
+					EditorID:    1,
+					ReplaceTags: true,
+					TagID:       []int32{},
+				}).Return(services.PatchPostRow{
+					ID: 1,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test null title",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"title": null}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test id not provided",
+			userID: 1,
+			url:    "http://localhost:8000/post",
+			body:   `{"title": "holiday yay"}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "test post not found",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"title": "holiday yay"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), gomock.Any()).Return(services.PatchPostRow{}, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"title": "holiday yay"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), gomock.Any()).Return(services.PatchPostRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "test matching if match",
+			userID:  1,
+			url:     "http://localhost:8000/post?id=1",
+			body:    `{"title": "holiday yay"}`,
+			ifMatch: `"3"`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:       1,
+					EditorID: 1,
+					Title:    &title,
+					Version:  3,
+				}).Return(services.PatchPostRow{
+					ID:      1,
+					Title:   "holiday yay",
+					Version: 4,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "test stale if match",
+			userID:  1,
+			url:     "http://localhost:8000/post?id=1",
+			body:    `{"title": "holiday yay"}`,
+			ifMatch: `"2"`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:       1,
+					EditorID: 1,
+					Title:    &title,
+					Version:  2,
+				}).Return(services.PatchPostRow{}, services.ErrVersionConflict)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "test weak if match",
+			userID:  1,
+			url:     "http://localhost:8000/post?id=1",
+			body:    `{"title": "holiday yay"}`,
+			ifMatch: `W/"3"`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "test status change by another user",
+			userID: 3,
+			url:    "http://localhost:8000/post?id=1",
+			body:   `{"status": "published"}`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().PatchPost(gomock.Any(), services.PatchPostParams{
+					ID:       1,
+					Status:   &published,
+					EditorID: 3,
+				}).Return(services.PatchPostRow{}, services.ErrNotAuthor)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/post?id=1",
+			body: `{"title": "holiday yay"}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", tt.url, strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.PatchPost(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("PatchPost() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name        string
+		viewer      int32
+		url         string
+		ifNoneMatch string
+		fields      func() PostHandler
+		wantStatus  int
+		wantETag    string
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/post?id=1",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPost(gomock.Any(), services.GetPostParams{ID: 1}).Return(services.GetPostRow{
+					ID:      1,
+					Title:   "holiday yay",
+					Version: 3,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:        "test not modified",
+			url:         "http://localhost:8000/post?id=1",
+			ifNoneMatch: `"3"`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPost(gomock.Any(), services.GetPostParams{ID: 1}).Return(services.GetPostRow{
+					ID:      1,
+					Title:   "holiday yay",
+					Version: 3,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusNotModified,
+			wantETag:   `"3"`,
+		},
+		{
+			name:        "test stale if none match",
+			url:         "http://localhost:8000/post?id=1",
+			ifNoneMatch: `"2"`,
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPost(gomock.Any(), services.GetPostParams{ID: 1}).Return(services.GetPostRow{
+					ID:      1,
+					Title:   "holiday yay",
+					Version: 3,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:   "test authenticated viewer",
+			viewer: 2,
+			url:    "http://localhost:8000/post?id=1",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPost(gomock.Any(), services.GetPostParams{ID: 1, ViewerID: 2}).Return(services.GetPostRow{
+					ID:         1,
+					Visibility: services.PostVisibilityFollowers,
+					Version:    3,
+				}, nil)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name: "test id not provided",
+			url:  "http://localhost:8000/post",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "test post not found",
+			url:  "http://localhost:8000/post?id=1",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPost(gomock.Any(), services.GetPostParams{ID: 1}).Return(services.GetPostRow{}, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/post?id=1",
+			fields: func() PostHandler {
+				postMock := NewMockPostService(ctrl)
+				postMock.EXPECT().GetPost(gomock.Any(), services.GetPostParams{ID: 1}).Return(services.GetPostRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: postMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			field := tt.fields()
+			field.GetPost(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetPost() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("GetPost() ETag = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func Test_GetTagFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			url:    "http://localhost:8000/feed/tags?limit=10&offset=10",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetTagFeed(gomock.Any(), services.GetTagFeedParams{
+					UserID: 1,
+					Limit:  10,
+					Offset: 10,
+				}).Return([]services.GetPostsRow{{ID: 1}}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test default limit",
+			userID: 1,
+			url:    "http://localhost:8000/feed/tags",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetTagFeed(gomock.Any(), services.GetTagFeedParams{
+					UserID: 1,
+					Limit:  20,
+				}).Return([]services.GetPostsRow{}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/feed/tags",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/feed/tags",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetTagFeed(gomock.Any(), gomock.Any()).Return([]services.GetPostsRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.GetTagFeed(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetTagFeed() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetBookmarks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			url:    "http://localhost:8000/bookmarks?collection_id=3&limit=10&offset=10",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetBookmarks(gomock.Any(), services.GetBookmarksParams{
+					UserID:       1,
+					CollectionID: 3,
+					Limit:        10,
+					Offset:       10,
+				}).Return([]services.GetPostsRow{{ID: 1, Bookmarked: true}}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test default limit",
+			userID: 1,
+			url:    "http://localhost:8000/bookmarks",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetBookmarks(gomock.Any(), services.GetBookmarksParams{
+					UserID: 1,
+					Limit:  20,
+				}).Return([]services.GetPostsRow{}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/bookmarks",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/bookmarks",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetBookmarks(gomock.Any(), gomock.Any()).Return([]services.GetPostsRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.GetBookmarks(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetBookmarks() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_Repost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name: "test repost",
+			body: `{"user_id": 2, "post_id": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Repost(gomock.Any(), services.RepostParams{
+					UserID: 2,
+					PostID: 1,
+				}).Return(services.RepostRow{ID: 7, Userid: 2, RepostOfID: 1}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "test quote",
+			body: `{"user_id": 2, "post_id": 1, "quote": "so true"}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Repost(gomock.Any(), services.RepostParams{
+					UserID: 2,
+					PostID: 1,
+					Quote:  "so true",
+				}).Return(services.RepostRow{ID: 8, Userid: 2, Description: "so true", QuoteOfID: 1}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "test missing post id",
+			body: `{"user_id": 2}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test own post",
+			body: `{"user_id": 1, "post_id": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Repost(gomock.Any(), gomock.Any()).Return(services.RepostRow{}, services.ErrOwnPost)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test post by a blocked user",
+			body: `{"user_id": 1, "post_id": 2}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Repost(gomock.Any(), gomock.Any()).Return(services.RepostRow{}, services.ErrBlocked)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test post not found",
+			body: `{"user_id": 2, "post_id": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Repost(gomock.Any(), gomock.Any()).Return(services.RepostRow{}, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			body: `{"user_id": 2, "post_id": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Repost(gomock.Any(), gomock.Any()).Return(services.RepostRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/repost", strings.NewReader(tt.body))
+			field := tt.fields()
+			field.Repost(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Repost() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_Unrepost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/repost?user_id=2&post_id=1",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Unrepost(gomock.Any(), services.UnrepostParams{
+					UserID: 2,
+					PostID: 1,
+				}).Return(nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing post id",
+			url:  "http://localhost:8000/repost?user_id=2",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/repost?user_id=2&post_id=1",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Unrepost(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", tt.url, nil)
+			field := tt.fields()
+			field.Unrepost(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Unrepost() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_React(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			body: `{"user_id": 2, "post_id": 1, "kind": "love"}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().React(gomock.Any(), services.ReactParams{
+					UserID: 2,
+					PostID: 1,
+					Kind:   "love",
+				}).Return(nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unknown kind",
+			body: `{"user_id": 2, "post_id": 1, "kind": "meh"}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test blocked",
+			body: `{"user_id": 2, "post_id": 1, "kind": "like"}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().React(gomock.Any(), gomock.Any()).Return(services.ErrBlocked)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test not found",
+			body: `{"user_id": 2, "post_id": 1, "kind": "like"}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().React(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			body: `{"user_id": 2, "post_id": 1, "kind": "like"}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().React(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/reaction", strings.NewReader(tt.body))
+			field := tt.fields()
+			field.React(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("React() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_Unreact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/reaction?user_id=2&post_id=1",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Unreact(gomock.Any(), services.UnreactParams{
+					UserID: 2,
+					PostID: 1,
+				}).Return(nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing post id",
+			url:  "http://localhost:8000/reaction?user_id=2",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/reaction?user_id=2&post_id=1",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().Unreact(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", tt.url, nil)
+			field := tt.fields()
+			field.Unreact(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Unreact() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		viewer     int32
+		id         string
+		query      string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			viewer: 3,
+			id:     "2",
+			query:  "?depth=5&limit=10&offset=10",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetThread(gomock.Any(), services.GetThreadParams{
+					ID:       2,
+					ViewerID: 3,
+					Depth:    5,
+					Limit:    10,
+					Offset:   10,
+				}).Return(services.GetThreadRow{Post: services.ThreadPost{ID: 2}}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test defaults",
+			id:   "2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetThread(gomock.Any(), services.GetThreadParams{
+					ID:    2,
+					Depth: 3,
+					Limit: 20,
+				}).Return(services.GetThreadRow{Post: services.ThreadPost{ID: 2}}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test invalid id",
+			id:   "abc",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "test depth out of range",
+			id:    "2",
+			query: "?depth=11",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test not found",
+			id:   "2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetThread(gomock.Any(), gomock.Any()).Return(services.GetThreadRow{}, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetThread(gomock.Any(), gomock.Any()).Return(services.GetThreadRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/posts/"+tt.id+"/thread"+tt.query, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			field := tt.fields()
+			field.GetThread(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetThread() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetPostRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		viewer     int32
+		id         string
+		query      string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			viewer: 3,
+			id:     "2",
+			query:  "?limit=10&offset=10",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisions(gomock.Any(), services.GetPostRevisionsParams{
+					ID:       2,
+					ViewerID: 3,
+					Limit:    10,
+					Offset:   10,
+				}).Return([]services.PostRevision{{PostID: 2, Revision: 1}}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test defaults",
+			id:   "2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisions(gomock.Any(), services.GetPostRevisionsParams{
+					ID:    2,
+					Limit: 20,
+				}).Return([]services.PostRevision{}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test invalid id",
+			id:   "abc",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "test limit out of range",
+			id:    "2",
+			query: "?limit=101",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test not found",
+			id:   "2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisions(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			id:   "2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisions(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/posts/"+tt.id+"/revisions"+tt.query, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			field := tt.fields()
+			field.GetPostRevisions(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetPostRevisions() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetPostRevisionDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		viewer     int32
+		id         string
+		query      string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			viewer: 4,
+			id:     "2",
+			query:  "?from=1&to=3",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisionDiff(gomock.Any(), services.GetPostRevisionDiffParams{
+					ID:       2,
+					ViewerID: 4,
+					From:     1,
+					To:       3,
+				}).Return(services.PostRevisionDiff{PostID: 2, From: 1, To: 3}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "test missing from",
+			id:    "2",
+			query: "?to=3",
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "test revision not found",
+			id:    "2",
+			query: "?from=1&to=9",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisionDiff(gomock.Any(), gomock.Any()).Return(services.PostRevisionDiff{}, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:  "test internal server error",
+			id:    "2",
+			query: "?from=1&to=2",
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().GetPostRevisionDiff(gomock.Any(), gomock.Any()).Return(services.PostRevisionDiff{}, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/posts/"+tt.id+"/revisions/diff"+tt.query, nil)
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			field := tt.fields()
+			field.GetPostRevisionDiff(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetPostRevisionDiff() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_RevertPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		body       string
+		ifMatch    string
+		fields     func() PostHandler
+		wantStatus int
+	}{
+		{
+			name:    "test normal flow",
+			userID:  9,
+			body:    `{"revision": 1}`,
+			ifMatch: `"4"`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().RevertPost(gomock.Any(), services.RevertPostParams{
+					ID:       2,
+					Revision: 1,
+					UserID:   9,
+					Version:  4,
+				}).Return(services.UpdatePostRow{Title: "holiday", Version: 5}, nil)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test missing revision",
+			userID: 9,
+			body:   `{}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test not admin",
+			userID: 3,
+			body:   `{"revision": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().RevertPost(gomock.Any(), gomock.Any()).Return(services.UpdatePostRow{}, services.ErrNotAdmin)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:    "test version conflict",
+			userID:  9,
+			body:    `{"revision": 1}`,
+			ifMatch: `"3"`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().RevertPost(gomock.Any(), gomock.Any()).Return(services.UpdatePostRow{}, services.ErrVersionConflict)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "test tag gone",
+			userID: 9,
+			body:   `{"revision": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().RevertPost(gomock.Any(), gomock.Any()).Return(services.UpdatePostRow{}, services.ErrTagGone)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test not found",
+			userID: 9,
+			body:   `{"revision": 7}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().RevertPost(gomock.Any(), gomock.Any()).Return(services.UpdatePostRow{}, sql.ErrNoRows)
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test unauthenticated",
+			body: `{"revision": 1}`,
+			fields: func() PostHandler {
+				return PostHandler{
+					postService: NewMockPostService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 9,
+			body:   `{"revision": 1}`,
+			fields: func() PostHandler {
+				m := NewMockPostService(ctrl)
+				m.EXPECT().RevertPost(gomock.Any(), gomock.Any()).Return(services.UpdatePostRow{}, errors.New("error"))
+
+				return PostHandler{
+					postService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/posts/2/revert", strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "2")
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			if tt.userID != 0 {
+				ctx = auth.WithUserID(ctx, tt.userID)
+			}
+			r = r.WithContext(ctx)
+			field := tt.fields()
+			field.RevertPost(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("RevertPost() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
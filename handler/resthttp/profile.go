@@ -0,0 +1,147 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/validation"
+	"github.com/go-chi/chi"
+)
+
+const (
+	codeInvalidHandle  = "invalid_handle"
+	codeInvalidWebsite = "invalid_website"
+	codeHandleTaken    = "handle_taken"
+	codeAvatarNotOwned = "avatar_not_owned"
+)
+
+type ProfileHandler struct {
+	profileService ProfileService
+}
+
+func NewProfileHandler(profileService ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// GetProfile answers a handle the user has since given up, or one spelled
+// in another case, with a redirect to the current handle. The redirect is
+// a 302 because an old handle can go to someone else once its grace period
+// ends.
+func (p ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	handle := chi.URLParam(r, "handle")
+	res, err := p.profileService.GetProfile(r.Context(), services.GetProfileParams{
+		Handle:   handle,
+		ViewerID: viewerID(r),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	if res.Handle != handle {
+		location := "/users/" + url.PathEscape(res.Handle)
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, location, http.StatusFound)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p ProfileHandler) SetProfile(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type SetProfileReq struct {
+		Handle        string `json:"handle" validate:"required,max=30"`
+		Bio           string `json:"bio" validate:"max=300"`
+		Location      string `json:"location" validate:"max=100"`
+		Website       string `json:"website" validate:"max=200"`
+		AvatarMediaID int32  `json:"avatar_media_id" validate:"gte=0"`
+	}
+
+	reqBody := SetProfileReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	res, err := p.profileService.SetProfile(r.Context(), services.SetProfileParams{
+		UserID:        userID,
+		Handle:        reqBody.Handle,
+		Bio:           reqBody.Bio,
+		Location:      reqBody.Location,
+		Website:       reqBody.Website,
+		AvatarMediaID: reqBody.AvatarMediaID,
+	})
+	var cooldownErr *services.HandleCooldownError
+	if errors.As(err, &cooldownErr) {
+		retryAfter := int64(time.Until(cooldownErr.RetryAt).Seconds()) + 1
+		w.Header().Set("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
+		resp.SetTooManyRequests(cooldownErr.Error(), w)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidHandle) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "handle",
+			Code:    codeInvalidHandle,
+			Message: "handle must start with a letter and have 3 to 30 letters, digits or underscores",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidWebsite) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "website",
+			Code:    codeInvalidWebsite,
+			Message: "website must be an http or https URL",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrHandleTaken) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "handle",
+			Code:    codeHandleTaken,
+			Message: "handle is taken",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrAvatarNotOwned) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "avatar_media_id",
+			Code:    codeAvatarNotOwned,
+			Message: "avatar_media_id must be an image uploaded by the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	resp.SetOK(res, w)
+	return
+}
@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import "github.com/google/wire"
+
+func InitializedProfileHandler(ps ProfileService) (*ProfileHandler, error) {
+	wire.Build(NewProfileHandler)
+	return nil, nil
+}
@@ -0,0 +1,258 @@
+package resthttp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_NewProfileHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profileMock := NewMockProfileService(ctrl)
+
+	want := &ProfileHandler{
+		profileService: profileMock,
+	}
+	if got := NewProfileHandler(profileMock); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewProfileHandler() = %v, want %v", got, want)
+	}
+}
+
+func Test_GetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name         string
+		viewer       int32
+		handle       string
+		query        string
+		initMock     func(m *MockProfileService)
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name:   "test normal flow",
+			viewer: 2,
+			handle: "ana",
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().GetProfile(gomock.Any(), services.GetProfileParams{Handle: "ana", ViewerID: 2}).Return(services.ProfileRow{ID: 1, Handle: "ana"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test old handle redirects",
+			handle: "ana_old",
+			query:  "?tab=posts",
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(services.ProfileRow{ID: 1, Handle: "ana"}, nil)
+			},
+			wantStatus:   http.StatusFound,
+			wantLocation: "/users/ana?tab=posts",
+		},
+		{
+			name:   "test other case redirects",
+			handle: "Ana",
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(services.ProfileRow{ID: 1, Handle: "ana"}, nil)
+			},
+			wantStatus:   http.StatusFound,
+			wantLocation: "/users/ana",
+		},
+		{
+			name:   "test not found",
+			handle: "ana",
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(services.ProfileRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			handle: "ana",
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(services.ProfileRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockProfileService(ctrl)
+			tt.initMock(m)
+			h := ProfileHandler{profileService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/users/"+tt.handle+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("handle", tt.handle)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			if tt.viewer != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.viewer))
+			}
+
+			h.GetProfile(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetProfile() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("GetProfile() Location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func Test_SetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name           string
+		userID         int32
+		body           string
+		initMock       func(m *MockProfileService)
+		wantStatus     int
+		wantCode       string
+		wantRetryAfter bool
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			body:   `{"handle":"ana","bio":"hello","website":"https://ana.dev","avatar_media_id":4}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), services.SetProfileParams{
+					UserID:        1,
+					Handle:        "ana",
+					Bio:           "hello",
+					Website:       "https://ana.dev",
+					AvatarMediaID: 4,
+				}).Return(services.SetProfileRow{ID: 1, Handle: "ana", Version: 2}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test missing handle",
+			userID:     1,
+			body:       `{"bio":"hello"}`,
+			initMock:   func(m *MockProfileService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test bio too long",
+			userID:     1,
+			body:       `{"handle":"ana","bio":"` + strings.Repeat("x", 301) + `"}`,
+			initMock:   func(m *MockProfileService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test unauthenticated",
+			body:       `{"handle":"ana"}`,
+			initMock:   func(m *MockProfileService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test handle changed too recently",
+			userID: 1,
+			body:   `{"handle":"ana_b"}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, &services.HandleCooldownError{RetryAt: time.Now().Add(time.Hour)})
+			},
+			wantStatus:     http.StatusTooManyRequests,
+			wantRetryAfter: true,
+		},
+		{
+			name:   "test invalid handle",
+			userID: 1,
+			body:   `{"handle":"1ana"}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, services.ErrInvalidHandle)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   codeInvalidHandle,
+		},
+		{
+			name:   "test invalid website",
+			userID: 1,
+			body:   `{"handle":"ana","website":"ftp://ana.dev"}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, services.ErrInvalidWebsite)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   codeInvalidWebsite,
+		},
+		{
+			name:   "test handle taken",
+			userID: 1,
+			body:   `{"handle":"ana"}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, services.ErrHandleTaken)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   codeHandleTaken,
+		},
+		{
+			name:   "test avatar not owned",
+			userID: 1,
+			body:   `{"handle":"ana","avatar_media_id":9}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, services.ErrAvatarNotOwned)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   codeAvatarNotOwned,
+		},
+		{
+			name:   "test user not found",
+			userID: 1,
+			body:   `{"handle":"ana"}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			body:   `{"handle":"ana"}`,
+			initMock: func(m *MockProfileService) {
+				m.EXPECT().SetProfile(gomock.Any(), gomock.Any()).Return(services.SetProfileRow{}, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockProfileService(ctrl)
+			tt.initMock(m)
+			h := ProfileHandler{profileService: m}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "http://localhost:8000/user/profile", strings.NewReader(tt.body))
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.SetProfile(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("SetProfile() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), tt.wantCode) {
+				t.Errorf("SetProfile() body = %s, want code %s", w.Body, tt.wantCode)
+			}
+			if tt.wantRetryAfter {
+				secs, err := strconv.Atoi(w.Header().Get("Retry-After"))
+				if err != nil || secs < 1 || secs > 3601 {
+					t.Errorf("SetProfile() Retry-After = %q", w.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package resthttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/validation"
+)
+
+// authUserID returns the user authenticated by the Authenticate middleware.
+// Without one it writes a 401 itself and reports false.
+func authUserID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, ok := auth.UserID(r.Context())
+	if !ok {
+		resp := NewResponse()
+		resp.SetUnauthorized("", w)
+	}
+	return id, ok
+}
+
+// viewerID returns the user authenticated by OptionalAuthenticate, or 0 for
+// anonymous readers.
+func viewerID(r *http.Request) int32 {
+	id, _ := auth.UserID(r.Context())
+	return id
+}
+
+// decodeRequest reads the JSON body of r into dst, rejecting unknown fields,
+// and validates dst. On failure it writes the error response itself and
+// returns false.
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if err == nil && dec.More() {
+		err = errors.New("request body must contain a single JSON object")
+	}
+	if err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+
+	return validateRequest(w, dst)
+}
+
+// decodeMergePatch reads a JSON Merge Patch (RFC 7396) document from r into
+// dst, whose fields should be pointers or slices so that absent members stay
+// nil. It returns the set of members present in the document. A null member
+// clears slice fields and is rejected for any other field, as those map to
+// NOT NULL columns.
+func decodeMergePatch(w http.ResponseWriter, r *http.Request, dst interface{}) (map[string]bool, bool) {
+	resp := NewResponse()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeDecodeError(w, err)
+		return nil, false
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		resp.SetBadRequest("request body is empty", w)
+		return nil, false
+	}
+
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		resp.SetBadRequest("request body must be a JSON object", w)
+		return nil, false
+	}
+
+	fields := map[string]reflect.Kind{}
+	rt := reflect.Indirect(reflect.ValueOf(dst)).Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name := strings.Split(rt.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = rt.Field(i).Type.Kind()
+		}
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs validation.Errors
+	present := map[string]bool{}
+	for _, name := range names {
+		kind, ok := fields[name]
+		switch {
+		case !ok:
+			errs = append(errs, validation.FieldError{
+				Field:   name,
+				Code:    validation.CodeUnknownField,
+				Message: name + " is not a known field",
+			})
+		case string(bytes.TrimSpace(members[name])) == "null" && kind != reflect.Slice:
+			errs = append(errs, validation.FieldError{
+				Field:   name,
+				Code:    validation.CodeRequired,
+				Message: name + " cannot be null",
+			})
+		default:
+			present[name] = true
+		}
+	}
+	if len(errs) > 0 {
+		resp.SetUnprocessableEntity(errs, w)
+		return nil, false
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		writeDecodeError(w, err)
+		return nil, false
+	}
+
+	return present, validateRequest(w, dst)
+}
+
+func writeDecodeError(w http.ResponseWriter, err error) {
+	resp := NewResponse()
+
+	var maxBytesErr *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		resp.SetRequestEntityTooLarge(fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit), w)
+	case errors.As(err, &typeErr):
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   typeErr.Field,
+			Code:    validation.CodeInvalidType,
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, typeErr.Type),
+		}}, w)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   field,
+			Code:    validation.CodeUnknownField,
+			Message: field + " is not a known field",
+		}}, w)
+	case errors.Is(err, io.EOF):
+		resp.SetBadRequest("request body is empty", w)
+	default:
+		resp.SetBadRequest(err.Error(), w)
+	}
+}
+
+// decodeQuery fills dst, a pointer to a struct, from the URL query of r and
+// validates it. Query parameters are matched to fields by their JSON name.
+// Fields without a parameter keep their current value, so callers can set
+// defaults before decoding. Slice fields take a comma separated list and may
+// also be repeated.
+func decodeQuery(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	query := r.URL.Query()
+	rv := reflect.ValueOf(dst).Elem()
+	rt := rv.Type()
+
+	var errs validation.Errors
+	for i := 0; i < rt.NumField(); i++ {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("json"), ",")
+		raw := strings.Join(query[name], ",")
+		if name == "" || name == "-" || raw == "" {
+			continue
+		}
+		if err := setQueryField(rv.Field(i), raw); err != nil {
+			errs = append(errs, validation.FieldError{
+				Field:   name,
+				Code:    validation.CodeInvalidType,
+				Message: fmt.Sprintf("%s must be %s", name, queryTypeName(rv.Field(i))),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		resp := NewResponse()
+		resp.SetUnprocessableEntity(errs, w)
+		return false
+	}
+
+	return validateRequest(w, dst)
+}
+
+func setQueryField(f reflect.Value, raw string) error {
+	if _, ok := f.Interface().(time.Time); ok {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, raw)
+		}
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		s := reflect.MakeSlice(f.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setQueryField(s.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		f.Set(s)
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported query field type %s", f.Type())
+	}
+	return nil
+}
+
+func queryTypeName(f reflect.Value) string {
+	if _, ok := f.Interface().(time.Time); ok {
+		return "a date or an RFC 3339 timestamp"
+	}
+	return f.Type().String()
+}
+
+func validateRequest(w http.ResponseWriter, dst interface{}) bool {
+	resp := NewResponse()
+
+	if err := validation.Struct(dst); err != nil {
+		var errs validation.Errors
+		if errors.As(err, &errs) {
+			resp.SetUnprocessableEntity(errs, w)
+			return false
+		}
+		resp.SetBadRequest(err.Error(), w)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,297 @@
+package resthttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/validation"
+)
+
+func Test_decodeRequest(t *testing.T) {
+	type req struct {
+		Title  string  `json:"title" validate:"required,max=5"`
+		TagIDs []int32 `json:"tag_ids" validate:"max=2"`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantStatus int
+		wantErrors []validation.FieldError
+	}{
+		{
+			name:   "valid body",
+			body:   `{"title": "hello", "tag_ids": [1]}`,
+			wantOK: true,
+		},
+		{
+			name:       "all violations at once",
+			body:       `{"title": "too long title", "tag_ids": [1, 2, 3]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "title", Code: validation.CodeTooLong, Message: "title must be at most 5 long"},
+				{Field: "tag_ids", Code: validation.CodeTooLong, Message: "tag_ids must be at most 2 long"},
+			},
+		},
+		{
+			name:       "unknown field",
+			body:       `{"title": "hello", "titel": "x"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "titel", Code: validation.CodeUnknownField, Message: "titel is not a known field"},
+			},
+		},
+		{
+			name:       "wrong type",
+			body:       `{"title": 1}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "title", Code: validation.CodeInvalidType, Message: "title must be string"},
+			},
+		},
+		{
+			name:       "body too large",
+			body:       `{"title": "` + strings.Repeat("a", 100) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "empty body",
+			body:       ``,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed body",
+			body:       `{"title": `,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/post", strings.NewReader(tt.body))
+			r.Body = http.MaxBytesReader(w, r.Body, 64)
+
+			dst := req{}
+			ok := decodeRequest(w, r, &dst)
+			if ok != tt.wantOK {
+				t.Fatalf("decodeRequest() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				return
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("decodeRequest() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			got := baseResp{}
+			json.Unmarshal(w.Body.Bytes(), &got)
+			if !reflect.DeepEqual(got.Errors, tt.wantErrors) {
+				t.Errorf("decodeRequest() errors = %v, want %v", got.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func Test_decodeMergePatch(t *testing.T) {
+	type req struct {
+		Title  *string `json:"title" validate:"min=1,max=5"`
+		TagIDs []int32 `json:"tag_ids"`
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		wantOK      bool
+		wantPresent map[string]bool
+		wantTitle   string
+		wantTagIDs  []int32
+		wantStatus  int
+		wantErrors  []validation.FieldError
+	}{
+		{
+			name:        "absent members",
+			body:        `{"title": "hello"}`,
+			wantOK:      true,
+			wantPresent: map[string]bool{"title": true},
+			wantTitle:   "hello",
+		},
+		{
+			name:        "empty array clears tags",
+			body:        `{"tag_ids": []}`,
+			wantOK:      true,
+			wantPresent: map[string]bool{"tag_ids": true},
+			wantTagIDs:  []int32{},
+		},
+		{
+			name:        "null clears tags",
+			body:        `{"tag_ids": null}`,
+			wantOK:      true,
+			wantPresent: map[string]bool{"tag_ids": true},
+		},
+		{
+			name:        "empty document",
+			body:        `{}`,
+			wantOK:      true,
+			wantPresent: map[string]bool{},
+		},
+		{
+			name:       "null on not null field",
+			body:       `{"title": null}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "title", Code: validation.CodeRequired, Message: "title cannot be null"},
+			},
+		},
+		{
+			name:       "unknown field",
+			body:       `{"titel": "x"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "titel", Code: validation.CodeUnknownField, Message: "titel is not a known field"},
+			},
+		},
+		{
+			name:       "rule violation",
+			body:       `{"title": ""}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "title", Code: validation.CodeTooShort, Message: "title must be at least 1 long"},
+			},
+		},
+		{
+			name:       "wrong type",
+			body:       `{"tag_ids": "1"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []validation.FieldError{
+				{Field: "tag_ids", Code: validation.CodeInvalidType, Message: "tag_ids must be []int32"},
+			},
+		},
+		{
+			name:       "not an object",
+			body:       `[1]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "empty body",
+			body:       ``,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "body too large",
+			body:       `{"title": "` + strings.Repeat("a", 100) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", "http://localhost:8000/post?id=1", strings.NewReader(tt.body))
+			r.Body = http.MaxBytesReader(w, r.Body, 64)
+
+			dst := req{}
+			present, ok := decodeMergePatch(w, r, &dst)
+			if ok != tt.wantOK {
+				t.Fatalf("decodeMergePatch() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				if !reflect.DeepEqual(present, tt.wantPresent) {
+					t.Errorf("decodeMergePatch() present = %v, want %v", present, tt.wantPresent)
+				}
+				if tt.wantTitle != "" && (dst.Title == nil || *dst.Title != tt.wantTitle) {
+					t.Errorf("decodeMergePatch() title = %v, want %v", dst.Title, tt.wantTitle)
+				}
+				if !reflect.DeepEqual(dst.TagIDs, tt.wantTagIDs) {
+					t.Errorf("decodeMergePatch() tag_ids = %#v, want %#v", dst.TagIDs, tt.wantTagIDs)
+				}
+				return
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("decodeMergePatch() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			got := baseResp{}
+			json.Unmarshal(w.Body.Bytes(), &got)
+			if !reflect.DeepEqual(got.Errors, tt.wantErrors) {
+				t.Errorf("decodeMergePatch() errors = %v, want %v", got.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func Test_decodeQuery(t *testing.T) {
+	type req struct {
+		Query string    `json:"q" validate:"required"`
+		Limit int32     `json:"limit" validate:"gte=1,lte=100"`
+		From  time.Time `json:"from"`
+		Draft bool      `json:"draft"`
+		IDs   []int32   `json:"ids"`
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantOK     bool
+		want       req
+		wantErrors []validation.FieldError
+	}{
+		{
+			name:   "defaults kept",
+			url:    "/search?q=go",
+			wantOK: true,
+			want:   req{Query: "go", Limit: 20},
+		},
+		{
+			name:   "all fields",
+			url:    "/search?q=go&limit=5&from=2024-01-02T03:04:05Z&draft=true&ids=1,2&ids=3",
+			wantOK: true,
+			want:   req{Query: "go", Limit: 5, From: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Draft: true, IDs: []int32{1, 2, 3}},
+		},
+		{
+			name: "wrong types",
+			url:  "/search?q=go&limit=ten&from=soon&ids=1,a",
+			wantErrors: []validation.FieldError{
+				{Field: "limit", Code: validation.CodeInvalidType, Message: "limit must be int32"},
+				{Field: "from", Code: validation.CodeInvalidType, Message: "from must be a date or an RFC 3339 timestamp"},
+				{Field: "ids", Code: validation.CodeInvalidType, Message: "ids must be []int32"},
+			},
+		},
+		{
+			name: "rule violation",
+			url:  "/search?limit=0",
+			wantErrors: []validation.FieldError{
+				{Field: "q", Code: validation.CodeRequired, Message: "q is required"},
+				{Field: "limit", Code: validation.CodeOutOfRange, Message: "limit must be greater than or equal to 1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000"+tt.url, nil)
+
+			dst := req{Limit: 20}
+			ok := decodeQuery(w, r, &dst)
+			if ok != tt.wantOK {
+				t.Fatalf("decodeQuery() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				if !reflect.DeepEqual(dst, tt.want) {
+					t.Errorf("decodeQuery() = %v, want %v", dst, tt.want)
+				}
+				return
+			}
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("decodeQuery() status = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+			}
+			got := baseResp{}
+			json.Unmarshal(w.Body.Bytes(), &got)
+			if !reflect.DeepEqual(got.Errors, tt.wantErrors) {
+				t.Errorf("decodeQuery() errors = %v, want %v", got.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -1,50 +1,215 @@
 package resthttp
 
 import (
-	"log"
+	"log/slog"
+	"time"
 
+	"github.com/gadhittana01/socialmedia/auth"
 	"github.com/go-chi/chi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultMaxBodyBytes    = 1 << 20
+	defaultStreamHeartbeat = 15 * time.Second
+	defaultStreamTokenTTL  = 5 * time.Minute
 )
 
 type RouterDependencies struct {
 	PR PostService
+	UR UserService
+	TR TagService
+	TS TrendingService
+	BS BookmarkService
+	MS MediaService
+	PS ProfileService
+	NS NotificationService
+	MG MessageService
+	SS StreamService
+	TP trace.TracerProvider
+	IS IdempotencyService
+	// Auth verifies bearer tokens. When nil, endpoints that need a caller
+	// answer 401.
+	Auth *auth.Signer
+
+	MaxBodyBytes int64
+	// MaxUploadBytes is how much larger than MaxBodyBytes a media upload
+	// may be.
+	MaxUploadBytes int64
+	// StreamHeartbeat is how often an idle stream gets a comment line.
+	StreamHeartbeat time.Duration
+	// StreamTokenTTL is how long a stream token stays usable.
+	StreamTokenTTL time.Duration
 }
 
 func NewRoutes(rd RouterDependencies) *chi.Mux {
-	router := chi.NewRouter()
+	tp := rd.TP
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	maxBody := rd.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = defaultMaxBodyBytes
+	}
+
+	heartbeat := rd.StreamHeartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+
+	streamTokenTTL := rd.StreamTokenTTL
+	if streamTokenTTL <= 0 {
+		streamTokenTTL = defaultStreamTokenTTL
+	}
 
-	uh, err := InitializedUserHandler()
+	mux := chi.NewRouter()
+	mux.Use(RequestID, Tracing(tp), RequestLogger)
+	router := mux.With(MaxBodySize(maxBody))
+	authed := router.With(Authenticate(rd.Auth))
+	viewer := router.With(OptionalAuthenticate(rd.Auth))
+
+	create := chi.Chain()
+	if rd.IS != nil {
+		create = chi.Chain(Idempotency(rd.IS))
+	}
+
+	uh, err := InitializedUserHandler(rd.UR)
+	if err != nil {
+		slog.Error("init handler", "err", err)
+	}
+
+	th, err := InitializedTagHandler(rd.TR)
 	if err != nil {
-		log.Println(err)
+		slog.Error("init handler", "err", err)
 	}
 
-	th, err := InitializedTagHandler()
+	trh, err := InitializedTrendingHandler(rd.TS)
 	if err != nil {
-		log.Println(err)
+		slog.Error("init handler", "err", err)
 	}
 
 	ph, err := InitializedPostHandler(rd.PR)
 	if err != nil {
-		log.Println(err)
+		slog.Error("init handler", "err", err)
+	}
+
+	bh, err := InitializedBookmarkHandler(rd.BS)
+	if err != nil {
+		slog.Error("init handler", "err", err)
+	}
+
+	mh, err := InitializedMediaHandler(rd.MS)
+	if err != nil {
+		slog.Error("init handler", "err", err)
+	}
+
+	prh, err := InitializedProfileHandler(rd.PS)
+	if err != nil {
+		slog.Error("init handler", "err", err)
+	}
+
+	nh, err := InitializedNotificationHandler(rd.NS)
+	if err != nil {
+		slog.Error("init handler", "err", err)
+	}
+
+	mgh, err := InitializedMessageHandler(rd.MG)
+	if err != nil {
+		slog.Error("init handler", "err", err)
+	}
+
+	sh, err := InitializedStreamHandler(rd.SS, heartbeat, rd.Auth, StreamTokenTTL(streamTokenTTL))
+	if err != nil {
+		slog.Error("init handler", "err", err)
 	}
 
 	// user
 	router.Get("/users", uh.GetUsers)
-	router.Post("/user", uh.CreateUser)
+	router.Get("/user", uh.GetUser)
+	router.With(create...).Post("/user", uh.CreateUser)
 	router.Put("/user", uh.UpdateUser)
+	router.Patch("/user", uh.PatchUser)
 	router.Delete("/user", uh.DeleteUser)
+	router.Post("/user/follow", uh.FollowUser)
+	router.Delete("/user/follow", uh.UnfollowUser)
+	authed.Get("/user/blocks", uh.GetBlockedUsers)
+	authed.Post("/user/block", uh.BlockUser)
+	authed.Delete("/user/block", uh.UnblockUser)
+	authed.Get("/user/mutes", uh.GetMutedUsers)
+	authed.Post("/user/mute", uh.MuteUser)
+	authed.Delete("/user/mute", uh.UnmuteUser)
+	viewer.Get("/users/{handle}", prh.GetProfile)
+	authed.Put("/user/profile", prh.SetProfile)
 
 	// tag
 	router.Get("/tags", th.GetTags)
-	router.Post("/tag", th.CreateTag)
+	router.Get("/tags/trending", trh.GetTrendingTags)
+	router.Get("/tags/followed", th.GetFollowedTags)
+	router.Get("/tag", th.GetTag)
+	router.With(create...).Post("/tag", th.CreateTag)
 	router.Put("/tag", th.UpdateTag)
+	router.Patch("/tag", th.PatchTag)
 	router.Delete("/tag", th.DeleteTag)
+	router.Post("/tag/follow", th.FollowTag)
+	router.Delete("/tag/follow", th.UnfollowTag)
 
 	// post
-	router.Get("/posts", ph.GetPosts)
-	router.Post("/post", ph.CreatePost)
-	router.Put("/post", ph.UpdatePost)
+	viewer.Get("/posts", ph.GetPosts)
+	viewer.Get("/post", ph.GetPost)
+	authed.With(create...).Post("/post", ph.CreatePost)
+	authed.Put("/post", ph.UpdatePost)
+	authed.Patch("/post", ph.PatchPost)
 	router.Delete("/post", ph.DeletePost)
+	viewer.Get("/posts/{id}/thread", ph.GetThread)
+	viewer.Get("/posts/{id}/revisions", ph.GetPostRevisions)
+	viewer.Get("/posts/{id}/revisions/diff", ph.GetPostRevisionDiff)
+	authed.Post("/posts/{id}/revert", ph.RevertPost)
+	authed.Get("/feed/tags", ph.GetTagFeed)
+	router.Post("/repost", ph.Repost)
+	router.Delete("/repost", ph.Unrepost)
+	router.Post("/reaction", ph.React)
+	router.Delete("/reaction", ph.Unreact)
+
+	// bookmark
+	authed.Get("/bookmarks", ph.GetBookmarks)
+	authed.Post("/bookmark", bh.AddBookmark)
+	authed.Delete("/bookmark", bh.RemoveBookmark)
+	authed.Get("/collections", bh.GetCollections)
+	authed.With(create...).Post("/collection", bh.CreateCollection)
+	authed.Put("/collection", bh.RenameCollection)
+	authed.Delete("/collection", bh.DeleteCollection)
+
+	// media
+	mux.With(MaxBodySize(maxBody+rd.MaxUploadBytes)).Post("/media", mh.UploadMedia)
+	viewer.Get("/media/{id}", mh.GetMedia)
+	viewer.Get("/media/{id}/thumbnail", mh.GetMediaThumbnail)
+
+	// notification
+	authed.Get("/notifications", nh.GetNotifications)
+	authed.Post("/notifications/read", nh.MarkAllRead)
+	authed.Post("/notifications/{id}/read", nh.MarkRead)
+	authed.Get("/notifications/preferences", nh.GetPreferences)
+	authed.Put("/notifications/preferences", nh.SetPreferences)
+
+	// message
+	authed.Get("/conversations", mgh.GetConversations)
+	authed.Post("/conversations", mgh.StartConversation)
+	authed.Get("/conversations/{id}", mgh.GetConversation)
+	authed.Get("/conversations/{id}/messages", mgh.GetMessages)
+	authed.Get("/conversations/{id}/messages/{message_id}", mgh.GetMessage)
+	authed.Post("/conversations/{id}/messages", mgh.SendMessage)
+	authed.Post("/conversations/{id}/read", mgh.MarkRead)
+
+	// stream
+	router.Get("/stream", sh.Stream)
+	authed.Post("/stream/token", sh.IssueToken)
+
+	// search
+	viewer.Get("/search/posts", ph.SearchPosts)
+	router.Get("/search/users", uh.SearchUsers)
+	router.Get("/search/tags", th.SearchTags)
 
-	return router
+	return mux
 }
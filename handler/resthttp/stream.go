@@ -0,0 +1,165 @@
+package resthttp
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+)
+
+// streamWriteTimeout is how long one write to a stream may block before the
+// client is given up on.
+const streamWriteTimeout = 10 * time.Second
+
+// StreamTokenTTL is how long a token from POST /stream/token can be used to
+// open a stream.
+type StreamTokenTTL time.Duration
+
+type StreamHandler struct {
+	streamService StreamService
+	heartbeat     time.Duration
+	signer        *auth.Signer
+	tokenTTL      StreamTokenTTL
+}
+
+func NewStreamHandler(streamService StreamService, heartbeat time.Duration, signer *auth.Signer, tokenTTL StreamTokenTTL) *StreamHandler {
+	return &StreamHandler{
+		streamService: streamService,
+		heartbeat:     heartbeat,
+		signer:        signer,
+		tokenTTL:      tokenTTL,
+	}
+}
+
+// IssueToken returns a short-lived token that opens the authenticated
+// user's stream. EventSource cannot send headers, so the stream takes it in
+// the query string instead of the bearer token.
+func (p StreamHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := auth.UserID(r.Context())
+	if !ok || p.signer == nil {
+		resp.SetUnauthorized("", w)
+		return
+	}
+
+	ttl := time.Duration(p.tokenTTL)
+	resp.SetOK(map[string]interface{}{
+		"token":      p.signer.Sign(userID, auth.ScopeStream, ttl),
+		"expires_in": int64(ttl / time.Second),
+	}, w)
+	return
+}
+
+// Stream sends the events of the user named by the stream token as
+// Server-Sent Events until the client leaves. A Last-Event-ID header, or the last_event_id parameter for the
+// first connection, replays what the client missed. Clients dropped for
+// falling behind are expected to reconnect the same way.
+func (p StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type StreamReq struct {
+		Token       string `json:"token" validate:"required"`
+		LastEventID int64  `json:"last_event_id" validate:"gte=0"`
+	}
+
+	req := StreamReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+	if p.signer == nil {
+		resp.SetUnauthorized("", w)
+		return
+	}
+	claims, err := p.signer.Verify(req.Token, auth.ScopeStream)
+	if err != nil {
+		resp.SetUnauthorized(err.Error(), w)
+		return
+	}
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			resp.SetBadRequest("Invalid Last-Event-ID", w)
+			return
+		}
+		req.LastEventID = id
+	}
+
+	sub, err := p.streamService.Subscribe(r.Context(), services.SubscribeParams{
+		UserID:      claims.UserID,
+		LastEventID: req.LastEventID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+	defer p.streamService.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	send := func(format string, args ...interface{}) bool {
+		err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+	sendEvent := func(e services.StreamEvent) bool {
+		return send("id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Kind, e.Data)
+	}
+
+	// An empty id makes the client forget its last event id, so it does
+	// not ask for the same replay again after reloading.
+	if sub.Missed && !send("id\nevent: %s\ndata: {}\n\n", services.StreamEventReset) {
+		return
+	}
+	var last int64
+	for _, e := range sub.Backlog {
+		if !sendEvent(e) {
+			return
+		}
+		last = e.ID
+	}
+	if !send(": connected\n\n") {
+		return
+	}
+
+	heartbeat := time.NewTicker(p.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if !send(": ping\n\n") {
+				return
+			}
+		case e, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if e.ID <= last {
+				continue
+			}
+			if !sendEvent(e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import (
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/google/wire"
+)
+
+func InitializedStreamHandler(ss StreamService, heartbeat time.Duration, signer *auth.Signer, tokenTTL StreamTokenTTL) (*StreamHandler, error) {
+	wire.Build(NewStreamHandler)
+	return nil, nil
+}
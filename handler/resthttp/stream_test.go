@@ -0,0 +1,258 @@
+package resthttp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/auth"
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/golang/mock/gomock"
+)
+
+func newTestSigner(t *testing.T) *auth.Signer {
+	t.Helper()
+	signer, err := auth.NewSigner([]byte(strings.Repeat("s", auth.MinSecretLen)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func Test_NewStreamHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	streamMock := NewMockStreamService(ctrl)
+	signer := newTestSigner(t)
+
+	want := &StreamHandler{
+		streamService: streamMock,
+		heartbeat:     time.Second,
+		signer:        signer,
+		tokenTTL:      StreamTokenTTL(time.Minute),
+	}
+	if got := NewStreamHandler(streamMock, time.Second, signer, StreamTokenTTL(time.Minute)); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewStreamHandler() = %v, want %v", got, want)
+	}
+}
+
+func Test_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	signer := newTestSigner(t)
+	token := "?token=" + signer.Sign(1, auth.ScopeStream, time.Minute)
+	post := services.StreamEvent{ID: 5, Kind: services.StreamEventPost, Data: json.RawMessage(`{"id":7}`)}
+	reaction := services.StreamEvent{ID: 6, Kind: services.StreamEventReactionCount, Data: json.RawMessage(`{"post_id":7,"reaction_count":1}`)}
+	// live returns a subscription whose live events are already waiting and
+	// which then ends, as when it is dropped.
+	live := func(backlog []services.StreamEvent, missed bool, events ...services.StreamEvent) *services.Subscription {
+		ch := make(chan services.StreamEvent, len(events))
+		for _, e := range events {
+			ch <- e
+		}
+		close(ch)
+		return &services.Subscription{UserID: 1, Backlog: backlog, Missed: missed, Events: ch}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		lastID     string
+		initMock   func(m *MockStreamService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:  "test normal flow",
+			query: token,
+			initMock: func(m *MockStreamService) {
+				sub := live(nil, false, post, reaction)
+				m.EXPECT().Subscribe(gomock.Any(), services.SubscribeParams{UserID: 1}).Return(sub, nil)
+				m.EXPECT().Unsubscribe(sub)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: ": connected\n\n" +
+				"id: 5\nevent: post\ndata: {\"id\":7}\n\n" +
+				"id: 6\nevent: reaction_count\ndata: {\"post_id\":7,\"reaction_count\":1}\n\n",
+		},
+		{
+			name:   "test resume skips repeated live events",
+			query:  token + "&last_event_id=2",
+			lastID: "4",
+			initMock: func(m *MockStreamService) {
+				sub := live([]services.StreamEvent{post}, false, post, reaction)
+				m.EXPECT().Subscribe(gomock.Any(), services.SubscribeParams{UserID: 1, LastEventID: 4}).Return(sub, nil)
+				m.EXPECT().Unsubscribe(sub)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: "id: 5\nevent: post\ndata: {\"id\":7}\n\n" +
+				": connected\n\n" +
+				"id: 6\nevent: reaction_count\ndata: {\"post_id\":7,\"reaction_count\":1}\n\n",
+		},
+		{
+			name:   "test resume after too many events",
+			query:  token,
+			lastID: "4",
+			initMock: func(m *MockStreamService) {
+				sub := live(nil, true)
+				m.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(sub, nil)
+				m.EXPECT().Unsubscribe(sub)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "id\nevent: reset\ndata: {}\n\n: connected\n\n",
+		},
+		{
+			name:       "test missing token",
+			initMock:   func(m *MockStreamService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "test api token",
+			query:      "?token=" + signer.Sign(1, auth.ScopeAPI, time.Minute),
+			initMock:   func(m *MockStreamService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "test expired token",
+			query:      "?token=" + signer.Sign(1, auth.ScopeStream, -time.Second),
+			initMock:   func(m *MockStreamService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "test invalid last event id",
+			query:      token,
+			lastID:     "abc",
+			initMock:   func(m *MockStreamService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "test user not found",
+			query: token,
+			initMock: func(m *MockStreamService) {
+				m.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:  "test internal server error",
+			query: token,
+			initMock: func(m *MockStreamService) {
+				m.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockStreamService(ctrl)
+			tt.initMock(m)
+			h := StreamHandler{streamService: m, heartbeat: time.Hour, signer: signer}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:8000/stream"+tt.query, nil)
+			if tt.lastID != "" {
+				r.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			h.Stream(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Stream() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("Stream() Content-Type = %q, want text/event-stream", got)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("Stream() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_StreamHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := NewMockStreamService(ctrl)
+	sub := &services.Subscription{UserID: 1, Events: make(chan services.StreamEvent)}
+	m.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(sub, nil)
+	m.EXPECT().Unsubscribe(sub)
+	signer := newTestSigner(t)
+	h := StreamHandler{streamService: m, heartbeat: time.Millisecond, signer: signer}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://localhost:8000/stream?token="+signer.Sign(1, auth.ScopeStream, time.Minute), nil).WithContext(ctx)
+	h.Stream(w, r)
+	if !strings.Contains(w.Body.String(), ": ping\n\n") {
+		t.Errorf("Stream() body = %q, want heartbeats", w.Body)
+	}
+}
+
+func Test_IssueToken(t *testing.T) {
+	signer := newTestSigner(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		signer     *auth.Signer
+		wantStatus int
+	}{
+		{
+			name:       "test normal flow",
+			userID:     1,
+			signer:     signer,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "test unauthenticated",
+			signer:     signer,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "test no signer",
+			userID:     1,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := StreamHandler{signer: tt.signer, tokenTTL: StreamTokenTTL(time.Minute)}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/stream/token", nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			h.IssueToken(w, r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("IssueToken() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data struct {
+					Token     string `json:"token"`
+					ExpiresIn int64  `json:"expires_in"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Data.ExpiresIn != 60 {
+				t.Errorf("IssueToken() expires_in = %d, want 60", body.Data.ExpiresIn)
+			}
+			claims, err := signer.Verify(body.Data.Token, auth.ScopeStream)
+			if err != nil || claims.UserID != tt.userID {
+				t.Errorf("IssueToken() token claims = %v, %v, want user %d", claims, err, tt.userID)
+			}
+		})
+	}
+}
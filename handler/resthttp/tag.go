@@ -1,9 +1,8 @@
 package resthttp
 
 import (
-	"context"
-	"encoding/json"
-	"io/ioutil"
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -23,7 +22,7 @@ func NewTagHandler(tagService TagService) *TagHandler {
 func (p TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	res, err := p.tagService.GetTags(context.Background())
+	res, err := p.tagService.GetTags(r.Context())
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -33,32 +32,45 @@ func (p TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+func (p TagHandler) SearchTags(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
-		return
+	type SearchTagsReq struct {
+		Query string `json:"q" validate:"required,max=100"`
+		Limit int32  `json:"limit" validate:"gte=1,lte=20"`
 	}
 
-	type CreateUserReq struct {
-		Tagname string `json:"tagname"`
+	req := SearchTagsReq{Limit: 10}
+	if !decodeQuery(w, r, &req) {
+		return
 	}
 
-	reqBody := CreateUserReq{}
-	err = json.Unmarshal(body, &reqBody)
+	res, err := p.tagService.SearchTags(r.Context(), services.SearchTagsParams{
+		Query: req.Query,
+		Limit: req.Limit,
+	})
 	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
-	if reqBody.Tagname == "" {
-		resp.SetBadRequest("Tagname cannot be null", w)
+	resp.SetOK(res, w)
+	return
+}
+
+func (p TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type CreateTagReq struct {
+		Tagname string `json:"tagname" validate:"required,max=50"`
+	}
+
+	reqBody := CreateTagReq{}
+	if !decodeRequest(w, r, &reqBody) {
 		return
 	}
 
-	res, err := p.tagService.CreateTag(context.Background(), reqBody.Tagname)
+	res, err := p.tagService.CreateTag(r.Context(), reqBody.Tagname)
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -68,23 +80,50 @@ func (p TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+func (p TagHandler) GetTag(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	body, err := ioutil.ReadAll(r.Body)
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	tid, err := strconv.Atoi(id)
 	if err != nil {
 		resp.SetBadRequest(err.Error(), w)
 		return
 	}
 
+	res, err := p.tagService.GetTag(r.Context(), int32(tid))
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("tag not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	if notModified(r, res.Version) {
+		resp.SetNotModified(w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
 	type UpdateTagReq struct {
-		Tagname string `json:"tagname"`
+		Tagname string `json:"tagname" validate:"required,max=50"`
 	}
 
 	reqBody := UpdateTagReq{}
-	err = json.Unmarshal(body, &reqBody)
-	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+	if !decodeRequest(w, r, &reqBody) {
 		return
 	}
 
@@ -100,20 +139,31 @@ func (p TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if reqBody.Tagname == "" {
-		resp.SetBadRequest("Tagname cannot be null", w)
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
 		return
 	}
 
-	res, err := p.tagService.UpdateTag(context.Background(), services.UpdateTagParams{
+	res, err := p.tagService.UpdateTag(r.Context(), services.UpdateTagParams{
 		ID:      int32(tid),
 		Tagname: reqBody.Tagname,
+		Version: version,
 	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("tag has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("tag not found", w)
+		return
+	}
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
+	w.Header().Set("ETag", etag(res.Version))
 	resp.SetCreated(res, w)
 	return
 }
@@ -133,7 +183,24 @@ func (p TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = p.tagService.DeleteTag(context.Background(), int32(tid))
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	err = p.tagService.DeleteTag(r.Context(), services.DeleteTagParams{
+		ID:      int32(tid),
+		Version: version,
+	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("tag has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("tag not found", w)
+		return
+	}
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -144,3 +211,139 @@ func (p TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
 	}, w)
 	return
 }
+
+func (p TagHandler) PatchTag(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type PatchTagReq struct {
+		Tagname *string `json:"tagname" validate:"min=1,max=50"`
+	}
+
+	reqBody := PatchTagReq{}
+	_, ok := decodeMergePatch(w, r, &reqBody)
+	if !ok {
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	tid, err := strconv.Atoi(id)
+	if err != nil {
+		resp.SetBadRequest(err.Error(), w)
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	res, err := p.tagService.PatchTag(r.Context(), services.PatchTagParams{
+		ID:      int32(tid),
+		Tagname: reqBody.Tagname,
+		Version: version,
+	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("tag has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("tag not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	resp.SetOK(res, w)
+	return
+}
+
+func (p TagHandler) FollowTag(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type FollowTagReq struct {
+		UserID int32 `json:"user_id" validate:"required,gte=1"`
+		TagID  int32 `json:"tag_id" validate:"required,gte=1"`
+	}
+
+	reqBody := FollowTagReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	err := p.tagService.FollowTag(r.Context(), services.FollowTagParams{
+		UserID: reqBody.UserID,
+		TagID:  reqBody.TagID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user or tag not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p TagHandler) UnfollowTag(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type UnfollowTagReq struct {
+		UserID int32 `json:"user_id" validate:"required,gte=1"`
+		TagID  int32 `json:"tag_id" validate:"required,gte=1"`
+	}
+
+	req := UnfollowTagReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.tagService.UnfollowTag(r.Context(), services.UnfollowTagParams{
+		UserID: req.UserID,
+		TagID:  req.TagID,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p TagHandler) GetFollowedTags(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type GetFollowedTagsReq struct {
+		UserID int32 `json:"user_id" validate:"required,gte=1"`
+	}
+
+	req := GetFollowedTagsReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	res, err := p.tagService.GetFollowedTags(r.Context(), req.UserID)
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
@@ -3,31 +3,9 @@
 
 package resthttp
 
-import (
-	"database/sql"
+import "github.com/google/wire"
 
-	"github.com/gadhittana01/socialmedia/db"
-	"github.com/gadhittana01/socialmedia/pkg/tag"
-	"github.com/gadhittana01/socialmedia/services"
-	"github.com/google/wire"
-)
-
-var dbTagSet = wire.NewSet(
-	db.InitDB,
-	wire.Bind(new(tag.DBTX), new(*sql.DB)),
-)
-
-var tagPkgSet = wire.NewSet(
-	tag.New,
-	wire.Bind(new(services.TagResource), new(*tag.Queries)),
-)
-
-var tagService = wire.NewSet(
-	services.NewTagService,
-	wire.Bind(new(TagService), new(services.TagService)),
-)
-
-func InitializedTagHandler() (*TagHandler, error) {
-	wire.Build(dbTagSet, tagPkgSet, tagService, NewTagHandler)
+func InitializedTagHandler(ts TagService) (*TagHandler, error) {
+	wire.Build(NewTagHandler)
 	return nil, nil
 }
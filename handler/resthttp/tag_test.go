@@ -1,6 +1,7 @@
 package resthttp
 
 import (
+	"database/sql"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -110,6 +111,94 @@ func Test_GetTags(t *testing.T) {
 	}
 }
 
+func Test_SearchTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() TagHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/search/tags?q=gi&limit=5",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().SearchTags(gomock.Any(), services.SearchTagsParams{
+					Query: "gi",
+					Limit: 5,
+				}).Return([]services.SearchTagsRow{{ID: 1}}, nil)
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test default limit",
+			url:  "http://localhost:8000/search/tags?q=gi",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().SearchTags(gomock.Any(), services.SearchTagsParams{
+					Query: "gi",
+					Limit: 10,
+				}).Return([]services.SearchTagsRow{}, nil)
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing query",
+			url:  "http://localhost:8000/search/tags",
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test limit above maximum",
+			url:  "http://localhost:8000/search/tags?q=gi&limit=21",
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/search/tags?q=gi",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().SearchTags(gomock.Any(), gomock.Any()).Return([]services.SearchTagsRow{}, errors.New("error"))
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			field := tt.fields()
+			field.SearchTags(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("SearchTags() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func Test_CreateTag(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/tag", strings.NewReader(`{
@@ -393,7 +482,9 @@ func Test_DeleteTag(t *testing.T) {
 			fields: func() TagHandler {
 				tagMock := NewMockTagService(ctrl)
 
-				tagMock.EXPECT().DeleteTag(gomock.Any(), int32(1)).Return(nil)
+				tagMock.EXPECT().DeleteTag(gomock.Any(), services.DeleteTagParams{
+					ID: 1,
+				}).Return(nil)
 
 				return TagHandler{
 					tagService: tagMock,
@@ -409,7 +500,9 @@ func Test_DeleteTag(t *testing.T) {
 			fields: func() TagHandler {
 				tagMock := NewMockTagService(ctrl)
 
-				tagMock.EXPECT().DeleteTag(gomock.Any(), int32(1)).Return(errors.New("error"))
+				tagMock.EXPECT().DeleteTag(gomock.Any(), services.DeleteTagParams{
+					ID: 1,
+				}).Return(errors.New("error"))
 
 				return TagHandler{
 					tagService: tagMock,
@@ -456,3 +549,417 @@ func Test_DeleteTag(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tagname := "new tagname"
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		fields     func() TagHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/tag?id=1",
+			body: `{"tagname": "new tagname"}`,
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().PatchTag(gomock.Any(), services.PatchTagParams{
+					ID:      1,
+					Tagname: &tagname,
+				}).Return(services.PatchTagRow{
+					ID:      1,
+					Tagname: "new tagname",
+				}, nil)
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test null tagname",
+			url:  "http://localhost:8000/tag?id=1",
+			body: `{"tagname": null}`,
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test error parsed id",
+			url:  "http://localhost:8000/tag?id='error'",
+			body: `{"tagname": "new tagname"}`,
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "test tag not found",
+			url:  "http://localhost:8000/tag?id=1",
+			body: `{"tagname": "new tagname"}`,
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().PatchTag(gomock.Any(), gomock.Any()).Return(services.PatchTagRow{}, sql.ErrNoRows)
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/tag?id=1",
+			body: `{"tagname": "new tagname"}`,
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().PatchTag(gomock.Any(), gomock.Any()).Return(services.PatchTagRow{}, errors.New("error"))
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", tt.url, strings.NewReader(tt.body))
+			field := tt.fields()
+			field.PatchTag(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("PatchTag() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name        string
+		url         string
+		ifNoneMatch string
+		fields      func() TagHandler
+		wantStatus  int
+		wantETag    string
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/tag?id=1",
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(services.GetTagRow{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 3,
+				}, nil)
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:        "test not modified",
+			url:         "http://localhost:8000/tag?id=1",
+			ifNoneMatch: `"3"`,
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(services.GetTagRow{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 3,
+				}, nil)
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusNotModified,
+			wantETag:   `"3"`,
+		},
+		{
+			name:        "test stale if none match",
+			url:         "http://localhost:8000/tag?id=1",
+			ifNoneMatch: `"2"`,
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(services.GetTagRow{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 3,
+				}, nil)
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name: "test id not provided",
+			url:  "http://localhost:8000/tag",
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "test tag not found",
+			url:  "http://localhost:8000/tag?id=1",
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(services.GetTagRow{}, sql.ErrNoRows)
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/tag?id=1",
+			fields: func() TagHandler {
+				tagMock := NewMockTagService(ctrl)
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(services.GetTagRow{}, errors.New("error"))
+
+				return TagHandler{
+					tagService: tagMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			field := tt.fields()
+			field.GetTag(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetTag() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("GetTag() ETag = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func Test_FollowTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		fields     func() TagHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			body: `{"user_id": 1, "tag_id": 2}`,
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().FollowTag(gomock.Any(), services.FollowTagParams{
+					UserID: 1,
+					TagID:  2,
+				}).Return(nil)
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing tag id",
+			body: `{"user_id": 1}`,
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test user or tag not found",
+			body: `{"user_id": 1, "tag_id": 2}`,
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().FollowTag(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			body: `{"user_id": 1, "tag_id": 2}`,
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().FollowTag(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/tag/follow", strings.NewReader(tt.body))
+			field := tt.fields()
+			field.FollowTag(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("FollowTag() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_UnfollowTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() TagHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/tag/follow?user_id=1&tag_id=2",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().UnfollowTag(gomock.Any(), services.UnfollowTagParams{
+					UserID: 1,
+					TagID:  2,
+				}).Return(nil)
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing user id",
+			url:  "http://localhost:8000/tag/follow?tag_id=2",
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/tag/follow?user_id=1&tag_id=2",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().UnfollowTag(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", tt.url, nil)
+			field := tt.fields()
+			field.UnfollowTag(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("UnfollowTag() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetFollowedTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() TagHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/tags/followed?user_id=1",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().GetFollowedTags(gomock.Any(), int32(1)).Return([]services.GetTagsRow{{ID: 2}}, nil)
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing user id",
+			url:  "http://localhost:8000/tags/followed",
+			fields: func() TagHandler {
+				return TagHandler{
+					tagService: NewMockTagService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/tags/followed?user_id=1",
+			fields: func() TagHandler {
+				m := NewMockTagService(ctrl)
+				m.EXPECT().GetFollowedTags(gomock.Any(), gomock.Any()).Return([]services.GetTagsRow{}, errors.New("error"))
+
+				return TagHandler{
+					tagService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			field := tt.fields()
+			field.GetFollowedTags(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetFollowedTags() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package resthttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/services"
+)
+
+type TrendingHandler struct {
+	trendingService TrendingService
+}
+
+func NewTrendingHandler(trendingService TrendingService) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService: trendingService,
+	}
+}
+
+func (p TrendingHandler) GetTrendingTags(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type GetTrendingTagsReq struct {
+		Window string `json:"window" validate:"oneof=1h 6h 24h"`
+		Limit  int32  `json:"limit" validate:"gte=1,lte=50"`
+	}
+
+	req := GetTrendingTagsReq{Window: "1h", Limit: 10}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	window, err := time.ParseDuration(req.Window)
+	if err != nil {
+		resp.SetBadRequest(err.Error(), w)
+		return
+	}
+
+	res, err := p.trendingService.GetTrendingTags(r.Context(), services.GetTrendingTagsParams{
+		Window: window,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package resthttp
+
+import "github.com/google/wire"
+
+func InitializedTrendingHandler(ts TrendingService) (*TrendingHandler, error) {
+	wire.Build(NewTrendingHandler)
+	return nil, nil
+}
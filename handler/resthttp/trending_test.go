@@ -0,0 +1,100 @@
+package resthttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/services"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_GetTrendingTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() TrendingHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/tags/trending?window=24h&limit=5",
+			fields: func() TrendingHandler {
+				m := NewMockTrendingService(ctrl)
+				m.EXPECT().GetTrendingTags(gomock.Any(), services.GetTrendingTagsParams{
+					Window: 24 * time.Hour,
+					Limit:  5,
+				}).Return([]services.GetTrendingTagsRow{{ID: 1}}, nil)
+
+				return TrendingHandler{
+					trendingService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test default window and limit",
+			url:  "http://localhost:8000/tags/trending",
+			fields: func() TrendingHandler {
+				m := NewMockTrendingService(ctrl)
+				m.EXPECT().GetTrendingTags(gomock.Any(), services.GetTrendingTagsParams{
+					Window: time.Hour,
+					Limit:  10,
+				}).Return([]services.GetTrendingTagsRow{}, nil)
+
+				return TrendingHandler{
+					trendingService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unsupported window",
+			url:  "http://localhost:8000/tags/trending?window=2h",
+			fields: func() TrendingHandler {
+				return TrendingHandler{
+					trendingService: NewMockTrendingService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test limit above maximum",
+			url:  "http://localhost:8000/tags/trending?limit=51",
+			fields: func() TrendingHandler {
+				return TrendingHandler{
+					trendingService: NewMockTrendingService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/tags/trending",
+			fields: func() TrendingHandler {
+				m := NewMockTrendingService(ctrl)
+				m.EXPECT().GetTrendingTags(gomock.Any(), gomock.Any()).Return([]services.GetTrendingTagsRow{}, errors.New("error"))
+
+				return TrendingHandler{
+					trendingService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			field := tt.fields()
+			field.GetTrendingTags(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetTrendingTags() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
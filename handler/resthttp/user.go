@@ -1,13 +1,20 @@
 package resthttp
 
 import (
-	"context"
-	"encoding/json"
-	"io/ioutil"
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gadhittana01/socialmedia/services"
+	"github.com/gadhittana01/socialmedia/validation"
+)
+
+const (
+	codeSelfFollow = "self_follow"
+	codeSelfBlock  = "self_block"
+	codeSelfMute   = "self_mute"
+	codeBlocked    = "blocked"
 )
 
 type UserHandler struct {
@@ -23,7 +30,7 @@ func NewUserHandler(userService UserService) *UserHandler {
 func (p UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	res, err := p.userService.GetUsers(context.Background())
+	res, err := p.userService.GetUsers(r.Context())
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -33,32 +40,45 @@ func (p UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+func (p UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
-		return
+	type SearchUsersReq struct {
+		Query string `json:"q" validate:"required,max=100"`
+		Limit int32  `json:"limit" validate:"gte=1,lte=20"`
 	}
 
-	type CreateUserReq struct {
-		Fullname string `json:"fullname"`
+	req := SearchUsersReq{Limit: 10}
+	if !decodeQuery(w, r, &req) {
+		return
 	}
 
-	reqBody := CreateUserReq{}
-	err = json.Unmarshal(body, &reqBody)
+	res, err := p.userService.SearchUsers(r.Context(), services.SearchUsersParams{
+		Query: req.Query,
+		Limit: req.Limit,
+	})
 	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
-	if reqBody.Fullname == "" {
-		resp.SetBadRequest("fullname cannot be null", w)
+	resp.SetOK(res, w)
+	return
+}
+
+func (p UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type CreateUserReq struct {
+		Fullname string `json:"fullname" validate:"required,max=100"`
+	}
+
+	reqBody := CreateUserReq{}
+	if !decodeRequest(w, r, &reqBody) {
 		return
 	}
 
-	res, err := p.userService.CreateUser(context.Background(), reqBody.Fullname)
+	res, err := p.userService.CreateUser(r.Context(), reqBody.Fullname)
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -68,23 +88,50 @@ func (p UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+func (p UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	resp := NewResponse()
 
-	body, err := ioutil.ReadAll(r.Body)
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	uid, err := strconv.Atoi(id)
 	if err != nil {
 		resp.SetBadRequest(err.Error(), w)
 		return
 	}
 
+	res, err := p.userService.GetUser(r.Context(), int32(uid))
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	if notModified(r, res.Version) {
+		resp.SetNotModified(w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
 	type UpdateUserReq struct {
-		Fullname string `json:"fullname"`
+		Fullname string `json:"fullname" validate:"required,max=100"`
 	}
 
 	reqBody := UpdateUserReq{}
-	err = json.Unmarshal(body, &reqBody)
-	if err != nil {
-		resp.SetBadRequest(err.Error(), w)
+	if !decodeRequest(w, r, &reqBody) {
 		return
 	}
 
@@ -100,20 +147,31 @@ func (p UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if reqBody.Fullname == "" {
-		resp.SetBadRequest("fullname cannot be null", w)
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
 		return
 	}
 
-	res, err := p.userService.UpdateUser(context.Background(), services.UpdateUserParams{
+	res, err := p.userService.UpdateUser(r.Context(), services.UpdateUserParams{
 		ID:       int32(uid),
 		Fullname: reqBody.Fullname,
+		Version:  version,
 	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("user has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
 	}
 
+	w.Header().Set("ETag", etag(res.Version))
 	resp.SetOK(res, w)
 	return
 }
@@ -133,7 +191,292 @@ func (p UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = p.userService.DeleteUser(context.Background(), int32(uid))
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	err = p.userService.DeleteUser(r.Context(), services.DeleteUserParams{
+		ID:      int32(uid),
+		Version: version,
+	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("user has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type PatchUserReq struct {
+		Fullname *string `json:"fullname" validate:"min=1,max=100"`
+	}
+
+	reqBody := PatchUserReq{}
+	_, ok := decodeMergePatch(w, r, &reqBody)
+	if !ok {
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		resp.SetBadRequest("Invalid Request Parameter", w)
+		return
+	}
+
+	uid, err := strconv.Atoi(id)
+	if err != nil {
+		resp.SetBadRequest(err.Error(), w)
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		resp.SetPreconditionFailed("If-Match does not name a current version", w)
+		return
+	}
+
+	res, err := p.userService.PatchUser(r.Context(), services.PatchUserParams{
+		ID:       int32(uid),
+		Fullname: reqBody.Fullname,
+		Version:  version,
+	})
+	if errors.Is(err, services.ErrVersionConflict) {
+		resp.SetPreconditionFailed("user has been modified", w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	w.Header().Set("ETag", etag(res.Version))
+	resp.SetOK(res, w)
+	return
+}
+
+func (p UserHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type FollowUserReq struct {
+		FollowerID int32 `json:"follower_id" validate:"required,gte=1"`
+		FolloweeID int32 `json:"followee_id" validate:"required,gte=1"`
+	}
+
+	reqBody := FollowUserReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	err := p.userService.FollowUser(r.Context(), services.FollowUserParams{
+		FollowerID: reqBody.FollowerID,
+		FolloweeID: reqBody.FolloweeID,
+	})
+	if errors.Is(err, services.ErrSelfFollow) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "followee_id",
+			Code:    codeSelfFollow,
+			Message: "followee_id must not be follower_id",
+		}}, w)
+		return
+	}
+	if errors.Is(err, services.ErrBlocked) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "followee_id",
+			Code:    codeBlocked,
+			Message: "followee_id must not block or be blocked by follower_id",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p UserHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	type UnfollowUserReq struct {
+		FollowerID int32 `json:"follower_id" validate:"required,gte=1"`
+		FolloweeID int32 `json:"followee_id" validate:"required,gte=1"`
+	}
+
+	req := UnfollowUserReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.userService.UnfollowUser(r.Context(), services.UnfollowUserParams{
+		FollowerID: req.FollowerID,
+		FolloweeID: req.FolloweeID,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p UserHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type BlockUserReq struct {
+		BlockedID int32 `json:"blocked_id" validate:"required,gte=1"`
+	}
+
+	reqBody := BlockUserReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	err := p.userService.BlockUser(r.Context(), services.BlockUserParams{
+		BlockerID: userID,
+		BlockedID: reqBody.BlockedID,
+	})
+	if errors.Is(err, services.ErrSelfBlock) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "blocked_id",
+			Code:    codeSelfBlock,
+			Message: "blocked_id must not be the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p UserHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type UnblockUserReq struct {
+		BlockedID int32 `json:"blocked_id" validate:"required,gte=1"`
+	}
+
+	req := UnblockUserReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.userService.UnblockUser(r.Context(), services.UnblockUserParams{
+		BlockerID: userID,
+		BlockedID: req.BlockedID,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p UserHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := p.userService.GetBlockedUsers(r.Context(), userID)
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
+
+func (p UserHandler) MuteUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type MuteUserReq struct {
+		MutedID int32 `json:"muted_id" validate:"required,gte=1"`
+	}
+
+	reqBody := MuteUserReq{}
+	if !decodeRequest(w, r, &reqBody) {
+		return
+	}
+
+	err := p.userService.MuteUser(r.Context(), services.MuteUserParams{
+		MuterID: userID,
+		MutedID: reqBody.MutedID,
+	})
+	if errors.Is(err, services.ErrSelfMute) {
+		resp.SetUnprocessableEntity(validation.Errors{{
+			Field:   "muted_id",
+			Code:    codeSelfMute,
+			Message: "muted_id must not be the caller",
+		}}, w)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		resp.SetNotFound("user not found", w)
+		return
+	}
 	if err != nil {
 		resp.SetInternalServerError(err.Error(), w)
 		return
@@ -144,3 +487,53 @@ func (p UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}, w)
 	return
 }
+
+func (p UserHandler) UnmuteUser(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	type UnmuteUserReq struct {
+		MutedID int32 `json:"muted_id" validate:"required,gte=1"`
+	}
+
+	req := UnmuteUserReq{}
+	if !decodeQuery(w, r, &req) {
+		return
+	}
+
+	err := p.userService.UnmuteUser(r.Context(), services.UnmuteUserParams{
+		MuterID: userID,
+		MutedID: req.MutedID,
+	})
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(map[string]interface{}{
+		"status": "success",
+	}, w)
+	return
+}
+
+func (p UserHandler) GetMutedUsers(w http.ResponseWriter, r *http.Request) {
+	resp := NewResponse()
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := p.userService.GetMutedUsers(r.Context(), userID)
+	if err != nil {
+		resp.SetInternalServerError(err.Error(), w)
+		return
+	}
+
+	resp.SetOK(res, w)
+	return
+}
@@ -1,6 +1,7 @@
 package resthttp
 
 import (
+	"database/sql"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gadhittana01/socialmedia/auth"
 	"github.com/gadhittana01/socialmedia/services"
 	"github.com/golang/mock/gomock"
 )
@@ -110,6 +112,94 @@ func Test_GetUsers(t *testing.T) {
 	}
 }
 
+func Test_SearchUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/search/users?q=gi&limit=5",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().SearchUsers(gomock.Any(), services.SearchUsersParams{
+					Query: "gi",
+					Limit: 5,
+				}).Return([]services.SearchUsersRow{{ID: 1}}, nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test default limit",
+			url:  "http://localhost:8000/search/users?q=gi",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().SearchUsers(gomock.Any(), services.SearchUsersParams{
+					Query: "gi",
+					Limit: 10,
+				}).Return([]services.SearchUsersRow{}, nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing query",
+			url:  "http://localhost:8000/search/users",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test limit above maximum",
+			url:  "http://localhost:8000/search/users?q=gi&limit=21",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/search/users?q=gi",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Return([]services.SearchUsersRow{}, errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			field := tt.fields()
+			field.SearchUsers(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("SearchUsers() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func Test_CreateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/user", strings.NewReader(`{
@@ -391,7 +481,9 @@ func Test_DeleteUser(t *testing.T) {
 			fields: func() UserHandler {
 				userMock := NewMockUserService(ctrl)
 
-				userMock.EXPECT().DeleteUser(gomock.Any(), int32(1)).Return(nil)
+				userMock.EXPECT().DeleteUser(gomock.Any(), services.DeleteUserParams{
+					ID: 1,
+				}).Return(nil)
 
 				return UserHandler{
 					userService: userMock,
@@ -407,7 +499,9 @@ func Test_DeleteUser(t *testing.T) {
 			fields: func() UserHandler {
 				userMock := NewMockUserService(ctrl)
 
-				userMock.EXPECT().DeleteUser(gomock.Any(), int32(1)).Return(errors.New("error"))
+				userMock.EXPECT().DeleteUser(gomock.Any(), services.DeleteUserParams{
+					ID: 1,
+				}).Return(errors.New("error"))
 
 				return UserHandler{
 					userService: userMock,
@@ -454,3 +548,844 @@ func Test_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	fullname := "new fullname"
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/user?id=1",
+			body: `{"fullname": "new fullname"}`,
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().PatchUser(gomock.Any(), services.PatchUserParams{
+					ID:       1,
+					Fullname: &fullname,
+				}).Return(services.PatchUserRow{
+					ID:       1,
+					Fullname: "new fullname",
+				}, nil)
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test null fullname",
+			url:  "http://localhost:8000/user?id=1",
+			body: `{"fullname": null}`,
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test error parsed id",
+			url:  "http://localhost:8000/user?id='error'",
+			body: `{"fullname": "new fullname"}`,
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "test user not found",
+			url:  "http://localhost:8000/user?id=1",
+			body: `{"fullname": "new fullname"}`,
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().PatchUser(gomock.Any(), gomock.Any()).Return(services.PatchUserRow{}, sql.ErrNoRows)
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/user?id=1",
+			body: `{"fullname": "new fullname"}`,
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().PatchUser(gomock.Any(), gomock.Any()).Return(services.PatchUserRow{}, errors.New("error"))
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", tt.url, strings.NewReader(tt.body))
+			field := tt.fields()
+			field.PatchUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("PatchUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name        string
+		url         string
+		ifNoneMatch string
+		fields      func() UserHandler
+		wantStatus  int
+		wantETag    string
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/user?id=1",
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(services.GetUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  3,
+				}, nil)
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:        "test not modified",
+			url:         "http://localhost:8000/user?id=1",
+			ifNoneMatch: `"3"`,
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(services.GetUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  3,
+				}, nil)
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusNotModified,
+			wantETag:   `"3"`,
+		},
+		{
+			name:        "test stale if none match",
+			url:         "http://localhost:8000/user?id=1",
+			ifNoneMatch: `"2"`,
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(services.GetUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  3,
+				}, nil)
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name: "test id not provided",
+			url:  "http://localhost:8000/user",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "test user not found",
+			url:  "http://localhost:8000/user?id=1",
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(services.GetUserRow{}, sql.ErrNoRows)
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/user?id=1",
+			fields: func() UserHandler {
+				userMock := NewMockUserService(ctrl)
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(services.GetUserRow{}, errors.New("error"))
+
+				return UserHandler{
+					userService: userMock,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			field := tt.fields()
+			field.GetUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("GetUser() ETag = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func Test_FollowUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			body: `{"follower_id": 1, "followee_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), services.FollowUserParams{
+					FollowerID: 1,
+					FolloweeID: 2,
+				}).Return(nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing followee id",
+			body: `{"follower_id": 1}`,
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test follow yourself",
+			body: `{"follower_id": 1, "followee_id": 1}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(services.ErrSelfFollow)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test follow a blocked user",
+			body: `{"follower_id": 1, "followee_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(services.ErrBlocked)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test user not found",
+			body: `{"follower_id": 1, "followee_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "test internal server error",
+			body: `{"follower_id": 1, "followee_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/user/follow", strings.NewReader(tt.body))
+			field := tt.fields()
+			field.FollowUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("FollowUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_UnfollowUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name: "test normal flow",
+			url:  "http://localhost:8000/user/follow?follower_id=1&followee_id=2",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().UnfollowUser(gomock.Any(), services.UnfollowUserParams{
+					FollowerID: 1,
+					FolloweeID: 2,
+				}).Return(nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test missing follower id",
+			url:  "http://localhost:8000/user/follow?followee_id=2",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "test internal server error",
+			url:  "http://localhost:8000/user/follow?follower_id=1&followee_id=2",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().UnfollowUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", tt.url, nil)
+			field := tt.fields()
+			field.UnfollowUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("UnfollowUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_BlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		body       string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			body:   `{"blocked_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), services.BlockUserParams{
+					BlockerID: 1,
+					BlockedID: 2,
+				}).Return(nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test missing blocked id",
+			userID: 1,
+			body:   `{}`,
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test block yourself",
+			userID: 1,
+			body:   `{"blocked_id": 1}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), gomock.Any()).Return(services.ErrSelfBlock)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test user not found",
+			userID: 1,
+			body:   `{"blocked_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			body:   `{"blocked_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/user/block", strings.NewReader(tt.body))
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.BlockUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("BlockUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_UnblockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			url:    "http://localhost:8000/user/block?blocked_id=2",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().UnblockUser(gomock.Any(), services.UnblockUserParams{
+					BlockerID: 1,
+					BlockedID: 2,
+				}).Return(nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/user/block?blocked_id=2",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/user/block?blocked_id=2",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().UnblockUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", tt.url, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.UnblockUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("UnblockUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetBlockedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			url:    "http://localhost:8000/user/blocks",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().GetBlockedUsers(gomock.Any(), int32(1)).Return([]services.GetUsersRow{{ID: 2}}, nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/user/blocks",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/user/blocks",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().GetBlockedUsers(gomock.Any(), gomock.Any()).Return([]services.GetUsersRow{}, errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.GetBlockedUsers(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetBlockedUsers() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_MuteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		body       string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			body:   `{"muted_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), services.MuteUserParams{
+					MuterID: 1,
+					MutedID: 2,
+				}).Return(nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "test missing muted id",
+			userID: 1,
+			body:   `{}`,
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test mute yourself",
+			userID: 1,
+			body:   `{"muted_id": 1}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), gomock.Any()).Return(services.ErrSelfMute)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "test user not found",
+			userID: 1,
+			body:   `{"muted_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			body:   `{"muted_id": 2}`,
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://localhost:8000/user/mute", strings.NewReader(tt.body))
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.MuteUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("MuteUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_UnmuteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			url:    "http://localhost:8000/user/mute?muted_id=2",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().UnmuteUser(gomock.Any(), services.UnmuteUserParams{
+					MuterID: 1,
+					MutedID: 2,
+				}).Return(nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/user/mute?muted_id=2",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/user/mute?muted_id=2",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().UnmuteUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", tt.url, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.UnmuteUser(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("UnmuteUser() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_GetMutedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		userID     int32
+		url        string
+		fields     func() UserHandler
+		wantStatus int
+	}{
+		{
+			name:   "test normal flow",
+			userID: 1,
+			url:    "http://localhost:8000/user/mutes",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().GetMutedUsers(gomock.Any(), int32(1)).Return([]services.GetUsersRow{{ID: 2}}, nil)
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "test unauthenticated",
+			url:  "http://localhost:8000/user/mutes",
+			fields: func() UserHandler {
+				return UserHandler{
+					userService: NewMockUserService(ctrl),
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "test internal server error",
+			userID: 1,
+			url:    "http://localhost:8000/user/mutes",
+			fields: func() UserHandler {
+				m := NewMockUserService(ctrl)
+				m.EXPECT().GetMutedUsers(gomock.Any(), gomock.Any()).Return([]services.GetUsersRow{}, errors.New("error"))
+
+				return UserHandler{
+					userService: m,
+				}
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.userID != 0 {
+				r = r.WithContext(auth.WithUserID(r.Context(), tt.userID))
+			}
+			field := tt.fields()
+			field.GetMutedUsers(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetMutedUsers() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
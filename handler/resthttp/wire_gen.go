@@ -7,14 +7,38 @@
 package resthttp
 
 import (
-	"database/sql"
-	"github.com/gadhittana01/socialmedia/db"
-	"github.com/gadhittana01/socialmedia/pkg/tag"
-	"github.com/gadhittana01/socialmedia/pkg/user"
-	"github.com/gadhittana01/socialmedia/services"
-	"github.com/google/wire"
+	"github.com/gadhittana01/socialmedia/auth"
+	"time"
 )
 
+// Injectors from bookmark_injector.go:
+
+func InitializedBookmarkHandler(bs BookmarkService) (*BookmarkHandler, error) {
+	bookmarkHandler := NewBookmarkHandler(bs)
+	return bookmarkHandler, nil
+}
+
+// Injectors from media_injector.go:
+
+func InitializedMediaHandler(ms MediaService) (*MediaHandler, error) {
+	mediaHandler := NewMediaHandler(ms)
+	return mediaHandler, nil
+}
+
+// Injectors from message_injector.go:
+
+func InitializedMessageHandler(mgs MessageService) (*MessageHandler, error) {
+	messageHandler := NewMessageHandler(mgs)
+	return messageHandler, nil
+}
+
+// Injectors from notification_injector.go:
+
+func InitializedNotificationHandler(ns NotificationService) (*NotificationHandler, error) {
+	notificationHandler := NewNotificationHandler(ns)
+	return notificationHandler, nil
+}
+
 // Injectors from post_injector.go:
 
 func InitializedPostHandler(ps PostService) (*PostHandler, error) {
@@ -22,44 +46,37 @@ func InitializedPostHandler(ps PostService) (*PostHandler, error) {
 	return postHandler, nil
 }
 
-// Injectors from tag_injector.go:
+// Injectors from profile_injector.go:
 
-func InitializedTagHandler() (*TagHandler, error) {
-	sqlDB := db.InitDB()
-	queries := tag.New(sqlDB)
-	servicesTagService, err := services.NewTagService(queries)
-	if err != nil {
-		return nil, err
-	}
-	tagHandler := NewTagHandler(servicesTagService)
-	return tagHandler, nil
+func InitializedProfileHandler(ps ProfileService) (*ProfileHandler, error) {
+	profileHandler := NewProfileHandler(ps)
+	return profileHandler, nil
 }
 
-// Injectors from user_injector.go:
+// Injectors from stream_injector.go:
 
-func InitializedUserHandler() (*UserHandler, error) {
-	sqlDB := db.InitDB()
-	queries := user.New(sqlDB)
-	servicesUserService, err := services.NewUserService(queries)
-	if err != nil {
-		return nil, err
-	}
-	userHandler := NewUserHandler(servicesUserService)
-	return userHandler, nil
+func InitializedStreamHandler(ss StreamService, heartbeat time.Duration, signer *auth.Signer, tokenTTL StreamTokenTTL) (*StreamHandler, error) {
+	streamHandler := NewStreamHandler(ss, heartbeat, signer, tokenTTL)
+	return streamHandler, nil
 }
 
-// tag_injector.go:
-
-var dbTagSet = wire.NewSet(db.InitDB, wire.Bind(new(tag.DBTX), new(*sql.DB)))
-
-var tagPkgSet = wire.NewSet(tag.New, wire.Bind(new(services.TagResource), new(*tag.Queries)))
+// Injectors from tag_injector.go:
 
-var tagService = wire.NewSet(services.NewTagService, wire.Bind(new(TagService), new(services.TagService)))
+func InitializedTagHandler(ts TagService) (*TagHandler, error) {
+	tagHandler := NewTagHandler(ts)
+	return tagHandler, nil
+}
 
-// user_injector.go:
+// Injectors from trending_injector.go:
 
-var dbUsrSet = wire.NewSet(db.InitDB, wire.Bind(new(user.DBTX), new(*sql.DB)))
+func InitializedTrendingHandler(ts TrendingService) (*TrendingHandler, error) {
+	trendingHandler := NewTrendingHandler(ts)
+	return trendingHandler, nil
+}
 
-var userPkgSet = wire.NewSet(user.New, wire.Bind(new(services.UserResource), new(*user.Queries)))
+// Injectors from user_injector.go:
 
-var userService = wire.NewSet(services.NewUserService, wire.Bind(new(UserService), new(services.UserService)))
+func InitializedUserHandler(us UserService) (*UserHandler, error) {
+	userHandler := NewUserHandler(us)
+	return userHandler, nil
+}
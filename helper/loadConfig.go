@@ -1,29 +1,149 @@
 package helper
 
 import (
-	"io/ioutil"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/gadhittana01/socialmedia/config"
 	"gopkg.in/yaml.v2"
 )
 
-func LoadConfig(c *config.GlobalConfig) {
-	path := "config/social-media-http.yaml"
-	yamlFile, err := ioutil.ReadFile(path)
-	if err != nil {
-		log.Printf("yamlFile.Get err   #%v ", err)
+const (
+	defaultConfigPath = "config/social-media-http.yaml"
+	configPathEnv     = "SOCIALMEDIA_CONFIG"
+	envPrefix         = "SOCIALMEDIA_"
+)
+
+// LoadConfig fills c from, in increasing order of precedence, the built-in
+// defaults, an optional YAML file, SOCIALMEDIA_* environment variables and
+// command line flags. Every GlobalConfig field can be set through an env var
+// named after its YAML path (db.host -> SOCIALMEDIA_DB_HOST) and a flag of
+// the same path (-db.host). The file is taken from -config, then
+// SOCIALMEDIA_CONFIG, then config/social-media-http.yaml if it exists.
+// The positional arguments left after the flags are returned.
+func LoadConfig(c *config.GlobalConfig, args []string) ([]string, error) {
+	*c = config.Default()
+
+	fs := flag.NewFlagSet("social-media-http", flag.ContinueOnError)
+	path := fs.String("config", "", "path to the YAML config file (env "+configPathEnv+")")
+	fields := configFields(reflect.ValueOf(c).Elem(), "")
+	values := map[string]*string{}
+	for _, f := range fields {
+		values[f.path] = fs.String(f.path, "", "overrides "+f.path+" (env "+f.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := loadConfigFile(c, *path); err != nil {
+		return nil, err
 	}
 
-	err = yaml.Unmarshal(yamlFile, c)
+	var problems config.ValidationError
+	for _, f := range fields {
+		if v, ok := os.LookupEnv(f.env); ok {
+			if err := setField(f.value, v); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", f.env, err))
+			}
+		}
+	}
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if f.path == fl.Name {
+				if err := setField(f.value, *values[f.path]); err != nil {
+					problems = append(problems, fmt.Sprintf("-%s: %v", f.path, err))
+				}
+			}
+		}
+	})
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
+
+func loadConfigFile(c *config.GlobalConfig, path string) error {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(configPathEnv)
+	}
+	if path == "" {
+		path, explicit = defaultConfigPath, false
+	}
+
+	yamlFile, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
 	if err != nil {
-		log.Fatalf("Unmarshal: %v", err)
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(yamlFile, c); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
 	}
+	return nil
+}
+
+type configField struct {
+	path  string
+	env   string
+	value reflect.Value
+}
+
+func configFields(v reflect.Value, prefix string) []configField {
+	var fields []configField
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if v.Field(i).Kind() == reflect.Struct {
+			fields = append(fields, configFields(v.Field(i), name)...)
+			continue
+		}
+		fields = append(fields, configField{
+			path:  name,
+			env:   envPrefix + strings.ToUpper(strings.ReplaceAll(name, ".", "_")),
+			value: v.Field(i),
+		})
+	}
+	return fields
+}
 
-	// change db host
-	DBHost := os.Getenv("DB_HOST")
-	if DBHost != "" {
-		c.DB.Host = DBHost
+func setField(f reflect.Value, raw string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.SetBool(b)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		f.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported config type %s", f.Kind())
 	}
+	return nil
 }
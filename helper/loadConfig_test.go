@@ -0,0 +1,99 @@
+package helper
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/config"
+)
+
+func Test_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	os.WriteFile(file, []byte("http:\n  port: 7000\ndb:\n  host: filehost\n  name: filedb\n"), 0644)
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		want     func() config.GlobalConfig
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name: "file overrides defaults",
+			args: []string{"-config", file},
+			want: func() config.GlobalConfig {
+				c := config.Default()
+				c.HTTP.Port = 7000
+				c.DB.Host = "filehost"
+				c.DB.Name = "filedb"
+				return c
+			},
+			wantArgs: []string{},
+		},
+		{
+			name: "env overrides file and flags override env",
+			args: []string{"-db.host", "flaghost", "migrate", "up"},
+			env: map[string]string{
+				"SOCIALMEDIA_CONFIG":           file,
+				"SOCIALMEDIA_DB_HOST":          "envhost",
+				"SOCIALMEDIA_DB_PASSWORD":      "secret",
+				"SOCIALMEDIA_LOG_LEVEL":        "debug",
+				"SOCIALMEDIA_TRACING_INSECURE": "true",
+			},
+			want: func() config.GlobalConfig {
+				c := config.Default()
+				c.HTTP.Port = 7000
+				c.DB.Host = "flaghost"
+				c.DB.Name = "filedb"
+				c.DB.Password = "secret"
+				c.Log.Level = "debug"
+				c.Tracing.Insecure = true
+				return c
+			},
+			wantArgs: []string{"migrate", "up"},
+		},
+		{
+			name:    "missing explicit file",
+			args:    []string{"-config", filepath.Join(dir, "missing.yaml")},
+			wantErr: true,
+		},
+		{
+			name: "invalid env value",
+			env: map[string]string{
+				"SOCIALMEDIA_HTTP_PORT": "eighty",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid result",
+			args:    []string{"-db.name", ""},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got := &config.GlobalConfig{}
+			args, err := LoadConfig(got, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(*got, tt.want()) {
+				t.Errorf("LoadConfig() = %+v, want %+v", *got, tt.want())
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("LoadConfig() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_Load(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 || migrations[0].Name != "create_main_table" {
+		t.Fatalf("Load() = %+v, want 1_create_main_table first", migrations)
+	}
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version <= migrations[i-1].Version {
+			t.Errorf("Load() versions not sorted: %d after %d", migrations[i].Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func Test_load(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "sorted pairs",
+			fsys: fstest.MapFS{
+				"sql/10_b.up.sql":   {Data: []byte("b up")},
+				"sql/10_b.down.sql": {Data: []byte("b down")},
+				"sql/2_a.up.sql":    {Data: []byte("a up")},
+				"sql/2_a.down.sql":  {Data: []byte("a down")},
+				"sql/readme.md":     {Data: []byte("ignored")},
+			},
+			want: []int64{2, 10},
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{
+				"sql/1_a.up.sql": {Data: []byte("a up")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid version",
+			fsys: fstest.MapFS{
+				"sql/one_a.up.sql":   {Data: []byte("a up")},
+				"sql/one_a.down.sql": {Data: []byte("a down")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load(tt.fsys, "sql")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("load() = %+v, want versions %v", got, tt.want)
+			}
+			for i, v := range tt.want {
+				if got[i].Version != v {
+					t.Errorf("load()[%d].Version = %d, want %d", i, got[i].Version, v)
+				}
+			}
+		})
+	}
+}
+
+func Test_Migrator_Up(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "a", Up: "CREATE TABLE a()", Down: "DROP TABLE a"},
+		{Version: 2, Name: "b", Up: "CREATE TABLE b()", Down: "DROP TABLE b"},
+		{Version: 3, Name: "c", Up: "CREATE TABLE c()", Down: "DROP TABLE c"},
+	}
+
+	tests := []struct {
+		name     string
+		target   int64
+		initMock func(mock sqlmock.Sqlmock)
+		wantFrom int64
+		wantTo   int64
+		wantErr  bool
+	}{
+		{
+			name:   "apply pending migrations under lock",
+			target: 0,
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b()")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "b").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE c()")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(3, "c").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantFrom: 1,
+			wantTo:   3,
+		},
+		{
+			name:   "stop at target",
+			target: 2,
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b()")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "b").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantFrom: 1,
+			wantTo:   2,
+		},
+		{
+			name:   "roll back failed migration",
+			target: 0,
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE c()")).WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantFrom: 2,
+			wantTo:   2,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			tt.initMock(mock)
+			m := &Migrator{db: db, migrations: migrations}
+
+			from, to, err := m.Up(context.Background(), tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Up() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("Up() = %d, %d, want %d, %d", from, to, tt.wantFrom, tt.wantTo)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_Migrator_Down(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "a", Up: "CREATE TABLE a()", Down: "DROP TABLE a"},
+		{Version: 2, Name: "b", Up: "CREATE TABLE b()", Down: "DROP TABLE b"},
+	}}
+	from, to, err := m.Down(context.Background())
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if from != 2 || to != 1 {
+		t.Errorf("Down() = %d, %d, want 2, 1", from, to)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
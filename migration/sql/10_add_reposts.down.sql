@@ -0,0 +1,3 @@
+DELETE FROM posts WHERE repost_of_id IS NOT NULL;
+ALTER TABLE posts DROP COLUMN IF EXISTS quote_of_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS repost_of_id;
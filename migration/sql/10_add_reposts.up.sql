@@ -0,0 +1,10 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS repost_of_id INT REFERENCES posts(id) ON DELETE CASCADE;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS quote_of_id INT REFERENCES posts(id) ON DELETE SET NULL;
+ALTER TABLE posts ADD CONSTRAINT posts_repost_or_quote_check
+   CHECK (repost_of_id IS NULL OR quote_of_id IS NULL);
+
+-- A user reposts a post at most once. Rows that are not reposts have a NULL
+-- repost_of_id and never conflict.
+CREATE UNIQUE INDEX IF NOT EXISTS posts_userid_repost_of_id_key ON posts (userid, repost_of_id);
+CREATE INDEX IF NOT EXISTS posts_repost_of_id_idx ON posts (repost_of_id);
+CREATE INDEX IF NOT EXISTS posts_quote_of_id_idx ON posts (quote_of_id);
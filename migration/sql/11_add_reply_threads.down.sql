@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS root_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS in_reply_to_id;
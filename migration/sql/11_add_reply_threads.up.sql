@@ -0,0 +1,7 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS in_reply_to_id INT REFERENCES posts(id) ON DELETE SET NULL;
+-- root_id is the post that started the thread a reply belongs to. It is NULL
+-- for posts that are not replies.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS root_id INT REFERENCES posts(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS posts_in_reply_to_id_idx ON posts (in_reply_to_id, created_at, id);
+CREATE INDEX IF NOT EXISTS posts_root_id_idx ON posts (root_id);
@@ -0,0 +1,4 @@
+DROP FUNCTION IF EXISTS post_visible_to(INT, INT, BOOLEAN);
+ALTER TABLE posts DROP COLUMN IF EXISTS visibility;
+DROP TYPE IF EXISTS post_visibility;
+DROP TABLE IF EXISTS user_follows;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS user_follows(
+   follower_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   followee_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (follower_id, followee_id),
+   CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_follows_followee_id_idx ON user_follows (followee_id);
+
+CREATE TYPE post_visibility AS ENUM ('public', 'followers', 'private', 'unlisted');
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS visibility post_visibility NOT NULL DEFAULT 'public';
+
+-- post_visible_to tells whether viewer_id may see a post. Authors see all
+-- their posts, followers also see followers-only ones, and everyone else
+-- only public and unlisted ones. Unlisted posts are left out of listings.
+-- A reply is only visible when the root of its thread is too. A viewer_id
+-- of 0 is an anonymous viewer.
+CREATE OR REPLACE FUNCTION post_visible_to(target_id INT, viewer_id INT, listing BOOLEAN)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT coalesce(bool_and(
+      p.userid = viewer_id
+      OR p.visibility = 'public'
+      OR (p.visibility = 'unlisted' AND NOT (listing AND p.id = target_id))
+      OR (p.visibility = 'followers' AND EXISTS (
+         SELECT 1 FROM user_follows f
+         WHERE f.followee_id = p.userid AND f.follower_id = viewer_id
+      ))
+   ), false)
+   FROM posts p
+   WHERE p.id = target_id
+      OR p.id = (SELECT t.root_id FROM posts t WHERE t.id = target_id)
+$$;
@@ -0,0 +1,25 @@
+CREATE OR REPLACE FUNCTION post_visible_to(target_id INT, viewer_id INT, listing BOOLEAN)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT coalesce(bool_and(
+      p.userid = viewer_id
+      OR p.visibility = 'public'
+      OR (p.visibility = 'unlisted' AND NOT (listing AND p.id = target_id))
+      OR (p.visibility = 'followers' AND EXISTS (
+         SELECT 1 FROM user_follows f
+         WHERE f.followee_id = p.userid AND f.follower_id = viewer_id
+      ))
+   ), false)
+   FROM posts p
+   WHERE p.id = target_id
+      OR p.id = (SELECT t.root_id FROM posts t WHERE t.id = target_id)
+$$;
+
+DROP TRIGGER IF EXISTS reactions_reject_blocked ON reactions;
+DROP TRIGGER IF EXISTS posts_reject_blocked ON posts;
+DROP TRIGGER IF EXISTS user_follows_reject_blocked ON user_follows;
+DROP FUNCTION IF EXISTS reject_blocked_reaction();
+DROP FUNCTION IF EXISTS reject_blocked_post();
+DROP FUNCTION IF EXISTS reject_blocked_follow();
+DROP FUNCTION IF EXISTS users_blocked(INT, INT);
+DROP TABLE IF EXISTS user_mutes;
+DROP TABLE IF EXISTS user_blocks;
@@ -0,0 +1,102 @@
+CREATE TABLE IF NOT EXISTS user_blocks(
+   blocker_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   blocked_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (blocker_id, blocked_id),
+   CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_blocks_blocked_id_idx ON user_blocks (blocked_id);
+
+CREATE TABLE IF NOT EXISTS user_mutes(
+   muter_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   muted_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (muter_id, muted_id),
+   CHECK (muter_id <> muted_id)
+);
+
+-- users_blocked tells whether either user blocks the other.
+CREATE OR REPLACE FUNCTION users_blocked(a INT, b INT)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT EXISTS (
+      SELECT 1 FROM user_blocks
+      WHERE (blocker_id = a AND blocked_id = b)
+         OR (blocker_id = b AND blocked_id = a)
+   )
+$$;
+
+-- Users who block each other cannot follow each other, nor reply to,
+-- repost, quote or react to each other's posts. These writes fail with the
+-- custom SQLSTATE SM001.
+CREATE OR REPLACE FUNCTION reject_blocked_follow()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF users_blocked(NEW.follower_id, NEW.followee_id) THEN
+      RAISE EXCEPTION 'users % and % block each other', NEW.follower_id, NEW.followee_id
+         USING ERRCODE = 'SM001';
+   END IF;
+   RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION reject_blocked_post()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF EXISTS (
+      SELECT 1 FROM posts t
+      WHERE t.id IN (NEW.in_reply_to_id, NEW.repost_of_id, NEW.quote_of_id)
+         AND users_blocked(NEW.userid, t.userid)
+   ) THEN
+      RAISE EXCEPTION 'user % is blocked from interacting with this post', NEW.userid
+         USING ERRCODE = 'SM001';
+   END IF;
+   RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION reject_blocked_reaction()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF users_blocked(NEW.user_id, (SELECT userid FROM posts WHERE id = NEW.post_id)) THEN
+      RAISE EXCEPTION 'user % is blocked from reacting to post %', NEW.user_id, NEW.post_id
+         USING ERRCODE = 'SM001';
+   END IF;
+   RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER user_follows_reject_blocked BEFORE INSERT ON user_follows
+   FOR EACH ROW EXECUTE FUNCTION reject_blocked_follow();
+CREATE TRIGGER posts_reject_blocked BEFORE INSERT ON posts
+   FOR EACH ROW EXECUTE FUNCTION reject_blocked_post();
+CREATE TRIGGER reactions_reject_blocked BEFORE INSERT ON reactions
+   FOR EACH ROW EXECUTE FUNCTION reject_blocked_reaction();
+
+-- post_visible_to additionally hides posts when the viewer and the author of
+-- the post or of its thread root block each other, or the viewer muted that
+-- author.
+CREATE OR REPLACE FUNCTION post_visible_to(target_id INT, viewer_id INT, listing BOOLEAN)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT coalesce(bool_and(
+      p.userid = viewer_id
+      OR (
+         NOT users_blocked(p.userid, viewer_id)
+         AND NOT EXISTS (
+            SELECT 1 FROM user_mutes m
+            WHERE m.muter_id = viewer_id AND m.muted_id = p.userid
+         )
+         AND (
+            p.visibility = 'public'
+            OR (p.visibility = 'unlisted' AND NOT (listing AND p.id = target_id))
+            OR (p.visibility = 'followers' AND EXISTS (
+               SELECT 1 FROM user_follows f
+               WHERE f.followee_id = p.userid AND f.follower_id = viewer_id
+            ))
+         )
+      )
+   ), false)
+   FROM posts p
+   WHERE p.id = target_id
+      OR p.id = (SELECT t.root_id FROM posts t WHERE t.id = target_id)
+$$;
@@ -0,0 +1,30 @@
+CREATE OR REPLACE FUNCTION post_visible_to(target_id INT, viewer_id INT, listing BOOLEAN)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT coalesce(bool_and(
+      p.userid = viewer_id
+      OR (
+         NOT users_blocked(p.userid, viewer_id)
+         AND NOT EXISTS (
+            SELECT 1 FROM user_mutes m
+            WHERE m.muter_id = viewer_id AND m.muted_id = p.userid
+         )
+         AND (
+            p.visibility = 'public'
+            OR (p.visibility = 'unlisted' AND NOT (listing AND p.id = target_id))
+            OR (p.visibility = 'followers' AND EXISTS (
+               SELECT 1 FROM user_follows f
+               WHERE f.followee_id = p.userid AND f.follower_id = viewer_id
+            ))
+         )
+      )
+   ), false)
+   FROM posts p
+   WHERE p.id = target_id
+      OR p.id = (SELECT t.root_id FROM posts t WHERE t.id = target_id)
+$$;
+
+DROP INDEX IF EXISTS posts_scheduled_publish_at_idx;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_scheduled_publish_at_check;
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS post_status;
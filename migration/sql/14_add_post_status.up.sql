@@ -0,0 +1,37 @@
+CREATE TYPE post_status AS ENUM ('draft', 'scheduled', 'published');
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS status post_status NOT NULL DEFAULT 'published';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE posts ADD CONSTRAINT posts_scheduled_publish_at_check
+   CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+-- The scheduler only ever looks for scheduled posts that are due.
+CREATE INDEX IF NOT EXISTS posts_scheduled_publish_at_idx ON posts (publish_at, id)
+   WHERE status = 'scheduled';
+
+-- post_visible_to additionally keeps drafts and scheduled posts to their
+-- author.
+CREATE OR REPLACE FUNCTION post_visible_to(target_id INT, viewer_id INT, listing BOOLEAN)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT coalesce(bool_and(
+      p.userid = viewer_id
+      OR (
+         p.status = 'published'
+         AND NOT users_blocked(p.userid, viewer_id)
+         AND NOT EXISTS (
+            SELECT 1 FROM user_mutes m
+            WHERE m.muter_id = viewer_id AND m.muted_id = p.userid
+         )
+         AND (
+            p.visibility = 'public'
+            OR (p.visibility = 'unlisted' AND NOT (listing AND p.id = target_id))
+            OR (p.visibility = 'followers' AND EXISTS (
+               SELECT 1 FROM user_follows f
+               WHERE f.followee_id = p.userid AND f.follower_id = viewer_id
+            ))
+         )
+      )
+   ), false)
+   FROM posts p
+   WHERE p.id = target_id
+      OR p.id = (SELECT t.root_id FROM posts t WHERE t.id = target_id)
+$$;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS post_revisions;
+DROP FUNCTION IF EXISTS mark_post_edited();
+DROP FUNCTION IF EXISTS reject_revision_update();
+ALTER TABLE posts DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1,54 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS post_revisions(
+   post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+   revision INT NOT NULL,
+   title VARCHAR NOT NULL,
+   description VARCHAR NOT NULL,
+   tag_ids INT[] NOT NULL,
+   editor_id INT REFERENCES users(id) ON DELETE SET NULL,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (post_id, revision)
+);
+
+-- Revisions are history: they go away with their post but never change.
+-- Clearing the editor of a deleted user is the one update allowed.
+CREATE OR REPLACE FUNCTION reject_revision_update()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF (NEW.post_id, NEW.revision, NEW.title, NEW.description, NEW.tag_ids, NEW.created_at)
+      IS DISTINCT FROM (OLD.post_id, OLD.revision, OLD.title, OLD.description, OLD.tag_ids, OLD.created_at)
+      OR NEW.editor_id IS NOT NULL AND NEW.editor_id IS DISTINCT FROM OLD.editor_id THEN
+      RAISE EXCEPTION 'revision % of post % is immutable', OLD.revision, OLD.post_id;
+   END IF;
+   RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER post_revisions_immutable
+   BEFORE UPDATE ON post_revisions
+   FOR EACH ROW EXECUTE FUNCTION reject_revision_update();
+
+-- Every revision after the first is an edit of the post.
+CREATE OR REPLACE FUNCTION mark_post_edited()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   UPDATE posts SET edited_at = NEW.created_at WHERE id = NEW.post_id;
+   RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER post_revisions_mark_edited
+   AFTER INSERT ON post_revisions
+   FOR EACH ROW WHEN (NEW.revision > 1)
+   EXECUTE FUNCTION mark_post_edited();
+
+-- Existing posts start their history with their current content. Who wrote
+-- it is not recorded, so the author stands in as the editor.
+INSERT INTO post_revisions (post_id, revision, title, description, tag_ids, editor_id, created_at)
+SELECT p.id, 1, p.title, p.description,
+   COALESCE((SELECT array_agg(DISTINCT pt.tagid ORDER BY pt.tagid) FROM post_tags pt WHERE pt.postid = p.id), '{}'),
+   p.userid, COALESCE(p.updated_at, p.created_at, now())
+FROM posts p
+ON CONFLICT DO NOTHING;
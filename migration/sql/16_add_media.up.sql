@@ -0,0 +1,24 @@
+-- A media row is one user's upload. The bytes live in storage under their
+-- SHA-256, so identical files share one object however many users upload
+-- them, and a user uploading the same file again gets the same row back.
+CREATE TABLE IF NOT EXISTS media(
+   id SERIAL PRIMARY KEY,
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   sha256 CHAR(64) NOT NULL,
+   content_type VARCHAR NOT NULL,
+   size BIGINT NOT NULL,
+   width INT,
+   height INT,
+   thumbnail_content_type VARCHAR,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   UNIQUE (user_id, sha256)
+);
+
+CREATE TABLE IF NOT EXISTS post_media(
+   post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+   media_id INT NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+   position INT NOT NULL,
+   PRIMARY KEY (post_id, media_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_media_media_id_idx ON post_media (media_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS user_handle_redirects;
+
+ALTER TABLE users
+   DROP COLUMN IF EXISTS avatar_media_id,
+   DROP COLUMN IF EXISTS website,
+   DROP COLUMN IF EXISTS location,
+   DROP COLUMN IF EXISTS bio,
+   DROP COLUMN IF EXISTS handle_changed_at,
+   DROP COLUMN IF EXISTS handle;
@@ -0,0 +1,19 @@
+-- Handles are stored lowercased, so uniqueness and lookups ignore case.
+-- Users created before handles existed have none until they pick one.
+ALTER TABLE users
+   ADD COLUMN IF NOT EXISTS handle VARCHAR(30) UNIQUE CHECK (handle ~ '^[a-z][a-z0-9_]{2,29}$'),
+   ADD COLUMN IF NOT EXISTS handle_changed_at TIMESTAMP,
+   ADD COLUMN IF NOT EXISTS bio VARCHAR(300) NOT NULL DEFAULT '',
+   ADD COLUMN IF NOT EXISTS location VARCHAR(100) NOT NULL DEFAULT '',
+   ADD COLUMN IF NOT EXISTS website VARCHAR(200) NOT NULL DEFAULT '',
+   ADD COLUMN IF NOT EXISTS avatar_media_id INT REFERENCES media(id) ON DELETE SET NULL;
+
+-- A handle a user gave up keeps pointing at them until expires_at, and
+-- nobody else can take it before then.
+CREATE TABLE IF NOT EXISTS user_handle_redirects(
+   handle VARCHAR(30) PRIMARY KEY,
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_handle_redirects_user_id_idx ON user_handle_redirects (user_id);
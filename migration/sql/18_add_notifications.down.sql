@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS posts_enqueue_notifications ON posts;
+DROP TRIGGER IF EXISTS reactions_enqueue_notification ON reactions;
+DROP TRIGGER IF EXISTS user_follows_enqueue_notification ON user_follows;
+DROP FUNCTION IF EXISTS enqueue_post_notifications();
+DROP FUNCTION IF EXISTS enqueue_reaction_notification();
+DROP FUNCTION IF EXISTS enqueue_follow_notification();
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notification_actors;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_events;
+DROP TYPE IF EXISTS notification_kind;
@@ -0,0 +1,113 @@
+CREATE TYPE notification_kind AS ENUM ('follow', 'reply', 'mention', 'reaction', 'repost', 'quote');
+
+-- notification_events is the outbox the triggers below write to. Writes only
+-- record what happened; the notification worker turns events into
+-- notifications later and deletes them.
+CREATE TABLE IF NOT EXISTS notification_events(
+   id BIGSERIAL PRIMARY KEY,
+   kind notification_kind NOT NULL,
+   recipient_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   actor_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- For follows, reactions and reposts post_id is the recipient's post, or
+-- NULL for follows, so one notification gathers every actor. For replies,
+-- mentions and quotes it is the actor's new post.
+CREATE TABLE IF NOT EXISTS notifications(
+   id SERIAL PRIMARY KEY,
+   recipient_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   kind notification_kind NOT NULL,
+   post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   updated_at TIMESTAMP NOT NULL DEFAULT now(),
+   read_at TIMESTAMP
+);
+
+-- Events join the unread notification of their kind about the same post.
+-- Once it is read, the next event starts a new one.
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_unread_group_key
+   ON notifications (recipient_id, kind, coalesce(post_id, 0)) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS notifications_recipient_id_idx
+   ON notifications (recipient_id, updated_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS notification_actors(
+   notification_id INT NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+   actor_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (notification_id, actor_id)
+);
+
+-- Kinds without a row are enabled.
+CREATE TABLE IF NOT EXISTS notification_preferences(
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   kind notification_kind NOT NULL,
+   enabled BOOLEAN NOT NULL,
+   PRIMARY KEY (user_id, kind)
+);
+
+CREATE OR REPLACE FUNCTION enqueue_follow_notification()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   INSERT INTO notification_events (kind, recipient_id, actor_id)
+   VALUES ('follow', NEW.followee_id, NEW.follower_id);
+   RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION enqueue_reaction_notification()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   INSERT INTO notification_events (kind, recipient_id, actor_id, post_id)
+   SELECT 'reaction', p.userid, NEW.user_id, p.id
+   FROM posts p
+   WHERE p.id = NEW.post_id AND p.userid <> NEW.user_id;
+   RETURN NULL;
+END
+$$;
+
+-- Posts notify once, when they are published. Mentions are @handles in the
+-- title or description, except of the author and of whoever the post already
+-- notifies as a reply or quote.
+CREATE OR REPLACE FUNCTION enqueue_post_notifications()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF NEW.status <> 'published' OR (TG_OP = 'UPDATE' AND OLD.status = 'published') THEN
+      RETURN NULL;
+   END IF;
+
+   INSERT INTO notification_events (kind, recipient_id, actor_id, post_id)
+   SELECT 'reply', t.userid, NEW.userid, NEW.id
+   FROM posts t WHERE t.id = NEW.in_reply_to_id AND t.userid <> NEW.userid
+   UNION ALL
+   SELECT 'repost', t.userid, NEW.userid, t.id
+   FROM posts t WHERE t.id = NEW.repost_of_id AND t.userid <> NEW.userid
+   UNION ALL
+   SELECT 'quote', t.userid, NEW.userid, NEW.id
+   FROM posts t WHERE t.id = NEW.quote_of_id AND t.userid <> NEW.userid;
+
+   IF NEW.repost_of_id IS NULL THEN
+      INSERT INTO notification_events (kind, recipient_id, actor_id, post_id)
+      SELECT DISTINCT 'mention'::notification_kind, u.id, NEW.userid, NEW.id
+      FROM regexp_matches(
+         NEW.title || ' ' || NEW.description,
+         '(?:^|[^A-Za-z0-9_])@([A-Za-z][A-Za-z0-9_]{2,29})(?![A-Za-z0-9_])', 'g'
+      ) AS m
+      JOIN users u ON u.handle = lower(m[1])
+      WHERE u.id <> NEW.userid
+         AND u.id NOT IN (
+            SELECT t.userid FROM posts t
+            WHERE t.id IN (NEW.in_reply_to_id, NEW.quote_of_id)
+         );
+   END IF;
+   RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER user_follows_enqueue_notification AFTER INSERT ON user_follows
+   FOR EACH ROW EXECUTE FUNCTION enqueue_follow_notification();
+CREATE TRIGGER reactions_enqueue_notification AFTER INSERT ON reactions
+   FOR EACH ROW EXECUTE FUNCTION enqueue_reaction_notification();
+CREATE TRIGGER posts_enqueue_notifications AFTER INSERT OR UPDATE OF status ON posts
+   FOR EACH ROW EXECUTE FUNCTION enqueue_post_notifications();
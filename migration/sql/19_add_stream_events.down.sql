@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS notification_actors_stream_event ON notification_actors;
+DROP TRIGGER IF EXISTS reactions_stream_event ON reactions;
+DROP TRIGGER IF EXISTS posts_stream_event ON posts;
+DROP TRIGGER IF EXISTS stream_events_notify ON stream_events;
+DROP FUNCTION IF EXISTS stream_notification();
+DROP FUNCTION IF EXISTS stream_reaction_count();
+DROP FUNCTION IF EXISTS stream_published_post();
+DROP FUNCTION IF EXISTS notify_stream_event();
+DROP FUNCTION IF EXISTS stream_event_visible_to(stream_events, INT);
+DROP TABLE IF EXISTS stream_events;
+DROP TYPE IF EXISTS stream_event_kind;
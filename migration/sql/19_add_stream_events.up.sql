@@ -0,0 +1,113 @@
+CREATE TYPE stream_event_kind AS ENUM ('post', 'notification', 'reaction_count');
+
+-- stream_events feeds GET /stream. Every insert is announced on the
+-- stream_events channel with the new id as payload, so each replica can load
+-- and push it to its own connections. Rows are kept for a while so
+-- reconnecting clients can catch up from Last-Event-ID.
+CREATE TABLE IF NOT EXISTS stream_events(
+   id BIGSERIAL PRIMARY KEY,
+   kind stream_event_kind NOT NULL,
+   -- recipient_id is set for notifications, which go to that user only.
+   recipient_id INT REFERENCES users(id) ON DELETE CASCADE,
+   post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+   actor_id INT REFERENCES users(id) ON DELETE CASCADE,
+   payload JSONB NOT NULL,
+   created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS stream_events_created_at_idx ON stream_events (created_at);
+
+-- New posts reach their author and the author's followers, reaction counts
+-- reach whoever may read the post, and notifications their recipient.
+CREATE OR REPLACE FUNCTION stream_event_visible_to(e stream_events, viewer_id INT)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT CASE e.kind
+      WHEN 'notification' THEN e.recipient_id = viewer_id
+      WHEN 'post' THEN (
+         e.actor_id = viewer_id OR EXISTS (
+            SELECT 1 FROM user_follows f
+            WHERE f.followee_id = e.actor_id AND f.follower_id = viewer_id
+         )
+      ) AND post_visible_to(e.post_id, viewer_id, true)
+      ELSE post_visible_to(e.post_id, viewer_id, false)
+   END
+$$;
+
+CREATE OR REPLACE FUNCTION notify_stream_event()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   PERFORM pg_notify('stream_events', NEW.id::text);
+   RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION stream_published_post()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF NEW.status <> 'published' OR (TG_OP = 'UPDATE' AND OLD.status = 'published') THEN
+      RETURN NULL;
+   END IF;
+
+   INSERT INTO stream_events (kind, post_id, actor_id, payload)
+   VALUES ('post', NEW.id, NEW.userid, jsonb_build_object(
+      'id', NEW.id,
+      'user_id', NEW.userid,
+      'title', NEW.title,
+      'description', NEW.description,
+      'repost_of_id', NEW.repost_of_id,
+      'quote_of_id', NEW.quote_of_id,
+      'in_reply_to_id', NEW.in_reply_to_id,
+      'visibility', NEW.visibility,
+      'created_at', NEW.created_at
+   ));
+   RETURN NULL;
+END
+$$;
+
+-- The count is read when the reaction is written. Posts being deleted send
+-- nothing.
+CREATE OR REPLACE FUNCTION stream_reaction_count()
+RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+   target_id INT;
+BEGIN
+   IF TG_OP = 'DELETE' THEN
+      target_id := OLD.post_id;
+   ELSE
+      target_id := NEW.post_id;
+   END IF;
+
+   INSERT INTO stream_events (kind, post_id, payload)
+   SELECT 'reaction_count', p.id, jsonb_build_object(
+      'post_id', p.id,
+      'reaction_count', (SELECT count(*) FROM reactions r WHERE r.post_id = p.id)
+   )
+   FROM posts p WHERE p.id = target_id;
+   RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION stream_notification()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   INSERT INTO stream_events (kind, recipient_id, post_id, actor_id, payload)
+   SELECT 'notification', n.recipient_id, n.post_id, NEW.actor_id, jsonb_build_object(
+      'id', n.id,
+      'kind', n.kind,
+      'post_id', n.post_id,
+      'actor_id', NEW.actor_id,
+      'actor_count', (SELECT count(*) FROM notification_actors a WHERE a.notification_id = n.id)
+   )
+   FROM notifications n WHERE n.id = NEW.notification_id;
+   RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER stream_events_notify AFTER INSERT ON stream_events
+   FOR EACH ROW EXECUTE FUNCTION notify_stream_event();
+CREATE TRIGGER posts_stream_event AFTER INSERT OR UPDATE OF status ON posts
+   FOR EACH ROW EXECUTE FUNCTION stream_published_post();
+CREATE TRIGGER reactions_stream_event AFTER INSERT OR DELETE ON reactions
+   FOR EACH ROW EXECUTE FUNCTION stream_reaction_count();
+CREATE TRIGGER notification_actors_stream_event AFTER INSERT OR UPDATE ON notification_actors
+   FOR EACH ROW EXECUTE FUNCTION stream_notification();
@@ -0,0 +1,27 @@
+CREATE OR REPLACE FUNCTION stream_event_visible_to(e stream_events, viewer_id INT)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT CASE e.kind
+      WHEN 'notification' THEN e.recipient_id = viewer_id
+      WHEN 'post' THEN (
+         e.actor_id = viewer_id OR EXISTS (
+            SELECT 1 FROM user_follows f
+            WHERE f.followee_id = e.actor_id AND f.follower_id = viewer_id
+         )
+      ) AND post_visible_to(e.post_id, viewer_id, true)
+      ELSE post_visible_to(e.post_id, viewer_id, false)
+   END
+$$;
+
+DROP TRIGGER IF EXISTS messages_stream_event ON messages;
+DROP TRIGGER IF EXISTS messages_reject_blocked ON messages;
+DROP TRIGGER IF EXISTS conversation_participants_reject_blocked ON conversation_participants;
+DROP FUNCTION IF EXISTS stream_message();
+DROP FUNCTION IF EXISTS reject_blocked_message();
+DROP FUNCTION IF EXISTS reject_blocked_participant();
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS conversation_participants;
+DROP TABLE IF EXISTS conversations;
+
+-- Postgres cannot drop an enum value, so 'message' stays in
+-- stream_event_kind. Its events are removed instead.
+DELETE FROM stream_events WHERE kind = 'message';
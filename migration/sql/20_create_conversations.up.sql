@@ -0,0 +1,112 @@
+-- Values added here cannot be used before this migration commits, which is
+-- why nothing below names 'message' outside of trigger bodies.
+ALTER TYPE stream_event_kind ADD VALUE IF NOT EXISTS 'message';
+
+CREATE TABLE IF NOT EXISTS conversations(
+   id SERIAL PRIMARY KEY,
+   created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   -- direct_key is "<lower user id>:<higher user id>" for 1:1
+   -- conversations, so that two users always share the same one. Groups
+   -- have none.
+   direct_key TEXT UNIQUE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   last_message_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS conversation_participants(
+   conversation_id INT NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   joined_at TIMESTAMP NOT NULL DEFAULT now(),
+   -- The participant has read every message up to last_read_message_id.
+   last_read_message_id BIGINT NOT NULL DEFAULT 0,
+   last_read_at TIMESTAMP,
+   PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversation_participants_user_id_idx ON conversation_participants (user_id);
+
+CREATE TABLE IF NOT EXISTS messages(
+   id BIGSERIAL PRIMARY KEY,
+   conversation_id INT NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+   sender_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   body TEXT NOT NULL,
+   created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_id_idx ON messages (conversation_id, id);
+
+-- Users who block each other cannot be in a conversation together, nor
+-- write into one they already share. Rows added by the same statement are
+-- visible here, so adding several participants at once checks every pair.
+CREATE OR REPLACE FUNCTION reject_blocked_participant()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF EXISTS (
+      SELECT 1 FROM conversation_participants p
+      WHERE p.conversation_id = NEW.conversation_id
+         AND p.user_id <> NEW.user_id
+         AND users_blocked(p.user_id, NEW.user_id)
+   ) THEN
+      RAISE EXCEPTION 'user % blocks or is blocked by a participant of conversation %', NEW.user_id, NEW.conversation_id
+         USING ERRCODE = 'SM001';
+   END IF;
+   RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION reject_blocked_message()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   IF EXISTS (
+      SELECT 1 FROM conversation_participants p
+      WHERE p.conversation_id = NEW.conversation_id
+         AND p.user_id <> NEW.sender_id
+         AND users_blocked(p.user_id, NEW.sender_id)
+   ) THEN
+      RAISE EXCEPTION 'user % blocks or is blocked by a participant of conversation %', NEW.sender_id, NEW.conversation_id
+         USING ERRCODE = 'SM001';
+   END IF;
+   RETURN NEW;
+END
+$$;
+
+-- Every participant, the sender included for their other devices, gets an
+-- event of their own.
+CREATE OR REPLACE FUNCTION stream_message()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   INSERT INTO stream_events (kind, recipient_id, actor_id, payload)
+   SELECT 'message', p.user_id, NEW.sender_id, jsonb_build_object(
+      'id', NEW.id,
+      'conversation_id', NEW.conversation_id,
+      'sender_id', NEW.sender_id,
+      'body', NEW.body,
+      'created_at', NEW.created_at
+   )
+   FROM conversation_participants p
+   WHERE p.conversation_id = NEW.conversation_id;
+   RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER conversation_participants_reject_blocked BEFORE INSERT ON conversation_participants
+   FOR EACH ROW EXECUTE FUNCTION reject_blocked_participant();
+CREATE TRIGGER messages_reject_blocked BEFORE INSERT ON messages
+   FOR EACH ROW EXECUTE FUNCTION reject_blocked_message();
+CREATE TRIGGER messages_stream_event AFTER INSERT ON messages
+   FOR EACH ROW EXECUTE FUNCTION stream_message();
+
+-- Events with a recipient, notifications and messages, go to that user only.
+CREATE OR REPLACE FUNCTION stream_event_visible_to(e stream_events, viewer_id INT)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT CASE
+      WHEN e.recipient_id IS NOT NULL THEN e.recipient_id = viewer_id
+      WHEN e.kind = 'post' THEN (
+         e.actor_id = viewer_id OR EXISTS (
+            SELECT 1 FROM user_follows f
+            WHERE f.followee_id = e.actor_id AND f.follower_id = viewer_id
+         )
+      ) AND post_visible_to(e.post_id, viewer_id, true)
+      ELSE post_visible_to(e.post_id, viewer_id, false)
+   END
+$$;
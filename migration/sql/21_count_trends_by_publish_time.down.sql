@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS posts_listed_created_at_idx;
+CREATE INDEX IF NOT EXISTS post_tags_created_at_idx ON post_tags (created_at);
@@ -0,0 +1,5 @@
+-- Trending counts tags by the publish time of listed posts rather than by
+-- when a post_tags row was written.
+DROP INDEX IF EXISTS post_tags_created_at_idx;
+CREATE INDEX IF NOT EXISTS posts_listed_created_at_idx ON posts (created_at)
+   WHERE status = 'published' AND visibility = 'public' AND in_reply_to_id IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE reactions DROP CONSTRAINT IF EXISTS reactions_kind_check;
+ALTER TABLE reactions DROP CONSTRAINT IF EXISTS reactions_pkey;
+ALTER TABLE reactions ADD PRIMARY KEY (post_id, user_id, kind);
@@ -0,0 +1,10 @@
+-- A user has one reaction on a post, so reaction counts count people.
+-- Reacting again changes the kind of the existing reaction.
+DELETE FROM reactions r
+USING reactions newer
+WHERE newer.post_id = r.post_id AND newer.user_id = r.user_id
+  AND (newer.created_at, newer.kind) > (r.created_at, r.kind);
+ALTER TABLE reactions DROP CONSTRAINT IF EXISTS reactions_pkey;
+ALTER TABLE reactions ADD PRIMARY KEY (post_id, user_id);
+ALTER TABLE reactions ADD CONSTRAINT reactions_kind_check
+   CHECK (kind IN ('like', 'love', 'laugh', 'wow', 'sad', 'angry'));
@@ -0,0 +1,17 @@
+-- Events recorded meanwhile keep their ids only.
+CREATE OR REPLACE FUNCTION stream_message()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   INSERT INTO stream_events (kind, recipient_id, actor_id, payload)
+   SELECT 'message', p.user_id, NEW.sender_id, jsonb_build_object(
+      'id', NEW.id,
+      'conversation_id', NEW.conversation_id,
+      'sender_id', NEW.sender_id,
+      'body', NEW.body,
+      'created_at', NEW.created_at
+   )
+   FROM conversation_participants p
+   WHERE p.conversation_id = NEW.conversation_id;
+   RETURN NULL;
+END
+$$;
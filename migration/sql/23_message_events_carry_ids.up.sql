@@ -0,0 +1,21 @@
+-- Message events name the message instead of carrying it. Stream payloads sit
+-- in stream_events for replay, so the body stays behind GET
+-- /conversations/{id}/messages/{message_id} and its participant and block
+-- checks.
+CREATE OR REPLACE FUNCTION stream_message()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+   INSERT INTO stream_events (kind, recipient_id, actor_id, payload)
+   SELECT 'message', p.user_id, NEW.sender_id, jsonb_build_object(
+      'id', NEW.id,
+      'conversation_id', NEW.conversation_id,
+      'sender_id', NEW.sender_id
+   )
+   FROM conversation_participants p
+   WHERE p.conversation_id = NEW.conversation_id;
+   RETURN NULL;
+END
+$$;
+
+UPDATE stream_events SET payload = payload - 'body' - 'created_at'
+WHERE kind = 'message';
@@ -0,0 +1,27 @@
+-- A mute hides the muted user's own posts, not replies others write in the
+-- muted user's threads. Only blocks still cascade from the thread root.
+CREATE OR REPLACE FUNCTION post_visible_to(target_id INT, viewer_id INT, listing BOOLEAN)
+RETURNS BOOLEAN LANGUAGE sql STABLE AS $$
+   SELECT coalesce(bool_and(
+      p.userid = viewer_id
+      OR (
+         p.status = 'published'
+         AND NOT users_blocked(p.userid, viewer_id)
+         AND (p.id <> target_id OR NOT EXISTS (
+            SELECT 1 FROM user_mutes m
+            WHERE m.muter_id = viewer_id AND m.muted_id = p.userid
+         ))
+         AND (
+            p.visibility = 'public'
+            OR (p.visibility = 'unlisted' AND NOT (listing AND p.id = target_id))
+            OR (p.visibility = 'followers' AND EXISTS (
+               SELECT 1 FROM user_follows f
+               WHERE f.followee_id = p.userid AND f.follower_id = viewer_id
+            ))
+         )
+      )
+   ), false)
+   FROM posts p
+   WHERE p.id = target_id
+      OR p.id = (SELECT t.root_id FROM posts t WHERE t.id = target_id)
+$$;
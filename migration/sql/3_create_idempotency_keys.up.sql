@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+   key VARCHAR NOT NULL,
+   scope VARCHAR NOT NULL,
+   request_hash VARCHAR NOT NULL,
+   status_code INT NOT NULL,
+   content_type VARCHAR NOT NULL,
+   response_body BYTEA NOT NULL,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   expires_at TIMESTAMP NOT NULL,
+   PRIMARY KEY (key, scope)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys(expires_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS post_tags_postid_idx;
+DROP INDEX IF EXISTS posts_search_vector_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE posts ALTER COLUMN created_at DROP DEFAULT;
@@ -0,0 +1,11 @@
+ALTER TABLE posts ALTER COLUMN created_at SET DEFAULT now();
+UPDATE posts SET created_at = now() WHERE created_at IS NULL;
+
+ALTER TABLE posts ADD COLUMN search_vector tsvector
+   GENERATED ALWAYS AS (
+      setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+      setweight(to_tsvector('english', coalesce(description, '')), 'B')
+   ) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS post_tags_postid_idx ON post_tags (postid);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS post_tags_tagid_idx;
+DROP INDEX IF EXISTS tags_tagname_trgm_idx;
+DROP INDEX IF EXISTS users_fullname_trgm_idx;
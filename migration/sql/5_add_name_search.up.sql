@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS users_fullname_trgm_idx ON users USING GIN (fullname gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS tags_tagname_trgm_idx ON tags USING GIN (tagname gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS post_tags_tagid_idx ON post_tags (tagid);
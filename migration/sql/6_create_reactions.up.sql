@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS reactions(
+   post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   kind VARCHAR NOT NULL,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (post_id, user_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS posts_userid_idx ON posts (userid);
+CREATE INDEX IF NOT EXISTS posts_created_at_idx ON posts (created_at DESC, id DESC);
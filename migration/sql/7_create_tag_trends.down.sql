@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tag_trends;
+DROP INDEX IF EXISTS post_tags_created_at_idx;
+ALTER TABLE post_tags ALTER COLUMN created_at DROP DEFAULT;
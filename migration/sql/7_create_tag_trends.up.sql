@@ -0,0 +1,17 @@
+ALTER TABLE post_tags ALTER COLUMN created_at SET DEFAULT now();
+UPDATE post_tags pt SET created_at = p.created_at
+FROM posts p
+WHERE p.id = pt.postid AND pt.created_at IS NULL;
+CREATE INDEX IF NOT EXISTS post_tags_created_at_idx ON post_tags (created_at);
+
+CREATE TABLE IF NOT EXISTS tag_trends(
+   tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+   window_minutes INT NOT NULL,
+   recent_count INT NOT NULL,
+   baseline_count INT NOT NULL,
+   score DOUBLE PRECISION NOT NULL,
+   computed_at TIMESTAMP NOT NULL,
+   PRIMARY KEY (tag_id, window_minutes)
+);
+
+CREATE INDEX IF NOT EXISTS tag_trends_window_score_idx ON tag_trends (window_minutes, score DESC);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS tag_follows(
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   PRIMARY KEY (user_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS tag_follows_tag_id_idx ON tag_follows (tag_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS collections(
+   id SERIAL PRIMARY KEY,
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   name VARCHAR(100) NOT NULL,
+   created_at TIMESTAMP NOT NULL DEFAULT now(),
+   UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bookmarks(
+   id SERIAL PRIMARY KEY,
+   user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+   collection_id INT REFERENCES collections(id) ON DELETE CASCADE,
+   created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- A post is saved at most once outside any collection and once per collection.
+CREATE UNIQUE INDEX IF NOT EXISTS bookmarks_user_post_collection_key
+ON bookmarks (user_id, post_id, (COALESCE(collection_id, 0)));
+CREATE INDEX IF NOT EXISTS bookmarks_collection_id_idx ON bookmarks (collection_id);
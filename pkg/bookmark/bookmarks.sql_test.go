@@ -0,0 +1,644 @@
+package bookmark
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	reflect "reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := NewMockDBTX(ctrl)
+
+	type args struct {
+		db DBTX
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Queries
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				db: dbMock,
+			},
+			want: &Queries{
+				db: dbMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	txMock := sql.Tx{}
+
+	type args struct {
+		tx *sql.Tx
+	}
+	tests := []struct {
+		name     string
+		args     args
+		initMock func() *Queries
+		want     *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				tx: &txMock,
+			},
+			initMock: func() *Queries {
+				return &Queries{
+					db: &txMock,
+				}
+			},
+			want: &Queries{
+				db: &txMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if got := p.WithTx(tt.args.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddBookmark(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg AddBookmarkParams
+	}
+
+	q := `-- name: AddBookmark :execrows
+	INSERT INTO bookmarks (user_id, post_id, collection_id)
+	SELECT u.id, p.id, $1::int
+	FROM users u, posts p
+	WHERE u.id = $2 AND p.id = $3
+	  AND ($1::int IS NULL OR EXISTS (
+	    SELECT 1 FROM collections c
+	    WHERE c.id = $1 AND c.user_id = u.id
+	  ))
+	ON CONFLICT (user_id, post_id, (COALESCE(collection_id, 0)))
+	DO UPDATE SET created_at = bookmarks.created_at
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success add bookmark",
+			args: args{
+				ctx: context.Background(),
+				arg: AddBookmarkParams{CollectionID: sql.NullInt32{Int32: 3, Valid: true}, UserID: 1, PostID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "missing user, post or collection saves nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: AddBookmarkParams{CollectionID: sql.NullInt32{Int32: 3, Valid: true}, UserID: 1, PostID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error add bookmark",
+			args: args{
+				ctx: context.Background(),
+				arg: AddBookmarkParams{CollectionID: sql.NullInt32{Int32: 3, Valid: true}, UserID: 1, PostID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1, 2).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.AddBookmark(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddBookmark() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AddBookmark() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CreateCollection(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg CreateCollectionParams
+	}
+
+	q := `-- name: CreateCollection :one
+	INSERT INTO collections (
+	  user_id, name
+	) VALUES (
+	  $1,$2
+	)
+	RETURNING id, user_id, name
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     CreateCollectionRow
+		wantErr  bool
+	}{
+		{
+			name: "success create collection",
+			args: args{
+				ctx: context.Background(),
+				arg: CreateCollectionParams{UserID: 1, Name: "recipes"},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "recipes")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "recipes").WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    CreateCollectionRow{ID: 3, UserID: 1, Name: "recipes"},
+			wantErr: false,
+		},
+		{
+			name: "error create collection",
+			args: args{
+				ctx: context.Background(),
+				arg: CreateCollectionParams{UserID: 1, Name: "recipes"},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "recipes").WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    CreateCollectionRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.CreateCollection(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DeleteCollection(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg DeleteCollectionParams
+	}
+
+	q := `-- name: DeleteCollection :execrows
+	DELETE FROM collections
+	WHERE id = $1 AND user_id = $2
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success delete collection",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteCollectionParams{ID: 3, UserID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "missing collection deletes nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteCollectionParams{ID: 3, UserID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error delete collection",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteCollectionParams{ID: 3, UserID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.DeleteCollection(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DeleteCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetBookmarkedPostIDs(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetBookmarkedPostIDsParams
+	}
+
+	q := `-- name: GetBookmarkedPostIDs :many
+	SELECT DISTINCT post_id FROM bookmarks
+	WHERE user_id = $1 AND post_id = ANY($2::int[])
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []int32
+		wantErr  bool
+	}{
+		{
+			name: "success get bookmarked post ids",
+			args: args{
+				ctx: context.Background(),
+				arg: GetBookmarkedPostIDsParams{UserID: 1, PostIds: []int32{1, 2}},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"post_id"}).AddRow(2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, pq.Array([]int32{1, 2})).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []int32{2},
+			wantErr: false,
+		},
+		{
+			name: "error scan get bookmarked post ids",
+			args: args{
+				ctx: context.Background(),
+				arg: GetBookmarkedPostIDsParams{UserID: 1, PostIds: []int32{1, 2}},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"post_id"}).AddRow("x")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get bookmarked post ids",
+			args: args{
+				ctx: context.Background(),
+				arg: GetBookmarkedPostIDsParams{UserID: 1, PostIds: []int32{1, 2}},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetBookmarkedPostIDs(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBookmarkedPostIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBookmarkedPostIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetCollections(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg int32
+	}
+
+	q := `-- name: GetCollections :many
+	SELECT c.id, c.user_id, c.name, count(b.id) AS post_count
+	FROM collections c LEFT JOIN bookmarks b
+	ON b.collection_id = c.id
+	WHERE c.user_id = $1
+	GROUP BY c.id
+	ORDER BY c.name, c.id
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetCollectionsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get collections",
+			args: args{
+				ctx: context.Background(),
+				arg: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "post_count"}).AddRow(3, 1, "recipes", 4)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetCollectionsRow{{ID: 3, UserID: 1, Name: "recipes", PostCount: 4}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get collections",
+			args: args{
+				ctx: context.Background(),
+				arg: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "post_count"}).AddRow("x", 1, "recipes", 4)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get collections",
+			args: args{
+				ctx: context.Background(),
+				arg: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetCollections(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCollections() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCollections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RemoveBookmark(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg RemoveBookmarkParams
+	}
+
+	q := `-- name: RemoveBookmark :exec
+	DELETE FROM bookmarks
+	WHERE user_id = $1 AND post_id = $2
+	  AND ($3::int IS NULL OR collection_id = $3)
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success remove bookmark",
+			args: args{
+				ctx: context.Background(),
+				arg: RemoveBookmarkParams{UserID: 1, PostID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error remove bookmark",
+			args: args{
+				ctx: context.Background(),
+				arg: RemoveBookmarkParams{UserID: 1, PostID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.RemoveBookmark(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RemoveBookmark() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_RenameCollection(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg RenameCollectionParams
+	}
+
+	q := `-- name: RenameCollection :one
+	UPDATE collections
+	  set name = $1
+	WHERE id = $2 AND user_id = $3
+	RETURNING id, user_id, name
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     RenameCollectionRow
+		wantErr  bool
+	}{
+		{
+			name: "success rename collection",
+			args: args{
+				ctx: context.Background(),
+				arg: RenameCollectionParams{Name: "desserts", ID: 3, UserID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "desserts")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("desserts", 3, 1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    RenameCollectionRow{ID: 3, UserID: 1, Name: "desserts"},
+			wantErr: false,
+		},
+		{
+			name: "error rename collection",
+			args: args{
+				ctx: context.Background(),
+				arg: RenameCollectionParams{Name: "desserts", ID: 3, UserID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("desserts", 3, 1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    RenameCollectionRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.RenameCollection(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenameCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RenameCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
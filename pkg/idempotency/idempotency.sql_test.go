@@ -0,0 +1,363 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	reflect "reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := NewMockDBTX(ctrl)
+
+	type args struct {
+		db DBTX
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Queries
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				db: dbMock,
+			},
+			want: &Queries{
+				db: dbMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	txMock := sql.Tx{}
+
+	type args struct {
+		tx *sql.Tx
+	}
+	tests := []struct {
+		name     string
+		args     args
+		initMock func() *Queries
+		want     *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				tx: &txMock,
+			},
+			initMock: func() *Queries {
+				return &Queries{
+					db: &txMock,
+				}
+			},
+			want: &Queries{
+				db: &txMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if got := p.WithTx(tt.args.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LockIdempotencyKey(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		lockKey string
+	}
+
+	q := `-- name: LockIdempotencyKey :exec
+		SELECT pg_advisory_lock(hashtextextended($1::text, 0))
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success lock",
+			args: args{
+				ctx:     context.Background(),
+				lockKey: "POST /post:abc",
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error lock",
+			args: args{
+				ctx:     context.Background(),
+				lockKey: "POST /post:abc",
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("POST /post:abc").WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.LockIdempotencyKey(tt.args.ctx, tt.args.lockKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LockIdempotencyKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_UnlockIdempotencyKey(t *testing.T) {
+	q := `-- name: UnlockIdempotencyKey :exec
+		SELECT pg_advisory_unlock(hashtextextended($1::text, 0))
+	`
+
+	dbMock, mock, _ := sqlmock.New()
+	mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	p := &Queries{
+		db: dbMock,
+	}
+	if err := p.UnlockIdempotencyKey(context.Background(), "POST /post:abc"); err != nil {
+		t.Errorf("UnlockIdempotencyKey() error = %v", err)
+	}
+}
+
+func Test_GetIdempotencyKey(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetIdempotencyKeyParams
+	}
+
+	q := `-- name: GetIdempotencyKey :one
+		SELECT key, scope, request_hash, status_code, content_type, response_body FROM idempotency_keys
+		WHERE key = $1 AND scope = $2 AND expires_at > now()
+		LIMIT 1
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     GetIdempotencyKeyRow
+		wantErr  bool
+	}{
+		{
+			name: "success get idempotency key",
+			args: args{
+				ctx: context.Background(),
+				arg: GetIdempotencyKeyParams{
+					Key:   "abc",
+					Scope: "POST /post",
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"key", "scope", "request_hash", "status_code", "content_type", "response_body"}).
+					AddRow("abc", "POST /post", "hash", 201, "application/json", []byte(`{"status":"Success"}`))
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("abc", "POST /post").WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: GetIdempotencyKeyRow{
+				Key:          "abc",
+				Scope:        "POST /post",
+				RequestHash:  "hash",
+				StatusCode:   201,
+				ContentType:  "application/json",
+				ResponseBody: []byte(`{"status":"Success"}`),
+			},
+			wantErr: false,
+		},
+		{
+			name: "error no rows",
+			args: args{
+				ctx: context.Background(),
+				arg: GetIdempotencyKeyParams{
+					Key:   "abc",
+					Scope: "POST /post",
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("abc", "POST /post").WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetIdempotencyKeyRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetIdempotencyKey(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetIdempotencyKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetIdempotencyKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SaveIdempotencyKey(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg SaveIdempotencyKeyParams
+	}
+
+	arg := SaveIdempotencyKeyParams{
+		Key:          "abc",
+		Scope:        "POST /post",
+		RequestHash:  "hash",
+		StatusCode:   201,
+		ContentType:  "application/json",
+		ResponseBody: []byte(`{"status":"Success"}`),
+		TtlHours:     24,
+	}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success save idempotency key",
+			args: args{
+				ctx: context.Background(),
+				arg: arg,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec("INSERT INTO idempotency_keys").
+					WithArgs("abc", "POST /post", "hash", 201, "application/json", []byte(`{"status":"Success"}`), 24).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error save idempotency key",
+			args: args{
+				ctx: context.Background(),
+				arg: arg,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec("INSERT INTO idempotency_keys").WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.SaveIdempotencyKey(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SaveIdempotencyKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_DeleteExpiredIdempotencyKeys(t *testing.T) {
+	q := `-- name: DeleteExpiredIdempotencyKeys :execrows
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= now()
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success delete expired keys",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnResult(sqlmock.NewResult(0, 3))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "error delete expired keys",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.DeleteExpiredIdempotencyKeys(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteExpiredIdempotencyKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DeleteExpiredIdempotencyKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
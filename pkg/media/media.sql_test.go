@@ -0,0 +1,450 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	reflect "reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := NewMockDBTX(ctrl)
+
+	type args struct {
+		db DBTX
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Queries
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				db: dbMock,
+			},
+			want: &Queries{
+				db: dbMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	txMock := sql.Tx{}
+
+	type args struct {
+		tx *sql.Tx
+	}
+	tests := []struct {
+		name     string
+		args     args
+		initMock func() *Queries
+		want     *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				tx: &txMock,
+			},
+			initMock: func() *Queries {
+				return &Queries{
+					db: &txMock,
+				}
+			},
+			want: &Queries{
+				db: &txMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if got := p.WithTx(tt.args.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AttachPostMedia(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg AttachPostMediaParams
+	}
+
+	q := `-- name: AttachPostMedia :execrows
+	INSERT INTO post_media (post_id, media_id, position)
+	SELECT p.id, m.id, $1::int
+	FROM posts p JOIN media m ON m.user_id = p.userid
+	WHERE p.id = $2 AND m.id = $3
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success attach media",
+			args: args{
+				ctx: context.Background(),
+				arg: AttachPostMediaParams{Position: 0, PostID: 2, MediaID: 5},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(0, 2, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "media of another user attaches nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: AttachPostMediaParams{Position: 1, PostID: 2, MediaID: 6},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2, 6).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error attach media",
+			args: args{
+				ctx: context.Background(),
+				arg: AttachPostMediaParams{Position: 0, PostID: 2, MediaID: 5},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.AttachPostMedia(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AttachPostMedia() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AttachPostMedia() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CreateMedia(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg CreateMediaParams
+	}
+
+	q := `-- name: CreateMedia :one
+	INSERT INTO media (
+	  user_id, sha256, content_type, size, width, height, thumbnail_content_type
+	) VALUES (
+	  $1, $2, $3, $4, $5, $6, $7
+	)
+	ON CONFLICT (user_id, sha256) DO UPDATE SET sha256 = EXCLUDED.sha256
+	RETURNING id, user_id, sha256, content_type, size, width, height, thumbnail_content_type, created_at
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cols := []string{"id", "user_id", "sha256", "content_type", "size", "width", "height", "thumbnail_content_type", "created_at"}
+	arg := CreateMediaParams{
+		UserID:               1,
+		Sha256:               "ab12",
+		ContentType:          "image/png",
+		Size:                 2048,
+		Width:                sql.NullInt32{Int32: 640, Valid: true},
+		Height:               sql.NullInt32{Int32: 480, Valid: true},
+		ThumbnailContentType: sql.NullString{String: "image/png", Valid: true},
+	}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     Medium
+		wantErr  bool
+	}{
+		{
+			name: "success create media",
+			args: args{
+				ctx: context.Background(),
+				arg: arg,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(cols).AddRow(4, 1, "ab12", "image/png", 2048, 640, 480, "image/png", createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "ab12", "image/png", 2048, 640, 480, "image/png").WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: Medium{
+				ID:                   4,
+				UserID:               1,
+				Sha256:               "ab12",
+				ContentType:          "image/png",
+				Size:                 2048,
+				Width:                sql.NullInt32{Int32: 640, Valid: true},
+				Height:               sql.NullInt32{Int32: 480, Valid: true},
+				ThumbnailContentType: sql.NullString{String: "image/png", Valid: true},
+				CreatedAt:            createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error create media",
+			args: args{
+				ctx: context.Background(),
+				arg: arg,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Medium{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.CreateMedia(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateMedia() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateMedia() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPostMedia(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg int32
+	}
+
+	q := `-- name: GetPostMedia :many
+	SELECT m.id, m.content_type, m.size, m.width, m.height, m.thumbnail_content_type
+	FROM post_media pm JOIN media m ON m.id = pm.media_id
+	WHERE pm.post_id = $1
+	ORDER BY pm.position
+	`
+	cols := []string{"id", "content_type", "size", "width", "height", "thumbnail_content_type"}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetPostMediaRow
+		wantErr  bool
+	}{
+		{
+			name: "success get post media",
+			args: args{
+				ctx: context.Background(),
+				arg: 2,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(cols).
+					AddRow(4, "image/png", 2048, 640, 480, "image/png").
+					AddRow(5, "application/pdf", 4096, nil, nil, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetPostMediaRow{
+				{
+					ID:                   4,
+					ContentType:          "image/png",
+					Size:                 2048,
+					Width:                sql.NullInt32{Int32: 640, Valid: true},
+					Height:               sql.NullInt32{Int32: 480, Valid: true},
+					ThumbnailContentType: sql.NullString{String: "image/png", Valid: true},
+				},
+				{ID: 5, ContentType: "application/pdf", Size: 4096},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan get post media",
+			args: args{
+				ctx: context.Background(),
+				arg: 2,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(cols).AddRow("x", "image/png", 2048, 640, 480, "image/png")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get post media",
+			args: args{
+				ctx: context.Background(),
+				arg: 2,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetPostMedia(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPostMedia() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostMedia() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetVisibleMedia(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetVisibleMediaParams
+	}
+
+	q := `-- name: GetVisibleMedia :one
+	SELECT m.id, m.user_id, m.sha256, m.content_type, m.size, m.width, m.height, m.thumbnail_content_type, m.created_at FROM media m
+	WHERE m.id = $1
+	  AND (m.user_id = $2 OR EXISTS (
+	    SELECT 1 FROM post_media pm
+	    WHERE pm.media_id = m.id AND post_visible_to(pm.post_id, $2, false)
+	  ) OR EXISTS (
+	    SELECT 1 FROM users u WHERE u.avatar_media_id = m.id
+	  ))
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cols := []string{"id", "user_id", "sha256", "content_type", "size", "width", "height", "thumbnail_content_type", "created_at"}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     Medium
+		wantErr  bool
+	}{
+		{
+			name: "success get visible media",
+			args: args{
+				ctx: context.Background(),
+				arg: GetVisibleMediaParams{ID: 4, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(cols).AddRow(4, 1, "ab12", "application/pdf", 4096, nil, nil, nil, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(4, 3).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: Medium{
+				ID:          4,
+				UserID:      1,
+				Sha256:      "ab12",
+				ContentType: "application/pdf",
+				Size:        4096,
+				CreatedAt:   createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "hidden media",
+			args: args{
+				ctx: context.Background(),
+				arg: GetVisibleMediaParams{ID: 4, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(4, 3).WillReturnRows(sqlmock.NewRows(cols))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Medium{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetVisibleMedia(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetVisibleMedia() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetVisibleMedia() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,729 @@
+package message
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	reflect "reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := NewMockDBTX(ctrl)
+
+	type args struct {
+		db DBTX
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Queries
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				db: dbMock,
+			},
+			want: &Queries{
+				db: dbMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	txMock := sql.Tx{}
+
+	type args struct {
+		tx *sql.Tx
+	}
+	tests := []struct {
+		name     string
+		args     args
+		initMock func() *Queries
+		want     *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				tx: &txMock,
+			},
+			initMock: func() *Queries {
+				return &Queries{
+					db: &txMock,
+				}
+			},
+			want: &Queries{
+				db: &txMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if got := p.WithTx(tt.args.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddConversationParticipants(t *testing.T) {
+	q := `-- name: AddConversationParticipants :exec
+	INSERT INTO conversation_participants (conversation_id, user_id)
+	SELECT $1, u.id
+	FROM unnest($2::int[]) AS u(id)
+	ON CONFLICT (conversation_id, user_id) DO NOTHING
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success add conversation participants",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, "{1,2}").WillReturnResult(sqlmock.NewResult(0, 2))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error add conversation participants",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.AddConversationParticipants(context.Background(), AddConversationParticipantsParams{ConversationID: 3, UserIds: []int32{1, 2}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddConversationParticipants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_CreateConversation(t *testing.T) {
+	q := `-- name: CreateConversation :one
+	INSERT INTO conversations (created_by, direct_key)
+	VALUES ($1, $2)
+	ON CONFLICT (direct_key) DO UPDATE SET direct_key = EXCLUDED.direct_key
+	RETURNING id, created_by, direct_key, created_at, last_message_at
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     Conversation
+		wantErr  bool
+	}{
+		{
+			name: "success create conversation",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "1:2").WillReturnRows(
+					sqlmock.NewRows([]string{"id", "created_by", "direct_key", "created_at", "last_message_at"}).
+						AddRow(3, 1, "1:2", createdAt, createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: Conversation{
+				ID:            3,
+				CreatedBy:     1,
+				DirectKey:     sql.NullString{String: "1:2", Valid: true},
+				CreatedAt:     createdAt,
+				LastMessageAt: createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error create conversation",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Conversation{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.CreateConversation(context.Background(), CreateConversationParams{
+				CreatedBy: 1,
+				DirectKey: sql.NullString{String: "1:2", Valid: true},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateConversation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateConversation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetConversation(t *testing.T) {
+	q := `-- name: GetConversation :one
+	SELECT c.id, c.created_by, c.direct_key IS NOT NULL AS direct, c.created_at, c.last_message_at,
+	  (
+	    SELECT count(*) FROM messages m
+	    WHERE m.conversation_id = c.id
+	      AND m.id > p.last_read_message_id
+	      AND m.sender_id <> $1
+	      AND NOT users_blocked(m.sender_id, $1)
+	  )::int AS unread_count
+	FROM conversations c
+	JOIN conversation_participants p ON p.conversation_id = c.id AND p.user_id = $1
+	WHERE c.id = $2
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     GetConversationRow
+		wantErr  bool
+	}{
+		{
+			name: "success get conversation",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 3).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "created_by", "direct", "created_at", "last_message_at", "unread_count"}).
+						AddRow(3, 2, true, createdAt, createdAt, 4))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: GetConversationRow{
+				ID:            3,
+				CreatedBy:     2,
+				Direct:        true,
+				CreatedAt:     createdAt,
+				LastMessageAt: createdAt,
+				UnreadCount:   4,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get conversation",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetConversationRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetConversation(context.Background(), GetConversationParams{UserID: 1, ID: 3})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetConversation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConversation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetConversationParticipants(t *testing.T) {
+	q := `-- name: GetConversationParticipants :many
+	SELECT p.conversation_id, u.id, u.fullname, u.handle, p.last_read_message_id, p.last_read_at
+	FROM conversation_participants p
+	JOIN users u ON u.id = p.user_id
+	WHERE p.conversation_id = ANY($1::int[])
+	ORDER BY p.conversation_id, p.joined_at, u.id
+	`
+	readAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []GetConversationParticipantsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get conversation participants",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("{3}").WillReturnRows(
+					sqlmock.NewRows([]string{"conversation_id", "id", "fullname", "handle", "last_read_message_id", "last_read_at"}).
+						AddRow(3, 1, "Ana", "ana", 10, readAt).
+						AddRow(3, 2, "Budi", nil, 0, nil))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetConversationParticipantsRow{
+				{ConversationID: 3, ID: 1, Fullname: "Ana", Handle: sql.NullString{String: "ana", Valid: true}, LastReadMessageID: 10, LastReadAt: sql.NullTime{Time: readAt, Valid: true}},
+				{ConversationID: 3, ID: 2, Fullname: "Budi"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get conversation participants",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetConversationParticipants(context.Background(), []int32{3})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetConversationParticipants() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConversationParticipants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetConversations(t *testing.T) {
+	q := `-- name: GetConversations :many
+	SELECT c.id, c.created_by, c.direct_key IS NOT NULL AS direct, c.created_at, c.last_message_at,
+	  (
+	    SELECT count(*) FROM messages m
+	    WHERE m.conversation_id = c.id
+	      AND m.id > p.last_read_message_id
+	      AND m.sender_id <> $1
+	      AND NOT users_blocked(m.sender_id, $1)
+	  )::int AS unread_count
+	FROM conversations c
+	JOIN conversation_participants p ON p.conversation_id = c.id AND p.user_id = $1
+	ORDER BY c.last_message_at DESC, c.id DESC
+	LIMIT $2 OFFSET $3
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []GetConversationsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get conversations",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 20, 0).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "created_by", "direct", "created_at", "last_message_at", "unread_count"}).
+						AddRow(4, 1, false, createdAt, createdAt.Add(time.Hour), 0).
+						AddRow(3, 2, true, createdAt, createdAt, 2))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetConversationsRow{
+				{ID: 4, CreatedBy: 1, CreatedAt: createdAt, LastMessageAt: createdAt.Add(time.Hour)},
+				{ID: 3, CreatedBy: 2, Direct: true, CreatedAt: createdAt, LastMessageAt: createdAt, UnreadCount: 2},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get conversations",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetConversations(context.Background(), GetConversationsParams{UserID: 1, Limit: 20, Offset: 0})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetConversations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConversations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetLastMessages(t *testing.T) {
+	q := `-- name: GetLastMessages :many
+	SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at
+	FROM unnest($1::int[]) AS c(id)
+	CROSS JOIN LATERAL (
+	  SELECT lm.id, lm.conversation_id, lm.sender_id, lm.body, lm.created_at
+	  FROM messages lm
+	  WHERE lm.conversation_id = c.id
+	    AND NOT users_blocked(lm.sender_id, $2::int)
+	  ORDER BY lm.id DESC
+	  LIMIT 1
+	) m
+	ORDER BY m.conversation_id
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []Message
+		wantErr  bool
+	}{
+		{
+			name: "success get last messages",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("{3,4}", 1).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "conversation_id", "sender_id", "body", "created_at"}).
+						AddRow(10, 3, 2, "hi", createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []Message{{ID: 10, ConversationID: 3, SenderID: 2, Body: "hi", CreatedAt: createdAt}},
+			wantErr: false,
+		},
+		{
+			name: "error get last messages",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetLastMessages(context.Background(), GetLastMessagesParams{ConversationIds: []int32{3, 4}, UserID: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetLastMessages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLastMessages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetMessage(t *testing.T) {
+	q := `-- name: GetMessage :one
+	SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at
+	FROM messages m
+	JOIN conversation_participants p ON p.conversation_id = m.conversation_id AND p.user_id = $1
+	WHERE m.id = $2 AND m.conversation_id = $3
+	  AND NOT users_blocked(m.sender_id, $1::int)
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     Message
+		wantErr  bool
+	}{
+		{
+			name: "success get message",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 10, 3).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "conversation_id", "sender_id", "body", "created_at"}).
+						AddRow(10, 3, 2, "hi", createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Message{ID: 10, ConversationID: 3, SenderID: 2, Body: "hi", CreatedAt: createdAt},
+			wantErr: false,
+		},
+		{
+			name: "error get message",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Message{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetMessage(context.Background(), GetMessageParams{UserID: 1, ID: 10, ConversationID: 3})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetMessages(t *testing.T) {
+	q := `-- name: GetMessages :many
+	SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at
+	FROM messages m
+	WHERE m.conversation_id = $1
+	  AND ($2::bigint = 0 OR m.id < $2::bigint)
+	  AND NOT users_blocked(m.sender_id, $3::int)
+	ORDER BY m.id DESC
+	LIMIT $4
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []Message
+		wantErr  bool
+	}{
+		{
+			name: "success get messages",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(3, 12, 1, 2).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "conversation_id", "sender_id", "body", "created_at"}).
+						AddRow(11, 3, 1, "see you", createdAt.Add(time.Minute)).
+						AddRow(10, 3, 2, "hi", createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []Message{
+				{ID: 11, ConversationID: 3, SenderID: 1, Body: "see you", CreatedAt: createdAt.Add(time.Minute)},
+				{ID: 10, ConversationID: 3, SenderID: 2, Body: "hi", CreatedAt: createdAt},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get messages",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetMessages(context.Background(), GetMessagesParams{ConversationID: 3, BeforeID: 12, UserID: 1, Limit: 2})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMessages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMessages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarkConversationRead(t *testing.T) {
+	q := `-- name: MarkConversationRead :one
+	UPDATE conversation_participants p
+	  set last_read_message_id = greatest(p.last_read_message_id, (
+	    SELECT coalesce(max(m.id), 0) FROM messages m
+	    WHERE m.conversation_id = p.conversation_id
+	      AND ($1::bigint = 0 OR m.id <= $1::bigint)
+	  )),
+	  last_read_at = now()
+	WHERE p.conversation_id = $2 AND p.user_id = $3
+	RETURNING p.last_read_message_id, p.last_read_at
+	`
+	readAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     MarkConversationReadRow
+		wantErr  bool
+	}{
+		{
+			name: "success mark conversation read",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(0, 3, 1).WillReturnRows(
+					sqlmock.NewRows([]string{"last_read_message_id", "last_read_at"}).AddRow(11, readAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    MarkConversationReadRow{LastReadMessageID: 11, LastReadAt: sql.NullTime{Time: readAt, Valid: true}},
+			wantErr: false,
+		},
+		{
+			name: "error mark conversation read",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    MarkConversationReadRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.MarkConversationRead(context.Background(), MarkConversationReadParams{ConversationID: 3, UserID: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkConversationRead() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarkConversationRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SendMessage(t *testing.T) {
+	q := `-- name: SendMessage :one
+	WITH sent AS (
+	  INSERT INTO messages (conversation_id, sender_id, body)
+	  SELECT p.conversation_id, p.user_id, $1
+	  FROM conversation_participants p
+	  WHERE p.conversation_id = $2 AND p.user_id = $3
+	  RETURNING id, conversation_id, sender_id, body, created_at
+	), touched AS (
+	  UPDATE conversations c
+	    set last_message_at = sent.created_at
+	  FROM sent
+	  WHERE c.id = sent.conversation_id
+	), marked AS (
+	  UPDATE conversation_participants p
+	    set last_read_message_id = sent.id, last_read_at = sent.created_at
+	  FROM sent
+	  WHERE p.conversation_id = sent.conversation_id AND p.user_id = sent.sender_id
+	)
+	SELECT id, conversation_id, sender_id, body, created_at FROM sent
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     Message
+		wantErr  bool
+	}{
+		{
+			name: "success send message",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("hi", 3, 1).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "conversation_id", "sender_id", "body", "created_at"}).
+						AddRow(10, 3, 1, "hi", createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Message{ID: 10, ConversationID: 3, SenderID: 1, Body: "hi", CreatedAt: createdAt},
+			wantErr: false,
+		},
+		{
+			name: "error send message",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    Message{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.SendMessage(context.Background(), SendMessageParams{Body: "hi", ConversationID: 3, SenderID: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SendMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,683 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	reflect "reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := NewMockDBTX(ctrl)
+
+	type args struct {
+		db DBTX
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Queries
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				db: dbMock,
+			},
+			want: &Queries{
+				db: dbMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	txMock := sql.Tx{}
+
+	type args struct {
+		tx *sql.Tx
+	}
+	tests := []struct {
+		name     string
+		args     args
+		initMock func() *Queries
+		want     *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				tx: &txMock,
+			},
+			initMock: func() *Queries {
+				return &Queries{
+					db: &txMock,
+				}
+			},
+			want: &Queries{
+				db: &txMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if got := p.WithTx(tt.args.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddNotificationActor(t *testing.T) {
+	q := `-- name: AddNotificationActor :exec
+	INSERT INTO notification_actors (notification_id, actor_id, created_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (notification_id, actor_id) DO UPDATE SET
+	  created_at = greatest(notification_actors.created_at, EXCLUDED.created_at)
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success add notification actor",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 2, createdAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error add notification actor",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.AddNotificationActor(context.Background(), AddNotificationActorParams{NotificationID: 3, ActorID: 2, CreatedAt: createdAt})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddNotificationActor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ClaimNotificationEvents(t *testing.T) {
+	q := `-- name: ClaimNotificationEvents :many
+	SELECT id, kind, recipient_id, actor_id, post_id, created_at
+	FROM notification_events
+	ORDER BY id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []NotificationEvent
+		wantErr  bool
+	}{
+		{
+			name: "success claim notification events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(100).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "kind", "recipient_id", "actor_id", "post_id", "created_at"}).
+						AddRow(1, "follow", 1, 2, nil, createdAt).
+						AddRow(2, "reaction", 1, 3, 7, createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []NotificationEvent{
+				{ID: 1, Kind: NotificationKindFollow, RecipientID: 1, ActorID: 2, CreatedAt: createdAt},
+				{ID: 2, Kind: NotificationKindReaction, RecipientID: 1, ActorID: 3, PostID: sql.NullInt32{Int32: 7, Valid: true}, CreatedAt: createdAt},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error claim notification events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.ClaimNotificationEvents(context.Background(), 100)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClaimNotificationEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClaimNotificationEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DeleteNotificationEvents(t *testing.T) {
+	q := `-- name: DeleteNotificationEvents :exec
+	DELETE FROM notification_events WHERE id = ANY($1::bigint[])
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success delete notification events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("{1,2}").WillReturnResult(sqlmock.NewResult(0, 2))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error delete notification events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.DeleteNotificationEvents(context.Background(), []int64{1, 2})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteNotificationEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetNotificationActors(t *testing.T) {
+	q := `-- name: GetNotificationActors :many
+	SELECT a.notification_id, u.id, u.fullname, u.handle
+	FROM unnest($1::int[]) AS n(id)
+	CROSS JOIN LATERAL (
+	  SELECT na.notification_id, na.actor_id, na.created_at
+	  FROM notification_actors na
+	  WHERE na.notification_id = n.id
+	    AND NOT users_blocked(na.actor_id, $2::int)
+	    AND NOT EXISTS (
+	      SELECT 1 FROM user_mutes m
+	      WHERE m.muter_id = $2::int AND m.muted_id = na.actor_id
+	    )
+	  ORDER BY na.created_at DESC, na.actor_id DESC
+	  LIMIT $3::int
+	) a
+	JOIN users u ON u.id = a.actor_id
+	ORDER BY a.notification_id, a.created_at DESC, a.actor_id DESC
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []GetNotificationActorsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get notification actors",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("{3,4}", 1, 3).WillReturnRows(
+					sqlmock.NewRows([]string{"notification_id", "id", "fullname", "handle"}).
+						AddRow(3, 2, "Ana", "ana").
+						AddRow(4, 5, "Budi", nil))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetNotificationActorsRow{
+				{NotificationID: 3, ID: 2, Fullname: "Ana", Handle: sql.NullString{String: "ana", Valid: true}},
+				{NotificationID: 4, ID: 5, Fullname: "Budi"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get notification actors",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetNotificationActors(context.Background(), GetNotificationActorsParams{
+				NotificationIds: []int32{3, 4},
+				RecipientID:     1,
+				PerNotification: 3,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNotificationActors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNotificationActors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetNotificationPreferences(t *testing.T) {
+	q := `-- name: GetNotificationPreferences :many
+	SELECT kind, enabled FROM notification_preferences
+	WHERE user_id = $1
+	ORDER BY kind
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []GetNotificationPreferencesRow
+		wantErr  bool
+	}{
+		{
+			name: "success get notification preferences",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(
+					sqlmock.NewRows([]string{"kind", "enabled"}).AddRow("reaction", false))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetNotificationPreferencesRow{{Kind: NotificationKindReaction, Enabled: false}},
+			wantErr: false,
+		},
+		{
+			name: "error get notification preferences",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetNotificationPreferences(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNotificationPreferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNotificationPreferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetNotifications(t *testing.T) {
+	q := `-- name: GetNotifications :many
+	SELECT n.id, n.kind, n.post_id, n.created_at, n.updated_at, n.read_at,
+	  count(a.actor_id)::int AS actor_count
+	FROM notifications n
+	JOIN notification_actors a ON a.notification_id = n.id
+	WHERE n.recipient_id = $1
+	  AND (NOT $2::boolean OR n.read_at IS NULL)
+	  AND (n.post_id IS NULL OR post_visible_to(n.post_id, $1, false))
+	  AND NOT users_blocked(a.actor_id, $1)
+	  AND NOT EXISTS (
+	    SELECT 1 FROM user_mutes m
+	    WHERE m.muter_id = $1 AND m.muted_id = a.actor_id
+	  )
+	GROUP BY n.id
+	ORDER BY n.updated_at DESC, n.id DESC
+	LIMIT $3 OFFSET $4
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []GetNotificationsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get notifications",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, true, 20, 0).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "kind", "post_id", "created_at", "updated_at", "read_at", "actor_count"}).
+						AddRow(3, "reaction", 7, createdAt, updatedAt, nil, 6))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetNotificationsRow{{
+				ID:         3,
+				Kind:       NotificationKindReaction,
+				PostID:     sql.NullInt32{Int32: 7, Valid: true},
+				CreatedAt:  createdAt,
+				UpdatedAt:  updatedAt,
+				ActorCount: 6,
+			}},
+			wantErr: false,
+		},
+		{
+			name: "error get notifications",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetNotifications(context.Background(), GetNotificationsParams{RecipientID: 1, UnreadOnly: true, Limit: 20})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNotifications() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarkAllNotificationsRead(t *testing.T) {
+	q := `-- name: MarkAllNotificationsRead :execrows
+	UPDATE notifications
+	  set read_at = now()
+	WHERE recipient_id = $1 AND read_at IS NULL
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success mark all notifications read",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 4))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    4,
+			wantErr: false,
+		},
+		{
+			name: "error mark all notifications read",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.MarkAllNotificationsRead(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkAllNotificationsRead() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MarkAllNotificationsRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarkNotificationRead(t *testing.T) {
+	q := `-- name: MarkNotificationRead :execrows
+	UPDATE notifications
+	  set read_at = coalesce(read_at, now())
+	WHERE id = $1 AND recipient_id = $2
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success mark notification read",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "error mark notification read",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.MarkNotificationRead(context.Background(), MarkNotificationReadParams{ID: 3, RecipientID: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkNotificationRead() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MarkNotificationRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SetNotificationPreference(t *testing.T) {
+	q := `-- name: SetNotificationPreference :exec
+	INSERT INTO notification_preferences (user_id, kind, enabled)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, kind) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success set notification preference",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, "reaction", false).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error set notification preference",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.SetNotificationPreference(context.Background(), SetNotificationPreferenceParams{UserID: 1, Kind: NotificationKindReaction, Enabled: false})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetNotificationPreference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_UpsertNotification(t *testing.T) {
+	q := `-- name: UpsertNotification :one
+	INSERT INTO notifications (recipient_id, kind, post_id, created_at, updated_at)
+	SELECT $1::int, $2::notification_kind,
+	  $3::int, $4::timestamp, $4::timestamp
+	WHERE $1::int <> $5::int
+	  AND NOT users_blocked($1::int, $5::int)
+	  AND NOT EXISTS (
+	    SELECT 1 FROM user_mutes m
+	    WHERE m.muter_id = $1::int AND m.muted_id = $5::int
+	  )
+	  AND coalesce((
+	    SELECT np.enabled FROM notification_preferences np
+	    WHERE np.user_id = $1::int AND np.kind = $2::notification_kind
+	  ), true)
+	  AND ($3::int IS NULL OR post_visible_to($3::int, $1::int, false))
+	ON CONFLICT (recipient_id, kind, coalesce(post_id, 0)) WHERE read_at IS NULL
+	DO UPDATE SET updated_at = greatest(notifications.updated_at, EXCLUDED.updated_at)
+	RETURNING id
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	arg := UpsertNotificationParams{
+		RecipientID: 1,
+		Kind:        NotificationKindReaction,
+		PostID:      sql.NullInt32{Int32: 7, Valid: true},
+		CreatedAt:   createdAt,
+		ActorID:     2,
+	}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     int32
+		wantErr  bool
+	}{
+		{
+			name: "success upsert notification",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "reaction", 7, createdAt, 2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "error filtered out",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.UpsertNotification(context.Background(), arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpsertNotification() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UpsertNotification() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
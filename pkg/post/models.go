@@ -6,16 +6,316 @@ package post
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
 )
 
-type Post struct {
+type NotificationKind string
+
+const (
+	NotificationKindFollow   NotificationKind = "follow"
+	NotificationKindReply    NotificationKind = "reply"
+	NotificationKindMention  NotificationKind = "mention"
+	NotificationKindReaction NotificationKind = "reaction"
+	NotificationKindRepost   NotificationKind = "repost"
+	NotificationKindQuote    NotificationKind = "quote"
+)
+
+func (e *NotificationKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationKind(s)
+	case string:
+		*e = NotificationKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationKind: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationKind struct {
+	NotificationKind NotificationKind
+	Valid            bool // Valid is true if NotificationKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationKind), nil
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusScheduled PostStatus = "scheduled"
+	PostStatusPublished PostStatus = "published"
+)
+
+func (e *PostStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PostStatus(s)
+	case string:
+		*e = PostStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PostStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPostStatus struct {
+	PostStatus PostStatus
+	Valid      bool // Valid is true if PostStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPostStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PostStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PostStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPostStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PostStatus), nil
+}
+
+type PostVisibility string
+
+const (
+	PostVisibilityPublic    PostVisibility = "public"
+	PostVisibilityFollowers PostVisibility = "followers"
+	PostVisibilityPrivate   PostVisibility = "private"
+	PostVisibilityUnlisted  PostVisibility = "unlisted"
+)
+
+func (e *PostVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PostVisibility(s)
+	case string:
+		*e = PostVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PostVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullPostVisibility struct {
+	PostVisibility PostVisibility
+	Valid          bool // Valid is true if PostVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPostVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.PostVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PostVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPostVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PostVisibility), nil
+}
+
+type StreamEventKind string
+
+const (
+	StreamEventKindPost          StreamEventKind = "post"
+	StreamEventKindNotification  StreamEventKind = "notification"
+	StreamEventKindReactionCount StreamEventKind = "reaction_count"
+	StreamEventKindMessage       StreamEventKind = "message"
+)
+
+func (e *StreamEventKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StreamEventKind(s)
+	case string:
+		*e = StreamEventKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StreamEventKind: %T", src)
+	}
+	return nil
+}
+
+type NullStreamEventKind struct {
+	StreamEventKind StreamEventKind
+	Valid           bool // Valid is true if StreamEventKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStreamEventKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.StreamEventKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StreamEventKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStreamEventKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StreamEventKind), nil
+}
+
+type Bookmark struct {
+	ID           int32
+	UserID       int32
+	PostID       int32
+	CollectionID sql.NullInt32
+	CreatedAt    time.Time
+}
+
+type Collection struct {
+	ID        int32
+	UserID    int32
+	Name      string
+	CreatedAt time.Time
+}
+
+type Conversation struct {
+	ID            int32
+	CreatedBy     int32
+	DirectKey     sql.NullString
+	CreatedAt     time.Time
+	LastMessageAt time.Time
+}
+
+type ConversationParticipant struct {
+	ConversationID    int32
+	UserID            int32
+	JoinedAt          time.Time
+	LastReadMessageID int64
+	LastReadAt        sql.NullTime
+}
+
+type IdempotencyKey struct {
+	Key          string
+	Scope        string
+	RequestHash  string
+	StatusCode   int32
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+type Medium struct {
+	ID                   int32
+	UserID               int32
+	Sha256               string
+	ContentType          string
+	Size                 int64
+	Width                sql.NullInt32
+	Height               sql.NullInt32
+	ThumbnailContentType sql.NullString
+	CreatedAt            time.Time
+}
+
+type Message struct {
+	ID             int64
+	ConversationID int32
+	SenderID       int32
+	Body           string
+	CreatedAt      time.Time
+}
+
+type Notification struct {
 	ID          int32
-	Userid      int32
+	RecipientID int32
+	Kind        NotificationKind
+	PostID      sql.NullInt32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ReadAt      sql.NullTime
+}
+
+type NotificationActor struct {
+	NotificationID int32
+	ActorID        int32
+	CreatedAt      time.Time
+}
+
+type NotificationEvent struct {
+	ID          int64
+	Kind        NotificationKind
+	RecipientID int32
+	ActorID     int32
+	PostID      sql.NullInt32
+	CreatedAt   time.Time
+}
+
+type NotificationPreference struct {
+	UserID  int32
+	Kind    NotificationKind
+	Enabled bool
+}
+
+type Post struct {
+	ID           int32
+	Userid       int32
+	Title        string
+	Description  string
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	DeletedAt    sql.NullTime
+	Version      int32
+	SearchVector interface{}
+	RepostOfID   sql.NullInt32
+	QuoteOfID    sql.NullInt32
+	InReplyToID  sql.NullInt32
+	RootID       sql.NullInt32
+	Visibility   PostVisibility
+	Status       PostStatus
+	PublishAt    sql.NullTime
+	EditedAt     sql.NullTime
+}
+
+type PostMedium struct {
+	PostID   int32
+	MediaID  int32
+	Position int32
+}
+
+type PostRevision struct {
+	PostID      int32
+	Revision    int32
 	Title       string
 	Description string
-	CreatedAt   sql.NullTime
-	UpdatedAt   sql.NullTime
-	DeletedAt   sql.NullTime
+	TagIds      []int32
+	EditorID    sql.NullInt32
+	CreatedAt   time.Time
 }
 
 type PostTag struct {
@@ -27,18 +327,83 @@ type PostTag struct {
 	DeletedAt sql.NullTime
 }
 
+type Reaction struct {
+	PostID    int32
+	UserID    int32
+	Kind      string
+	CreatedAt time.Time
+}
+
+type StreamEvent struct {
+	ID          int64
+	Kind        StreamEventKind
+	RecipientID sql.NullInt32
+	PostID      sql.NullInt32
+	ActorID     sql.NullInt32
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+}
+
 type Tag struct {
 	ID        int32
 	Tagname   string
 	CreatedAt sql.NullTime
 	UpdatedAt sql.NullTime
 	DeletedAt sql.NullTime
+	Version   int32
+}
+
+type TagFollow struct {
+	UserID    int32
+	TagID     int32
+	CreatedAt time.Time
+}
+
+type TagTrend struct {
+	TagID         int32
+	WindowMinutes int32
+	RecentCount   int32
+	BaselineCount int32
+	Score         float64
+	ComputedAt    time.Time
 }
 
 type User struct {
-	ID        int32
-	Fullname  string
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	DeletedAt sql.NullTime
+	ID              int32
+	Fullname        string
+	CreatedAt       sql.NullTime
+	UpdatedAt       sql.NullTime
+	DeletedAt       sql.NullTime
+	Version         int32
+	IsAdmin         bool
+	Handle          sql.NullString
+	HandleChangedAt sql.NullTime
+	Bio             string
+	Location        string
+	Website         string
+	AvatarMediaID   sql.NullInt32
+}
+
+type UserBlock struct {
+	BlockerID int32
+	BlockedID int32
+	CreatedAt time.Time
+}
+
+type UserFollow struct {
+	FollowerID int32
+	FolloweeID int32
+	CreatedAt  time.Time
+}
+
+type UserHandleRedirect struct {
+	Handle    string
+	UserID    int32
+	ExpiresAt time.Time
+}
+
+type UserMute struct {
+	MuterID   int32
+	MutedID   int32
+	CreatedAt time.Time
 }
@@ -7,21 +7,54 @@ package post
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const addReaction = `-- name: AddReaction :execrows
+INSERT INTO reactions (post_id, user_id, kind)
+SELECT p.id, u.id, $1::text
+FROM posts p, users u
+WHERE p.id = $2 AND u.id = $3
+  AND post_visible_to(p.id, u.id, false)
+ON CONFLICT (post_id, user_id) DO UPDATE SET kind = EXCLUDED.kind
+`
+
+type AddReactionParams struct {
+	Kind   string
+	PostID int32
+	UserID int32
+}
+
+func (q *Queries) AddReaction(ctx context.Context, arg AddReactionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addReaction, arg.Kind, arg.PostID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (
-  userid, title, description
+  userid, title, description, in_reply_to_id, root_id, visibility, status, publish_at
 ) VALUES (
-  $1,$2,$3
+  $1, $2, $3, $4,
+  (SELECT COALESCE(r.root_id, r.id) FROM posts r WHERE r.id = $4),
+  $5, $6, $7
 )
-RETURNING id, userid, title, description
+RETURNING id, userid, title, description, in_reply_to_id, visibility, status, publish_at
 `
 
 type CreatePostParams struct {
 	Userid      int32
 	Title       string
 	Description string
+	InReplyToID sql.NullInt32
+	Visibility  PostVisibility
+	Status      PostStatus
+	PublishAt   sql.NullTime
 }
 
 type CreatePostRow struct {
@@ -29,32 +62,257 @@ type CreatePostRow struct {
 	Userid      int32
 	Title       string
 	Description string
+	InReplyToID sql.NullInt32
+	Visibility  PostVisibility
+	Status      PostStatus
+	PublishAt   sql.NullTime
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error) {
-	row := q.db.QueryRowContext(ctx, createPost, arg.Userid, arg.Title, arg.Description)
+	row := q.db.QueryRowContext(ctx, createPost,
+		arg.Userid,
+		arg.Title,
+		arg.Description,
+		arg.InReplyToID,
+		arg.Visibility,
+		arg.Status,
+		arg.PublishAt,
+	)
 	var i CreatePostRow
 	err := row.Scan(
 		&i.ID,
 		&i.Userid,
 		&i.Title,
 		&i.Description,
+		&i.InReplyToID,
+		&i.Visibility,
+		&i.Status,
+		&i.PublishAt,
 	)
 	return i, err
 }
 
-const deletePost = `-- name: DeletePost :exec
+const createPostRevision = `-- name: CreatePostRevision :one
+INSERT INTO post_revisions (
+  post_id, revision, title, description, tag_ids, editor_id
+)
+SELECT p.id,
+  COALESCE((SELECT max(r.revision) FROM post_revisions r WHERE r.post_id = p.id), 0) + 1,
+  p.title, p.description,
+  COALESCE((
+    SELECT array_agg(DISTINCT pt.tagid ORDER BY pt.tagid) FROM post_tags pt WHERE pt.postid = p.id
+  ), '{}')::int[],
+  COALESCE($1::int, p.userid)
+FROM posts p
+WHERE p.id = $2
+RETURNING post_id, revision, title, description, tag_ids, editor_id, created_at
+`
+
+type CreatePostRevisionParams struct {
+	EditorID sql.NullInt32
+	PostID   int32
+}
+
+func (q *Queries) CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRowContext(ctx, createPostRevision, arg.EditorID, arg.PostID)
+	var i PostRevision
+	err := row.Scan(
+		&i.PostID,
+		&i.Revision,
+		&i.Title,
+		&i.Description,
+		pq.Array(&i.TagIds),
+		&i.EditorID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createQuote = `-- name: CreateQuote :one
+INSERT INTO posts (
+  userid, title, description, quote_of_id
+) VALUES (
+  $1, '', $2, $3::int
+)
+RETURNING id, userid, description
+`
+
+type CreateQuoteParams struct {
+	Userid      int32
+	Description string
+	QuoteOfID   int32
+}
+
+type CreateQuoteRow struct {
+	ID          int32
+	Userid      int32
+	Description string
+}
+
+func (q *Queries) CreateQuote(ctx context.Context, arg CreateQuoteParams) (CreateQuoteRow, error) {
+	row := q.db.QueryRowContext(ctx, createQuote, arg.Userid, arg.Description, arg.QuoteOfID)
+	var i CreateQuoteRow
+	err := row.Scan(&i.ID, &i.Userid, &i.Description)
+	return i, err
+}
+
+const createRepost = `-- name: CreateRepost :one
+INSERT INTO posts (
+  userid, title, description, repost_of_id
+) VALUES (
+  $1, '', '', $2::int
+)
+ON CONFLICT (userid, repost_of_id) DO UPDATE SET repost_of_id = EXCLUDED.repost_of_id
+RETURNING id, userid
+`
+
+type CreateRepostParams struct {
+	Userid     int32
+	RepostOfID int32
+}
+
+type CreateRepostRow struct {
+	ID     int32
+	Userid int32
+}
+
+func (q *Queries) CreateRepost(ctx context.Context, arg CreateRepostParams) (CreateRepostRow, error) {
+	row := q.db.QueryRowContext(ctx, createRepost, arg.Userid, arg.RepostOfID)
+	var i CreateRepostRow
+	err := row.Scan(&i.ID, &i.Userid)
+	return i, err
+}
+
+const deletePost = `-- name: DeletePost :execrows
+WITH target AS (
+  SELECT id FROM posts
+  WHERE id = $1
+    AND version = COALESCE($2, version)
+  FOR UPDATE
+), removed_tags AS (
+  DELETE FROM post_tags WHERE postid IN (SELECT id FROM target)
+)
 DELETE FROM posts
-WHERE id = $1
+WHERE id IN (SELECT id FROM target)
 `
 
-func (q *Queries) DeletePost(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deletePost, id)
+type DeletePostParams struct {
+	ID      int32
+	Version sql.NullInt32
+}
+
+func (q *Queries) DeletePost(ctx context.Context, arg DeletePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePost, arg.ID, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRepost = `-- name: DeleteRepost :exec
+DELETE FROM posts
+WHERE userid = $1
+  AND repost_of_id = (
+    SELECT COALESCE(o.repost_of_id, o.id) FROM posts o WHERE o.id = $2
+  )
+`
+
+type DeleteRepostParams struct {
+	Userid int32
+	PostID int32
+}
+
+func (q *Queries) DeleteRepost(ctx context.Context, arg DeleteRepostParams) error {
+	_, err := q.db.ExecContext(ctx, deleteRepost, arg.Userid, arg.PostID)
 	return err
 }
 
+const getBookmarks = `-- name: GetBookmarks :many
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at
+FROM posts p JOIN (
+  SELECT b.post_id, max(b.created_at) AS saved_at FROM bookmarks b
+  WHERE b.user_id = $1
+    AND ($2::int IS NULL OR b.collection_id = $2)
+  GROUP BY b.post_id
+) s ON s.post_id = p.id
+WHERE post_visible_to(p.id, $1, false)
+ORDER BY s.saved_at DESC, p.id DESC
+LIMIT $3 OFFSET $4
+`
+
+type GetBookmarksParams struct {
+	UserID       int32
+	CollectionID sql.NullInt32
+	Limit        int32
+	Offset       int32
+}
+
+type GetBookmarksRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	ReactionCount int64
+	RepostCount   int64
+	QuoteCount    int64
+	ReplyCount    int64
+	RepostOfID    sql.NullInt32
+	QuoteOfID     sql.NullInt32
+	InReplyToID   sql.NullInt32
+	Visibility    PostVisibility
+	EditedAt      sql.NullTime
+}
+
+func (q *Queries) GetBookmarks(ctx context.Context, arg GetBookmarksParams) ([]GetBookmarksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBookmarks,
+		arg.UserID,
+		arg.CollectionID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBookmarksRow
+	for rows.Next() {
+		var i GetBookmarksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Userid,
+			&i.Title,
+			&i.Description,
+			&i.ReactionCount,
+			&i.RepostCount,
+			&i.QuoteCount,
+			&i.ReplyCount,
+			&i.RepostOfID,
+			&i.QuoteOfID,
+			&i.InReplyToID,
+			&i.Visibility,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPost = `-- name: GetPost :one
-SELECT id, userid, title, description FROM posts
+SELECT id, userid, title, description, status, publish_at, edited_at, version FROM posts
 WHERE id = $1 LIMIT 1
 `
 
@@ -63,6 +321,10 @@ type GetPostRow struct {
 	Userid      int32
 	Title       string
 	Description string
+	Status      PostStatus
+	PublishAt   sql.NullTime
+	EditedAt    sql.NullTime
+	Version     int32
 }
 
 func (q *Queries) GetPost(ctx context.Context, id int32) (GetPostRow, error) {
@@ -73,36 +335,759 @@ func (q *Queries) GetPost(ctx context.Context, id int32) (GetPostRow, error) {
 		&i.Userid,
 		&i.Title,
 		&i.Description,
+		&i.Status,
+		&i.PublishAt,
+		&i.EditedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getPostRevision = `-- name: GetPostRevision :one
+SELECT post_id, revision, title, description, tag_ids, editor_id, created_at FROM post_revisions
+WHERE post_id = $1 AND revision = $2
+`
+
+type GetPostRevisionParams struct {
+	PostID   int32
+	Revision int32
+}
+
+func (q *Queries) GetPostRevision(ctx context.Context, arg GetPostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRowContext(ctx, getPostRevision, arg.PostID, arg.Revision)
+	var i PostRevision
+	err := row.Scan(
+		&i.PostID,
+		&i.Revision,
+		&i.Title,
+		&i.Description,
+		pq.Array(&i.TagIds),
+		&i.EditorID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPostRevisions = `-- name: GetPostRevisions :many
+SELECT post_id, revision, title, description, tag_ids, editor_id, created_at FROM post_revisions
+WHERE post_id = $1
+ORDER BY revision DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetPostRevisionsParams struct {
+	PostID int32
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetPostRevisions(ctx context.Context, arg GetPostRevisionsParams) ([]PostRevision, error) {
+	rows, err := q.db.QueryContext(ctx, getPostRevisions, arg.PostID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PostRevision
+	for rows.Next() {
+		var i PostRevision
+		if err := rows.Scan(
+			&i.PostID,
+			&i.Revision,
+			&i.Title,
+			&i.Description,
+			pq.Array(&i.TagIds),
+			&i.EditorID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPosts = `-- name: GetPosts :many
-SELECT id, userid, title, description FROM posts
-ORDER BY created_at DESC
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at, p.created_at
+FROM posts p
+WHERE ($2::int IS NULL OR p.userid = $2)
+  AND ($3::int[] IS NULL OR CASE
+    WHEN $4::bool THEN (
+      SELECT count(DISTINCT pt.tagid) FROM post_tags pt
+      WHERE pt.postid = p.id AND pt.tagid = ANY($3::int[])
+    ) = cardinality($3::int[])
+    ELSE EXISTS (
+      SELECT 1 FROM post_tags pt
+      WHERE pt.postid = p.id AND pt.tagid = ANY($3::int[])
+    )
+  END)
+  AND ($5::timestamp IS NULL OR p.created_at >= $5)
+  AND ($6::timestamp IS NULL OR p.created_at < $6)
+  AND ($7::timestamp IS NULL OR CASE
+    WHEN $8::text = 'oldest'
+      THEN (p.created_at, p.id) > ($7, $9::int)
+    ELSE (p.created_at, p.id) < ($7, $9::int)
+  END)
+  AND post_visible_to(p.id, $1, true)
+ORDER BY
+  CASE WHEN $8::text = 'most_reacted'
+    THEN (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) END DESC,
+  CASE WHEN $8::text = 'oldest' THEN p.created_at END ASC,
+  CASE WHEN $8::text = 'oldest' THEN p.id END ASC,
+  p.created_at DESC,
+  p.id DESC
+LIMIT $10
 `
 
+type GetPostsParams struct {
+	ViewerID        int32
+	UserID          sql.NullInt32
+	TagIds          []int32
+	MatchAllTags    bool
+	CreatedFrom     sql.NullTime
+	CreatedTo       sql.NullTime
+	CursorCreatedAt sql.NullTime
+	Sort            string
+	CursorID        int32
+	Limit           int32
+}
+
 type GetPostsRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	ReactionCount int64
+	RepostCount   int64
+	QuoteCount    int64
+	ReplyCount    int64
+	RepostOfID    sql.NullInt32
+	QuoteOfID     sql.NullInt32
+	InReplyToID   sql.NullInt32
+	Visibility    PostVisibility
+	EditedAt      sql.NullTime
+	CreatedAt     sql.NullTime
+}
+
+func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) ([]GetPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPosts,
+		arg.ViewerID,
+		arg.UserID,
+		pq.Array(arg.TagIds),
+		arg.MatchAllTags,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorCreatedAt,
+		arg.Sort,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsRow
+	for rows.Next() {
+		var i GetPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Userid,
+			&i.Title,
+			&i.Description,
+			&i.ReactionCount,
+			&i.RepostCount,
+			&i.QuoteCount,
+			&i.ReplyCount,
+			&i.RepostOfID,
+			&i.QuoteOfID,
+			&i.InReplyToID,
+			&i.Visibility,
+			&i.EditedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsByIDs = `-- name: GetPostsByIDs :many
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+  p.edited_at
+FROM posts p
+WHERE p.id = ANY($2::int[])
+  AND post_visible_to(p.id, $1, false)
+`
+
+type GetPostsByIDsParams struct {
+	ViewerID int32
+	Ids      []int32
+}
+
+type GetPostsByIDsRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	ReactionCount int64
+	RepostCount   int64
+	QuoteCount    int64
+	ReplyCount    int64
+	EditedAt      sql.NullTime
+}
+
+func (q *Queries) GetPostsByIDs(ctx context.Context, arg GetPostsByIDsParams) ([]GetPostsByIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsByIDs, arg.ViewerID, pq.Array(arg.Ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsByIDsRow
+	for rows.Next() {
+		var i GetPostsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Userid,
+			&i.Title,
+			&i.Description,
+			&i.ReactionCount,
+			&i.RepostCount,
+			&i.QuoteCount,
+			&i.ReplyCount,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReplyChain = `-- name: GetReplyChain :many
+WITH RECURSIVE chain AS (
+  SELECT p.id, 0 AS distance FROM posts p
+  WHERE p.id = $1 AND p.deleted_at IS NULL
+    AND post_visible_to(p.id, $2, false)
+  UNION ALL
+  SELECT p.in_reply_to_id, c.distance + 1
+  FROM chain c JOIN posts p ON p.id = c.id
+  WHERE p.in_reply_to_id IS NOT NULL
+)
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $2, false)) AS reply_count,
+  p.edited_at
+FROM chain c JOIN posts p ON p.id = c.id
+WHERE post_visible_to(p.id, $2, false)
+ORDER BY c.distance DESC
+`
+
+type GetReplyChainParams struct {
+	ID       int32
+	ViewerID int32
+}
+
+type GetReplyChainRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	ReactionCount int64
+	ReplyCount    int64
+	EditedAt      sql.NullTime
+}
+
+func (q *Queries) GetReplyChain(ctx context.Context, arg GetReplyChainParams) ([]GetReplyChainRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReplyChain, arg.ID, arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReplyChainRow
+	for rows.Next() {
+		var i GetReplyChainRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Userid,
+			&i.Title,
+			&i.Description,
+			&i.ReactionCount,
+			&i.ReplyCount,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReplyTree = `-- name: GetReplyTree :many
+WITH RECURSIVE tree AS (
+  SELECT top.id, 1 AS depth FROM (
+    SELECT c.id FROM posts c
+    WHERE c.in_reply_to_id = $1::int AND c.deleted_at IS NULL
+      AND post_visible_to(c.id, $2, false)
+    ORDER BY c.created_at, c.id
+    LIMIT $3 OFFSET $4
+  ) top
+  UNION ALL
+  SELECT c.id, t.depth + 1
+  FROM tree t CROSS JOIN LATERAL (
+    SELECT c.id FROM posts c
+    WHERE c.in_reply_to_id = t.id AND c.deleted_at IS NULL
+      AND post_visible_to(c.id, $2, false)
+    ORDER BY c.created_at, c.id
+    LIMIT $3
+  ) c
+  WHERE t.depth < $5::int
+)
+SELECT p.id, p.userid, p.title, p.description, p.in_reply_to_id,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $2, false)) AS reply_count,
+  p.edited_at
+FROM tree t JOIN posts p ON p.id = t.id
+ORDER BY t.depth, p.created_at, p.id
+`
+
+type GetReplyTreeParams struct {
+	ID       int32
+	ViewerID int32
+	Limit    int32
+	Offset   int32
+	Depth    int32
+}
+
+type GetReplyTreeRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	InReplyToID   sql.NullInt32
+	ReactionCount int64
+	ReplyCount    int64
+	EditedAt      sql.NullTime
+}
+
+func (q *Queries) GetReplyTree(ctx context.Context, arg GetReplyTreeParams) ([]GetReplyTreeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReplyTree,
+		arg.ID,
+		arg.ViewerID,
+		arg.Limit,
+		arg.Offset,
+		arg.Depth,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReplyTreeRow
+	for rows.Next() {
+		var i GetReplyTreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Userid,
+			&i.Title,
+			&i.Description,
+			&i.InReplyToID,
+			&i.ReactionCount,
+			&i.ReplyCount,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRepostTarget = `-- name: GetRepostTarget :one
+SELECT o.id, o.userid FROM posts p JOIN posts o
+ON o.id = COALESCE(p.repost_of_id, p.id)
+WHERE p.id = $1
+  AND post_visible_to(o.id, 0, false)
+`
+
+type GetRepostTargetRow struct {
+	ID     int32
+	Userid int32
+}
+
+func (q *Queries) GetRepostTarget(ctx context.Context, id int32) (GetRepostTargetRow, error) {
+	row := q.db.QueryRowContext(ctx, getRepostTarget, id)
+	var i GetRepostTargetRow
+	err := row.Scan(&i.ID, &i.Userid)
+	return i, err
+}
+
+const getTagFeed = `-- name: GetTagFeed :many
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at
+FROM posts p
+WHERE EXISTS (
+  SELECT 1 FROM post_tags pt JOIN tag_follows tf
+  ON tf.tag_id = pt.tagid
+  WHERE pt.postid = p.id AND tf.user_id = $1
+)
+  AND post_visible_to(p.id, $1, true)
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetTagFeedParams struct {
+	UserID int32
+	Limit  int32
+	Offset int32
+}
+
+type GetTagFeedRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	ReactionCount int64
+	RepostCount   int64
+	QuoteCount    int64
+	ReplyCount    int64
+	RepostOfID    sql.NullInt32
+	QuoteOfID     sql.NullInt32
+	InReplyToID   sql.NullInt32
+	Visibility    PostVisibility
+	EditedAt      sql.NullTime
+}
+
+func (q *Queries) GetTagFeed(ctx context.Context, arg GetTagFeedParams) ([]GetTagFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagFeed, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagFeedRow
+	for rows.Next() {
+		var i GetTagFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Userid,
+			&i.Title,
+			&i.Description,
+			&i.ReactionCount,
+			&i.RepostCount,
+			&i.QuoteCount,
+			&i.ReplyCount,
+			&i.RepostOfID,
+			&i.QuoteOfID,
+			&i.InReplyToID,
+			&i.Visibility,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVisiblePost = `-- name: GetVisiblePost :one
+SELECT id, userid, title, description, visibility, status, publish_at, edited_at, version FROM posts
+WHERE id = $1 AND post_visible_to(id, $2, false)
+`
+
+type GetVisiblePostParams struct {
+	ID       int32
+	ViewerID int32
+}
+
+type GetVisiblePostRow struct {
+	ID          int32
+	Userid      int32
+	Title       string
+	Description string
+	Visibility  PostVisibility
+	Status      PostStatus
+	PublishAt   sql.NullTime
+	EditedAt    sql.NullTime
+	Version     int32
+}
+
+func (q *Queries) GetVisiblePost(ctx context.Context, arg GetVisiblePostParams) (GetVisiblePostRow, error) {
+	row := q.db.QueryRowContext(ctx, getVisiblePost, arg.ID, arg.ViewerID)
+	var i GetVisiblePostRow
+	err := row.Scan(
+		&i.ID,
+		&i.Userid,
+		&i.Title,
+		&i.Description,
+		&i.Visibility,
+		&i.Status,
+		&i.PublishAt,
+		&i.EditedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const patchPost = `-- name: PatchPost :one
+UPDATE posts
+  set title = COALESCE($1, title),
+  description = COALESCE($2, description),
+  visibility = COALESCE($3, visibility),
+  status = COALESCE($4, status),
+  publish_at = COALESCE($5, publish_at),
+  created_at = CASE WHEN status <> 'published' AND $4 = 'published'
+    THEN now() ELSE created_at END,
+  version = version + 1
+WHERE id = $6
+  AND version = COALESCE($7, version)
+RETURNING id, userid, title, description, visibility, status, publish_at, version
+`
+
+type PatchPostParams struct {
+	Title       sql.NullString
+	Description sql.NullString
+	Visibility  NullPostVisibility
+	Status      NullPostStatus
+	PublishAt   sql.NullTime
+	ID          int32
+	Version     sql.NullInt32
+}
+
+type PatchPostRow struct {
 	ID          int32
 	Userid      int32
 	Title       string
 	Description string
+	Visibility  PostVisibility
+	Status      PostStatus
+	PublishAt   sql.NullTime
+	Version     int32
+}
+
+func (q *Queries) PatchPost(ctx context.Context, arg PatchPostParams) (PatchPostRow, error) {
+	row := q.db.QueryRowContext(ctx, patchPost,
+		arg.Title,
+		arg.Description,
+		arg.Visibility,
+		arg.Status,
+		arg.PublishAt,
+		arg.ID,
+		arg.Version,
+	)
+	var i PatchPostRow
+	err := row.Scan(
+		&i.ID,
+		&i.Userid,
+		&i.Title,
+		&i.Description,
+		&i.Visibility,
+		&i.Status,
+		&i.PublishAt,
+		&i.Version,
+	)
+	return i, err
 }
 
-func (q *Queries) GetPosts(ctx context.Context) ([]GetPostsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPosts)
+const publishDuePosts = `-- name: PublishDuePosts :many
+UPDATE posts p
+  set status = 'published',
+  created_at = p.publish_at,
+  version = p.version + 1
+FROM (
+  SELECT d.id FROM posts d
+  WHERE d.status = 'scheduled' AND d.publish_at <= $1::timestamp
+  ORDER BY d.publish_at, d.id
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+) due
+WHERE p.id = due.id AND p.status = 'scheduled'
+RETURNING p.id
+`
+
+type PublishDuePostsParams struct {
+	Now   time.Time
+	Limit int32
+}
+
+func (q *Queries) PublishDuePosts(ctx context.Context, arg PublishDuePostsParams) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, publishDuePosts, arg.Now, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPostsRow
+	var items []int32
 	for rows.Next() {
-		var i GetPostsRow
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeReaction = `-- name: RemoveReaction :exec
+DELETE FROM reactions
+WHERE post_id = $1 AND user_id = $2
+`
+
+type RemoveReactionParams struct {
+	PostID int32
+	UserID int32
+}
+
+func (q *Queries) RemoveReaction(ctx context.Context, arg RemoveReactionParams) error {
+	_, err := q.db.ExecContext(ctx, removeReaction, arg.PostID, arg.UserID)
+	return err
+}
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT
+  p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at,
+  ts_headline('english', p.description, websearch_to_tsquery('english', $2),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet,
+  (ts_rank(p.search_vector, websearch_to_tsquery('english', $2))
+    / (1 + EXTRACT(EPOCH FROM now() - p.created_at) / 2592000))::float8 AS rank
+FROM posts p
+WHERE p.search_vector @@ websearch_to_tsquery('english', $2)
+  AND ($3::int IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags pt WHERE pt.postid = p.id AND pt.tagid = $3
+  ))
+  AND ($4::int IS NULL OR p.userid = $4)
+  AND ($5::timestamp IS NULL OR p.created_at >= $5)
+  AND ($6::timestamp IS NULL OR p.created_at < $6)
+  AND post_visible_to(p.id, $1, true)
+ORDER BY rank DESC, p.id DESC
+LIMIT $7 OFFSET $8
+`
+
+type SearchPostsParams struct {
+	ViewerID    int32
+	Query       string
+	TagID       sql.NullInt32
+	UserID      sql.NullInt32
+	CreatedFrom sql.NullTime
+	CreatedTo   sql.NullTime
+	Limit       int32
+	Offset      int32
+}
+
+type SearchPostsRow struct {
+	ID            int32
+	Userid        int32
+	Title         string
+	Description   string
+	ReactionCount int64
+	RepostCount   int64
+	QuoteCount    int64
+	ReplyCount    int64
+	RepostOfID    sql.NullInt32
+	QuoteOfID     sql.NullInt32
+	InReplyToID   sql.NullInt32
+	Visibility    PostVisibility
+	EditedAt      sql.NullTime
+	Snippet       string
+	Rank          float64
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.ViewerID,
+		arg.Query,
+		arg.TagID,
+		arg.UserID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Userid,
 			&i.Title,
 			&i.Description,
+			&i.ReactionCount,
+			&i.RepostCount,
+			&i.QuoteCount,
+			&i.ReplyCount,
+			&i.RepostOfID,
+			&i.QuoteOfID,
+			&i.InReplyToID,
+			&i.Visibility,
+			&i.EditedAt,
+			&i.Snippet,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -119,26 +1104,35 @@ func (q *Queries) GetPosts(ctx context.Context) ([]GetPostsRow, error) {
 
 const updatePost = `-- name: UpdatePost :one
 UPDATE posts
-  set title = $2,
-  description = $3
-WHERE id = $1
-RETURNING title, description
+  set title = $1,
+  description = $2,
+  version = version + 1
+WHERE id = $3
+  AND version = COALESCE($4, version)
+RETURNING title, description, version
 `
 
 type UpdatePostParams struct {
-	ID          int32
 	Title       string
 	Description string
+	ID          int32
+	Version     sql.NullInt32
 }
 
 type UpdatePostRow struct {
 	Title       string
 	Description string
+	Version     int32
 }
 
 func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (UpdatePostRow, error) {
-	row := q.db.QueryRowContext(ctx, updatePost, arg.ID, arg.Title, arg.Description)
+	row := q.db.QueryRowContext(ctx, updatePost,
+		arg.Title,
+		arg.Description,
+		arg.ID,
+		arg.Version,
+	)
 	var i UpdatePostRow
-	err := row.Scan(&i.Title, &i.Description)
+	err := row.Scan(&i.Title, &i.Description, &i.Version)
 	return i, err
 }
@@ -7,6 +7,7 @@ import (
 	reflect "reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	gomock "github.com/golang/mock/gomock"
@@ -90,13 +91,16 @@ func Test_CreatePost(t *testing.T) {
 		arg CreatePostParams
 	}
 
+	publishAt := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
 	q := `-- name: CreatePost :one
 	INSERT INTO posts (
-	  userid, title, description
+	  userid, title, description, in_reply_to_id, root_id, visibility, status, publish_at
 	) VALUES (
-	  $1,$2,$3
+	  $1, $2, $3, $4,
+	  (SELECT COALESCE(r.root_id, r.id) FROM posts r WHERE r.id = $4),
+	  $5, $6, $7
 	)
-	RETURNING id, userid, title, description
+	RETURNING id, userid, title, description, in_reply_to_id, visibility, status, publish_at
 	`
 
 	tests := []struct {
@@ -114,12 +118,14 @@ func Test_CreatePost(t *testing.T) {
 					Userid:      1,
 					Title:       "Holiday in maldives",
 					Description: "Yeah yeah yeah",
+					Visibility:  PostVisibilityPublic,
+					Status:      PostStatusPublished,
 				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description"}).AddRow(1, 1, "Holiday in maldives", "Yeah yeah yeah")
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Holiday in maldives", "Yeah yeah yeah").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "in_reply_to_id", "visibility", "status", "publish_at"}).AddRow(1, 1, "Holiday in maldives", "Yeah yeah yeah", nil, "public", "published", nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Holiday in maldives", "Yeah yeah yeah", nil, "public", "published", nil).WillReturnRows(rows)
 
 				return &Queries{
 					db: dbMock,
@@ -130,6 +136,74 @@ func Test_CreatePost(t *testing.T) {
 				Userid:      1,
 				Title:       "Holiday in maldives",
 				Description: "Yeah yeah yeah",
+				Visibility:  PostVisibilityPublic,
+				Status:      PostStatusPublished,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success create reply",
+			args: args{
+				ctx: context.Background(),
+				arg: CreatePostParams{
+					Userid:      2,
+					Title:       "Re: holiday",
+					Description: "Take me with you",
+					InReplyToID: sql.NullInt32{Int32: 1, Valid: true},
+					Visibility:  PostVisibilityFollowers,
+					Status:      PostStatusPublished,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "in_reply_to_id", "visibility", "status", "publish_at"}).AddRow(2, 2, "Re: holiday", "Take me with you", 1, "followers", "published", nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, "Re: holiday", "Take me with you", 1, "followers", "published", nil).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: CreatePostRow{
+				ID:          2,
+				Userid:      2,
+				Title:       "Re: holiday",
+				Description: "Take me with you",
+				InReplyToID: sql.NullInt32{Int32: 1, Valid: true},
+				Visibility:  PostVisibilityFollowers,
+				Status:      PostStatusPublished,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success create scheduled post",
+			args: args{
+				ctx: context.Background(),
+				arg: CreatePostParams{
+					Userid:      1,
+					Title:       "Holiday in maldives",
+					Description: "Yeah yeah yeah",
+					Visibility:  PostVisibilityPublic,
+					Status:      PostStatusScheduled,
+					PublishAt:   sql.NullTime{Time: publishAt, Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "in_reply_to_id", "visibility", "status", "publish_at"}).AddRow(3, 1, "Holiday in maldives", "Yeah yeah yeah", nil, "public", "scheduled", publishAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Holiday in maldives", "Yeah yeah yeah", nil, "public", "scheduled", publishAt).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: CreatePostRow{
+				ID:          3,
+				Userid:      1,
+				Title:       "Holiday in maldives",
+				Description: "Yeah yeah yeah",
+				Visibility:  PostVisibilityPublic,
+				Status:      PostStatusScheduled,
+				PublishAt:   sql.NullTime{Time: publishAt, Valid: true},
 			},
 			wantErr: false,
 		},
@@ -141,11 +215,13 @@ func Test_CreatePost(t *testing.T) {
 					Userid:      1,
 					Title:       "Holiday in maldives",
 					Description: "Yeah yeah yeah",
+					Visibility:  PostVisibilityPublic,
+					Status:      PostStatusPublished,
 				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Holiday in maldives", "Yeah yeah yeah").WillReturnError(errors.New("error"))
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Holiday in maldives", "Yeah yeah yeah", nil, "public", "published", nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
@@ -173,60 +249,98 @@ func Test_CreatePost(t *testing.T) {
 func Test_DeletePost(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		id  int32
+		arg DeletePostParams
 	}
 
-	q := `-- name: DeletePost :exec
-	DELETE FROM posts
-	WHERE id = $1
-	`
+	q := `-- name: DeletePost :execrows
+WITH target AS (
+  SELECT id FROM posts
+  WHERE id = $1
+    AND version = COALESCE($2, version)
+  FOR UPDATE
+), removed_tags AS (
+  DELETE FROM post_tags WHERE postid IN (SELECT id FROM target)
+)
+DELETE FROM posts
+WHERE id IN (SELECT id FROM target)
+`
 	tests := []struct {
 		name     string
 		initMock func() *Queries
 		args     args
+		want     int64
 		wantErr  bool
 	}{
 		{
 			name: "success delete post",
 			args: args{
 				ctx: context.Background(),
-				id:  1,
+				arg: DeletePostParams{
+					ID: 1,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "stale version deletes nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: DeletePostParams{
+					ID:      1,
+					Version: sql.NullInt32{Int32: 2, Valid: true},
+				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
 
 				return &Queries{
 					db: dbMock,
 				}
 			},
+			want:    0,
 			wantErr: false,
 		},
 		{
 			name: "error delete post",
 			args: args{
 				ctx: context.Background(),
-				id:  1,
+				arg: DeletePostParams{
+					ID: 1,
+				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
 				}
 			},
+			want:    0,
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.initMock()
-			err := p.DeletePost(tt.args.ctx, tt.args.id)
+			got, err := p.DeletePost(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeletePost() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if got != tt.want {
+				t.Errorf("DeletePost() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -238,8 +352,8 @@ func Test_GetPost(t *testing.T) {
 	}
 
 	q := `-- name: GetPost :one
-		SELECT id, userid, title, description FROM posts
-		WHERE id = $1 LIMIT 1
+	SELECT id, userid, title, description, status, publish_at, edited_at, version FROM posts
+	WHERE id = $1 LIMIT 1
 	`
 	tests := []struct {
 		name     string
@@ -256,7 +370,7 @@ func Test_GetPost(t *testing.T) {
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description"}).AddRow(1, 1, "holiday yay", "yeah yeah yeah")
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "status", "publish_at", "edited_at", "version"}).AddRow(1, 1, "holiday yay", "yeah yeah yeah", "published", nil, nil, 3)
 				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
 
 				return &Queries{
@@ -268,6 +382,8 @@ func Test_GetPost(t *testing.T) {
 				Userid:      1,
 				Title:       "holiday yay",
 				Description: "yeah yeah yeah",
+				Status:      PostStatusPublished,
+				Version:     3,
 			},
 			wantErr: false,
 		},
@@ -307,13 +423,50 @@ func Test_GetPost(t *testing.T) {
 func Test_GetPosts(t *testing.T) {
 	type args struct {
 		ctx context.Context
+		arg GetPostsParams
 	}
 
 	q := `-- name: GetPosts :many
-	SELECT id, userid, title, description FROM posts
-	ORDER BY created_at DESC
+	SELECT p.id, p.userid, p.title, p.description,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+	  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+	  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at, p.created_at
+	FROM posts p
+	WHERE ($2::int IS NULL OR p.userid = $2)
+	  AND ($3::int[] IS NULL OR CASE
+	    WHEN $4::bool THEN (
+	      SELECT count(DISTINCT pt.tagid) FROM post_tags pt
+	      WHERE pt.postid = p.id AND pt.tagid = ANY($3::int[])
+	    ) = cardinality($3::int[])
+	    ELSE EXISTS (
+	      SELECT 1 FROM post_tags pt
+	      WHERE pt.postid = p.id AND pt.tagid = ANY($3::int[])
+	    )
+	  END)
+	  AND ($5::timestamp IS NULL OR p.created_at >= $5)
+	  AND ($6::timestamp IS NULL OR p.created_at < $6)
+	  AND ($7::timestamp IS NULL OR CASE
+	    WHEN $8::text = 'oldest'
+	      THEN (p.created_at, p.id) > ($7, $9::int)
+	    ELSE (p.created_at, p.id) < ($7, $9::int)
+	  END)
+	  AND post_visible_to(p.id, $1, true)
+	ORDER BY
+	  CASE WHEN $8::text = 'most_reacted'
+	    THEN (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) END DESC,
+	  CASE WHEN $8::text = 'oldest' THEN p.created_at END ASC,
+	  CASE WHEN $8::text = 'oldest' THEN p.id END ASC,
+	  p.created_at DESC,
+	  p.id DESC
+	LIMIT $10
 	`
 
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	cursor := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name     string
 		initMock func() *Queries
@@ -325,11 +478,12 @@ func Test_GetPosts(t *testing.T) {
 			name: "success get posts",
 			args: args{
 				ctx: context.Background(),
+				arg: GetPostsParams{TagIds: []int32{1, 2}, MatchAllTags: true, ViewerID: 3, CursorCreatedAt: sql.NullTime{Time: cursor, Valid: true}, Sort: "newest", CursorID: 9, Limit: 20},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description"}).AddRow(1, 1, "holiday yay", "yeah yeah yeah")
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at", "created_at"}).AddRow(1, 1, "holiday yay", "yeah yeah yeah", 3, 2, 0, 1, nil, 4, nil, "public", nil, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(3, sql.NullInt32{}, "{1,2}", true, sql.NullTime{}, sql.NullTime{}, sql.NullTime{Time: cursor, Valid: true}, "newest", 9, 20).WillReturnRows(rows)
 
 				return &Queries{
 					db: dbMock,
@@ -337,22 +491,29 @@ func Test_GetPosts(t *testing.T) {
 			},
 			want: []GetPostsRow{
 				{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yeah yeah yeah",
+					ID:            1,
+					Userid:        1,
+					Title:         "holiday yay",
+					Description:   "yeah yeah yeah",
+					ReactionCount: 3,
+					RepostCount:   2,
+					ReplyCount:    1,
+					QuoteOfID:     sql.NullInt32{Int32: 4, Valid: true},
+					Visibility:    PostVisibilityPublic,
+					CreatedAt:     sql.NullTime{Time: createdAt, Valid: true},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "error scan get tags",
+			name: "error scan get posts",
 			args: args{
 				ctx: context.Background(),
+				arg: GetPostsParams{TagIds: []int32{1, 2}, MatchAllTags: true, ViewerID: 3, Sort: "newest", Limit: 20},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description"}).AddRow("error", 1, "holiday yay", "yeah yeah yeah")
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at", "created_at"}).AddRow("error", 1, "holiday yay", "yeah yeah yeah", 3, 2, 0, 1, nil, 4, nil, "public", nil, createdAt)
 				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
 
 				return &Queries{
@@ -363,9 +524,10 @@ func Test_GetPosts(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "error get tags",
+			name: "error get posts",
 			args: args{
 				ctx: context.Background(),
+				arg: GetPostsParams{TagIds: []int32{1, 2}, MatchAllTags: true, ViewerID: 3, Sort: "newest", Limit: 20},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
@@ -382,7 +544,7 @@ func Test_GetPosts(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.initMock()
-			got, err := p.GetPosts(tt.args.ctx)
+			got, err := p.GetPosts(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetPosts() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -394,6 +556,136 @@ func Test_GetPosts(t *testing.T) {
 	}
 }
 
+func Test_SearchPosts(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg SearchPostsParams
+	}
+
+	q := `-- name: SearchPosts :many
+	SELECT
+	  p.id, p.userid, p.title, p.description,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+	  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+	  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at,
+	  ts_headline('english', p.description, websearch_to_tsquery('english', $2),
+	    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet,
+	  (ts_rank(p.search_vector, websearch_to_tsquery('english', $2))
+	    / (1 + EXTRACT(EPOCH FROM now() - p.created_at) / 2592000))::float8 AS rank
+	FROM posts p
+	WHERE p.search_vector @@ websearch_to_tsquery('english', $2)
+	  AND ($3::int IS NULL OR EXISTS (
+	    SELECT 1 FROM post_tags pt WHERE pt.postid = p.id AND pt.tagid = $3
+	  ))
+	  AND ($4::int IS NULL OR p.userid = $4)
+	  AND ($5::timestamp IS NULL OR p.created_at >= $5)
+	  AND ($6::timestamp IS NULL OR p.created_at < $6)
+	  AND post_visible_to(p.id, $1, true)
+	ORDER BY rank DESC, p.id DESC
+	LIMIT $7 OFFSET $8
+	`
+	columns := []string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at", "snippet", "rank"}
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []SearchPostsRow
+		wantErr  bool
+	}{
+		{
+			name: "success search posts",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchPostsParams{
+					Query:    "holiday",
+					TagID:    sql.NullInt32{Int32: 1, Valid: true},
+					ViewerID: 3,
+					Limit:    20,
+					Offset:   0,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(columns).AddRow(1, 1, "holiday yay", "yeah yeah yeah", 3, 0, 0, 1, nil, 4, nil, "public", nil, "<mark>holiday</mark> yeah", 0.5)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).
+					WithArgs(3, "holiday", sql.NullInt32{Int32: 1, Valid: true}, sql.NullInt32{}, sql.NullTime{}, sql.NullTime{}, 20, 0).
+					WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []SearchPostsRow{
+				{
+					ID:            1,
+					Userid:        1,
+					Title:         "holiday yay",
+					Description:   "yeah yeah yeah",
+					ReactionCount: 3,
+					ReplyCount:    1,
+					QuoteOfID:     sql.NullInt32{Int32: 4, Valid: true},
+					Visibility:    PostVisibilityPublic,
+					Snippet:       "<mark>holiday</mark> yeah",
+					Rank:          0.5,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan search posts",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchPostsParams{Query: "holiday", Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(columns).AddRow("error", 1, "holiday yay", "yeah yeah yeah", 0, 0, 0, 0, nil, nil, nil, "public", nil, "", 0.5)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error search posts",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchPostsParams{Query: "holiday", Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.SearchPosts(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchPosts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchPosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_UpdatePost(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -402,10 +694,12 @@ func Test_UpdatePost(t *testing.T) {
 
 	q := `-- name: UpdatePost :one
 		UPDATE posts
-		set title = $2,
-		description = $3
-		WHERE id = $1
-		RETURNING title, description
+		set title = $1,
+		description = $2,
+		version = version + 1
+		WHERE id = $3
+		AND version = COALESCE($4, version)
+		RETURNING title, description, version
 	`
 
 	tests := []struct {
@@ -423,12 +717,13 @@ func Test_UpdatePost(t *testing.T) {
 					ID:          1,
 					Title:       "holiday yay",
 					Description: "yay yay",
+					Version:     sql.NullInt32{Int32: 1, Valid: true},
 				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"title", "description"}).AddRow("holiday yay", "yay yay")
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "holiday yay", "yay yay").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"title", "description", "version"}).AddRow("holiday yay", "yay yay", 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday yay", "yay yay", 1, 1).WillReturnRows(rows)
 
 				return &Queries{
 					db: dbMock,
@@ -437,9 +732,33 @@ func Test_UpdatePost(t *testing.T) {
 			want: UpdatePostRow{
 				Title:       "holiday yay",
 				Description: "yay yay",
+				Version:     2,
 			},
 			wantErr: false,
 		},
+		{
+			name: "stale version",
+			args: args{
+				ctx: context.Background(),
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay",
+					Version:     sql.NullInt32{Int32: 1, Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"title", "description", "version"})
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday yay", "yay yay", 1, 1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
 		{
 			name: "error update post",
 			args: args{
@@ -452,7 +771,7 @@ func Test_UpdatePost(t *testing.T) {
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "holiday").WillReturnError(errors.New("error"))
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday yay", "yay yay", 1, nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
@@ -476,3 +795,1474 @@ func Test_UpdatePost(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchPost(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg PatchPostParams
+	}
+
+	q := `-- name: PatchPost :one
+	UPDATE posts
+	  set title = COALESCE($1, title),
+	  description = COALESCE($2, description),
+	  visibility = COALESCE($3, visibility),
+	  status = COALESCE($4, status),
+	  publish_at = COALESCE($5, publish_at),
+	  created_at = CASE WHEN status <> 'published' AND $4 = 'published'
+	    THEN now() ELSE created_at END,
+	  version = version + 1
+	WHERE id = $6
+	  AND version = COALESCE($7, version)
+	RETURNING id, userid, title, description, visibility, status, publish_at, version
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     PatchPostRow
+		wantErr  bool
+	}{
+		{
+			name: "success patch post title only",
+			args: args{
+				ctx: context.Background(),
+				arg: PatchPostParams{
+					ID:    1,
+					Title: sql.NullString{String: "holiday yay", Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "visibility", "status", "publish_at", "version"}).AddRow(1, 1, "holiday yay", "yay yay", "public", "published", nil, 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday yay", nil, nil, nil, nil, 1, nil).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: PatchPostRow{
+				ID:          1,
+				Userid:      1,
+				Title:       "holiday yay",
+				Description: "yay yay",
+				Visibility:  PostVisibilityPublic,
+				Status:      PostStatusPublished,
+				Version:     2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error patch post",
+			args: args{
+				ctx: context.Background(),
+				arg: PatchPostParams{
+					ID:    1,
+					Title: sql.NullString{String: "holiday yay", Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday yay", nil, nil, nil, nil, 1, nil).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    PatchPostRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.PatchPost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchPost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetTagFeed(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetTagFeedParams
+	}
+
+	q := `-- name: GetTagFeed :many
+	SELECT p.id, p.userid, p.title, p.description,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+	  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+	  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at
+	FROM posts p
+	WHERE EXISTS (
+	  SELECT 1 FROM post_tags pt JOIN tag_follows tf
+	  ON tf.tag_id = pt.tagid
+	  WHERE pt.postid = p.id AND tf.user_id = $1
+	)
+	  AND post_visible_to(p.id, $1, true)
+	ORDER BY p.created_at DESC, p.id DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetTagFeedRow
+		wantErr  bool
+	}{
+		{
+			name: "success get tag feed",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTagFeedParams{UserID: 2, Limit: 20, Offset: 0},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at"}).AddRow(1, 2, "title", "description", 3, 0, 1, 0, 5, nil, 2, "public", nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, 20, 0).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetTagFeedRow{{ID: 1, Userid: 2, Title: "title", Description: "description", ReactionCount: 3, QuoteCount: 1, RepostOfID: sql.NullInt32{Int32: 5, Valid: true}, InReplyToID: sql.NullInt32{Int32: 2, Valid: true}, Visibility: PostVisibilityPublic}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get tag feed",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTagFeedParams{UserID: 2, Limit: 20, Offset: 0},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at"}).AddRow("x", 2, "title", "description", 3, 0, 1, 0, 5, nil, 2, "public", nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get tag feed",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTagFeedParams{UserID: 2, Limit: 20, Offset: 0},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetTagFeed(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTagFeed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTagFeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetBookmarks(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetBookmarksParams
+	}
+
+	q := `-- name: GetBookmarks :many
+	SELECT p.id, p.userid, p.title, p.description,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+	  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+	  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at
+	FROM posts p JOIN (
+	  SELECT b.post_id, max(b.created_at) AS saved_at FROM bookmarks b
+	  WHERE b.user_id = $1
+	    AND ($2::int IS NULL OR b.collection_id = $2)
+	  GROUP BY b.post_id
+	) s ON s.post_id = p.id
+	WHERE post_visible_to(p.id, $1, false)
+	ORDER BY s.saved_at DESC, p.id DESC
+	LIMIT $3 OFFSET $4
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetBookmarksRow
+		wantErr  bool
+	}{
+		{
+			name: "success get bookmarks",
+			args: args{
+				ctx: context.Background(),
+				arg: GetBookmarksParams{UserID: 2, CollectionID: sql.NullInt32{Int32: 3, Valid: true}, Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at"}).AddRow(1, 2, "title", "description", 3, 0, 1, 0, 5, nil, 2, "public", nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, 3, 20, 0).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetBookmarksRow{{ID: 1, Userid: 2, Title: "title", Description: "description", ReactionCount: 3, QuoteCount: 1, RepostOfID: sql.NullInt32{Int32: 5, Valid: true}, InReplyToID: sql.NullInt32{Int32: 2, Valid: true}, Visibility: PostVisibilityPublic}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get bookmarks",
+			args: args{
+				ctx: context.Background(),
+				arg: GetBookmarksParams{UserID: 2, CollectionID: sql.NullInt32{Int32: 3, Valid: true}, Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "repost_of_id", "quote_of_id", "in_reply_to_id", "visibility", "edited_at"}).AddRow("x", 2, "title", "description", 3, 0, 1, 0, 5, nil, 2, "public", nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get bookmarks",
+			args: args{
+				ctx: context.Background(),
+				arg: GetBookmarksParams{UserID: 2, CollectionID: sql.NullInt32{Int32: 3, Valid: true}, Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetBookmarks(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBookmarks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBookmarks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CreateQuote(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg CreateQuoteParams
+	}
+
+	q := `-- name: CreateQuote :one
+	INSERT INTO posts (
+	  userid, title, description, quote_of_id
+	) VALUES (
+	  $1, '', $2, $3::int
+	)
+	RETURNING id, userid, description
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     CreateQuoteRow
+		wantErr  bool
+	}{
+		{
+			name: "success create quote",
+			args: args{
+				ctx: context.Background(),
+				arg: CreateQuoteParams{Userid: 2, Description: "so true", QuoteOfID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "description"}).AddRow(7, 2, "so true")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, "so true", 1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    CreateQuoteRow{ID: 7, Userid: 2, Description: "so true"},
+			wantErr: false,
+		},
+		{
+			name: "error create quote",
+			args: args{
+				ctx: context.Background(),
+				arg: CreateQuoteParams{Userid: 2, Description: "so true", QuoteOfID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, "so true", 1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    CreateQuoteRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.CreateQuote(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateQuote() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateQuote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CreateRepost(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg CreateRepostParams
+	}
+
+	q := `-- name: CreateRepost :one
+	INSERT INTO posts (
+	  userid, title, description, repost_of_id
+	) VALUES (
+	  $1, '', '', $2::int
+	)
+	ON CONFLICT (userid, repost_of_id) DO UPDATE SET repost_of_id = EXCLUDED.repost_of_id
+	RETURNING id, userid
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     CreateRepostRow
+		wantErr  bool
+	}{
+		{
+			name: "success create repost",
+			args: args{
+				ctx: context.Background(),
+				arg: CreateRepostParams{Userid: 2, RepostOfID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid"}).AddRow(7, 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, 1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    CreateRepostRow{ID: 7, Userid: 2},
+			wantErr: false,
+		},
+		{
+			name: "error create repost",
+			args: args{
+				ctx: context.Background(),
+				arg: CreateRepostParams{Userid: 2, RepostOfID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, 1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    CreateRepostRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.CreateRepost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateRepost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateRepost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DeleteRepost(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg DeleteRepostParams
+	}
+
+	q := `-- name: DeleteRepost :exec
+	DELETE FROM posts
+	WHERE userid = $1
+	  AND repost_of_id = (
+	    SELECT COALESCE(o.repost_of_id, o.id) FROM posts o WHERE o.id = $2
+	  )
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success delete repost",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteRepostParams{Userid: 2, PostID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error delete repost",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteRepostParams{Userid: 2, PostID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.DeleteRepost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteRepost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetPostsByIDs(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetPostsByIDsParams
+	}
+
+	q := `-- name: GetPostsByIDs :many
+	SELECT p.id, p.userid, p.title, p.description,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+	  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $1, false)) AS reply_count,
+	  p.edited_at
+	FROM posts p
+	WHERE p.id = ANY($2::int[])
+	  AND post_visible_to(p.id, $1, false)
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetPostsByIDsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get posts by ids",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostsByIDsParams{Ids: []int32{1, 4}, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "edited_at"}).AddRow(1, 2, "title", "description", 3, 1, 0, 4, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(3, "{1,4}").WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetPostsByIDsRow{{ID: 1, Userid: 2, Title: "title", Description: "description", ReactionCount: 3, RepostCount: 1, ReplyCount: 4}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get posts by ids",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostsByIDsParams{Ids: []int32{1, 4}, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "repost_count", "quote_count", "reply_count", "edited_at"}).AddRow("x", 2, "title", "description", 3, 1, 0, 4, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get posts by ids",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostsByIDsParams{Ids: []int32{1, 4}, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetPostsByIDs(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPostsByIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostsByIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetRepostTarget(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		id  int32
+	}
+
+	q := `-- name: GetRepostTarget :one
+	SELECT o.id, o.userid FROM posts p JOIN posts o
+	ON o.id = COALESCE(p.repost_of_id, p.id)
+	WHERE p.id = $1
+	  AND post_visible_to(o.id, 0, false)
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     GetRepostTargetRow
+		wantErr  bool
+	}{
+		{
+			name: "success get repost target",
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid"}).AddRow(1, 3)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetRepostTargetRow{ID: 1, Userid: 3},
+			wantErr: false,
+		},
+		{
+			name: "error get repost target",
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetRepostTargetRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetRepostTarget(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetRepostTarget() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRepostTarget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetReplyChain(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetReplyChainParams
+	}
+
+	q := `-- name: GetReplyChain :many
+	WITH RECURSIVE chain AS (
+	  SELECT p.id, 0 AS distance FROM posts p
+	  WHERE p.id = $1 AND p.deleted_at IS NULL
+	    AND post_visible_to(p.id, $2, false)
+	  UNION ALL
+	  SELECT p.in_reply_to_id, c.distance + 1
+	  FROM chain c JOIN posts p ON p.id = c.id
+	  WHERE p.in_reply_to_id IS NOT NULL
+	)
+	SELECT p.id, p.userid, p.title, p.description,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $2, false)) AS reply_count,
+	  p.edited_at
+	FROM chain c JOIN posts p ON p.id = c.id
+	WHERE post_visible_to(p.id, $2, false)
+	ORDER BY c.distance DESC
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetReplyChainRow
+		wantErr  bool
+	}{
+		{
+			name: "success get reply chain",
+			args: args{
+				ctx: context.Background(),
+				arg: GetReplyChainParams{ID: 2, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "reply_count", "edited_at"}).AddRow(1, 1, "title", "description", 3, 1, nil).AddRow(2, 2, "reply", "reply", 0, 0, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, 3).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetReplyChainRow{{ID: 1, Userid: 1, Title: "title", Description: "description", ReactionCount: 3, ReplyCount: 1}, {ID: 2, Userid: 2, Title: "reply", Description: "reply"}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get reply chain",
+			args: args{
+				ctx: context.Background(),
+				arg: GetReplyChainParams{ID: 2, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "reaction_count", "reply_count", "edited_at"}).AddRow("x", 1, "title", "description", 3, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get reply chain",
+			args: args{
+				ctx: context.Background(),
+				arg: GetReplyChainParams{ID: 2, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetReplyChain(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetReplyChain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetReplyChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetReplyTree(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetReplyTreeParams
+	}
+
+	q := `-- name: GetReplyTree :many
+	WITH RECURSIVE tree AS (
+	  SELECT top.id, 1 AS depth FROM (
+	    SELECT c.id FROM posts c
+	    WHERE c.in_reply_to_id = $1::int AND c.deleted_at IS NULL
+	      AND post_visible_to(c.id, $2, false)
+	    ORDER BY c.created_at, c.id
+	    LIMIT $3 OFFSET $4
+	  ) top
+	  UNION ALL
+	  SELECT c.id, t.depth + 1
+	  FROM tree t CROSS JOIN LATERAL (
+	    SELECT c.id FROM posts c
+	    WHERE c.in_reply_to_id = t.id AND c.deleted_at IS NULL
+	      AND post_visible_to(c.id, $2, false)
+	    ORDER BY c.created_at, c.id
+	    LIMIT $3
+	  ) c
+	  WHERE t.depth < $5::int
+	)
+	SELECT p.id, p.userid, p.title, p.description, p.in_reply_to_id,
+	  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+	  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+	    AND post_visible_to(cp.id, $2, false)) AS reply_count,
+	  p.edited_at
+	FROM tree t JOIN posts p ON p.id = t.id
+	ORDER BY t.depth, p.created_at, p.id
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetReplyTreeRow
+		wantErr  bool
+	}{
+		{
+			name: "success get reply tree",
+			args: args{
+				ctx: context.Background(),
+				arg: GetReplyTreeParams{ID: 1, ViewerID: 4, Limit: 20, Depth: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "in_reply_to_id", "reaction_count", "reply_count", "edited_at"}).AddRow(2, 2, "reply", "reply", 1, 0, 1, nil).AddRow(3, 1, "reply", "reply", 2, 1, 0, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 4, 20, 0, 3).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetReplyTreeRow{{ID: 2, Userid: 2, Title: "reply", Description: "reply", InReplyToID: sql.NullInt32{Int32: 1, Valid: true}, ReplyCount: 1}, {ID: 3, Userid: 1, Title: "reply", Description: "reply", InReplyToID: sql.NullInt32{Int32: 2, Valid: true}, ReactionCount: 1}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get reply tree",
+			args: args{
+				ctx: context.Background(),
+				arg: GetReplyTreeParams{ID: 1, ViewerID: 4, Limit: 20, Depth: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "in_reply_to_id", "reaction_count", "reply_count", "edited_at"}).AddRow("x", 2, "reply", "reply", 1, 0, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get reply tree",
+			args: args{
+				ctx: context.Background(),
+				arg: GetReplyTreeParams{ID: 1, ViewerID: 4, Limit: 20, Depth: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetReplyTree(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetReplyTree() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetReplyTree() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetVisiblePost(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg GetVisiblePostParams
+	}
+
+	editedAt := time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC)
+	q := `-- name: GetVisiblePost :one
+	SELECT id, userid, title, description, visibility, status, publish_at, edited_at, version FROM posts
+	WHERE id = $1 AND post_visible_to(id, $2, false)
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     GetVisiblePostRow
+		wantErr  bool
+	}{
+		{
+			name: "success get visible post",
+			args: args{
+				ctx: context.Background(),
+				arg: GetVisiblePostParams{ID: 1, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "userid", "title", "description", "visibility", "status", "publish_at", "edited_at", "version"}).AddRow(1, 2, "holiday yay", "yeah yeah yeah", "followers", "published", nil, editedAt, 3)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 3).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetVisiblePostRow{ID: 1, Userid: 2, Title: "holiday yay", Description: "yeah yeah yeah", Visibility: PostVisibilityFollowers, Status: PostStatusPublished, EditedAt: sql.NullTime{Time: editedAt, Valid: true}, Version: 3},
+			wantErr: false,
+		},
+		{
+			name: "error get visible post",
+			args: args{
+				ctx: context.Background(),
+				arg: GetVisiblePostParams{ID: 1, ViewerID: 3},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 3).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetVisiblePostRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetVisiblePost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetVisiblePost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetVisiblePost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_PublishDuePosts(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg PublishDuePostsParams
+	}
+
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	q := `-- name: PublishDuePosts :many
+	UPDATE posts p
+	  set status = 'published',
+	  created_at = p.publish_at,
+	  version = p.version + 1
+	FROM (
+	  SELECT d.id FROM posts d
+	  WHERE d.status = 'scheduled' AND d.publish_at <= $1::timestamp
+	  ORDER BY d.publish_at, d.id
+	  LIMIT $2
+	  FOR UPDATE SKIP LOCKED
+	) due
+	WHERE p.id = due.id AND p.status = 'scheduled'
+	RETURNING p.id
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []int32
+		wantErr  bool
+	}{
+		{
+			name: "success publish due posts",
+			args: args{
+				ctx: context.Background(),
+				arg: PublishDuePostsParams{Now: now, Limit: 100},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(5)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(now, 100).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []int32{3, 5},
+			wantErr: false,
+		},
+		{
+			name: "error publish due posts",
+			args: args{
+				ctx: context.Background(),
+				arg: PublishDuePostsParams{Now: now, Limit: 100},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(now, 100).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error scan publish due posts",
+			args: args{
+				ctx: context.Background(),
+				arg: PublishDuePostsParams{Now: now, Limit: 100},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("three")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(now, 100).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.PublishDuePosts(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PublishDuePosts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PublishDuePosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CreatePostRevision(t *testing.T) {
+	createdAt := time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg CreatePostRevisionParams
+	}
+
+	q := `-- name: CreatePostRevision :one
+	INSERT INTO post_revisions (
+	  post_id, revision, title, description, tag_ids, editor_id
+	)
+	SELECT p.id,
+	  COALESCE((SELECT max(r.revision) FROM post_revisions r WHERE r.post_id = p.id), 0) + 1,
+	  p.title, p.description,
+	  COALESCE((
+	    SELECT array_agg(DISTINCT pt.tagid ORDER BY pt.tagid) FROM post_tags pt WHERE pt.postid = p.id
+	  ), '{}')::int[],
+	  COALESCE($1::int, p.userid)
+	FROM posts p
+	WHERE p.id = $2
+	RETURNING post_id, revision, title, description, tag_ids, editor_id, created_at
+	`
+	columns := []string{"post_id", "revision", "title", "description", "tag_ids", "editor_id", "created_at"}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     PostRevision
+		wantErr  bool
+	}{
+		{
+			name: "success create revision by the author",
+			args: args{
+				ctx: context.Background(),
+				arg: CreatePostRevisionParams{PostID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(columns).AddRow(1, 1, "holiday", "yay", "{}", 2, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(nil, 1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: PostRevision{
+				PostID:      1,
+				Revision:    1,
+				Title:       "holiday",
+				Description: "yay",
+				TagIds:      []int32{},
+				EditorID:    sql.NullInt32{Int32: 2, Valid: true},
+				CreatedAt:   createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success create revision by an editor",
+			args: args{
+				ctx: context.Background(),
+				arg: CreatePostRevisionParams{PostID: 1, EditorID: sql.NullInt32{Int32: 5, Valid: true}},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "holiday yay", "yay yay", "{1,3}", 5, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(5, 1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: PostRevision{
+				PostID:      1,
+				Revision:    2,
+				Title:       "holiday yay",
+				Description: "yay yay",
+				TagIds:      []int32{1, 3},
+				EditorID:    sql.NullInt32{Int32: 5, Valid: true},
+				CreatedAt:   createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error create revision",
+			args: args{
+				ctx: context.Background(),
+				arg: CreatePostRevisionParams{PostID: 1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(nil, 1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    PostRevision{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.CreatePostRevision(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreatePostRevision() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreatePostRevision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPostRevision(t *testing.T) {
+	createdAt := time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg GetPostRevisionParams
+	}
+
+	q := `-- name: GetPostRevision :one
+	SELECT post_id, revision, title, description, tag_ids, editor_id, created_at FROM post_revisions
+	WHERE post_id = $1 AND revision = $2
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     PostRevision
+		wantErr  bool
+	}{
+		{
+			name: "success get revision",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostRevisionParams{PostID: 1, Revision: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"post_id", "revision", "title", "description", "tag_ids", "editor_id", "created_at"}).AddRow(1, 2, "holiday", "yay", "{4}", nil, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: PostRevision{
+				PostID:      1,
+				Revision:    2,
+				Title:       "holiday",
+				Description: "yay",
+				TagIds:      []int32{4},
+				CreatedAt:   createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get revision",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostRevisionParams{PostID: 1, Revision: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    PostRevision{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetPostRevision(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPostRevision() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostRevision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPostRevisions(t *testing.T) {
+	createdAt := time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg GetPostRevisionsParams
+	}
+
+	q := `-- name: GetPostRevisions :many
+	SELECT post_id, revision, title, description, tag_ids, editor_id, created_at FROM post_revisions
+	WHERE post_id = $1
+	ORDER BY revision DESC
+	LIMIT $2 OFFSET $3
+	`
+	columns := []string{"post_id", "revision", "title", "description", "tag_ids", "editor_id", "created_at"}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []PostRevision
+		wantErr  bool
+	}{
+		{
+			name: "success get revisions",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostRevisionsParams{PostID: 1, Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 2, "holiday yay", "yay", "{1}", 3, createdAt.Add(time.Hour)).
+					AddRow(1, 1, "holiday", "yay", "{}", 2, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 20, 0).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []PostRevision{
+				{PostID: 1, Revision: 2, Title: "holiday yay", Description: "yay", TagIds: []int32{1}, EditorID: sql.NullInt32{Int32: 3, Valid: true}, CreatedAt: createdAt.Add(time.Hour)},
+				{PostID: 1, Revision: 1, Title: "holiday", Description: "yay", TagIds: []int32{}, EditorID: sql.NullInt32{Int32: 2, Valid: true}, CreatedAt: createdAt},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan revisions",
+			args: args{
+				ctx: context.Background(),
+				arg: GetPostRevisionsParams{PostID: 1, Limit: 20},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows(columns).AddRow("x", 1, "holiday", "yay", "{}", 2, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, 20, 0).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetPostRevisions(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPostRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostRevisions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddReaction(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg AddReactionParams
+	}
+
+	q := `-- name: AddReaction :execrows
+	INSERT INTO reactions (post_id, user_id, kind)
+	SELECT p.id, u.id, $1::text
+	FROM posts p, users u
+	WHERE p.id = $2 AND u.id = $3
+	  AND post_visible_to(p.id, u.id, false)
+	ON CONFLICT (post_id, user_id) DO UPDATE SET kind = EXCLUDED.kind
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success add reaction",
+			args: args{
+				ctx: context.Background(),
+				arg: AddReactionParams{Kind: "like", PostID: 1, UserID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("like", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "hidden post adds nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: AddReactionParams{Kind: "like", PostID: 1, UserID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("like", 1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error add reaction",
+			args: args{
+				ctx: context.Background(),
+				arg: AddReactionParams{Kind: "like", PostID: 1, UserID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.AddReaction(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddReaction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AddReaction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RemoveReaction(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg RemoveReactionParams
+	}
+
+	q := `-- name: RemoveReaction :exec
+	DELETE FROM reactions
+	WHERE post_id = $1 AND user_id = $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success remove reaction",
+			args: args{
+				ctx: context.Background(),
+				arg: RemoveReactionParams{PostID: 1, UserID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error remove reaction",
+			args: args{
+				ctx: context.Background(),
+				arg: RemoveReactionParams{PostID: 1, UserID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if err := p.RemoveReaction(tt.args.ctx, tt.args.arg); (err != nil) != tt.wantErr {
+				t.Errorf("RemoveReaction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
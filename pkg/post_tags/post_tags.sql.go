@@ -7,6 +7,8 @@ package post_tags
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const createPostTag = `-- name: CreatePostTag :one
@@ -45,3 +47,47 @@ func (q *Queries) DeletePostTag(ctx context.Context, postid int32) error {
 	_, err := q.db.ExecContext(ctx, deletePostTag, postid)
 	return err
 }
+
+const deletePostTagsByTagIDs = `-- name: DeletePostTagsByTagIDs :exec
+DELETE FROM post_tags
+WHERE postid = $1 AND tagid = ANY($2::int[])
+`
+
+type DeletePostTagsByTagIDsParams struct {
+	Postid int32
+	TagIds []int32
+}
+
+func (q *Queries) DeletePostTagsByTagIDs(ctx context.Context, arg DeletePostTagsByTagIDsParams) error {
+	_, err := q.db.ExecContext(ctx, deletePostTagsByTagIDs, arg.Postid, pq.Array(arg.TagIds))
+	return err
+}
+
+const getPostTagIDs = `-- name: GetPostTagIDs :many
+SELECT tagid FROM post_tags
+WHERE postid = $1
+ORDER BY id
+`
+
+func (q *Queries) GetPostTagIDs(ctx context.Context, postid int32) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, getPostTagIDs, postid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var tagid int32
+		if err := rows.Scan(&tagid); err != nil {
+			return nil, err
+		}
+		items = append(items, tagid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	gomock "github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 )
 
 func TestNew(t *testing.T) {
@@ -226,3 +227,149 @@ func Test_DeleteTag(t *testing.T) {
 		})
 	}
 }
+
+func Test_DeletePostTagsByTagIDs(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg DeletePostTagsByTagIDsParams
+	}
+
+	q := `-- name: DeletePostTagsByTagIDs :exec
+	DELETE FROM post_tags
+	WHERE postid = $1 AND tagid = ANY($2::int[])
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success delete post tags by tag ids",
+			args: args{
+				ctx: context.Background(),
+				arg: DeletePostTagsByTagIDsParams{Postid: 1, TagIds: []int32{2, 3}},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, pq.Array([]int32{2, 3})).WillReturnResult(sqlmock.NewResult(0, 2))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error delete post tags by tag ids",
+			args: args{
+				ctx: context.Background(),
+				arg: DeletePostTagsByTagIDsParams{Postid: 1, TagIds: []int32{2, 3}},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, pq.Array([]int32{2, 3})).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if err := p.DeletePostTagsByTagIDs(tt.args.ctx, tt.args.arg); (err != nil) != tt.wantErr {
+				t.Errorf("DeletePostTagsByTagIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetPostTagIDs(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		postid int32
+	}
+
+	q := `-- name: GetPostTagIDs :many
+	SELECT tagid FROM post_tags
+	WHERE postid = $1
+	ORDER BY id
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []int32
+		wantErr  bool
+	}{
+		{
+			name: "success get post tag ids",
+			args: args{
+				ctx:    context.Background(),
+				postid: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"tagid"}).AddRow(2).AddRow(3)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []int32{2, 3},
+			wantErr: false,
+		},
+		{
+			name: "error scan get post tag ids",
+			args: args{
+				ctx:    context.Background(),
+				postid: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"tagid"}).AddRow("error")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get post tag ids",
+			args: args{
+				ctx:    context.Background(),
+				postid: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetPostTagIDs(tt.args.ctx, tt.args.postid)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPostTagIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostTagIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
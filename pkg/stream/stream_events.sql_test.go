@@ -0,0 +1,377 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	reflect "reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := NewMockDBTX(ctrl)
+
+	type args struct {
+		db DBTX
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Queries
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				db: dbMock,
+			},
+			want: &Queries{
+				db: dbMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	txMock := sql.Tx{}
+
+	type args struct {
+		tx *sql.Tx
+	}
+	tests := []struct {
+		name     string
+		args     args
+		initMock func() *Queries
+		want     *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				tx: &txMock,
+			},
+			initMock: func() *Queries {
+				return &Queries{
+					db: &txMock,
+				}
+			},
+			want: &Queries{
+				db: &txMock,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			if got := p.WithTx(tt.args.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetStreamEvents(t *testing.T) {
+	q := `-- name: GetStreamEvents :many
+	SELECT id, kind, recipient_id, post_id, actor_id, payload, created_at
+	FROM stream_events
+	WHERE id = ANY($1::bigint[])
+	ORDER BY id
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []StreamEvent
+		wantErr  bool
+	}{
+		{
+			name: "success get stream events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("{1,2}").WillReturnRows(
+					sqlmock.NewRows([]string{"id", "kind", "recipient_id", "post_id", "actor_id", "payload", "created_at"}).
+						AddRow(1, "post", nil, 7, 2, []byte(`{"id":7}`), createdAt).
+						AddRow(2, "notification", 1, nil, 3, []byte(`{"id":4}`), createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []StreamEvent{
+				{ID: 1, Kind: StreamEventKindPost, PostID: sql.NullInt32{Int32: 7, Valid: true}, ActorID: sql.NullInt32{Int32: 2, Valid: true}, Payload: json.RawMessage(`{"id":7}`), CreatedAt: createdAt},
+				{ID: 2, Kind: StreamEventKindNotification, RecipientID: sql.NullInt32{Int32: 1, Valid: true}, ActorID: sql.NullInt32{Int32: 3, Valid: true}, Payload: json.RawMessage(`{"id":4}`), CreatedAt: createdAt},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get stream events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetStreamEvents(context.Background(), []int64{1, 2})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetStreamEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStreamEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetStreamEventsAfter(t *testing.T) {
+	q := `-- name: GetStreamEventsAfter :many
+	SELECT e.id, e.kind, e.recipient_id, e.post_id, e.actor_id, e.payload, e.created_at
+	FROM stream_events e
+	WHERE e.id > $1
+	  AND stream_event_visible_to(e, $2)
+	ORDER BY e.id
+	LIMIT $3
+	`
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []StreamEvent
+		wantErr  bool
+	}{
+		{
+			name: "success get stream events after",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(5, 1, 100).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "kind", "recipient_id", "post_id", "actor_id", "payload", "created_at"}).
+						AddRow(6, "reaction_count", nil, 7, nil, []byte(`{"post_id":7,"reaction_count":2}`), createdAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []StreamEvent{
+				{ID: 6, Kind: StreamEventKindReactionCount, PostID: sql.NullInt32{Int32: 7, Valid: true}, Payload: json.RawMessage(`{"post_id":7,"reaction_count":2}`), CreatedAt: createdAt},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get stream events after",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetStreamEventsAfter(context.Background(), GetStreamEventsAfterParams{AfterID: 5, ViewerID: 1, Limit: 100})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetStreamEventsAfter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStreamEventsAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetStreamRecipients(t *testing.T) {
+	q := `-- name: GetStreamRecipients :many
+	SELECT e.id AS event_id, v.id::int AS viewer_id
+	FROM stream_events e
+	CROSS JOIN unnest($1::int[]) AS v(id)
+	WHERE e.id = ANY($2::bigint[])
+	  AND stream_event_visible_to(e, v.id)
+	ORDER BY e.id, v.id
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     []GetStreamRecipientsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get stream recipients",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("{1,2}", "{5}").WillReturnRows(
+					sqlmock.NewRows([]string{"event_id", "viewer_id"}).
+						AddRow(5, 1).
+						AddRow(5, 2))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetStreamRecipientsRow{{EventID: 5, ViewerID: 1}, {EventID: 5, ViewerID: 2}},
+			wantErr: false,
+		},
+		{
+			name: "error get stream recipients",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetStreamRecipients(context.Background(), GetStreamRecipientsParams{ViewerIds: []int32{1, 2}, Ids: []int64{5}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetStreamRecipients() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStreamRecipients() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_PurgeStreamEvents(t *testing.T) {
+	q := `-- name: PurgeStreamEvents :execrows
+	DELETE FROM stream_events
+	WHERE created_at < now() - make_interval(mins => $1::int)
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success purge stream events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(60).WillReturnResult(sqlmock.NewResult(0, 12))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    12,
+			wantErr: false,
+		},
+		{
+			name: "error purge stream events",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.PurgeStreamEvents(context.Background(), 60)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PurgeStreamEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PurgeStreamEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_StreamUserExists(t *testing.T) {
+	q := `-- name: StreamUserExists :one
+	SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name: "success stream user exists",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(
+					sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "error stream user exists",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.StreamUserExists(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StreamUserExists() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("StreamUserExists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,10 @@ package tag
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 const createTag = `-- name: CreateTag :one
@@ -30,30 +34,112 @@ func (q *Queries) CreateTag(ctx context.Context, tagname string) (CreateTagRow,
 	return i, err
 }
 
-const deleteTag = `-- name: DeleteTag :exec
+const deleteStaleTagTrends = `-- name: DeleteStaleTagTrends :exec
+DELETE FROM tag_trends
+WHERE window_minutes = $1
+  AND computed_at < $2
+`
+
+type DeleteStaleTagTrendsParams struct {
+	WindowMinutes int32
+	ComputedAt    time.Time
+}
+
+func (q *Queries) DeleteStaleTagTrends(ctx context.Context, arg DeleteStaleTagTrendsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteStaleTagTrends, arg.WindowMinutes, arg.ComputedAt)
+	return err
+}
+
+const deleteTag = `-- name: DeleteTag :execrows
 DELETE FROM tags
 WHERE id = $1
+  AND version = COALESCE($2, version)
 `
 
-func (q *Queries) DeleteTag(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteTag, id)
-	return err
+type DeleteTagParams struct {
+	ID      int32
+	Version sql.NullInt32
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTag, arg.ID, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const followTag = `-- name: FollowTag :execrows
+INSERT INTO tag_follows (user_id, tag_id)
+SELECT u.id, t.id FROM users u, tags t
+WHERE u.id = $1 AND t.id = $2
+ON CONFLICT (user_id, tag_id) DO UPDATE SET created_at = tag_follows.created_at
+`
+
+type FollowTagParams struct {
+	UserID int32
+	TagID  int32
+}
+
+func (q *Queries) FollowTag(ctx context.Context, arg FollowTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, followTag, arg.UserID, arg.TagID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFollowedTags = `-- name: GetFollowedTags :many
+SELECT t.id, t.tagname
+FROM tag_follows tf JOIN tags t
+ON t.id = tf.tag_id
+WHERE tf.user_id = $1
+ORDER BY t.tagname, t.id
+`
+
+type GetFollowedTagsRow struct {
+	ID      int32
+	Tagname string
+}
+
+func (q *Queries) GetFollowedTags(ctx context.Context, userID int32) ([]GetFollowedTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedTags, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowedTagsRow
+	for rows.Next() {
+		var i GetFollowedTagsRow
+		if err := rows.Scan(&i.ID, &i.Tagname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getTag = `-- name: GetTag :one
-SELECT id, tagname FROM tags
+SELECT id, tagname, version FROM tags
 WHERE id = $1 LIMIT 1
 `
 
 type GetTagRow struct {
 	ID      int32
 	Tagname string
+	Version int32
 }
 
 func (q *Queries) GetTag(ctx context.Context, id int32) (GetTagRow, error) {
 	row := q.db.QueryRowContext(ctx, getTag, id)
 	var i GetTagRow
-	err := row.Scan(&i.ID, &i.Tagname)
+	err := row.Scan(&i.ID, &i.Tagname, &i.Version)
 	return i, err
 }
 
@@ -94,6 +180,57 @@ func (q *Queries) GetTagByPostID(ctx context.Context, postid int32) ([]GetTagByP
 	return items, nil
 }
 
+const getTagUsageCounts = `-- name: GetTagUsageCounts :many
+SELECT pt.tagid,
+  count(*) FILTER (WHERE p.created_at >= $1::timestamp) AS recent_count,
+  count(*) FILTER (WHERE p.created_at < $1::timestamp) AS baseline_count
+FROM post_tags pt JOIN posts p
+ON p.id = pt.postid
+WHERE p.status = 'published'
+  AND p.visibility = 'public'
+  AND p.in_reply_to_id IS NULL
+  AND p.created_at >= $2::timestamp
+  AND p.created_at < $3::timestamp
+GROUP BY pt.tagid
+`
+
+type GetTagUsageCountsParams struct {
+	WindowStart   time.Time
+	BaselineStart time.Time
+	WindowEnd     time.Time
+}
+
+type GetTagUsageCountsRow struct {
+	Tagid         int32
+	RecentCount   int64
+	BaselineCount int64
+}
+
+// created_at holds the publish time once a post is published, so only
+// posts that show up in listings count, at the time they went out.
+func (q *Queries) GetTagUsageCounts(ctx context.Context, arg GetTagUsageCountsParams) ([]GetTagUsageCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagUsageCounts, arg.WindowStart, arg.BaselineStart, arg.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagUsageCountsRow
+	for rows.Next() {
+		var i GetTagUsageCountsRow
+		if err := rows.Scan(&i.Tagid, &i.RecentCount, &i.BaselineCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTags = `-- name: GetTags :many
 SELECT id, tagname FROM tags
 `
@@ -126,26 +263,254 @@ func (q *Queries) GetTags(ctx context.Context) ([]GetTagsRow, error) {
 	return items, nil
 }
 
+const getTagsByPostIDs = `-- name: GetTagsByPostIDs :many
+SELECT
+	a.postid,
+	b.id,
+	b.tagname
+FROM post_tags a JOIN tags b
+ON a.tagID = b.id
+WHERE a.postid = ANY($1::int[])
+ORDER BY a.postid, a.id
+`
+
+type GetTagsByPostIDsRow struct {
+	Postid  int32
+	ID      int32
+	Tagname string
+}
+
+func (q *Queries) GetTagsByPostIDs(ctx context.Context, postids []int32) ([]GetTagsByPostIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagsByPostIDs, pq.Array(postids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsByPostIDsRow
+	for rows.Next() {
+		var i GetTagsByPostIDsRow
+		if err := rows.Scan(&i.Postid, &i.ID, &i.Tagname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrendingTags = `-- name: GetTrendingTags :many
+SELECT t.id, t.tagname, tt.recent_count, tt.baseline_count, tt.score, tt.computed_at
+FROM tag_trends tt JOIN tags t
+ON t.id = tt.tag_id
+WHERE tt.window_minutes = $1
+  AND tt.recent_count > 0
+ORDER BY tt.score DESC, tt.recent_count DESC, t.id
+LIMIT $2
+`
+
+type GetTrendingTagsParams struct {
+	WindowMinutes int32
+	Limit         int32
+}
+
+type GetTrendingTagsRow struct {
+	ID            int32
+	Tagname       string
+	RecentCount   int32
+	BaselineCount int32
+	Score         float64
+	ComputedAt    time.Time
+}
+
+func (q *Queries) GetTrendingTags(ctx context.Context, arg GetTrendingTagsParams) ([]GetTrendingTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrendingTags, arg.WindowMinutes, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrendingTagsRow
+	for rows.Next() {
+		var i GetTrendingTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Tagname,
+			&i.RecentCount,
+			&i.BaselineCount,
+			&i.Score,
+			&i.ComputedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const patchTag = `-- name: PatchTag :one
+UPDATE tags
+  set tagname = COALESCE($1, tagname),
+  version = version + 1
+WHERE id = $2
+  AND version = COALESCE($3, version)
+RETURNING id, tagname, version
+`
+
+type PatchTagParams struct {
+	Tagname sql.NullString
+	ID      int32
+	Version sql.NullInt32
+}
+
+type PatchTagRow struct {
+	ID      int32
+	Tagname string
+	Version int32
+}
+
+func (q *Queries) PatchTag(ctx context.Context, arg PatchTagParams) (PatchTagRow, error) {
+	row := q.db.QueryRowContext(ctx, patchTag, arg.Tagname, arg.ID, arg.Version)
+	var i PatchTagRow
+	err := row.Scan(&i.ID, &i.Tagname, &i.Version)
+	return i, err
+}
+
+const searchTags = `-- name: SearchTags :many
+SELECT t.id, t.tagname, count(pt.id) AS usage_count
+FROM tags t LEFT JOIN post_tags pt
+ON pt.tagid = t.id
+WHERE t.tagname ILIKE $1 OR t.tagname % $2
+GROUP BY t.id
+ORDER BY t.tagname ILIKE $1 DESC,
+  usage_count DESC,
+  similarity(t.tagname, $2) DESC,
+  t.id
+LIMIT $3
+`
+
+type SearchTagsParams struct {
+	Prefix string
+	Query  string
+	Limit  int32
+}
+
+type SearchTagsRow struct {
+	ID         int32
+	Tagname    string
+	UsageCount int64
+}
+
+func (q *Queries) SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchTags, arg.Prefix, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchTagsRow
+	for rows.Next() {
+		var i SearchTagsRow
+		if err := rows.Scan(&i.ID, &i.Tagname, &i.UsageCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unfollowTag = `-- name: UnfollowTag :exec
+DELETE FROM tag_follows
+WHERE user_id = $1 AND tag_id = $2
+`
+
+type UnfollowTagParams struct {
+	UserID int32
+	TagID  int32
+}
+
+func (q *Queries) UnfollowTag(ctx context.Context, arg UnfollowTagParams) error {
+	_, err := q.db.ExecContext(ctx, unfollowTag, arg.UserID, arg.TagID)
+	return err
+}
+
 const updateTag = `-- name: UpdateTag :one
 UPDATE tags
-  set tagname = $2
-WHERE id = $1
-RETURNING id, tagname
+  set tagname = $1,
+  version = version + 1
+WHERE id = $2
+  AND version = COALESCE($3, version)
+RETURNING id, tagname, version
 `
 
 type UpdateTagParams struct {
-	ID      int32
 	Tagname string
+	ID      int32
+	Version sql.NullInt32
 }
 
 type UpdateTagRow struct {
 	ID      int32
 	Tagname string
+	Version int32
 }
 
 func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (UpdateTagRow, error) {
-	row := q.db.QueryRowContext(ctx, updateTag, arg.ID, arg.Tagname)
+	row := q.db.QueryRowContext(ctx, updateTag, arg.Tagname, arg.ID, arg.Version)
 	var i UpdateTagRow
-	err := row.Scan(&i.ID, &i.Tagname)
+	err := row.Scan(&i.ID, &i.Tagname, &i.Version)
 	return i, err
 }
+
+const upsertTagTrends = `-- name: UpsertTagTrends :exec
+INSERT INTO tag_trends (
+  tag_id, window_minutes, recent_count, baseline_count, score, computed_at
+)
+SELECT
+  unnest($1::int[]),
+  $2::int,
+  unnest($3::int[]),
+  unnest($4::int[]),
+  unnest($5::float8[]),
+  $6::timestamp
+ON CONFLICT (tag_id, window_minutes) DO UPDATE SET
+  recent_count = EXCLUDED.recent_count,
+  baseline_count = EXCLUDED.baseline_count,
+  score = EXCLUDED.score,
+  computed_at = EXCLUDED.computed_at
+`
+
+type UpsertTagTrendsParams struct {
+	TagIds         []int32
+	WindowMinutes  int32
+	RecentCounts   []int32
+	BaselineCounts []int32
+	Scores         []float64
+	ComputedAt     time.Time
+}
+
+func (q *Queries) UpsertTagTrends(ctx context.Context, arg UpsertTagTrendsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTagTrends,
+		pq.Array(arg.TagIds),
+		arg.WindowMinutes,
+		pq.Array(arg.RecentCounts),
+		pq.Array(arg.BaselineCounts),
+		pq.Array(arg.Scores),
+		arg.ComputedAt,
+	)
+	return err
+}
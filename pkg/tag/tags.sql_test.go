@@ -7,6 +7,7 @@ import (
 	reflect "reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	gomock "github.com/golang/mock/gomock"
@@ -162,60 +163,91 @@ func Test_CreateTag(t *testing.T) {
 func Test_DeleteTag(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		id  int32
+		arg DeleteTagParams
 	}
 
-	q := `-- name: DeleteTag :exec
+	q := `-- name: DeleteTag :execrows
 		DELETE FROM tags
 		WHERE id = $1
+		AND version = COALESCE($2, version)
 	`
 	tests := []struct {
 		name     string
 		initMock func() *Queries
 		args     args
+		want     int64
 		wantErr  bool
 	}{
 		{
 			name: "success delete tag",
 			args: args{
 				ctx: context.Background(),
-				id:  1,
+				arg: DeleteTagParams{
+					ID: 1,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "stale version deletes nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteTagParams{
+					ID:      1,
+					Version: sql.NullInt32{Int32: 2, Valid: true},
+				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
 
 				return &Queries{
 					db: dbMock,
 				}
 			},
+			want:    0,
 			wantErr: false,
 		},
 		{
 			name: "error delete tag",
 			args: args{
 				ctx: context.Background(),
-				id:  1,
+				arg: DeleteTagParams{
+					ID: 1,
+				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
 				}
 			},
+			want:    0,
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.initMock()
-			err := p.DeleteTag(tt.args.ctx, tt.args.id)
+			got, err := p.DeleteTag(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteTag() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if got != tt.want {
+				t.Errorf("DeleteTag() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -227,7 +259,7 @@ func Test_GetTag(t *testing.T) {
 	}
 
 	q := `-- name: GetTag :one
-		SELECT id, tagname FROM tags
+		SELECT id, tagname, version FROM tags
 		WHERE id = $1 LIMIT 1
 	`
 	tests := []struct {
@@ -245,7 +277,7 @@ func Test_GetTag(t *testing.T) {
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "tagname"}).AddRow(1, "holiday")
+				rows := sqlmock.NewRows([]string{"id", "tagname", "version"}).AddRow(1, "holiday", 3)
 				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
 
 				return &Queries{
@@ -255,6 +287,7 @@ func Test_GetTag(t *testing.T) {
 			want: GetTagRow{
 				ID:      1,
 				Tagname: "holiday",
+				Version: 3,
 			},
 			wantErr: false,
 		},
@@ -481,6 +514,213 @@ func Test_GetTags(t *testing.T) {
 	}
 }
 
+func Test_GetTagsByPostIDs(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		postids []int32
+	}
+
+	q := `-- name: GetTagsByPostIDs :many
+	SELECT
+		a.postid,
+		b.id,
+		b.tagname
+	FROM post_tags a JOIN tags b
+	ON a.tagID = b.id
+	WHERE a.postid = ANY($1::int[])
+	ORDER BY a.postid, a.id
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetTagsByPostIDsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get tags by post ids",
+			args: args{
+				ctx:     context.Background(),
+				postids: []int32{1, 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"postid", "id", "tagname"}).
+					AddRow(1, 1, "holiday").
+					AddRow(2, 1, "holiday")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("{1,2}").WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetTagsByPostIDsRow{
+				{
+					Postid:  1,
+					ID:      1,
+					Tagname: "holiday",
+				},
+				{
+					Postid:  2,
+					ID:      1,
+					Tagname: "holiday",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan get tags by post ids",
+			args: args{
+				ctx:     context.Background(),
+				postids: []int32{1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"postid", "id", "tagname"}).AddRow("error", 1, "holiday")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get tags by post ids",
+			args: args{
+				ctx:     context.Background(),
+				postids: []int32{1},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetTagsByPostIDs(tt.args.ctx, tt.args.postids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTagsByPostIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTagsByPostIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SearchTags(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg SearchTagsParams
+	}
+
+	q := `-- name: SearchTags :many
+	SELECT t.id, t.tagname, count(pt.id) AS usage_count
+	FROM tags t LEFT JOIN post_tags pt
+	ON pt.tagid = t.id
+	WHERE t.tagname ILIKE $1 OR t.tagname % $2
+	GROUP BY t.id
+	ORDER BY t.tagname ILIKE $1 DESC,
+	  usage_count DESC,
+	  similarity(t.tagname, $2) DESC,
+	  t.id
+	LIMIT $3
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []SearchTagsRow
+		wantErr  bool
+	}{
+		{
+			name: "success search tags",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchTagsParams{Prefix: "hol%", Query: "hol", Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname", "usage_count"}).AddRow(1, "holiday", 12)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("hol%", "hol", 10).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []SearchTagsRow{
+				{
+					ID:         1,
+					Tagname:    "holiday",
+					UsageCount: 12,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan search tags",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchTagsParams{Prefix: "hol%", Query: "hol", Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname", "usage_count"}).AddRow("error", "holiday", 12)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error search tags",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchTagsParams{Prefix: "hol%", Query: "hol", Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.SearchTags(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_UpdateUser(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -489,9 +729,11 @@ func Test_UpdateUser(t *testing.T) {
 
 	q := `-- name: UpdateTag :one
 		UPDATE tags
-		set tagname = $2
-		WHERE id = $1
-		RETURNING id, tagname
+		set tagname = $1,
+		version = version + 1
+		WHERE id = $2
+		AND version = COALESCE($3, version)
+		RETURNING id, tagname, version
 	`
 	tests := []struct {
 		name     string
@@ -507,12 +749,13 @@ func Test_UpdateUser(t *testing.T) {
 				arg: UpdateTagParams{
 					ID:      1,
 					Tagname: "holiday",
+					Version: sql.NullInt32{Int32: 1, Valid: true},
 				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow(1, "holiday")
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "holiday").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "tagname", "version"}).AddRow(1, "holiday", 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday", 1, 1).WillReturnRows(rows)
 
 				return &Queries{
 					db: dbMock,
@@ -521,6 +764,7 @@ func Test_UpdateUser(t *testing.T) {
 			want: UpdateTagRow{
 				ID:      1,
 				Tagname: "holiday",
+				Version: 2,
 			},
 			wantErr: false,
 		},
@@ -535,7 +779,7 @@ func Test_UpdateUser(t *testing.T) {
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "holiday").WillReturnError(errors.New("error"))
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday", 1, nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
@@ -554,7 +798,678 @@ func Test_UpdateUser(t *testing.T) {
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("UpdateUser() = %v, want %v", got, tt.want)
+				t.Errorf("UpdateTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_PatchTag(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg PatchTagParams
+	}
+
+	q := `-- name: PatchTag :one
+		UPDATE tags
+		set tagname = COALESCE($1, tagname),
+		version = version + 1
+		WHERE id = $2
+		AND version = COALESCE($3, version)
+		RETURNING id, tagname, version
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     PatchTagRow
+		wantErr  bool
+	}{
+		{
+			name: "success patch tag",
+			args: args{
+				ctx: context.Background(),
+				arg: PatchTagParams{
+					ID:      1,
+					Tagname: sql.NullString{String: "holiday", Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname", "version"}).AddRow(1, "holiday", 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday", 1, nil).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: PatchTagRow{
+				ID:      1,
+				Tagname: "holiday",
+				Version: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error patch tag",
+			args: args{
+				ctx: context.Background(),
+				arg: PatchTagParams{
+					ID:      1,
+					Tagname: sql.NullString{String: "holiday", Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("holiday", 1, nil).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    PatchTagRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.PatchTag(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetTagUsageCounts(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg GetTagUsageCountsParams
+	}
+
+	q := `-- name: GetTagUsageCounts :many
+	SELECT pt.tagid,
+	  count(*) FILTER (WHERE p.created_at >= $1::timestamp) AS recent_count,
+	  count(*) FILTER (WHERE p.created_at < $1::timestamp) AS baseline_count
+	FROM post_tags pt JOIN posts p
+	ON p.id = pt.postid
+	WHERE p.status = 'published'
+	  AND p.visibility = 'public'
+	  AND p.in_reply_to_id IS NULL
+	  AND p.created_at >= $2::timestamp
+	  AND p.created_at < $3::timestamp
+	GROUP BY pt.tagid
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetTagUsageCountsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get tag usage counts",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTagUsageCountsParams{WindowStart: now.Add(-time.Hour), BaselineStart: now.Add(-169 * time.Hour), WindowEnd: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"tagid", "recent_count", "baseline_count"}).AddRow(1, 4, 21)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(now.Add(-time.Hour), now.Add(-169*time.Hour), now).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetTagUsageCountsRow{
+				{
+					Tagid:         1,
+					RecentCount:   4,
+					BaselineCount: 21,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan get tag usage counts",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTagUsageCountsParams{WindowStart: now.Add(-time.Hour), BaselineStart: now.Add(-169 * time.Hour), WindowEnd: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"tagid", "recent_count", "baseline_count"}).AddRow("error", 4, 21)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get tag usage counts",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTagUsageCountsParams{WindowStart: now.Add(-time.Hour), BaselineStart: now.Add(-169 * time.Hour), WindowEnd: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetTagUsageCounts(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTagUsageCounts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTagUsageCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetTrendingTags(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg GetTrendingTagsParams
+	}
+
+	q := `-- name: GetTrendingTags :many
+	SELECT t.id, t.tagname, tt.recent_count, tt.baseline_count, tt.score, tt.computed_at
+	FROM tag_trends tt JOIN tags t
+	ON t.id = tt.tag_id
+	WHERE tt.window_minutes = $1
+	  AND tt.recent_count > 0
+	ORDER BY tt.score DESC, tt.recent_count DESC, t.id
+	LIMIT $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetTrendingTagsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get trending tags",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTrendingTagsParams{WindowMinutes: 60, Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname", "recent_count", "baseline_count", "score", "computed_at"}).AddRow(1, "holiday", 4, 21, 2.5, now)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(60, 10).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetTrendingTagsRow{
+				{
+					ID:            1,
+					Tagname:       "holiday",
+					RecentCount:   4,
+					BaselineCount: 21,
+					Score:         2.5,
+					ComputedAt:    now,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan get trending tags",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTrendingTagsParams{WindowMinutes: 60, Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname", "recent_count", "baseline_count", "score", "computed_at"}).AddRow("error", "holiday", 4, 21, 2.5, now)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get trending tags",
+			args: args{
+				ctx: context.Background(),
+				arg: GetTrendingTagsParams{WindowMinutes: 60, Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetTrendingTags(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTrendingTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTrendingTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UpsertTagTrends(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg UpsertTagTrendsParams
+	}
+
+	q := `-- name: UpsertTagTrends :exec
+	INSERT INTO tag_trends (
+	  tag_id, window_minutes, recent_count, baseline_count, score, computed_at
+	)
+	SELECT
+	  unnest($1::int[]),
+	  $2::int,
+	  unnest($3::int[]),
+	  unnest($4::int[]),
+	  unnest($5::float8[]),
+	  $6::timestamp
+	ON CONFLICT (tag_id, window_minutes) DO UPDATE SET
+	  recent_count = EXCLUDED.recent_count,
+	  baseline_count = EXCLUDED.baseline_count,
+	  score = EXCLUDED.score,
+	  computed_at = EXCLUDED.computed_at
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success upsert tag trends",
+			args: args{
+				ctx: context.Background(),
+				arg: UpsertTagTrendsParams{TagIds: []int32{1, 2}, WindowMinutes: 60, RecentCounts: []int32{4, 1}, BaselineCounts: []int32{21, 0}, Scores: []float64{2.5, 1}, ComputedAt: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("{1,2}", 60, "{4,1}", "{21,0}", "{2.5,1}", now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error upsert tag trends",
+			args: args{
+				ctx: context.Background(),
+				arg: UpsertTagTrendsParams{TagIds: []int32{1, 2}, WindowMinutes: 60, RecentCounts: []int32{4, 1}, BaselineCounts: []int32{21, 0}, Scores: []float64{2.5, 1}, ComputedAt: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.UpsertTagTrends(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpsertTagTrends() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_DeleteStaleTagTrends(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		arg DeleteStaleTagTrendsParams
+	}
+
+	q := `-- name: DeleteStaleTagTrends :exec
+	DELETE FROM tag_trends
+	WHERE window_minutes = $1
+	  AND computed_at < $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success delete stale tag trends",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteStaleTagTrendsParams{WindowMinutes: 60, ComputedAt: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(60, now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error delete stale tag trends",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteStaleTagTrendsParams{WindowMinutes: 60, ComputedAt: now},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.DeleteStaleTagTrends(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteStaleTagTrends() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_FollowTag(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg FollowTagParams
+	}
+
+	q := `-- name: FollowTag :execrows
+	INSERT INTO tag_follows (user_id, tag_id)
+	SELECT u.id, t.id FROM users u, tags t
+	WHERE u.id = $1 AND t.id = $2
+	ON CONFLICT (user_id, tag_id) DO UPDATE SET created_at = tag_follows.created_at
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success follow tag",
+			args: args{
+				ctx: context.Background(),
+				arg: FollowTagParams{
+					UserID: 1,
+					TagID:  2,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "missing user or tag follows nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: FollowTagParams{
+					UserID: 1,
+					TagID:  2,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error follow tag",
+			args: args{
+				ctx: context.Background(),
+				arg: FollowTagParams{
+					UserID: 1,
+					TagID:  2,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.FollowTag(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FollowTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FollowTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UnfollowTag(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg UnfollowTagParams
+	}
+
+	q := `-- name: UnfollowTag :exec
+	DELETE FROM tag_follows
+	WHERE user_id = $1 AND tag_id = $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success unfollow tag",
+			args: args{
+				ctx: context.Background(),
+				arg: UnfollowTagParams{UserID: 1, TagID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unfollow tag",
+			args: args{
+				ctx: context.Background(),
+				arg: UnfollowTagParams{UserID: 1, TagID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.UnfollowTag(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnfollowTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetFollowedTags(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg int32
+	}
+
+	q := `-- name: GetFollowedTags :many
+	SELECT t.id, t.tagname
+	FROM tag_follows tf JOIN tags t
+	ON t.id = tf.tag_id
+	WHERE tf.user_id = $1
+	ORDER BY t.tagname, t.id
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetFollowedTagsRow
+		wantErr  bool
+	}{
+		{
+			name: "success get followed tags",
+			args: args{
+				ctx: context.Background(),
+				arg: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname"}).AddRow(1, "holiday")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    []GetFollowedTagsRow{{ID: 1, Tagname: "holiday"}},
+			wantErr: false,
+		},
+		{
+			name: "error scan get followed tags",
+			args: args{
+				ctx: context.Background(),
+				arg: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "tagname"}).AddRow("x", "holiday")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error get followed tags",
+			args: args{
+				ctx: context.Background(),
+				arg: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetFollowedTags(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFollowedTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFollowedTags() = %v, want %v", got, tt.want)
 			}
 		})
 	}
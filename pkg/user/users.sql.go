@@ -7,8 +7,70 @@ package user
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const addHandleRedirect = `-- name: AddHandleRedirect :exec
+INSERT INTO user_handle_redirects (handle, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (handle) DO UPDATE SET
+  user_id = EXCLUDED.user_id,
+  expires_at = EXCLUDED.expires_at
+`
+
+type AddHandleRedirectParams struct {
+	Handle    string
+	UserID    int32
+	ExpiresAt time.Time
+}
+
+func (q *Queries) AddHandleRedirect(ctx context.Context, arg AddHandleRedirectParams) error {
+	_, err := q.db.ExecContext(ctx, addHandleRedirect, arg.Handle, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const blockUser = `-- name: BlockUser :execrows
+WITH unfollowed AS (
+  DELETE FROM user_follows
+  WHERE (follower_id = $1 AND followee_id = $2)
+    OR (follower_id = $2 AND followee_id = $1)
+)
+INSERT INTO user_blocks (blocker_id, blocked_id)
+SELECT b.id, d.id FROM users b, users d
+WHERE b.id = $1 AND d.id = $2
+ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET created_at = user_blocks.created_at
+`
+
+type BlockUserParams struct {
+	BlockerID int32
+	BlockedID int32
+}
+
+func (q *Queries) BlockUser(ctx context.Context, arg BlockUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, blockUser, arg.BlockerID, arg.BlockedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const claimHandleRedirect = `-- name: ClaimHandleRedirect :exec
+DELETE FROM user_handle_redirects
+WHERE handle = $1
+  AND (user_id = $2 OR expires_at <= now())
+`
+
+type ClaimHandleRedirectParams struct {
+	Handle string
+	UserID int32
+}
+
+func (q *Queries) ClaimHandleRedirect(ctx context.Context, arg ClaimHandleRedirectParams) error {
+	_, err := q.db.ExecContext(ctx, claimHandleRedirect, arg.Handle, arg.UserID)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   fullname
@@ -30,30 +92,205 @@ func (q *Queries) CreateUser(ctx context.Context, fullname string) (CreateUserRo
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
+const deleteUser = `-- name: DeleteUser :execrows
 DELETE FROM users
 WHERE id = $1
+  AND version = COALESCE($2, version)
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
+type DeleteUserParams struct {
+	ID      int32
+	Version sql.NullInt32
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUser, arg.ID, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const followUser = `-- name: FollowUser :execrows
+INSERT INTO user_follows (follower_id, followee_id)
+SELECT f.id, e.id FROM users f, users e
+WHERE f.id = $1 AND e.id = $2
+ON CONFLICT (follower_id, followee_id) DO UPDATE SET created_at = user_follows.created_at
+`
+
+type FollowUserParams struct {
+	FollowerID int32
+	FolloweeID int32
+}
+
+func (q *Queries) FollowUser(ctx context.Context, arg FollowUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, followUser, arg.FollowerID, arg.FolloweeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getBlockedUsers = `-- name: GetBlockedUsers :many
+SELECT u.id, u.fullname FROM user_blocks b
+JOIN users u ON u.id = b.blocked_id
+WHERE b.blocker_id = $1
+ORDER BY b.created_at DESC, u.id
+`
+
+type GetBlockedUsersRow struct {
+	ID       int32
+	Fullname string
+}
+
+func (q *Queries) GetBlockedUsers(ctx context.Context, blockerID int32) ([]GetBlockedUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBlockedUsers, blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var items []GetBlockedUsersRow
+	for rows.Next() {
+		var i GetBlockedUsersRow
+		if err := rows.Scan(&i.ID, &i.Fullname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHandleForUpdate = `-- name: GetHandleForUpdate :one
+SELECT handle, handle_changed_at FROM users
+WHERE id = $1
+FOR UPDATE
+`
+
+type GetHandleForUpdateRow struct {
+	Handle          sql.NullString
+	HandleChangedAt sql.NullTime
+}
+
+func (q *Queries) GetHandleForUpdate(ctx context.Context, id int32) (GetHandleForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getHandleForUpdate, id)
+	var i GetHandleForUpdateRow
+	err := row.Scan(&i.Handle, &i.HandleChangedAt)
+	return i, err
+}
+
+const getMutedUsers = `-- name: GetMutedUsers :many
+SELECT u.id, u.fullname FROM user_mutes m
+JOIN users u ON u.id = m.muted_id
+WHERE m.muter_id = $1
+ORDER BY m.created_at DESC, u.id
+`
+
+type GetMutedUsersRow struct {
+	ID       int32
+	Fullname string
+}
+
+func (q *Queries) GetMutedUsers(ctx context.Context, muterID int32) ([]GetMutedUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMutedUsers, muterID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var items []GetMutedUsersRow
+	for rows.Next() {
+		var i GetMutedUsersRow
+		if err := rows.Scan(&i.ID, &i.Fullname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProfile = `-- name: GetProfile :one
+SELECT u.id, u.fullname, u.handle, u.bio, u.location, u.website, u.avatar_media_id,
+  m.thumbnail_content_type AS avatar_thumbnail_content_type,
+  (SELECT count(*) FROM posts p
+    WHERE p.userid = u.id AND post_visible_to(p.id, $1, true))::int AS post_count,
+  (SELECT count(*) FROM user_follows f WHERE f.followee_id = u.id)::int AS follower_count,
+  (SELECT count(*) FROM user_follows f WHERE f.follower_id = u.id)::int AS following_count
+FROM users u
+LEFT JOIN media m ON m.id = u.avatar_media_id
+WHERE u.handle = $2::text
+  OR u.id = (
+    SELECT r.user_id FROM user_handle_redirects r
+    WHERE r.handle = $2::text AND r.expires_at > now()
+  )
+LIMIT 1
+`
+
+type GetProfileParams struct {
+	ViewerID int32
+	Handle   string
+}
+
+type GetProfileRow struct {
+	ID                         int32
+	Fullname                   string
+	Handle                     sql.NullString
+	Bio                        string
+	Location                   string
+	Website                    string
+	AvatarMediaID              sql.NullInt32
+	AvatarThumbnailContentType sql.NullString
+	PostCount                  int32
+	FollowerCount              int32
+	FollowingCount             int32
+}
+
+func (q *Queries) GetProfile(ctx context.Context, arg GetProfileParams) (GetProfileRow, error) {
+	row := q.db.QueryRowContext(ctx, getProfile, arg.ViewerID, arg.Handle)
+	var i GetProfileRow
+	err := row.Scan(
+		&i.ID,
+		&i.Fullname,
+		&i.Handle,
+		&i.Bio,
+		&i.Location,
+		&i.Website,
+		&i.AvatarMediaID,
+		&i.AvatarThumbnailContentType,
+		&i.PostCount,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, fullname FROM users
+SELECT id, fullname, version FROM users
 WHERE id = $1 LIMIT 1
 `
 
 type GetUserRow struct {
 	ID       int32
 	Fullname string
+	Version  int32
 }
 
 func (q *Queries) GetUser(ctx context.Context, id int32) (GetUserRow, error) {
 	row := q.db.QueryRowContext(ctx, getUser, id)
 	var i GetUserRow
-	err := row.Scan(&i.ID, &i.Fullname)
+	err := row.Scan(&i.ID, &i.Fullname, &i.Version)
 	return i, err
 }
 
@@ -72,7 +309,7 @@ func (q *Queries) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	defer rows.Close()
 	var items []GetUsersRow
 	for rows.Next() {
@@ -91,26 +328,280 @@ func (q *Queries) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
 	return items, nil
 }
 
+const isAdmin = `-- name: IsAdmin :one
+SELECT is_admin FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) IsAdmin(ctx context.Context, id int32) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isAdmin, id)
+	var is_admin bool
+	err := row.Scan(&is_admin)
+	return is_admin, err
+}
+
+const isHandleReserved = `-- name: IsHandleReserved :one
+SELECT EXISTS (
+  SELECT 1 FROM user_handle_redirects WHERE handle = $1
+)
+`
+
+func (q *Queries) IsHandleReserved(ctx context.Context, handle string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isHandleReserved, handle)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isOwnImage = `-- name: IsOwnImage :one
+SELECT EXISTS (
+  SELECT 1 FROM media
+  WHERE id = $1 AND user_id = $2 AND content_type LIKE 'image/%'
+)
+`
+
+type IsOwnImageParams struct {
+	MediaID int32
+	UserID  int32
+}
+
+func (q *Queries) IsOwnImage(ctx context.Context, arg IsOwnImageParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isOwnImage, arg.MediaID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const lockHandle = `-- name: LockHandle :exec
+SELECT pg_advisory_xact_lock(hashtextextended('handle:' || $1::text, 0))
+`
+
+func (q *Queries) LockHandle(ctx context.Context, handle string) error {
+	_, err := q.db.ExecContext(ctx, lockHandle, handle)
+	return err
+}
+
+const muteUser = `-- name: MuteUser :execrows
+INSERT INTO user_mutes (muter_id, muted_id)
+SELECT m.id, d.id FROM users m, users d
+WHERE m.id = $1 AND d.id = $2
+ON CONFLICT (muter_id, muted_id) DO UPDATE SET created_at = user_mutes.created_at
+`
+
+type MuteUserParams struct {
+	MuterID int32
+	MutedID int32
+}
+
+func (q *Queries) MuteUser(ctx context.Context, arg MuteUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, muteUser, arg.MuterID, arg.MutedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const patchUser = `-- name: PatchUser :one
+UPDATE users
+  set fullname = COALESCE($1, fullname),
+  version = version + 1
+WHERE id = $2
+  AND version = COALESCE($3, version)
+RETURNING id, fullname, version
+`
+
+type PatchUserParams struct {
+	Fullname sql.NullString
+	ID       int32
+	Version  sql.NullInt32
+}
+
+type PatchUserRow struct {
+	ID       int32
+	Fullname string
+	Version  int32
+}
+
+func (q *Queries) PatchUser(ctx context.Context, arg PatchUserParams) (PatchUserRow, error) {
+	row := q.db.QueryRowContext(ctx, patchUser, arg.Fullname, arg.ID, arg.Version)
+	var i PatchUserRow
+	err := row.Scan(&i.ID, &i.Fullname, &i.Version)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, fullname FROM users
+WHERE fullname ILIKE $1 OR fullname % $2
+ORDER BY fullname ILIKE $1 DESC,
+  similarity(fullname, $2) DESC,
+  id
+LIMIT $3
+`
+
+type SearchUsersParams struct {
+	Prefix string
+	Query  string
+	Limit  int32
+}
+
+type SearchUsersRow struct {
+	ID       int32
+	Fullname string
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers, arg.Prefix, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(&i.ID, &i.Fullname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unblockUser = `-- name: UnblockUser :exec
+DELETE FROM user_blocks
+WHERE blocker_id = $1 AND blocked_id = $2
+`
+
+type UnblockUserParams struct {
+	BlockerID int32
+	BlockedID int32
+}
+
+func (q *Queries) UnblockUser(ctx context.Context, arg UnblockUserParams) error {
+	_, err := q.db.ExecContext(ctx, unblockUser, arg.BlockerID, arg.BlockedID)
+	return err
+}
+
+const unfollowUser = `-- name: UnfollowUser :exec
+DELETE FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type UnfollowUserParams struct {
+	FollowerID int32
+	FolloweeID int32
+}
+
+func (q *Queries) UnfollowUser(ctx context.Context, arg UnfollowUserParams) error {
+	_, err := q.db.ExecContext(ctx, unfollowUser, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const unmuteUser = `-- name: UnmuteUser :exec
+DELETE FROM user_mutes
+WHERE muter_id = $1 AND muted_id = $2
+`
+
+type UnmuteUserParams struct {
+	MuterID int32
+	MutedID int32
+}
+
+func (q *Queries) UnmuteUser(ctx context.Context, arg UnmuteUserParams) error {
+	_, err := q.db.ExecContext(ctx, unmuteUser, arg.MuterID, arg.MutedID)
+	return err
+}
+
+const updateProfile = `-- name: UpdateProfile :one
+UPDATE users
+  set handle = $1::text,
+  handle_changed_at = CASE
+    WHEN handle IS DISTINCT FROM $1::text THEN $2::timestamp
+    ELSE handle_changed_at
+  END,
+  bio = $3,
+  location = $4,
+  website = $5,
+  avatar_media_id = $6,
+  version = version + 1
+WHERE id = $7
+RETURNING id, fullname, handle, bio, location, website, avatar_media_id, version
+`
+
+type UpdateProfileParams struct {
+	Handle        string
+	ChangedAt     time.Time
+	Bio           string
+	Location      string
+	Website       string
+	AvatarMediaID sql.NullInt32
+	ID            int32
+}
+
+type UpdateProfileRow struct {
+	ID            int32
+	Fullname      string
+	Handle        sql.NullString
+	Bio           string
+	Location      string
+	Website       string
+	AvatarMediaID sql.NullInt32
+	Version       int32
+}
+
+func (q *Queries) UpdateProfile(ctx context.Context, arg UpdateProfileParams) (UpdateProfileRow, error) {
+	row := q.db.QueryRowContext(ctx, updateProfile,
+		arg.Handle,
+		arg.ChangedAt,
+		arg.Bio,
+		arg.Location,
+		arg.Website,
+		arg.AvatarMediaID,
+		arg.ID,
+	)
+	var i UpdateProfileRow
+	err := row.Scan(
+		&i.ID,
+		&i.Fullname,
+		&i.Handle,
+		&i.Bio,
+		&i.Location,
+		&i.Website,
+		&i.AvatarMediaID,
+		&i.Version,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-  set fullname = $2
-WHERE id = $1
-RETURNING id, fullname
+  set fullname = $1,
+  version = version + 1
+WHERE id = $2
+  AND version = COALESCE($3, version)
+RETURNING id, fullname, version
 `
 
 type UpdateUserParams struct {
-	ID       int32
 	Fullname string
+	ID       int32
+	Version  sql.NullInt32
 }
 
 type UpdateUserRow struct {
 	ID       int32
 	Fullname string
+	Version  int32
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
-	row := q.db.QueryRowContext(ctx, updateUser, arg.ID, arg.Fullname)
+	row := q.db.QueryRowContext(ctx, updateUser, arg.Fullname, arg.ID, arg.Version)
 	var i UpdateUserRow
-	err := row.Scan(&i.ID, &i.Fullname)
+	err := row.Scan(&i.ID, &i.Fullname, &i.Version)
 	return i, err
 }
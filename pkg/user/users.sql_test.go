@@ -7,6 +7,7 @@ import (
 	reflect "reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	gomock "github.com/golang/mock/gomock"
@@ -162,60 +163,91 @@ func Test_CreateUser(t *testing.T) {
 func Test_DeleteUser(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		id  int32
+		arg DeleteUserParams
 	}
 
-	q := `-- name: DeleteUser :exec
+	q := `-- name: DeleteUser :execrows
 		DELETE FROM users
 		WHERE id = $1
+		AND version = COALESCE($2, version)
 	`
 	tests := []struct {
 		name     string
 		initMock func() *Queries
 		args     args
+		want     int64
 		wantErr  bool
 	}{
 		{
 			name: "success delete user",
 			args: args{
 				ctx: context.Background(),
-				id:  1,
+				arg: DeleteUserParams{
+					ID: 1,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "stale version deletes nothing",
+			args: args{
+				ctx: context.Background(),
+				arg: DeleteUserParams{
+					ID:      1,
+					Version: sql.NullInt32{Int32: 2, Valid: true},
+				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
 
 				return &Queries{
 					db: dbMock,
 				}
 			},
+			want:    0,
 			wantErr: false,
 		},
 		{
 			name: "error delete user",
 			args: args{
 				ctx: context.Background(),
-				id:  1,
+				arg: DeleteUserParams{
+					ID: 1,
+				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
 				}
 			},
+			want:    0,
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.initMock()
-			err := p.DeleteUser(tt.args.ctx, tt.args.id)
+			got, err := p.DeleteUser(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteUser() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if got != tt.want {
+				t.Errorf("DeleteUser() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -227,7 +259,7 @@ func Test_GetUser(t *testing.T) {
 	}
 
 	q := `-- name: GetUser :one
-		SELECT id, fullname FROM users
+		SELECT id, fullname, version FROM users
 		WHERE id = $1 LIMIT 1
 	`
 	tests := []struct {
@@ -245,7 +277,7 @@ func Test_GetUser(t *testing.T) {
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow(1, "Giri Putra Adhittana")
+				rows := sqlmock.NewRows([]string{"id", "fullname", "version"}).AddRow(1, "Giri Putra Adhittana", 3)
 				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
 
 				return &Queries{
@@ -255,6 +287,7 @@ func Test_GetUser(t *testing.T) {
 			want: GetUserRow{
 				ID:       1,
 				Fullname: "Giri Putra Adhittana",
+				Version:  3,
 			},
 			wantErr: false,
 		},
@@ -382,6 +415,102 @@ func Test_GetUsers(t *testing.T) {
 	}
 }
 
+func Test_SearchUsers(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg SearchUsersParams
+	}
+
+	q := `-- name: SearchUsers :many
+	SELECT id, fullname FROM users
+	WHERE fullname ILIKE $1 OR fullname % $2
+	ORDER BY fullname ILIKE $1 DESC,
+	  similarity(fullname, $2) DESC,
+	  id
+	LIMIT $3
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []SearchUsersRow
+		wantErr  bool
+	}{
+		{
+			name: "success search users",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchUsersParams{Prefix: "jo%", Query: "jo", Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow(1, "John Doe")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("jo%", "jo", 10).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []SearchUsersRow{
+				{
+					ID:       1,
+					Fullname: "John Doe",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error scan search users",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchUsersParams{Prefix: "jo%", Query: "jo", Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow("error", "John Doe")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error search users",
+			args: args{
+				ctx: context.Background(),
+				arg: SearchUsersParams{Prefix: "jo%", Query: "jo", Limit: 10},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.SearchUsers(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_UpdateUser(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -390,9 +519,11 @@ func Test_UpdateUser(t *testing.T) {
 
 	q := `-- name: UpdateUser :one
 		UPDATE users
-		set fullname = $2
-		WHERE id = $1
-		RETURNING id, fullname
+		set fullname = $1,
+		version = version + 1
+		WHERE id = $2
+		AND version = COALESCE($3, version)
+		RETURNING id, fullname, version
 	`
 	tests := []struct {
 		name     string
@@ -408,12 +539,13 @@ func Test_UpdateUser(t *testing.T) {
 				arg: UpdateUserParams{
 					ID:       1,
 					Fullname: "Giri Putra Adhittana",
+					Version:  sql.NullInt32{Int32: 1, Valid: true},
 				},
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow(1, "Giri Putra Adhittana")
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Giri Putra Adhittana").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "fullname", "version"}).AddRow(1, "Giri Putra Adhittana", 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("Giri Putra Adhittana", 1, 1).WillReturnRows(rows)
 
 				return &Queries{
 					db: dbMock,
@@ -422,6 +554,7 @@ func Test_UpdateUser(t *testing.T) {
 			want: UpdateUserRow{
 				ID:       1,
 				Fullname: "Giri Putra Adhittana",
+				Version:  2,
 			},
 			wantErr: false,
 		},
@@ -436,7 +569,7 @@ func Test_UpdateUser(t *testing.T) {
 			},
 			initMock: func() *Queries {
 				dbMock, mock, _ := sqlmock.New()
-				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1, "Giri Putra Adhittana").WillReturnError(errors.New("error"))
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("Giri Putra Adhittana", 1, nil).WillReturnError(errors.New("error"))
 
 				return &Queries{
 					db: dbMock,
@@ -460,3 +593,1250 @@ func Test_UpdateUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg PatchUserParams
+	}
+
+	q := `-- name: PatchUser :one
+		UPDATE users
+		set fullname = COALESCE($1, fullname),
+		version = version + 1
+		WHERE id = $2
+		AND version = COALESCE($3, version)
+		RETURNING id, fullname, version
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     PatchUserRow
+		wantErr  bool
+	}{
+		{
+			name: "success patch user",
+			args: args{
+				ctx: context.Background(),
+				arg: PatchUserParams{
+					ID:       1,
+					Fullname: sql.NullString{String: "Giri Putra Adhittana", Valid: true},
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname", "version"}).AddRow(1, "Giri Putra Adhittana", 2)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("Giri Putra Adhittana", 1, nil).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: PatchUserRow{
+				ID:       1,
+				Fullname: "Giri Putra Adhittana",
+				Version:  2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error patch user",
+			args: args{
+				ctx: context.Background(),
+				arg: PatchUserParams{
+					ID: 1,
+				},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(nil, 1, nil).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    PatchUserRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.PatchUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FollowUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg FollowUserParams
+	}
+
+	q := `-- name: FollowUser :execrows
+	INSERT INTO user_follows (follower_id, followee_id)
+	SELECT f.id, e.id FROM users f, users e
+	WHERE f.id = $1 AND e.id = $2
+	ON CONFLICT (follower_id, followee_id) DO UPDATE SET created_at = user_follows.created_at
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success follow user",
+			args: args{
+				ctx: context.Background(),
+				arg: FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "missing follower or followee follows nobody",
+			args: args{
+				ctx: context.Background(),
+				arg: FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error follow user",
+			args: args{
+				ctx: context.Background(),
+				arg: FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.FollowUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FollowUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FollowUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UnfollowUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg UnfollowUserParams
+	}
+
+	q := `-- name: UnfollowUser :exec
+	DELETE FROM user_follows
+	WHERE follower_id = $1 AND followee_id = $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success unfollow user",
+			args: args{
+				ctx: context.Background(),
+				arg: UnfollowUserParams{FollowerID: 1, FolloweeID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unfollow user",
+			args: args{
+				ctx: context.Background(),
+				arg: UnfollowUserParams{FollowerID: 1, FolloweeID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.UnfollowUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnfollowUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_BlockUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg BlockUserParams
+	}
+
+	q := `-- name: BlockUser :execrows
+	WITH unfollowed AS (
+	  DELETE FROM user_follows
+	  WHERE (follower_id = $1 AND followee_id = $2)
+	    OR (follower_id = $2 AND followee_id = $1)
+	)
+	INSERT INTO user_blocks (blocker_id, blocked_id)
+	SELECT b.id, d.id FROM users b, users d
+	WHERE b.id = $1 AND d.id = $2
+	ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET created_at = user_blocks.created_at
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success block user",
+			args: args{
+				ctx: context.Background(),
+				arg: BlockUserParams{BlockerID: 1, BlockedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "missing blocker or blocked user blocks nobody",
+			args: args{
+				ctx: context.Background(),
+				arg: BlockUserParams{BlockerID: 1, BlockedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error block user",
+			args: args{
+				ctx: context.Background(),
+				arg: BlockUserParams{BlockerID: 1, BlockedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.BlockUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BlockUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("BlockUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UnblockUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg UnblockUserParams
+	}
+
+	q := `-- name: UnblockUser :exec
+	DELETE FROM user_blocks
+	WHERE blocker_id = $1 AND blocked_id = $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success unblock user",
+			args: args{
+				ctx: context.Background(),
+				arg: UnblockUserParams{BlockerID: 1, BlockedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unblock user",
+			args: args{
+				ctx: context.Background(),
+				arg: UnblockUserParams{BlockerID: 1, BlockedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.UnblockUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnblockUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_MuteUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg MuteUserParams
+	}
+
+	q := `-- name: MuteUser :execrows
+	INSERT INTO user_mutes (muter_id, muted_id)
+	SELECT m.id, d.id FROM users m, users d
+	WHERE m.id = $1 AND d.id = $2
+	ON CONFLICT (muter_id, muted_id) DO UPDATE SET created_at = user_mutes.created_at
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name: "success mute user",
+			args: args{
+				ctx: context.Background(),
+				arg: MuteUserParams{MuterID: 1, MutedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "missing muter or muted user mutes nobody",
+			args: args{
+				ctx: context.Background(),
+				arg: MuteUserParams{MuterID: 1, MutedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error mute user",
+			args: args{
+				ctx: context.Background(),
+				arg: MuteUserParams{MuterID: 1, MutedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.MuteUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MuteUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MuteUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UnmuteUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		arg UnmuteUserParams
+	}
+
+	q := `-- name: UnmuteUser :exec
+	DELETE FROM user_mutes
+	WHERE muter_id = $1 AND muted_id = $2
+	`
+
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "success unmute user",
+			args: args{
+				ctx: context.Background(),
+				arg: UnmuteUserParams{MuterID: 1, MutedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unmute user",
+			args: args{
+				ctx: context.Background(),
+				arg: UnmuteUserParams{MuterID: 1, MutedID: 2},
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.UnmuteUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmuteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetBlockedUsers(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		blockerID int32
+	}
+
+	q := `-- name: GetBlockedUsers :many
+	SELECT u.id, u.fullname FROM user_blocks b
+	JOIN users u ON u.id = b.blocked_id
+	WHERE b.blocker_id = $1
+	ORDER BY b.created_at DESC, u.id
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetBlockedUsersRow
+		wantErr  bool
+	}{
+		{
+			name: "success get blocked users",
+			args: args{
+				ctx:       context.Background(),
+				blockerID: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow(1, "Giri Putra Adhittana").AddRow(2, "Giri Adhittana")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetBlockedUsersRow{
+				{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+				},
+				{
+					ID:       2,
+					Fullname: "Giri Adhittana",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get blocked users",
+			args: args{
+				ctx:       context.Background(),
+				blockerID: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error scan get blocked users",
+			args: args{
+				ctx:       context.Background(),
+				blockerID: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow("Giri Putra Adhittana", 1)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetBlockedUsers(tt.args.ctx, tt.args.blockerID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBlockedUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBlockedUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetMutedUsers(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		muterID int32
+	}
+
+	q := `-- name: GetMutedUsers :many
+	SELECT u.id, u.fullname FROM user_mutes m
+	JOIN users u ON u.id = m.muted_id
+	WHERE m.muter_id = $1
+	ORDER BY m.created_at DESC, u.id
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		args     args
+		want     []GetMutedUsersRow
+		wantErr  bool
+	}{
+		{
+			name: "success get muted users",
+			args: args{
+				ctx:     context.Background(),
+				muterID: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow(1, "Giri Putra Adhittana").AddRow(2, "Giri Adhittana")
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: []GetMutedUsersRow{
+				{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+				},
+				{
+					ID:       2,
+					Fullname: "Giri Adhittana",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get muted users",
+			args: args{
+				ctx:     context.Background(),
+				muterID: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error scan get muted users",
+			args: args{
+				ctx:     context.Background(),
+				muterID: 1,
+			},
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				rows := sqlmock.NewRows([]string{"id", "fullname"}).AddRow("Giri Putra Adhittana", 1)
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(rows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetMutedUsers(tt.args.ctx, tt.args.muterID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMutedUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMutedUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IsAdmin(t *testing.T) {
+	q := `-- name: IsAdmin :one
+	SELECT is_admin FROM users
+	WHERE id = $1 LIMIT 1
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name: "success admin",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"is_admin"}).AddRow(true))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "error missing user",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.IsAdmin(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsAdmin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetProfile(t *testing.T) {
+	q := `-- name: GetProfile :one
+	SELECT u.id, u.fullname, u.handle, u.bio, u.location, u.website, u.avatar_media_id,
+	  m.thumbnail_content_type AS avatar_thumbnail_content_type,
+	  (SELECT count(*) FROM posts p
+	    WHERE p.userid = u.id AND post_visible_to(p.id, $1, true))::int AS post_count,
+	  (SELECT count(*) FROM user_follows f WHERE f.followee_id = u.id)::int AS follower_count,
+	  (SELECT count(*) FROM user_follows f WHERE f.follower_id = u.id)::int AS following_count
+	FROM users u
+	LEFT JOIN media m ON m.id = u.avatar_media_id
+	WHERE u.handle = $2::text
+	  OR u.id = (
+	    SELECT r.user_id FROM user_handle_redirects r
+	    WHERE r.handle = $2::text AND r.expires_at > now()
+	  )
+	LIMIT 1
+	`
+	cols := []string{"id", "fullname", "handle", "bio", "location", "website", "avatar_media_id", "avatar_thumbnail_content_type", "post_count", "follower_count", "following_count"}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     GetProfileRow
+		wantErr  bool
+	}{
+		{
+			name: "success get profile",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(2, "ana").WillReturnRows(sqlmock.NewRows(cols).
+					AddRow(1, "Ana", "ana", "hello", "Jakarta", "https://ana.dev", 4, "image/jpeg", 3, 5, 6))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: GetProfileRow{
+				ID:                         1,
+				Fullname:                   "Ana",
+				Handle:                     sql.NullString{String: "ana", Valid: true},
+				Bio:                        "hello",
+				Location:                   "Jakarta",
+				Website:                    "https://ana.dev",
+				AvatarMediaID:              sql.NullInt32{Int32: 4, Valid: true},
+				AvatarThumbnailContentType: sql.NullString{String: "image/jpeg", Valid: true},
+				PostCount:                  3,
+				FollowerCount:              5,
+				FollowingCount:             6,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error missing profile",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetProfileRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetProfile(context.Background(), GetProfileParams{ViewerID: 2, Handle: "ana"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetHandleForUpdate(t *testing.T) {
+	q := `-- name: GetHandleForUpdate :one
+	SELECT handle, handle_changed_at FROM users
+	WHERE id = $1
+	FOR UPDATE
+	`
+	changedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     GetHandleForUpdateRow
+		wantErr  bool
+	}{
+		{
+			name: "success get handle",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"handle", "handle_changed_at"}).AddRow("ana", changedAt))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: GetHandleForUpdateRow{
+				Handle:          sql.NullString{String: "ana", Valid: true},
+				HandleChangedAt: sql.NullTime{Time: changedAt, Valid: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error missing user",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    GetHandleForUpdateRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.GetHandleForUpdate(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetHandleForUpdate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetHandleForUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LockHandle(t *testing.T) {
+	q := `-- name: LockHandle :exec
+	SELECT pg_advisory_xact_lock(hashtextextended('handle:' || $1::text, 0))
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success lock handle",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("ana").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error lock handle",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.LockHandle(context.Background(), "ana")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LockHandle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ClaimHandleRedirect(t *testing.T) {
+	q := `-- name: ClaimHandleRedirect :exec
+	DELETE FROM user_handle_redirects
+	WHERE handle = $1
+	  AND (user_id = $2 OR expires_at <= now())
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success claim handle redirect",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("ana", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error claim handle redirect",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.ClaimHandleRedirect(context.Background(), ClaimHandleRedirectParams{Handle: "ana", UserID: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClaimHandleRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_IsHandleReserved(t *testing.T) {
+	q := `-- name: IsHandleReserved :one
+	SELECT EXISTS (
+	  SELECT 1 FROM user_handle_redirects WHERE handle = $1
+	)
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name: "success reserved",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("ana").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "error is handle reserved",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.IsHandleReserved(context.Background(), "ana")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsHandleReserved() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsHandleReserved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddHandleRedirect(t *testing.T) {
+	q := `-- name: AddHandleRedirect :exec
+	INSERT INTO user_handle_redirects (handle, user_id, expires_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (handle) DO UPDATE SET
+	  user_id = EXCLUDED.user_id,
+	  expires_at = EXCLUDED.expires_at
+	`
+	expiresAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		wantErr  bool
+	}{
+		{
+			name: "success add handle redirect",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs("ana", 1, expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error add handle redirect",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			err := p.AddHandleRedirect(context.Background(), AddHandleRedirectParams{Handle: "ana", UserID: 1, ExpiresAt: expiresAt})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddHandleRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_IsOwnImage(t *testing.T) {
+	q := `-- name: IsOwnImage :one
+	SELECT EXISTS (
+	  SELECT 1 FROM media
+	  WHERE id = $1 AND user_id = $2 AND content_type LIKE 'image/%'
+	)
+	`
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name: "success own image",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(4, 1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "error is own image",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.IsOwnImage(context.Background(), IsOwnImageParams{MediaID: 4, UserID: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsOwnImage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsOwnImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UpdateProfile(t *testing.T) {
+	q := `-- name: UpdateProfile :one
+	UPDATE users
+	  set handle = $1::text,
+	  handle_changed_at = CASE
+	    WHEN handle IS DISTINCT FROM $1::text THEN $2::timestamp
+	    ELSE handle_changed_at
+	  END,
+	  bio = $3,
+	  location = $4,
+	  website = $5,
+	  avatar_media_id = $6,
+	  version = version + 1
+	WHERE id = $7
+	RETURNING id, fullname, handle, bio, location, website, avatar_media_id, version
+	`
+	changedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	arg := UpdateProfileParams{
+		Handle:        "ana",
+		ChangedAt:     changedAt,
+		Bio:           "hello",
+		Location:      "Jakarta",
+		Website:       "https://ana.dev",
+		AvatarMediaID: sql.NullInt32{Int32: 4, Valid: true},
+		ID:            1,
+	}
+	tests := []struct {
+		name     string
+		initMock func() *Queries
+		want     UpdateProfileRow
+		wantErr  bool
+	}{
+		{
+			name: "success update profile",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs("ana", changedAt, "hello", "Jakarta", "https://ana.dev", 4, 1).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "fullname", "handle", "bio", "location", "website", "avatar_media_id", "version"}).
+						AddRow(1, "Ana", "ana", "hello", "Jakarta", "https://ana.dev", 4, 2))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want: UpdateProfileRow{
+				ID:            1,
+				Fullname:      "Ana",
+				Handle:        sql.NullString{String: "ana", Valid: true},
+				Bio:           "hello",
+				Location:      "Jakarta",
+				Website:       "https://ana.dev",
+				AvatarMediaID: sql.NullInt32{Int32: 4, Valid: true},
+				Version:       2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error update profile",
+			initMock: func() *Queries {
+				dbMock, mock, _ := sqlmock.New()
+				mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnError(errors.New("error"))
+
+				return &Queries{
+					db: dbMock,
+				}
+			},
+			want:    UpdateProfileRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.initMock()
+			got, err := p.UpdateProfile(context.Background(), arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpdateProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+-- name: CreateCollection :one
+INSERT INTO collections (
+  user_id, name
+) VALUES (
+  $1,$2
+)
+RETURNING id, user_id, name;
+
+-- name: RenameCollection :one
+UPDATE collections
+  set name = sqlc.arg('name')
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+RETURNING id, user_id, name;
+
+-- name: DeleteCollection :execrows
+DELETE FROM collections
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetCollections :many
+SELECT c.id, c.user_id, c.name, count(b.id) AS post_count
+FROM collections c LEFT JOIN bookmarks b
+ON b.collection_id = c.id
+WHERE c.user_id = $1
+GROUP BY c.id
+ORDER BY c.name, c.id;
+
+-- name: AddBookmark :execrows
+INSERT INTO bookmarks (user_id, post_id, collection_id)
+SELECT u.id, p.id, sqlc.narg('collection_id')::int
+FROM users u, posts p
+WHERE u.id = sqlc.arg('user_id') AND p.id = sqlc.arg('post_id')
+  AND (sqlc.narg('collection_id')::int IS NULL OR EXISTS (
+    SELECT 1 FROM collections c
+    WHERE c.id = sqlc.narg('collection_id') AND c.user_id = u.id
+  ))
+ON CONFLICT (user_id, post_id, (COALESCE(collection_id, 0)))
+DO UPDATE SET created_at = bookmarks.created_at;
+
+-- name: RemoveBookmark :exec
+DELETE FROM bookmarks
+WHERE user_id = sqlc.arg('user_id') AND post_id = sqlc.arg('post_id')
+  AND (sqlc.narg('collection_id')::int IS NULL OR collection_id = sqlc.narg('collection_id'));
+
+-- name: GetBookmarkedPostIDs :many
+SELECT DISTINCT post_id FROM bookmarks
+WHERE user_id = sqlc.arg('user_id') AND post_id = ANY(sqlc.arg('post_ids')::int[]);
@@ -0,0 +1,30 @@
+-- name: LockIdempotencyKey :exec
+SELECT pg_advisory_lock(hashtextextended(sqlc.arg('lock_key')::text, 0));
+
+-- name: UnlockIdempotencyKey :exec
+SELECT pg_advisory_unlock(hashtextextended(sqlc.arg('lock_key')::text, 0));
+
+-- name: GetIdempotencyKey :one
+SELECT key, scope, request_hash, status_code, content_type, response_body FROM idempotency_keys
+WHERE key = $1 AND scope = $2 AND expires_at > now()
+LIMIT 1;
+
+-- name: SaveIdempotencyKey :exec
+INSERT INTO idempotency_keys (
+  key, scope, request_hash, status_code, content_type, response_body, expires_at
+) VALUES (
+  sqlc.arg('key'), sqlc.arg('scope'), sqlc.arg('request_hash'), sqlc.arg('status_code'),
+  sqlc.arg('content_type'), sqlc.arg('response_body'),
+  now() + make_interval(hours => sqlc.arg('ttl_hours')::int)
+)
+ON CONFLICT (key, scope) DO UPDATE SET
+  request_hash = EXCLUDED.request_hash,
+  status_code = EXCLUDED.status_code,
+  content_type = EXCLUDED.content_type,
+  response_body = EXCLUDED.response_body,
+  created_at = now(),
+  expires_at = EXCLUDED.expires_at;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= now();
@@ -0,0 +1,30 @@
+-- name: CreateMedia :one
+INSERT INTO media (
+  user_id, sha256, content_type, size, width, height, thumbnail_content_type
+) VALUES (
+  $1, $2, $3, $4, sqlc.narg('width'), sqlc.narg('height'), sqlc.narg('thumbnail_content_type')
+)
+ON CONFLICT (user_id, sha256) DO UPDATE SET sha256 = EXCLUDED.sha256
+RETURNING *;
+
+-- name: GetVisibleMedia :one
+SELECT m.* FROM media m
+WHERE m.id = sqlc.arg('id')
+  AND (m.user_id = sqlc.arg('viewer_id') OR EXISTS (
+    SELECT 1 FROM post_media pm
+    WHERE pm.media_id = m.id AND post_visible_to(pm.post_id, sqlc.arg('viewer_id'), false)
+  ) OR EXISTS (
+    SELECT 1 FROM users u WHERE u.avatar_media_id = m.id
+  ));
+
+-- name: AttachPostMedia :execrows
+INSERT INTO post_media (post_id, media_id, position)
+SELECT p.id, m.id, sqlc.arg('position')::int
+FROM posts p JOIN media m ON m.user_id = p.userid
+WHERE p.id = sqlc.arg('post_id') AND m.id = sqlc.arg('media_id');
+
+-- name: GetPostMedia :many
+SELECT m.id, m.content_type, m.size, m.width, m.height, m.thumbnail_content_type
+FROM post_media pm JOIN media m ON m.id = pm.media_id
+WHERE pm.post_id = $1
+ORDER BY pm.position;
@@ -0,0 +1,105 @@
+-- name: CreateConversation :one
+INSERT INTO conversations (created_by, direct_key)
+VALUES ($1, $2)
+ON CONFLICT (direct_key) DO UPDATE SET direct_key = EXCLUDED.direct_key
+RETURNING id, created_by, direct_key, created_at, last_message_at;
+
+-- name: AddConversationParticipants :exec
+INSERT INTO conversation_participants (conversation_id, user_id)
+SELECT sqlc.arg('conversation_id'), u.id
+FROM unnest(sqlc.arg('user_ids')::int[]) AS u(id)
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+-- name: GetConversations :many
+SELECT c.id, c.created_by, c.direct_key IS NOT NULL AS direct, c.created_at, c.last_message_at,
+  (
+    SELECT count(*) FROM messages m
+    WHERE m.conversation_id = c.id
+      AND m.id > p.last_read_message_id
+      AND m.sender_id <> sqlc.arg('user_id')
+      AND NOT users_blocked(m.sender_id, sqlc.arg('user_id'))
+  )::int AS unread_count
+FROM conversations c
+JOIN conversation_participants p ON p.conversation_id = c.id AND p.user_id = sqlc.arg('user_id')
+ORDER BY c.last_message_at DESC, c.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetConversation :one
+SELECT c.id, c.created_by, c.direct_key IS NOT NULL AS direct, c.created_at, c.last_message_at,
+  (
+    SELECT count(*) FROM messages m
+    WHERE m.conversation_id = c.id
+      AND m.id > p.last_read_message_id
+      AND m.sender_id <> sqlc.arg('user_id')
+      AND NOT users_blocked(m.sender_id, sqlc.arg('user_id'))
+  )::int AS unread_count
+FROM conversations c
+JOIN conversation_participants p ON p.conversation_id = c.id AND p.user_id = sqlc.arg('user_id')
+WHERE c.id = sqlc.arg('id');
+
+-- name: GetConversationParticipants :many
+SELECT p.conversation_id, u.id, u.fullname, u.handle, p.last_read_message_id, p.last_read_at
+FROM conversation_participants p
+JOIN users u ON u.id = p.user_id
+WHERE p.conversation_id = ANY(sqlc.arg('conversation_ids')::int[])
+ORDER BY p.conversation_id, p.joined_at, u.id;
+
+-- name: GetLastMessages :many
+SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at
+FROM unnest(sqlc.arg('conversation_ids')::int[]) AS c(id)
+CROSS JOIN LATERAL (
+  SELECT lm.id, lm.conversation_id, lm.sender_id, lm.body, lm.created_at
+  FROM messages lm
+  WHERE lm.conversation_id = c.id
+    AND NOT users_blocked(lm.sender_id, sqlc.arg('user_id')::int)
+  ORDER BY lm.id DESC
+  LIMIT 1
+) m
+ORDER BY m.conversation_id;
+
+-- name: GetMessage :one
+SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at
+FROM messages m
+JOIN conversation_participants p ON p.conversation_id = m.conversation_id AND p.user_id = sqlc.arg('user_id')
+WHERE m.id = sqlc.arg('id') AND m.conversation_id = sqlc.arg('conversation_id')
+  AND NOT users_blocked(m.sender_id, sqlc.arg('user_id')::int);
+
+-- name: GetMessages :many
+SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at
+FROM messages m
+WHERE m.conversation_id = sqlc.arg('conversation_id')
+  AND (sqlc.arg('before_id')::bigint = 0 OR m.id < sqlc.arg('before_id')::bigint)
+  AND NOT users_blocked(m.sender_id, sqlc.arg('user_id')::int)
+ORDER BY m.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: SendMessage :one
+WITH sent AS (
+  INSERT INTO messages (conversation_id, sender_id, body)
+  SELECT p.conversation_id, p.user_id, sqlc.arg('body')
+  FROM conversation_participants p
+  WHERE p.conversation_id = sqlc.arg('conversation_id') AND p.user_id = sqlc.arg('sender_id')
+  RETURNING id, conversation_id, sender_id, body, created_at
+), touched AS (
+  UPDATE conversations c
+    set last_message_at = sent.created_at
+  FROM sent
+  WHERE c.id = sent.conversation_id
+), marked AS (
+  UPDATE conversation_participants p
+    set last_read_message_id = sent.id, last_read_at = sent.created_at
+  FROM sent
+  WHERE p.conversation_id = sent.conversation_id AND p.user_id = sent.sender_id
+)
+SELECT id, conversation_id, sender_id, body, created_at FROM sent;
+
+-- name: MarkConversationRead :one
+UPDATE conversation_participants p
+  set last_read_message_id = greatest(p.last_read_message_id, (
+    SELECT coalesce(max(m.id), 0) FROM messages m
+    WHERE m.conversation_id = p.conversation_id
+      AND (sqlc.arg('message_id')::bigint = 0 OR m.id <= sqlc.arg('message_id')::bigint)
+  )),
+  last_read_at = now()
+WHERE p.conversation_id = sqlc.arg('conversation_id') AND p.user_id = sqlc.arg('user_id')
+RETURNING p.last_read_message_id, p.last_read_at;
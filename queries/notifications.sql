@@ -0,0 +1,89 @@
+-- name: ClaimNotificationEvents :many
+SELECT id, kind, recipient_id, actor_id, post_id, created_at
+FROM notification_events
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteNotificationEvents :exec
+DELETE FROM notification_events WHERE id = ANY(sqlc.arg('ids')::bigint[]);
+
+-- name: UpsertNotification :one
+INSERT INTO notifications (recipient_id, kind, post_id, created_at, updated_at)
+SELECT sqlc.arg('recipient_id')::int, sqlc.arg('kind')::notification_kind,
+  sqlc.narg('post_id')::int, sqlc.arg('created_at')::timestamp, sqlc.arg('created_at')::timestamp
+WHERE sqlc.arg('recipient_id')::int <> sqlc.arg('actor_id')::int
+  AND NOT users_blocked(sqlc.arg('recipient_id')::int, sqlc.arg('actor_id')::int)
+  AND NOT EXISTS (
+    SELECT 1 FROM user_mutes m
+    WHERE m.muter_id = sqlc.arg('recipient_id')::int AND m.muted_id = sqlc.arg('actor_id')::int
+  )
+  AND coalesce((
+    SELECT np.enabled FROM notification_preferences np
+    WHERE np.user_id = sqlc.arg('recipient_id')::int AND np.kind = sqlc.arg('kind')::notification_kind
+  ), true)
+  AND (sqlc.narg('post_id')::int IS NULL OR post_visible_to(sqlc.narg('post_id')::int, sqlc.arg('recipient_id')::int, false))
+ON CONFLICT (recipient_id, kind, coalesce(post_id, 0)) WHERE read_at IS NULL
+DO UPDATE SET updated_at = greatest(notifications.updated_at, EXCLUDED.updated_at)
+RETURNING id;
+
+-- name: AddNotificationActor :exec
+INSERT INTO notification_actors (notification_id, actor_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (notification_id, actor_id) DO UPDATE SET
+  created_at = greatest(notification_actors.created_at, EXCLUDED.created_at);
+
+-- name: GetNotifications :many
+SELECT n.id, n.kind, n.post_id, n.created_at, n.updated_at, n.read_at,
+  count(a.actor_id)::int AS actor_count
+FROM notifications n
+JOIN notification_actors a ON a.notification_id = n.id
+WHERE n.recipient_id = sqlc.arg('recipient_id')
+  AND (NOT sqlc.arg('unread_only')::boolean OR n.read_at IS NULL)
+  AND (n.post_id IS NULL OR post_visible_to(n.post_id, sqlc.arg('recipient_id'), false))
+  AND NOT users_blocked(a.actor_id, sqlc.arg('recipient_id'))
+  AND NOT EXISTS (
+    SELECT 1 FROM user_mutes m
+    WHERE m.muter_id = sqlc.arg('recipient_id') AND m.muted_id = a.actor_id
+  )
+GROUP BY n.id
+ORDER BY n.updated_at DESC, n.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetNotificationActors :many
+SELECT a.notification_id, u.id, u.fullname, u.handle
+FROM unnest(sqlc.arg('notification_ids')::int[]) AS n(id)
+CROSS JOIN LATERAL (
+  SELECT na.notification_id, na.actor_id, na.created_at
+  FROM notification_actors na
+  WHERE na.notification_id = n.id
+    AND NOT users_blocked(na.actor_id, sqlc.arg('recipient_id')::int)
+    AND NOT EXISTS (
+      SELECT 1 FROM user_mutes m
+      WHERE m.muter_id = sqlc.arg('recipient_id')::int AND m.muted_id = na.actor_id
+    )
+  ORDER BY na.created_at DESC, na.actor_id DESC
+  LIMIT sqlc.arg('per_notification')::int
+) a
+JOIN users u ON u.id = a.actor_id
+ORDER BY a.notification_id, a.created_at DESC, a.actor_id DESC;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+  set read_at = coalesce(read_at, now())
+WHERE id = $1 AND recipient_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+  set read_at = now()
+WHERE recipient_id = $1 AND read_at IS NULL;
+
+-- name: GetNotificationPreferences :many
+SELECT kind, enabled FROM notification_preferences
+WHERE user_id = $1
+ORDER BY kind;
+
+-- name: SetNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, kind, enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, kind) DO UPDATE SET enabled = EXCLUDED.enabled;
@@ -8,4 +8,12 @@ RETURNING id, postid, tagid;
 
 -- name: DeletePostTag :exec
 DELETE FROM post_tags
-WHERE postid = $1;
\ No newline at end of file
+WHERE postid = $1;
+-- name: DeletePostTagsByTagIDs :exec
+DELETE FROM post_tags
+WHERE postid = sqlc.arg('postid') AND tagid = ANY(sqlc.arg('tag_ids')::int[]);
+
+-- name: GetPostTagIDs :many
+SELECT tagid FROM post_tags
+WHERE postid = $1
+ORDER BY id;
@@ -1,26 +1,291 @@
 -- name: GetPosts :many
-SELECT id, userid, title, description FROM posts
-ORDER BY created_at DESC;
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('viewer_id'), false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at, p.created_at
+FROM posts p
+WHERE (sqlc.narg('user_id')::int IS NULL OR p.userid = sqlc.narg('user_id'))
+  AND (sqlc.narg('tag_ids')::int[] IS NULL OR CASE
+    WHEN sqlc.arg('match_all_tags')::bool THEN (
+      SELECT count(DISTINCT pt.tagid) FROM post_tags pt
+      WHERE pt.postid = p.id AND pt.tagid = ANY(sqlc.narg('tag_ids')::int[])
+    ) = cardinality(sqlc.narg('tag_ids')::int[])
+    ELSE EXISTS (
+      SELECT 1 FROM post_tags pt
+      WHERE pt.postid = p.id AND pt.tagid = ANY(sqlc.narg('tag_ids')::int[])
+    )
+  END)
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR p.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR p.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL OR CASE
+    WHEN sqlc.arg('sort')::text = 'oldest'
+      THEN (p.created_at, p.id) > (sqlc.narg('cursor_created_at'), sqlc.arg('cursor_id')::int)
+    ELSE (p.created_at, p.id) < (sqlc.narg('cursor_created_at'), sqlc.arg('cursor_id')::int)
+  END)
+  AND post_visible_to(p.id, sqlc.arg('viewer_id'), true)
+ORDER BY
+  CASE WHEN sqlc.arg('sort')::text = 'most_reacted'
+    THEN (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) END DESC,
+  CASE WHEN sqlc.arg('sort')::text = 'oldest' THEN p.created_at END ASC,
+  CASE WHEN sqlc.arg('sort')::text = 'oldest' THEN p.id END ASC,
+  p.created_at DESC,
+  p.id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: CreatePost :one
 INSERT INTO posts (
-  userid, title, description
+  userid, title, description, in_reply_to_id, root_id, visibility, status, publish_at
 ) VALUES (
-  $1,$2,$3
+  sqlc.arg('userid'), sqlc.arg('title'), sqlc.arg('description'), sqlc.narg('in_reply_to_id'),
+  (SELECT COALESCE(r.root_id, r.id) FROM posts r WHERE r.id = sqlc.narg('in_reply_to_id')),
+  sqlc.arg('visibility'), sqlc.arg('status'), sqlc.narg('publish_at')
 )
-RETURNING id, userid, title, description;
+RETURNING id, userid, title, description, in_reply_to_id, visibility, status, publish_at;
 
 -- name: UpdatePost :one
 UPDATE posts
-  set title = $2,
-  description = $3
-WHERE id = $1
-RETURNING title, description;
+  set title = sqlc.arg('title'),
+  description = sqlc.arg('description'),
+  version = version + 1
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version)
+RETURNING title, description, version;
 
--- name: DeletePost :exec
+-- name: DeletePost :execrows
+WITH target AS (
+  SELECT id FROM posts
+  WHERE id = sqlc.arg('id')
+    AND version = COALESCE(sqlc.narg('version'), version)
+  FOR UPDATE
+), removed_tags AS (
+  DELETE FROM post_tags WHERE postid IN (SELECT id FROM target)
+)
 DELETE FROM posts
-WHERE id = $1;
+WHERE id IN (SELECT id FROM target);
 
 -- name: GetPost :one
-SELECT id, userid, title, description FROM posts
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+SELECT id, userid, title, description, status, publish_at, edited_at, version FROM posts
+WHERE id = $1 LIMIT 1;
+
+-- name: GetVisiblePost :one
+SELECT id, userid, title, description, visibility, status, publish_at, edited_at, version FROM posts
+WHERE id = sqlc.arg('id') AND post_visible_to(id, sqlc.arg('viewer_id'), false);
+
+-- name: PatchPost :one
+UPDATE posts
+  set title = COALESCE(sqlc.narg('title'), title),
+  description = COALESCE(sqlc.narg('description'), description),
+  visibility = COALESCE(sqlc.narg('visibility'), visibility),
+  status = COALESCE(sqlc.narg('status'), status),
+  publish_at = COALESCE(sqlc.narg('publish_at'), publish_at),
+  created_at = CASE WHEN status <> 'published' AND sqlc.narg('status') = 'published'
+    THEN now() ELSE created_at END,
+  version = version + 1
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version)
+RETURNING id, userid, title, description, visibility, status, publish_at, version;
+
+-- name: SearchPosts :many
+SELECT
+  p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('viewer_id'), false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at,
+  ts_headline('english', p.description, websearch_to_tsquery('english', sqlc.arg('query')),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet,
+  (ts_rank(p.search_vector, websearch_to_tsquery('english', sqlc.arg('query')))
+    / (1 + EXTRACT(EPOCH FROM now() - p.created_at) / 2592000))::float8 AS rank
+FROM posts p
+WHERE p.search_vector @@ websearch_to_tsquery('english', sqlc.arg('query'))
+  AND (sqlc.narg('tag_id')::int IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags pt WHERE pt.postid = p.id AND pt.tagid = sqlc.narg('tag_id')
+  ))
+  AND (sqlc.narg('user_id')::int IS NULL OR p.userid = sqlc.narg('user_id'))
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR p.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR p.created_at < sqlc.narg('created_to'))
+  AND post_visible_to(p.id, sqlc.arg('viewer_id'), true)
+ORDER BY rank DESC, p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetTagFeed :many
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('user_id'), false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at
+FROM posts p
+WHERE EXISTS (
+  SELECT 1 FROM post_tags pt JOIN tag_follows tf
+  ON tf.tag_id = pt.tagid
+  WHERE pt.postid = p.id AND tf.user_id = sqlc.arg('user_id')
+)
+  AND post_visible_to(p.id, sqlc.arg('user_id'), true)
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetBookmarks :many
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('user_id'), false)) AS reply_count,
+  p.repost_of_id, p.quote_of_id, p.in_reply_to_id, p.visibility, p.edited_at
+FROM posts p JOIN (
+  SELECT b.post_id, max(b.created_at) AS saved_at FROM bookmarks b
+  WHERE b.user_id = sqlc.arg('user_id')
+    AND (sqlc.narg('collection_id')::int IS NULL OR b.collection_id = sqlc.narg('collection_id'))
+  GROUP BY b.post_id
+) s ON s.post_id = p.id
+WHERE post_visible_to(p.id, sqlc.arg('user_id'), false)
+ORDER BY s.saved_at DESC, p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetPostsByIDs :many
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts rp WHERE rp.repost_of_id = p.id) AS repost_count,
+  (SELECT count(*) FROM posts qp WHERE qp.quote_of_id = p.id) AS quote_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('viewer_id'), false)) AS reply_count,
+  p.edited_at
+FROM posts p
+WHERE p.id = ANY(sqlc.arg('ids')::int[])
+  AND post_visible_to(p.id, sqlc.arg('viewer_id'), false);
+
+-- name: GetRepostTarget :one
+SELECT o.id, o.userid FROM posts p JOIN posts o
+ON o.id = COALESCE(p.repost_of_id, p.id)
+WHERE p.id = $1
+  AND post_visible_to(o.id, 0, false);
+
+-- name: CreateRepost :one
+INSERT INTO posts (
+  userid, title, description, repost_of_id
+) VALUES (
+  sqlc.arg('userid'), '', '', sqlc.arg('repost_of_id')::int
+)
+ON CONFLICT (userid, repost_of_id) DO UPDATE SET repost_of_id = EXCLUDED.repost_of_id
+RETURNING id, userid;
+
+-- name: CreateQuote :one
+INSERT INTO posts (
+  userid, title, description, quote_of_id
+) VALUES (
+  sqlc.arg('userid'), '', sqlc.arg('description'), sqlc.arg('quote_of_id')::int
+)
+RETURNING id, userid, description;
+
+-- name: DeleteRepost :exec
+DELETE FROM posts
+WHERE userid = sqlc.arg('userid')
+  AND repost_of_id = (
+    SELECT COALESCE(o.repost_of_id, o.id) FROM posts o WHERE o.id = sqlc.arg('post_id')
+  );
+
+-- name: GetReplyChain :many
+WITH RECURSIVE chain AS (
+  SELECT p.id, 0 AS distance FROM posts p
+  WHERE p.id = sqlc.arg('id') AND p.deleted_at IS NULL
+    AND post_visible_to(p.id, sqlc.arg('viewer_id'), false)
+  UNION ALL
+  SELECT p.in_reply_to_id, c.distance + 1
+  FROM chain c JOIN posts p ON p.id = c.id
+  WHERE p.in_reply_to_id IS NOT NULL
+)
+SELECT p.id, p.userid, p.title, p.description,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('viewer_id'), false)) AS reply_count,
+  p.edited_at
+FROM chain c JOIN posts p ON p.id = c.id
+WHERE post_visible_to(p.id, sqlc.arg('viewer_id'), false)
+ORDER BY c.distance DESC;
+
+-- name: GetReplyTree :many
+WITH RECURSIVE tree AS (
+  SELECT top.id, 1 AS depth FROM (
+    SELECT c.id FROM posts c
+    WHERE c.in_reply_to_id = sqlc.arg('id')::int AND c.deleted_at IS NULL
+      AND post_visible_to(c.id, sqlc.arg('viewer_id'), false)
+    ORDER BY c.created_at, c.id
+    LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset')
+  ) top
+  UNION ALL
+  SELECT c.id, t.depth + 1
+  FROM tree t CROSS JOIN LATERAL (
+    SELECT c.id FROM posts c
+    WHERE c.in_reply_to_id = t.id AND c.deleted_at IS NULL
+      AND post_visible_to(c.id, sqlc.arg('viewer_id'), false)
+    ORDER BY c.created_at, c.id
+    LIMIT sqlc.arg('limit')
+  ) c
+  WHERE t.depth < sqlc.arg('depth')::int
+)
+SELECT p.id, p.userid, p.title, p.description, p.in_reply_to_id,
+  (SELECT count(*) FROM reactions r WHERE r.post_id = p.id) AS reaction_count,
+  (SELECT count(*) FROM posts cp WHERE cp.in_reply_to_id = p.id
+    AND post_visible_to(cp.id, sqlc.arg('viewer_id'), false)) AS reply_count,
+  p.edited_at
+FROM tree t JOIN posts p ON p.id = t.id
+ORDER BY t.depth, p.created_at, p.id;
+
+-- name: PublishDuePosts :many
+UPDATE posts p
+  set status = 'published',
+  created_at = p.publish_at,
+  version = p.version + 1
+FROM (
+  SELECT d.id FROM posts d
+  WHERE d.status = 'scheduled' AND d.publish_at <= sqlc.arg('now')::timestamp
+  ORDER BY d.publish_at, d.id
+  LIMIT sqlc.arg('limit')
+  FOR UPDATE SKIP LOCKED
+) due
+WHERE p.id = due.id AND p.status = 'scheduled'
+RETURNING p.id;
+
+-- name: CreatePostRevision :one
+INSERT INTO post_revisions (
+  post_id, revision, title, description, tag_ids, editor_id
+)
+SELECT p.id,
+  COALESCE((SELECT max(r.revision) FROM post_revisions r WHERE r.post_id = p.id), 0) + 1,
+  p.title, p.description,
+  COALESCE((
+    SELECT array_agg(DISTINCT pt.tagid ORDER BY pt.tagid) FROM post_tags pt WHERE pt.postid = p.id
+  ), '{}')::int[],
+  COALESCE(sqlc.narg('editor_id')::int, p.userid)
+FROM posts p
+WHERE p.id = sqlc.arg('post_id')
+RETURNING post_id, revision, title, description, tag_ids, editor_id, created_at;
+
+-- name: GetPostRevisions :many
+SELECT post_id, revision, title, description, tag_ids, editor_id, created_at FROM post_revisions
+WHERE post_id = sqlc.arg('post_id')
+ORDER BY revision DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetPostRevision :one
+SELECT post_id, revision, title, description, tag_ids, editor_id, created_at FROM post_revisions
+WHERE post_id = sqlc.arg('post_id') AND revision = sqlc.arg('revision');
+
+-- name: AddReaction :execrows
+INSERT INTO reactions (post_id, user_id, kind)
+SELECT p.id, u.id, sqlc.arg('kind')::text
+FROM posts p, users u
+WHERE p.id = sqlc.arg('post_id') AND u.id = sqlc.arg('user_id')
+  AND post_visible_to(p.id, u.id, false)
+ON CONFLICT (post_id, user_id) DO UPDATE SET kind = EXCLUDED.kind;
+
+-- name: RemoveReaction :exec
+DELETE FROM reactions
+WHERE post_id = sqlc.arg('post_id') AND user_id = sqlc.arg('user_id');
@@ -0,0 +1,28 @@
+-- name: GetStreamEvents :many
+SELECT id, kind, recipient_id, post_id, actor_id, payload, created_at
+FROM stream_events
+WHERE id = ANY(sqlc.arg('ids')::bigint[])
+ORDER BY id;
+
+-- name: GetStreamRecipients :many
+SELECT e.id AS event_id, v.id::int AS viewer_id
+FROM stream_events e
+CROSS JOIN unnest(sqlc.arg('viewer_ids')::int[]) AS v(id)
+WHERE e.id = ANY(sqlc.arg('ids')::bigint[])
+  AND stream_event_visible_to(e, v.id)
+ORDER BY e.id, v.id;
+
+-- name: GetStreamEventsAfter :many
+SELECT e.id, e.kind, e.recipient_id, e.post_id, e.actor_id, e.payload, e.created_at
+FROM stream_events e
+WHERE e.id > sqlc.arg('after_id')
+  AND stream_event_visible_to(e, sqlc.arg('viewer_id'))
+ORDER BY e.id
+LIMIT sqlc.arg('limit');
+
+-- name: PurgeStreamEvents :execrows
+DELETE FROM stream_events
+WHERE created_at < now() - make_interval(mins => sqlc.arg('retention_minutes')::int);
+
+-- name: StreamUserExists :one
+SELECT EXISTS (SELECT 1 FROM users WHERE id = $1);
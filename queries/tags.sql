@@ -17,16 +17,112 @@ FROM post_tags a JOIN tags b
 ON a.tagID = b.id
 WHERE a.postid = $1;
 
+-- name: GetTagsByPostIDs :many
+SELECT
+	a.postid,
+	b.id,
+	b.tagname
+FROM post_tags a JOIN tags b
+ON a.tagID = b.id
+WHERE a.postid = ANY(sqlc.arg('postids')::int[])
+ORDER BY a.postid, a.id;
+
 -- name: UpdateTag :one
 UPDATE tags
-  set tagname = $2
-WHERE id = $1
-RETURNING id, tagname;
+  set tagname = sqlc.arg('tagname'),
+  version = version + 1
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version)
+RETURNING id, tagname, version;
 
--- name: DeleteTag :exec
+-- name: DeleteTag :execrows
 DELETE FROM tags
-WHERE id = $1;
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version);
 
 -- name: GetTag :one
-SELECT id, tagname FROM tags
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+SELECT id, tagname, version FROM tags
+WHERE id = $1 LIMIT 1;
+
+-- name: PatchTag :one
+UPDATE tags
+  set tagname = COALESCE(sqlc.narg('tagname'), tagname),
+  version = version + 1
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version)
+RETURNING id, tagname, version;
+
+-- name: SearchTags :many
+SELECT t.id, t.tagname, count(pt.id) AS usage_count
+FROM tags t LEFT JOIN post_tags pt
+ON pt.tagid = t.id
+WHERE t.tagname ILIKE sqlc.arg('prefix') OR t.tagname % sqlc.arg('query')
+GROUP BY t.id
+ORDER BY t.tagname ILIKE sqlc.arg('prefix') DESC,
+  usage_count DESC,
+  similarity(t.tagname, sqlc.arg('query')) DESC,
+  t.id
+LIMIT sqlc.arg('limit');
+
+-- name: GetTagUsageCounts :many
+-- created_at holds the publish time once a post is published, so only
+-- posts that show up in listings count, at the time they went out.
+SELECT pt.tagid,
+  count(*) FILTER (WHERE p.created_at >= sqlc.arg('window_start')::timestamp) AS recent_count,
+  count(*) FILTER (WHERE p.created_at < sqlc.arg('window_start')::timestamp) AS baseline_count
+FROM post_tags pt JOIN posts p
+ON p.id = pt.postid
+WHERE p.status = 'published'
+  AND p.visibility = 'public'
+  AND p.in_reply_to_id IS NULL
+  AND p.created_at >= sqlc.arg('baseline_start')::timestamp
+  AND p.created_at < sqlc.arg('window_end')::timestamp
+GROUP BY pt.tagid;
+
+-- name: UpsertTagTrends :exec
+INSERT INTO tag_trends (
+  tag_id, window_minutes, recent_count, baseline_count, score, computed_at
+)
+SELECT
+  unnest(sqlc.arg('tag_ids')::int[]),
+  sqlc.arg('window_minutes')::int,
+  unnest(sqlc.arg('recent_counts')::int[]),
+  unnest(sqlc.arg('baseline_counts')::int[]),
+  unnest(sqlc.arg('scores')::float8[]),
+  sqlc.arg('computed_at')::timestamp
+ON CONFLICT (tag_id, window_minutes) DO UPDATE SET
+  recent_count = EXCLUDED.recent_count,
+  baseline_count = EXCLUDED.baseline_count,
+  score = EXCLUDED.score,
+  computed_at = EXCLUDED.computed_at;
+
+-- name: DeleteStaleTagTrends :exec
+DELETE FROM tag_trends
+WHERE window_minutes = sqlc.arg('window_minutes')
+  AND computed_at < sqlc.arg('computed_at');
+
+-- name: GetTrendingTags :many
+SELECT t.id, t.tagname, tt.recent_count, tt.baseline_count, tt.score, tt.computed_at
+FROM tag_trends tt JOIN tags t
+ON t.id = tt.tag_id
+WHERE tt.window_minutes = sqlc.arg('window_minutes')
+  AND tt.recent_count > 0
+ORDER BY tt.score DESC, tt.recent_count DESC, t.id
+LIMIT sqlc.arg('limit');
+
+-- name: FollowTag :execrows
+INSERT INTO tag_follows (user_id, tag_id)
+SELECT u.id, t.id FROM users u, tags t
+WHERE u.id = sqlc.arg('user_id') AND t.id = sqlc.arg('tag_id')
+ON CONFLICT (user_id, tag_id) DO UPDATE SET created_at = tag_follows.created_at;
+
+-- name: UnfollowTag :exec
+DELETE FROM tag_follows
+WHERE user_id = sqlc.arg('user_id') AND tag_id = sqlc.arg('tag_id');
+
+-- name: GetFollowedTags :many
+SELECT t.id, t.tagname
+FROM tag_follows tf JOIN tags t
+ON t.id = tf.tag_id
+WHERE tf.user_id = $1
+ORDER BY t.tagname, t.id;
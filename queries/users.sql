@@ -12,14 +12,146 @@ RETURNING id, fullname;
 
 -- name: UpdateUser :one
 UPDATE users
-  set fullname = $2
-WHERE id = $1
-RETURNING id, fullname;
+  set fullname = sqlc.arg('fullname'),
+  version = version + 1
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version)
+RETURNING id, fullname, version;
 
--- name: DeleteUser :exec
+-- name: DeleteUser :execrows
 DELETE FROM users
-WHERE id = $1;
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version);
 
 -- name: GetUser :one
+SELECT id, fullname, version FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: IsAdmin :one
+SELECT is_admin FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: PatchUser :one
+UPDATE users
+  set fullname = COALESCE(sqlc.narg('fullname'), fullname),
+  version = version + 1
+WHERE id = sqlc.arg('id')
+  AND version = COALESCE(sqlc.narg('version'), version)
+RETURNING id, fullname, version;
+
+-- name: SearchUsers :many
 SELECT id, fullname FROM users
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE fullname ILIKE sqlc.arg('prefix') OR fullname % sqlc.arg('query')
+ORDER BY fullname ILIKE sqlc.arg('prefix') DESC,
+  similarity(fullname, sqlc.arg('query')) DESC,
+  id
+LIMIT sqlc.arg('limit');
+
+-- name: FollowUser :execrows
+INSERT INTO user_follows (follower_id, followee_id)
+SELECT f.id, e.id FROM users f, users e
+WHERE f.id = sqlc.arg('follower_id') AND e.id = sqlc.arg('followee_id')
+ON CONFLICT (follower_id, followee_id) DO UPDATE SET created_at = user_follows.created_at;
+
+-- name: UnfollowUser :exec
+DELETE FROM user_follows
+WHERE follower_id = sqlc.arg('follower_id') AND followee_id = sqlc.arg('followee_id');
+
+-- name: BlockUser :execrows
+WITH unfollowed AS (
+  DELETE FROM user_follows
+  WHERE (follower_id = sqlc.arg('blocker_id') AND followee_id = sqlc.arg('blocked_id'))
+    OR (follower_id = sqlc.arg('blocked_id') AND followee_id = sqlc.arg('blocker_id'))
+)
+INSERT INTO user_blocks (blocker_id, blocked_id)
+SELECT b.id, d.id FROM users b, users d
+WHERE b.id = sqlc.arg('blocker_id') AND d.id = sqlc.arg('blocked_id')
+ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET created_at = user_blocks.created_at;
+
+-- name: UnblockUser :exec
+DELETE FROM user_blocks
+WHERE blocker_id = sqlc.arg('blocker_id') AND blocked_id = sqlc.arg('blocked_id');
+
+-- name: GetBlockedUsers :many
+SELECT u.id, u.fullname FROM user_blocks b
+JOIN users u ON u.id = b.blocked_id
+WHERE b.blocker_id = $1
+ORDER BY b.created_at DESC, u.id;
+
+-- name: MuteUser :execrows
+INSERT INTO user_mutes (muter_id, muted_id)
+SELECT m.id, d.id FROM users m, users d
+WHERE m.id = sqlc.arg('muter_id') AND d.id = sqlc.arg('muted_id')
+ON CONFLICT (muter_id, muted_id) DO UPDATE SET created_at = user_mutes.created_at;
+
+-- name: UnmuteUser :exec
+DELETE FROM user_mutes
+WHERE muter_id = sqlc.arg('muter_id') AND muted_id = sqlc.arg('muted_id');
+
+-- name: GetMutedUsers :many
+SELECT u.id, u.fullname FROM user_mutes m
+JOIN users u ON u.id = m.muted_id
+WHERE m.muter_id = $1
+ORDER BY m.created_at DESC, u.id;
+
+-- name: GetProfile :one
+SELECT u.id, u.fullname, u.handle, u.bio, u.location, u.website, u.avatar_media_id,
+  m.thumbnail_content_type AS avatar_thumbnail_content_type,
+  (SELECT count(*) FROM posts p
+    WHERE p.userid = u.id AND post_visible_to(p.id, sqlc.arg('viewer_id'), true))::int AS post_count,
+  (SELECT count(*) FROM user_follows f WHERE f.followee_id = u.id)::int AS follower_count,
+  (SELECT count(*) FROM user_follows f WHERE f.follower_id = u.id)::int AS following_count
+FROM users u
+LEFT JOIN media m ON m.id = u.avatar_media_id
+WHERE u.handle = sqlc.arg('handle')::text
+  OR u.id = (
+    SELECT r.user_id FROM user_handle_redirects r
+    WHERE r.handle = sqlc.arg('handle')::text AND r.expires_at > now()
+  )
+LIMIT 1;
+
+-- name: GetHandleForUpdate :one
+SELECT handle, handle_changed_at FROM users
+WHERE id = $1
+FOR UPDATE;
+
+-- name: LockHandle :exec
+SELECT pg_advisory_xact_lock(hashtextextended('handle:' || sqlc.arg('handle')::text, 0));
+
+-- name: ClaimHandleRedirect :exec
+DELETE FROM user_handle_redirects
+WHERE handle = sqlc.arg('handle')
+  AND (user_id = sqlc.arg('user_id') OR expires_at <= now());
+
+-- name: IsHandleReserved :one
+SELECT EXISTS (
+  SELECT 1 FROM user_handle_redirects WHERE handle = $1
+);
+
+-- name: AddHandleRedirect :exec
+INSERT INTO user_handle_redirects (handle, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (handle) DO UPDATE SET
+  user_id = EXCLUDED.user_id,
+  expires_at = EXCLUDED.expires_at;
+
+-- name: IsOwnImage :one
+SELECT EXISTS (
+  SELECT 1 FROM media
+  WHERE id = sqlc.arg('media_id') AND user_id = sqlc.arg('user_id') AND content_type LIKE 'image/%'
+);
+
+-- name: UpdateProfile :one
+UPDATE users
+  set handle = sqlc.arg('handle')::text,
+  handle_changed_at = CASE
+    WHEN handle IS DISTINCT FROM sqlc.arg('handle')::text THEN sqlc.arg('changed_at')::timestamp
+    ELSE handle_changed_at
+  END,
+  bio = sqlc.arg('bio'),
+  location = sqlc.arg('location'),
+  website = sqlc.arg('website'),
+  avatar_media_id = sqlc.narg('avatar_media_id'),
+  version = version + 1
+WHERE id = sqlc.arg('id')
+RETURNING id, fullname, handle, bio, location, website, avatar_media_id, version;
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+)
+
+type BookmarkService interface {
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (CreateCollectionRow, error)
+	RenameCollection(ctx context.Context, arg RenameCollectionParams) (RenameCollectionRow, error)
+	DeleteCollection(ctx context.Context, arg DeleteCollectionParams) error
+	GetCollections(ctx context.Context, userID int32) ([]GetCollectionsRow, error)
+	AddBookmark(ctx context.Context, arg AddBookmarkParams) error
+	RemoveBookmark(ctx context.Context, arg RemoveBookmarkParams) error
+}
+
+type bookmarkService struct {
+	br BookmarkResource
+}
+
+func NewBookmarkService(BR BookmarkResource) (BookmarkService, error) {
+	return &bookmarkService{
+		br: BR,
+	}, nil
+}
+
+// CreateCollection returns sql.ErrNoRows when the user does not exist and
+// ErrCollectionExists when the user already has a collection of that name.
+func (bs *bookmarkService) CreateCollection(ctx context.Context, arg CreateCollectionParams) (CreateCollectionRow, error) {
+	var result CreateCollectionRow = CreateCollectionRow{}
+	res, err := bs.br.CreateCollection(ctx, bookmark.CreateCollectionParams{
+		UserID: arg.UserID,
+		Name:   arg.Name,
+	})
+	if isPQError(err, pqForeignKeyViolation) {
+		return result, sql.ErrNoRows
+	}
+	if isPQError(err, pqUniqueViolation) {
+		return result, ErrCollectionExists
+	}
+	if err != nil {
+		logSQLError(ctx, "CreateCollection", err)
+		return result, err
+	}
+	result = CreateCollectionRow(res)
+	return result, nil
+}
+
+func (bs *bookmarkService) RenameCollection(ctx context.Context, arg RenameCollectionParams) (RenameCollectionRow, error) {
+	var result RenameCollectionRow = RenameCollectionRow{}
+	res, err := bs.br.RenameCollection(ctx, bookmark.RenameCollectionParams{
+		Name:   arg.Name,
+		ID:     arg.ID,
+		UserID: arg.UserID,
+	})
+	if isPQError(err, pqUniqueViolation) {
+		return result, ErrCollectionExists
+	}
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logSQLError(ctx, "RenameCollection", err)
+		}
+		return result, err
+	}
+	result = RenameCollectionRow(res)
+	return result, nil
+}
+
+// DeleteCollection also removes the bookmarks filed in the collection. A
+// collection of another user gives sql.ErrNoRows.
+func (bs *bookmarkService) DeleteCollection(ctx context.Context, arg DeleteCollectionParams) error {
+	deleted, err := bs.br.DeleteCollection(ctx, bookmark.DeleteCollectionParams{
+		ID:     arg.ID,
+		UserID: arg.UserID,
+	})
+	if err != nil {
+		logSQLError(ctx, "DeleteCollection", err)
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (bs *bookmarkService) GetCollections(ctx context.Context, userID int32) ([]GetCollectionsRow, error) {
+	var result []GetCollectionsRow = []GetCollectionsRow{}
+	res, err := bs.br.GetCollections(ctx, userID)
+	if err != nil {
+		logSQLError(ctx, "GetCollections", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, GetCollectionsRow(item))
+	}
+	return result, nil
+}
+
+// AddBookmark succeeds when the post is already saved there. It returns
+// sql.ErrNoRows when the user or post does not exist, or the collection does
+// not exist or belongs to someone else.
+func (bs *bookmarkService) AddBookmark(ctx context.Context, arg AddBookmarkParams) error {
+	added, err := bs.br.AddBookmark(ctx, bookmark.AddBookmarkParams{
+		CollectionID: nullInt32(arg.CollectionID),
+		UserID:       arg.UserID,
+		PostID:       arg.PostID,
+	})
+	if err != nil {
+		logSQLError(ctx, "AddBookmark", err)
+		return err
+	}
+	if added == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (bs *bookmarkService) RemoveBookmark(ctx context.Context, arg RemoveBookmarkParams) error {
+	err := bs.br.RemoveBookmark(ctx, bookmark.RemoveBookmarkParams{
+		UserID:       arg.UserID,
+		PostID:       arg.PostID,
+		CollectionID: nullInt32(arg.CollectionID),
+	})
+	if err != nil {
+		logSQLError(ctx, "RemoveBookmark", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+)
+
+func TestNewBookmarkService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookmarkMock := NewMockBookmarkResource(ctrl)
+
+	got, err := NewBookmarkService(bookmarkMock)
+	if err != nil {
+		t.Fatalf("NewBookmarkService() error = %v", err)
+	}
+	want := &bookmarkService{
+		br: bookmarkMock,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBookmarkService() = %v, want %v", got, want)
+	}
+}
+
+func Test_CreateCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := CreateCollectionParams{UserID: 1, Name: "recipes"}
+
+	tests := []struct {
+		name    string
+		mock    func() *bookmarkService
+		want    CreateCollectionRow
+		wantErr error
+	}{
+		{
+			name: "success create collection",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().CreateCollection(gomock.Any(), bookmark.CreateCollectionParams{
+					UserID: 1,
+					Name:   "recipes",
+				}).Return(bookmark.CreateCollectionRow{ID: 3, UserID: 1, Name: "recipes"}, nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want: CreateCollectionRow{ID: 3, UserID: 1, Name: "recipes"},
+		},
+		{
+			name: "user not found",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).Return(bookmark.CreateCollectionRow{}, &pq.Error{Code: pqForeignKeyViolation})
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    CreateCollectionRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "name already used",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).Return(bookmark.CreateCollectionRow{}, &pq.Error{Code: pqUniqueViolation})
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    CreateCollectionRow{},
+			wantErr: ErrCollectionExists,
+		},
+		{
+			name: "error create collection",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).Return(bookmark.CreateCollectionRow{}, errors.New("error"))
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    CreateCollectionRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.CreateCollection(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("CreateCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RenameCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := RenameCollectionParams{ID: 3, UserID: 1, Name: "desserts"}
+
+	tests := []struct {
+		name    string
+		mock    func() *bookmarkService
+		want    RenameCollectionRow
+		wantErr error
+	}{
+		{
+			name: "success rename collection",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RenameCollection(gomock.Any(), bookmark.RenameCollectionParams{
+					Name:   "desserts",
+					ID:     3,
+					UserID: 1,
+				}).Return(bookmark.RenameCollectionRow{ID: 3, UserID: 1, Name: "desserts"}, nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want: RenameCollectionRow{ID: 3, UserID: 1, Name: "desserts"},
+		},
+		{
+			name: "collection not found",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RenameCollection(gomock.Any(), gomock.Any()).Return(bookmark.RenameCollectionRow{}, sql.ErrNoRows)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    RenameCollectionRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "name already used",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RenameCollection(gomock.Any(), gomock.Any()).Return(bookmark.RenameCollectionRow{}, &pq.Error{Code: pqUniqueViolation})
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    RenameCollectionRow{},
+			wantErr: ErrCollectionExists,
+		},
+		{
+			name: "error rename collection",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RenameCollection(gomock.Any(), gomock.Any()).Return(bookmark.RenameCollectionRow{}, errors.New("error"))
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    RenameCollectionRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.RenameCollection(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("RenameCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RenameCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DeleteCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := DeleteCollectionParams{ID: 3, UserID: 1}
+
+	tests := []struct {
+		name    string
+		mock    func() *bookmarkService
+		wantErr error
+	}{
+		{
+			name: "success delete collection",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().DeleteCollection(gomock.Any(), bookmark.DeleteCollectionParams{ID: 3, UserID: 1}).Return(int64(1), nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+		},
+		{
+			name: "collection not found",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().DeleteCollection(gomock.Any(), bookmark.DeleteCollectionParams{ID: 3, UserID: 1}).Return(int64(0), nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error delete collection",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().DeleteCollection(gomock.Any(), bookmark.DeleteCollectionParams{ID: 3, UserID: 1}).Return(int64(0), errors.New("error"))
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.DeleteCollection(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("DeleteCollection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetCollections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mock    func() *bookmarkService
+		want    []GetCollectionsRow
+		wantErr bool
+	}{
+		{
+			name: "success get collections",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().GetCollections(gomock.Any(), int32(1)).Return([]bookmark.GetCollectionsRow{
+					{ID: 3, UserID: 1, Name: "recipes", PostCount: 2},
+				}, nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want: []GetCollectionsRow{
+				{ID: 3, UserID: 1, Name: "recipes", PostCount: 2},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get collections",
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().GetCollections(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			want:    []GetCollectionsRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.GetCollections(ctx, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCollections() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCollections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddBookmark(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     AddBookmarkParams
+		mock    func() *bookmarkService
+		wantErr error
+	}{
+		{
+			name: "success add bookmark",
+			arg:  AddBookmarkParams{UserID: 1, PostID: 2},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().AddBookmark(gomock.Any(), bookmark.AddBookmarkParams{
+					UserID: 1,
+					PostID: 2,
+				}).Return(int64(1), nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+		},
+		{
+			name: "success add bookmark to a collection",
+			arg:  AddBookmarkParams{UserID: 1, PostID: 2, CollectionID: 3},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().AddBookmark(gomock.Any(), bookmark.AddBookmarkParams{
+					CollectionID: sql.NullInt32{Int32: 3, Valid: true},
+					UserID:       1,
+					PostID:       2,
+				}).Return(int64(1), nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+		},
+		{
+			name: "user, post or collection not found",
+			arg:  AddBookmarkParams{UserID: 1, PostID: 2, CollectionID: 3},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().AddBookmark(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error add bookmark",
+			arg:  AddBookmarkParams{UserID: 1, PostID: 2},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().AddBookmark(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.AddBookmark(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("AddBookmark() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_RemoveBookmark(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     RemoveBookmarkParams
+		mock    func() *bookmarkService
+		wantErr bool
+	}{
+		{
+			name: "success remove bookmark everywhere",
+			arg:  RemoveBookmarkParams{UserID: 1, PostID: 2},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RemoveBookmark(gomock.Any(), bookmark.RemoveBookmarkParams{
+					UserID: 1,
+					PostID: 2,
+				}).Return(nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "success remove bookmark from a collection",
+			arg:  RemoveBookmarkParams{UserID: 1, PostID: 2, CollectionID: 3},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RemoveBookmark(gomock.Any(), bookmark.RemoveBookmarkParams{
+					UserID:       1,
+					PostID:       2,
+					CollectionID: sql.NullInt32{Int32: 3, Valid: true},
+				}).Return(nil)
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error remove bookmark",
+			arg:  RemoveBookmarkParams{UserID: 1, PostID: 2},
+			mock: func() *bookmarkService {
+				m := NewMockBookmarkResource(ctrl)
+				m.EXPECT().RemoveBookmark(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &bookmarkService{
+					br: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.RemoveBookmark(ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RemoveBookmark() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
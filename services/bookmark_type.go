@@ -0,0 +1,53 @@
+package services
+
+type CreateCollectionParams struct {
+	UserID int32
+	Name   string
+}
+
+type CreateCollectionRow struct {
+	ID     int32  `json:"id"`
+	UserID int32  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type RenameCollectionParams struct {
+	ID int32
+	// UserID owns the collection. A collection of another user is not
+	// found.
+	UserID int32
+	Name   string
+}
+
+type DeleteCollectionParams struct {
+	ID     int32
+	UserID int32
+}
+
+type RenameCollectionRow struct {
+	ID     int32  `json:"id"`
+	UserID int32  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type GetCollectionsRow struct {
+	ID        int32  `json:"id"`
+	UserID    int32  `json:"user_id"`
+	Name      string `json:"name"`
+	PostCount int64  `json:"post_count"`
+}
+
+type AddBookmarkParams struct {
+	UserID int32
+	PostID int32
+	// CollectionID is 0 to save the post outside any collection.
+	CollectionID int32
+}
+
+type RemoveBookmarkParams struct {
+	UserID int32
+	PostID int32
+	// CollectionID is 0 to remove the post from every collection and from
+	// the unfiled bookmarks.
+	CollectionID int32
+}
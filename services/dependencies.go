@@ -3,8 +3,13 @@ package services
 import (
 	"context"
 
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/pkg/message"
+	"github.com/gadhittana01/socialmedia/pkg/notification"
 	"github.com/gadhittana01/socialmedia/pkg/post"
 	"github.com/gadhittana01/socialmedia/pkg/post_tags"
+	"github.com/gadhittana01/socialmedia/pkg/stream"
 	"github.com/gadhittana01/socialmedia/pkg/tag"
 	"github.com/gadhittana01/socialmedia/pkg/user"
 )
@@ -13,30 +18,135 @@ type (
 	UserResource interface {
 		CreateUser(ctx context.Context, fullname string) (user.CreateUserRow, error)
 		GetUsers(ctx context.Context) ([]user.GetUsersRow, error)
+		SearchUsers(ctx context.Context, arg user.SearchUsersParams) ([]user.SearchUsersRow, error)
 		UpdateUser(ctx context.Context, arg user.UpdateUserParams) (user.UpdateUserRow, error)
-		DeleteUser(ctx context.Context, id int32) error
+		DeleteUser(ctx context.Context, arg user.DeleteUserParams) (int64, error)
 		GetUser(ctx context.Context, id int32) (user.GetUserRow, error)
+		PatchUser(ctx context.Context, arg user.PatchUserParams) (user.PatchUserRow, error)
+		FollowUser(ctx context.Context, arg user.FollowUserParams) (int64, error)
+		UnfollowUser(ctx context.Context, arg user.UnfollowUserParams) error
+		BlockUser(ctx context.Context, arg user.BlockUserParams) (int64, error)
+		UnblockUser(ctx context.Context, arg user.UnblockUserParams) error
+		GetBlockedUsers(ctx context.Context, blockerID int32) ([]user.GetBlockedUsersRow, error)
+		MuteUser(ctx context.Context, arg user.MuteUserParams) (int64, error)
+		UnmuteUser(ctx context.Context, arg user.UnmuteUserParams) error
+		GetMutedUsers(ctx context.Context, muterID int32) ([]user.GetMutedUsersRow, error)
+		IsAdmin(ctx context.Context, id int32) (bool, error)
+		GetProfile(ctx context.Context, arg user.GetProfileParams) (user.GetProfileRow, error)
+		GetHandleForUpdate(ctx context.Context, id int32) (user.GetHandleForUpdateRow, error)
+		LockHandle(ctx context.Context, handle string) error
+		ClaimHandleRedirect(ctx context.Context, arg user.ClaimHandleRedirectParams) error
+		IsHandleReserved(ctx context.Context, handle string) (bool, error)
+		AddHandleRedirect(ctx context.Context, arg user.AddHandleRedirectParams) error
+		IsOwnImage(ctx context.Context, arg user.IsOwnImageParams) (bool, error)
+		UpdateProfile(ctx context.Context, arg user.UpdateProfileParams) (user.UpdateProfileRow, error)
 	}
 
 	PostResource interface {
 		CreatePost(ctx context.Context, arg post.CreatePostParams) (post.CreatePostRow, error)
-		GetPosts(ctx context.Context) ([]post.GetPostsRow, error)
+		GetPosts(ctx context.Context, arg post.GetPostsParams) ([]post.GetPostsRow, error)
+		SearchPosts(ctx context.Context, arg post.SearchPostsParams) ([]post.SearchPostsRow, error)
+		GetTagFeed(ctx context.Context, arg post.GetTagFeedParams) ([]post.GetTagFeedRow, error)
+		GetBookmarks(ctx context.Context, arg post.GetBookmarksParams) ([]post.GetBookmarksRow, error)
 		UpdatePost(ctx context.Context, arg post.UpdatePostParams) (post.UpdatePostRow, error)
-		DeletePost(ctx context.Context, id int32) error
+		DeletePost(ctx context.Context, arg post.DeletePostParams) (int64, error)
 		GetPost(ctx context.Context, id int32) (post.GetPostRow, error)
+		GetVisiblePost(ctx context.Context, arg post.GetVisiblePostParams) (post.GetVisiblePostRow, error)
+		PatchPost(ctx context.Context, arg post.PatchPostParams) (post.PatchPostRow, error)
+		GetPostsByIDs(ctx context.Context, arg post.GetPostsByIDsParams) ([]post.GetPostsByIDsRow, error)
+		GetRepostTarget(ctx context.Context, id int32) (post.GetRepostTargetRow, error)
+		CreateRepost(ctx context.Context, arg post.CreateRepostParams) (post.CreateRepostRow, error)
+		CreateQuote(ctx context.Context, arg post.CreateQuoteParams) (post.CreateQuoteRow, error)
+		DeleteRepost(ctx context.Context, arg post.DeleteRepostParams) error
+		AddReaction(ctx context.Context, arg post.AddReactionParams) (int64, error)
+		RemoveReaction(ctx context.Context, arg post.RemoveReactionParams) error
+		GetReplyChain(ctx context.Context, arg post.GetReplyChainParams) ([]post.GetReplyChainRow, error)
+		GetReplyTree(ctx context.Context, arg post.GetReplyTreeParams) ([]post.GetReplyTreeRow, error)
+		PublishDuePosts(ctx context.Context, arg post.PublishDuePostsParams) ([]int32, error)
+		CreatePostRevision(ctx context.Context, arg post.CreatePostRevisionParams) (post.PostRevision, error)
+		GetPostRevision(ctx context.Context, arg post.GetPostRevisionParams) (post.PostRevision, error)
+		GetPostRevisions(ctx context.Context, arg post.GetPostRevisionsParams) ([]post.PostRevision, error)
 	}
 
 	TagResource interface {
 		CreateTag(ctx context.Context, tagname string) (tag.CreateTagRow, error)
 		GetTagByPostID(ctx context.Context, postid int32) ([]tag.GetTagByPostIDRow, error)
 		GetTags(ctx context.Context) ([]tag.GetTagsRow, error)
+		GetTagsByPostIDs(ctx context.Context, postids []int32) ([]tag.GetTagsByPostIDsRow, error)
+		SearchTags(ctx context.Context, arg tag.SearchTagsParams) ([]tag.SearchTagsRow, error)
+		GetTagUsageCounts(ctx context.Context, arg tag.GetTagUsageCountsParams) ([]tag.GetTagUsageCountsRow, error)
+		UpsertTagTrends(ctx context.Context, arg tag.UpsertTagTrendsParams) error
+		DeleteStaleTagTrends(ctx context.Context, arg tag.DeleteStaleTagTrendsParams) error
+		GetTrendingTags(ctx context.Context, arg tag.GetTrendingTagsParams) ([]tag.GetTrendingTagsRow, error)
+		FollowTag(ctx context.Context, arg tag.FollowTagParams) (int64, error)
+		UnfollowTag(ctx context.Context, arg tag.UnfollowTagParams) error
+		GetFollowedTags(ctx context.Context, userID int32) ([]tag.GetFollowedTagsRow, error)
 		UpdateTag(ctx context.Context, arg tag.UpdateTagParams) (tag.UpdateTagRow, error)
-		DeleteTag(ctx context.Context, id int32) error
+		DeleteTag(ctx context.Context, arg tag.DeleteTagParams) (int64, error)
 		GetTag(ctx context.Context, id int32) (tag.GetTagRow, error)
+		PatchTag(ctx context.Context, arg tag.PatchTagParams) (tag.PatchTagRow, error)
+	}
+
+	MediaResource interface {
+		CreateMedia(ctx context.Context, arg media.CreateMediaParams) (media.Medium, error)
+		GetVisibleMedia(ctx context.Context, arg media.GetVisibleMediaParams) (media.Medium, error)
+		AttachPostMedia(ctx context.Context, arg media.AttachPostMediaParams) (int64, error)
+		GetPostMedia(ctx context.Context, postID int32) ([]media.GetPostMediaRow, error)
+	}
+
+	NotificationResource interface {
+		ClaimNotificationEvents(ctx context.Context, limit int32) ([]notification.NotificationEvent, error)
+		UpsertNotification(ctx context.Context, arg notification.UpsertNotificationParams) (int32, error)
+		AddNotificationActor(ctx context.Context, arg notification.AddNotificationActorParams) error
+		DeleteNotificationEvents(ctx context.Context, ids []int64) error
+		GetNotifications(ctx context.Context, arg notification.GetNotificationsParams) ([]notification.GetNotificationsRow, error)
+		GetNotificationActors(ctx context.Context, arg notification.GetNotificationActorsParams) ([]notification.GetNotificationActorsRow, error)
+		MarkNotificationRead(ctx context.Context, arg notification.MarkNotificationReadParams) (int64, error)
+		MarkAllNotificationsRead(ctx context.Context, recipientID int32) (int64, error)
+		GetNotificationPreferences(ctx context.Context, userID int32) ([]notification.GetNotificationPreferencesRow, error)
+		SetNotificationPreference(ctx context.Context, arg notification.SetNotificationPreferenceParams) error
+	}
+
+	MessageResource interface {
+		CreateConversation(ctx context.Context, arg message.CreateConversationParams) (message.Conversation, error)
+		AddConversationParticipants(ctx context.Context, arg message.AddConversationParticipantsParams) error
+		GetConversation(ctx context.Context, arg message.GetConversationParams) (message.GetConversationRow, error)
+		GetConversations(ctx context.Context, arg message.GetConversationsParams) ([]message.GetConversationsRow, error)
+		GetConversationParticipants(ctx context.Context, conversationIds []int32) ([]message.GetConversationParticipantsRow, error)
+		GetLastMessages(ctx context.Context, arg message.GetLastMessagesParams) ([]message.Message, error)
+		SendMessage(ctx context.Context, arg message.SendMessageParams) (message.Message, error)
+		GetMessage(ctx context.Context, arg message.GetMessageParams) (message.Message, error)
+		GetMessages(ctx context.Context, arg message.GetMessagesParams) ([]message.Message, error)
+		MarkConversationRead(ctx context.Context, arg message.MarkConversationReadParams) (message.MarkConversationReadRow, error)
+	}
+
+	StreamResource interface {
+		StreamUserExists(ctx context.Context, id int32) (bool, error)
+		GetStreamEvents(ctx context.Context, ids []int64) ([]stream.StreamEvent, error)
+		GetStreamRecipients(ctx context.Context, arg stream.GetStreamRecipientsParams) ([]stream.GetStreamRecipientsRow, error)
+		GetStreamEventsAfter(ctx context.Context, arg stream.GetStreamEventsAfterParams) ([]stream.StreamEvent, error)
+		PurgeStreamEvents(ctx context.Context, retentionMinutes int32) (int64, error)
 	}
 
 	PostTagResource interface {
 		CreatePostTag(ctx context.Context, arg post_tags.CreatePostTagParams) (post_tags.CreatePostTagRow, error)
-		DeletePostTag(ctx context.Context, postid int32) error
+		DeletePostTagsByTagIDs(ctx context.Context, arg post_tags.DeletePostTagsByTagIDsParams) error
+		GetPostTagIDs(ctx context.Context, postid int32) ([]int32, error)
+	}
+
+	BookmarkResource interface {
+		CreateCollection(ctx context.Context, arg bookmark.CreateCollectionParams) (bookmark.CreateCollectionRow, error)
+		RenameCollection(ctx context.Context, arg bookmark.RenameCollectionParams) (bookmark.RenameCollectionRow, error)
+		DeleteCollection(ctx context.Context, arg bookmark.DeleteCollectionParams) (int64, error)
+		GetCollections(ctx context.Context, userID int32) ([]bookmark.GetCollectionsRow, error)
+		AddBookmark(ctx context.Context, arg bookmark.AddBookmarkParams) (int64, error)
+		RemoveBookmark(ctx context.Context, arg bookmark.RemoveBookmarkParams) error
+		GetBookmarkedPostIDs(ctx context.Context, arg bookmark.GetBookmarkedPostIDsParams) ([]int32, error)
+	}
+
+	// TxRunner runs fn in a database transaction, which commits when fn
+	// returns nil and rolls back otherwise.
+	TxRunner interface {
+		InTx(ctx context.Context, fn func(tx TxResources) error) error
 	}
 )
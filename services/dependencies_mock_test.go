@@ -8,8 +8,13 @@ import (
 	context "context"
 	reflect "reflect"
 
+	bookmark "github.com/gadhittana01/socialmedia/pkg/bookmark"
+	media "github.com/gadhittana01/socialmedia/pkg/media"
+	message "github.com/gadhittana01/socialmedia/pkg/message"
+	notification "github.com/gadhittana01/socialmedia/pkg/notification"
 	post "github.com/gadhittana01/socialmedia/pkg/post"
 	post_tags "github.com/gadhittana01/socialmedia/pkg/post_tags"
+	stream "github.com/gadhittana01/socialmedia/pkg/stream"
 	tag "github.com/gadhittana01/socialmedia/pkg/tag"
 	user "github.com/gadhittana01/socialmedia/pkg/user"
 	gomock "github.com/golang/mock/gomock"
@@ -38,6 +43,49 @@ func (m *MockUserResource) EXPECT() *MockUserResourceMockRecorder {
 	return m.recorder
 }
 
+// AddHandleRedirect mocks base method.
+func (m *MockUserResource) AddHandleRedirect(ctx context.Context, arg user.AddHandleRedirectParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHandleRedirect", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHandleRedirect indicates an expected call of AddHandleRedirect.
+func (mr *MockUserResourceMockRecorder) AddHandleRedirect(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHandleRedirect", reflect.TypeOf((*MockUserResource)(nil).AddHandleRedirect), ctx, arg)
+}
+
+// BlockUser mocks base method.
+func (m *MockUserResource) BlockUser(ctx context.Context, arg user.BlockUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockUserResourceMockRecorder) BlockUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockUserResource)(nil).BlockUser), ctx, arg)
+}
+
+// ClaimHandleRedirect mocks base method.
+func (m *MockUserResource) ClaimHandleRedirect(ctx context.Context, arg user.ClaimHandleRedirectParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimHandleRedirect", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimHandleRedirect indicates an expected call of ClaimHandleRedirect.
+func (mr *MockUserResourceMockRecorder) ClaimHandleRedirect(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimHandleRedirect", reflect.TypeOf((*MockUserResource)(nil).ClaimHandleRedirect), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserResource) CreateUser(ctx context.Context, fullname string) (user.CreateUserRow, error) {
 	m.ctrl.T.Helper()
@@ -54,17 +102,93 @@ func (mr *MockUserResourceMockRecorder) CreateUser(ctx, fullname interface{}) *g
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserResource) DeleteUser(ctx context.Context, id int32) error {
+func (m *MockUserResource) DeleteUser(ctx context.Context, arg user.DeleteUserParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserResourceMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call {
+func (mr *MockUserResourceMockRecorder) DeleteUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserResource)(nil).DeleteUser), ctx, arg)
+}
+
+// FollowUser mocks base method.
+func (m *MockUserResource) FollowUser(ctx context.Context, arg user.FollowUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowUser indicates an expected call of FollowUser.
+func (mr *MockUserResourceMockRecorder) FollowUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowUser", reflect.TypeOf((*MockUserResource)(nil).FollowUser), ctx, arg)
+}
+
+// GetBlockedUsers mocks base method.
+func (m *MockUserResource) GetBlockedUsers(ctx context.Context, blockerID int32) ([]user.GetBlockedUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsers", ctx, blockerID)
+	ret0, _ := ret[0].([]user.GetBlockedUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsers indicates an expected call of GetBlockedUsers.
+func (mr *MockUserResourceMockRecorder) GetBlockedUsers(ctx, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsers", reflect.TypeOf((*MockUserResource)(nil).GetBlockedUsers), ctx, blockerID)
+}
+
+// GetHandleForUpdate mocks base method.
+func (m *MockUserResource) GetHandleForUpdate(ctx context.Context, id int32) (user.GetHandleForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHandleForUpdate", ctx, id)
+	ret0, _ := ret[0].(user.GetHandleForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHandleForUpdate indicates an expected call of GetHandleForUpdate.
+func (mr *MockUserResourceMockRecorder) GetHandleForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHandleForUpdate", reflect.TypeOf((*MockUserResource)(nil).GetHandleForUpdate), ctx, id)
+}
+
+// GetMutedUsers mocks base method.
+func (m *MockUserResource) GetMutedUsers(ctx context.Context, muterID int32) ([]user.GetMutedUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutedUsers", ctx, muterID)
+	ret0, _ := ret[0].([]user.GetMutedUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMutedUsers indicates an expected call of GetMutedUsers.
+func (mr *MockUserResourceMockRecorder) GetMutedUsers(ctx, muterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutedUsers", reflect.TypeOf((*MockUserResource)(nil).GetMutedUsers), ctx, muterID)
+}
+
+// GetProfile mocks base method.
+func (m *MockUserResource) GetProfile(ctx context.Context, arg user.GetProfileParams) (user.GetProfileRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, arg)
+	ret0, _ := ret[0].(user.GetProfileRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUserResourceMockRecorder) GetProfile(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserResource)(nil).DeleteUser), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUserResource)(nil).GetProfile), ctx, arg)
 }
 
 // GetUser mocks base method.
@@ -97,6 +221,167 @@ func (mr *MockUserResourceMockRecorder) GetUsers(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserResource)(nil).GetUsers), ctx)
 }
 
+// IsAdmin mocks base method.
+func (m *MockUserResource) IsAdmin(ctx context.Context, id int32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdmin", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAdmin indicates an expected call of IsAdmin.
+func (mr *MockUserResourceMockRecorder) IsAdmin(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdmin", reflect.TypeOf((*MockUserResource)(nil).IsAdmin), ctx, id)
+}
+
+// IsHandleReserved mocks base method.
+func (m *MockUserResource) IsHandleReserved(ctx context.Context, handle string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsHandleReserved", ctx, handle)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsHandleReserved indicates an expected call of IsHandleReserved.
+func (mr *MockUserResourceMockRecorder) IsHandleReserved(ctx, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsHandleReserved", reflect.TypeOf((*MockUserResource)(nil).IsHandleReserved), ctx, handle)
+}
+
+// IsOwnImage mocks base method.
+func (m *MockUserResource) IsOwnImage(ctx context.Context, arg user.IsOwnImageParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnImage", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsOwnImage indicates an expected call of IsOwnImage.
+func (mr *MockUserResourceMockRecorder) IsOwnImage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnImage", reflect.TypeOf((*MockUserResource)(nil).IsOwnImage), ctx, arg)
+}
+
+// LockHandle mocks base method.
+func (m *MockUserResource) LockHandle(ctx context.Context, handle string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockHandle", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockHandle indicates an expected call of LockHandle.
+func (mr *MockUserResourceMockRecorder) LockHandle(ctx, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockHandle", reflect.TypeOf((*MockUserResource)(nil).LockHandle), ctx, handle)
+}
+
+// MuteUser mocks base method.
+func (m *MockUserResource) MuteUser(ctx context.Context, arg user.MuteUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MuteUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MuteUser indicates an expected call of MuteUser.
+func (mr *MockUserResourceMockRecorder) MuteUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MuteUser", reflect.TypeOf((*MockUserResource)(nil).MuteUser), ctx, arg)
+}
+
+// PatchUser mocks base method.
+func (m *MockUserResource) PatchUser(ctx context.Context, arg user.PatchUserParams) (user.PatchUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", ctx, arg)
+	ret0, _ := ret[0].(user.PatchUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchUser indicates an expected call of PatchUser.
+func (mr *MockUserResourceMockRecorder) PatchUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockUserResource)(nil).PatchUser), ctx, arg)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserResource) SearchUsers(ctx context.Context, arg user.SearchUsersParams) ([]user.SearchUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, arg)
+	ret0, _ := ret[0].([]user.SearchUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserResourceMockRecorder) SearchUsers(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserResource)(nil).SearchUsers), ctx, arg)
+}
+
+// UnblockUser mocks base method.
+func (m *MockUserResource) UnblockUser(ctx context.Context, arg user.UnblockUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockUserResourceMockRecorder) UnblockUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockUserResource)(nil).UnblockUser), ctx, arg)
+}
+
+// UnfollowUser mocks base method.
+func (m *MockUserResource) UnfollowUser(ctx context.Context, arg user.UnfollowUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowUser indicates an expected call of UnfollowUser.
+func (mr *MockUserResourceMockRecorder) UnfollowUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowUser", reflect.TypeOf((*MockUserResource)(nil).UnfollowUser), ctx, arg)
+}
+
+// UnmuteUser mocks base method.
+func (m *MockUserResource) UnmuteUser(ctx context.Context, arg user.UnmuteUserParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmuteUser", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmuteUser indicates an expected call of UnmuteUser.
+func (mr *MockUserResourceMockRecorder) UnmuteUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmuteUser", reflect.TypeOf((*MockUserResource)(nil).UnmuteUser), ctx, arg)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserResource) UpdateProfile(ctx context.Context, arg user.UpdateProfileParams) (user.UpdateProfileRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, arg)
+	ret0, _ := ret[0].(user.UpdateProfileRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserResourceMockRecorder) UpdateProfile(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserResource)(nil).UpdateProfile), ctx, arg)
+}
+
 // UpdateUser mocks base method.
 func (m *MockUserResource) UpdateUser(ctx context.Context, arg user.UpdateUserParams) (user.UpdateUserRow, error) {
 	m.ctrl.T.Helper()
@@ -135,6 +420,21 @@ func (m *MockPostResource) EXPECT() *MockPostResourceMockRecorder {
 	return m.recorder
 }
 
+// AddReaction mocks base method.
+func (m *MockPostResource) AddReaction(ctx context.Context, arg post.AddReactionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockPostResourceMockRecorder) AddReaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockPostResource)(nil).AddReaction), ctx, arg)
+}
+
 // CreatePost mocks base method.
 func (m *MockPostResource) CreatePost(ctx context.Context, arg post.CreatePostParams) (post.CreatePostRow, error) {
 	m.ctrl.T.Helper()
@@ -150,175 +450,1100 @@ func (mr *MockPostResourceMockRecorder) CreatePost(ctx, arg interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockPostResource)(nil).CreatePost), ctx, arg)
 }
 
-// DeletePost mocks base method.
-func (m *MockPostResource) DeletePost(ctx context.Context, id int32) error {
+// CreatePostRevision mocks base method.
+func (m *MockPostResource) CreatePostRevision(ctx context.Context, arg post.CreatePostRevisionParams) (post.PostRevision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreatePostRevision", ctx, arg)
+	ret0, _ := ret[0].(post.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeletePost indicates an expected call of DeletePost.
-func (mr *MockPostResourceMockRecorder) DeletePost(ctx, id interface{}) *gomock.Call {
+// CreatePostRevision indicates an expected call of CreatePostRevision.
+func (mr *MockPostResourceMockRecorder) CreatePostRevision(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPostResource)(nil).DeletePost), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostRevision", reflect.TypeOf((*MockPostResource)(nil).CreatePostRevision), ctx, arg)
 }
 
-// GetPost mocks base method.
-func (m *MockPostResource) GetPost(ctx context.Context, id int32) (post.GetPostRow, error) {
+// CreateQuote mocks base method.
+func (m *MockPostResource) CreateQuote(ctx context.Context, arg post.CreateQuoteParams) (post.CreateQuoteRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPost", ctx, id)
-	ret0, _ := ret[0].(post.GetPostRow)
+	ret := m.ctrl.Call(m, "CreateQuote", ctx, arg)
+	ret0, _ := ret[0].(post.CreateQuoteRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPost indicates an expected call of GetPost.
-func (mr *MockPostResourceMockRecorder) GetPost(ctx, id interface{}) *gomock.Call {
+// CreateQuote indicates an expected call of CreateQuote.
+func (mr *MockPostResourceMockRecorder) CreateQuote(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockPostResource)(nil).GetPost), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuote", reflect.TypeOf((*MockPostResource)(nil).CreateQuote), ctx, arg)
 }
 
-// GetPosts mocks base method.
-func (m *MockPostResource) GetPosts(ctx context.Context) ([]post.GetPostsRow, error) {
+// CreateRepost mocks base method.
+func (m *MockPostResource) CreateRepost(ctx context.Context, arg post.CreateRepostParams) (post.CreateRepostRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx)
-	ret0, _ := ret[0].([]post.GetPostsRow)
+	ret := m.ctrl.Call(m, "CreateRepost", ctx, arg)
+	ret0, _ := ret[0].(post.CreateRepostRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPosts indicates an expected call of GetPosts.
-func (mr *MockPostResourceMockRecorder) GetPosts(ctx interface{}) *gomock.Call {
+// CreateRepost indicates an expected call of CreateRepost.
+func (mr *MockPostResourceMockRecorder) CreateRepost(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPostResource)(nil).GetPosts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRepost", reflect.TypeOf((*MockPostResource)(nil).CreateRepost), ctx, arg)
 }
 
-// UpdatePost mocks base method.
-func (m *MockPostResource) UpdatePost(ctx context.Context, arg post.UpdatePostParams) (post.UpdatePostRow, error) {
+// DeletePost mocks base method.
+func (m *MockPostResource) DeletePost(ctx context.Context, arg post.DeletePostParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePost", ctx, arg)
-	ret0, _ := ret[0].(post.UpdatePostRow)
+	ret := m.ctrl.Call(m, "DeletePost", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdatePost indicates an expected call of UpdatePost.
-func (mr *MockPostResourceMockRecorder) UpdatePost(ctx, arg interface{}) *gomock.Call {
+// DeletePost indicates an expected call of DeletePost.
+func (mr *MockPostResourceMockRecorder) DeletePost(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockPostResource)(nil).UpdatePost), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPostResource)(nil).DeletePost), ctx, arg)
 }
 
-// MockTagResource is a mock of TagResource interface.
-type MockTagResource struct {
-	ctrl     *gomock.Controller
-	recorder *MockTagResourceMockRecorder
+// DeleteRepost mocks base method.
+func (m *MockPostResource) DeleteRepost(ctx context.Context, arg post.DeleteRepostParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRepost", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockTagResourceMockRecorder is the mock recorder for MockTagResource.
-type MockTagResourceMockRecorder struct {
-	mock *MockTagResource
+// DeleteRepost indicates an expected call of DeleteRepost.
+func (mr *MockPostResourceMockRecorder) DeleteRepost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepost", reflect.TypeOf((*MockPostResource)(nil).DeleteRepost), ctx, arg)
 }
 
-// NewMockTagResource creates a new mock instance.
-func NewMockTagResource(ctrl *gomock.Controller) *MockTagResource {
-	mock := &MockTagResource{ctrl: ctrl}
-	mock.recorder = &MockTagResourceMockRecorder{mock}
-	return mock
+// GetBookmarks mocks base method.
+func (m *MockPostResource) GetBookmarks(ctx context.Context, arg post.GetBookmarksParams) ([]post.GetBookmarksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarks", ctx, arg)
+	ret0, _ := ret[0].([]post.GetBookmarksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTagResource) EXPECT() *MockTagResourceMockRecorder {
-	return m.recorder
+// GetBookmarks indicates an expected call of GetBookmarks.
+func (mr *MockPostResourceMockRecorder) GetBookmarks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarks", reflect.TypeOf((*MockPostResource)(nil).GetBookmarks), ctx, arg)
 }
 
-// CreateTag mocks base method.
-func (m *MockTagResource) CreateTag(ctx context.Context, tagname string) (tag.CreateTagRow, error) {
+// GetPost mocks base method.
+func (m *MockPostResource) GetPost(ctx context.Context, id int32) (post.GetPostRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTag", ctx, tagname)
-	ret0, _ := ret[0].(tag.CreateTagRow)
+	ret := m.ctrl.Call(m, "GetPost", ctx, id)
+	ret0, _ := ret[0].(post.GetPostRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTag indicates an expected call of CreateTag.
-func (mr *MockTagResourceMockRecorder) CreateTag(ctx, tagname interface{}) *gomock.Call {
+// GetPost indicates an expected call of GetPost.
+func (mr *MockPostResourceMockRecorder) GetPost(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockTagResource)(nil).CreateTag), ctx, tagname)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockPostResource)(nil).GetPost), ctx, id)
 }
 
-// DeleteTag mocks base method.
-func (m *MockTagResource) DeleteTag(ctx context.Context, id int32) error {
+// GetPostRevision mocks base method.
+func (m *MockPostResource) GetPostRevision(ctx context.Context, arg post.GetPostRevisionParams) (post.PostRevision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetPostRevision", ctx, arg)
+	ret0, _ := ret[0].(post.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteTag indicates an expected call of DeleteTag.
-func (mr *MockTagResourceMockRecorder) DeleteTag(ctx, id interface{}) *gomock.Call {
+// GetPostRevision indicates an expected call of GetPostRevision.
+func (mr *MockPostResourceMockRecorder) GetPostRevision(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagResource)(nil).DeleteTag), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevision", reflect.TypeOf((*MockPostResource)(nil).GetPostRevision), ctx, arg)
 }
 
-// GetTag mocks base method.
-func (m *MockTagResource) GetTag(ctx context.Context, id int32) (tag.GetTagRow, error) {
+// GetPostRevisions mocks base method.
+func (m *MockPostResource) GetPostRevisions(ctx context.Context, arg post.GetPostRevisionsParams) ([]post.PostRevision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTag", ctx, id)
-	ret0, _ := ret[0].(tag.GetTagRow)
+	ret := m.ctrl.Call(m, "GetPostRevisions", ctx, arg)
+	ret0, _ := ret[0].([]post.PostRevision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTag indicates an expected call of GetTag.
-func (mr *MockTagResourceMockRecorder) GetTag(ctx, id interface{}) *gomock.Call {
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockPostResourceMockRecorder) GetPostRevisions(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockTagResource)(nil).GetTag), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockPostResource)(nil).GetPostRevisions), ctx, arg)
 }
 
-// GetTagByPostID mocks base method.
-func (m *MockTagResource) GetTagByPostID(ctx context.Context, postid int32) ([]tag.GetTagByPostIDRow, error) {
+// GetPosts mocks base method.
+func (m *MockPostResource) GetPosts(ctx context.Context, arg post.GetPostsParams) ([]post.GetPostsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTagByPostID", ctx, postid)
-	ret0, _ := ret[0].([]tag.GetTagByPostIDRow)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, arg)
+	ret0, _ := ret[0].([]post.GetPostsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTagByPostID indicates an expected call of GetTagByPostID.
-func (mr *MockTagResourceMockRecorder) GetTagByPostID(ctx, postid interface{}) *gomock.Call {
+// GetPosts indicates an expected call of GetPosts.
+func (mr *MockPostResourceMockRecorder) GetPosts(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByPostID", reflect.TypeOf((*MockTagResource)(nil).GetTagByPostID), ctx, postid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPostResource)(nil).GetPosts), ctx, arg)
 }
 
-// GetTags mocks base method.
-func (m *MockTagResource) GetTags(ctx context.Context) ([]tag.GetTagsRow, error) {
+// GetPostsByIDs mocks base method.
+func (m *MockPostResource) GetPostsByIDs(ctx context.Context, arg post.GetPostsByIDsParams) ([]post.GetPostsByIDsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTags", ctx)
-	ret0, _ := ret[0].([]tag.GetTagsRow)
+	ret := m.ctrl.Call(m, "GetPostsByIDs", ctx, arg)
+	ret0, _ := ret[0].([]post.GetPostsByIDsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTags indicates an expected call of GetTags.
-func (mr *MockTagResourceMockRecorder) GetTags(ctx interface{}) *gomock.Call {
+// GetPostsByIDs indicates an expected call of GetPostsByIDs.
+func (mr *MockPostResourceMockRecorder) GetPostsByIDs(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTagResource)(nil).GetTags), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByIDs", reflect.TypeOf((*MockPostResource)(nil).GetPostsByIDs), ctx, arg)
+}
+
+// GetReplyChain mocks base method.
+func (m *MockPostResource) GetReplyChain(ctx context.Context, arg post.GetReplyChainParams) ([]post.GetReplyChainRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplyChain", ctx, arg)
+	ret0, _ := ret[0].([]post.GetReplyChainRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplyChain indicates an expected call of GetReplyChain.
+func (mr *MockPostResourceMockRecorder) GetReplyChain(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplyChain", reflect.TypeOf((*MockPostResource)(nil).GetReplyChain), ctx, arg)
+}
+
+// GetReplyTree mocks base method.
+func (m *MockPostResource) GetReplyTree(ctx context.Context, arg post.GetReplyTreeParams) ([]post.GetReplyTreeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplyTree", ctx, arg)
+	ret0, _ := ret[0].([]post.GetReplyTreeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplyTree indicates an expected call of GetReplyTree.
+func (mr *MockPostResourceMockRecorder) GetReplyTree(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplyTree", reflect.TypeOf((*MockPostResource)(nil).GetReplyTree), ctx, arg)
+}
+
+// GetRepostTarget mocks base method.
+func (m *MockPostResource) GetRepostTarget(ctx context.Context, id int32) (post.GetRepostTargetRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepostTarget", ctx, id)
+	ret0, _ := ret[0].(post.GetRepostTargetRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRepostTarget indicates an expected call of GetRepostTarget.
+func (mr *MockPostResourceMockRecorder) GetRepostTarget(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepostTarget", reflect.TypeOf((*MockPostResource)(nil).GetRepostTarget), ctx, id)
+}
+
+// GetTagFeed mocks base method.
+func (m *MockPostResource) GetTagFeed(ctx context.Context, arg post.GetTagFeedParams) ([]post.GetTagFeedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagFeed", ctx, arg)
+	ret0, _ := ret[0].([]post.GetTagFeedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagFeed indicates an expected call of GetTagFeed.
+func (mr *MockPostResourceMockRecorder) GetTagFeed(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagFeed", reflect.TypeOf((*MockPostResource)(nil).GetTagFeed), ctx, arg)
+}
+
+// GetVisiblePost mocks base method.
+func (m *MockPostResource) GetVisiblePost(ctx context.Context, arg post.GetVisiblePostParams) (post.GetVisiblePostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVisiblePost", ctx, arg)
+	ret0, _ := ret[0].(post.GetVisiblePostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVisiblePost indicates an expected call of GetVisiblePost.
+func (mr *MockPostResourceMockRecorder) GetVisiblePost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVisiblePost", reflect.TypeOf((*MockPostResource)(nil).GetVisiblePost), ctx, arg)
+}
+
+// PatchPost mocks base method.
+func (m *MockPostResource) PatchPost(ctx context.Context, arg post.PatchPostParams) (post.PatchPostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPost", ctx, arg)
+	ret0, _ := ret[0].(post.PatchPostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchPost indicates an expected call of PatchPost.
+func (mr *MockPostResourceMockRecorder) PatchPost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPost", reflect.TypeOf((*MockPostResource)(nil).PatchPost), ctx, arg)
+}
+
+// PublishDuePosts mocks base method.
+func (m *MockPostResource) PublishDuePosts(ctx context.Context, arg post.PublishDuePostsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDuePosts", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDuePosts indicates an expected call of PublishDuePosts.
+func (mr *MockPostResourceMockRecorder) PublishDuePosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDuePosts", reflect.TypeOf((*MockPostResource)(nil).PublishDuePosts), ctx, arg)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockPostResource) RemoveReaction(ctx context.Context, arg post.RemoveReactionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockPostResourceMockRecorder) RemoveReaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockPostResource)(nil).RemoveReaction), ctx, arg)
+}
+
+// SearchPosts mocks base method.
+func (m *MockPostResource) SearchPosts(ctx context.Context, arg post.SearchPostsParams) ([]post.SearchPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, arg)
+	ret0, _ := ret[0].([]post.SearchPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockPostResourceMockRecorder) SearchPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockPostResource)(nil).SearchPosts), ctx, arg)
+}
+
+// UpdatePost mocks base method.
+func (m *MockPostResource) UpdatePost(ctx context.Context, arg post.UpdatePostParams) (post.UpdatePostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePost", ctx, arg)
+	ret0, _ := ret[0].(post.UpdatePostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePost indicates an expected call of UpdatePost.
+func (mr *MockPostResourceMockRecorder) UpdatePost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockPostResource)(nil).UpdatePost), ctx, arg)
+}
+
+// MockTagResource is a mock of TagResource interface.
+type MockTagResource struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagResourceMockRecorder
+}
+
+// MockTagResourceMockRecorder is the mock recorder for MockTagResource.
+type MockTagResourceMockRecorder struct {
+	mock *MockTagResource
+}
+
+// NewMockTagResource creates a new mock instance.
+func NewMockTagResource(ctrl *gomock.Controller) *MockTagResource {
+	mock := &MockTagResource{ctrl: ctrl}
+	mock.recorder = &MockTagResourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagResource) EXPECT() *MockTagResourceMockRecorder {
+	return m.recorder
+}
+
+// CreateTag mocks base method.
+func (m *MockTagResource) CreateTag(ctx context.Context, tagname string) (tag.CreateTagRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, tagname)
+	ret0, _ := ret[0].(tag.CreateTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockTagResourceMockRecorder) CreateTag(ctx, tagname interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockTagResource)(nil).CreateTag), ctx, tagname)
+}
+
+// DeleteStaleTagTrends mocks base method.
+func (m *MockTagResource) DeleteStaleTagTrends(ctx context.Context, arg tag.DeleteStaleTagTrendsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleTagTrends", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleTagTrends indicates an expected call of DeleteStaleTagTrends.
+func (mr *MockTagResourceMockRecorder) DeleteStaleTagTrends(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleTagTrends", reflect.TypeOf((*MockTagResource)(nil).DeleteStaleTagTrends), ctx, arg)
+}
+
+// DeleteTag mocks base method.
+func (m *MockTagResource) DeleteTag(ctx context.Context, arg tag.DeleteTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockTagResourceMockRecorder) DeleteTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagResource)(nil).DeleteTag), ctx, arg)
+}
+
+// FollowTag mocks base method.
+func (m *MockTagResource) FollowTag(ctx context.Context, arg tag.FollowTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowTag indicates an expected call of FollowTag.
+func (mr *MockTagResourceMockRecorder) FollowTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowTag", reflect.TypeOf((*MockTagResource)(nil).FollowTag), ctx, arg)
+}
+
+// GetFollowedTags mocks base method.
+func (m *MockTagResource) GetFollowedTags(ctx context.Context, userID int32) ([]tag.GetFollowedTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowedTags", ctx, userID)
+	ret0, _ := ret[0].([]tag.GetFollowedTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowedTags indicates an expected call of GetFollowedTags.
+func (mr *MockTagResourceMockRecorder) GetFollowedTags(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowedTags", reflect.TypeOf((*MockTagResource)(nil).GetFollowedTags), ctx, userID)
+}
+
+// GetTag mocks base method.
+func (m *MockTagResource) GetTag(ctx context.Context, id int32) (tag.GetTagRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, id)
+	ret0, _ := ret[0].(tag.GetTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockTagResourceMockRecorder) GetTag(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockTagResource)(nil).GetTag), ctx, id)
+}
+
+// GetTagByPostID mocks base method.
+func (m *MockTagResource) GetTagByPostID(ctx context.Context, postid int32) ([]tag.GetTagByPostIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByPostID", ctx, postid)
+	ret0, _ := ret[0].([]tag.GetTagByPostIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByPostID indicates an expected call of GetTagByPostID.
+func (mr *MockTagResourceMockRecorder) GetTagByPostID(ctx, postid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByPostID", reflect.TypeOf((*MockTagResource)(nil).GetTagByPostID), ctx, postid)
+}
+
+// GetTagUsageCounts mocks base method.
+func (m *MockTagResource) GetTagUsageCounts(ctx context.Context, arg tag.GetTagUsageCountsParams) ([]tag.GetTagUsageCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagUsageCounts", ctx, arg)
+	ret0, _ := ret[0].([]tag.GetTagUsageCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagUsageCounts indicates an expected call of GetTagUsageCounts.
+func (mr *MockTagResourceMockRecorder) GetTagUsageCounts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagUsageCounts", reflect.TypeOf((*MockTagResource)(nil).GetTagUsageCounts), ctx, arg)
+}
+
+// GetTags mocks base method.
+func (m *MockTagResource) GetTags(ctx context.Context) ([]tag.GetTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx)
+	ret0, _ := ret[0].([]tag.GetTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockTagResourceMockRecorder) GetTags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTagResource)(nil).GetTags), ctx)
+}
+
+// GetTagsByPostIDs mocks base method.
+func (m *MockTagResource) GetTagsByPostIDs(ctx context.Context, postids []int32) ([]tag.GetTagsByPostIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsByPostIDs", ctx, postids)
+	ret0, _ := ret[0].([]tag.GetTagsByPostIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsByPostIDs indicates an expected call of GetTagsByPostIDs.
+func (mr *MockTagResourceMockRecorder) GetTagsByPostIDs(ctx, postids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByPostIDs", reflect.TypeOf((*MockTagResource)(nil).GetTagsByPostIDs), ctx, postids)
+}
+
+// GetTrendingTags mocks base method.
+func (m *MockTagResource) GetTrendingTags(ctx context.Context, arg tag.GetTrendingTagsParams) ([]tag.GetTrendingTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrendingTags", ctx, arg)
+	ret0, _ := ret[0].([]tag.GetTrendingTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrendingTags indicates an expected call of GetTrendingTags.
+func (mr *MockTagResourceMockRecorder) GetTrendingTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrendingTags", reflect.TypeOf((*MockTagResource)(nil).GetTrendingTags), ctx, arg)
+}
+
+// PatchTag mocks base method.
+func (m *MockTagResource) PatchTag(ctx context.Context, arg tag.PatchTagParams) (tag.PatchTagRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTag", ctx, arg)
+	ret0, _ := ret[0].(tag.PatchTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTag indicates an expected call of PatchTag.
+func (mr *MockTagResourceMockRecorder) PatchTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTag", reflect.TypeOf((*MockTagResource)(nil).PatchTag), ctx, arg)
+}
+
+// SearchTags mocks base method.
+func (m *MockTagResource) SearchTags(ctx context.Context, arg tag.SearchTagsParams) ([]tag.SearchTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTags", ctx, arg)
+	ret0, _ := ret[0].([]tag.SearchTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTags indicates an expected call of SearchTags.
+func (mr *MockTagResourceMockRecorder) SearchTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTags", reflect.TypeOf((*MockTagResource)(nil).SearchTags), ctx, arg)
+}
+
+// UnfollowTag mocks base method.
+func (m *MockTagResource) UnfollowTag(ctx context.Context, arg tag.UnfollowTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowTag indicates an expected call of UnfollowTag.
+func (mr *MockTagResourceMockRecorder) UnfollowTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowTag", reflect.TypeOf((*MockTagResource)(nil).UnfollowTag), ctx, arg)
 }
 
 // UpdateTag mocks base method.
 func (m *MockTagResource) UpdateTag(ctx context.Context, arg tag.UpdateTagParams) (tag.UpdateTagRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTag", ctx, arg)
-	ret0, _ := ret[0].(tag.UpdateTagRow)
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, arg)
+	ret0, _ := ret[0].(tag.UpdateTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockTagResourceMockRecorder) UpdateTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockTagResource)(nil).UpdateTag), ctx, arg)
+}
+
+// UpsertTagTrends mocks base method.
+func (m *MockTagResource) UpsertTagTrends(ctx context.Context, arg tag.UpsertTagTrendsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTagTrends", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTagTrends indicates an expected call of UpsertTagTrends.
+func (mr *MockTagResourceMockRecorder) UpsertTagTrends(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTagTrends", reflect.TypeOf((*MockTagResource)(nil).UpsertTagTrends), ctx, arg)
+}
+
+// MockMediaResource is a mock of MediaResource interface.
+type MockMediaResource struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaResourceMockRecorder
+}
+
+// MockMediaResourceMockRecorder is the mock recorder for MockMediaResource.
+type MockMediaResourceMockRecorder struct {
+	mock *MockMediaResource
+}
+
+// NewMockMediaResource creates a new mock instance.
+func NewMockMediaResource(ctrl *gomock.Controller) *MockMediaResource {
+	mock := &MockMediaResource{ctrl: ctrl}
+	mock.recorder = &MockMediaResourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaResource) EXPECT() *MockMediaResourceMockRecorder {
+	return m.recorder
+}
+
+// AttachPostMedia mocks base method.
+func (m *MockMediaResource) AttachPostMedia(ctx context.Context, arg media.AttachPostMediaParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachPostMedia", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachPostMedia indicates an expected call of AttachPostMedia.
+func (mr *MockMediaResourceMockRecorder) AttachPostMedia(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPostMedia", reflect.TypeOf((*MockMediaResource)(nil).AttachPostMedia), ctx, arg)
+}
+
+// CreateMedia mocks base method.
+func (m *MockMediaResource) CreateMedia(ctx context.Context, arg media.CreateMediaParams) (media.Medium, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMedia", ctx, arg)
+	ret0, _ := ret[0].(media.Medium)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMedia indicates an expected call of CreateMedia.
+func (mr *MockMediaResourceMockRecorder) CreateMedia(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMedia", reflect.TypeOf((*MockMediaResource)(nil).CreateMedia), ctx, arg)
+}
+
+// GetPostMedia mocks base method.
+func (m *MockMediaResource) GetPostMedia(ctx context.Context, postID int32) ([]media.GetPostMediaRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostMedia", ctx, postID)
+	ret0, _ := ret[0].([]media.GetPostMediaRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostMedia indicates an expected call of GetPostMedia.
+func (mr *MockMediaResourceMockRecorder) GetPostMedia(ctx, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostMedia", reflect.TypeOf((*MockMediaResource)(nil).GetPostMedia), ctx, postID)
+}
+
+// GetVisibleMedia mocks base method.
+func (m *MockMediaResource) GetVisibleMedia(ctx context.Context, arg media.GetVisibleMediaParams) (media.Medium, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVisibleMedia", ctx, arg)
+	ret0, _ := ret[0].(media.Medium)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVisibleMedia indicates an expected call of GetVisibleMedia.
+func (mr *MockMediaResourceMockRecorder) GetVisibleMedia(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVisibleMedia", reflect.TypeOf((*MockMediaResource)(nil).GetVisibleMedia), ctx, arg)
+}
+
+// MockNotificationResource is a mock of NotificationResource interface.
+type MockNotificationResource struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationResourceMockRecorder
+}
+
+// MockNotificationResourceMockRecorder is the mock recorder for MockNotificationResource.
+type MockNotificationResourceMockRecorder struct {
+	mock *MockNotificationResource
+}
+
+// NewMockNotificationResource creates a new mock instance.
+func NewMockNotificationResource(ctrl *gomock.Controller) *MockNotificationResource {
+	mock := &MockNotificationResource{ctrl: ctrl}
+	mock.recorder = &MockNotificationResourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationResource) EXPECT() *MockNotificationResourceMockRecorder {
+	return m.recorder
+}
+
+// AddNotificationActor mocks base method.
+func (m *MockNotificationResource) AddNotificationActor(ctx context.Context, arg notification.AddNotificationActorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotificationActor", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNotificationActor indicates an expected call of AddNotificationActor.
+func (mr *MockNotificationResourceMockRecorder) AddNotificationActor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotificationActor", reflect.TypeOf((*MockNotificationResource)(nil).AddNotificationActor), ctx, arg)
+}
+
+// ClaimNotificationEvents mocks base method.
+func (m *MockNotificationResource) ClaimNotificationEvents(ctx context.Context, limit int32) ([]notification.NotificationEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNotificationEvents", ctx, limit)
+	ret0, _ := ret[0].([]notification.NotificationEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNotificationEvents indicates an expected call of ClaimNotificationEvents.
+func (mr *MockNotificationResourceMockRecorder) ClaimNotificationEvents(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNotificationEvents", reflect.TypeOf((*MockNotificationResource)(nil).ClaimNotificationEvents), ctx, limit)
+}
+
+// DeleteNotificationEvents mocks base method.
+func (m *MockNotificationResource) DeleteNotificationEvents(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationEvents", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationEvents indicates an expected call of DeleteNotificationEvents.
+func (mr *MockNotificationResourceMockRecorder) DeleteNotificationEvents(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationEvents", reflect.TypeOf((*MockNotificationResource)(nil).DeleteNotificationEvents), ctx, ids)
+}
+
+// GetNotificationActors mocks base method.
+func (m *MockNotificationResource) GetNotificationActors(ctx context.Context, arg notification.GetNotificationActorsParams) ([]notification.GetNotificationActorsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationActors", ctx, arg)
+	ret0, _ := ret[0].([]notification.GetNotificationActorsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationActors indicates an expected call of GetNotificationActors.
+func (mr *MockNotificationResourceMockRecorder) GetNotificationActors(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationActors", reflect.TypeOf((*MockNotificationResource)(nil).GetNotificationActors), ctx, arg)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockNotificationResource) GetNotificationPreferences(ctx context.Context, userID int32) ([]notification.GetNotificationPreferencesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", ctx, userID)
+	ret0, _ := ret[0].([]notification.GetNotificationPreferencesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockNotificationResourceMockRecorder) GetNotificationPreferences(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockNotificationResource)(nil).GetNotificationPreferences), ctx, userID)
+}
+
+// GetNotifications mocks base method.
+func (m *MockNotificationResource) GetNotifications(ctx context.Context, arg notification.GetNotificationsParams) ([]notification.GetNotificationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, arg)
+	ret0, _ := ret[0].([]notification.GetNotificationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockNotificationResourceMockRecorder) GetNotifications(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockNotificationResource)(nil).GetNotifications), ctx, arg)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockNotificationResource) MarkAllNotificationsRead(ctx context.Context, recipientID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, recipientID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockNotificationResourceMockRecorder) MarkAllNotificationsRead(ctx, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockNotificationResource)(nil).MarkAllNotificationsRead), ctx, recipientID)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockNotificationResource) MarkNotificationRead(ctx context.Context, arg notification.MarkNotificationReadParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockNotificationResourceMockRecorder) MarkNotificationRead(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockNotificationResource)(nil).MarkNotificationRead), ctx, arg)
+}
+
+// SetNotificationPreference mocks base method.
+func (m *MockNotificationResource) SetNotificationPreference(ctx context.Context, arg notification.SetNotificationPreferenceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationPreference", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationPreference indicates an expected call of SetNotificationPreference.
+func (mr *MockNotificationResourceMockRecorder) SetNotificationPreference(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationPreference", reflect.TypeOf((*MockNotificationResource)(nil).SetNotificationPreference), ctx, arg)
+}
+
+// UpsertNotification mocks base method.
+func (m *MockNotificationResource) UpsertNotification(ctx context.Context, arg notification.UpsertNotificationParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotification", ctx, arg)
+	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateTag indicates an expected call of UpdateTag.
-func (mr *MockTagResourceMockRecorder) UpdateTag(ctx, arg interface{}) *gomock.Call {
+// UpsertNotification indicates an expected call of UpsertNotification.
+func (mr *MockNotificationResourceMockRecorder) UpsertNotification(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockTagResource)(nil).UpdateTag), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotification", reflect.TypeOf((*MockNotificationResource)(nil).UpsertNotification), ctx, arg)
+}
+
+// MockMessageResource is a mock of MessageResource interface.
+type MockMessageResource struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageResourceMockRecorder
+}
+
+// MockMessageResourceMockRecorder is the mock recorder for MockMessageResource.
+type MockMessageResourceMockRecorder struct {
+	mock *MockMessageResource
+}
+
+// NewMockMessageResource creates a new mock instance.
+func NewMockMessageResource(ctrl *gomock.Controller) *MockMessageResource {
+	mock := &MockMessageResource{ctrl: ctrl}
+	mock.recorder = &MockMessageResourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageResource) EXPECT() *MockMessageResourceMockRecorder {
+	return m.recorder
+}
+
+// AddConversationParticipants mocks base method.
+func (m *MockMessageResource) AddConversationParticipants(ctx context.Context, arg message.AddConversationParticipantsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddConversationParticipants", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddConversationParticipants indicates an expected call of AddConversationParticipants.
+func (mr *MockMessageResourceMockRecorder) AddConversationParticipants(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConversationParticipants", reflect.TypeOf((*MockMessageResource)(nil).AddConversationParticipants), ctx, arg)
+}
+
+// CreateConversation mocks base method.
+func (m *MockMessageResource) CreateConversation(ctx context.Context, arg message.CreateConversationParams) (message.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConversation", ctx, arg)
+	ret0, _ := ret[0].(message.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConversation indicates an expected call of CreateConversation.
+func (mr *MockMessageResourceMockRecorder) CreateConversation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConversation", reflect.TypeOf((*MockMessageResource)(nil).CreateConversation), ctx, arg)
+}
+
+// GetConversation mocks base method.
+func (m *MockMessageResource) GetConversation(ctx context.Context, arg message.GetConversationParams) (message.GetConversationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversation", ctx, arg)
+	ret0, _ := ret[0].(message.GetConversationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversation indicates an expected call of GetConversation.
+func (mr *MockMessageResourceMockRecorder) GetConversation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversation", reflect.TypeOf((*MockMessageResource)(nil).GetConversation), ctx, arg)
+}
+
+// GetConversationParticipants mocks base method.
+func (m *MockMessageResource) GetConversationParticipants(ctx context.Context, conversationIds []int32) ([]message.GetConversationParticipantsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversationParticipants", ctx, conversationIds)
+	ret0, _ := ret[0].([]message.GetConversationParticipantsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversationParticipants indicates an expected call of GetConversationParticipants.
+func (mr *MockMessageResourceMockRecorder) GetConversationParticipants(ctx, conversationIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversationParticipants", reflect.TypeOf((*MockMessageResource)(nil).GetConversationParticipants), ctx, conversationIds)
+}
+
+// GetConversations mocks base method.
+func (m *MockMessageResource) GetConversations(ctx context.Context, arg message.GetConversationsParams) ([]message.GetConversationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversations", ctx, arg)
+	ret0, _ := ret[0].([]message.GetConversationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversations indicates an expected call of GetConversations.
+func (mr *MockMessageResourceMockRecorder) GetConversations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversations", reflect.TypeOf((*MockMessageResource)(nil).GetConversations), ctx, arg)
+}
+
+// GetLastMessages mocks base method.
+func (m *MockMessageResource) GetLastMessages(ctx context.Context, arg message.GetLastMessagesParams) ([]message.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastMessages", ctx, arg)
+	ret0, _ := ret[0].([]message.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastMessages indicates an expected call of GetLastMessages.
+func (mr *MockMessageResourceMockRecorder) GetLastMessages(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastMessages", reflect.TypeOf((*MockMessageResource)(nil).GetLastMessages), ctx, arg)
+}
+
+// GetMessage mocks base method.
+func (m *MockMessageResource) GetMessage(ctx context.Context, arg message.GetMessageParams) (message.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, arg)
+	ret0, _ := ret[0].(message.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockMessageResourceMockRecorder) GetMessage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockMessageResource)(nil).GetMessage), ctx, arg)
+}
+
+// GetMessages mocks base method.
+func (m *MockMessageResource) GetMessages(ctx context.Context, arg message.GetMessagesParams) ([]message.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", ctx, arg)
+	ret0, _ := ret[0].([]message.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockMessageResourceMockRecorder) GetMessages(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockMessageResource)(nil).GetMessages), ctx, arg)
+}
+
+// MarkConversationRead mocks base method.
+func (m *MockMessageResource) MarkConversationRead(ctx context.Context, arg message.MarkConversationReadParams) (message.MarkConversationReadRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkConversationRead", ctx, arg)
+	ret0, _ := ret[0].(message.MarkConversationReadRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkConversationRead indicates an expected call of MarkConversationRead.
+func (mr *MockMessageResourceMockRecorder) MarkConversationRead(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkConversationRead", reflect.TypeOf((*MockMessageResource)(nil).MarkConversationRead), ctx, arg)
+}
+
+// SendMessage mocks base method.
+func (m *MockMessageResource) SendMessage(ctx context.Context, arg message.SendMessageParams) (message.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", ctx, arg)
+	ret0, _ := ret[0].(message.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockMessageResourceMockRecorder) SendMessage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessageResource)(nil).SendMessage), ctx, arg)
+}
+
+// MockStreamResource is a mock of StreamResource interface.
+type MockStreamResource struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamResourceMockRecorder
+}
+
+// MockStreamResourceMockRecorder is the mock recorder for MockStreamResource.
+type MockStreamResourceMockRecorder struct {
+	mock *MockStreamResource
+}
+
+// NewMockStreamResource creates a new mock instance.
+func NewMockStreamResource(ctrl *gomock.Controller) *MockStreamResource {
+	mock := &MockStreamResource{ctrl: ctrl}
+	mock.recorder = &MockStreamResourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamResource) EXPECT() *MockStreamResourceMockRecorder {
+	return m.recorder
+}
+
+// GetStreamEvents mocks base method.
+func (m *MockStreamResource) GetStreamEvents(ctx context.Context, ids []int64) ([]stream.StreamEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamEvents", ctx, ids)
+	ret0, _ := ret[0].([]stream.StreamEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamEvents indicates an expected call of GetStreamEvents.
+func (mr *MockStreamResourceMockRecorder) GetStreamEvents(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamEvents", reflect.TypeOf((*MockStreamResource)(nil).GetStreamEvents), ctx, ids)
+}
+
+// GetStreamEventsAfter mocks base method.
+func (m *MockStreamResource) GetStreamEventsAfter(ctx context.Context, arg stream.GetStreamEventsAfterParams) ([]stream.StreamEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamEventsAfter", ctx, arg)
+	ret0, _ := ret[0].([]stream.StreamEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamEventsAfter indicates an expected call of GetStreamEventsAfter.
+func (mr *MockStreamResourceMockRecorder) GetStreamEventsAfter(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamEventsAfter", reflect.TypeOf((*MockStreamResource)(nil).GetStreamEventsAfter), ctx, arg)
+}
+
+// GetStreamRecipients mocks base method.
+func (m *MockStreamResource) GetStreamRecipients(ctx context.Context, arg stream.GetStreamRecipientsParams) ([]stream.GetStreamRecipientsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamRecipients", ctx, arg)
+	ret0, _ := ret[0].([]stream.GetStreamRecipientsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamRecipients indicates an expected call of GetStreamRecipients.
+func (mr *MockStreamResourceMockRecorder) GetStreamRecipients(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamRecipients", reflect.TypeOf((*MockStreamResource)(nil).GetStreamRecipients), ctx, arg)
+}
+
+// PurgeStreamEvents mocks base method.
+func (m *MockStreamResource) PurgeStreamEvents(ctx context.Context, retentionMinutes int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeStreamEvents", ctx, retentionMinutes)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeStreamEvents indicates an expected call of PurgeStreamEvents.
+func (mr *MockStreamResourceMockRecorder) PurgeStreamEvents(ctx, retentionMinutes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeStreamEvents", reflect.TypeOf((*MockStreamResource)(nil).PurgeStreamEvents), ctx, retentionMinutes)
+}
+
+// StreamUserExists mocks base method.
+func (m *MockStreamResource) StreamUserExists(ctx context.Context, id int32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamUserExists", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamUserExists indicates an expected call of StreamUserExists.
+func (mr *MockStreamResourceMockRecorder) StreamUserExists(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamUserExists", reflect.TypeOf((*MockStreamResource)(nil).StreamUserExists), ctx, id)
 }
 
 // MockPostTagResource is a mock of PostTagResource interface.
@@ -359,16 +1584,195 @@ func (mr *MockPostTagResourceMockRecorder) CreatePostTag(ctx, arg interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostTag", reflect.TypeOf((*MockPostTagResource)(nil).CreatePostTag), ctx, arg)
 }
 
-// DeletePostTag mocks base method.
-func (m *MockPostTagResource) DeletePostTag(ctx context.Context, postid int32) error {
+// DeletePostTagsByTagIDs mocks base method.
+func (m *MockPostTagResource) DeletePostTagsByTagIDs(ctx context.Context, arg post_tags.DeletePostTagsByTagIDsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostTagsByTagIDs", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostTagsByTagIDs indicates an expected call of DeletePostTagsByTagIDs.
+func (mr *MockPostTagResourceMockRecorder) DeletePostTagsByTagIDs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostTagsByTagIDs", reflect.TypeOf((*MockPostTagResource)(nil).DeletePostTagsByTagIDs), ctx, arg)
+}
+
+// GetPostTagIDs mocks base method.
+func (m *MockPostTagResource) GetPostTagIDs(ctx context.Context, postid int32) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostTagIDs", ctx, postid)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostTagIDs indicates an expected call of GetPostTagIDs.
+func (mr *MockPostTagResourceMockRecorder) GetPostTagIDs(ctx, postid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostTagIDs", reflect.TypeOf((*MockPostTagResource)(nil).GetPostTagIDs), ctx, postid)
+}
+
+// MockBookmarkResource is a mock of BookmarkResource interface.
+type MockBookmarkResource struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookmarkResourceMockRecorder
+}
+
+// MockBookmarkResourceMockRecorder is the mock recorder for MockBookmarkResource.
+type MockBookmarkResourceMockRecorder struct {
+	mock *MockBookmarkResource
+}
+
+// NewMockBookmarkResource creates a new mock instance.
+func NewMockBookmarkResource(ctrl *gomock.Controller) *MockBookmarkResource {
+	mock := &MockBookmarkResource{ctrl: ctrl}
+	mock.recorder = &MockBookmarkResourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookmarkResource) EXPECT() *MockBookmarkResourceMockRecorder {
+	return m.recorder
+}
+
+// AddBookmark mocks base method.
+func (m *MockBookmarkResource) AddBookmark(ctx context.Context, arg bookmark.AddBookmarkParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookmark", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBookmark indicates an expected call of AddBookmark.
+func (mr *MockBookmarkResourceMockRecorder) AddBookmark(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookmark", reflect.TypeOf((*MockBookmarkResource)(nil).AddBookmark), ctx, arg)
+}
+
+// CreateCollection mocks base method.
+func (m *MockBookmarkResource) CreateCollection(ctx context.Context, arg bookmark.CreateCollectionParams) (bookmark.CreateCollectionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", ctx, arg)
+	ret0, _ := ret[0].(bookmark.CreateCollectionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockBookmarkResourceMockRecorder) CreateCollection(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockBookmarkResource)(nil).CreateCollection), ctx, arg)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockBookmarkResource) DeleteCollection(ctx context.Context, arg bookmark.DeleteCollectionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockBookmarkResourceMockRecorder) DeleteCollection(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockBookmarkResource)(nil).DeleteCollection), ctx, arg)
+}
+
+// GetBookmarkedPostIDs mocks base method.
+func (m *MockBookmarkResource) GetBookmarkedPostIDs(ctx context.Context, arg bookmark.GetBookmarkedPostIDsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarkedPostIDs", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookmarkedPostIDs indicates an expected call of GetBookmarkedPostIDs.
+func (mr *MockBookmarkResourceMockRecorder) GetBookmarkedPostIDs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarkedPostIDs", reflect.TypeOf((*MockBookmarkResource)(nil).GetBookmarkedPostIDs), ctx, arg)
+}
+
+// GetCollections mocks base method.
+func (m *MockBookmarkResource) GetCollections(ctx context.Context, userID int32) ([]bookmark.GetCollectionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", ctx, userID)
+	ret0, _ := ret[0].([]bookmark.GetCollectionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockBookmarkResourceMockRecorder) GetCollections(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockBookmarkResource)(nil).GetCollections), ctx, userID)
+}
+
+// RemoveBookmark mocks base method.
+func (m *MockBookmarkResource) RemoveBookmark(ctx context.Context, arg bookmark.RemoveBookmarkParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBookmark", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBookmark indicates an expected call of RemoveBookmark.
+func (mr *MockBookmarkResourceMockRecorder) RemoveBookmark(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBookmark", reflect.TypeOf((*MockBookmarkResource)(nil).RemoveBookmark), ctx, arg)
+}
+
+// RenameCollection mocks base method.
+func (m *MockBookmarkResource) RenameCollection(ctx context.Context, arg bookmark.RenameCollectionParams) (bookmark.RenameCollectionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCollection", ctx, arg)
+	ret0, _ := ret[0].(bookmark.RenameCollectionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCollection indicates an expected call of RenameCollection.
+func (mr *MockBookmarkResourceMockRecorder) RenameCollection(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCollection", reflect.TypeOf((*MockBookmarkResource)(nil).RenameCollection), ctx, arg)
+}
+
+// MockTxRunner is a mock of TxRunner interface.
+type MockTxRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxRunnerMockRecorder
+}
+
+// MockTxRunnerMockRecorder is the mock recorder for MockTxRunner.
+type MockTxRunnerMockRecorder struct {
+	mock *MockTxRunner
+}
+
+// NewMockTxRunner creates a new mock instance.
+func NewMockTxRunner(ctrl *gomock.Controller) *MockTxRunner {
+	mock := &MockTxRunner{ctrl: ctrl}
+	mock.recorder = &MockTxRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTxRunner) EXPECT() *MockTxRunnerMockRecorder {
+	return m.recorder
+}
+
+// InTx mocks base method.
+func (m *MockTxRunner) InTx(ctx context.Context, fn func(TxResources) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePostTag", ctx, postid)
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeletePostTag indicates an expected call of DeletePostTag.
-func (mr *MockPostTagResourceMockRecorder) DeletePostTag(ctx, postid interface{}) *gomock.Call {
+// InTx indicates an expected call of InTx.
+func (mr *MockTxRunnerMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostTag", reflect.TypeOf((*MockPostTagResource)(nil).DeletePostTag), ctx, postid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockTxRunner)(nil).InTx), ctx, fn)
 }
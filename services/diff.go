@@ -0,0 +1,116 @@
+package services
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxDiffCells bounds the table diffLines fills. Texts whose changed middles
+// would need more are shown as the old lines removed and the new ones added.
+const maxDiffCells = 1 << 20
+
+// diffLines lines up the lines of a and b along their longest common
+// subsequence and marks every line kept, deleted from a or inserted from b.
+func diffLines(a, b string) []DiffLine {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	// Most edits touch a few lines, so the common head and tail are taken
+	// out before the quadratic part.
+	head := 0
+	for head < len(x) && head < len(y) && x[head] == y[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(x)-head && tail < len(y)-head && x[len(x)-1-tail] == y[len(y)-1-tail] {
+		tail++
+	}
+
+	result := make([]DiffLine, 0, len(x)+len(y))
+	for _, line := range x[:head] {
+		result = append(result, DiffLine{Op: DiffEqual, Text: line})
+	}
+	result = append(result, diffMiddle(x[head:len(x)-tail], y[head:len(y)-tail])...)
+	for _, line := range x[len(x)-tail:] {
+		result = append(result, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return result
+}
+
+func diffMiddle(x, y []string) []DiffLine {
+	var result []DiffLine
+	if (len(x)+1)*(len(y)+1) > maxDiffCells {
+		for _, line := range x {
+			result = append(result, DiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range y {
+			result = append(result, DiffLine{Op: DiffInsert, Text: line})
+		}
+		return result
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int32, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			result = append(result, DiffLine{Op: DiffEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffLine{Op: DiffDelete, Text: x[i]})
+			i++
+		default:
+			result = append(result, DiffLine{Op: DiffInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		result = append(result, DiffLine{Op: DiffDelete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		result = append(result, DiffLine{Op: DiffInsert, Text: y[j]})
+	}
+	return result
+}
+
+// diffIDs returns, in ascending order, the ids only in b and those only in a.
+func diffIDs(a, b []int32) (added, removed []int32) {
+	inA := make(map[int32]bool, len(a))
+	for _, id := range a {
+		inA[id] = true
+	}
+	inB := make(map[int32]bool, len(b))
+	for _, id := range b {
+		inB[id] = true
+	}
+
+	added, removed = []int32{}, []int32{}
+	for id := range inB {
+		if !inA[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range inA {
+		if !inB[id] {
+			removed = append(removed, id)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
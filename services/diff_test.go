@@ -0,0 +1,85 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_diffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []DiffLine
+	}{
+		{
+			name: "unchanged",
+			a:    "holiday",
+			b:    "holiday",
+			want: []DiffLine{{Op: DiffEqual, Text: "holiday"}},
+		},
+		{
+			name: "changed middle line",
+			a:    "sun\nsea\nsky",
+			b:    "sun\nsand\nsky",
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "sun"},
+				{Op: DiffDelete, Text: "sea"},
+				{Op: DiffInsert, Text: "sand"},
+				{Op: DiffEqual, Text: "sky"},
+			},
+		},
+		{
+			name: "moved line",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\na\nd",
+			want: []DiffLine{
+				{Op: DiffDelete, Text: "a"},
+				{Op: DiffEqual, Text: "b"},
+				{Op: DiffEqual, Text: "c"},
+				{Op: DiffInsert, Text: "a"},
+				{Op: DiffEqual, Text: "d"},
+			},
+		},
+		{
+			name: "appended line",
+			a:    "sun",
+			b:    "sun\nsea",
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "sun"},
+				{Op: DiffInsert, Text: "sea"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_diffLines_TooLarge(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 1100; i++ {
+		a = append(a, "a")
+		b = append(b, "b")
+	}
+
+	got := diffLines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+	if len(got) != 2200 || got[0].Op != DiffDelete || got[1100].Op != DiffInsert {
+		t.Errorf("diffLines() gave %d lines, want 1100 deleted then 1100 inserted", len(got))
+	}
+}
+
+func Test_diffIDs(t *testing.T) {
+	added, removed := diffIDs([]int32{3, 1, 2}, []int32{2, 5, 4})
+	if !reflect.DeepEqual(added, []int32{4, 5}) {
+		t.Errorf("diffIDs() added = %v, want [4 5]", added)
+	}
+	if !reflect.DeepEqual(removed, []int32{1, 3}) {
+		t.Errorf("diffIDs() removed = %v, want [1 3]", removed)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrVersionConflict is returned when a write names an expected version that
+// no longer matches the stored row, because someone else changed it first.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrCollectionExists is returned when a user already has a collection with
+// the requested name.
+var ErrCollectionExists = errors.New("collection already exists")
+
+// ErrOwnPost is returned when a user tries to repost or quote their own post.
+var ErrOwnPost = errors.New("cannot repost your own post")
+
+// ErrSelfFollow is returned when a user tries to follow themselves.
+var ErrSelfFollow = errors.New("cannot follow yourself")
+
+// ErrSelfBlock is returned when a user tries to block themselves.
+var ErrSelfBlock = errors.New("cannot block yourself")
+
+// ErrSelfMute is returned when a user tries to mute themselves.
+var ErrSelfMute = errors.New("cannot mute yourself")
+
+// ErrBlocked is returned when a user tries to follow, reply to, repost,
+// quote or message someone they block or are blocked by.
+var ErrBlocked = errors.New("users block each other")
+
+// ErrPublishAtRequired is returned when a post is scheduled without a time
+// to publish it.
+var ErrPublishAtRequired = errors.New("publish_at is required for scheduled posts")
+
+// ErrAlreadyPublished is returned when a published post would be turned back
+// into a draft or rescheduled.
+var ErrAlreadyPublished = errors.New("post is already published")
+
+// ErrNotAdmin is returned when a user who is not an admin asks for an
+// admin-only change.
+var ErrNotAdmin = errors.New("user is not an admin")
+
+// ErrNotAuthor is returned when someone other than the author edits a draft
+// or scheduled post or changes the status of a post.
+var ErrNotAuthor = errors.New("user is not the author of the post")
+
+// ErrTagGone is returned when a post is reverted to a revision carrying a
+// tag that has since been deleted.
+var ErrTagGone = errors.New("a tag of the revision no longer exists")
+
+// ErrMediaTooLarge is returned for uploads over the configured size limit.
+var ErrMediaTooLarge = errors.New("media is too large")
+
+// ErrUnsupportedMedia is returned for uploads whose content is not one of
+// the accepted types, or claims to be an image it does not decode as.
+var ErrUnsupportedMedia = errors.New("unsupported media type")
+
+// ErrMediaNotOwned is returned when a post references media that does not
+// exist or was uploaded by someone other than its author.
+var ErrMediaNotOwned = errors.New("media not found for the post's author")
+
+// ErrInvalidHandle is returned for a handle that does not follow the format
+// rules: a lowercase letter, then 2 to 29 lowercase letters, digits or
+// underscores.
+var ErrInvalidHandle = errors.New("invalid handle")
+
+// ErrInvalidWebsite is returned for a profile website that is not an http or
+// https URL.
+var ErrInvalidWebsite = errors.New("invalid website")
+
+// ErrHandleTaken is returned when a handle belongs to another user, or still
+// redirects to the user who gave it up.
+var ErrHandleTaken = errors.New("handle is taken")
+
+// ErrAvatarNotOwned is returned when an avatar names media that is not an
+// image uploaded by the user.
+var ErrAvatarNotOwned = errors.New("avatar must be an image uploaded by the user")
+
+// ErrNoParticipants is returned when a conversation is started with nobody
+// besides the user starting it.
+var ErrNoParticipants = errors.New("conversation needs another participant")
+
+// Postgres error codes the services map to their own errors.
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	// pqBlocked is raised by the triggers guarding writes between users who
+	// block each other.
+	pqBlocked = "SM001"
+)
+
+func isPQError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
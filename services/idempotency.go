@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gadhittana01/socialmedia/pkg/idempotency"
+)
+
+// ErrIdempotencyKeyReused is returned when a key is replayed with a request
+// body that differs from the one it was first used with.
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+type IdempotencyService interface {
+	// Do runs fn at most once per key and scope while the stored response
+	// is live, and replays that response afterwards. Concurrent calls with
+	// the same key wait for each other. The bool reports a replay.
+	Do(ctx context.Context, arg IdempotencyParams, fn func(ctx context.Context) IdempotentResponse) (IdempotentResponse, bool, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	db       *sql.DB
+	ttlHours int32
+}
+
+func NewIdempotencyService(db *sql.DB, ttlHours int32) (IdempotencyService, error) {
+	return &idempotencyService{
+		db:       db,
+		ttlHours: ttlHours,
+	}, nil
+}
+
+func (is *idempotencyService) Do(ctx context.Context, arg IdempotencyParams, fn func(ctx context.Context) IdempotentResponse) (IdempotentResponse, bool, error) {
+	var result IdempotentResponse = IdempotentResponse{}
+
+	// The advisory lock belongs to the session, so lock, lookup, save and
+	// unlock must all run on the same connection.
+	conn, err := is.db.Conn(ctx)
+	if err != nil {
+		return result, false, err
+	}
+	defer conn.Close()
+	q := idempotency.New(conn)
+
+	lockKey := arg.Scope + ":" + arg.Key
+	err = q.LockIdempotencyKey(ctx, lockKey)
+	if err != nil {
+		logSQLError(ctx, "LockIdempotencyKey", err)
+		return result, false, err
+	}
+	defer func() {
+		if err := q.UnlockIdempotencyKey(context.Background(), lockKey); err != nil {
+			logSQLError(ctx, "UnlockIdempotencyKey", err)
+		}
+	}()
+
+	stored, err := q.GetIdempotencyKey(ctx, idempotency.GetIdempotencyKeyParams{
+		Key:   arg.Key,
+		Scope: arg.Scope,
+	})
+	if err == nil {
+		if stored.RequestHash != arg.RequestHash {
+			return result, false, ErrIdempotencyKeyReused
+		}
+		result = IdempotentResponse{
+			StatusCode:  int(stored.StatusCode),
+			ContentType: stored.ContentType,
+			Body:        stored.ResponseBody,
+		}
+		return result, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		logSQLError(ctx, "GetIdempotencyKey", err)
+		return result, false, err
+	}
+
+	result = fn(ctx)
+
+	// Server errors are not stored so that the client can retry them.
+	if result.StatusCode >= 500 {
+		return result, false, nil
+	}
+
+	err = q.SaveIdempotencyKey(ctx, idempotency.SaveIdempotencyKeyParams{
+		Key:          arg.Key,
+		Scope:        arg.Scope,
+		RequestHash:  arg.RequestHash,
+		StatusCode:   int32(result.StatusCode),
+		ContentType:  result.ContentType,
+		ResponseBody: result.Body,
+		TtlHours:     is.ttlHours,
+	})
+	if err != nil {
+		logSQLError(ctx, "SaveIdempotencyKey", err)
+	}
+
+	return result, false, nil
+}
+
+func (is *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	res, err := idempotency.New(is.db).DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		logSQLError(ctx, "DeleteExpiredIdempotencyKeys", err)
+		return 0, err
+	}
+	return res, nil
+}
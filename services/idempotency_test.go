@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_IdempotencyService_Do(t *testing.T) {
+	ctx := context.Background()
+	arg := IdempotencyParams{
+		Key:         "abc",
+		Scope:       "POST /post",
+		RequestHash: "hash",
+	}
+	created := IdempotentResponse{
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"status":"Success"}`),
+	}
+	lock := regexp.QuoteMeta("SELECT pg_advisory_lock(hashtextextended($1::text, 0))")
+	unlock := regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtextextended($1::text, 0))")
+	get := "SELECT key, scope, request_hash, status_code, content_type, response_body FROM idempotency_keys"
+	columns := []string{"key", "scope", "request_hash", "status_code", "content_type", "response_body"}
+
+	tests := []struct {
+		name         string
+		fnResponse   IdempotentResponse
+		initMock     func(mock sqlmock.Sqlmock)
+		want         IdempotentResponse
+		wantReplayed bool
+		wantCalls    int
+		wantErr      error
+	}{
+		{
+			name:       "first request runs and stores the response",
+			fnResponse: created,
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(lock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(get).WithArgs("abc", "POST /post").WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO idempotency_keys").
+					WithArgs("abc", "POST /post", "hash", 201, "application/json", created.Body, 24).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(unlock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want:      created,
+			wantCalls: 1,
+		},
+		{
+			name: "replay returns the stored response",
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(lock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(get).WithArgs("abc", "POST /post").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("abc", "POST /post", "hash", 201, "application/json", created.Body))
+				mock.ExpectExec(unlock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want:         created,
+			wantReplayed: true,
+		},
+		{
+			name: "key reused with a different body",
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(lock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(get).WithArgs("abc", "POST /post").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("abc", "POST /post", "other", 201, "application/json", created.Body))
+				mock.ExpectExec(unlock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want:    IdempotentResponse{},
+			wantErr: ErrIdempotencyKeyReused,
+		},
+		{
+			name:       "server errors are not stored",
+			fnResponse: IdempotentResponse{StatusCode: 500},
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(lock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(get).WithArgs("abc", "POST /post").WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec(unlock).WithArgs("POST /post:abc").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want:      IdempotentResponse{StatusCode: 500},
+			wantCalls: 1,
+		},
+		{
+			name: "error acquiring the lock",
+			initMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(lock).WithArgs("POST /post:abc").WillReturnError(errors.New("error"))
+			},
+			want:    IdempotentResponse{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			tt.initMock(mock)
+			is, _ := NewIdempotencyService(db, 24)
+
+			calls := 0
+			got, replayed, err := is.Do(ctx, arg, func(ctx context.Context) IdempotentResponse {
+				calls++
+				return tt.fnResponse
+			})
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Do() = %v, want %v", got, tt.want)
+			}
+			if replayed != tt.wantReplayed {
+				t.Errorf("Do() replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Do() ran fn %d times, want %d", calls, tt.wantCalls)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package services
+
+type IdempotencyParams struct {
+	Key string
+	// Scope separates keys used by different callers and on different
+	// endpoints, e.g. "42 POST /post".
+	Scope       string
+	RequestHash string
+}
+
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
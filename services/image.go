@@ -0,0 +1,324 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// maxThumbnailPixels keeps thumbnailing from decoding images so large that
+// their pixels alone would exhaust memory. Larger images are stored without
+// a thumbnail.
+const maxThumbnailPixels = 50_000_000
+
+var errMalformedImage = errors.New("malformed image")
+
+// stripMetadata drops the metadata segments of a JPEG, PNG or WebP image
+// that can identify where or on what it was taken, leaving the pixel data
+// untouched. JPEGs keep their EXIF orientation, which is returned, so they
+// still display the right way up. Other content types pass through as is.
+func stripMetadata(contentType string, b []byte) ([]byte, int, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(b)
+	case "image/png":
+		out, err := stripPNG(b)
+		return out, 1, err
+	case "image/webp":
+		out, err := stripWebP(b)
+		return out, 1, err
+	}
+	return b, 1, nil
+}
+
+func stripJPEG(b []byte) ([]byte, int, error) {
+	if len(b) < 4 || b[0] != 0xFF || b[1] != 0xD8 {
+		return nil, 0, errMalformedImage
+	}
+
+	out := make([]byte, 0, len(b))
+	out = append(out, 0xFF, 0xD8)
+	orientation := 1
+	i := 2
+	for {
+		if i+2 > len(b) || b[i] != 0xFF {
+			return nil, 0, errMalformedImage
+		}
+		marker := b[i+1]
+		if marker == 0xFF {
+			// Fill byte before the marker proper.
+			i++
+			continue
+		}
+		if marker == 0xD9 {
+			return append(out, b[i:]...), orientation, nil
+		}
+		if i+4 > len(b) {
+			return nil, 0, errMalformedImage
+		}
+		n := int(binary.BigEndian.Uint16(b[i+2:]))
+		if n < 2 || i+2+n > len(b) {
+			return nil, 0, errMalformedImage
+		}
+		segment := b[i : i+2+n]
+		payload := segment[4:]
+
+		switch {
+		case marker == 0xE1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")):
+			if o := exifOrientation(payload[6:]); o > 1 {
+				orientation = o
+				out = append(out, orientationSegment(o)...)
+			}
+		case marker == 0xE1, marker == 0xED, marker == 0xFE:
+			// XMP, IPTC and comments.
+		case marker == 0xDA:
+			// The entropy-coded scan data that follows has no segments to
+			// strip.
+			return append(out, b[i:]...), orientation, nil
+		default:
+			out = append(out, segment...)
+		}
+		i += 2 + n
+	}
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF
+// structure, returning 1, the upright default, when there is none.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for e := ifd + 2; e+12 <= len(tiff) && count > 0; e, count = e+12, count-1 {
+		if order.Uint16(tiff[e:]) == 0x0112 && order.Uint16(tiff[e+2:]) == 3 {
+			o := int(order.Uint16(tiff[e+8:]))
+			if o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orientationSegment is an APP1 segment carrying nothing but an EXIF
+// orientation tag.
+func orientationSegment(orientation int) []byte {
+	seg := []byte{0xFF, 0xE1, 0, 34}
+	seg = append(seg, "Exif\x00\x00MM\x00*"...)
+	seg = binary.BigEndian.AppendUint32(seg, 8)
+	seg = binary.BigEndian.AppendUint16(seg, 1)
+	seg = binary.BigEndian.AppendUint16(seg, 0x0112)
+	seg = binary.BigEndian.AppendUint16(seg, 3)
+	seg = binary.BigEndian.AppendUint32(seg, 1)
+	seg = binary.BigEndian.AppendUint16(seg, uint16(orientation))
+	seg = binary.BigEndian.AppendUint16(seg, 0)
+	return binary.BigEndian.AppendUint32(seg, 0)
+}
+
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+func stripPNG(b []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(b, []byte(signature)) {
+		return nil, errMalformedImage
+	}
+
+	out := make([]byte, 0, len(b))
+	out = append(out, signature...)
+	for i := len(signature); i < len(b); {
+		if i+12 > len(b) {
+			return nil, errMalformedImage
+		}
+		n := int(binary.BigEndian.Uint32(b[i:]))
+		if i+12+n > len(b) {
+			return nil, errMalformedImage
+		}
+		chunk := b[i : i+12+n]
+		if !pngMetadataChunks[string(chunk[4:8])] {
+			out = append(out, chunk...)
+		}
+		i += 12 + n
+	}
+	return out, nil
+}
+
+func stripWebP(b []byte) ([]byte, error) {
+	if len(b) < 12 || string(b[:4]) != "RIFF" || string(b[8:12]) != "WEBP" {
+		return nil, errMalformedImage
+	}
+
+	out := make([]byte, 0, len(b))
+	out = append(out, b[:12]...)
+	for i := 12; i < len(b); {
+		if i+8 > len(b) {
+			return nil, errMalformedImage
+		}
+		n := int(binary.LittleEndian.Uint32(b[i+4:]))
+		if i+8+n > len(b) {
+			return nil, errMalformedImage
+		}
+		// Chunks are padded to an even length.
+		end := min(i+8+n+n%2, len(b))
+		chunk := b[i:end]
+		switch string(chunk[:4]) {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk = bytes.Clone(chunk)
+			if n > 0 {
+				// Clear the flags announcing EXIF and XMP chunks.
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, chunk...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+// imageSize returns the dimensions an image displays at once its
+// orientation is applied. ok is false for formats that cannot be decoded.
+func imageSize(b []byte, orientation int) (width, height int, ok bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return 0, 0, false
+	}
+	if orientation >= 5 {
+		return cfg.Height, cfg.Width, true
+	}
+	return cfg.Width, cfg.Height, true
+}
+
+// makeThumbnail scales an image down to fit in a size by size square,
+// turned upright, and encodes it as a JPEG, or as a PNG when it has
+// transparency. ok is false when the image cannot be thumbnailed.
+func makeThumbnail(b []byte, orientation int, size int) (thumb []byte, contentType string, ok bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil || cfg.Width*cfg.Height > maxThumbnailPixels {
+		return nil, "", false
+	}
+	src, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", false
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	img := orient(shrink(rgba, size), orientation)
+
+	var buf bytes.Buffer
+	if img.Opaque() {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80})
+		contentType = "image/jpeg"
+	} else {
+		err = png.Encode(&buf, img)
+		contentType = "image/png"
+	}
+	if err != nil {
+		return nil, "", false
+	}
+	return buf.Bytes(), contentType, true
+}
+
+// shrink scales src down to fit in a size by size square, averaging the
+// source pixels each thumbnail pixel covers. Smaller images are returned as
+// they are.
+func shrink(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if w <= size && h <= size {
+		return src
+	}
+	dw, dh := size, h*size/w
+	if h > w {
+		dw, dh = w*size/h, size
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[sx*4+c])
+					}
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			d := dst.Pix[y*dst.Stride+x*4:]
+			for c := 0; c < 4; c++ {
+				d[c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// orient turns src the way an EXIF orientation says it should be shown.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], src.Pix[y*src.Stride+x*4:])
+		}
+	}
+	return dst
+}
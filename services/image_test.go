@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func testJPEG(t *testing.T, w, h int, segments ...[]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(w, h, color.RGBA{200, 10, 10, 255}), nil); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	return append(append(append([]byte{}, b[:2]...), bytes.Join(segments, nil)...), b[2:]...)
+}
+
+func jpegSegment(marker byte, payload string) []byte {
+	seg := []byte{0xFF, marker}
+	seg = binary.BigEndian.AppendUint16(seg, uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// exifWithOrientation is a little-endian EXIF block whose first IFD holds a
+// camera model and the given orientation.
+func exifWithOrientation(orientation uint16) string {
+	b := []byte("Exif\x00\x00II*\x00")
+	b = binary.LittleEndian.AppendUint32(b, 8)
+	b = binary.LittleEndian.AppendUint16(b, 2)
+	b = binary.LittleEndian.AppendUint16(b, 0x0110)
+	b = binary.LittleEndian.AppendUint16(b, 2)
+	b = binary.LittleEndian.AppendUint32(b, 4)
+	b = append(b, "Cam\x00"...)
+	b = binary.LittleEndian.AppendUint16(b, 0x0112)
+	b = binary.LittleEndian.AppendUint16(b, 3)
+	b = binary.LittleEndian.AppendUint32(b, 1)
+	b = binary.LittleEndian.AppendUint16(b, orientation)
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	return string(b)
+}
+
+func Test_stripJPEG(t *testing.T) {
+	src := testJPEG(t, 4, 2,
+		jpegSegment(0xE1, exifWithOrientation(6)),
+		jpegSegment(0xE1, "http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>secret</x:xmpmeta>"),
+		jpegSegment(0xFE, "taken at home"),
+	)
+
+	got, orientation, err := stripMetadata("image/jpeg", src)
+	if err != nil {
+		t.Fatalf("stripMetadata() error = %v", err)
+	}
+	if orientation != 6 {
+		t.Errorf("stripMetadata() orientation = %d, want 6", orientation)
+	}
+	for _, leak := range []string{"Cam", "xmpmeta", "taken at home"} {
+		if bytes.Contains(got, []byte(leak)) {
+			t.Errorf("stripMetadata() kept %q", leak)
+		}
+	}
+	if !bytes.Contains(got, orientationSegment(6)) {
+		t.Errorf("stripMetadata() dropped the orientation")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(got)); err != nil {
+		t.Errorf("stripped JPEG does not decode: %v", err)
+	}
+	if w, h, _ := imageSize(got, orientation); w != 2 || h != 4 {
+		t.Errorf("imageSize() = %dx%d, want 2x4", w, h)
+	}
+
+	if _, _, err := stripMetadata("image/jpeg", src[:40]); err == nil {
+		t.Errorf("stripMetadata() of a truncated JPEG succeeded, want an error")
+	}
+}
+
+func Test_stripPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(2, 2, color.White)); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	text := "Author\x00Someone"
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)))
+	chunk = append(chunk, "tEXt"+text...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	src := append(append(append([]byte{}, b[:33]...), chunk...), b[33:]...)
+
+	got, _, err := stripMetadata("image/png", src)
+	if err != nil {
+		t.Fatalf("stripMetadata() error = %v", err)
+	}
+	if !bytes.Equal(got, b) {
+		t.Errorf("stripMetadata() kept the text chunk")
+	}
+}
+
+func Test_stripWebP(t *testing.T) {
+	chunk := func(fourcc string, data []byte) []byte {
+		c := append([]byte(fourcc), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+		c = append(c, data...)
+		if len(data)%2 == 1 {
+			c = append(c, 0)
+		}
+		return c
+	}
+	riff := func(chunks ...[]byte) []byte {
+		body := append([]byte("WEBP"), bytes.Join(chunks, nil)...)
+		return append(append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...), body...)
+	}
+
+	vp8x := []byte{0x0C, 0, 0, 0, 1, 0, 0, 1, 0, 0}
+	src := riff(chunk("VP8X", vp8x), chunk("VP8L", []byte{1, 2, 3}), chunk("EXIF", []byte("gps")), chunk("XMP ", []byte("<x/>")))
+
+	got, _, err := stripMetadata("image/webp", src)
+	if err != nil {
+		t.Fatalf("stripMetadata() error = %v", err)
+	}
+	want := riff(chunk("VP8X", append([]byte{0}, vp8x[1:]...)), chunk("VP8L", []byte{1, 2, 3}))
+	if !bytes.Equal(got, want) {
+		t.Errorf("stripMetadata() = %q, want %q", got, want)
+	}
+}
+
+func Test_makeThumbnail(t *testing.T) {
+	src := testJPEG(t, 640, 320)
+
+	thumb, contentType, ok := makeThumbnail(src, 6, 100)
+	if !ok || contentType != "image/jpeg" {
+		t.Fatalf("makeThumbnail() = %q, %v, want a JPEG", contentType, ok)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 640x320 shrinks to 100x50, then turns upright to 50x100.
+	if cfg.Width != 50 || cfg.Height != 100 {
+		t.Errorf("thumbnail is %dx%d, want 50x100", cfg.Width, cfg.Height)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(10, 10, color.RGBA{0, 0, 0, 0})); err != nil {
+		t.Fatal(err)
+	}
+	if _, contentType, ok := makeThumbnail(buf.Bytes(), 1, 100); !ok || contentType != "image/png" {
+		t.Errorf("makeThumbnail() of a transparent image = %q, %v, want a PNG", contentType, ok)
+	}
+
+	if _, _, ok := makeThumbnail([]byte("%PDF-1.4"), 1, 100); ok {
+		t.Errorf("makeThumbnail() of a PDF succeeded")
+	}
+}
+
+func Test_orient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	src.Set(1, 0, color.RGBA{0, 0, 255, 255})
+
+	// Orientation 6 means the camera was turned a quarter clockwise, so
+	// the left pixel ends up on top.
+	got := orient(src, 6)
+	if got.Rect.Dx() != 1 || got.Rect.Dy() != 2 {
+		t.Fatalf("orient() size = %v, want 1x2", got.Rect)
+	}
+	if got.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) || got.RGBAAt(0, 1) != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("orient() = %v %v, want red above blue", got.RGBAAt(0, 0), got.RGBAAt(0, 1))
+	}
+}
@@ -0,0 +1,11 @@
+package services
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix turns user input into a LIKE pattern matching values that start
+// with it, so that % and _ in the input match themselves.
+func likePrefix(s string) string {
+	return likeEscaper.Replace(s) + "%"
+}
@@ -0,0 +1,10 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+)
+
+func logSQLError(ctx context.Context, op string, err error) {
+	slog.ErrorContext(ctx, "sql error", "op", op, "err", err)
+}
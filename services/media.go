@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/storage"
+)
+
+type MediaService interface {
+	UploadMedia(ctx context.Context, arg UploadMediaParams) (MediaRow, error)
+	GetMediaContent(ctx context.Context, arg GetMediaContentParams) (MediaContent, error)
+}
+
+type mediaService struct {
+	mr            MediaResource
+	store         storage.Storage
+	maxBytes      int64
+	thumbnailSize int
+}
+
+// NewMediaService returns a MediaService keeping files in store. Uploads
+// over maxBytes are refused, and image thumbnails fit in a thumbnailSize
+// pixel square.
+func NewMediaService(MR MediaResource, store storage.Storage, maxBytes int64, thumbnailSize int) (MediaService, error) {
+	return &mediaService{
+		mr:            MR,
+		store:         store,
+		maxBytes:      maxBytes,
+		thumbnailSize: thumbnailSize,
+	}, nil
+}
+
+// UploadMedia stores a file for a user. Its type is sniffed from its
+// content, images lose their metadata and get a thumbnail, and a file the
+// user has uploaded before returns the existing media. An unknown user gives
+// sql.ErrNoRows.
+func (ms *mediaService) UploadMedia(ctx context.Context, arg UploadMediaParams) (MediaRow, error) {
+	var result MediaRow = MediaRow{}
+	if int64(len(arg.Data)) > ms.maxBytes {
+		return result, ErrMediaTooLarge
+	}
+
+	contentType := http.DetectContentType(arg.Data)
+	if !mediaContentTypes[contentType] {
+		return result, ErrUnsupportedMedia
+	}
+	data, orientation, err := stripMetadata(contentType, arg.Data)
+	if err != nil {
+		return result, ErrUnsupportedMedia
+	}
+
+	var width, height int
+	var thumb []byte
+	var thumbType *string
+	if contentType != "application/pdf" && contentType != "image/webp" {
+		var ok bool
+		width, height, ok = imageSize(data, orientation)
+		if !ok {
+			return result, ErrUnsupportedMedia
+		}
+		if t, ct, ok := makeThumbnail(data, orientation, ms.thumbnailSize); ok {
+			thumb, thumbType = t, &ct
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	err = ms.store.Put(ctx, mediaKey(digest), bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		logSQLError(ctx, "PutMedia", err)
+		return result, err
+	}
+	if thumb != nil {
+		err = ms.store.Put(ctx, thumbnailKey(digest), bytes.NewReader(thumb), int64(len(thumb)), *thumbType)
+		if err != nil {
+			logSQLError(ctx, "PutThumbnail", err)
+			return result, err
+		}
+	}
+
+	res, err := ms.mr.CreateMedia(ctx, media.CreateMediaParams{
+		UserID:               arg.UserID,
+		Sha256:               digest,
+		ContentType:          contentType,
+		Size:                 int64(len(data)),
+		Width:                nullInt32(int32(width)),
+		Height:               nullInt32(int32(height)),
+		ThumbnailContentType: nullString(thumbType),
+	})
+	if isPQError(err, pqForeignKeyViolation) {
+		return result, sql.ErrNoRows
+	}
+	if err != nil {
+		logSQLError(ctx, "CreateMedia", err)
+		return result, err
+	}
+
+	result = toMediaRow(res)
+	return result, nil
+}
+
+// GetMediaContent opens a file, or its thumbnail, for a viewer who uploaded
+// it or may see a post it is attached to. Anything else, including a
+// thumbnail for a file without one, gives sql.ErrNoRows.
+func (ms *mediaService) GetMediaContent(ctx context.Context, arg GetMediaContentParams) (MediaContent, error) {
+	var result MediaContent = MediaContent{}
+	res, err := ms.mr.GetVisibleMedia(ctx, media.GetVisibleMediaParams{
+		ID:       arg.ID,
+		ViewerID: arg.ViewerID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetVisibleMedia", err)
+		return result, err
+	}
+
+	key, contentType, size := mediaKey(res.Sha256), res.ContentType, res.Size
+	if arg.Thumbnail {
+		if !res.ThumbnailContentType.Valid {
+			return result, sql.ErrNoRows
+		}
+		key, contentType, size = thumbnailKey(res.Sha256), res.ThumbnailContentType.String, 0
+	}
+
+	body, err := ms.store.Open(ctx, key)
+	if err != nil {
+		logSQLError(ctx, "OpenMedia", err)
+		return result, err
+	}
+
+	result = MediaContent{
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      res.Sha256,
+		Body:        body,
+	}
+	return result, nil
+}
+
+// mediaKey spreads files over directories named after the first byte of
+// their digest.
+func mediaKey(digest string) string {
+	return digest[:2] + "/" + digest
+}
+
+func thumbnailKey(digest string) string {
+	return mediaKey(digest) + "-thumb"
+}
+
+func mediaURL(id int32) string {
+	return fmt.Sprintf("/media/%d", id)
+}
+
+func thumbnailURL(id int32, thumbnailType sql.NullString) string {
+	if !thumbnailType.Valid {
+		return ""
+	}
+	return mediaURL(id) + "/thumbnail"
+}
+
+func toMediaRow(m media.Medium) MediaRow {
+	return MediaRow{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		ContentType:  m.ContentType,
+		Size:         m.Size,
+		Width:        m.Width.Int32,
+		Height:       m.Height.Int32,
+		SHA256:       m.Sha256,
+		URL:          mediaURL(m.ID),
+		ThumbnailURL: thumbnailURL(m.ID, m.ThumbnailContentType),
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func toPostMedia(m media.GetPostMediaRow) PostMedia {
+	return PostMedia{
+		ID:           m.ID,
+		ContentType:  m.ContentType,
+		Size:         m.Size,
+		Width:        m.Width.Int32,
+		Height:       m.Height.Int32,
+		URL:          mediaURL(m.ID),
+		ThumbnailURL: thumbnailURL(m.ID, m.ThumbnailContentType),
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"image/color"
+	"image/png"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+)
+
+func testStore(t *testing.T) *storage.Local {
+	t.Helper()
+	s, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestNewMediaService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mediaMock := NewMockMediaResource(ctrl)
+	store := testStore(t)
+
+	got, err := NewMediaService(mediaMock, store, 1024, 64)
+	if err != nil {
+		t.Fatalf("NewMediaService() error = %v", err)
+	}
+	want := &mediaService{
+		mr:            mediaMock,
+		store:         store,
+		maxBytes:      1024,
+		thumbnailSize: 64,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewMediaService() = %v, want %v", got, want)
+	}
+}
+
+func Test_UploadMedia(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(4, 2, color.White)); err != nil {
+		t.Fatal(err)
+	}
+	pngData := buf.Bytes()
+	sum := sha256.Sum256(pngData)
+	pngSHA := hex.EncodeToString(sum[:])
+	pdfData := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	sum = sha256.Sum256(pdfData)
+	pdfSHA := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		arg       UploadMediaParams
+		mock      func() MediaResource
+		want      MediaRow
+		wantErr   error
+		wantStore []string
+	}{
+		{
+			name: "success upload image",
+			arg:  UploadMediaParams{UserID: 1, Data: pngData},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().CreateMedia(gomock.Any(), media.CreateMediaParams{
+					UserID:               1,
+					Sha256:               pngSHA,
+					ContentType:          "image/png",
+					Size:                 int64(len(pngData)),
+					Width:                sql.NullInt32{Int32: 4, Valid: true},
+					Height:               sql.NullInt32{Int32: 2, Valid: true},
+					ThumbnailContentType: sql.NullString{String: "image/jpeg", Valid: true},
+				}).Return(media.Medium{
+					ID:                   7,
+					UserID:               1,
+					Sha256:               pngSHA,
+					ContentType:          "image/png",
+					Size:                 int64(len(pngData)),
+					Width:                sql.NullInt32{Int32: 4, Valid: true},
+					Height:               sql.NullInt32{Int32: 2, Valid: true},
+					ThumbnailContentType: sql.NullString{String: "image/jpeg", Valid: true},
+					CreatedAt:            createdAt,
+				}, nil)
+				return m
+			},
+			want: MediaRow{
+				ID:           7,
+				UserID:       1,
+				ContentType:  "image/png",
+				Size:         int64(len(pngData)),
+				Width:        4,
+				Height:       2,
+				SHA256:       pngSHA,
+				URL:          "/media/7",
+				ThumbnailURL: "/media/7/thumbnail",
+				CreatedAt:    createdAt,
+			},
+			wantStore: []string{mediaKey(pngSHA), thumbnailKey(pngSHA)},
+		},
+		{
+			name: "success upload pdf",
+			arg:  UploadMediaParams{UserID: 1, Data: pdfData},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().CreateMedia(gomock.Any(), media.CreateMediaParams{
+					UserID:      1,
+					Sha256:      pdfSHA,
+					ContentType: "application/pdf",
+					Size:        int64(len(pdfData)),
+				}).Return(media.Medium{
+					ID:          8,
+					UserID:      1,
+					Sha256:      pdfSHA,
+					ContentType: "application/pdf",
+					Size:        int64(len(pdfData)),
+					CreatedAt:   createdAt,
+				}, nil)
+				return m
+			},
+			want: MediaRow{
+				ID:          8,
+				UserID:      1,
+				ContentType: "application/pdf",
+				Size:        int64(len(pdfData)),
+				SHA256:      pdfSHA,
+				URL:         "/media/8",
+				CreatedAt:   createdAt,
+			},
+			wantStore: []string{mediaKey(pdfSHA)},
+		},
+		{
+			name: "file too large",
+			arg:  UploadMediaParams{UserID: 1, Data: make([]byte, 1025)},
+			mock: func() MediaResource {
+				return NewMockMediaResource(ctrl)
+			},
+			want:    MediaRow{},
+			wantErr: ErrMediaTooLarge,
+		},
+		{
+			name: "unsupported content type",
+			arg:  UploadMediaParams{UserID: 1, Data: []byte("<html><script>alert(1)</script></html>")},
+			mock: func() MediaResource {
+				return NewMockMediaResource(ctrl)
+			},
+			want:    MediaRow{},
+			wantErr: ErrUnsupportedMedia,
+		},
+		{
+			name: "truncated image",
+			arg:  UploadMediaParams{UserID: 1, Data: pngData[:40]},
+			mock: func() MediaResource {
+				return NewMockMediaResource(ctrl)
+			},
+			want:    MediaRow{},
+			wantErr: ErrUnsupportedMedia,
+		},
+		{
+			name: "user not found",
+			arg:  UploadMediaParams{UserID: 1, Data: pdfData},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Return(media.Medium{}, &pq.Error{Code: pqForeignKeyViolation})
+				return m
+			},
+			want:    MediaRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error create media",
+			arg:  UploadMediaParams{UserID: 1, Data: pdfData},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Return(media.Medium{}, errors.New("error"))
+				return m
+			},
+			want:    MediaRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := testStore(t)
+			s := &mediaService{
+				mr:            tt.mock(),
+				store:         store,
+				maxBytes:      1024,
+				thumbnailSize: 64,
+			}
+			got, err := s.UploadMedia(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("UploadMedia() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UploadMedia() = %v, want %v", got, tt.want)
+			}
+			for _, key := range tt.wantStore {
+				rc, err := store.Open(ctx, key)
+				if err != nil {
+					t.Errorf("UploadMedia() did not store %s: %v", key, err)
+					continue
+				}
+				rc.Close()
+			}
+		})
+	}
+}
+
+func Test_GetMediaContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	digest := strings.Repeat("ab", 32)
+
+	store := testStore(t)
+	if err := store.Put(ctx, mediaKey(digest), strings.NewReader("original"), 8, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, thumbnailKey(digest), strings.NewReader("thumb"), 5, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	stored := media.Medium{
+		ID:                   7,
+		UserID:               1,
+		Sha256:               digest,
+		ContentType:          "image/png",
+		Size:                 8,
+		ThumbnailContentType: sql.NullString{String: "image/jpeg", Valid: true},
+	}
+
+	tests := []struct {
+		name     string
+		arg      GetMediaContentParams
+		mock     func() MediaResource
+		want     MediaContent
+		wantBody string
+		wantErr  error
+	}{
+		{
+			name: "success get original",
+			arg:  GetMediaContentParams{ID: 7, ViewerID: 2},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().GetVisibleMedia(gomock.Any(), media.GetVisibleMediaParams{ID: 7, ViewerID: 2}).Return(stored, nil)
+				return m
+			},
+			want:     MediaContent{ContentType: "image/png", Size: 8, SHA256: digest},
+			wantBody: "original",
+		},
+		{
+			name: "success get thumbnail",
+			arg:  GetMediaContentParams{ID: 7, ViewerID: 2, Thumbnail: true},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().GetVisibleMedia(gomock.Any(), media.GetVisibleMediaParams{ID: 7, ViewerID: 2}).Return(stored, nil)
+				return m
+			},
+			want:     MediaContent{ContentType: "image/jpeg", SHA256: digest},
+			wantBody: "thumb",
+		},
+		{
+			name: "no thumbnail",
+			arg:  GetMediaContentParams{ID: 7, ViewerID: 2, Thumbnail: true},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().GetVisibleMedia(gomock.Any(), gomock.Any()).Return(media.Medium{Sha256: digest, ContentType: "application/pdf"}, nil)
+				return m
+			},
+			want:    MediaContent{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "hidden or missing media",
+			arg:  GetMediaContentParams{ID: 7, ViewerID: 2},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().GetVisibleMedia(gomock.Any(), gomock.Any()).Return(media.Medium{}, sql.ErrNoRows)
+				return m
+			},
+			want:    MediaContent{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "file missing from storage",
+			arg:  GetMediaContentParams{ID: 7, ViewerID: 2},
+			mock: func() MediaResource {
+				m := NewMockMediaResource(ctrl)
+				m.EXPECT().GetVisibleMedia(gomock.Any(), gomock.Any()).Return(media.Medium{Sha256: strings.Repeat("cd", 32)}, nil)
+				return m
+			},
+			want:    MediaContent{},
+			wantErr: storage.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mediaService{
+				mr:    tt.mock(),
+				store: store,
+			}
+			got, err := s.GetMediaContent(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetMediaContent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			body, err := io.ReadAll(got.Body)
+			got.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("GetMediaContent() body = %q, want %q", body, tt.wantBody)
+			}
+			got.Body = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMediaContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"io"
+	"time"
+)
+
+// Content types accepted for upload, as sniffed from the file itself.
+var mediaContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+type UploadMediaParams struct {
+	UserID int32
+	Data   []byte
+}
+
+type MediaRow struct {
+	ID           int32     `json:"id"`
+	UserID       int32     `json:"user_id"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int32     `json:"width,omitempty"`
+	Height       int32     `json:"height,omitempty"`
+	SHA256       string    `json:"sha256"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PostMedia is a file attached to a post, as shown with the post.
+type PostMedia struct {
+	ID           int32  `json:"id"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int32  `json:"width,omitempty"`
+	Height       int32  `json:"height,omitempty"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+type GetMediaContentParams struct {
+	ID       int32
+	ViewerID int32
+	// Thumbnail asks for the thumbnail instead of the file itself.
+	Thumbnail bool
+}
+
+// MediaContent is an open stored file. The caller closes Body.
+type MediaContent struct {
+	ContentType string
+	Size        int64
+	SHA256      string
+	Body        io.ReadCloser
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gadhittana01/socialmedia/pkg/message"
+)
+
+type MessageService interface {
+	// StartConversation creates a conversation between the user and the
+	// participants. Starting a 1:1 conversation that already exists returns
+	// it instead.
+	StartConversation(ctx context.Context, arg StartConversationParams) (ConversationRow, error)
+	// GetConversations lists the user's conversations, most recently active
+	// first.
+	GetConversations(ctx context.Context, arg GetConversationsParams) ([]ConversationRow, error)
+	GetConversation(ctx context.Context, arg GetConversationParams) (ConversationRow, error)
+	SendMessage(ctx context.Context, arg SendMessageParams) (Message, error)
+	// GetMessage returns one message to a participant of its conversation,
+	// unless its sender and the reader block each other. Stream events only
+	// name messages, so clients fetch them here.
+	GetMessage(ctx context.Context, arg GetMessageParams) (Message, error)
+	// GetMessages pages through a conversation, newest first. Messages from
+	// users the reader blocks or is blocked by are left out.
+	GetMessages(ctx context.Context, arg GetMessagesParams) ([]Message, error)
+	// MarkRead moves the user's read receipt forward. It never moves back.
+	MarkRead(ctx context.Context, arg MarkConversationReadParams) (ReadReceipt, error)
+}
+
+type messageService struct {
+	mr MessageResource
+	tx TxRunner
+}
+
+func NewMessageService(MR MessageResource, TX TxRunner) (MessageService, error) {
+	return &messageService{
+		mr: MR,
+		tx: TX,
+	}, nil
+}
+
+func (ms *messageService) StartConversation(ctx context.Context, arg StartConversationParams) (ConversationRow, error) {
+	var result ConversationRow = ConversationRow{}
+
+	others := make([]int32, 0, len(arg.ParticipantIDs))
+	seen := map[int32]bool{arg.UserID: true}
+	for _, id := range arg.ParticipantIDs {
+		if !seen[id] {
+			seen[id] = true
+			others = append(others, id)
+		}
+	}
+	if len(others) == 0 {
+		return result, ErrNoParticipants
+	}
+
+	directKey := sql.NullString{}
+	if len(others) == 1 {
+		directKey = sql.NullString{String: conversationDirectKey(arg.UserID, others[0]), Valid: true}
+	}
+
+	err := ms.tx.InTx(ctx, func(tx TxResources) error {
+		c, err := tx.Message.CreateConversation(ctx, message.CreateConversationParams{
+			CreatedBy: arg.UserID,
+			DirectKey: directKey,
+		})
+		if isPQError(err, pqForeignKeyViolation) {
+			return sql.ErrNoRows
+		}
+		if err != nil {
+			logSQLError(ctx, "CreateConversation", err)
+			return err
+		}
+
+		err = tx.Message.AddConversationParticipants(ctx, message.AddConversationParticipantsParams{
+			ConversationID: c.ID,
+			UserIds:        append([]int32{arg.UserID}, others...),
+		})
+		if isPQError(err, pqBlocked) {
+			return ErrBlocked
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			return sql.ErrNoRows
+		}
+		if err != nil {
+			logSQLError(ctx, "AddConversationParticipants", err)
+			return err
+		}
+
+		res, err := getConversation(ctx, tx.Message, GetConversationParams{ID: c.ID, UserID: arg.UserID})
+		if err != nil {
+			return err
+		}
+		result = res
+		return nil
+	})
+	if err != nil {
+		return ConversationRow{}, err
+	}
+	return result, nil
+}
+
+// conversationDirectKey names the 1:1 conversation of two users the same
+// whichever of them starts it.
+func conversationDirectKey(a, b int32) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%d:%d", a, b)
+}
+
+func (ms *messageService) GetConversations(ctx context.Context, arg GetConversationsParams) ([]ConversationRow, error) {
+	var result []ConversationRow = []ConversationRow{}
+	res, err := ms.mr.GetConversations(ctx, message.GetConversationsParams{
+		UserID: arg.UserID,
+		Limit:  arg.Limit,
+		Offset: arg.Offset,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetConversations", err)
+		return result, err
+	}
+	if len(res) == 0 {
+		return result, nil
+	}
+
+	for _, item := range res {
+		result = append(result, ConversationRow{
+			ID:            item.ID,
+			Direct:        item.Direct,
+			CreatedBy:     item.CreatedBy,
+			UnreadCount:   item.UnreadCount,
+			CreatedAt:     item.CreatedAt,
+			LastMessageAt: item.LastMessageAt,
+		})
+	}
+	err = fillConversations(ctx, ms.mr, arg.UserID, result)
+	if err != nil {
+		return []ConversationRow{}, err
+	}
+	return result, nil
+}
+
+func (ms *messageService) GetConversation(ctx context.Context, arg GetConversationParams) (ConversationRow, error) {
+	return getConversation(ctx, ms.mr, arg)
+}
+
+// getConversation reads one of the user's conversations through mr. It is
+// sql.ErrNoRows when the user does not take part in it.
+func getConversation(ctx context.Context, mr MessageResource, arg GetConversationParams) (ConversationRow, error) {
+	res, err := mr.GetConversation(ctx, message.GetConversationParams{
+		UserID: arg.UserID,
+		ID:     arg.ID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetConversation", err)
+		return ConversationRow{}, err
+	}
+
+	result := []ConversationRow{{
+		ID:            res.ID,
+		Direct:        res.Direct,
+		CreatedBy:     res.CreatedBy,
+		UnreadCount:   res.UnreadCount,
+		CreatedAt:     res.CreatedAt,
+		LastMessageAt: res.LastMessageAt,
+	}}
+	err = fillConversations(ctx, mr, arg.UserID, result)
+	if err != nil {
+		return ConversationRow{}, err
+	}
+	return result[0], nil
+}
+
+// fillConversations adds the participants and the last message the user may
+// read to each conversation.
+func fillConversations(ctx context.Context, mr MessageResource, userID int32, rows []ConversationRow) error {
+	ids := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	participants, err := mr.GetConversationParticipants(ctx, ids)
+	if err != nil {
+		logSQLError(ctx, "GetConversationParticipants", err)
+		return err
+	}
+	byConversation := map[int32][]ConversationParticipant{}
+	for _, p := range participants {
+		byConversation[p.ConversationID] = append(byConversation[p.ConversationID], ConversationParticipant{
+			ID:                p.ID,
+			Fullname:          p.Fullname,
+			Handle:            p.Handle.String,
+			LastReadMessageID: p.LastReadMessageID,
+			LastReadAt:        timePtr(p.LastReadAt),
+		})
+	}
+
+	last, err := mr.GetLastMessages(ctx, message.GetLastMessagesParams{
+		ConversationIds: ids,
+		UserID:          userID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetLastMessages", err)
+		return err
+	}
+	lastByConversation := map[int32]Message{}
+	for _, m := range last {
+		lastByConversation[m.ConversationID] = toMessage(m)
+	}
+
+	for i := range rows {
+		rows[i].Participants = byConversation[rows[i].ID]
+		if rows[i].Participants == nil {
+			rows[i].Participants = []ConversationParticipant{}
+		}
+		if m, ok := lastByConversation[rows[i].ID]; ok {
+			rows[i].LastMessage = &m
+		}
+	}
+	return nil
+}
+
+func (ms *messageService) SendMessage(ctx context.Context, arg SendMessageParams) (Message, error) {
+	var result Message = Message{}
+	res, err := ms.mr.SendMessage(ctx, message.SendMessageParams{
+		Body:           arg.Body,
+		ConversationID: arg.ConversationID,
+		SenderID:       arg.UserID,
+	})
+	if isPQError(err, pqBlocked) {
+		return result, ErrBlocked
+	}
+	if err != nil {
+		logSQLError(ctx, "SendMessage", err)
+		return result, err
+	}
+	return toMessage(res), nil
+}
+
+func (ms *messageService) GetMessage(ctx context.Context, arg GetMessageParams) (Message, error) {
+	var result Message = Message{}
+	res, err := ms.mr.GetMessage(ctx, message.GetMessageParams{
+		UserID:         arg.UserID,
+		ID:             arg.ID,
+		ConversationID: arg.ConversationID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetMessage", err)
+		return result, err
+	}
+	return toMessage(res), nil
+}
+
+func (ms *messageService) GetMessages(ctx context.Context, arg GetMessagesParams) ([]Message, error) {
+	var result []Message = []Message{}
+	_, err := ms.mr.GetConversation(ctx, message.GetConversationParams{
+		UserID: arg.UserID,
+		ID:     arg.ConversationID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetConversation", err)
+		return result, err
+	}
+
+	res, err := ms.mr.GetMessages(ctx, message.GetMessagesParams{
+		ConversationID: arg.ConversationID,
+		BeforeID:       arg.BeforeID,
+		UserID:         arg.UserID,
+		Limit:          arg.Limit,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetMessages", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, toMessage(item))
+	}
+	return result, nil
+}
+
+func (ms *messageService) MarkRead(ctx context.Context, arg MarkConversationReadParams) (ReadReceipt, error) {
+	var result ReadReceipt = ReadReceipt{}
+	res, err := ms.mr.MarkConversationRead(ctx, message.MarkConversationReadParams{
+		MessageID:      arg.MessageID,
+		ConversationID: arg.ConversationID,
+		UserID:         arg.UserID,
+	})
+	if err != nil {
+		logSQLError(ctx, "MarkConversationRead", err)
+		return result, err
+	}
+
+	result = ReadReceipt{
+		ConversationID:    arg.ConversationID,
+		UserID:            arg.UserID,
+		LastReadMessageID: res.LastReadMessageID,
+		LastReadAt:        res.LastReadAt.Time,
+	}
+	return result, nil
+}
+
+func toMessage(m message.Message) Message {
+	return Message{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		SenderID:       m.SenderID,
+		Body:           m.Body,
+		CreatedAt:      m.CreatedAt,
+	}
+}
@@ -0,0 +1,577 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/message"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+)
+
+func TestNewMessageService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageMock := NewMockMessageResource(ctrl)
+	txMock := inlineTx{Message: messageMock}
+
+	want := &messageService{
+		mr: messageMock,
+		tx: txMock,
+	}
+	got, err := NewMessageService(messageMock, txMock)
+	if err != nil {
+		t.Fatalf("NewMessageService() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewMessageService() = %v, want %v", got, want)
+	}
+}
+
+func Test_StartConversation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	// loaded expects conversation 3 to be read back for user 1.
+	loaded := func(m *MockMessageResource, direct bool) {
+		m.EXPECT().GetConversation(gomock.Any(), message.GetConversationParams{UserID: 1, ID: 3}).Return(message.GetConversationRow{
+			ID: 3, CreatedBy: 1, Direct: direct, CreatedAt: createdAt, LastMessageAt: createdAt,
+		}, nil)
+		m.EXPECT().GetConversationParticipants(gomock.Any(), []int32{3}).Return([]message.GetConversationParticipantsRow{
+			{ConversationID: 3, ID: 1, Fullname: "Ana"},
+			{ConversationID: 3, ID: 2, Fullname: "Budi"},
+		}, nil)
+		m.EXPECT().GetLastMessages(gomock.Any(), message.GetLastMessagesParams{ConversationIds: []int32{3}, UserID: 1}).Return(nil, nil)
+	}
+	direct := ConversationRow{
+		ID:        3,
+		Direct:    true,
+		CreatedBy: 1,
+		Participants: []ConversationParticipant{
+			{ID: 1, Fullname: "Ana"},
+			{ID: 2, Fullname: "Budi"},
+		},
+		CreatedAt:     createdAt,
+		LastMessageAt: createdAt,
+	}
+
+	tests := []struct {
+		name    string
+		arg     StartConversationParams
+		mock    func() MessageResource
+		want    ConversationRow
+		wantErr error
+	}{
+		{
+			name: "success start direct conversation",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{2, 2, 1}},
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().CreateConversation(gomock.Any(), message.CreateConversationParams{
+						CreatedBy: 1,
+						DirectKey: sql.NullString{String: "1:2", Valid: true},
+					}).Return(message.Conversation{ID: 3}, nil),
+					m.EXPECT().AddConversationParticipants(gomock.Any(), message.AddConversationParticipantsParams{
+						ConversationID: 3,
+						UserIds:        []int32{1, 2},
+					}).Return(nil),
+				)
+				loaded(m, true)
+				return m
+			},
+			want: direct,
+		},
+		{
+			name: "success start group conversation",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{4, 2}},
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().CreateConversation(gomock.Any(), message.CreateConversationParams{CreatedBy: 1}).Return(message.Conversation{ID: 3}, nil)
+				m.EXPECT().AddConversationParticipants(gomock.Any(), message.AddConversationParticipantsParams{
+					ConversationID: 3,
+					UserIds:        []int32{1, 4, 2},
+				}).Return(nil)
+				loaded(m, false)
+				return m
+			},
+			want: ConversationRow{
+				ID:            3,
+				CreatedBy:     1,
+				Participants:  direct.Participants,
+				CreatedAt:     createdAt,
+				LastMessageAt: createdAt,
+			},
+		},
+		{
+			name: "only the user",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{1}},
+			mock: func() MessageResource {
+				return NewMockMessageResource(ctrl)
+			},
+			want:    ConversationRow{},
+			wantErr: ErrNoParticipants,
+		},
+		{
+			name: "blocked",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{2}},
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().CreateConversation(gomock.Any(), gomock.Any()).Return(message.Conversation{ID: 3}, nil)
+				m.EXPECT().AddConversationParticipants(gomock.Any(), gomock.Any()).Return(&pq.Error{Code: pqBlocked})
+				return m
+			},
+			want:    ConversationRow{},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "participant not found",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{2}},
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().CreateConversation(gomock.Any(), gomock.Any()).Return(message.Conversation{ID: 3}, nil)
+				m.EXPECT().AddConversationParticipants(gomock.Any(), gomock.Any()).Return(&pq.Error{Code: pqForeignKeyViolation})
+				return m
+			},
+			want:    ConversationRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "user not found",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{2}},
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().CreateConversation(gomock.Any(), gomock.Any()).Return(message.Conversation{}, &pq.Error{Code: pqForeignKeyViolation})
+				return m
+			},
+			want:    ConversationRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error create conversation",
+			arg:  StartConversationParams{UserID: 1, ParticipantIDs: []int32{2}},
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().CreateConversation(gomock.Any(), gomock.Any()).Return(message.Conversation{}, errors.New("error"))
+				return m
+			},
+			want:    ConversationRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mock()
+			ms := &messageService{mr: m, tx: inlineTx{Message: m}}
+			got, err := ms.StartConversation(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("StartConversation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StartConversation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetConversations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	readAt := createdAt.Add(time.Minute)
+	arg := GetConversationsParams{UserID: 1, Limit: 20}
+
+	tests := []struct {
+		name    string
+		mock    func() MessageResource
+		want    []ConversationRow
+		wantErr bool
+	}{
+		{
+			name: "success get conversations",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversations(gomock.Any(), message.GetConversationsParams{UserID: 1, Limit: 20}).Return([]message.GetConversationsRow{
+					{ID: 4, CreatedBy: 2, CreatedAt: createdAt, LastMessageAt: readAt, UnreadCount: 1},
+					{ID: 3, CreatedBy: 1, Direct: true, CreatedAt: createdAt, LastMessageAt: createdAt},
+				}, nil)
+				m.EXPECT().GetConversationParticipants(gomock.Any(), []int32{4, 3}).Return([]message.GetConversationParticipantsRow{
+					{ConversationID: 3, ID: 1, Fullname: "Ana", Handle: sql.NullString{String: "ana", Valid: true}, LastReadMessageID: 9, LastReadAt: sql.NullTime{Time: readAt, Valid: true}},
+					{ConversationID: 3, ID: 2, Fullname: "Budi"},
+					{ConversationID: 4, ID: 1, Fullname: "Ana", Handle: sql.NullString{String: "ana", Valid: true}},
+				}, nil)
+				m.EXPECT().GetLastMessages(gomock.Any(), message.GetLastMessagesParams{ConversationIds: []int32{4, 3}, UserID: 1}).Return([]message.Message{
+					{ID: 10, ConversationID: 4, SenderID: 2, Body: "hi", CreatedAt: readAt},
+				}, nil)
+				return m
+			},
+			want: []ConversationRow{
+				{
+					ID:            4,
+					CreatedBy:     2,
+					Participants:  []ConversationParticipant{{ID: 1, Fullname: "Ana", Handle: "ana"}},
+					UnreadCount:   1,
+					LastMessage:   &Message{ID: 10, ConversationID: 4, SenderID: 2, Body: "hi", CreatedAt: readAt},
+					CreatedAt:     createdAt,
+					LastMessageAt: readAt,
+				},
+				{
+					ID:        3,
+					Direct:    true,
+					CreatedBy: 1,
+					Participants: []ConversationParticipant{
+						{ID: 1, Fullname: "Ana", Handle: "ana", LastReadMessageID: 9, LastReadAt: &readAt},
+						{ID: 2, Fullname: "Budi"},
+					},
+					CreatedAt:     createdAt,
+					LastMessageAt: createdAt,
+				},
+			},
+		},
+		{
+			name: "no conversations",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversations(gomock.Any(), gomock.Any()).Return(nil, nil)
+				return m
+			},
+			want: []ConversationRow{},
+		},
+		{
+			name: "error get participants",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversations(gomock.Any(), gomock.Any()).Return([]message.GetConversationsRow{{ID: 3}}, nil)
+				m.EXPECT().GetConversationParticipants(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    []ConversationRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get conversations",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversations(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    []ConversationRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &messageService{mr: tt.mock()}
+			got, err := ms.GetConversations(ctx, arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetConversations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConversations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetConversation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func() MessageResource
+		want    ConversationRow
+		wantErr error
+	}{
+		{
+			name: "success get conversation",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversation(gomock.Any(), message.GetConversationParams{UserID: 1, ID: 3}).Return(message.GetConversationRow{
+					ID: 3, CreatedBy: 2, Direct: true, CreatedAt: createdAt, LastMessageAt: createdAt, UnreadCount: 2,
+				}, nil)
+				m.EXPECT().GetConversationParticipants(gomock.Any(), []int32{3}).Return(nil, nil)
+				m.EXPECT().GetLastMessages(gomock.Any(), gomock.Any()).Return([]message.Message{
+					{ID: 10, ConversationID: 3, SenderID: 2, Body: "hi", CreatedAt: createdAt},
+				}, nil)
+				return m
+			},
+			want: ConversationRow{
+				ID:            3,
+				Direct:        true,
+				CreatedBy:     2,
+				Participants:  []ConversationParticipant{},
+				UnreadCount:   2,
+				LastMessage:   &Message{ID: 10, ConversationID: 3, SenderID: 2, Body: "hi", CreatedAt: createdAt},
+				CreatedAt:     createdAt,
+				LastMessageAt: createdAt,
+			},
+		},
+		{
+			name: "not a participant",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversation(gomock.Any(), gomock.Any()).Return(message.GetConversationRow{}, sql.ErrNoRows)
+				return m
+			},
+			want:    ConversationRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error get last messages",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversation(gomock.Any(), gomock.Any()).Return(message.GetConversationRow{ID: 3}, nil)
+				m.EXPECT().GetConversationParticipants(gomock.Any(), gomock.Any()).Return(nil, nil)
+				m.EXPECT().GetLastMessages(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    ConversationRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &messageService{mr: tt.mock()}
+			got, err := ms.GetConversation(ctx, GetConversationParams{ID: 3, UserID: 1})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetConversation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConversation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_conversationDirectKey(t *testing.T) {
+	if got := conversationDirectKey(7, 3); got != "3:7" {
+		t.Errorf("conversationDirectKey(7, 3) = %q, want %q", got, "3:7")
+	}
+	if got := conversationDirectKey(3, 7); got != "3:7" {
+		t.Errorf("conversationDirectKey(3, 7) = %q, want %q", got, "3:7")
+	}
+}
+
+func Test_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func() MessageResource
+		want    Message
+		wantErr error
+	}{
+		{
+			name: "success send message",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().SendMessage(gomock.Any(), message.SendMessageParams{Body: "hi", ConversationID: 3, SenderID: 1}).Return(message.Message{
+					ID: 10, ConversationID: 3, SenderID: 1, Body: "hi", CreatedAt: createdAt,
+				}, nil)
+				return m
+			},
+			want: Message{ID: 10, ConversationID: 3, SenderID: 1, Body: "hi", CreatedAt: createdAt},
+		},
+		{
+			name: "blocked",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(message.Message{}, &pq.Error{Code: pqBlocked})
+				return m
+			},
+			want:    Message{},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "not a participant",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(message.Message{}, sql.ErrNoRows)
+				return m
+			},
+			want:    Message{},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &messageService{mr: tt.mock()}
+			got, err := ms.SendMessage(ctx, SendMessageParams{ConversationID: 3, UserID: 1, Body: "hi"})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("SendMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SendMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	arg := GetMessageParams{ConversationID: 3, ID: 11, UserID: 1}
+
+	tests := []struct {
+		name    string
+		mock    func() MessageResource
+		want    Message
+		wantErr error
+	}{
+		{
+			name: "success get message",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetMessage(gomock.Any(), message.GetMessageParams{UserID: 1, ID: 11, ConversationID: 3}).Return(
+					message.Message{ID: 11, ConversationID: 3, SenderID: 2, Body: "bye", CreatedAt: createdAt}, nil)
+				return m
+			},
+			want: Message{ID: 11, ConversationID: 3, SenderID: 2, Body: "bye", CreatedAt: createdAt},
+		},
+		{
+			name: "not a participant",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetMessage(gomock.Any(), gomock.Any()).Return(message.Message{}, sql.ErrNoRows)
+				return m
+			},
+			want:    Message{},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &messageService{mr: tt.mock()}
+			got, err := ms.GetMessage(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	arg := GetMessagesParams{ConversationID: 3, UserID: 1, BeforeID: 12, Limit: 2}
+
+	tests := []struct {
+		name    string
+		mock    func() MessageResource
+		want    []Message
+		wantErr error
+	}{
+		{
+			name: "success get messages",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversation(gomock.Any(), message.GetConversationParams{UserID: 1, ID: 3}).Return(message.GetConversationRow{ID: 3}, nil)
+				m.EXPECT().GetMessages(gomock.Any(), message.GetMessagesParams{ConversationID: 3, BeforeID: 12, UserID: 1, Limit: 2}).Return([]message.Message{
+					{ID: 11, ConversationID: 3, SenderID: 2, Body: "bye", CreatedAt: createdAt},
+				}, nil)
+				return m
+			},
+			want: []Message{{ID: 11, ConversationID: 3, SenderID: 2, Body: "bye", CreatedAt: createdAt}},
+		},
+		{
+			name: "not a participant",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversation(gomock.Any(), gomock.Any()).Return(message.GetConversationRow{}, sql.ErrNoRows)
+				return m
+			},
+			want:    []Message{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error get messages",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().GetConversation(gomock.Any(), gomock.Any()).Return(message.GetConversationRow{ID: 3}, nil)
+				m.EXPECT().GetMessages(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    []Message{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &messageService{mr: tt.mock()}
+			got, err := ms.GetMessages(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetMessages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMessages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarkConversationRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	readAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func() MessageResource
+		want    ReadReceipt
+		wantErr error
+	}{
+		{
+			name: "success mark read",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().MarkConversationRead(gomock.Any(), message.MarkConversationReadParams{MessageID: 10, ConversationID: 3, UserID: 1}).Return(message.MarkConversationReadRow{
+					LastReadMessageID: 11,
+					LastReadAt:        sql.NullTime{Time: readAt, Valid: true},
+				}, nil)
+				return m
+			},
+			want: ReadReceipt{ConversationID: 3, UserID: 1, LastReadMessageID: 11, LastReadAt: readAt},
+		},
+		{
+			name: "not a participant",
+			mock: func() MessageResource {
+				m := NewMockMessageResource(ctrl)
+				m.EXPECT().MarkConversationRead(gomock.Any(), gomock.Any()).Return(message.MarkConversationReadRow{}, sql.ErrNoRows)
+				return m
+			},
+			want:    ReadReceipt{},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &messageService{mr: tt.mock()}
+			got, err := ms.MarkRead(ctx, MarkConversationReadParams{ConversationID: 3, UserID: 1, MessageID: 10})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("MarkRead() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarkRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import "time"
+
+type StartConversationParams struct {
+	UserID int32
+	// ParticipantIDs are the other users to talk to. A single one makes a
+	// 1:1 conversation, which two users only ever have one of.
+	ParticipantIDs []int32
+}
+
+type GetConversationsParams struct {
+	UserID int32
+	Limit  int32
+	Offset int32
+}
+
+type GetConversationParams struct {
+	ID     int32
+	UserID int32
+}
+
+// ConversationParticipant carries the participant's read receipt: they have
+// read every message up to LastReadMessageID.
+type ConversationParticipant struct {
+	ID                int32      `json:"id"`
+	Fullname          string     `json:"fullname"`
+	Handle            string     `json:"handle,omitempty"`
+	LastReadMessageID int64      `json:"last_read_message_id"`
+	LastReadAt        *time.Time `json:"last_read_at,omitempty"`
+}
+
+type Message struct {
+	ID             int64     `json:"id"`
+	ConversationID int32     `json:"conversation_id"`
+	SenderID       int32     `json:"sender_id"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ConversationRow struct {
+	ID           int32                     `json:"id"`
+	Direct       bool                      `json:"direct"`
+	CreatedBy    int32                     `json:"created_by"`
+	Participants []ConversationParticipant `json:"participants"`
+	// UnreadCount counts the messages from others after the user's read
+	// receipt.
+	UnreadCount   int32     `json:"unread_count"`
+	LastMessage   *Message  `json:"last_message,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastMessageAt time.Time `json:"last_message_at"`
+}
+
+type SendMessageParams struct {
+	ConversationID int32
+	UserID         int32
+	Body           string
+}
+
+type GetMessageParams struct {
+	ConversationID int32
+	ID             int64
+	UserID         int32
+}
+
+type GetMessagesParams struct {
+	ConversationID int32
+	UserID         int32
+	// BeforeID, when not 0, pages back from that message.
+	BeforeID int64
+	Limit    int32
+}
+
+type MarkConversationReadParams struct {
+	ConversationID int32
+	UserID         int32
+	// MessageID is the last message read. 0 marks the whole conversation
+	// read.
+	MessageID int64
+}
+
+type ReadReceipt struct {
+	ConversationID    int32     `json:"conversation_id"`
+	UserID            int32     `json:"user_id"`
+	LastReadMessageID int64     `json:"last_read_message_id"`
+	LastReadAt        time.Time `json:"last_read_at"`
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gadhittana01/socialmedia/pkg/notification"
+)
+
+const (
+	// notificationBatchSize caps the events one transaction turns into
+	// notifications.
+	notificationBatchSize = 100
+	// notificationActorsShown is how many actors a notification lists by
+	// name.
+	notificationActorsShown = 3
+)
+
+var notificationVerbs = map[string]string{
+	NotificationKindFollow:   "followed you",
+	NotificationKindReply:    "replied to your post",
+	NotificationKindMention:  "mentioned you",
+	NotificationKindReaction: "reacted to your post",
+	NotificationKindRepost:   "reposted your post",
+	NotificationKindQuote:    "quoted your post",
+}
+
+type NotificationService interface {
+	// ProcessEvents turns the events recorded by follows, reactions and
+	// published posts into notifications, and returns how many events it
+	// handled. Events for recipients who turned the kind off, who cannot
+	// see the post, or who block or muted the actor are dropped. Runs on
+	// several replicas at once never handle the same event twice.
+	ProcessEvents(ctx context.Context) (int, error)
+	GetNotifications(ctx context.Context, arg GetNotificationsParams) ([]NotificationRow, error)
+	MarkRead(ctx context.Context, arg MarkNotificationReadParams) error
+	MarkAllRead(ctx context.Context, userID int32) (int64, error)
+	GetPreferences(ctx context.Context, userID int32) (NotificationPreferences, error)
+	SetPreferences(ctx context.Context, arg SetNotificationPreferencesParams) (NotificationPreferences, error)
+}
+
+type notificationService struct {
+	nr NotificationResource
+	tx TxRunner
+}
+
+func NewNotificationService(NR NotificationResource, TX TxRunner) (NotificationService, error) {
+	return &notificationService{
+		nr: NR,
+		tx: TX,
+	}, nil
+}
+
+func (ns *notificationService) ProcessEvents(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		var n int
+		err := ns.tx.InTx(ctx, func(tx TxResources) error {
+			events, err := tx.Notification.ClaimNotificationEvents(ctx, notificationBatchSize)
+			if err != nil {
+				logSQLError(ctx, "ClaimNotificationEvents", err)
+				return err
+			}
+			if len(events) == 0 {
+				return nil
+			}
+
+			ids := make([]int64, 0, len(events))
+			for _, e := range events {
+				ids = append(ids, e.ID)
+				id, err := tx.Notification.UpsertNotification(ctx, notification.UpsertNotificationParams{
+					RecipientID: e.RecipientID,
+					Kind:        e.Kind,
+					PostID:      e.PostID,
+					CreatedAt:   e.CreatedAt,
+					ActorID:     e.ActorID,
+				})
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				if err != nil {
+					logSQLError(ctx, "UpsertNotification", err)
+					return err
+				}
+				err = tx.Notification.AddNotificationActor(ctx, notification.AddNotificationActorParams{
+					NotificationID: id,
+					ActorID:        e.ActorID,
+					CreatedAt:      e.CreatedAt,
+				})
+				if err != nil {
+					logSQLError(ctx, "AddNotificationActor", err)
+					return err
+				}
+			}
+
+			err = tx.Notification.DeleteNotificationEvents(ctx, ids)
+			if err != nil {
+				logSQLError(ctx, "DeleteNotificationEvents", err)
+				return err
+			}
+			n = len(events)
+			return nil
+		})
+		if err != nil {
+			return processed, err
+		}
+		processed += n
+		if n < notificationBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+// GetNotifications lists the user's notifications, most recently active
+// first. Notifications about posts the user can no longer see, and actors
+// the user has since blocked or muted, are left out.
+func (ns *notificationService) GetNotifications(ctx context.Context, arg GetNotificationsParams) ([]NotificationRow, error) {
+	var result []NotificationRow = []NotificationRow{}
+	res, err := ns.nr.GetNotifications(ctx, notification.GetNotificationsParams{
+		RecipientID: arg.UserID,
+		UnreadOnly:  arg.UnreadOnly,
+		Limit:       arg.Limit,
+		Offset:      arg.Offset,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetNotifications", err)
+		return result, err
+	}
+	if len(res) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int32, 0, len(res))
+	for _, item := range res {
+		ids = append(ids, item.ID)
+	}
+	actors, err := ns.nr.GetNotificationActors(ctx, notification.GetNotificationActorsParams{
+		NotificationIds: ids,
+		RecipientID:     arg.UserID,
+		PerNotification: notificationActorsShown,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetNotificationActors", err)
+		return []NotificationRow{}, err
+	}
+	byNotification := map[int32][]NotificationActor{}
+	for _, a := range actors {
+		byNotification[a.NotificationID] = append(byNotification[a.NotificationID], NotificationActor{
+			ID:       a.ID,
+			Fullname: a.Fullname,
+			Handle:   a.Handle.String,
+		})
+	}
+
+	for _, item := range res {
+		row := NotificationRow{
+			ID:         item.ID,
+			Kind:       string(item.Kind),
+			PostID:     item.PostID.Int32,
+			Actors:     byNotification[item.ID],
+			ActorCount: item.ActorCount,
+			Read:       item.ReadAt.Valid,
+			CreatedAt:  item.CreatedAt,
+			UpdatedAt:  item.UpdatedAt,
+		}
+		if row.Actors == nil {
+			row.Actors = []NotificationActor{}
+		}
+		row.Message = notificationMessage(row)
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+func (ns *notificationService) MarkRead(ctx context.Context, arg MarkNotificationReadParams) error {
+	n, err := ns.nr.MarkNotificationRead(ctx, notification.MarkNotificationReadParams{
+		ID:          arg.ID,
+		RecipientID: arg.UserID,
+	})
+	if err != nil {
+		logSQLError(ctx, "MarkNotificationRead", err)
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (ns *notificationService) MarkAllRead(ctx context.Context, userID int32) (int64, error) {
+	n, err := ns.nr.MarkAllNotificationsRead(ctx, userID)
+	if err != nil {
+		logSQLError(ctx, "MarkAllNotificationsRead", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+func (ns *notificationService) GetPreferences(ctx context.Context, userID int32) (NotificationPreferences, error) {
+	return getNotificationPreferences(ctx, ns.nr, userID)
+}
+
+// getNotificationPreferences reads the user's settings through nr, filling
+// in the kinds they never changed.
+func getNotificationPreferences(ctx context.Context, nr NotificationResource, userID int32) (NotificationPreferences, error) {
+	res, err := nr.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		logSQLError(ctx, "GetNotificationPreferences", err)
+		return NotificationPreferences{}, err
+	}
+
+	result := NotificationPreferences{}
+	for _, kind := range notificationKinds {
+		result[kind] = true
+	}
+	for _, item := range res {
+		result[string(item.Kind)] = item.Enabled
+	}
+	return result, nil
+}
+
+func (ns *notificationService) SetPreferences(ctx context.Context, arg SetNotificationPreferencesParams) (NotificationPreferences, error) {
+	var result NotificationPreferences
+	err := ns.tx.InTx(ctx, func(tx TxResources) error {
+		for _, kind := range notificationKinds {
+			enabled, ok := arg.Preferences[kind]
+			if !ok {
+				continue
+			}
+			err := tx.Notification.SetNotificationPreference(ctx, notification.SetNotificationPreferenceParams{
+				UserID:  arg.UserID,
+				Kind:    notification.NotificationKind(kind),
+				Enabled: enabled,
+			})
+			if isPQError(err, pqForeignKeyViolation) {
+				return sql.ErrNoRows
+			}
+			if err != nil {
+				logSQLError(ctx, "SetNotificationPreference", err)
+				return err
+			}
+		}
+
+		prefs, err := getNotificationPreferences(ctx, tx.Notification, arg.UserID)
+		if err != nil {
+			return err
+		}
+		result = prefs
+		return nil
+	})
+	if err != nil {
+		return NotificationPreferences{}, err
+	}
+	return result, nil
+}
+
+// notificationMessage summarizes a notification as, for example, "Ana and 5
+// others reacted to your post".
+func notificationMessage(n NotificationRow) string {
+	if len(n.Actors) == 0 {
+		return ""
+	}
+	who := n.Actors[0].Fullname
+	switch {
+	case n.ActorCount == 2 && len(n.Actors) > 1:
+		who += " and " + n.Actors[1].Fullname
+	case n.ActorCount == 2:
+		who += " and 1 other"
+	case n.ActorCount > 2:
+		who += fmt.Sprintf(" and %d others", n.ActorCount-1)
+	}
+	return who + " " + notificationVerbs[n.Kind]
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/notification"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+)
+
+func TestNewNotificationService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	notificationMock := NewMockNotificationResource(ctrl)
+	txMock := inlineTx{Notification: notificationMock}
+
+	want := &notificationService{
+		nr: notificationMock,
+		tx: txMock,
+	}
+	got, err := NewNotificationService(notificationMock, txMock)
+	if err != nil {
+		t.Fatalf("NewNotificationService() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewNotificationService() = %v, want %v", got, want)
+	}
+}
+
+func Test_ProcessEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	follow := notification.NotificationEvent{ID: 1, Kind: notification.NotificationKindFollow, RecipientID: 1, ActorID: 2, CreatedAt: createdAt}
+	muted := notification.NotificationEvent{ID: 2, Kind: notification.NotificationKindReaction, RecipientID: 1, ActorID: 3, PostID: sql.NullInt32{Int32: 7, Valid: true}, CreatedAt: createdAt}
+	batch := func(n int) []notification.NotificationEvent {
+		events := make([]notification.NotificationEvent, n)
+		for i := range events {
+			events[i] = follow
+		}
+		return events
+	}
+
+	tests := []struct {
+		name    string
+		mock    func() NotificationResource
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "nothing to do",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().ClaimNotificationEvents(gomock.Any(), int32(notificationBatchSize)).Return(nil, nil)
+				return m
+			},
+			want: 0,
+		},
+		{
+			name: "notify and drop filtered events",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().ClaimNotificationEvents(gomock.Any(), int32(notificationBatchSize)).Return([]notification.NotificationEvent{follow, muted}, nil),
+					m.EXPECT().UpsertNotification(gomock.Any(), notification.UpsertNotificationParams{
+						RecipientID: 1,
+						Kind:        notification.NotificationKindFollow,
+						CreatedAt:   createdAt,
+						ActorID:     2,
+					}).Return(int32(5), nil),
+					m.EXPECT().AddNotificationActor(gomock.Any(), notification.AddNotificationActorParams{
+						NotificationID: 5,
+						ActorID:        2,
+						CreatedAt:      createdAt,
+					}).Return(nil),
+					m.EXPECT().UpsertNotification(gomock.Any(), gomock.Any()).Return(int32(0), sql.ErrNoRows),
+					m.EXPECT().DeleteNotificationEvents(gomock.Any(), []int64{1, 2}).Return(nil),
+				)
+				return m
+			},
+			want: 2,
+		},
+		{
+			name: "full batch runs again",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().ClaimNotificationEvents(gomock.Any(), gomock.Any()).Return(batch(notificationBatchSize), nil),
+					m.EXPECT().ClaimNotificationEvents(gomock.Any(), gomock.Any()).Return(batch(1), nil),
+				)
+				m.EXPECT().UpsertNotification(gomock.Any(), gomock.Any()).Return(int32(5), nil).Times(notificationBatchSize + 1)
+				m.EXPECT().AddNotificationActor(gomock.Any(), gomock.Any()).Return(nil).Times(notificationBatchSize + 1)
+				m.EXPECT().DeleteNotificationEvents(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				return m
+			},
+			want: notificationBatchSize + 1,
+		},
+		{
+			name: "error keeps the processed count",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().ClaimNotificationEvents(gomock.Any(), gomock.Any()).Return(batch(notificationBatchSize), nil),
+					m.EXPECT().ClaimNotificationEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")),
+				)
+				m.EXPECT().UpsertNotification(gomock.Any(), gomock.Any()).Return(int32(5), nil).Times(notificationBatchSize)
+				m.EXPECT().AddNotificationActor(gomock.Any(), gomock.Any()).Return(nil).Times(notificationBatchSize)
+				m.EXPECT().DeleteNotificationEvents(gomock.Any(), gomock.Any()).Return(nil)
+				return m
+			},
+			want:    notificationBatchSize,
+			wantErr: true,
+		},
+		{
+			name: "error upsert notification",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().ClaimNotificationEvents(gomock.Any(), gomock.Any()).Return([]notification.NotificationEvent{follow}, nil)
+				m.EXPECT().UpsertNotification(gomock.Any(), gomock.Any()).Return(int32(0), errors.New("error"))
+				return m
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mock()
+			ns := &notificationService{nr: m, tx: inlineTx{Notification: m}}
+			got, err := ns.ProcessEvents(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ProcessEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ProcessEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	arg := GetNotificationsParams{UserID: 1, UnreadOnly: true, Limit: 20}
+
+	tests := []struct {
+		name    string
+		mock    func() NotificationResource
+		want    []NotificationRow
+		wantErr bool
+	}{
+		{
+			name: "success get notifications",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().GetNotifications(gomock.Any(), notification.GetNotificationsParams{
+					RecipientID: 1,
+					UnreadOnly:  true,
+					Limit:       20,
+				}).Return([]notification.GetNotificationsRow{
+					{ID: 3, Kind: notification.NotificationKindReaction, PostID: sql.NullInt32{Int32: 7, Valid: true}, CreatedAt: createdAt, UpdatedAt: updatedAt, ActorCount: 6},
+					{ID: 4, Kind: notification.NotificationKindFollow, CreatedAt: createdAt, UpdatedAt: createdAt, ReadAt: sql.NullTime{Time: updatedAt, Valid: true}, ActorCount: 2},
+				}, nil)
+				m.EXPECT().GetNotificationActors(gomock.Any(), notification.GetNotificationActorsParams{
+					NotificationIds: []int32{3, 4},
+					RecipientID:     1,
+					PerNotification: notificationActorsShown,
+				}).Return([]notification.GetNotificationActorsRow{
+					{NotificationID: 3, ID: 2, Fullname: "Ana", Handle: sql.NullString{String: "ana", Valid: true}},
+					{NotificationID: 3, ID: 5, Fullname: "Budi"},
+					{NotificationID: 4, ID: 6, Fullname: "Citra"},
+					{NotificationID: 4, ID: 2, Fullname: "Ana"},
+				}, nil)
+				return m
+			},
+			want: []NotificationRow{
+				{
+					ID:         3,
+					Kind:       NotificationKindReaction,
+					PostID:     7,
+					Actors:     []NotificationActor{{ID: 2, Fullname: "Ana", Handle: "ana"}, {ID: 5, Fullname: "Budi"}},
+					ActorCount: 6,
+					Message:    "Ana and 5 others reacted to your post",
+					CreatedAt:  createdAt,
+					UpdatedAt:  updatedAt,
+				},
+				{
+					ID:         4,
+					Kind:       NotificationKindFollow,
+					Actors:     []NotificationActor{{ID: 6, Fullname: "Citra"}, {ID: 2, Fullname: "Ana"}},
+					ActorCount: 2,
+					Message:    "Citra and Ana followed you",
+					Read:       true,
+					CreatedAt:  createdAt,
+					UpdatedAt:  createdAt,
+				},
+			},
+		},
+		{
+			name: "no notifications",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().GetNotifications(gomock.Any(), gomock.Any()).Return(nil, nil)
+				return m
+			},
+			want: []NotificationRow{},
+		},
+		{
+			name: "error get notifications",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().GetNotifications(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    []NotificationRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get notification actors",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().GetNotifications(gomock.Any(), gomock.Any()).Return([]notification.GetNotificationsRow{{ID: 3}}, nil)
+				m.EXPECT().GetNotificationActors(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    []NotificationRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &notificationService{nr: tt.mock()}
+			got, err := ns.GetNotifications(ctx, arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNotifications() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_notificationMessage(t *testing.T) {
+	ana := NotificationActor{ID: 1, Fullname: "Ana"}
+	budi := NotificationActor{ID: 2, Fullname: "Budi"}
+	tests := []struct {
+		name string
+		n    NotificationRow
+		want string
+	}{
+		{"one actor", NotificationRow{Kind: NotificationKindMention, Actors: []NotificationActor{ana}, ActorCount: 1}, "Ana mentioned you"},
+		{"two actors", NotificationRow{Kind: NotificationKindRepost, Actors: []NotificationActor{ana, budi}, ActorCount: 2}, "Ana and Budi reposted your post"},
+		{"second actor hidden", NotificationRow{Kind: NotificationKindRepost, Actors: []NotificationActor{ana}, ActorCount: 2}, "Ana and 1 other reposted your post"},
+		{"many actors", NotificationRow{Kind: NotificationKindFollow, Actors: []NotificationActor{ana, budi}, ActorCount: 6}, "Ana and 5 others followed you"},
+		{"no actors", NotificationRow{Kind: NotificationKindFollow}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notificationMessage(tt.n); got != tt.want {
+				t.Errorf("notificationMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mock    func() NotificationResource
+		wantErr error
+	}{
+		{
+			name: "success mark read",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().MarkNotificationRead(gomock.Any(), notification.MarkNotificationReadParams{ID: 3, RecipientID: 1}).Return(int64(1), nil)
+				return m
+			},
+		},
+		{
+			name: "notification of someone else",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+				return m
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error mark read",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+				return m
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &notificationService{nr: tt.mock()}
+			err := ns.MarkRead(ctx, MarkNotificationReadParams{ID: 3, UserID: 1})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("MarkRead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_MarkAllRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mock    func() NotificationResource
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success mark all read",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().MarkAllNotificationsRead(gomock.Any(), int32(1)).Return(int64(4), nil)
+				return m
+			},
+			want: 4,
+		},
+		{
+			name: "error mark all read",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+				return m
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &notificationService{nr: tt.mock()}
+			got, err := ns.MarkAllRead(ctx, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkAllRead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MarkAllRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mock    func() NotificationResource
+		want    NotificationPreferences
+		wantErr bool
+	}{
+		{
+			name: "defaults and overrides",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().GetNotificationPreferences(gomock.Any(), int32(1)).Return([]notification.GetNotificationPreferencesRow{
+					{Kind: notification.NotificationKindReaction, Enabled: false},
+				}, nil)
+				return m
+			},
+			want: NotificationPreferences{
+				NotificationKindFollow:   true,
+				NotificationKindReply:    true,
+				NotificationKindMention:  true,
+				NotificationKindReaction: false,
+				NotificationKindRepost:   true,
+				NotificationKindQuote:    true,
+			},
+		},
+		{
+			name: "error get preferences",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			want:    NotificationPreferences{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &notificationService{nr: tt.mock()}
+			got, err := ns.GetPreferences(ctx, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPreferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPreferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SetPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := SetNotificationPreferencesParams{
+		UserID:      1,
+		Preferences: NotificationPreferences{NotificationKindReaction: false, NotificationKindFollow: true},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func() NotificationResource
+		want    NotificationPreferences
+		wantErr error
+	}{
+		{
+			name: "success set preferences",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().SetNotificationPreference(gomock.Any(), notification.SetNotificationPreferenceParams{
+						UserID:  1,
+						Kind:    notification.NotificationKindFollow,
+						Enabled: true,
+					}).Return(nil),
+					m.EXPECT().SetNotificationPreference(gomock.Any(), notification.SetNotificationPreferenceParams{
+						UserID:  1,
+						Kind:    notification.NotificationKindReaction,
+						Enabled: false,
+					}).Return(nil),
+					m.EXPECT().GetNotificationPreferences(gomock.Any(), int32(1)).Return([]notification.GetNotificationPreferencesRow{
+						{Kind: notification.NotificationKindFollow, Enabled: true},
+						{Kind: notification.NotificationKindReaction, Enabled: false},
+					}, nil),
+				)
+				return m
+			},
+			want: NotificationPreferences{
+				NotificationKindFollow:   true,
+				NotificationKindReply:    true,
+				NotificationKindMention:  true,
+				NotificationKindReaction: false,
+				NotificationKindRepost:   true,
+				NotificationKindQuote:    true,
+			},
+		},
+		{
+			name: "user not found",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().SetNotificationPreference(gomock.Any(), gomock.Any()).Return(&pq.Error{Code: pqForeignKeyViolation})
+				return m
+			},
+			want:    NotificationPreferences{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error set preference",
+			mock: func() NotificationResource {
+				m := NewMockNotificationResource(ctrl)
+				m.EXPECT().SetNotificationPreference(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+				return m
+			},
+			want:    NotificationPreferences{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mock()
+			ns := &notificationService{nr: m, tx: inlineTx{Notification: m}}
+			got, err := ns.SetPreferences(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("SetPreferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetPreferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import "time"
+
+// What a notification is about. Follows, reactions and reposts are grouped,
+// so that one notification names everyone who did the same thing.
+const (
+	NotificationKindFollow   = "follow"
+	NotificationKindReply    = "reply"
+	NotificationKindMention  = "mention"
+	NotificationKindReaction = "reaction"
+	NotificationKindRepost   = "repost"
+	NotificationKindQuote    = "quote"
+)
+
+var notificationKinds = []string{
+	NotificationKindFollow,
+	NotificationKindReply,
+	NotificationKindMention,
+	NotificationKindReaction,
+	NotificationKindRepost,
+	NotificationKindQuote,
+}
+
+type GetNotificationsParams struct {
+	UserID     int32
+	UnreadOnly bool
+	Limit      int32
+	Offset     int32
+}
+
+type NotificationActor struct {
+	ID       int32  `json:"id"`
+	Fullname string `json:"fullname"`
+	Handle   string `json:"handle,omitempty"`
+}
+
+type NotificationRow struct {
+	ID   int32  `json:"id"`
+	Kind string `json:"kind"`
+	// PostID is the post reacted to or reposted, or the reply, quote or
+	// post mentioning the user. Follows have none.
+	PostID int32 `json:"post_id,omitempty"`
+	// Actors are the latest few users behind the notification, newest
+	// first, out of ActorCount.
+	Actors     []NotificationActor `json:"actors"`
+	ActorCount int32               `json:"actor_count"`
+	Message    string              `json:"message"`
+	Read       bool                `json:"read"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+type MarkNotificationReadParams struct {
+	ID     int32
+	UserID int32
+}
+
+// NotificationPreferences tells for every notification kind whether the
+// user gets notified.
+type NotificationPreferences map[string]bool
+
+type SetNotificationPreferencesParams struct {
+	UserID int32
+	// Preferences holds the kinds to change. Kinds left out keep their
+	// setting.
+	Preferences NotificationPreferences
+}
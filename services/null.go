@@ -0,0 +1,70 @@
+package services
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/post"
+)
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// nullVersion maps an expected version to the query argument, where 0 means
+// the caller did not ask for a version check.
+func nullVersion(v int32) sql.NullInt32 {
+	if v == 0 {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: v, Valid: true}
+}
+
+// nullInt32 treats 0 as "not set", which suits optional ids and filters.
+func nullInt32(v int32) sql.NullInt32 {
+	if v == 0 {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: v, Valid: true}
+}
+
+func nullVisibility(v *string) post.NullPostVisibility {
+	if v == nil {
+		return post.NullPostVisibility{}
+	}
+	return post.NullPostVisibility{PostVisibility: post.PostVisibility(*v), Valid: true}
+}
+
+func nullStatus(s *string) post.NullPostStatus {
+	if s == nil {
+		return post.NullPostStatus{}
+	}
+	return post.NullPostStatus{PostStatus: post.PostStatus(*s), Valid: true}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// nullTimePtr converts to UTC, as the timestamp columns do not keep the
+// zone and Postgres would otherwise store the local wall clock time.
+func nullTimePtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// timePtr maps a nullable column back to an optional JSON field.
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
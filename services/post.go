@@ -2,94 +2,584 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/gadhittana01/socialmedia/pkg/media"
 	"github.com/gadhittana01/socialmedia/pkg/post"
 	"github.com/gadhittana01/socialmedia/pkg/post_tags"
 )
 
 type PostService interface {
 	CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error)
-	GetPosts(ctx context.Context) ([]GetPostsRow, error)
+	GetPosts(ctx context.Context, arg GetPostsParams) ([]GetPostsRow, error)
+	SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error)
+	GetTagFeed(ctx context.Context, arg GetTagFeedParams) ([]GetPostsRow, error)
+	GetBookmarks(ctx context.Context, arg GetBookmarksParams) ([]GetPostsRow, error)
+	Repost(ctx context.Context, arg RepostParams) (RepostRow, error)
+	Unrepost(ctx context.Context, arg UnrepostParams) error
+	React(ctx context.Context, arg ReactParams) error
+	Unreact(ctx context.Context, arg UnreactParams) error
+	GetThread(ctx context.Context, arg GetThreadParams) (GetThreadRow, error)
 	UpdatePost(ctx context.Context, arg UpdatePostParams) (UpdatePostRow, error)
-	DeletePost(ctx context.Context, id int32) error
+	DeletePost(ctx context.Context, arg DeletePostParams) error
+	PatchPost(ctx context.Context, arg PatchPostParams) (PatchPostRow, error)
+	GetPost(ctx context.Context, arg GetPostParams) (GetPostRow, error)
+	GetPostRevisions(ctx context.Context, arg GetPostRevisionsParams) ([]PostRevision, error)
+	GetPostRevisionDiff(ctx context.Context, arg GetPostRevisionDiffParams) (PostRevisionDiff, error)
+	RevertPost(ctx context.Context, arg RevertPostParams) (UpdatePostRow, error)
 }
 
 type postService struct {
 	pr  PostResource
 	tr  TagResource
 	ptr PostTagResource
+	br  BookmarkResource
+	mr  MediaResource
+	// tx runs the writes that touch several tables, so that a post, its
+	// tags and its revisions always change together.
+	tx TxRunner
 }
 
-func NewPostService(PR PostResource, TR TagResource, PTR PostTagResource) (PostService, error) {
+func NewPostService(PR PostResource, TR TagResource, PTR PostTagResource, BR BookmarkResource, MR MediaResource, TX TxRunner) (PostService, error) {
 	return &postService{
 		pr:  PR,
 		tr:  TR,
 		ptr: PTR,
+		br:  BR,
+		mr:  MR,
+		tx:  TX,
 	}, nil
 }
 
 func (ps *postService) CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error) {
 	var result CreatePostRow = CreatePostRow{}
-	var tagIDs []int32
-	res, err := ps.pr.CreatePost(ctx, post.CreatePostParams{
-		Userid:      arg.Userid,
-		Title:       arg.Title,
-		Description: arg.Description,
+	visibility := arg.Visibility
+	if visibility == "" {
+		visibility = PostVisibilityPublic
+	}
+	status := arg.Status
+	if status == "" {
+		status = PostStatusPublished
+	}
+	var publishAt sql.NullTime
+	if status == PostStatusScheduled {
+		if arg.PublishAt.IsZero() {
+			return result, ErrPublishAtRequired
+		}
+		publishAt = nullTime(arg.PublishAt.UTC())
+	}
+
+	err := ps.tx.InTx(ctx, func(tx TxResources) error {
+		res, err := tx.Post.CreatePost(ctx, post.CreatePostParams{
+			Userid:      arg.Userid,
+			Title:       arg.Title,
+			Description: arg.Description,
+			InReplyToID: nullInt32(arg.InReplyToID),
+			Visibility:  post.PostVisibility(visibility),
+			Status:      post.PostStatus(status),
+			PublishAt:   publishAt,
+		})
+		if isPQError(err, pqBlocked) {
+			return ErrBlocked
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			return sql.ErrNoRows
+		}
+		if err != nil {
+			logSQLError(ctx, "CreatePost", err)
+			return err
+		}
+
+		var tagIDs []int32
+		for _, tagID := range arg.TagID {
+			resPostTag, err := tx.PostTag.CreatePostTag(ctx, post_tags.CreatePostTagParams{
+				Postid: res.ID,
+				Tagid:  tagID,
+			})
+			if err != nil {
+				logSQLError(ctx, "CreatePostTag", err)
+				return err
+			}
+			tagIDs = append(tagIDs, resPostTag.Tagid)
+		}
+
+		mediaIDs, err := attachMedia(ctx, tx, res.ID, arg.MediaIDs)
+		if err != nil {
+			return err
+		}
+
+		_, err = addRevision(ctx, tx, res.ID, 0)
+		if err != nil {
+			return err
+		}
+
+		result = CreatePostRow{
+			ID:          res.ID,
+			Userid:      res.Userid,
+			Title:       res.Title,
+			Description: res.Description,
+			TagID:       tagIDs,
+			InReplyToID: res.InReplyToID.Int32,
+			Visibility:  string(res.Visibility),
+			Status:      string(res.Status),
+			PublishAt:   timePtr(res.PublishAt),
+			MediaIDs:    mediaIDs,
+		}
+		return nil
 	})
 	if err != nil {
+		return CreatePostRow{}, err
+	}
+
+	return result, nil
+}
+
+func (ps *postService) GetPosts(ctx context.Context, arg GetPostsParams) ([]GetPostsRow, error) {
+	var result []GetPostsRow = []GetPostsRow{}
+	sort := arg.Sort
+	if sort == "" {
+		sort = PostSortNewest
+	}
+	res, err := ps.pr.GetPosts(ctx, post.GetPostsParams{
+		UserID:          nullInt32(arg.UserID),
+		TagIds:          uniqueIDs(arg.TagIDs),
+		MatchAllTags:    arg.MatchAllTags,
+		CreatedFrom:     nullTime(arg.CreatedFrom),
+		CreatedTo:       nullTime(arg.CreatedTo),
+		CursorCreatedAt: nullTime(arg.CursorCreatedAt),
+		CursorID:        arg.CursorID,
+		ViewerID:        arg.ViewerID,
+		Sort:            sort,
+		Limit:           arg.Limit,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetPosts", err)
 		return result, err
 	}
 
-	for _, tagID := range arg.TagID {
-		resPostTag, err := ps.ptr.CreatePostTag(ctx, post_tags.CreatePostTagParams{
-			Postid: res.ID,
-			Tagid:  tagID,
+	for _, item := range res {
+		result = append(result, GetPostsRow{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			ReactionCount: item.ReactionCount,
+			RepostCount:   item.RepostCount,
+			QuoteCount:    item.QuoteCount,
+			ReplyCount:    item.ReplyCount,
+			InReplyToID:   item.InReplyToID.Int32,
+			Visibility:    string(item.Visibility),
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+			repostOfID:    item.RepostOfID.Int32,
+			CreatedAt:     timePtr(item.CreatedAt),
+			quoteOfID:     item.QuoteOfID.Int32,
 		})
-		if err != nil {
-			return result, err
-		}
-		tagIDs = append(tagIDs, resPostTag.Tagid)
+	}
+	err = ps.fillPosts(ctx, arg.ViewerID, result)
+	if err != nil {
+		return []GetPostsRow{}, err
 	}
 
-	result = CreatePostRow{
-		ID:          res.ID,
-		Userid:      res.Userid,
-		Title:       res.Title,
-		Description: res.Description,
-		TagID:       tagIDs,
+	return result, nil
+}
+
+// GetTagFeed lists, newest first, the posts carrying any tag the user
+// follows and that the user may see. A post matching several followed tags
+// is listed once.
+func (ps *postService) GetTagFeed(ctx context.Context, arg GetTagFeedParams) ([]GetPostsRow, error) {
+	var result []GetPostsRow = []GetPostsRow{}
+	res, err := ps.pr.GetTagFeed(ctx, post.GetTagFeedParams{
+		UserID: arg.UserID,
+		Limit:  arg.Limit,
+		Offset: arg.Offset,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetTagFeed", err)
+		return result, err
+	}
+
+	for _, item := range res {
+		result = append(result, GetPostsRow{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			ReactionCount: item.ReactionCount,
+			RepostCount:   item.RepostCount,
+			QuoteCount:    item.QuoteCount,
+			ReplyCount:    item.ReplyCount,
+			InReplyToID:   item.InReplyToID.Int32,
+			Visibility:    string(item.Visibility),
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+			repostOfID:    item.RepostOfID.Int32,
+			quoteOfID:     item.QuoteOfID.Int32,
+		})
+	}
+	err = ps.fillPosts(ctx, arg.UserID, result)
+	if err != nil {
+		return []GetPostsRow{}, err
 	}
 
 	return result, nil
 }
 
-func (ps *postService) GetPosts(ctx context.Context) ([]GetPostsRow, error) {
+// GetBookmarks lists the posts the user saved, most recently saved first.
+// A post saved in several collections is listed once.
+func (ps *postService) GetBookmarks(ctx context.Context, arg GetBookmarksParams) ([]GetPostsRow, error) {
 	var result []GetPostsRow = []GetPostsRow{}
-	res, err := ps.pr.GetPosts(context.Background())
+	res, err := ps.pr.GetBookmarks(ctx, post.GetBookmarksParams{
+		UserID:       arg.UserID,
+		CollectionID: nullInt32(arg.CollectionID),
+		Limit:        arg.Limit,
+		Offset:       arg.Offset,
+	})
 	if err != nil {
+		logSQLError(ctx, "GetBookmarks", err)
 		return result, err
 	}
 
 	for _, item := range res {
-		res, err := ps.tr.GetTagByPostID(context.Background(), item.ID)
+		result = append(result, GetPostsRow{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			ReactionCount: item.ReactionCount,
+			RepostCount:   item.RepostCount,
+			QuoteCount:    item.QuoteCount,
+			ReplyCount:    item.ReplyCount,
+			InReplyToID:   item.InReplyToID.Int32,
+			Visibility:    string(item.Visibility),
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+			repostOfID:    item.RepostOfID.Int32,
+			quoteOfID:     item.QuoteOfID.Int32,
+		})
+	}
+	err = ps.fillPosts(ctx, arg.UserID, result)
+	if err != nil {
+		return []GetPostsRow{}, err
+	}
+
+	return result, nil
+}
+
+// Repost reposts or, when arg.Quote is set, quotes a post. Reposting a
+// repost points at its original. Reposting the same post twice returns the
+// existing repost.
+func (ps *postService) Repost(ctx context.Context, arg RepostParams) (RepostRow, error) {
+	var result RepostRow = RepostRow{}
+	target, err := ps.pr.GetRepostTarget(ctx, arg.PostID)
+	if err != nil {
+		logSQLError(ctx, "GetRepostTarget", err)
+		return result, err
+	}
+	if target.Userid == arg.UserID {
+		return result, ErrOwnPost
+	}
+
+	if arg.Quote == "" {
+		res, err := ps.pr.CreateRepost(ctx, post.CreateRepostParams{
+			Userid:     arg.UserID,
+			RepostOfID: target.ID,
+		})
+		if isPQError(err, pqBlocked) {
+			return result, ErrBlocked
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			return result, sql.ErrNoRows
+		}
 		if err != nil {
+			logSQLError(ctx, "CreateRepost", err)
 			return result, err
 		}
 
-		var tags = []GetTagByPostIDRow{}
-		for _, tag := range res {
-			tags = append(tags, GetTagByPostIDRow{
-				ID:      tag.ID,
-				Tagname: tag.Tagname,
-			})
+		result = RepostRow{
+			ID:         res.ID,
+			Userid:     res.Userid,
+			RepostOfID: target.ID,
 		}
+		return result, nil
+	}
 
-		result = append(result, GetPostsRow{
-			ID:          item.ID,
-			Userid:      item.Userid,
-			Title:       item.Title,
-			Description: item.Description,
-			Tags:        tags,
+	err = ps.tx.InTx(ctx, func(tx TxResources) error {
+		res, err := tx.Post.CreateQuote(ctx, post.CreateQuoteParams{
+			Userid:      arg.UserID,
+			Description: arg.Quote,
+			QuoteOfID:   target.ID,
 		})
+		if isPQError(err, pqBlocked) {
+			return ErrBlocked
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			return sql.ErrNoRows
+		}
+		if err != nil {
+			logSQLError(ctx, "CreateQuote", err)
+			return err
+		}
+
+		_, err = addRevision(ctx, tx, res.ID, 0)
+		if err != nil {
+			return err
+		}
+
+		result = RepostRow{
+			ID:          res.ID,
+			Userid:      res.Userid,
+			Description: res.Description,
+			QuoteOfID:   target.ID,
+		}
+		return nil
+	})
+	if err != nil {
+		return RepostRow{}, err
+	}
+	return result, nil
+}
+
+// Unrepost removes the user's repost of a post. Quotes are posts of their
+// own and are removed with DeletePost.
+func (ps *postService) Unrepost(ctx context.Context, arg UnrepostParams) error {
+	err := ps.pr.DeleteRepost(ctx, post.DeleteRepostParams{
+		Userid: arg.UserID,
+		PostID: arg.PostID,
+	})
+	if err != nil {
+		logSQLError(ctx, "DeleteRepost", err)
+		return err
+	}
+	return nil
+}
+
+// React sets the user's reaction to a post, replacing the kind of an earlier
+// one. A post the user may not see gives sql.ErrNoRows.
+func (ps *postService) React(ctx context.Context, arg ReactParams) error {
+	added, err := ps.pr.AddReaction(ctx, post.AddReactionParams{
+		Kind:   arg.Kind,
+		PostID: arg.PostID,
+		UserID: arg.UserID,
+	})
+	if isPQError(err, pqBlocked) {
+		return ErrBlocked
+	}
+	if err != nil {
+		logSQLError(ctx, "AddReaction", err)
+		return err
+	}
+	if added == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Unreact removes the user's reaction to a post, if any.
+func (ps *postService) Unreact(ctx context.Context, arg UnreactParams) error {
+	err := ps.pr.RemoveReaction(ctx, post.RemoveReactionParams{
+		PostID: arg.PostID,
+		UserID: arg.UserID,
+	})
+	if err != nil {
+		logSQLError(ctx, "RemoveReaction", err)
+		return err
+	}
+	return nil
+}
+
+// GetThread returns a post with the chain of posts it replies to and a tree
+// of its replies, Depth levels deep. Posts the viewer may not see are left
+// out, and a hidden post gives sql.ErrNoRows.
+func (ps *postService) GetThread(ctx context.Context, arg GetThreadParams) (GetThreadRow, error) {
+	var result GetThreadRow = GetThreadRow{}
+	chain, err := ps.pr.GetReplyChain(ctx, post.GetReplyChainParams{
+		ID:       arg.ID,
+		ViewerID: arg.ViewerID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetReplyChain", err)
+		return result, err
+	}
+	if len(chain) == 0 {
+		return result, sql.ErrNoRows
+	}
+
+	replies, err := ps.pr.GetReplyTree(ctx, post.GetReplyTreeParams{
+		ID:       arg.ID,
+		ViewerID: arg.ViewerID,
+		Limit:    arg.Limit,
+		Offset:   arg.Offset,
+		Depth:    arg.Depth,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetReplyTree", err)
+		return result, err
+	}
+
+	ids := make([]int32, 0, len(chain)+len(replies))
+	for _, item := range chain {
+		ids = append(ids, item.ID)
+	}
+	for _, item := range replies {
+		ids = append(ids, item.ID)
+	}
+	tags, err := ps.getTagsByPostIDs(ctx, ids)
+	if err != nil {
+		return result, err
+	}
+
+	ancestors := make([]ThreadPost, 0, len(chain)-1)
+	for _, item := range chain[:len(chain)-1] {
+		ancestors = append(ancestors, ThreadPost{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			Tags:          tags[item.ID],
+			ReactionCount: item.ReactionCount,
+			ReplyCount:    item.ReplyCount,
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+		})
+	}
+	last := chain[len(chain)-1]
+	root := &ThreadPost{
+		ID:            last.ID,
+		Userid:        last.Userid,
+		Title:         last.Title,
+		Description:   last.Description,
+		Tags:          tags[last.ID],
+		ReactionCount: last.ReactionCount,
+		ReplyCount:    last.ReplyCount,
+		Edited:        last.EditedAt.Valid,
+		EditedAt:      timePtr(last.EditedAt),
+	}
+
+	// Replies come level by level, so a parent is always seen before its
+	// replies.
+	nodes := map[int32]*ThreadPost{root.ID: root}
+	for _, item := range replies {
+		node := &ThreadPost{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			Tags:          tags[item.ID],
+			ReactionCount: item.ReactionCount,
+			ReplyCount:    item.ReplyCount,
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+		}
+		nodes[item.ID] = node
+		if parent := nodes[item.InReplyToID.Int32]; parent != nil {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	result = GetThreadRow{
+		Ancestors: ancestors,
+		Post:      *root,
+	}
+	return result, nil
+}
+
+func (ps *postService) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	var result []SearchPostsRow = []SearchPostsRow{}
+	res, err := ps.pr.SearchPosts(ctx, post.SearchPostsParams{
+		Query:       arg.Query,
+		TagID:       nullInt32(arg.TagID),
+		UserID:      nullInt32(arg.UserID),
+		CreatedFrom: nullTime(arg.CreatedFrom),
+		CreatedTo:   nullTime(arg.CreatedTo),
+		ViewerID:    arg.ViewerID,
+		Limit:       arg.Limit,
+		Offset:      arg.Offset,
+	})
+	if err != nil {
+		logSQLError(ctx, "SearchPosts", err)
+		return result, err
+	}
+
+	posts := make([]GetPostsRow, 0, len(res))
+	for _, item := range res {
+		posts = append(posts, GetPostsRow{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			ReactionCount: item.ReactionCount,
+			RepostCount:   item.RepostCount,
+			QuoteCount:    item.QuoteCount,
+			ReplyCount:    item.ReplyCount,
+			InReplyToID:   item.InReplyToID.Int32,
+			Visibility:    string(item.Visibility),
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+			repostOfID:    item.RepostOfID.Int32,
+			quoteOfID:     item.QuoteOfID.Int32,
+		})
+	}
+	err = ps.fillPosts(ctx, arg.ViewerID, posts)
+	if err != nil {
+		return result, err
+	}
+
+	for i, item := range res {
+		result = append(result, SearchPostsRow{
+			GetPostsRow: posts[i],
+			Snippet:     item.Snippet,
+			Rank:        item.Rank,
+		})
+	}
+
+	return result, nil
+}
+
+// GetPost returns sql.ErrNoRows for posts the viewer may not see, so that
+// their existence is not revealed.
+func (ps *postService) GetPost(ctx context.Context, arg GetPostParams) (GetPostRow, error) {
+	var result GetPostRow = GetPostRow{}
+	res, err := ps.pr.GetVisiblePost(ctx, post.GetVisiblePostParams{
+		ID:       arg.ID,
+		ViewerID: arg.ViewerID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetVisiblePost", err)
+		return result, err
+	}
+
+	tags, err := ps.getTags(ctx, arg.ID)
+	if err != nil {
+		return result, err
+	}
+
+	resMedia, err := ps.mr.GetPostMedia(ctx, arg.ID)
+	if err != nil {
+		logSQLError(ctx, "GetPostMedia", err)
+		return result, err
+	}
+	postMedia := []PostMedia{}
+	for _, item := range resMedia {
+		postMedia = append(postMedia, toPostMedia(item))
+	}
+
+	result = GetPostRow{
+		ID:          res.ID,
+		Userid:      res.Userid,
+		Title:       res.Title,
+		Description: res.Description,
+		Tags:        tags,
+		Visibility:  string(res.Visibility),
+		Status:      string(res.Status),
+		PublishAt:   timePtr(res.PublishAt),
+		Edited:      res.EditedAt.Valid,
+		EditedAt:    timePtr(res.EditedAt),
+		Version:     res.Version,
+		Media:       postMedia,
 	}
 
 	return result, nil
@@ -97,61 +587,601 @@ func (ps *postService) GetPosts(ctx context.Context) ([]GetPostsRow, error) {
 
 func (ps *postService) UpdatePost(ctx context.Context, arg UpdatePostParams) (UpdatePostRow, error) {
 	var result UpdatePostRow = UpdatePostRow{}
-	_, err := ps.pr.GetPost(ctx, arg.ID)
+	err := ps.tx.InTx(ctx, func(tx TxResources) error {
+		current, err := tx.Post.GetPost(ctx, arg.ID)
+		if err != nil {
+			logSQLError(ctx, "GetPost", err)
+			return err
+		}
+		if arg.Version != 0 && arg.Version != current.Version {
+			return ErrVersionConflict
+		}
+		if current.Status != post.PostStatusPublished && !isAuthor(arg.EditorID, current.Userid) {
+			return ErrNotAuthor
+		}
+
+		res, err := tx.Post.UpdatePost(ctx, post.UpdatePostParams{
+			ID:          arg.ID,
+			Title:       arg.Title,
+			Description: arg.Description,
+			Version:     nullVersion(arg.Version),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			logSQLError(ctx, "UpdatePost", err)
+			return err
+		}
+
+		tagIDs, err := replaceTags(ctx, tx, arg.ID, arg.TagID)
+		if err != nil {
+			return err
+		}
+
+		rev, err := addRevision(ctx, tx, arg.ID, arg.EditorID)
+		if err != nil {
+			return err
+		}
+
+		result = UpdatePostRow{
+			Title:       res.Title,
+			Description: res.Description,
+			TagID:       tagIDs,
+			Edited:      rev.Revision > 1,
+			EditedAt:    revisionEditedAt(rev),
+			Version:     res.Version,
+		}
+		return nil
+	})
+	if err != nil {
+		return UpdatePostRow{}, err
+	}
+
+	return result, nil
+}
+
+func (ps *postService) DeletePost(ctx context.Context, arg DeletePostParams) error {
+	current, err := ps.pr.GetPost(ctx, arg.ID)
+	if err != nil {
+		logSQLError(ctx, "GetPost", err)
+		return err
+	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return ErrVersionConflict
+	}
+
+	deleted, err := ps.pr.DeletePost(ctx, post.DeletePostParams{
+		ID:      arg.ID,
+		Version: nullVersion(arg.Version),
+	})
+	if err != nil {
+		logSQLError(ctx, "DeletePost", err)
+		return err
+	}
+	if deleted == 0 {
+		return ErrVersionConflict
+	}
+
+	return nil
+}
+
+func (ps *postService) PatchPost(ctx context.Context, arg PatchPostParams) (PatchPostRow, error) {
+	var result PatchPostRow = PatchPostRow{}
+	var res post.PatchPostRow
+	editedAt := sql.NullTime{}
+	err := ps.tx.InTx(ctx, func(tx TxResources) error {
+		current, err := tx.Post.GetPost(ctx, arg.ID)
+		if err != nil {
+			logSQLError(ctx, "GetPost", err)
+			return err
+		}
+		if arg.Version != 0 && arg.Version != current.Version {
+			return ErrVersionConflict
+		}
+		if (arg.Status != nil || current.Status != post.PostStatusPublished) && !isAuthor(arg.EditorID, current.Userid) {
+			return ErrNotAuthor
+		}
+		if arg.Status != nil && *arg.Status != PostStatusPublished && current.Status == post.PostStatusPublished {
+			return ErrAlreadyPublished
+		}
+		if arg.Status != nil && *arg.Status == PostStatusScheduled && arg.PublishAt == nil && !current.PublishAt.Valid {
+			return ErrPublishAtRequired
+		}
+		editedAt = current.EditedAt
+
+		res, err = tx.Post.PatchPost(ctx, post.PatchPostParams{
+			ID:          arg.ID,
+			Title:       nullString(arg.Title),
+			Description: nullString(arg.Description),
+			Visibility:  nullVisibility(arg.Visibility),
+			Status:      nullStatus(arg.Status),
+			PublishAt:   nullTimePtr(arg.PublishAt),
+			Version:     nullVersion(arg.Version),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			logSQLError(ctx, "PatchPost", err)
+			return err
+		}
+
+		if arg.ReplaceTags {
+			_, err = replaceTags(ctx, tx, arg.ID, arg.TagID)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Only changes to what the post says are revisions. Moving it
+		// between audiences or publishing it is not.
+		if arg.Title != nil || arg.Description != nil || arg.ReplaceTags {
+			rev, err := addRevision(ctx, tx, arg.ID, arg.EditorID)
+			if err != nil {
+				return err
+			}
+			if rev.Revision > 1 {
+				editedAt = nullTime(rev.CreatedAt)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	tags, err := ps.getTags(ctx, arg.ID)
 	if err != nil {
 		return result, err
 	}
 
-	res, err := ps.pr.UpdatePost(ctx, post.UpdatePostParams{
-		ID:          arg.ID,
-		Title:       arg.Title,
-		Description: arg.Description,
+	result = PatchPostRow{
+		ID:          res.ID,
+		Userid:      res.Userid,
+		Title:       res.Title,
+		Description: res.Description,
+		Tags:        tags,
+		Visibility:  string(res.Visibility),
+		Status:      string(res.Status),
+		PublishAt:   timePtr(res.PublishAt),
+		Edited:      editedAt.Valid,
+		EditedAt:    timePtr(editedAt),
+		Version:     res.Version,
+	}
+
+	return result, nil
+}
+
+// GetPostRevisions lists the revisions of a post, newest first. A post the
+// viewer may not see gives sql.ErrNoRows.
+func (ps *postService) GetPostRevisions(ctx context.Context, arg GetPostRevisionsParams) ([]PostRevision, error) {
+	var result []PostRevision = []PostRevision{}
+	_, err := ps.pr.GetVisiblePost(ctx, post.GetVisiblePostParams{
+		ID:       arg.ID,
+		ViewerID: arg.ViewerID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetVisiblePost", err)
+		return result, err
+	}
+
+	res, err := ps.pr.GetPostRevisions(ctx, post.GetPostRevisionsParams{
+		PostID: arg.ID,
+		Limit:  arg.Limit,
+		Offset: arg.Offset,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetPostRevisions", err)
+		return result, err
+	}
+
+	for _, item := range res {
+		result = append(result, toPostRevision(item))
+	}
+	return result, nil
+}
+
+// GetPostRevisionDiff compares two revisions of a post the viewer may see.
+// A hidden post or a missing revision gives sql.ErrNoRows.
+func (ps *postService) GetPostRevisionDiff(ctx context.Context, arg GetPostRevisionDiffParams) (PostRevisionDiff, error) {
+	var result PostRevisionDiff = PostRevisionDiff{}
+	_, err := ps.pr.GetVisiblePost(ctx, post.GetVisiblePostParams{
+		ID:       arg.ID,
+		ViewerID: arg.ViewerID,
 	})
 	if err != nil {
+		logSQLError(ctx, "GetVisiblePost", err)
 		return result, err
 	}
 
-	err = ps.ptr.DeletePostTag(ctx, arg.ID)
+	from, err := ps.pr.GetPostRevision(ctx, post.GetPostRevisionParams{
+		PostID:   arg.ID,
+		Revision: arg.From,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetPostRevision", err)
+		return result, err
+	}
+	to, err := ps.pr.GetPostRevision(ctx, post.GetPostRevisionParams{
+		PostID:   arg.ID,
+		Revision: arg.To,
+	})
 	if err != nil {
+		logSQLError(ctx, "GetPostRevision", err)
 		return result, err
 	}
 
-	var tagIDs []int32
-	for _, tagID := range arg.TagID {
-		resPostTag, err := ps.ptr.CreatePostTag(ctx, post_tags.CreatePostTagParams{
-			Postid: arg.ID,
+	added, removed := diffIDs(from.TagIds, to.TagIds)
+	result = PostRevisionDiff{
+		PostID:      arg.ID,
+		From:        from.Revision,
+		To:          to.Revision,
+		Title:       diffLines(from.Title, to.Title),
+		Description: diffLines(from.Description, to.Description),
+		TagsAdded:   added,
+		TagsRemoved: removed,
+	}
+	return result, nil
+}
+
+// RevertPost restores the title, description and tags a post had at an
+// earlier revision, recording the revert as a new revision by the admin.
+// Users who are not admins get ErrNotAdmin.
+func (ps *postService) RevertPost(ctx context.Context, arg RevertPostParams) (UpdatePostRow, error) {
+	var result UpdatePostRow = UpdatePostRow{}
+	err := ps.tx.InTx(ctx, func(tx TxResources) error {
+		isAdmin, err := tx.User.IsAdmin(ctx, arg.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotAdmin
+		}
+		if err != nil {
+			logSQLError(ctx, "IsAdmin", err)
+			return err
+		}
+		if !isAdmin {
+			return ErrNotAdmin
+		}
+
+		current, err := tx.Post.GetPost(ctx, arg.ID)
+		if err != nil {
+			logSQLError(ctx, "GetPost", err)
+			return err
+		}
+		if arg.Version != 0 && arg.Version != current.Version {
+			return ErrVersionConflict
+		}
+
+		old, err := tx.Post.GetPostRevision(ctx, post.GetPostRevisionParams{
+			PostID:   arg.ID,
+			Revision: arg.Revision,
+		})
+		if err != nil {
+			logSQLError(ctx, "GetPostRevision", err)
+			return err
+		}
+
+		res, err := tx.Post.UpdatePost(ctx, post.UpdatePostParams{
+			ID:          arg.ID,
+			Title:       old.Title,
+			Description: old.Description,
+			Version:     nullVersion(arg.Version),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			logSQLError(ctx, "UpdatePost", err)
+			return err
+		}
+
+		tagIDs, err := replaceTags(ctx, tx, arg.ID, old.TagIds)
+		if isPQError(err, pqForeignKeyViolation) {
+			return ErrTagGone
+		}
+		if err != nil {
+			return err
+		}
+
+		rev, err := addRevision(ctx, tx, arg.ID, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		result = UpdatePostRow{
+			Title:       res.Title,
+			Description: res.Description,
+			TagID:       tagIDs,
+			Edited:      rev.Revision > 1,
+			EditedAt:    revisionEditedAt(rev),
+			Version:     res.Version,
+		}
+		return nil
+	})
+	if err != nil {
+		return UpdatePostRow{}, err
+	}
+
+	return result, nil
+}
+
+// replaceTags swaps the tags of a post for tagIDs and returns the tags it
+// now has. Tags the post keeps are left as they are, so only added and
+// removed tags are written.
+func replaceTags(ctx context.Context, tx TxResources, postID int32, tagIDs []int32) ([]int32, error) {
+	current, err := tx.PostTag.GetPostTagIDs(ctx, postID)
+	if err != nil {
+		logSQLError(ctx, "GetPostTagIDs", err)
+		return nil, err
+	}
+
+	result := uniqueIDs(tagIDs)
+	keep := make(map[int32]bool, len(result))
+	for _, tagID := range result {
+		keep[tagID] = true
+	}
+	has := make(map[int32]bool, len(current))
+	var removed []int32
+	for _, tagID := range current {
+		has[tagID] = true
+		if !keep[tagID] {
+			removed = append(removed, tagID)
+		}
+	}
+
+	if len(removed) > 0 {
+		err = tx.PostTag.DeletePostTagsByTagIDs(ctx, post_tags.DeletePostTagsByTagIDsParams{
+			Postid: postID,
+			TagIds: removed,
+		})
+		if err != nil {
+			logSQLError(ctx, "DeletePostTagsByTagIDs", err)
+			return nil, err
+		}
+	}
+
+	for _, tagID := range result {
+		if has[tagID] {
+			continue
+		}
+		_, err := tx.PostTag.CreatePostTag(ctx, post_tags.CreatePostTagParams{
+			Postid: postID,
 			Tagid:  tagID,
 		})
 		if err != nil {
-			return result, err
+			logSQLError(ctx, "CreatePostTag", err)
+			return nil, err
 		}
-		tagIDs = append(tagIDs, resPostTag.Tagid)
 	}
+	return result, nil
+}
 
-	result = UpdatePostRow{
-		Title:       res.Title,
-		Description: res.Description,
-		TagID:       tagIDs,
+// isAuthor reports whether editorID, where 0 stands for the author, is the
+// author of a post.
+func isAuthor(editorID, authorID int32) bool {
+	return editorID == 0 || editorID == authorID
+}
+
+// addRevision records what a post says now as its next revision, edited by
+// editorID or, when that is 0, by the author. An unknown editor gives
+// sql.ErrNoRows.
+func addRevision(ctx context.Context, tx TxResources, postID, editorID int32) (post.PostRevision, error) {
+	rev, err := tx.Post.CreatePostRevision(ctx, post.CreatePostRevisionParams{
+		EditorID: nullInt32(editorID),
+		PostID:   postID,
+	})
+	if isPQError(err, pqForeignKeyViolation) {
+		return rev, sql.ErrNoRows
 	}
+	if err != nil {
+		logSQLError(ctx, "CreatePostRevision", err)
+		return rev, err
+	}
+	return rev, nil
+}
 
+// attachMedia attaches media to a post in the order given, ignoring repeats.
+// Media that does not exist or belongs to someone other than the author of
+// the post gives ErrMediaNotOwned.
+func attachMedia(ctx context.Context, tx TxResources, postID int32, mediaIDs []int32) ([]int32, error) {
+	var result []int32
+	for i, mediaID := range uniqueIDs(mediaIDs) {
+		n, err := tx.Media.AttachPostMedia(ctx, media.AttachPostMediaParams{
+			Position: int32(i),
+			PostID:   postID,
+			MediaID:  mediaID,
+		})
+		if err != nil {
+			logSQLError(ctx, "AttachPostMedia", err)
+			return nil, err
+		}
+		if n == 0 {
+			return nil, ErrMediaNotOwned
+		}
+		result = append(result, mediaID)
+	}
 	return result, nil
 }
 
-func (ps *postService) DeletePost(ctx context.Context, id int32) error {
-	_, err := ps.pr.GetPost(ctx, id)
+// revisionEditedAt is when rev edited its post, or nil for the revision the
+// post was created with.
+func revisionEditedAt(rev post.PostRevision) *time.Time {
+	if rev.Revision <= 1 {
+		return nil
+	}
+	return &rev.CreatedAt
+}
+
+func toPostRevision(rev post.PostRevision) PostRevision {
+	return PostRevision{
+		PostID:      rev.PostID,
+		Revision:    rev.Revision,
+		Title:       rev.Title,
+		Description: rev.Description,
+		TagIDs:      rev.TagIds,
+		EditorID:    rev.EditorID.Int32,
+		CreatedAt:   rev.CreatedAt,
+	}
+}
+
+func (ps *postService) getTags(ctx context.Context, postID int32) ([]GetTagByPostIDRow, error) {
+	res, err := ps.tr.GetTagByPostID(ctx, postID)
 	if err != nil {
-		return err
+		logSQLError(ctx, "GetTagByPostID", err)
+		return nil, err
 	}
 
-	err = ps.ptr.DeletePostTag(ctx, id)
+	var tags = []GetTagByPostIDRow{}
+	for _, tag := range res {
+		tags = append(tags, GetTagByPostIDRow{
+			ID:      tag.ID,
+			Tagname: tag.Tagname,
+		})
+	}
+	return tags, nil
+}
+
+// getTagsByPostIDs loads the tags of many posts in one query. Every id gets
+// an entry, empty when the post has no tags.
+func (ps *postService) getTagsByPostIDs(ctx context.Context, postIDs []int32) (map[int32][]GetTagByPostIDRow, error) {
+	tags := make(map[int32][]GetTagByPostIDRow, len(postIDs))
+	if len(postIDs) == 0 {
+		return tags, nil
+	}
+	for _, id := range postIDs {
+		tags[id] = []GetTagByPostIDRow{}
+	}
+
+	res, err := ps.tr.GetTagsByPostIDs(ctx, postIDs)
 	if err != nil {
-		return err
+		logSQLError(ctx, "GetTagsByPostIDs", err)
+		return nil, err
+	}
+	for _, tag := range res {
+		tags[tag.Postid] = append(tags[tag.Postid], GetTagByPostIDRow{
+			ID:      tag.ID,
+			Tagname: tag.Tagname,
+		})
+	}
+	return tags, nil
+}
+
+// fillPosts completes a page of posts with their tags, whether viewerID
+// saved them, and the posts they repost or quote as far as viewerID may see
+// them. Each of these is one query for the whole page, whatever its size.
+func (ps *postService) fillPosts(ctx context.Context, viewerID int32, posts []GetPostsRow) error {
+	ids := make([]int32, 0, len(posts))
+	var embeddedIDs []int32
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+		if p.repostOfID != 0 {
+			embeddedIDs = append(embeddedIDs, p.repostOfID)
+		}
+		if p.quoteOfID != 0 {
+			embeddedIDs = append(embeddedIDs, p.quoteOfID)
+		}
 	}
 
-	err = ps.pr.DeletePost(ctx, id)
+	embedded, err := ps.getEmbeddedPosts(ctx, viewerID, uniqueIDs(embeddedIDs))
+	if err != nil {
+		return err
+	}
+	tagIDs := append([]int32{}, ids...)
+	for _, id := range embeddedIDs {
+		if embedded[id] != nil {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+	tags, err := ps.getTagsByPostIDs(ctx, uniqueIDs(tagIDs))
+	if err != nil {
+		return err
+	}
+	for id, p := range embedded {
+		p.Tags = tags[id]
+	}
+	bookmarked, err := ps.getBookmarkedPostIDs(ctx, viewerID, ids)
 	if err != nil {
 		return err
 	}
 
+	for i := range posts {
+		p := &posts[i]
+		p.Tags = tags[p.ID]
+		p.Bookmarked = bookmarked[p.ID]
+		p.RepostOf = embedded[p.repostOfID]
+		p.QuoteOf = embedded[p.quoteOfID]
+	}
 	return nil
 }
+
+// getEmbeddedPosts loads the originals of reposts and quotes in one query.
+// Posts that are gone or hidden from viewerID have no entry.
+func (ps *postService) getEmbeddedPosts(ctx context.Context, viewerID int32, postIDs []int32) (map[int32]*EmbeddedPost, error) {
+	posts := make(map[int32]*EmbeddedPost, len(postIDs))
+	if len(postIDs) == 0 {
+		return posts, nil
+	}
+
+	res, err := ps.pr.GetPostsByIDs(ctx, post.GetPostsByIDsParams{
+		Ids:      postIDs,
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetPostsByIDs", err)
+		return nil, err
+	}
+	for _, item := range res {
+		posts[item.ID] = &EmbeddedPost{
+			ID:            item.ID,
+			Userid:        item.Userid,
+			Title:         item.Title,
+			Description:   item.Description,
+			ReactionCount: item.ReactionCount,
+			RepostCount:   item.RepostCount,
+			QuoteCount:    item.QuoteCount,
+			ReplyCount:    item.ReplyCount,
+			Edited:        item.EditedAt.Valid,
+			EditedAt:      timePtr(item.EditedAt),
+		}
+	}
+	return posts, nil
+}
+
+// getBookmarkedPostIDs loads in one query which of postIDs the viewer has
+// saved. Nothing is loaded when viewerID is 0.
+func (ps *postService) getBookmarkedPostIDs(ctx context.Context, viewerID int32, postIDs []int32) (map[int32]bool, error) {
+	bookmarked := make(map[int32]bool, len(postIDs))
+	if viewerID == 0 || len(postIDs) == 0 {
+		return bookmarked, nil
+	}
+
+	res, err := ps.br.GetBookmarkedPostIDs(ctx, bookmark.GetBookmarkedPostIDsParams{
+		UserID:  viewerID,
+		PostIds: postIDs,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetBookmarkedPostIDs", err)
+		return nil, err
+	}
+	for _, id := range res {
+		bookmarked[id] = true
+	}
+	return bookmarked, nil
+}
+
+// uniqueIDs drops repeated ids, keeping the first occurrence, and returns nil
+// for an empty list so that the query treats it as "no filter".
+func uniqueIDs(ids []int32) []int32 {
+	if len(ids) == 0 {
+		return nil
+	}
+	seen := make(map[int32]bool, len(ids))
+	res := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
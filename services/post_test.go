@@ -2,14 +2,19 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/gadhittana01/socialmedia/pkg/media"
 	"github.com/gadhittana01/socialmedia/pkg/post"
 	"github.com/gadhittana01/socialmedia/pkg/post_tags"
 	"github.com/gadhittana01/socialmedia/pkg/tag"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 )
 
 func TestNewPostService(t *testing.T) {
@@ -18,11 +23,17 @@ func TestNewPostService(t *testing.T) {
 	postMock := NewMockPostResource(ctrl)
 	postTagMock := NewMockPostTagResource(ctrl)
 	tagMock := NewMockTagResource(ctrl)
+	bookmarkMock := NewMockBookmarkResource(ctrl)
+	mediaMock := NewMockMediaResource(ctrl)
+	txMock := NewMockTxRunner(ctrl)
 
 	type args struct {
 		PR  PostResource
 		TR  TagResource
 		PTR PostTagResource
+		BR  BookmarkResource
+		MR  MediaResource
+		TX  TxRunner
 	}
 	tests := []struct {
 		name    string
@@ -36,18 +47,24 @@ func TestNewPostService(t *testing.T) {
 				PR:  postMock,
 				TR:  tagMock,
 				PTR: postTagMock,
+				BR:  bookmarkMock,
+				MR:  mediaMock,
+				TX:  txMock,
 			},
 			want: &postService{
 				pr:  postMock,
 				tr:  tagMock,
 				ptr: postTagMock,
+				br:  bookmarkMock,
+				mr:  mediaMock,
+				tx:  txMock,
 			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewPostService(tt.args.PR, tt.args.TR, tt.args.PTR)
+			got, err := NewPostService(tt.args.PR, tt.args.TR, tt.args.PTR, tt.args.BR, tt.args.MR, tt.args.TX)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewPostService() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -94,11 +111,15 @@ func Test_CreatePost(t *testing.T) {
 					Userid:      1,
 					Title:       "holiday yay",
 					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
 				}).Return(post.CreatePostRow{
 					ID:          1,
 					Userid:      1,
 					Title:       "holiday yay",
 					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
 				}, nil)
 
 				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
@@ -128,10 +149,15 @@ func Test_CreatePost(t *testing.T) {
 					Tagid:  3,
 				}, nil)
 
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 1}, nil)
+
 				return &postService{
 					pr:  postMock,
 					tr:  tagMock,
 					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
 				}
 			},
 			want: CreatePostRow{
@@ -140,6 +166,8 @@ func Test_CreatePost(t *testing.T) {
 				Title:       "holiday yay",
 				Description: "Yes Holiday",
 				TagID:       []int32{1, 2, 3},
+				Visibility:  PostVisibilityPublic,
+				Status:      PostStatusPublished,
 			},
 			wantErr: false,
 		},
@@ -163,12 +191,15 @@ func Test_CreatePost(t *testing.T) {
 					Userid:      1,
 					Title:       "holiday yay",
 					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
 				}).Return(post.CreatePostRow{}, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
 					tr:  tagMock,
 					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
 				}
 			},
 			want:    CreatePostRow{},
@@ -194,11 +225,15 @@ func Test_CreatePost(t *testing.T) {
 					Userid:      1,
 					Title:       "holiday yay",
 					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
 				}).Return(post.CreatePostRow{
 					ID:          1,
 					Userid:      1,
 					Title:       "holiday yay",
 					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
 				}, nil)
 
 				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
@@ -210,6 +245,275 @@ func Test_CreatePost(t *testing.T) {
 					pr:  postMock,
 					tr:  tagMock,
 					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    CreatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "success create followers-only reply",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      2,
+					Title:       "Re: holiday",
+					Description: "Take me with you",
+					InReplyToID: 1,
+					Visibility:  PostVisibilityFollowers,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), post.CreatePostParams{
+					Userid:      2,
+					Title:       "Re: holiday",
+					Description: "Take me with you",
+					InReplyToID: sql.NullInt32{Int32: 1, Valid: true},
+					Visibility:  post.PostVisibilityFollowers,
+					Status:      post.PostStatusPublished,
+				}).Return(post.CreatePostRow{
+					ID:          2,
+					Userid:      2,
+					Title:       "Re: holiday",
+					Description: "Take me with you",
+					InReplyToID: sql.NullInt32{Int32: 1, Valid: true},
+					Visibility:  post.PostVisibilityFollowers,
+					Status:      post.PostStatusPublished,
+				}, nil)
+
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 2,
+				}).Return(post.PostRevision{PostID: 2, Revision: 1}, nil)
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want: CreatePostRow{
+				ID:          2,
+				Userid:      2,
+				Title:       "Re: holiday",
+				Description: "Take me with you",
+				InReplyToID: 1,
+				Visibility:  PostVisibilityFollowers,
+				Status:      PostStatusPublished,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success create scheduled post",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					Status:      PostStatusScheduled,
+					PublishAt:   time.Date(2023, 5, 1, 9, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), post.CreatePostParams{
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusScheduled,
+					PublishAt:   sql.NullTime{Time: time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC), Valid: true},
+				}).Return(post.CreatePostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusScheduled,
+					PublishAt:   sql.NullTime{Time: time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC), Valid: true},
+				}, nil)
+
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 1}, nil)
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want: CreatePostRow{
+				ID:          1,
+				Userid:      1,
+				Title:       "holiday yay",
+				Description: "Yes Holiday",
+				Visibility:  PostVisibilityPublic,
+				Status:      PostStatusScheduled,
+				PublishAt:   timePtr(sql.NullTime{Time: time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC), Valid: true}),
+			},
+			wantErr: false,
+		},
+		{
+			name: "scheduled post without a publish time",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					Status:      PostStatusScheduled,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    CreatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "reply to a missing post",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      2,
+					Title:       "Re: holiday",
+					Description: "Take me with you",
+					InReplyToID: 9,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(post.CreatePostRow{}, &pq.Error{Code: pqForeignKeyViolation})
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    CreatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "reply to a post by a blocked user",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      2,
+					Title:       "Re: holiday",
+					Description: "Take me with you",
+					InReplyToID: 9,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(post.CreatePostRow{}, &pq.Error{Code: pqBlocked})
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    CreatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "success create post with media",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					MediaIDs:    []int32{5, 4, 5},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				mediaMock := NewMockMediaResource(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), post.CreatePostParams{
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
+				}).Return(post.CreatePostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					Visibility:  post.PostVisibilityPublic,
+					Status:      post.PostStatusPublished,
+				}, nil)
+
+				gomock.InOrder(
+					mediaMock.EXPECT().AttachPostMedia(gomock.Any(), media.AttachPostMediaParams{
+						Position: 0,
+						PostID:   1,
+						MediaID:  5,
+					}).Return(int64(1), nil),
+					mediaMock.EXPECT().AttachPostMedia(gomock.Any(), media.AttachPostMediaParams{
+						Position: 1,
+						PostID:   1,
+						MediaID:  4,
+					}).Return(int64(1), nil),
+				)
+
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 1}, nil)
+
+				return &postService{
+					pr: postMock,
+					mr: mediaMock,
+					tx: inlineTx{Post: postMock, Media: mediaMock},
+				}
+			},
+			want: CreatePostRow{
+				ID:          1,
+				Userid:      1,
+				Title:       "holiday yay",
+				Description: "Yes Holiday",
+				Visibility:  PostVisibilityPublic,
+				Status:      PostStatusPublished,
+				MediaIDs:    []int32{5, 4},
+			},
+			wantErr: false,
+		},
+		{
+			name: "media of another user",
+			args: args{
+				ctx: ctx,
+				arg: CreatePostParams{
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "Yes Holiday",
+					MediaIDs:    []int32{9},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				mediaMock := NewMockMediaResource(ctrl)
+
+				postMock.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(post.CreatePostRow{ID: 1, Userid: 1}, nil)
+				mediaMock.EXPECT().AttachPostMedia(gomock.Any(), media.AttachPostMediaParams{
+					Position: 0,
+					PostID:   1,
+					MediaID:  9,
+				}).Return(int64(0), nil)
+
+				return &postService{
+					pr: postMock,
+					mr: mediaMock,
+					tx: inlineTx{Post: postMock, Media: mediaMock},
 				}
 			},
 			want:    CreatePostRow{},
@@ -237,6 +541,7 @@ func Test_GetPosts(t *testing.T) {
 
 	type args struct {
 		ctx context.Context
+		arg GetPostsParams
 	}
 	tests := []struct {
 		name    string
@@ -255,7 +560,7 @@ func Test_GetPosts(t *testing.T) {
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPosts(gomock.Any()).Return([]post.GetPostsRow{
+				postMock.EXPECT().GetPosts(gomock.Any(), post.GetPostsParams{Sort: PostSortNewest}).Return([]post.GetPostsRow{
 					{
 						ID:          1,
 						Userid:      1,
@@ -270,23 +575,24 @@ func Test_GetPosts(t *testing.T) {
 					},
 				}, nil)
 
-				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1, 2}).Return([]tag.GetTagsByPostIDsRow{
 					{
+						Postid:  1,
 						ID:      1,
 						Tagname: "holiday",
 					},
 					{
+						Postid:  1,
 						ID:      2,
 						Tagname: "reading",
 					},
-				}, nil)
-
-				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(2)).Return([]tag.GetTagByPostIDRow{
 					{
+						Postid:  2,
 						ID:      2,
 						Tagname: "reading",
 					},
 					{
+						Postid:  2,
 						ID:      3,
 						Tagname: "shopping",
 					},
@@ -344,7 +650,7 @@ func Test_GetPosts(t *testing.T) {
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPosts(gomock.Any()).Return([]post.GetPostsRow{}, errors.New("error"))
+				postMock.EXPECT().GetPosts(gomock.Any(), post.GetPostsParams{Sort: PostSortNewest}).Return([]post.GetPostsRow{}, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
@@ -356,7 +662,7 @@ func Test_GetPosts(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "error get tag by post id",
+			name: "error get tags by post ids",
 			args: args{
 				ctx: ctx,
 			},
@@ -365,7 +671,7 @@ func Test_GetPosts(t *testing.T) {
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPosts(gomock.Any()).Return([]post.GetPostsRow{
+				postMock.EXPECT().GetPosts(gomock.Any(), post.GetPostsParams{Sort: PostSortNewest}).Return([]post.GetPostsRow{
 					{
 						ID:          1,
 						Userid:      1,
@@ -380,7 +686,7 @@ func Test_GetPosts(t *testing.T) {
 					},
 				}, nil)
 
-				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{}, errors.New("error"))
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1, 2}).Return(nil, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
@@ -391,166 +697,88 @@ func Test_GetPosts(t *testing.T) {
 			want:    []GetPostsRow{},
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			p := tt.mock()
-			got, err := p.GetPosts(tt.args.ctx)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetPosts() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetPosts() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func Test_UpdatePost(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	ctx := context.Background()
-
-	type args struct {
-		ctx context.Context
-		arg UpdatePostParams
-	}
-	tests := []struct {
-		name    string
-		args    args
-		mock    func() *postService
-		want    UpdatePostRow
-		wantErr bool
-	}{
 		{
-			name: "success update post",
+			name: "bookmarked flags for the viewer",
 			args: args{
 				ctx: ctx,
-				arg: UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-					TagID:       []int32{1, 2, 3},
-				},
+				arg: GetPostsParams{ViewerID: 3},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
+				bookmarkMock := NewMockBookmarkResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}).Return(post.UpdatePostRow{
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postTagMock.EXPECT().DeletePostTag(gomock.Any(), int32(1))
-
-				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
-					Postid: 1,
-					Tagid:  1,
-				}).Return(post_tags.CreatePostTagRow{
-					ID:     1,
-					Postid: 1,
-					Tagid:  1,
-				}, nil)
-
-				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
-					Postid: 1,
-					Tagid:  2,
-				}).Return(post_tags.CreatePostTagRow{
-					ID:     2,
-					Postid: 1,
-					Tagid:  2,
-				}, nil)
-
-				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
-					Postid: 1,
-					Tagid:  3,
-				}).Return(post_tags.CreatePostTagRow{
-					ID:     3,
-					Postid: 1,
-					Tagid:  3,
+				postMock.EXPECT().GetPosts(gomock.Any(), post.GetPostsParams{ViewerID: 3, Sort: PostSortNewest}).Return([]post.GetPostsRow{
+					{ID: 1},
+					{ID: 2},
 				}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1, 2}).Return(nil, nil)
+				bookmarkMock.EXPECT().GetBookmarkedPostIDs(gomock.Any(), bookmark.GetBookmarkedPostIDsParams{
+					UserID:  3,
+					PostIds: []int32{1, 2},
+				}).Return([]int32{2}, nil)
 
 				return &postService{
 					pr:  postMock,
 					tr:  tagMock,
 					ptr: postTagMock,
+					br:  bookmarkMock,
 				}
 			},
-			want: UpdatePostRow{
-				Title:       "holiday yay",
-				Description: "yay yay yay",
-				TagID:       []int32{1, 2, 3},
+			want: []GetPostsRow{
+				{ID: 1, Tags: []GetTagByPostIDRow{}},
+				{ID: 2, Tags: []GetTagByPostIDRow{}, Bookmarked: true},
 			},
 			wantErr: false,
 		},
 		{
-			name: "error get post",
+			name: "error get bookmarked post ids",
 			args: args{
 				ctx: ctx,
-				arg: UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-					TagID:       []int32{1, 2, 3},
-				},
+				arg: GetPostsParams{ViewerID: 3},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
+				bookmarkMock := NewMockBookmarkResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{}, errors.New("error"))
+				postMock.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return([]post.GetPostsRow{{ID: 1}}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1}).Return(nil, nil)
+				bookmarkMock.EXPECT().GetBookmarkedPostIDs(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
 					tr:  tagMock,
 					ptr: postTagMock,
+					br:  bookmarkMock,
 				}
 			},
-			want:    UpdatePostRow{},
+			want:    []GetPostsRow{},
 			wantErr: true,
 		},
 		{
-			name: "error update post",
+			name: "embeds reposted and quoted posts",
 			args: args{
 				ctx: ctx,
-				arg: UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-					TagID:       []int32{1, 2, 3},
-				},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
+				postMock.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return([]post.GetPostsRow{
+					{ID: 5, Userid: 2, RepostOfID: sql.NullInt32{Int32: 1, Valid: true}},
+					{ID: 6, Userid: 3, Description: "so true", QuoteOfID: sql.NullInt32{Int32: 4, Valid: true}, InReplyToID: sql.NullInt32{Int32: 1, Valid: true}},
+					{ID: 1, Userid: 1, Title: "Book A", RepostCount: 1, ReplyCount: 1},
+				}, nil)
+				postMock.EXPECT().GetPostsByIDs(gomock.Any(), post.GetPostsByIDsParams{Ids: []int32{1, 4}}).Return([]post.GetPostsByIDsRow{
+					{ID: 1, Userid: 1, Title: "Book A", RepostCount: 1},
+				}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{5, 6, 1}).Return([]tag.GetTagsByPostIDsRow{
+					{Postid: 1, ID: 1, Tagname: "holiday"},
 				}, nil)
-
-				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}).Return(post.UpdatePostRow{}, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
@@ -558,90 +786,53 @@ func Test_UpdatePost(t *testing.T) {
 					ptr: postTagMock,
 				}
 			},
-			want:    UpdatePostRow{},
-			wantErr: true,
-		},
-		{
-			name: "error delete post tag",
-			args: args{
-				ctx: ctx,
-				arg: UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-					TagID:       []int32{1, 2, 3},
+			want: []GetPostsRow{
+				{
+					ID:     5,
+					Userid: 2,
+					Tags:   []GetTagByPostIDRow{},
+					RepostOf: &EmbeddedPost{
+						ID:          1,
+						Userid:      1,
+						Title:       "Book A",
+						Tags:        []GetTagByPostIDRow{{ID: 1, Tagname: "holiday"}},
+						RepostCount: 1,
+					},
+					repostOfID: 1,
 				},
-			},
-			mock: func() *postService {
-				postMock := NewMockPostResource(ctrl)
-				postTagMock := NewMockPostTagResource(ctrl)
-				tagMock := NewMockTagResource(ctrl)
-
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+				{
+					ID:          6,
+					Userid:      3,
+					Description: "so true",
+					Tags:        []GetTagByPostIDRow{},
+					InReplyToID: 1,
+					quoteOfID:   4,
+				},
+				{
 					ID:          1,
 					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}).Return(post.UpdatePostRow{
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postTagMock.EXPECT().DeletePostTag(gomock.Any(), int32(1)).Return(errors.New("error"))
-
-				return &postService{
-					pr:  postMock,
-					tr:  tagMock,
-					ptr: postTagMock,
-				}
+					Title:       "Book A",
+					Tags:        []GetTagByPostIDRow{{ID: 1, Tagname: "holiday"}},
+					RepostCount: 1,
+					ReplyCount:  1,
+				},
 			},
-			want:    UpdatePostRow{},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "error create post tag",
+			name: "error get posts by ids",
 			args: args{
 				ctx: ctx,
-				arg: UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-					TagID:       []int32{1, 2, 3},
-				},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
-					ID:          1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}).Return(post.UpdatePostRow{
-					Title:       "holiday yay",
-					Description: "yay yay yay",
+				postMock.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return([]post.GetPostsRow{
+					{ID: 5, RepostOfID: sql.NullInt32{Int32: 1, Valid: true}},
 				}, nil)
-
-				postTagMock.EXPECT().DeletePostTag(gomock.Any(), int32(1))
-
-				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
-					Postid: 1,
-					Tagid:  1,
-				}).Return(post_tags.CreatePostTagRow{}, errors.New("error"))
+				postMock.EXPECT().GetPostsByIDs(gomock.Any(), post.GetPostsByIDsParams{Ids: []int32{1}}).Return(nil, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
@@ -649,81 +840,262 @@ func Test_UpdatePost(t *testing.T) {
 					ptr: postTagMock,
 				}
 			},
-			want:    UpdatePostRow{},
+			want:    []GetPostsRow{},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.mock()
-			got, err := p.UpdatePost(tt.args.ctx, tt.args.arg)
+			got, err := p.GetPosts(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("UpdatePost() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("GetPosts() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("UpdatePost() = %v, want %v", got, tt.want)
+				t.Errorf("GetPosts() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_DeletePost(t *testing.T) {
+func Test_GetPosts_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		arg  GetPostsParams
+		want post.GetPostsParams
+	}{
+		{
+			name: "defaults to newest",
+			want: post.GetPostsParams{Sort: PostSortNewest},
+		},
+		{
+			name: "any tags are deduplicated",
+			arg:  GetPostsParams{TagIDs: []int32{1, 2, 1}, Sort: PostSortOldest},
+			want: post.GetPostsParams{TagIds: []int32{1, 2}, Sort: PostSortOldest},
+		},
+		{
+			name: "all tags",
+			arg:  GetPostsParams{TagIDs: []int32{3, 3, 4}, MatchAllTags: true},
+			want: post.GetPostsParams{TagIds: []int32{3, 4}, MatchAllTags: true, Sort: PostSortNewest},
+		},
+		{
+			name: "user and date range",
+			arg:  GetPostsParams{UserID: 7, CreatedFrom: from, CreatedTo: to},
+			want: post.GetPostsParams{
+				UserID:      sql.NullInt32{Int32: 7, Valid: true},
+				CreatedFrom: sql.NullTime{Time: from, Valid: true},
+				CreatedTo:   sql.NullTime{Time: to, Valid: true},
+				Sort:        PostSortNewest,
+			},
+		},
+		{
+			name: "most reacted",
+			arg:  GetPostsParams{Sort: PostSortMostReacted},
+			want: post.GetPostsParams{Sort: PostSortMostReacted},
+		},
+		{
+			name: "cursor and limit",
+			arg:  GetPostsParams{CursorCreatedAt: from, CursorID: 9, Limit: 20},
+			want: post.GetPostsParams{
+				CursorCreatedAt: sql.NullTime{Time: from, Valid: true},
+				CursorID:        9,
+				Sort:            PostSortNewest,
+				Limit:           20,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postMock := NewMockPostResource(ctrl)
+			tagMock := NewMockTagResource(ctrl)
+
+			postMock.EXPECT().GetPosts(gomock.Any(), tt.want).Return([]post.GetPostsRow{
+				{
+					ID:            1,
+					Userid:        7,
+					ReactionCount: 2,
+				},
+			}, nil)
+			tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1}).Return(nil, nil)
+
+			p := &postService{
+				pr:  postMock,
+				tr:  tagMock,
+				ptr: NewMockPostTagResource(ctrl),
+			}
+			got, err := p.GetPosts(ctx, tt.arg)
+			if err != nil {
+				t.Fatalf("GetPosts() error = %v", err)
+			}
+			want := []GetPostsRow{
+				{
+					ID:            1,
+					Userid:        7,
+					Tags:          []GetTagByPostIDRow{},
+					ReactionCount: 2,
+				},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetPosts() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_SearchPosts(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	type args struct {
 		ctx context.Context
-		id  int32
+		arg SearchPostsParams
 	}
 	tests := []struct {
 		name    string
 		args    args
 		mock    func() *postService
+		want    []SearchPostsRow
 		wantErr bool
 	}{
 		{
-			name: "success delete post",
+			name: "success search posts",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: SearchPostsParams{
+					Query:       "book",
+					UserID:      1,
+					CreatedFrom: from,
+					ViewerID:    3,
+					Limit:       20,
+				},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
+				bookmarkMock := NewMockBookmarkResource(ctrl)
+
+				postMock.EXPECT().SearchPosts(gomock.Any(), post.SearchPostsParams{
+					Query:       "book",
+					UserID:      sql.NullInt32{Int32: 1, Valid: true},
+					CreatedFrom: sql.NullTime{Time: from, Valid: true},
+					ViewerID:    3,
+					Limit:       20,
+				}).Return([]post.SearchPostsRow{
+					{
+						ID:          2,
+						Userid:      1,
+						Title:       "Book B",
+						Description: "This is book B",
+						QuoteCount:  1,
+						QuoteOfID:   sql.NullInt32{Int32: 5, Valid: true},
+						Visibility:  post.PostVisibilityPublic,
+						Snippet:     "This is <mark>book</mark> B",
+						Rank:        0.6,
+					},
+					{
+						ID:          1,
+						Userid:      1,
+						Title:       "Book A",
+						Description: "This is book A",
+						Visibility:  post.PostVisibilityPublic,
+						Snippet:     "This is <mark>book</mark> A",
+						Rank:        0.3,
+					},
+				}, nil)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
+				postMock.EXPECT().GetPostsByIDs(gomock.Any(), post.GetPostsByIDsParams{Ids: []int32{5}, ViewerID: 3}).Return([]post.GetPostsByIDsRow{
+					{
+						ID:          5,
+						Userid:      2,
+						Title:       "Book C",
+						Description: "This is book C",
+					},
 				}, nil)
 
-				postTagMock.EXPECT().DeletePostTag(gomock.Any(), int32(1))
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{2, 1, 5}).Return([]tag.GetTagsByPostIDsRow{
+					{
+						Postid:  2,
+						ID:      2,
+						Tagname: "reading",
+					},
+				}, nil)
 
-				postMock.EXPECT().DeletePost(gomock.Any(), int32(1)).Return(nil)
+				bookmarkMock.EXPECT().GetBookmarkedPostIDs(gomock.Any(), bookmark.GetBookmarkedPostIDsParams{
+					UserID:  3,
+					PostIds: []int32{2, 1},
+				}).Return([]int32{1}, nil)
 
 				return &postService{
 					pr:  postMock,
 					tr:  tagMock,
 					ptr: postTagMock,
+					br:  bookmarkMock,
 				}
 			},
+			want: []SearchPostsRow{
+				{
+					GetPostsRow: GetPostsRow{
+						ID:          2,
+						Userid:      1,
+						Title:       "Book B",
+						Description: "This is book B",
+						Tags: []GetTagByPostIDRow{
+							{
+								ID:      2,
+								Tagname: "reading",
+							},
+						},
+						QuoteCount: 1,
+						Visibility: PostVisibilityPublic,
+						QuoteOf: &EmbeddedPost{
+							ID:          5,
+							Userid:      2,
+							Title:       "Book C",
+							Description: "This is book C",
+							Tags:        []GetTagByPostIDRow{},
+						},
+						quoteOfID: 5,
+					},
+					Snippet: "This is <mark>book</mark> B",
+					Rank:    0.6,
+				},
+				{
+					GetPostsRow: GetPostsRow{
+						ID:          1,
+						Userid:      1,
+						Title:       "Book A",
+						Description: "This is book A",
+						Tags:        []GetTagByPostIDRow{},
+						Visibility:  PostVisibilityPublic,
+						Bookmarked:  true,
+					},
+					Snippet: "This is <mark>book</mark> A",
+					Rank:    0.3,
+				},
+			},
 			wantErr: false,
 		},
 		{
-			name: "error get post",
+			name: "no results",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: SearchPostsParams{Query: "book", Limit: 20},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{}, errors.New("error"))
+				postMock.EXPECT().SearchPosts(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 				return &postService{
 					pr:  postMock,
@@ -731,27 +1103,21 @@ func Test_DeletePost(t *testing.T) {
 					ptr: postTagMock,
 				}
 			},
-			wantErr: true,
+			want:    []SearchPostsRow{},
+			wantErr: false,
 		},
 		{
-			name: "error delete post tag",
+			name: "error search posts",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: SearchPostsParams{Query: "book", Limit: 20},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postTagMock.EXPECT().DeletePostTag(gomock.Any(), int32(1)).Return(errors.New("error"))
+				postMock.EXPECT().SearchPosts(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
@@ -759,29 +1125,22 @@ func Test_DeletePost(t *testing.T) {
 					ptr: postTagMock,
 				}
 			},
+			want:    []SearchPostsRow{},
 			wantErr: true,
 		},
 		{
-			name: "error delete post",
+			name: "error get tags by post ids",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: SearchPostsParams{Query: "book", Limit: 20},
 			},
 			mock: func() *postService {
 				postMock := NewMockPostResource(ctrl)
 				postTagMock := NewMockPostTagResource(ctrl)
 				tagMock := NewMockTagResource(ctrl)
 
-				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
-					ID:          1,
-					Userid:      1,
-					Title:       "holiday yay",
-					Description: "yay yay yay",
-				}, nil)
-
-				postTagMock.EXPECT().DeletePostTag(gomock.Any(), int32(1))
-
-				postMock.EXPECT().DeletePost(gomock.Any(), int32(1)).Return(errors.New("error"))
+				postMock.EXPECT().SearchPosts(gomock.Any(), gomock.Any()).Return([]post.SearchPostsRow{{ID: 1}}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1}).Return(nil, errors.New("error"))
 
 				return &postService{
 					pr:  postMock,
@@ -789,17 +1148,2273 @@ func Test_DeletePost(t *testing.T) {
 					ptr: postTagMock,
 				}
 			},
+			want:    []SearchPostsRow{},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.mock()
-			err := p.DeletePost(tt.args.ctx, tt.args.id)
+			got, err := p.SearchPosts(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("DeletePost() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("SearchPosts() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchPosts() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
+
+func Test_UpdatePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	editedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	type args struct {
+		ctx context.Context
+		arg UpdatePostParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *postService
+		want    UpdatePostRow
+		wantErr bool
+	}{
+		{
+			name: "success update post",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					TagID:       []int32{1, 2, 3},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}).Return(post.UpdatePostRow{
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1)).Return([]int32{1, 4}, nil)
+
+				postTagMock.EXPECT().DeletePostTagsByTagIDs(gomock.Any(), post_tags.DeletePostTagsByTagIDsParams{
+					Postid: 1,
+					TagIds: []int32{4},
+				})
+
+				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
+					Postid: 1,
+					Tagid:  2,
+				}).Return(post_tags.CreatePostTagRow{
+					ID:     2,
+					Postid: 1,
+					Tagid:  2,
+				}, nil)
+
+				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
+					Postid: 1,
+					Tagid:  3,
+				}).Return(post_tags.CreatePostTagRow{
+					ID:     3,
+					Postid: 1,
+					Tagid:  3,
+				}, nil)
+
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 2, CreatedAt: editedAt}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want: UpdatePostRow{
+				Title:       "holiday yay",
+				Description: "yay yay yay",
+				TagID:       []int32{1, 2, 3},
+				Edited:      true,
+				EditedAt:    &editedAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown editor",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					EditorID:    99,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1, Status: post.PostStatusPublished}, nil)
+				postMock.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).Return(post.UpdatePostRow{Title: "holiday yay"}, nil)
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1))
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					EditorID: sql.NullInt32{Int32: 99, Valid: true},
+					PostID:   1,
+				}).Return(post.PostRevision{}, &pq.Error{Code: pqForeignKeyViolation})
+
+				return &postService{
+					pr:  postMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "draft edited by another user",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					EditorID:    99,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1, Status: post.PostStatusDraft}, nil)
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get post",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					TagID:       []int32{1, 2, 3},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{}, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "error update post",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					TagID:       []int32{1, 2, 3},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}).Return(post.UpdatePostRow{}, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get post tag ids",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					TagID:       []int32{1, 2, 3},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}).Return(post.UpdatePostRow{
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1)).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "error create post tag",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					TagID:       []int32{1, 2, 3},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}).Return(post.UpdatePostRow{
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1))
+
+				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
+					Postid: 1,
+					Tagid:  1,
+				}).Return(post_tags.CreatePostTagRow{}, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+		{
+			name: "error version changed before update",
+			args: args{
+				ctx: ctx,
+				arg: UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					Version:     2,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:      1,
+					Version: 2,
+				}, nil)
+
+				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
+					ID:          1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					Version:     sql.NullInt32{Int32: 2, Valid: true},
+				}).Return(post.UpdatePostRow{}, sql.ErrNoRows)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    UpdatePostRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.UpdatePost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdatePost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpdatePost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DeletePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		arg DeletePostParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *postService
+		wantErr bool
+	}{
+		{
+			name: "success delete post",
+			args: args{
+				ctx: ctx,
+				arg: DeletePostParams{
+					ID: 1,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().DeletePost(gomock.Any(), post.DeletePostParams{
+					ID: 1,
+				}).Return(int64(1), nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get post",
+			args: args{
+				ctx: ctx,
+				arg: DeletePostParams{
+					ID: 1,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{}, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error delete post",
+			args: args{
+				ctx: ctx,
+				arg: DeletePostParams{
+					ID: 1,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().DeletePost(gomock.Any(), post.DeletePostParams{
+					ID: 1,
+				}).Return(int64(0), errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error version conflict",
+			args: args{
+				ctx: ctx,
+				arg: DeletePostParams{
+					ID:      1,
+					Version: 1,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:      1,
+					Version: 2,
+				}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error version changed before delete",
+			args: args{
+				ctx: ctx,
+				arg: DeletePostParams{
+					ID:      1,
+					Version: 2,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:      1,
+					Version: 2,
+				}, nil)
+
+				postMock.EXPECT().DeletePost(gomock.Any(), post.DeletePostParams{
+					ID:      1,
+					Version: sql.NullInt32{Int32: 2, Valid: true},
+				}).Return(int64(0), nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			err := p.DeletePost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeletePost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_PatchPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	title := "holiday yay"
+	private := PostVisibilityPrivate
+	editedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	published := PostStatusPublished
+	draft := PostStatusDraft
+	scheduled := PostStatusScheduled
+
+	type args struct {
+		ctx context.Context
+		arg PatchPostParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *postService
+		want    PatchPostRow
+		wantErr bool
+	}{
+		{
+			name: "success patch title keeps tags",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:    1,
+					Title: &title,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().PatchPost(gomock.Any(), post.PatchPostParams{
+					ID:    1,
+					Title: sql.NullString{String: "holiday yay", Valid: true},
+				}).Return(post.PatchPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 2, CreatedAt: editedAt}, nil)
+
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{
+					{
+						ID:      1,
+						Tagname: "holiday",
+					},
+				}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want: PatchPostRow{
+				ID:          1,
+				Userid:      1,
+				Title:       "holiday yay",
+				Description: "yay yay yay",
+				Edited:      true,
+				EditedAt:    &editedAt,
+				Tags: []GetTagByPostIDRow{
+					{
+						ID:      1,
+						Tagname: "holiday",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success patch visibility",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:         1,
+					Visibility: &private,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1}, nil)
+				postMock.EXPECT().PatchPost(gomock.Any(), post.PatchPostParams{
+					ID:         1,
+					Visibility: post.NullPostVisibility{PostVisibility: post.PostVisibilityPrivate, Valid: true},
+				}).Return(post.PatchPostRow{
+					ID:         1,
+					Userid:     1,
+					Visibility: post.PostVisibilityPrivate,
+				}, nil)
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return(nil, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want: PatchPostRow{
+				ID:         1,
+				Userid:     1,
+				Tags:       []GetTagByPostIDRow{},
+				Visibility: PostVisibilityPrivate,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success publish draft",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:     1,
+					Status: &published,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1, Status: post.PostStatusDraft}, nil)
+				postMock.EXPECT().PatchPost(gomock.Any(), post.PatchPostParams{
+					ID:     1,
+					Status: post.NullPostStatus{PostStatus: post.PostStatusPublished, Valid: true},
+				}).Return(post.PatchPostRow{
+					ID:     1,
+					Userid: 1,
+					Status: post.PostStatusPublished,
+				}, nil)
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return(nil, nil)
+
+				return &postService{
+					pr: postMock,
+					tr: tagMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want: PatchPostRow{
+				ID:     1,
+				Userid: 1,
+				Tags:   []GetTagByPostIDRow{},
+				Status: PostStatusPublished,
+			},
+			wantErr: false,
+		},
+		{
+			name: "un-publish a published post",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:     1,
+					Status: &draft,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1, Status: post.PostStatusPublished}, nil)
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    PatchPostRow{},
+			wantErr: true,
+		},
+		{
+			name: "publish a draft of another user",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:       1,
+					Status:   &published,
+					EditorID: 2,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1, Status: post.PostStatusDraft}, nil)
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    PatchPostRow{},
+			wantErr: true,
+		},
+		{
+			name: "schedule a draft without a publish time",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:     1,
+					Status: &scheduled,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Userid: 1, Status: post.PostStatusDraft}, nil)
+
+				return &postService{
+					pr: postMock,
+					tx: inlineTx{Post: postMock},
+				}
+			},
+			want:    PatchPostRow{},
+			wantErr: true,
+		},
+		{
+			name: "success replace tags",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:          1,
+					ReplaceTags: true,
+					TagID:       []int32{2},
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().PatchPost(gomock.Any(), post.PatchPostParams{
+					ID: 1,
+				}).Return(post.PatchPostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+				}, nil)
+
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 2, CreatedAt: editedAt}, nil)
+
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1)).Return([]int32{1}, nil)
+
+				postTagMock.EXPECT().DeletePostTagsByTagIDs(gomock.Any(), post_tags.DeletePostTagsByTagIDsParams{
+					Postid: 1,
+					TagIds: []int32{1},
+				})
+
+				postTagMock.EXPECT().CreatePostTag(gomock.Any(), post_tags.CreatePostTagParams{
+					Postid: 1,
+					Tagid:  2,
+				}).Return(post_tags.CreatePostTagRow{
+					ID:     1,
+					Postid: 1,
+					Tagid:  2,
+				}, nil)
+
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{
+					{
+						ID:      2,
+						Tagname: "travel",
+					},
+				}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want: PatchPostRow{
+				ID:          1,
+				Userid:      1,
+				Title:       "holiday yay",
+				Description: "yay yay yay",
+				Edited:      true,
+				EditedAt:    &editedAt,
+				Tags: []GetTagByPostIDRow{
+					{
+						ID:      2,
+						Tagname: "travel",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get post",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:    1,
+					Title: &title,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{}, sql.ErrNoRows)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    PatchPostRow{},
+			wantErr: true,
+		},
+		{
+			name: "error delete post tag",
+			args: args{
+				ctx: ctx,
+				arg: PatchPostParams{
+					ID:          1,
+					ReplaceTags: true,
+				},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{
+					ID: 1,
+				}, nil)
+
+				postMock.EXPECT().PatchPost(gomock.Any(), post.PatchPostParams{
+					ID: 1,
+				}).Return(post.PatchPostRow{
+					ID: 1,
+				}, nil)
+
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1)).Return([]int32{1}, nil)
+
+				postTagMock.EXPECT().DeletePostTagsByTagIDs(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					tx:  inlineTx{Post: postMock, PostTag: postTagMock},
+				}
+			},
+			want:    PatchPostRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.PatchPost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchPost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	editedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	type args struct {
+		ctx context.Context
+		arg GetPostParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *postService
+		want    GetPostRow
+		wantErr bool
+	}{
+		{
+			name: "success get post",
+			args: args{
+				ctx: ctx,
+				arg: GetPostParams{ID: 1, ViewerID: 2},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{
+					ID:          1,
+					Userid:      1,
+					Title:       "holiday yay",
+					Description: "yay yay yay",
+					Visibility:  post.PostVisibilityFollowers,
+					Version:     3,
+				}, nil)
+
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{
+					{
+						ID:      1,
+						Tagname: "holiday",
+					},
+				}, nil)
+
+				mediaMock := NewMockMediaResource(ctrl)
+				mediaMock.EXPECT().GetPostMedia(gomock.Any(), int32(1)).Return([]media.GetPostMediaRow{
+					{
+						ID:                   4,
+						ContentType:          "image/jpeg",
+						Size:                 2048,
+						Width:                sql.NullInt32{Int32: 640, Valid: true},
+						Height:               sql.NullInt32{Int32: 480, Valid: true},
+						ThumbnailContentType: sql.NullString{String: "image/jpeg", Valid: true},
+					},
+					{
+						ID:          5,
+						ContentType: "application/pdf",
+						Size:        4096,
+					},
+				}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					mr:  mediaMock,
+				}
+			},
+			want: GetPostRow{
+				ID:          1,
+				Userid:      1,
+				Title:       "holiday yay",
+				Description: "yay yay yay",
+				Tags: []GetTagByPostIDRow{
+					{
+						ID:      1,
+						Tagname: "holiday",
+					},
+				},
+				Visibility: PostVisibilityFollowers,
+				Version:    3,
+				Media: []PostMedia{
+					{
+						ID:           4,
+						ContentType:  "image/jpeg",
+						Size:         2048,
+						Width:        640,
+						Height:       480,
+						URL:          "/media/4",
+						ThumbnailURL: "/media/4/thumbnail",
+					},
+					{
+						ID:          5,
+						ContentType: "application/pdf",
+						Size:        4096,
+						URL:         "/media/5",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success get edited post",
+			args: args{
+				ctx: ctx,
+				arg: GetPostParams{ID: 1, ViewerID: 2},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{
+					ID:         1,
+					Userid:     1,
+					Title:      "holiday yay",
+					Visibility: post.PostVisibilityPublic,
+					EditedAt:   sql.NullTime{Time: editedAt, Valid: true},
+					Version:    2,
+				}, nil)
+
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{}, nil)
+
+				mediaMock := NewMockMediaResource(ctrl)
+				mediaMock.EXPECT().GetPostMedia(gomock.Any(), int32(1)).Return([]media.GetPostMediaRow{}, nil)
+
+				return &postService{
+					pr: postMock,
+					tr: tagMock,
+					mr: mediaMock,
+				}
+			},
+			want: GetPostRow{
+				ID:         1,
+				Userid:     1,
+				Title:      "holiday yay",
+				Tags:       []GetTagByPostIDRow{},
+				Visibility: PostVisibilityPublic,
+				Edited:     true,
+				EditedAt:   &editedAt,
+				Version:    2,
+				Media:      []PostMedia{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get post media",
+			args: args{
+				ctx: ctx,
+				arg: GetPostParams{ID: 1, ViewerID: 2},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+				mediaMock := NewMockMediaResource(ctrl)
+
+				postMock.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{ID: 1}, nil)
+				tagMock.EXPECT().GetTagByPostID(gomock.Any(), int32(1)).Return([]tag.GetTagByPostIDRow{}, nil)
+				mediaMock.EXPECT().GetPostMedia(gomock.Any(), int32(1)).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr: postMock,
+					tr: tagMock,
+					mr: mediaMock,
+				}
+			},
+			want:    GetPostRow{},
+			wantErr: true,
+		},
+		{
+			name: "hidden or missing post",
+			args: args{
+				ctx: ctx,
+				arg: GetPostParams{ID: 1, ViewerID: 2},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{}, sql.ErrNoRows)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    GetPostRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetPost(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetTagFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		arg GetTagFeedParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *postService
+		want    []GetPostsRow
+		wantErr bool
+	}{
+		{
+			name: "success get tag feed",
+			args: args{
+				ctx: ctx,
+				arg: GetTagFeedParams{UserID: 1, Limit: 20, Offset: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+				bookmarkMock := NewMockBookmarkResource(ctrl)
+
+				postMock.EXPECT().GetTagFeed(gomock.Any(), post.GetTagFeedParams{
+					UserID: 1,
+					Limit:  20,
+					Offset: 20,
+				}).Return([]post.GetTagFeedRow{
+					{
+						ID:            2,
+						Userid:        3,
+						Title:         "Beach trip",
+						Description:   "Holiday reading list",
+						ReactionCount: 4,
+					},
+				}, nil)
+
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{2}).Return([]tag.GetTagsByPostIDsRow{
+					{
+						Postid:  2,
+						ID:      1,
+						Tagname: "holiday",
+					},
+					{
+						Postid:  2,
+						ID:      2,
+						Tagname: "reading",
+					},
+				}, nil)
+				bookmarkMock.EXPECT().GetBookmarkedPostIDs(gomock.Any(), bookmark.GetBookmarkedPostIDsParams{
+					UserID:  1,
+					PostIds: []int32{2},
+				}).Return([]int32{2}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					br:  bookmarkMock,
+				}
+			},
+			want: []GetPostsRow{
+				{
+					ID:          2,
+					Userid:      3,
+					Title:       "Beach trip",
+					Description: "Holiday reading list",
+					Tags: []GetTagByPostIDRow{
+						{
+							ID:      1,
+							Tagname: "holiday",
+						},
+						{
+							ID:      2,
+							Tagname: "reading",
+						},
+					},
+					ReactionCount: 4,
+					Bookmarked:    true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "no followed tags",
+			args: args{
+				ctx: ctx,
+				arg: GetTagFeedParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetTagFeed(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: false,
+		},
+		{
+			name: "error get tag feed",
+			args: args{
+				ctx: ctx,
+				arg: GetTagFeedParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetTagFeed(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get tags by post ids",
+			args: args{
+				ctx: ctx,
+				arg: GetTagFeedParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetTagFeed(gomock.Any(), gomock.Any()).Return([]post.GetTagFeedRow{{ID: 1}}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1}).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get bookmarked post ids",
+			args: args{
+				ctx: ctx,
+				arg: GetTagFeedParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+				bookmarkMock := NewMockBookmarkResource(ctrl)
+
+				postMock.EXPECT().GetTagFeed(gomock.Any(), gomock.Any()).Return([]post.GetTagFeedRow{{ID: 1}}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1}).Return(nil, nil)
+				bookmarkMock.EXPECT().GetBookmarkedPostIDs(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					br:  bookmarkMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetTagFeed(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTagFeed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTagFeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetBookmarks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		arg GetBookmarksParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *postService
+		want    []GetPostsRow
+		wantErr bool
+	}{
+		{
+			name: "success get bookmarks",
+			args: args{
+				ctx: ctx,
+				arg: GetBookmarksParams{UserID: 1, CollectionID: 3, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+				bookmarkMock := NewMockBookmarkResource(ctrl)
+
+				postMock.EXPECT().GetBookmarks(gomock.Any(), post.GetBookmarksParams{
+					UserID:       1,
+					CollectionID: sql.NullInt32{Int32: 3, Valid: true},
+					Limit:        20,
+				}).Return([]post.GetBookmarksRow{
+					{
+						ID:          2,
+						Userid:      4,
+						Title:       "Pancakes",
+						Description: "Fluffy pancakes",
+					},
+				}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{2}).Return(nil, nil)
+				bookmarkMock.EXPECT().GetBookmarkedPostIDs(gomock.Any(), bookmark.GetBookmarkedPostIDsParams{
+					UserID:  1,
+					PostIds: []int32{2},
+				}).Return([]int32{2}, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+					br:  bookmarkMock,
+				}
+			},
+			want: []GetPostsRow{
+				{
+					ID:          2,
+					Userid:      4,
+					Title:       "Pancakes",
+					Description: "Fluffy pancakes",
+					Tags:        []GetTagByPostIDRow{},
+					Bookmarked:  true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "every saved post",
+			args: args{
+				ctx: ctx,
+				arg: GetBookmarksParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetBookmarks(gomock.Any(), post.GetBookmarksParams{
+					UserID: 1,
+					Limit:  20,
+				}).Return(nil, nil)
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: false,
+		},
+		{
+			name: "error get bookmarks",
+			args: args{
+				ctx: ctx,
+				arg: GetBookmarksParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetBookmarks(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: true,
+		},
+		{
+			name: "error get tags by post ids",
+			args: args{
+				ctx: ctx,
+				arg: GetBookmarksParams{UserID: 1, Limit: 20},
+			},
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetBookmarks(gomock.Any(), gomock.Any()).Return([]post.GetBookmarksRow{{ID: 1}}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1}).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr:  postMock,
+					tr:  tagMock,
+					ptr: postTagMock,
+				}
+			},
+			want:    []GetPostsRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetBookmarks(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBookmarks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBookmarks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Repost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     RepostParams
+		mock    func() *postService
+		want    RepostRow
+		wantErr error
+	}{
+		{
+			name: "success repost",
+			arg:  RepostParams{UserID: 2, PostID: 5},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(5)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateRepost(gomock.Any(), post.CreateRepostParams{
+					Userid:     2,
+					RepostOfID: 1,
+				}).Return(post.CreateRepostRow{ID: 7, Userid: 2}, nil)
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want: RepostRow{ID: 7, Userid: 2, RepostOfID: 1},
+		},
+		{
+			name: "success quote",
+			arg:  RepostParams{UserID: 2, PostID: 1, Quote: "so true"},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateQuote(gomock.Any(), post.CreateQuoteParams{
+					Userid:      2,
+					Description: "so true",
+					QuoteOfID:   1,
+				}).Return(post.CreateQuoteRow{ID: 8, Userid: 2, Description: "so true"}, nil)
+				m.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					PostID: 8,
+				}).Return(post.PostRevision{PostID: 8, Revision: 1}, nil)
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want: RepostRow{ID: 8, Userid: 2, Description: "so true", QuoteOfID: 1},
+		},
+		{
+			name: "own post",
+			arg:  RepostParams{UserID: 1, PostID: 5},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(5)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: ErrOwnPost,
+		},
+		{
+			name: "post not found",
+			arg:  RepostParams{UserID: 2, PostID: 5},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(5)).Return(post.GetRepostTargetRow{}, sql.ErrNoRows)
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "user not found",
+			arg:  RepostParams{UserID: 9, PostID: 1},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateRepost(gomock.Any(), gomock.Any()).Return(post.CreateRepostRow{}, &pq.Error{Code: pqForeignKeyViolation})
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "repost a post by a blocked user",
+			arg:  RepostParams{UserID: 2, PostID: 1},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateRepost(gomock.Any(), gomock.Any()).Return(post.CreateRepostRow{}, &pq.Error{Code: pqBlocked})
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "quote a post by a blocked user",
+			arg:  RepostParams{UserID: 2, PostID: 1, Quote: "so true"},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateQuote(gomock.Any(), gomock.Any()).Return(post.CreateQuoteRow{}, &pq.Error{Code: pqBlocked})
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "error create repost",
+			arg:  RepostParams{UserID: 2, PostID: 1},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateRepost(gomock.Any(), gomock.Any()).Return(post.CreateRepostRow{}, errors.New("error"))
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "quoted post deleted meanwhile",
+			arg:  RepostParams{UserID: 2, PostID: 1, Quote: "so true"},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateQuote(gomock.Any(), gomock.Any()).Return(post.CreateQuoteRow{}, &pq.Error{Code: pqForeignKeyViolation})
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error create quote",
+			arg:  RepostParams{UserID: 2, PostID: 1, Quote: "so true"},
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetRepostTarget(gomock.Any(), int32(1)).Return(post.GetRepostTargetRow{ID: 1, Userid: 1}, nil)
+				m.EXPECT().CreateQuote(gomock.Any(), gomock.Any()).Return(post.CreateQuoteRow{}, errors.New("error"))
+
+				return &postService{
+					pr: m,
+					tx: inlineTx{Post: m},
+				}
+			},
+			want:    RepostRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.Repost(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Repost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Unrepost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := UnrepostParams{UserID: 2, PostID: 1}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		wantErr bool
+	}{
+		{
+			name: "success unrepost",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().DeleteRepost(gomock.Any(), post.DeleteRepostParams{
+					Userid: 2,
+					PostID: 1,
+				}).Return(nil)
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unrepost",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().DeleteRepost(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			err := p.Unrepost(ctx, arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unrepost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_React(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := ReactParams{UserID: 2, PostID: 1, Kind: ReactionLove}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		wantErr error
+	}{
+		{
+			name: "success react",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().AddReaction(gomock.Any(), post.AddReactionParams{
+					Kind:   "love",
+					PostID: 1,
+					UserID: 2,
+				}).Return(int64(1), nil)
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: nil,
+		},
+		{
+			name: "hidden or missing post",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().AddReaction(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "blocked",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().AddReaction(gomock.Any(), gomock.Any()).Return(int64(0), &pq.Error{Code: pqBlocked})
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "error react",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().AddReaction(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			err := p.React(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("React() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Unreact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := UnreactParams{UserID: 2, PostID: 1}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		wantErr bool
+	}{
+		{
+			name: "success unreact",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().RemoveReaction(gomock.Any(), post.RemoveReactionParams{
+					PostID: 1,
+					UserID: 2,
+				}).Return(nil)
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unreact",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().RemoveReaction(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &postService{
+					pr: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			err := p.Unreact(ctx, arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unreact() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := GetThreadParams{ID: 2, ViewerID: 7, Depth: 3, Limit: 20}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		want    GetThreadRow
+		wantErr error
+	}{
+		{
+			name: "success get thread",
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+
+				postMock.EXPECT().GetReplyChain(gomock.Any(), post.GetReplyChainParams{ID: 2, ViewerID: 7}).Return([]post.GetReplyChainRow{
+					{ID: 1, Userid: 1, Title: "root", ReplyCount: 1},
+					{ID: 2, Userid: 2, Title: "reply", ReplyCount: 2},
+				}, nil)
+				postMock.EXPECT().GetReplyTree(gomock.Any(), post.GetReplyTreeParams{
+					ID:       2,
+					ViewerID: 7,
+					Limit:    20,
+					Depth:    3,
+				}).Return([]post.GetReplyTreeRow{
+					{ID: 3, Userid: 1, InReplyToID: sql.NullInt32{Int32: 2, Valid: true}, ReplyCount: 1},
+					{ID: 4, Userid: 3, InReplyToID: sql.NullInt32{Int32: 2, Valid: true}},
+					{ID: 5, Userid: 2, InReplyToID: sql.NullInt32{Int32: 3, Valid: true}, ReactionCount: 2},
+				}, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{1, 2, 3, 4, 5}).Return([]tag.GetTagsByPostIDsRow{
+					{Postid: 1, ID: 1, Tagname: "holiday"},
+				}, nil)
+
+				return &postService{
+					pr: postMock,
+					tr: tagMock,
+				}
+			},
+			want: GetThreadRow{
+				Ancestors: []ThreadPost{
+					{ID: 1, Userid: 1, Title: "root", Tags: []GetTagByPostIDRow{{ID: 1, Tagname: "holiday"}}, ReplyCount: 1},
+				},
+				Post: ThreadPost{
+					ID:         2,
+					Userid:     2,
+					Title:      "reply",
+					Tags:       []GetTagByPostIDRow{},
+					ReplyCount: 2,
+					Replies: []*ThreadPost{
+						{
+							ID:         3,
+							Userid:     1,
+							Tags:       []GetTagByPostIDRow{},
+							ReplyCount: 1,
+							Replies: []*ThreadPost{
+								{ID: 5, Userid: 2, Tags: []GetTagByPostIDRow{}, ReactionCount: 2},
+							},
+						},
+						{ID: 4, Userid: 3, Tags: []GetTagByPostIDRow{}},
+					},
+				},
+			},
+		},
+		{
+			name: "post not found",
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postMock.EXPECT().GetReplyChain(gomock.Any(), post.GetReplyChainParams{ID: 2, ViewerID: 7}).Return(nil, nil)
+
+				return &postService{
+					pr: postMock,
+				}
+			},
+			want:    GetThreadRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error get reply chain",
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postMock.EXPECT().GetReplyChain(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr: postMock,
+				}
+			},
+			want:    GetThreadRow{},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error get reply tree",
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				postMock.EXPECT().GetReplyChain(gomock.Any(), gomock.Any()).Return([]post.GetReplyChainRow{{ID: 2}}, nil)
+				postMock.EXPECT().GetReplyTree(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr: postMock,
+				}
+			},
+			want:    GetThreadRow{},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error get tags by post ids",
+			mock: func() *postService {
+				postMock := NewMockPostResource(ctrl)
+				tagMock := NewMockTagResource(ctrl)
+				postMock.EXPECT().GetReplyChain(gomock.Any(), gomock.Any()).Return([]post.GetReplyChainRow{{ID: 2}}, nil)
+				postMock.EXPECT().GetReplyTree(gomock.Any(), gomock.Any()).Return(nil, nil)
+				tagMock.EXPECT().GetTagsByPostIDs(gomock.Any(), []int32{2}).Return(nil, errors.New("error"))
+
+				return &postService{
+					pr: postMock,
+					tr: tagMock,
+				}
+			},
+			want:    GetThreadRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetThread(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetThread() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetThread() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPostRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	arg := GetPostRevisionsParams{ID: 1, ViewerID: 2, Limit: 20}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		want    []PostRevision
+		wantErr error
+	}{
+		{
+			name: "success",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{ID: 1}, nil)
+				m.EXPECT().GetPostRevisions(gomock.Any(), post.GetPostRevisionsParams{PostID: 1, Limit: 20}).Return([]post.PostRevision{
+					{PostID: 1, Revision: 2, Title: "holiday yay", TagIds: []int32{1, 2}, EditorID: sql.NullInt32{Int32: 3, Valid: true}, CreatedAt: createdAt},
+					{PostID: 1, Revision: 1, Title: "holiday", TagIds: []int32{}, EditorID: sql.NullInt32{Int32: 1, Valid: true}, CreatedAt: createdAt},
+				}, nil)
+
+				return &postService{pr: m}
+			},
+			want: []PostRevision{
+				{PostID: 1, Revision: 2, Title: "holiday yay", TagIDs: []int32{1, 2}, EditorID: 3, CreatedAt: createdAt},
+				{PostID: 1, Revision: 1, Title: "holiday", TagIDs: []int32{}, EditorID: 1, CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "hidden post",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{}, sql.ErrNoRows)
+
+				return &postService{pr: m}
+			},
+			want:    []PostRevision{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error get revisions",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetVisiblePost(gomock.Any(), gomock.Any()).Return(post.GetVisiblePostRow{ID: 1}, nil)
+				m.EXPECT().GetPostRevisions(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &postService{pr: m}
+			},
+			want:    []PostRevision{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetPostRevisions(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetPostRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostRevisions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetPostRevisionDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	arg := GetPostRevisionDiffParams{ID: 1, ViewerID: 2, From: 1, To: 2}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		want    PostRevisionDiff
+		wantErr error
+	}{
+		{
+			name: "success",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetVisiblePost(gomock.Any(), post.GetVisiblePostParams{ID: 1, ViewerID: 2}).Return(post.GetVisiblePostRow{ID: 1}, nil)
+				m.EXPECT().GetPostRevision(gomock.Any(), post.GetPostRevisionParams{PostID: 1, Revision: 1}).Return(post.PostRevision{
+					PostID: 1, Revision: 1, Title: "holiday", Description: "sun\nsea", TagIds: []int32{1, 2},
+				}, nil)
+				m.EXPECT().GetPostRevision(gomock.Any(), post.GetPostRevisionParams{PostID: 1, Revision: 2}).Return(post.PostRevision{
+					PostID: 1, Revision: 2, Title: "holiday", Description: "sun\nsand", TagIds: []int32{2, 3},
+				}, nil)
+
+				return &postService{pr: m}
+			},
+			want: PostRevisionDiff{
+				PostID: 1,
+				From:   1,
+				To:     2,
+				Title:  []DiffLine{{Op: DiffEqual, Text: "holiday"}},
+				Description: []DiffLine{
+					{Op: DiffEqual, Text: "sun"},
+					{Op: DiffDelete, Text: "sea"},
+					{Op: DiffInsert, Text: "sand"},
+				},
+				TagsAdded:   []int32{3},
+				TagsRemoved: []int32{1},
+			},
+		},
+		{
+			name: "hidden post",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetVisiblePost(gomock.Any(), gomock.Any()).Return(post.GetVisiblePostRow{}, sql.ErrNoRows)
+
+				return &postService{pr: m}
+			},
+			want:    PostRevisionDiff{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "missing revision",
+			mock: func() *postService {
+				m := NewMockPostResource(ctrl)
+				m.EXPECT().GetVisiblePost(gomock.Any(), gomock.Any()).Return(post.GetVisiblePostRow{ID: 1}, nil)
+				m.EXPECT().GetPostRevision(gomock.Any(), post.GetPostRevisionParams{PostID: 1, Revision: 1}).Return(post.PostRevision{PostID: 1, Revision: 1}, nil)
+				m.EXPECT().GetPostRevision(gomock.Any(), post.GetPostRevisionParams{PostID: 1, Revision: 2}).Return(post.PostRevision{}, sql.ErrNoRows)
+
+				return &postService{pr: m}
+			},
+			want:    PostRevisionDiff{},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetPostRevisionDiff(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetPostRevisionDiff() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPostRevisionDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RevertPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	editedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	arg := RevertPostParams{ID: 1, Revision: 1, UserID: 9}
+
+	tests := []struct {
+		name    string
+		mock    func() *postService
+		want    UpdatePostRow
+		wantErr error
+	}{
+		{
+			name: "success",
+			mock: func() *postService {
+				userMock := NewMockUserResource(ctrl)
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				userMock.EXPECT().IsAdmin(gomock.Any(), int32(9)).Return(true, nil)
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Title: "spam", Version: 3}, nil)
+				postMock.EXPECT().GetPostRevision(gomock.Any(), post.GetPostRevisionParams{PostID: 1, Revision: 1}).Return(post.PostRevision{
+					PostID: 1, Revision: 1, Title: "holiday", Description: "yay", TagIds: []int32{2},
+				}, nil)
+				postMock.EXPECT().UpdatePost(gomock.Any(), post.UpdatePostParams{
+					ID:          1,
+					Title:       "holiday",
+					Description: "yay",
+				}).Return(post.UpdatePostRow{Title: "holiday", Description: "yay", Version: 4}, nil)
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1)).Return([]int32{2, 3}, nil)
+				postTagMock.EXPECT().DeletePostTagsByTagIDs(gomock.Any(), post_tags.DeletePostTagsByTagIDsParams{Postid: 1, TagIds: []int32{3}})
+				postMock.EXPECT().CreatePostRevision(gomock.Any(), post.CreatePostRevisionParams{
+					EditorID: sql.NullInt32{Int32: 9, Valid: true},
+					PostID:   1,
+				}).Return(post.PostRevision{PostID: 1, Revision: 4, CreatedAt: editedAt}, nil)
+
+				return &postService{tx: inlineTx{Post: postMock, PostTag: postTagMock, User: userMock}}
+			},
+			want: UpdatePostRow{
+				Title:       "holiday",
+				Description: "yay",
+				TagID:       []int32{2},
+				Edited:      true,
+				EditedAt:    &editedAt,
+				Version:     4,
+			},
+		},
+		{
+			name: "not admin",
+			mock: func() *postService {
+				userMock := NewMockUserResource(ctrl)
+				userMock.EXPECT().IsAdmin(gomock.Any(), int32(9)).Return(false, nil)
+
+				return &postService{tx: inlineTx{User: userMock}}
+			},
+			want:    UpdatePostRow{},
+			wantErr: ErrNotAdmin,
+		},
+		{
+			name: "unknown user",
+			mock: func() *postService {
+				userMock := NewMockUserResource(ctrl)
+				userMock.EXPECT().IsAdmin(gomock.Any(), int32(9)).Return(false, sql.ErrNoRows)
+
+				return &postService{tx: inlineTx{User: userMock}}
+			},
+			want:    UpdatePostRow{},
+			wantErr: ErrNotAdmin,
+		},
+		{
+			name: "missing revision",
+			mock: func() *postService {
+				userMock := NewMockUserResource(ctrl)
+				postMock := NewMockPostResource(ctrl)
+				userMock.EXPECT().IsAdmin(gomock.Any(), int32(9)).Return(true, nil)
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Version: 3}, nil)
+				postMock.EXPECT().GetPostRevision(gomock.Any(), gomock.Any()).Return(post.PostRevision{}, sql.ErrNoRows)
+
+				return &postService{tx: inlineTx{Post: postMock, User: userMock}}
+			},
+			want:    UpdatePostRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "tag deleted since",
+			mock: func() *postService {
+				userMock := NewMockUserResource(ctrl)
+				postMock := NewMockPostResource(ctrl)
+				postTagMock := NewMockPostTagResource(ctrl)
+				userMock.EXPECT().IsAdmin(gomock.Any(), int32(9)).Return(true, nil)
+				postMock.EXPECT().GetPost(gomock.Any(), int32(1)).Return(post.GetPostRow{ID: 1, Version: 3}, nil)
+				postMock.EXPECT().GetPostRevision(gomock.Any(), gomock.Any()).Return(post.PostRevision{PostID: 1, Revision: 1, TagIds: []int32{7}}, nil)
+				postMock.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).Return(post.UpdatePostRow{Version: 4}, nil)
+				postTagMock.EXPECT().GetPostTagIDs(gomock.Any(), int32(1))
+				postTagMock.EXPECT().CreatePostTag(gomock.Any(), gomock.Any()).Return(post_tags.CreatePostTagRow{}, &pq.Error{Code: pqForeignKeyViolation})
+
+				return &postService{tx: inlineTx{Post: postMock, PostTag: postTagMock, User: userMock}}
+			},
+			want:    UpdatePostRow{},
+			wantErr: ErrTagGone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.RevertPost(ctx, arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("RevertPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RevertPost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// inlineTx runs transaction bodies straight on the mocks it holds.
+type inlineTx TxResources
+
+func (t inlineTx) InTx(ctx context.Context, fn func(tx TxResources) error) error {
+	return fn(TxResources(t))
+}
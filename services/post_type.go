@@ -1,6 +1,9 @@
 package services
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 type Post struct {
 	ID          int32               `json:"id"`
@@ -13,19 +16,60 @@ type Post struct {
 	Tags        []GetTagByPostIDRow `json:"tags"`
 }
 
+// Who may read a post. Authors always see their own posts, and a reply is
+// only shown to readers who may see the root of its thread.
+const (
+	PostVisibilityPublic = "public"
+	// PostVisibilityFollowers shows the post to its author and the users
+	// following them.
+	PostVisibilityFollowers = "followers"
+	// PostVisibilityPrivate shows the post to its author only.
+	PostVisibilityPrivate = "private"
+	// PostVisibilityUnlisted shows the post to anyone who asks for it, but
+	// keeps it out of listings, feeds and search.
+	PostVisibilityUnlisted = "unlisted"
+)
+
+// Where a post is in its life. Drafts and scheduled posts are only shown to
+// their author until they are published.
+const (
+	PostStatusDraft = "draft"
+	// PostStatusScheduled posts are published by the scheduler once their
+	// publish time has passed.
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+)
+
 type CreatePostParams struct {
 	Userid      int32
 	Title       string
 	Description string
 	TagID       []int32
+	// InReplyToID is the post this one replies to, or 0.
+	InReplyToID int32
+	// Visibility is one of the PostVisibility constants and defaults to
+	// PostVisibilityPublic.
+	Visibility string
+	// Status is one of the PostStatus constants and defaults to
+	// PostStatusPublished. PublishAt is required for scheduled posts and
+	// ignored otherwise.
+	Status    string
+	PublishAt time.Time
+	// MediaIDs are media the author uploaded, attached in the given order.
+	MediaIDs []int32
 }
 
 type CreatePostRow struct {
-	ID          int32   `json:"id"`
-	Userid      int32   `json:"user_id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	TagID       []int32 `json:"tag_ids"`
+	ID          int32      `json:"id"`
+	Userid      int32      `json:"user_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	TagID       []int32    `json:"tag_ids"`
+	InReplyToID int32      `json:"in_reply_to_id,omitempty"`
+	Visibility  string     `json:"visibility"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	MediaIDs    []int32    `json:"media_ids"`
 }
 
 type UpdatePostParams struct {
@@ -33,25 +77,319 @@ type UpdatePostParams struct {
 	Title       string
 	Description string
 	TagID       []int32
+	// EditorID is the user making the change, recorded in the revision it
+	// creates. It defaults to the author of the post. Only the author may
+	// edit a post that is not published yet.
+	EditorID int32
+	// Version is the expected current version, or 0 to skip the check.
+	Version int32
 }
 
 type UpdatePostRow struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	TagID       []int32 `json:"tag_ids"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	TagID       []int32    `json:"tag_ids"`
+	Edited      bool       `json:"edited"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"`
+	Version     int32      `json:"version"`
+}
+
+type GetPostParams struct {
+	ID int32
+	// ViewerID is the user asking for the post, or 0 for an anonymous
+	// reader.
+	ViewerID int32
 }
 
 type GetPostRow struct {
-	ID          int32  `json:"id"`
-	Userid      int32  `json:"user_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	ID          int32               `json:"id"`
+	Userid      int32               `json:"user_id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Tags        []GetTagByPostIDRow `json:"tags"`
+	Visibility  string              `json:"visibility"`
+	Status      string              `json:"status"`
+	PublishAt   *time.Time          `json:"publish_at,omitempty"`
+	Edited      bool                `json:"edited"`
+	EditedAt    *time.Time          `json:"edited_at,omitempty"`
+	Version     int32               `json:"version"`
+	Media       []PostMedia         `json:"media"`
+}
+
+type DeletePostParams struct {
+	ID      int32
+	Version int32
+}
+
+const (
+	PostSortNewest      = "newest"
+	PostSortOldest      = "oldest"
+	PostSortMostReacted = "most_reacted"
+)
+
+type GetPostsParams struct {
+	// UserID is 0 and CreatedFrom and CreatedTo are zero when the filter is
+	// not used. CreatedTo is exclusive.
+	UserID      int32
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// TagIDs keeps posts having any of the tags, or all of them when
+	// MatchAllTags is set. An empty list does not filter.
+	TagIDs       []int32
+	MatchAllTags bool
+	// Sort is one of the PostSort constants and defaults to PostSortNewest.
+	Sort string
+	// CursorCreatedAt and CursorID are the created_at and id of the last
+	// post of the previous page, and start the list after it. They are
+	// zero for the first page and are not used with PostSortMostReacted.
+	CursorCreatedAt time.Time
+	CursorID        int32
+	Limit           int32
+	// ViewerID is the user reading the list, or 0 for an anonymous reader.
+	// Posts they may not see are left out, and their bookmarks set
+	// Bookmarked.
+	ViewerID int32
 }
 
 type GetPostsRow struct {
+	ID            int32               `json:"id"`
+	Userid        int32               `json:"user_id"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Tags          []GetTagByPostIDRow `json:"tags"`
+	ReactionCount int64               `json:"reaction_count"`
+	RepostCount   int64               `json:"repost_count"`
+	QuoteCount    int64               `json:"quote_count"`
+	ReplyCount    int64               `json:"reply_count"`
+	InReplyToID   int32               `json:"in_reply_to_id,omitempty"`
+	Visibility    string              `json:"visibility"`
+	Edited        bool                `json:"edited"`
+	EditedAt      *time.Time          `json:"edited_at,omitempty"`
+	Bookmarked    bool                `json:"bookmarked"`
+	// CreatedAt is set by GetPosts, to be passed back as its cursor.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// RepostOf and QuoteOf embed the post this one reposts or quotes. They
+	// are nil for plain posts and when the original is gone or hidden from
+	// the viewer.
+	RepostOf *EmbeddedPost `json:"repost_of"`
+	QuoteOf  *EmbeddedPost `json:"quote_of"`
+
+	repostOfID int32
+	quoteOfID  int32
+}
+
+type EmbeddedPost struct {
+	ID            int32               `json:"id"`
+	Userid        int32               `json:"user_id"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Tags          []GetTagByPostIDRow `json:"tags"`
+	ReactionCount int64               `json:"reaction_count"`
+	RepostCount   int64               `json:"repost_count"`
+	QuoteCount    int64               `json:"quote_count"`
+	ReplyCount    int64               `json:"reply_count"`
+	Edited        bool                `json:"edited"`
+	EditedAt      *time.Time          `json:"edited_at,omitempty"`
+}
+
+type PatchPostParams struct {
+	ID          int32
+	Title       *string
+	Description *string
+	// ReplaceTags is set when the patch carries tag_ids. TagID then holds
+	// the new, possibly empty, tag set.
+	ReplaceTags bool
+	TagID       []int32
+	Visibility  *string
+	// Status moves a draft or scheduled post along. A published post stays
+	// published.
+	Status    *string
+	PublishAt *time.Time
+	// EditorID is recorded as in UpdatePostParams when the patch changes
+	// the title, the description or the tags. Only the author may patch a
+	// post that is not published yet or change its status.
+	EditorID int32
+	Version  int32
+}
+
+type PatchPostRow struct {
 	ID          int32               `json:"id"`
 	Userid      int32               `json:"user_id"`
 	Title       string              `json:"title"`
 	Description string              `json:"description"`
 	Tags        []GetTagByPostIDRow `json:"tags"`
+	Visibility  string              `json:"visibility"`
+	Status      string              `json:"status"`
+	PublishAt   *time.Time          `json:"publish_at,omitempty"`
+	Edited      bool                `json:"edited"`
+	EditedAt    *time.Time          `json:"edited_at,omitempty"`
+	Version     int32               `json:"version"`
+}
+
+type GetTagFeedParams struct {
+	UserID int32
+	Limit  int32
+	Offset int32
+}
+
+type GetBookmarksParams struct {
+	UserID int32
+	// CollectionID is 0 to list every saved post.
+	CollectionID int32
+	Limit        int32
+	Offset       int32
+}
+
+type GetThreadParams struct {
+	ID       int32
+	ViewerID int32
+	// Depth is how many levels of replies to load below the post. Limit
+	// caps the replies loaded under each post, and Offset pages through the
+	// direct replies to the post.
+	Depth  int32
+	Limit  int32
+	Offset int32
+}
+
+type GetThreadRow struct {
+	// Ancestors runs from the root of the thread down to the parent of Post.
+	Ancestors []ThreadPost `json:"ancestors"`
+	Post      ThreadPost   `json:"post"`
+}
+
+type ThreadPost struct {
+	ID            int32               `json:"id"`
+	Userid        int32               `json:"user_id"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Tags          []GetTagByPostIDRow `json:"tags"`
+	ReactionCount int64               `json:"reaction_count"`
+	ReplyCount    int64               `json:"reply_count"`
+	Edited        bool                `json:"edited"`
+	EditedAt      *time.Time          `json:"edited_at,omitempty"`
+	Replies       []*ThreadPost       `json:"replies,omitempty"`
+}
+
+type RepostParams struct {
+	UserID int32
+	PostID int32
+	// Quote, when not empty, makes a quote post with this text instead of a
+	// plain repost.
+	Quote string
+}
+
+type RepostRow struct {
+	ID          int32  `json:"id"`
+	Userid      int32  `json:"user_id"`
+	Description string `json:"description"`
+	RepostOfID  int32  `json:"repost_of_id,omitempty"`
+	QuoteOfID   int32  `json:"quote_of_id,omitempty"`
+}
+
+type UnrepostParams struct {
+	UserID int32
+	PostID int32
+}
+
+// The reactions a user may leave on a post. A user has one reaction on a
+// post at a time.
+const (
+	ReactionLike  = "like"
+	ReactionLove  = "love"
+	ReactionLaugh = "laugh"
+	ReactionWow   = "wow"
+	ReactionSad   = "sad"
+	ReactionAngry = "angry"
+)
+
+type ReactParams struct {
+	UserID int32
+	PostID int32
+	// Kind is one of the Reaction constants.
+	Kind string
+}
+
+type UnreactParams struct {
+	UserID int32
+	PostID int32
+}
+
+type SearchPostsParams struct {
+	Query string
+	// TagID and UserID are 0 and CreatedFrom and CreatedTo are zero when
+	// the filter is not used. CreatedTo is exclusive.
+	TagID       int32
+	UserID      int32
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	ViewerID    int32
+	Limit       int32
+	Offset      int32
+}
+
+// SearchPostsRow is a listed post, in the same shape as GetPostsRow, with
+// the matched snippet and its rank.
+type SearchPostsRow struct {
+	GetPostsRow
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+type GetPostRevisionsParams struct {
+	ID int32
+	// ViewerID is the user reading the history, who must be able to see
+	// the post.
+	ViewerID int32
+	Limit    int32
+	Offset   int32
+}
+
+type PostRevision struct {
+	PostID      int32     `json:"post_id"`
+	Revision    int32     `json:"revision"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	TagIDs      []int32   `json:"tag_ids"`
+	EditorID    int32     `json:"editor_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetPostRevisionDiffParams struct {
+	ID       int32
+	ViewerID int32
+	From     int32
+	To       int32
+}
+
+// How a line of a diff relates the older revision to the newer one.
+const (
+	DiffEqual  = "equal"
+	DiffDelete = "delete"
+	DiffInsert = "insert"
+)
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// PostRevisionDiff tells how revision To of a post differs from revision
+// From. Title and Description walk both texts line by line.
+type PostRevisionDiff struct {
+	PostID      int32      `json:"post_id"`
+	From        int32      `json:"from"`
+	To          int32      `json:"to"`
+	Title       []DiffLine `json:"title"`
+	Description []DiffLine `json:"description"`
+	TagsAdded   []int32    `json:"tags_added"`
+	TagsRemoved []int32    `json:"tags_removed"`
+}
+
+type RevertPostParams struct {
+	ID       int32
+	Revision int32
+	// UserID is the admin asking for the revert and becomes the editor of
+	// the revision it creates.
+	UserID  int32
+	Version int32
 }
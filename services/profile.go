@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/user"
+)
+
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+type ProfileService interface {
+	// GetProfile looks a user up by their handle, or by a handle they gave
+	// up less than the redirect grace period ago. Callers compare the
+	// returned handle with the one they asked for to tell the two apart.
+	GetProfile(ctx context.Context, arg GetProfileParams) (ProfileRow, error)
+	SetProfile(ctx context.Context, arg SetProfileParams) (SetProfileRow, error)
+}
+
+type profileService struct {
+	ur             UserResource
+	tx             TxRunner
+	handleCooldown time.Duration
+	handleGrace    time.Duration
+	now            func() time.Time
+}
+
+// NewProfileService returns a ProfileService letting a user change their
+// handle once per handleCooldown, and keeping an old handle pointing at its
+// user for handleGrace after the change. The clock is now, or time.Now when
+// now is nil.
+func NewProfileService(UR UserResource, TX TxRunner, handleCooldown, handleGrace time.Duration, now func() time.Time) (ProfileService, error) {
+	if now == nil {
+		now = time.Now
+	}
+	return &profileService{
+		ur:             UR,
+		tx:             TX,
+		handleCooldown: handleCooldown,
+		handleGrace:    handleGrace,
+		now:            now,
+	}, nil
+}
+
+func (ps *profileService) GetProfile(ctx context.Context, arg GetProfileParams) (ProfileRow, error) {
+	var result ProfileRow = ProfileRow{}
+	res, err := ps.ur.GetProfile(ctx, user.GetProfileParams{
+		ViewerID: arg.ViewerID,
+		Handle:   strings.ToLower(arg.Handle),
+	})
+	if err != nil {
+		logSQLError(ctx, "GetProfile", err)
+		return result, err
+	}
+
+	result = ProfileRow{
+		ID:             res.ID,
+		Fullname:       res.Fullname,
+		Handle:         res.Handle.String,
+		Bio:            res.Bio,
+		Location:       res.Location,
+		Website:        res.Website,
+		PostCount:      res.PostCount,
+		FollowerCount:  res.FollowerCount,
+		FollowingCount: res.FollowingCount,
+	}
+	if res.AvatarMediaID.Valid {
+		result.AvatarMediaID = res.AvatarMediaID.Int32
+		result.AvatarURL = mediaURL(res.AvatarMediaID.Int32)
+		result.AvatarThumbnailURL = thumbnailURL(res.AvatarMediaID.Int32, res.AvatarThumbnailContentType)
+	}
+	return result, nil
+}
+
+func (ps *profileService) SetProfile(ctx context.Context, arg SetProfileParams) (SetProfileRow, error) {
+	var result SetProfileRow = SetProfileRow{}
+	handle := strings.ToLower(arg.Handle)
+	if !handlePattern.MatchString(handle) {
+		return result, ErrInvalidHandle
+	}
+	if !validWebsite(arg.Website) {
+		return result, ErrInvalidWebsite
+	}
+
+	now := ps.now().UTC()
+	err := ps.tx.InTx(ctx, func(tx TxResources) error {
+		current, err := tx.User.GetHandleForUpdate(ctx, arg.UserID)
+		if err != nil {
+			logSQLError(ctx, "GetHandleForUpdate", err)
+			return err
+		}
+		changed := current.Handle.String != handle
+		if changed && current.Handle.Valid && current.HandleChangedAt.Valid {
+			retryAt := current.HandleChangedAt.Time.Add(ps.handleCooldown)
+			if now.Before(retryAt) {
+				return &HandleCooldownError{RetryAt: retryAt}
+			}
+		}
+
+		if changed {
+			err = ps.lockHandles(ctx, tx.User, current.Handle, handle)
+			if err != nil {
+				return err
+			}
+			err = tx.User.ClaimHandleRedirect(ctx, user.ClaimHandleRedirectParams{
+				Handle: handle,
+				UserID: arg.UserID,
+			})
+			if err != nil {
+				logSQLError(ctx, "ClaimHandleRedirect", err)
+				return err
+			}
+			reserved, err := tx.User.IsHandleReserved(ctx, handle)
+			if err != nil {
+				logSQLError(ctx, "IsHandleReserved", err)
+				return err
+			}
+			if reserved {
+				return ErrHandleTaken
+			}
+		}
+
+		if arg.AvatarMediaID != 0 {
+			own, err := tx.User.IsOwnImage(ctx, user.IsOwnImageParams{
+				MediaID: arg.AvatarMediaID,
+				UserID:  arg.UserID,
+			})
+			if err != nil {
+				logSQLError(ctx, "IsOwnImage", err)
+				return err
+			}
+			if !own {
+				return ErrAvatarNotOwned
+			}
+		}
+
+		res, err := tx.User.UpdateProfile(ctx, user.UpdateProfileParams{
+			Handle:        handle,
+			ChangedAt:     now,
+			Bio:           arg.Bio,
+			Location:      arg.Location,
+			Website:       arg.Website,
+			AvatarMediaID: nullInt32(arg.AvatarMediaID),
+			ID:            arg.UserID,
+		})
+		if isPQError(err, pqUniqueViolation) {
+			return ErrHandleTaken
+		}
+		if err != nil {
+			logSQLError(ctx, "UpdateProfile", err)
+			return err
+		}
+
+		if changed && current.Handle.Valid {
+			err = tx.User.AddHandleRedirect(ctx, user.AddHandleRedirectParams{
+				Handle:    current.Handle.String,
+				UserID:    arg.UserID,
+				ExpiresAt: now.Add(ps.handleGrace),
+			})
+			if err != nil {
+				logSQLError(ctx, "AddHandleRedirect", err)
+				return err
+			}
+		}
+
+		result = SetProfileRow{
+			ID:            res.ID,
+			Fullname:      res.Fullname,
+			Handle:        res.Handle.String,
+			Bio:           res.Bio,
+			Location:      res.Location,
+			Website:       res.Website,
+			AvatarMediaID: res.AvatarMediaID.Int32,
+			Version:       res.Version,
+		}
+		return nil
+	})
+	if err != nil {
+		return SetProfileRow{}, err
+	}
+	return result, nil
+}
+
+// lockHandles serializes changes involving the old or the new handle, taking
+// the locks in a fixed order so that two users swapping handles cannot
+// deadlock.
+func (ps *profileService) lockHandles(ctx context.Context, ur UserResource, old sql.NullString, handle string) error {
+	handles := []string{handle}
+	if old.Valid {
+		handles = append(handles, old.String)
+		if old.String < handle {
+			handles[0], handles[1] = handles[1], handles[0]
+		}
+	}
+	for _, h := range handles {
+		err := ur.LockHandle(ctx, h)
+		if err != nil {
+			logSQLError(ctx, "LockHandle", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func validWebsite(website string) bool {
+	if website == "" {
+		return true
+	}
+	u, err := url.Parse(website)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/user"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+)
+
+func TestNewProfileService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userMock := NewMockUserResource(ctrl)
+	txMock := inlineTx{User: userMock}
+
+	got, err := NewProfileService(userMock, txMock, time.Hour, 2*time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewProfileService() error = %v", err)
+	}
+	ps := got.(*profileService)
+	if ps.ur != userMock || ps.tx != txMock || ps.handleCooldown != time.Hour || ps.handleGrace != 2*time.Hour || ps.now == nil {
+		t.Errorf("NewProfileService() = %+v", ps)
+	}
+}
+
+func Test_GetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     GetProfileParams
+		mock    func() UserResource
+		want    ProfileRow
+		wantErr error
+	}{
+		{
+			name: "success get profile",
+			arg:  GetProfileParams{Handle: "Ana", ViewerID: 2},
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetProfile(gomock.Any(), user.GetProfileParams{ViewerID: 2, Handle: "ana"}).Return(user.GetProfileRow{
+					ID:                         1,
+					Fullname:                   "Ana",
+					Handle:                     sql.NullString{String: "ana", Valid: true},
+					Bio:                        "hello",
+					Website:                    "https://ana.dev",
+					AvatarMediaID:              sql.NullInt32{Int32: 4, Valid: true},
+					AvatarThumbnailContentType: sql.NullString{String: "image/jpeg", Valid: true},
+					PostCount:                  3,
+					FollowerCount:              5,
+					FollowingCount:             6,
+				}, nil)
+				return m
+			},
+			want: ProfileRow{
+				ID:                 1,
+				Fullname:           "Ana",
+				Handle:             "ana",
+				Bio:                "hello",
+				Website:            "https://ana.dev",
+				AvatarMediaID:      4,
+				AvatarURL:          "/media/4",
+				AvatarThumbnailURL: "/media/4/thumbnail",
+				PostCount:          3,
+				FollowerCount:      5,
+				FollowingCount:     6,
+			},
+		},
+		{
+			name: "success without avatar",
+			arg:  GetProfileParams{Handle: "ana"},
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(user.GetProfileRow{
+					ID:     1,
+					Handle: sql.NullString{String: "ana", Valid: true},
+				}, nil)
+				return m
+			},
+			want: ProfileRow{ID: 1, Handle: "ana"},
+		},
+		{
+			name: "profile not found",
+			arg:  GetProfileParams{Handle: "ana"},
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(user.GetProfileRow{}, sql.ErrNoRows)
+				return m
+			},
+			want:    ProfileRow{},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &profileService{ur: tt.mock()}
+			got, err := ps.GetProfile(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("GetProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	arg := SetProfileParams{
+		UserID:        1,
+		Handle:        "Ana_B",
+		Bio:           "hello",
+		Location:      "Jakarta",
+		Website:       "https://ana.dev",
+		AvatarMediaID: 4,
+	}
+	update := user.UpdateProfileParams{
+		Handle:        "ana_b",
+		ChangedAt:     now,
+		Bio:           "hello",
+		Location:      "Jakarta",
+		Website:       "https://ana.dev",
+		AvatarMediaID: sql.NullInt32{Int32: 4, Valid: true},
+		ID:            1,
+	}
+	updated := user.UpdateProfileRow{
+		ID:            1,
+		Fullname:      "Ana",
+		Handle:        sql.NullString{String: "ana_b", Valid: true},
+		Bio:           "hello",
+		Location:      "Jakarta",
+		Website:       "https://ana.dev",
+		AvatarMediaID: sql.NullInt32{Int32: 4, Valid: true},
+		Version:       3,
+	}
+	want := SetProfileRow{
+		ID:            1,
+		Fullname:      "Ana",
+		Handle:        "ana_b",
+		Bio:           "hello",
+		Location:      "Jakarta",
+		Website:       "https://ana.dev",
+		AvatarMediaID: 4,
+		Version:       3,
+	}
+	current := func(handle string, changedAt time.Time) user.GetHandleForUpdateRow {
+		return user.GetHandleForUpdateRow{
+			Handle:          sql.NullString{String: handle, Valid: handle != ""},
+			HandleChangedAt: sql.NullTime{Time: changedAt, Valid: !changedAt.IsZero()},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		arg     SetProfileParams
+		mock    func() UserResource
+		want    SetProfileRow
+		wantErr error
+	}{
+		{
+			name: "first handle",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("", time.Time{}), nil),
+					m.EXPECT().LockHandle(gomock.Any(), "ana_b").Return(nil),
+					m.EXPECT().ClaimHandleRedirect(gomock.Any(), user.ClaimHandleRedirectParams{Handle: "ana_b", UserID: 1}).Return(nil),
+					m.EXPECT().IsHandleReserved(gomock.Any(), "ana_b").Return(false, nil),
+					m.EXPECT().IsOwnImage(gomock.Any(), user.IsOwnImageParams{MediaID: 4, UserID: 1}).Return(true, nil),
+					m.EXPECT().UpdateProfile(gomock.Any(), update).Return(updated, nil),
+				)
+				return m
+			},
+			want: want,
+		},
+		{
+			name: "handle change leaves a redirect",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				gomock.InOrder(
+					m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("ana", now.Add(-8*24*time.Hour)), nil),
+					m.EXPECT().LockHandle(gomock.Any(), "ana").Return(nil),
+					m.EXPECT().LockHandle(gomock.Any(), "ana_b").Return(nil),
+					m.EXPECT().ClaimHandleRedirect(gomock.Any(), gomock.Any()).Return(nil),
+					m.EXPECT().IsHandleReserved(gomock.Any(), "ana_b").Return(false, nil),
+					m.EXPECT().IsOwnImage(gomock.Any(), gomock.Any()).Return(true, nil),
+					m.EXPECT().UpdateProfile(gomock.Any(), update).Return(updated, nil),
+					m.EXPECT().AddHandleRedirect(gomock.Any(), user.AddHandleRedirectParams{
+						Handle:    "ana",
+						UserID:    1,
+						ExpiresAt: now.Add(30 * 24 * time.Hour),
+					}).Return(nil),
+				)
+				return m
+			},
+			want: want,
+		},
+		{
+			name: "same handle skips the cooldown",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("ana_b", now.Add(-time.Hour)), nil)
+				m.EXPECT().IsOwnImage(gomock.Any(), gomock.Any()).Return(true, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), update).Return(updated, nil)
+				return m
+			},
+			want: want,
+		},
+		{
+			name: "handle changed too recently",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("ana", now.Add(-24*time.Hour)), nil)
+				return m
+			},
+			want:    SetProfileRow{},
+			wantErr: &HandleCooldownError{RetryAt: now.Add(6 * 24 * time.Hour)},
+		},
+		{
+			name: "invalid handle",
+			arg:  SetProfileParams{UserID: 1, Handle: "1ana"},
+			mock: func() UserResource {
+				return NewMockUserResource(ctrl)
+			},
+			want:    SetProfileRow{},
+			wantErr: ErrInvalidHandle,
+		},
+		{
+			name: "invalid website",
+			arg:  SetProfileParams{UserID: 1, Handle: "ana", Website: "javascript:alert(1)"},
+			mock: func() UserResource {
+				return NewMockUserResource(ctrl)
+			},
+			want:    SetProfileRow{},
+			wantErr: ErrInvalidWebsite,
+		},
+		{
+			name: "user not found",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(user.GetHandleForUpdateRow{}, sql.ErrNoRows)
+				return m
+			},
+			want:    SetProfileRow{},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "handle held by a redirect",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("", time.Time{}), nil)
+				m.EXPECT().LockHandle(gomock.Any(), "ana_b").Return(nil)
+				m.EXPECT().ClaimHandleRedirect(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().IsHandleReserved(gomock.Any(), "ana_b").Return(true, nil)
+				return m
+			},
+			want:    SetProfileRow{},
+			wantErr: ErrHandleTaken,
+		},
+		{
+			name: "handle held by another user",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("", time.Time{}), nil)
+				m.EXPECT().LockHandle(gomock.Any(), "ana_b").Return(nil)
+				m.EXPECT().ClaimHandleRedirect(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().IsHandleReserved(gomock.Any(), "ana_b").Return(false, nil)
+				m.EXPECT().IsOwnImage(gomock.Any(), gomock.Any()).Return(true, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(user.UpdateProfileRow{}, &pq.Error{Code: pqUniqueViolation})
+				return m
+			},
+			want:    SetProfileRow{},
+			wantErr: ErrHandleTaken,
+		},
+		{
+			name: "avatar not owned",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("ana_b", time.Time{}), nil)
+				m.EXPECT().IsOwnImage(gomock.Any(), gomock.Any()).Return(false, nil)
+				return m
+			},
+			want:    SetProfileRow{},
+			wantErr: ErrAvatarNotOwned,
+		},
+		{
+			name: "error add handle redirect",
+			arg:  arg,
+			mock: func() UserResource {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetHandleForUpdate(gomock.Any(), int32(1)).Return(current("ana", time.Time{}), nil)
+				m.EXPECT().LockHandle(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				m.EXPECT().ClaimHandleRedirect(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().IsHandleReserved(gomock.Any(), gomock.Any()).Return(false, nil)
+				m.EXPECT().IsOwnImage(gomock.Any(), gomock.Any()).Return(true, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(updated, nil)
+				m.EXPECT().AddHandleRedirect(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+				return m
+			},
+			want:    SetProfileRow{},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userMock := tt.mock()
+			ps := &profileService{
+				ur:             userMock,
+				tx:             inlineTx{User: userMock},
+				handleCooldown: 7 * 24 * time.Hour,
+				handleGrace:    30 * 24 * time.Hour,
+				now:            clock,
+			}
+			got, err := ps.SetProfile(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("SetProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
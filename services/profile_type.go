@@ -0,0 +1,57 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+type GetProfileParams struct {
+	Handle   string
+	ViewerID int32
+}
+
+type ProfileRow struct {
+	ID                 int32  `json:"id"`
+	Fullname           string `json:"fullname"`
+	Handle             string `json:"handle"`
+	Bio                string `json:"bio"`
+	Location           string `json:"location"`
+	Website            string `json:"website"`
+	AvatarMediaID      int32  `json:"avatar_media_id,omitempty"`
+	AvatarURL          string `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL string `json:"avatar_thumbnail_url,omitempty"`
+	PostCount          int32  `json:"post_count"`
+	FollowerCount      int32  `json:"follower_count"`
+	FollowingCount     int32  `json:"following_count"`
+}
+
+type SetProfileParams struct {
+	UserID   int32
+	Handle   string
+	Bio      string
+	Location string
+	Website  string
+	// AvatarMediaID is an uploaded image of the user's, or 0 for no avatar.
+	AvatarMediaID int32
+}
+
+type SetProfileRow struct {
+	ID            int32  `json:"id"`
+	Fullname      string `json:"fullname"`
+	Handle        string `json:"handle"`
+	Bio           string `json:"bio"`
+	Location      string `json:"location"`
+	Website       string `json:"website"`
+	AvatarMediaID int32  `json:"avatar_media_id,omitempty"`
+	Version       int32  `json:"version"`
+}
+
+// HandleCooldownError is returned when a user changes their handle again
+// before the cooldown since the last change has passed.
+type HandleCooldownError struct {
+	RetryAt time.Time
+}
+
+func (e *HandleCooldownError) Error() string {
+	return fmt.Sprintf("handle can be changed again at %s", e.RetryAt.Format(time.RFC3339))
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/post"
+)
+
+// publishBatchSize caps the posts one statement publishes, so that the row
+// locks taken by a run are held briefly.
+const publishBatchSize = 100
+
+type SchedulerService interface {
+	// PublishDuePosts publishes every scheduled post whose publish time has
+	// passed by the service clock and returns how many it published. Runs
+	// on several replicas at once never publish the same post twice.
+	PublishDuePosts(ctx context.Context) (int, error)
+}
+
+type schedulerService struct {
+	pr  PostResource
+	now func() time.Time
+}
+
+// NewSchedulerService returns a SchedulerService reading the time from now,
+// or from time.Now when now is nil.
+func NewSchedulerService(PR PostResource, now func() time.Time) (SchedulerService, error) {
+	if now == nil {
+		now = time.Now
+	}
+	return &schedulerService{
+		pr:  PR,
+		now: now,
+	}, nil
+}
+
+func (ss *schedulerService) PublishDuePosts(ctx context.Context) (int, error) {
+	now := ss.now().UTC()
+	published := 0
+	for {
+		ids, err := ss.pr.PublishDuePosts(ctx, post.PublishDuePostsParams{
+			Now:   now,
+			Limit: publishBatchSize,
+		})
+		if err != nil {
+			logSQLError(ctx, "PublishDuePosts", err)
+			return published, err
+		}
+		published += len(ids)
+		if len(ids) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
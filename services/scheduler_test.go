@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/post"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_PublishDuePosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	clock := func() time.Time {
+		return time.Date(2024, 1, 8, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	}
+	arg := post.PublishDuePostsParams{
+		Now:   time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC),
+		Limit: publishBatchSize,
+	}
+	batch := func(n int) []int32 {
+		ids := make([]int32, n)
+		for i := range ids {
+			ids[i] = int32(i + 1)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		mock    func() *schedulerService
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "nothing due",
+			mock: func() *schedulerService {
+				postMock := NewMockPostResource(ctrl)
+				postMock.EXPECT().PublishDuePosts(gomock.Any(), arg).Return(nil, nil)
+				return &schedulerService{pr: postMock, now: clock}
+			},
+			want: 0,
+		},
+		{
+			name: "full batch runs again",
+			mock: func() *schedulerService {
+				postMock := NewMockPostResource(ctrl)
+				gomock.InOrder(
+					postMock.EXPECT().PublishDuePosts(gomock.Any(), arg).Return(batch(publishBatchSize), nil),
+					postMock.EXPECT().PublishDuePosts(gomock.Any(), arg).Return(batch(3), nil),
+				)
+				return &schedulerService{pr: postMock, now: clock}
+			},
+			want: publishBatchSize + 3,
+		},
+		{
+			name: "error keeps the published count",
+			mock: func() *schedulerService {
+				postMock := NewMockPostResource(ctrl)
+				gomock.InOrder(
+					postMock.EXPECT().PublishDuePosts(gomock.Any(), arg).Return(batch(publishBatchSize), nil),
+					postMock.EXPECT().PublishDuePosts(gomock.Any(), arg).Return(nil, errors.New("error")),
+				)
+				return &schedulerService{pr: postMock, now: clock}
+			},
+			want:    publishBatchSize,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mock().PublishDuePosts(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PublishDuePosts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PublishDuePosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/migration"
+	"github.com/gadhittana01/socialmedia/pkg/tag"
+	"github.com/gadhittana01/socialmedia/pkg/user"
+	_ "github.com/lib/pq"
+)
+
+// Test_SearchLatency seeds the database named by SOCIALMEDIA_TEST_DSN and
+// checks the p99 latency of user and tag autocomplete. It is skipped when
+// the variable is unset. Point it at a disposable database: seeded rows are
+// left in place so later runs start faster.
+func Test_SearchLatency(t *testing.T) {
+	dsn := os.Getenv("SOCIALMEDIA_TEST_DSN")
+	if dsn == "" {
+		t.Skip("SOCIALMEDIA_TEST_DSN is not set")
+	}
+	budget := 50 * time.Millisecond
+	if ms, err := strconv.Atoi(os.Getenv("SOCIALMEDIA_TEST_SEARCH_P99_MS")); err == nil {
+		budget = time.Duration(ms) * time.Millisecond
+	}
+
+	ctx := context.Background()
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := migration.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Up(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	seedSearchData(t, db, 100000, 5000)
+
+	us, _ := NewUserService(user.New(db))
+	ts, _ := NewTagService(tag.New(db))
+	queries := []string{"g", "gi", "gir", "giri", "adh", "putra", "wid", "tag1", "tag12", "hol", "holidy", "zzz"}
+
+	tests := []struct {
+		name   string
+		search func(q string) error
+	}{
+		{
+			name: "search users",
+			search: func(q string) error {
+				_, err := us.SearchUsers(ctx, SearchUsersParams{Query: q, Limit: 10})
+				return err
+			},
+		},
+		{
+			name: "search tags",
+			search: func(q string) error {
+				_, err := ts.SearchTags(ctx, SearchTagsParams{Query: q, Limit: 10})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var durations []time.Duration
+			for i := 0; i < 20; i++ {
+				for _, q := range queries {
+					start := time.Now()
+					if err := tt.search(q); err != nil {
+						t.Fatal(err)
+					}
+					durations = append(durations, time.Since(start))
+				}
+			}
+
+			sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+			p99 := durations[int(math.Ceil(0.99*float64(len(durations))))-1]
+			t.Logf("p99 = %v over %d requests", p99, len(durations))
+			if p99 > budget {
+				t.Errorf("p99 = %v, want at most %v", p99, budget)
+			}
+		})
+	}
+}
+
+func seedSearchData(t *testing.T, db *sql.DB, users, tags int) {
+	t.Helper()
+
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM users`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n >= users {
+		return
+	}
+
+	stmts := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: `INSERT INTO users (fullname)
+			SELECT (ARRAY['Giri','Adi','Putra','Widya','Sari','Budi','Dewi','Agus','Rina','Eko'])[1 + i % 10]
+				|| ' ' || (ARRAY['Adhittana','Santoso','Wijaya','Pratama','Lestari','Hidayat','Saputra','Kusuma'])[1 + (i / 10) % 8]
+				|| ' ' || i
+			FROM generate_series(1, $1) i`,
+			args: []interface{}{users},
+		},
+		{
+			query: `INSERT INTO tags (tagname)
+			SELECT (ARRAY['holiday','reading','shopping','music','travel','food','tag'])[1 + i % 7] || i
+			FROM generate_series(1, $1) i`,
+			args: []interface{}{tags},
+		},
+		{
+			query: `INSERT INTO posts (userid, title, description)
+			SELECT id, 'post ' || id, 'seeded post' FROM users`,
+		},
+		{
+			query: `INSERT INTO post_tags (postid, tagid)
+			SELECT p.id, t.id
+			FROM posts p JOIN tags t ON t.id = (SELECT min(id) FROM tags) + (p.id * 7919) % $1
+			WHERE p.id % 2 = 0`,
+			args: []interface{}{tags},
+		},
+		{
+			query: `ANALYZE`,
+		},
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/gadhittana01/socialmedia/pkg/stream"
+)
+
+// streamReplayLimit caps the events replayed to a reconnecting client.
+const streamReplayLimit = 500
+
+type StreamService interface {
+	// Subscribe opens a stream of the events the user may see. Live events
+	// wait in a buffer of their own per subscription, and a subscription
+	// whose buffer fills up is dropped rather than holding up the others.
+	Subscribe(ctx context.Context, arg SubscribeParams) (*Subscription, error)
+	Unsubscribe(sub *Subscription)
+	// Dispatch loads the stored events with the given ids and pushes each
+	// to the subscriptions allowed to see it, in id order.
+	Dispatch(ctx context.Context, ids []int64) error
+	// DropAll ends every subscription, so that clients reconnect and replay
+	// what they missed. It is for when announcements may have been lost.
+	DropAll()
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type streamService struct {
+	sr               StreamResource
+	bufferSize       int
+	retentionMinutes int32
+
+	mu   sync.Mutex
+	subs map[int32]map[*Subscription]struct{}
+}
+
+func NewStreamService(SR StreamResource, bufferSize int, retentionMinutes int32) (StreamService, error) {
+	return &streamService{
+		sr:               SR,
+		bufferSize:       bufferSize,
+		retentionMinutes: retentionMinutes,
+		subs:             map[int32]map[*Subscription]struct{}{},
+	}, nil
+}
+
+func (ss *streamService) Subscribe(ctx context.Context, arg SubscribeParams) (*Subscription, error) {
+	exists, err := ss.sr.StreamUserExists(ctx, arg.UserID)
+	if err != nil {
+		logSQLError(ctx, "StreamUserExists", err)
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	events := make(chan StreamEvent, ss.bufferSize)
+	sub := &Subscription{
+		UserID: arg.UserID,
+		Events: events,
+		events: events,
+	}
+	// Registering before the replay means nothing published meanwhile is
+	// lost, at the cost of some events arriving twice.
+	ss.mu.Lock()
+	if ss.subs[arg.UserID] == nil {
+		ss.subs[arg.UserID] = map[*Subscription]struct{}{}
+	}
+	ss.subs[arg.UserID][sub] = struct{}{}
+	ss.mu.Unlock()
+
+	if arg.LastEventID == 0 {
+		return sub, nil
+	}
+	res, err := ss.sr.GetStreamEventsAfter(ctx, stream.GetStreamEventsAfterParams{
+		AfterID:  arg.LastEventID,
+		ViewerID: arg.UserID,
+		Limit:    streamReplayLimit + 1,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetStreamEventsAfter", err)
+		ss.Unsubscribe(sub)
+		return nil, err
+	}
+	if len(res) > streamReplayLimit {
+		sub.Missed = true
+		return sub, nil
+	}
+	for _, item := range res {
+		sub.Backlog = append(sub.Backlog, streamEvent(item))
+	}
+	return sub, nil
+}
+
+func (ss *streamService) Unsubscribe(sub *Subscription) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.remove(sub)
+}
+
+func (ss *streamService) Dispatch(ctx context.Context, ids []int64) error {
+	viewers := ss.subscribers()
+	if len(viewers) == 0 || len(ids) == 0 {
+		return nil
+	}
+
+	events, err := ss.sr.GetStreamEvents(ctx, ids)
+	if err != nil {
+		logSQLError(ctx, "GetStreamEvents", err)
+		return err
+	}
+	recipients, err := ss.sr.GetStreamRecipients(ctx, stream.GetStreamRecipientsParams{
+		ViewerIds: viewers,
+		Ids:       ids,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetStreamRecipients", err)
+		return err
+	}
+	byEvent := map[int64][]int32{}
+	for _, r := range recipients {
+		byEvent[r.EventID] = append(byEvent[r.EventID], r.ViewerID)
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, e := range events {
+		for _, userID := range byEvent[e.ID] {
+			for sub := range ss.subs[userID] {
+				select {
+				case sub.events <- streamEvent(e):
+				default:
+					ss.remove(sub)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (ss *streamService) DropAll() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, subs := range ss.subs {
+		for sub := range subs {
+			ss.remove(sub)
+		}
+	}
+}
+
+func (ss *streamService) PurgeExpired(ctx context.Context) (int64, error) {
+	n, err := ss.sr.PurgeStreamEvents(ctx, ss.retentionMinutes)
+	if err != nil {
+		logSQLError(ctx, "PurgeStreamEvents", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+// subscribers lists, in order, the users with an open subscription.
+func (ss *streamService) subscribers() []int32 {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	res := make([]int32, 0, len(ss.subs))
+	for userID := range ss.subs {
+		res = append(res, userID)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// remove drops sub and closes its channel, unless that already happened.
+// The caller holds ss.mu.
+func (ss *streamService) remove(sub *Subscription) {
+	subs := ss.subs[sub.UserID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(ss.subs, sub.UserID)
+	}
+	close(sub.events)
+}
+
+func streamEvent(e stream.StreamEvent) StreamEvent {
+	return StreamEvent{
+		ID:   e.ID,
+		Kind: string(e.Kind),
+		Data: e.Payload,
+	}
+}
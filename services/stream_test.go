@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gadhittana01/socialmedia/pkg/stream"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNewStreamService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	streamMock := NewMockStreamResource(ctrl)
+
+	want := &streamService{
+		sr:               streamMock,
+		bufferSize:       64,
+		retentionMinutes: 60,
+		subs:             map[int32]map[*Subscription]struct{}{},
+	}
+	got, err := NewStreamService(streamMock, 64, 60)
+	if err != nil {
+		t.Fatalf("NewStreamService() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewStreamService() = %v, want %v", got, want)
+	}
+}
+
+func Test_Subscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	replayed := []stream.StreamEvent{
+		{ID: 6, Kind: stream.StreamEventKindPost, Payload: json.RawMessage(`{"id":7}`)},
+		{ID: 8, Kind: stream.StreamEventKindReactionCount, Payload: json.RawMessage(`{"post_id":7,"reaction_count":1}`)},
+	}
+
+	tests := []struct {
+		name        string
+		arg         SubscribeParams
+		mock        func() StreamResource
+		wantBacklog []StreamEvent
+		wantMissed  bool
+		wantErr     error
+	}{
+		{
+			name: "new subscription",
+			arg:  SubscribeParams{UserID: 1},
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().StreamUserExists(gomock.Any(), int32(1)).Return(true, nil)
+				return m
+			},
+		},
+		{
+			name: "resume replays missed events",
+			arg:  SubscribeParams{UserID: 1, LastEventID: 5},
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().StreamUserExists(gomock.Any(), int32(1)).Return(true, nil)
+				m.EXPECT().GetStreamEventsAfter(gomock.Any(), stream.GetStreamEventsAfterParams{
+					AfterID:  5,
+					ViewerID: 1,
+					Limit:    streamReplayLimit + 1,
+				}).Return(replayed, nil)
+				return m
+			},
+			wantBacklog: []StreamEvent{
+				{ID: 6, Kind: StreamEventPost, Data: json.RawMessage(`{"id":7}`)},
+				{ID: 8, Kind: StreamEventReactionCount, Data: json.RawMessage(`{"post_id":7,"reaction_count":1}`)},
+			},
+		},
+		{
+			name: "resume after too many events",
+			arg:  SubscribeParams{UserID: 1, LastEventID: 5},
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil)
+				m.EXPECT().GetStreamEventsAfter(gomock.Any(), gomock.Any()).Return(make([]stream.StreamEvent, streamReplayLimit+1), nil)
+				return m
+			},
+			wantMissed: true,
+		},
+		{
+			name: "user not found",
+			arg:  SubscribeParams{UserID: 1},
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(false, nil)
+				return m
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error stream user exists",
+			arg:  SubscribeParams{UserID: 1},
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(false, errors.New("error"))
+				return m
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error get stream events after",
+			arg:  SubscribeParams{UserID: 1, LastEventID: 5},
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil)
+				m.EXPECT().GetStreamEventsAfter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				return m
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := &streamService{sr: tt.mock(), bufferSize: 4, subs: map[int32]map[*Subscription]struct{}{}}
+			got, err := ss.Subscribe(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Subscribe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if len(ss.subs) != 0 {
+					t.Errorf("Subscribe() left %d users subscribed after failing", len(ss.subs))
+				}
+				return
+			}
+			if !reflect.DeepEqual(got.Backlog, tt.wantBacklog) || got.Missed != tt.wantMissed {
+				t.Errorf("Subscribe() backlog = %v, missed = %v, want %v, %v", got.Backlog, got.Missed, tt.wantBacklog, tt.wantMissed)
+			}
+			if _, ok := ss.subs[tt.arg.UserID][got]; !ok {
+				t.Errorf("Subscribe() did not register the subscription")
+			}
+		})
+	}
+}
+
+func Test_Dispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	post := stream.StreamEvent{ID: 5, Kind: stream.StreamEventKindPost, Payload: json.RawMessage(`{"id":7}`)}
+	reaction := stream.StreamEvent{ID: 6, Kind: stream.StreamEventKindReactionCount, Payload: json.RawMessage(`{"post_id":7,"reaction_count":1}`)}
+	wantPost := StreamEvent{ID: 5, Kind: StreamEventPost, Data: json.RawMessage(`{"id":7}`)}
+	wantReaction := StreamEvent{ID: 6, Kind: StreamEventReactionCount, Data: json.RawMessage(`{"post_id":7,"reaction_count":1}`)}
+
+	t.Run("nobody subscribed", func(t *testing.T) {
+		ss, _ := NewStreamService(NewMockStreamResource(ctrl), 4, 60)
+		if err := ss.Dispatch(ctx, []int64{5}); err != nil {
+			t.Errorf("Dispatch() error = %v", err)
+		}
+	})
+
+	t.Run("events reach allowed subscribers in order", func(t *testing.T) {
+		m := NewMockStreamResource(ctrl)
+		m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+		m.EXPECT().GetStreamEvents(gomock.Any(), []int64{5, 6}).Return([]stream.StreamEvent{post, reaction}, nil)
+		m.EXPECT().GetStreamRecipients(gomock.Any(), stream.GetStreamRecipientsParams{
+			ViewerIds: []int32{1, 2},
+			Ids:       []int64{5, 6},
+		}).Return([]stream.GetStreamRecipientsRow{
+			{EventID: 5, ViewerID: 1},
+			{EventID: 6, ViewerID: 1},
+			{EventID: 6, ViewerID: 2},
+		}, nil)
+		ss, _ := NewStreamService(m, 4, 60)
+		follower, _ := ss.Subscribe(ctx, SubscribeParams{UserID: 1})
+		secondTab, _ := ss.Subscribe(ctx, SubscribeParams{UserID: 1})
+		stranger, _ := ss.Subscribe(ctx, SubscribeParams{UserID: 2})
+
+		if err := ss.Dispatch(ctx, []int64{5, 6}); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		for _, sub := range []*Subscription{follower, secondTab} {
+			if got := []StreamEvent{<-sub.Events, <-sub.Events}; !reflect.DeepEqual(got, []StreamEvent{wantPost, wantReaction}) {
+				t.Errorf("follower got %v, want %v", got, []StreamEvent{wantPost, wantReaction})
+			}
+		}
+		if got := <-stranger.Events; !reflect.DeepEqual(got, wantReaction) {
+			t.Errorf("stranger got %v, want %v", got, wantReaction)
+		}
+		if len(stranger.Events) != 0 {
+			t.Errorf("stranger got %d more events, want none", len(stranger.Events))
+		}
+	})
+
+	t.Run("subscribers falling behind are dropped", func(t *testing.T) {
+		m := NewMockStreamResource(ctrl)
+		m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil)
+		m.EXPECT().GetStreamEvents(gomock.Any(), gomock.Any()).Return([]stream.StreamEvent{post, reaction}, nil)
+		m.EXPECT().GetStreamRecipients(gomock.Any(), gomock.Any()).Return([]stream.GetStreamRecipientsRow{
+			{EventID: 5, ViewerID: 1},
+			{EventID: 6, ViewerID: 1},
+		}, nil)
+		ss, _ := NewStreamService(m, 1, 60)
+		slow, _ := ss.Subscribe(ctx, SubscribeParams{UserID: 1})
+
+		if err := ss.Dispatch(ctx, []int64{5, 6}); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		if got := <-slow.Events; !reflect.DeepEqual(got, wantPost) {
+			t.Errorf("slow subscriber got %v, want %v", got, wantPost)
+		}
+		if _, ok := <-slow.Events; ok {
+			t.Errorf("slow subscriber was not dropped")
+		}
+		ss.Unsubscribe(slow)
+	})
+
+	t.Run("error get stream events", func(t *testing.T) {
+		m := NewMockStreamResource(ctrl)
+		m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil)
+		m.EXPECT().GetStreamEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		ss, _ := NewStreamService(m, 4, 60)
+		ss.Subscribe(ctx, SubscribeParams{UserID: 1})
+		if err := ss.Dispatch(ctx, []int64{5}); err == nil {
+			t.Errorf("Dispatch() error = nil, want an error")
+		}
+	})
+
+	t.Run("error get stream recipients", func(t *testing.T) {
+		m := NewMockStreamResource(ctrl)
+		m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil)
+		m.EXPECT().GetStreamEvents(gomock.Any(), gomock.Any()).Return([]stream.StreamEvent{post}, nil)
+		m.EXPECT().GetStreamRecipients(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		ss, _ := NewStreamService(m, 4, 60)
+		ss.Subscribe(ctx, SubscribeParams{UserID: 1})
+		if err := ss.Dispatch(ctx, []int64{5}); err == nil {
+			t.Errorf("Dispatch() error = nil, want an error")
+		}
+	})
+}
+
+func Test_DropAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	m := NewMockStreamResource(ctrl)
+	m.EXPECT().StreamUserExists(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+	ss, _ := NewStreamService(m, 4, 60)
+	a, _ := ss.Subscribe(ctx, SubscribeParams{UserID: 1})
+	b, _ := ss.Subscribe(ctx, SubscribeParams{UserID: 2})
+
+	ss.DropAll()
+	for _, sub := range []*Subscription{a, b} {
+		if _, ok := <-sub.Events; ok {
+			t.Errorf("subscription of user %d is still open", sub.UserID)
+		}
+	}
+	ss.Unsubscribe(a)
+	if err := ss.Dispatch(ctx, []int64{5}); err != nil {
+		t.Errorf("Dispatch() error = %v", err)
+	}
+}
+
+func Test_PurgeStreamEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mock    func() StreamResource
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success purge",
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().PurgeStreamEvents(gomock.Any(), int32(60)).Return(int64(12), nil)
+				return m
+			},
+			want: 12,
+		},
+		{
+			name: "error purge",
+			mock: func() StreamResource {
+				m := NewMockStreamResource(ctrl)
+				m.EXPECT().PurgeStreamEvents(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+				return m
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss, _ := NewStreamService(tt.mock(), 4, 60)
+			got, err := ss.PurgeExpired(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PurgeExpired() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PurgeExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import "encoding/json"
+
+// What a stream event is about. Reset is sent alone, to clients that missed
+// more than can be replayed and should reload what they show.
+const (
+	StreamEventPost          = "post"
+	StreamEventNotification  = "notification"
+	StreamEventReactionCount = "reaction_count"
+	StreamEventMessage       = "message"
+	StreamEventReset         = "reset"
+)
+
+// StreamChannel is the Postgres channel new stream events are announced on,
+// with their id as payload.
+const StreamChannel = "stream_events"
+
+type StreamEvent struct {
+	ID   int64
+	Kind string
+	Data json.RawMessage
+}
+
+type SubscribeParams struct {
+	UserID int32
+	// LastEventID, when not 0, replays the events after it.
+	LastEventID int64
+}
+
+type Subscription struct {
+	UserID int32
+	// Backlog holds the replayed events, oldest first. Events may reach
+	// both Backlog and Events, so live events up to the last replayed id
+	// are repeats.
+	Backlog []StreamEvent
+	// Missed is set, and Backlog left empty, when there were more events to
+	// replay than a subscription takes.
+	Missed bool
+	// Events receives live events. It is closed when the subscription is
+	// dropped, which also happens when the client falls behind.
+	Events <-chan StreamEvent
+
+	events chan StreamEvent
+}
@@ -2,15 +2,23 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/gadhittana01/socialmedia/pkg/tag"
 )
 
 type TagService interface {
 	GetTags(ctx context.Context) ([]GetTagsRow, error)
+	SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error)
 	CreateTag(ctx context.Context, tagname string) (CreateTagRow, error)
 	UpdateTag(ctx context.Context, arg UpdateTagParams) (UpdateTagRow, error)
-	DeleteTag(ctx context.Context, id int32) error
+	DeleteTag(ctx context.Context, arg DeleteTagParams) error
+	PatchTag(ctx context.Context, arg PatchTagParams) (PatchTagRow, error)
+	GetTag(ctx context.Context, id int32) (GetTagRow, error)
+	FollowTag(ctx context.Context, arg FollowTagParams) error
+	UnfollowTag(ctx context.Context, arg UnfollowTagParams) error
+	GetFollowedTags(ctx context.Context, userID int32) ([]GetTagsRow, error)
 }
 
 type tagService struct {
@@ -27,6 +35,7 @@ func (ts *tagService) GetTags(ctx context.Context) ([]GetTagsRow, error) {
 	var result []GetTagsRow = []GetTagsRow{}
 	res, err := ts.tr.GetTags(ctx)
 	if err != nil {
+		logSQLError(ctx, "GetTags", err)
 		return result, err
 	}
 
@@ -39,8 +48,9 @@ func (ts *tagService) GetTags(ctx context.Context) ([]GetTagsRow, error) {
 
 func (ts *tagService) CreateTag(ctx context.Context, tagname string) (CreateTagRow, error) {
 	var result CreateTagRow = CreateTagRow{}
-	res, err := ts.tr.CreateTag(context.Background(), tagname)
+	res, err := ts.tr.CreateTag(ctx, tagname)
 	if err != nil {
+		logSQLError(ctx, "CreateTag", err)
 		return result, err
 	}
 	result = CreateTagRow{
@@ -50,36 +60,166 @@ func (ts *tagService) CreateTag(ctx context.Context, tagname string) (CreateTagR
 	return result, nil
 }
 
+func (ts *tagService) GetTag(ctx context.Context, id int32) (GetTagRow, error) {
+	var result GetTagRow = GetTagRow{}
+	res, err := ts.tr.GetTag(ctx, id)
+	if err != nil {
+		logSQLError(ctx, "GetTag", err)
+		return result, err
+	}
+	result = GetTagRow{
+		ID:      res.ID,
+		Tagname: res.Tagname,
+		Version: res.Version,
+	}
+	return result, nil
+}
+
 func (ts *tagService) UpdateTag(ctx context.Context, arg UpdateTagParams) (UpdateTagRow, error) {
 	var result UpdateTagRow = UpdateTagRow{}
-	_, err := ts.tr.GetTag(context.Background(), int32(arg.ID))
+	current, err := ts.tr.GetTag(ctx, arg.ID)
 	if err != nil {
+		logSQLError(ctx, "GetTag", err)
 		return result, err
 	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return result, ErrVersionConflict
+	}
 
-	res, err := ts.tr.UpdateTag(context.Background(), tag.UpdateTagParams{
+	res, err := ts.tr.UpdateTag(ctx, tag.UpdateTagParams{
 		ID:      arg.ID,
 		Tagname: arg.Tagname,
+		Version: nullVersion(arg.Version),
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrVersionConflict
+	}
 	if err != nil {
+		logSQLError(ctx, "UpdateTag", err)
 		return result, err
 	}
 	result = UpdateTagRow{
 		ID:      res.ID,
 		Tagname: res.Tagname,
+		Version: res.Version,
 	}
 	return result, nil
 }
 
-func (ts *tagService) DeleteTag(ctx context.Context, id int32) error {
-	_, err := ts.tr.GetTag(context.Background(), int32(id))
+func (ts *tagService) DeleteTag(ctx context.Context, arg DeleteTagParams) error {
+	current, err := ts.tr.GetTag(ctx, arg.ID)
 	if err != nil {
+		logSQLError(ctx, "GetTag", err)
 		return err
 	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return ErrVersionConflict
+	}
 
-	err = ts.tr.DeleteTag(context.Background(), id)
+	deleted, err := ts.tr.DeleteTag(ctx, tag.DeleteTagParams{
+		ID:      arg.ID,
+		Version: nullVersion(arg.Version),
+	})
 	if err != nil {
+		logSQLError(ctx, "DeleteTag", err)
 		return err
 	}
+	if deleted == 0 {
+		return ErrVersionConflict
+	}
 	return nil
 }
+
+func (ts *tagService) PatchTag(ctx context.Context, arg PatchTagParams) (PatchTagRow, error) {
+	var result PatchTagRow = PatchTagRow{}
+	current, err := ts.tr.GetTag(ctx, arg.ID)
+	if err != nil {
+		logSQLError(ctx, "GetTag", err)
+		return result, err
+	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return result, ErrVersionConflict
+	}
+
+	res, err := ts.tr.PatchTag(ctx, tag.PatchTagParams{
+		ID:      arg.ID,
+		Tagname: nullString(arg.Tagname),
+		Version: nullVersion(arg.Version),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrVersionConflict
+	}
+	if err != nil {
+		logSQLError(ctx, "PatchTag", err)
+		return result, err
+	}
+	result = PatchTagRow{
+		ID:      res.ID,
+		Tagname: res.Tagname,
+		Version: res.Version,
+	}
+	return result, nil
+}
+
+func (ts *tagService) SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error) {
+	var result []SearchTagsRow = []SearchTagsRow{}
+	res, err := ts.tr.SearchTags(ctx, tag.SearchTagsParams{
+		Prefix: likePrefix(arg.Query),
+		Query:  arg.Query,
+		Limit:  arg.Limit,
+	})
+	if err != nil {
+		logSQLError(ctx, "SearchTags", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, SearchTagsRow{
+			ID:         item.ID,
+			Tagname:    item.Tagname,
+			UsageCount: item.UsageCount,
+		})
+	}
+	return result, nil
+}
+
+// FollowTag succeeds when the user already follows the tag. It returns
+// sql.ErrNoRows when the user or the tag does not exist.
+func (ts *tagService) FollowTag(ctx context.Context, arg FollowTagParams) error {
+	followed, err := ts.tr.FollowTag(ctx, tag.FollowTagParams{
+		UserID: arg.UserID,
+		TagID:  arg.TagID,
+	})
+	if err != nil {
+		logSQLError(ctx, "FollowTag", err)
+		return err
+	}
+	if followed == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (ts *tagService) UnfollowTag(ctx context.Context, arg UnfollowTagParams) error {
+	err := ts.tr.UnfollowTag(ctx, tag.UnfollowTagParams{
+		UserID: arg.UserID,
+		TagID:  arg.TagID,
+	})
+	if err != nil {
+		logSQLError(ctx, "UnfollowTag", err)
+		return err
+	}
+	return nil
+}
+
+func (ts *tagService) GetFollowedTags(ctx context.Context, userID int32) ([]GetTagsRow, error) {
+	var result []GetTagsRow = []GetTagsRow{}
+	res, err := ts.tr.GetFollowedTags(ctx, userID)
+	if err != nil {
+		logSQLError(ctx, "GetFollowedTags", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, GetTagsRow(item))
+	}
+	return result, nil
+}
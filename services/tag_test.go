@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
 	"testing"
@@ -123,6 +124,89 @@ func Test_GetTags(t *testing.T) {
 	}
 }
 
+func Test_SearchTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		arg SearchTagsParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *tagService
+		want    []SearchTagsRow
+		wantErr bool
+	}{
+		{
+			name: "success search tags",
+			args: args{
+				ctx: ctx,
+				arg: SearchTagsParams{Query: "100%", Limit: 10},
+			},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+
+				m.EXPECT().SearchTags(gomock.Any(), tag.SearchTagsParams{
+					Prefix: `100\%%`,
+					Query:  "100%",
+					Limit:  10,
+				}).Return([]tag.SearchTagsRow{
+					{
+						ID:         1,
+						Tagname:    "100%",
+						UsageCount: 3,
+					},
+				}, nil)
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			want: []SearchTagsRow{
+				{
+					ID:         1,
+					Tagname:    "100%",
+					UsageCount: 3,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error search tags",
+			args: args{
+				ctx: ctx,
+				arg: SearchTagsParams{Query: "100%", Limit: 10},
+			},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+
+				m.EXPECT().SearchTags(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			want:    []SearchTagsRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.SearchTags(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_CreateTag(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ctx := context.Background()
@@ -296,6 +380,64 @@ func Test_UpdateTag(t *testing.T) {
 			want:    UpdateTagRow{},
 			wantErr: true,
 		},
+		{
+			name: "error version conflict",
+			args: args{
+				ctx: ctx,
+				arg: UpdateTagParams{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 1,
+				},
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 2,
+				}, nil)
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want:    UpdateTagRow{},
+			wantErr: true,
+		},
+		{
+			name: "error version changed before update",
+			args: args{
+				ctx: ctx,
+				arg: UpdateTagParams{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 2,
+				},
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 2,
+				}, nil)
+
+				tagMock.EXPECT().UpdateTag(gomock.Any(), tag.UpdateTagParams{
+					ID:      1,
+					Tagname: "holiday",
+					Version: sql.NullInt32{Int32: 2, Valid: true},
+				}).Return(tag.UpdateTagRow{}, sql.ErrNoRows)
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want:    UpdateTagRow{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -318,7 +460,7 @@ func Test_DeleteTag(t *testing.T) {
 
 	type args struct {
 		ctx context.Context
-		id  int32
+		arg DeleteTagParams
 	}
 	tests := []struct {
 		name    string
@@ -330,7 +472,9 @@ func Test_DeleteTag(t *testing.T) {
 			name: "success delete tag",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: DeleteTagParams{
+					ID: 1,
+				},
 			},
 			mock: func() *tagService {
 				tagMock := NewMockTagResource(ctrl)
@@ -340,7 +484,9 @@ func Test_DeleteTag(t *testing.T) {
 					Tagname: "holiday",
 				}, nil)
 
-				tagMock.EXPECT().DeleteTag(gomock.Any(), int32(1)).Return(nil)
+				tagMock.EXPECT().DeleteTag(gomock.Any(), tag.DeleteTagParams{
+					ID: 1,
+				}).Return(int64(1), nil)
 
 				return &tagService{
 					tr: tagMock,
@@ -352,7 +498,9 @@ func Test_DeleteTag(t *testing.T) {
 			name: "error get tag",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: DeleteTagParams{
+					ID: 1,
+				},
 			},
 			mock: func() *tagService {
 				tagMock := NewMockTagResource(ctrl)
@@ -369,7 +517,9 @@ func Test_DeleteTag(t *testing.T) {
 			name: "error delete tag",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: DeleteTagParams{
+					ID: 1,
+				},
 			},
 			mock: func() *tagService {
 				tagMock := NewMockTagResource(ctrl)
@@ -379,7 +529,9 @@ func Test_DeleteTag(t *testing.T) {
 					Tagname: "holiday",
 				}, nil)
 
-				tagMock.EXPECT().DeleteTag(gomock.Any(), int32(1)).Return(errors.New("error"))
+				tagMock.EXPECT().DeleteTag(gomock.Any(), tag.DeleteTagParams{
+					ID: 1,
+				}).Return(int64(0), errors.New("error"))
 
 				return &tagService{
 					tr: tagMock,
@@ -391,7 +543,7 @@ func Test_DeleteTag(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.mock()
-			err := p.DeleteTag(tt.args.ctx, tt.args.id)
+			err := p.DeleteTag(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteTag() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -399,3 +551,367 @@ func Test_DeleteTag(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	tagTagname := "holiday"
+
+	type args struct {
+		ctx context.Context
+		arg PatchTagParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *tagService
+		want    PatchTagRow
+		wantErr bool
+	}{
+		{
+			name: "success patch tag",
+			args: args{
+				ctx: ctx,
+				arg: PatchTagParams{
+					ID:      1,
+					Tagname: &tagTagname,
+				},
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{
+					ID:      1,
+					Tagname: "old",
+				}, nil)
+
+				tagMock.EXPECT().PatchTag(gomock.Any(), tag.PatchTagParams{
+					ID:      1,
+					Tagname: sql.NullString{String: "holiday", Valid: true},
+				}).Return(tag.PatchTagRow{
+					ID:      1,
+					Tagname: "holiday",
+				}, nil)
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want: PatchTagRow{
+				ID:      1,
+				Tagname: "holiday",
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get tag",
+			args: args{
+				ctx: ctx,
+				arg: PatchTagParams{
+					ID:      1,
+					Tagname: &tagTagname,
+				},
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{}, sql.ErrNoRows)
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want:    PatchTagRow{},
+			wantErr: true,
+		},
+		{
+			name: "error patch tag",
+			args: args{
+				ctx: ctx,
+				arg: PatchTagParams{
+					ID: 1,
+				},
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{
+					ID:      1,
+					Tagname: "old",
+				}, nil)
+
+				tagMock.EXPECT().PatchTag(gomock.Any(), tag.PatchTagParams{
+					ID: 1,
+				}).Return(tag.PatchTagRow{}, errors.New("error"))
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want:    PatchTagRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.PatchTag(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		id  int32
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *tagService
+		want    GetTagRow
+		wantErr bool
+	}{
+		{
+			name: "success get tag",
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{
+					ID:      1,
+					Tagname: "holiday",
+					Version: 3,
+				}, nil)
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want: GetTagRow{
+				ID:      1,
+				Tagname: "holiday",
+				Version: 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get tag",
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			mock: func() *tagService {
+				tagMock := NewMockTagResource(ctrl)
+
+				tagMock.EXPECT().GetTag(gomock.Any(), int32(1)).Return(tag.GetTagRow{}, sql.ErrNoRows)
+
+				return &tagService{
+					tr: tagMock,
+				}
+			},
+			want:    GetTagRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetTag(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FollowTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     FollowTagParams
+		mock    func() *tagService
+		wantErr error
+	}{
+		{
+			name: "success follow tag",
+			arg:  FollowTagParams{UserID: 1, TagID: 2},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().FollowTag(gomock.Any(), tag.FollowTagParams{UserID: 1, TagID: 2}).Return(int64(1), nil)
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			wantErr: nil,
+		},
+		{
+			name: "user or tag not found",
+			arg:  FollowTagParams{UserID: 1, TagID: 2},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().FollowTag(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error follow tag",
+			arg:  FollowTagParams{UserID: 1, TagID: 2},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().FollowTag(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.FollowTag(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("FollowTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_UnfollowTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     UnfollowTagParams
+		mock    func() *tagService
+		wantErr bool
+	}{
+		{
+			name: "success unfollow tag",
+			arg:  UnfollowTagParams{UserID: 1, TagID: 2},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().UnfollowTag(gomock.Any(), tag.UnfollowTagParams{UserID: 1, TagID: 2}).Return(nil)
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unfollow tag",
+			arg:  UnfollowTagParams{UserID: 1, TagID: 2},
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().UnfollowTag(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.UnfollowTag(ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnfollowTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetFollowedTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		userID  int32
+		mock    func() *tagService
+		want    []GetTagsRow
+		wantErr bool
+	}{
+		{
+			name:   "success get followed tags",
+			userID: 1,
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().GetFollowedTags(gomock.Any(), int32(1)).Return([]tag.GetFollowedTagsRow{
+					{
+						ID:      1,
+						Tagname: "holiday",
+					},
+				}, nil)
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			want: []GetTagsRow{
+				{
+					ID:      1,
+					Tagname: "holiday",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "error get followed tags",
+			userID: 1,
+			mock: func() *tagService {
+				m := NewMockTagResource(ctrl)
+				m.EXPECT().GetFollowedTags(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &tagService{
+					tr: m,
+				}
+			},
+			want:    []GetTagsRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.GetFollowedTags(ctx, tt.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFollowedTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFollowedTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package services
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 type CreateTagRow struct {
 	ID      int32  `json:"id"`
@@ -23,14 +26,76 @@ type Tag struct {
 type UpdateTagParams struct {
 	ID      int32
 	Tagname string
+	// Version is the expected current version, or 0 to skip the check.
+	Version int32
 }
 
 type UpdateTagRow struct {
 	ID      int32  `json:"id"`
 	Tagname string `json:"tagname"`
+	Version int32  `json:"version"`
 }
 
 type GetTagsRow struct {
 	ID      int32  `json:"id"`
 	Tagname string `json:"tagname"`
 }
+
+type PatchTagParams struct {
+	ID      int32
+	Tagname *string
+	Version int32
+}
+
+type PatchTagRow struct {
+	ID      int32  `json:"id"`
+	Tagname string `json:"tagname"`
+	Version int32  `json:"version"`
+}
+
+type GetTagRow struct {
+	ID      int32  `json:"id"`
+	Tagname string `json:"tagname"`
+	Version int32  `json:"version"`
+}
+
+type DeleteTagParams struct {
+	ID      int32
+	Version int32
+}
+
+type SearchTagsParams struct {
+	Query string
+	Limit int32
+}
+
+type SearchTagsRow struct {
+	ID         int32  `json:"id"`
+	Tagname    string `json:"tagname"`
+	UsageCount int64  `json:"usage_count"`
+}
+
+type GetTrendingTagsParams struct {
+	// Window is one of TrendingWindows.
+	Window time.Duration
+	Limit  int32
+}
+
+type GetTrendingTagsRow struct {
+	ID            int32     `json:"id"`
+	Tagname       string    `json:"tagname"`
+	RecentCount   int32     `json:"recent_count"`
+	BaselineCount int32     `json:"baseline_count"`
+	Score         float64   `json:"score"`
+	ComputedAt    time.Time `json:"computed_at"`
+}
+
+type FollowTagParams struct {
+	UserID int32
+	TagID  int32
+}
+
+type UnfollowTagParams struct {
+	UserID int32
+	TagID  int32
+}
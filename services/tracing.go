@@ -0,0 +1,1042 @@
+package services
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/gadhittana01/socialmedia/services"
+
+type tracedPostService struct {
+	next   PostService
+	tracer trace.Tracer
+}
+
+func NewTracedPostService(next PostService, tp trace.TracerProvider) PostService {
+	return &tracedPostService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedPostService) CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.CreatePost", trace.WithAttributes(
+		attribute.Int("post.user_id", int(arg.Userid)),
+		attribute.Int("post.tag_count", len(arg.TagID)),
+	))
+	defer span.End()
+
+	res, err := t.next.CreatePost(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) GetPosts(ctx context.Context, arg GetPostsParams) ([]GetPostsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetPosts", trace.WithAttributes(
+		attribute.String("post.sort", arg.Sort),
+		attribute.Int("post.tag_filter_count", len(arg.TagIDs)),
+		attribute.Int("post.limit", int(arg.Limit)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetPosts(ctx, arg)
+	span.SetAttributes(attribute.Int("post.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.SearchPosts", trace.WithAttributes(
+		attribute.Int("search.limit", int(arg.Limit)),
+		attribute.Int("search.offset", int(arg.Offset)),
+	))
+	defer span.End()
+
+	res, err := t.next.SearchPosts(ctx, arg)
+	span.SetAttributes(attribute.Int("post.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) GetTagFeed(ctx context.Context, arg GetTagFeedParams) ([]GetPostsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetTagFeed", trace.WithAttributes(
+		attribute.Int("feed.user_id", int(arg.UserID)),
+		attribute.Int("feed.limit", int(arg.Limit)),
+		attribute.Int("feed.offset", int(arg.Offset)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetTagFeed(ctx, arg)
+	span.SetAttributes(attribute.Int("post.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) GetBookmarks(ctx context.Context, arg GetBookmarksParams) ([]GetPostsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetBookmarks", trace.WithAttributes(
+		attribute.Int("bookmark.user_id", int(arg.UserID)),
+		attribute.Int("bookmark.collection_id", int(arg.CollectionID)),
+		attribute.Int("bookmark.limit", int(arg.Limit)),
+		attribute.Int("bookmark.offset", int(arg.Offset)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetBookmarks(ctx, arg)
+	span.SetAttributes(attribute.Int("post.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) Repost(ctx context.Context, arg RepostParams) (RepostRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.Repost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.PostID)),
+		attribute.Int("post.reposter_id", int(arg.UserID)),
+		attribute.Bool("post.quote", arg.Quote != ""),
+	))
+	defer span.End()
+
+	res, err := t.next.Repost(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) Unrepost(ctx context.Context, arg UnrepostParams) error {
+	ctx, span := t.tracer.Start(ctx, "PostService.Unrepost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.PostID)),
+		attribute.Int("post.reposter_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	err := t.next.Unrepost(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedPostService) React(ctx context.Context, arg ReactParams) error {
+	ctx, span := t.tracer.Start(ctx, "PostService.React", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.PostID)),
+		attribute.Int("reaction.user_id", int(arg.UserID)),
+		attribute.String("reaction.kind", arg.Kind),
+	))
+	defer span.End()
+
+	err := t.next.React(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedPostService) Unreact(ctx context.Context, arg UnreactParams) error {
+	ctx, span := t.tracer.Start(ctx, "PostService.Unreact", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.PostID)),
+		attribute.Int("reaction.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	err := t.next.Unreact(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedPostService) GetThread(ctx context.Context, arg GetThreadParams) (GetThreadRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetThread", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("thread.depth", int(arg.Depth)),
+		attribute.Int("thread.limit", int(arg.Limit)),
+		attribute.Int("thread.offset", int(arg.Offset)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetThread(ctx, arg)
+	span.SetAttributes(attribute.Int("thread.ancestor_count", len(res.Ancestors)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) UpdatePost(ctx context.Context, arg UpdatePostParams) (UpdatePostRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.UpdatePost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("post.tag_count", len(arg.TagID)),
+	))
+	defer span.End()
+
+	res, err := t.next.UpdatePost(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) GetPost(ctx context.Context, arg GetPostParams) (GetPostRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetPost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("post.viewer_id", int(arg.ViewerID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetPost(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) DeletePost(ctx context.Context, arg DeletePostParams) error {
+	ctx, span := t.tracer.Start(ctx, "PostService.DeletePost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("post.version", int(arg.Version)),
+	))
+	defer span.End()
+
+	err := t.next.DeletePost(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedPostService) PatchPost(ctx context.Context, arg PatchPostParams) (PatchPostRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.PatchPost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Bool("post.replace_tags", arg.ReplaceTags),
+	))
+	defer span.End()
+
+	res, err := t.next.PatchPost(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) GetPostRevisions(ctx context.Context, arg GetPostRevisionsParams) ([]PostRevision, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetPostRevisions", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("revision.limit", int(arg.Limit)),
+		attribute.Int("revision.offset", int(arg.Offset)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetPostRevisions(ctx, arg)
+	span.SetAttributes(attribute.Int("revision.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) GetPostRevisionDiff(ctx context.Context, arg GetPostRevisionDiffParams) (PostRevisionDiff, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.GetPostRevisionDiff", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("revision.from", int(arg.From)),
+		attribute.Int("revision.to", int(arg.To)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetPostRevisionDiff(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedPostService) RevertPost(ctx context.Context, arg RevertPostParams) (UpdatePostRow, error) {
+	ctx, span := t.tracer.Start(ctx, "PostService.RevertPost", trace.WithAttributes(
+		attribute.Int("post.id", int(arg.ID)),
+		attribute.Int("revision.number", int(arg.Revision)),
+	))
+	defer span.End()
+
+	res, err := t.next.RevertPost(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedUserService struct {
+	next   UserService
+	tracer trace.Tracer
+}
+
+func NewTracedUserService(next UserService, tp trace.TracerProvider) UserService {
+	return &tracedUserService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedUserService) CreateUser(ctx context.Context, fullname string) (CreateUserRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
+	res, err := t.next.CreateUser(ctx, fullname)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.GetUsers")
+	defer span.End()
+
+	res, err := t.next.GetUsers(ctx)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.SearchUsers", trace.WithAttributes(
+		attribute.Int("search.limit", int(arg.Limit)),
+	))
+	defer span.End()
+
+	res, err := t.next.SearchUsers(ctx, arg)
+	span.SetAttributes(attribute.Int("user.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.UpdateUser", trace.WithAttributes(
+		attribute.Int("user.id", int(arg.ID)),
+	))
+	defer span.End()
+
+	res, err := t.next.UpdateUser(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) GetUser(ctx context.Context, id int32) (GetUserRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.GetUser", trace.WithAttributes(
+		attribute.Int("user.id", int(id)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetUser(ctx, id)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) DeleteUser(ctx context.Context, arg DeleteUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.DeleteUser", trace.WithAttributes(
+		attribute.Int("user.id", int(arg.ID)),
+		attribute.Int("user.version", int(arg.Version)),
+	))
+	defer span.End()
+
+	err := t.next.DeleteUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) PatchUser(ctx context.Context, arg PatchUserParams) (PatchUserRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.PatchUser", trace.WithAttributes(
+		attribute.Int("user.id", int(arg.ID)),
+	))
+	defer span.End()
+
+	res, err := t.next.PatchUser(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) FollowUser(ctx context.Context, arg FollowUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.FollowUser", trace.WithAttributes(
+		attribute.Int("user.follower_id", int(arg.FollowerID)),
+		attribute.Int("user.followee_id", int(arg.FolloweeID)),
+	))
+	defer span.End()
+
+	err := t.next.FollowUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) UnfollowUser(ctx context.Context, arg UnfollowUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.UnfollowUser", trace.WithAttributes(
+		attribute.Int("user.follower_id", int(arg.FollowerID)),
+		attribute.Int("user.followee_id", int(arg.FolloweeID)),
+	))
+	defer span.End()
+
+	err := t.next.UnfollowUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) BlockUser(ctx context.Context, arg BlockUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.BlockUser", trace.WithAttributes(
+		attribute.Int("user.blocker_id", int(arg.BlockerID)),
+		attribute.Int("user.blocked_id", int(arg.BlockedID)),
+	))
+	defer span.End()
+
+	err := t.next.BlockUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) UnblockUser(ctx context.Context, arg UnblockUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.UnblockUser", trace.WithAttributes(
+		attribute.Int("user.blocker_id", int(arg.BlockerID)),
+		attribute.Int("user.blocked_id", int(arg.BlockedID)),
+	))
+	defer span.End()
+
+	err := t.next.UnblockUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) GetBlockedUsers(ctx context.Context, userID int32) ([]GetUsersRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.GetBlockedUsers", trace.WithAttributes(
+		attribute.Int("user.id", int(userID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetBlockedUsers(ctx, userID)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedUserService) MuteUser(ctx context.Context, arg MuteUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.MuteUser", trace.WithAttributes(
+		attribute.Int("user.muter_id", int(arg.MuterID)),
+		attribute.Int("user.muted_id", int(arg.MutedID)),
+	))
+	defer span.End()
+
+	err := t.next.MuteUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) UnmuteUser(ctx context.Context, arg UnmuteUserParams) error {
+	ctx, span := t.tracer.Start(ctx, "UserService.UnmuteUser", trace.WithAttributes(
+		attribute.Int("user.muter_id", int(arg.MuterID)),
+		attribute.Int("user.muted_id", int(arg.MutedID)),
+	))
+	defer span.End()
+
+	err := t.next.UnmuteUser(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUserService) GetMutedUsers(ctx context.Context, userID int32) ([]GetUsersRow, error) {
+	ctx, span := t.tracer.Start(ctx, "UserService.GetMutedUsers", trace.WithAttributes(
+		attribute.Int("user.id", int(userID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetMutedUsers(ctx, userID)
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedTagService struct {
+	next   TagService
+	tracer trace.Tracer
+}
+
+func NewTracedTagService(next TagService, tp trace.TracerProvider) TagService {
+	return &tracedTagService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedTagService) GetTags(ctx context.Context) ([]GetTagsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.GetTags")
+	defer span.End()
+
+	res, err := t.next.GetTags(ctx)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTagService) CreateTag(ctx context.Context, tagname string) (CreateTagRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.CreateTag")
+	defer span.End()
+
+	res, err := t.next.CreateTag(ctx, tagname)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTagService) SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.SearchTags", trace.WithAttributes(
+		attribute.Int("search.limit", int(arg.Limit)),
+	))
+	defer span.End()
+
+	res, err := t.next.SearchTags(ctx, arg)
+	span.SetAttributes(attribute.Int("tag.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTagService) UpdateTag(ctx context.Context, arg UpdateTagParams) (UpdateTagRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.UpdateTag", trace.WithAttributes(
+		attribute.Int("tag.id", int(arg.ID)),
+	))
+	defer span.End()
+
+	res, err := t.next.UpdateTag(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTagService) GetTag(ctx context.Context, id int32) (GetTagRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.GetTag", trace.WithAttributes(
+		attribute.Int("tag.id", int(id)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetTag(ctx, id)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTagService) DeleteTag(ctx context.Context, arg DeleteTagParams) error {
+	ctx, span := t.tracer.Start(ctx, "TagService.DeleteTag", trace.WithAttributes(
+		attribute.Int("tag.id", int(arg.ID)),
+		attribute.Int("tag.version", int(arg.Version)),
+	))
+	defer span.End()
+
+	err := t.next.DeleteTag(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedTagService) PatchTag(ctx context.Context, arg PatchTagParams) (PatchTagRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.PatchTag", trace.WithAttributes(
+		attribute.Int("tag.id", int(arg.ID)),
+	))
+	defer span.End()
+
+	res, err := t.next.PatchTag(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTagService) FollowTag(ctx context.Context, arg FollowTagParams) error {
+	ctx, span := t.tracer.Start(ctx, "TagService.FollowTag", trace.WithAttributes(
+		attribute.Int("tag.id", int(arg.TagID)),
+		attribute.Int("tag.follower_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	err := t.next.FollowTag(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedTagService) UnfollowTag(ctx context.Context, arg UnfollowTagParams) error {
+	ctx, span := t.tracer.Start(ctx, "TagService.UnfollowTag", trace.WithAttributes(
+		attribute.Int("tag.id", int(arg.TagID)),
+		attribute.Int("tag.follower_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	err := t.next.UnfollowTag(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedTagService) GetFollowedTags(ctx context.Context, userID int32) ([]GetTagsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TagService.GetFollowedTags", trace.WithAttributes(
+		attribute.Int("tag.follower_id", int(userID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetFollowedTags(ctx, userID)
+	span.SetAttributes(attribute.Int("tag.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedBookmarkService struct {
+	next   BookmarkService
+	tracer trace.Tracer
+}
+
+func NewTracedBookmarkService(next BookmarkService, tp trace.TracerProvider) BookmarkService {
+	return &tracedBookmarkService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedBookmarkService) CreateCollection(ctx context.Context, arg CreateCollectionParams) (CreateCollectionRow, error) {
+	ctx, span := t.tracer.Start(ctx, "BookmarkService.CreateCollection", trace.WithAttributes(
+		attribute.Int("bookmark.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.CreateCollection(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedBookmarkService) RenameCollection(ctx context.Context, arg RenameCollectionParams) (RenameCollectionRow, error) {
+	ctx, span := t.tracer.Start(ctx, "BookmarkService.RenameCollection", trace.WithAttributes(
+		attribute.Int("bookmark.collection_id", int(arg.ID)),
+		attribute.Int("bookmark.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.RenameCollection(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedBookmarkService) DeleteCollection(ctx context.Context, arg DeleteCollectionParams) error {
+	ctx, span := t.tracer.Start(ctx, "BookmarkService.DeleteCollection", trace.WithAttributes(
+		attribute.Int("bookmark.collection_id", int(arg.ID)),
+		attribute.Int("bookmark.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	err := t.next.DeleteCollection(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedBookmarkService) GetCollections(ctx context.Context, userID int32) ([]GetCollectionsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "BookmarkService.GetCollections", trace.WithAttributes(
+		attribute.Int("bookmark.user_id", int(userID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetCollections(ctx, userID)
+	span.SetAttributes(attribute.Int("collection.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedBookmarkService) AddBookmark(ctx context.Context, arg AddBookmarkParams) error {
+	ctx, span := t.tracer.Start(ctx, "BookmarkService.AddBookmark", trace.WithAttributes(
+		attribute.Int("bookmark.user_id", int(arg.UserID)),
+		attribute.Int("bookmark.post_id", int(arg.PostID)),
+		attribute.Int("bookmark.collection_id", int(arg.CollectionID)),
+	))
+	defer span.End()
+
+	err := t.next.AddBookmark(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedBookmarkService) RemoveBookmark(ctx context.Context, arg RemoveBookmarkParams) error {
+	ctx, span := t.tracer.Start(ctx, "BookmarkService.RemoveBookmark", trace.WithAttributes(
+		attribute.Int("bookmark.user_id", int(arg.UserID)),
+		attribute.Int("bookmark.post_id", int(arg.PostID)),
+		attribute.Int("bookmark.collection_id", int(arg.CollectionID)),
+	))
+	defer span.End()
+
+	err := t.next.RemoveBookmark(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+type tracedTrendingService struct {
+	next   TrendingService
+	tracer trace.Tracer
+}
+
+func NewTracedTrendingService(next TrendingService, tp trace.TracerProvider) TrendingService {
+	return &tracedTrendingService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedTrendingService) GetTrendingTags(ctx context.Context, arg GetTrendingTagsParams) ([]GetTrendingTagsRow, error) {
+	ctx, span := t.tracer.Start(ctx, "TrendingService.GetTrendingTags", trace.WithAttributes(
+		attribute.String("trending.window", arg.Window.String()),
+		attribute.Int("trending.limit", int(arg.Limit)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetTrendingTags(ctx, arg)
+	span.SetAttributes(attribute.Int("tag.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedTrendingService) RefreshTrendingTags(ctx context.Context) error {
+	ctx, span := t.tracer.Start(ctx, "TrendingService.RefreshTrendingTags")
+	defer span.End()
+
+	err := t.next.RefreshTrendingTags(ctx)
+	endSpan(span, err)
+	return err
+}
+
+type tracedSchedulerService struct {
+	next   SchedulerService
+	tracer trace.Tracer
+}
+
+func NewTracedSchedulerService(next SchedulerService, tp trace.TracerProvider) SchedulerService {
+	return &tracedSchedulerService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedSchedulerService) PublishDuePosts(ctx context.Context) (int, error) {
+	ctx, span := t.tracer.Start(ctx, "SchedulerService.PublishDuePosts")
+	defer span.End()
+
+	n, err := t.next.PublishDuePosts(ctx)
+	span.SetAttributes(attribute.Int("post.count", n))
+	endSpan(span, err)
+	return n, err
+}
+
+type tracedMediaService struct {
+	next   MediaService
+	tracer trace.Tracer
+}
+
+func NewTracedMediaService(next MediaService, tp trace.TracerProvider) MediaService {
+	return &tracedMediaService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedMediaService) UploadMedia(ctx context.Context, arg UploadMediaParams) (MediaRow, error) {
+	ctx, span := t.tracer.Start(ctx, "MediaService.UploadMedia", trace.WithAttributes(
+		attribute.Int("media.user_id", int(arg.UserID)),
+		attribute.Int("media.size", len(arg.Data)),
+	))
+	defer span.End()
+
+	res, err := t.next.UploadMedia(ctx, arg)
+	span.SetAttributes(attribute.String("media.content_type", res.ContentType))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMediaService) GetMediaContent(ctx context.Context, arg GetMediaContentParams) (MediaContent, error) {
+	ctx, span := t.tracer.Start(ctx, "MediaService.GetMediaContent", trace.WithAttributes(
+		attribute.Int("media.id", int(arg.ID)),
+		attribute.Int("media.viewer_id", int(arg.ViewerID)),
+		attribute.Bool("media.thumbnail", arg.Thumbnail),
+	))
+	defer span.End()
+
+	res, err := t.next.GetMediaContent(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedProfileService struct {
+	next   ProfileService
+	tracer trace.Tracer
+}
+
+func NewTracedProfileService(next ProfileService, tp trace.TracerProvider) ProfileService {
+	return &tracedProfileService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedProfileService) GetProfile(ctx context.Context, arg GetProfileParams) (ProfileRow, error) {
+	ctx, span := t.tracer.Start(ctx, "ProfileService.GetProfile", trace.WithAttributes(
+		attribute.String("profile.handle", arg.Handle),
+		attribute.Int("profile.viewer_id", int(arg.ViewerID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetProfile(ctx, arg)
+	span.SetAttributes(attribute.Int("profile.user_id", int(res.ID)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedProfileService) SetProfile(ctx context.Context, arg SetProfileParams) (SetProfileRow, error) {
+	ctx, span := t.tracer.Start(ctx, "ProfileService.SetProfile", trace.WithAttributes(
+		attribute.Int("profile.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.SetProfile(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedNotificationService struct {
+	next   NotificationService
+	tracer trace.Tracer
+}
+
+func NewTracedNotificationService(next NotificationService, tp trace.TracerProvider) NotificationService {
+	return &tracedNotificationService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedNotificationService) ProcessEvents(ctx context.Context) (int, error) {
+	ctx, span := t.tracer.Start(ctx, "NotificationService.ProcessEvents")
+	defer span.End()
+
+	n, err := t.next.ProcessEvents(ctx)
+	span.SetAttributes(attribute.Int("notification.event_count", n))
+	endSpan(span, err)
+	return n, err
+}
+
+func (t *tracedNotificationService) GetNotifications(ctx context.Context, arg GetNotificationsParams) ([]NotificationRow, error) {
+	ctx, span := t.tracer.Start(ctx, "NotificationService.GetNotifications", trace.WithAttributes(
+		attribute.Int("notification.user_id", int(arg.UserID)),
+		attribute.Bool("notification.unread_only", arg.UnreadOnly),
+	))
+	defer span.End()
+
+	res, err := t.next.GetNotifications(ctx, arg)
+	span.SetAttributes(attribute.Int("notification.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedNotificationService) MarkRead(ctx context.Context, arg MarkNotificationReadParams) error {
+	ctx, span := t.tracer.Start(ctx, "NotificationService.MarkRead", trace.WithAttributes(
+		attribute.Int("notification.id", int(arg.ID)),
+		attribute.Int("notification.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	err := t.next.MarkRead(ctx, arg)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNotificationService) MarkAllRead(ctx context.Context, userID int32) (int64, error) {
+	ctx, span := t.tracer.Start(ctx, "NotificationService.MarkAllRead", trace.WithAttributes(
+		attribute.Int("notification.user_id", int(userID)),
+	))
+	defer span.End()
+
+	n, err := t.next.MarkAllRead(ctx, userID)
+	span.SetAttributes(attribute.Int64("notification.count", n))
+	endSpan(span, err)
+	return n, err
+}
+
+func (t *tracedNotificationService) GetPreferences(ctx context.Context, userID int32) (NotificationPreferences, error) {
+	ctx, span := t.tracer.Start(ctx, "NotificationService.GetPreferences", trace.WithAttributes(
+		attribute.Int("notification.user_id", int(userID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetPreferences(ctx, userID)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedNotificationService) SetPreferences(ctx context.Context, arg SetNotificationPreferencesParams) (NotificationPreferences, error) {
+	ctx, span := t.tracer.Start(ctx, "NotificationService.SetPreferences", trace.WithAttributes(
+		attribute.Int("notification.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.SetPreferences(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedIdempotencyService struct {
+	next   IdempotencyService
+	tracer trace.Tracer
+}
+
+func NewTracedIdempotencyService(next IdempotencyService, tp trace.TracerProvider) IdempotencyService {
+	return &tracedIdempotencyService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedIdempotencyService) Do(ctx context.Context, arg IdempotencyParams, fn func(ctx context.Context) IdempotentResponse) (IdempotentResponse, bool, error) {
+	ctx, span := t.tracer.Start(ctx, "IdempotencyService.Do", trace.WithAttributes(
+		attribute.String("idempotency.scope", arg.Scope),
+	))
+	defer span.End()
+
+	res, replayed, err := t.next.Do(ctx, arg, fn)
+	span.SetAttributes(attribute.Bool("idempotency.replayed", replayed))
+	endSpan(span, err)
+	return res, replayed, err
+}
+
+func (t *tracedIdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, span := t.tracer.Start(ctx, "IdempotencyService.PurgeExpired")
+	defer span.End()
+
+	res, err := t.next.PurgeExpired(ctx)
+	span.SetAttributes(attribute.Int64("idempotency.purged", res))
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedStreamService struct {
+	next   StreamService
+	tracer trace.Tracer
+}
+
+func NewTracedStreamService(next StreamService, tp trace.TracerProvider) StreamService {
+	return &tracedStreamService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedStreamService) Subscribe(ctx context.Context, arg SubscribeParams) (*Subscription, error) {
+	ctx, span := t.tracer.Start(ctx, "StreamService.Subscribe", trace.WithAttributes(
+		attribute.Int("stream.user_id", int(arg.UserID)),
+		attribute.Int64("stream.last_event_id", arg.LastEventID),
+	))
+	defer span.End()
+
+	res, err := t.next.Subscribe(ctx, arg)
+	if res != nil {
+		span.SetAttributes(
+			attribute.Int("stream.replayed", len(res.Backlog)),
+			attribute.Bool("stream.missed", res.Missed),
+		)
+	}
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedStreamService) Unsubscribe(sub *Subscription) {
+	t.next.Unsubscribe(sub)
+}
+
+func (t *tracedStreamService) Dispatch(ctx context.Context, ids []int64) error {
+	ctx, span := t.tracer.Start(ctx, "StreamService.Dispatch", trace.WithAttributes(
+		attribute.Int("stream.event_count", len(ids)),
+	))
+	defer span.End()
+
+	err := t.next.Dispatch(ctx, ids)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedStreamService) DropAll() {
+	t.next.DropAll()
+}
+
+func (t *tracedStreamService) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, span := t.tracer.Start(ctx, "StreamService.PurgeExpired")
+	defer span.End()
+
+	res, err := t.next.PurgeExpired(ctx)
+	span.SetAttributes(attribute.Int64("stream.purged", res))
+	endSpan(span, err)
+	return res, err
+}
+
+type tracedMessageService struct {
+	next   MessageService
+	tracer trace.Tracer
+}
+
+func NewTracedMessageService(next MessageService, tp trace.TracerProvider) MessageService {
+	return &tracedMessageService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracedMessageService) StartConversation(ctx context.Context, arg StartConversationParams) (ConversationRow, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.StartConversation", trace.WithAttributes(
+		attribute.Int("message.user_id", int(arg.UserID)),
+		attribute.Int("message.participant_count", len(arg.ParticipantIDs)),
+	))
+	defer span.End()
+
+	res, err := t.next.StartConversation(ctx, arg)
+	span.SetAttributes(attribute.Int("message.conversation_id", int(res.ID)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMessageService) GetConversations(ctx context.Context, arg GetConversationsParams) ([]ConversationRow, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.GetConversations", trace.WithAttributes(
+		attribute.Int("message.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetConversations(ctx, arg)
+	span.SetAttributes(attribute.Int("message.conversation_count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMessageService) GetConversation(ctx context.Context, arg GetConversationParams) (ConversationRow, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.GetConversation", trace.WithAttributes(
+		attribute.Int("message.conversation_id", int(arg.ID)),
+		attribute.Int("message.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetConversation(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMessageService) SendMessage(ctx context.Context, arg SendMessageParams) (Message, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.SendMessage", trace.WithAttributes(
+		attribute.Int("message.conversation_id", int(arg.ConversationID)),
+		attribute.Int("message.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.SendMessage(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMessageService) GetMessage(ctx context.Context, arg GetMessageParams) (Message, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.GetMessage", trace.WithAttributes(
+		attribute.Int("message.conversation_id", int(arg.ConversationID)),
+		attribute.Int64("message.id", arg.ID),
+		attribute.Int("message.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetMessage(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMessageService) GetMessages(ctx context.Context, arg GetMessagesParams) ([]Message, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.GetMessages", trace.WithAttributes(
+		attribute.Int("message.conversation_id", int(arg.ConversationID)),
+		attribute.Int("message.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.GetMessages(ctx, arg)
+	span.SetAttributes(attribute.Int("message.count", len(res)))
+	endSpan(span, err)
+	return res, err
+}
+
+func (t *tracedMessageService) MarkRead(ctx context.Context, arg MarkConversationReadParams) (ReadReceipt, error) {
+	ctx, span := t.tracer.Start(ctx, "MessageService.MarkRead", trace.WithAttributes(
+		attribute.Int("message.conversation_id", int(arg.ConversationID)),
+		attribute.Int("message.user_id", int(arg.UserID)),
+	))
+	defer span.End()
+
+	res, err := t.next.MarkRead(ctx, arg)
+	endSpan(span, err)
+	return res, err
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gadhittana01/socialmedia/db"
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/pkg/post"
+	"github.com/gadhittana01/socialmedia/pkg/post_tags"
+	"github.com/gadhittana01/socialmedia/pkg/tag"
+	"github.com/golang/mock/gomock"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_TracedPostService_CreatePost(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	dbMock, mock, _ := sqlmock.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("-- name: CreatePost :one")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "userid", "title", "description", "in_reply_to_id", "visibility", "status", "publish_at"}).AddRow(1, 1, "Upa", "Dayo", nil, "public", "published", nil))
+	mock.ExpectQuery(regexp.QuoteMeta("-- name: CreatePostTag :one")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "postid", "tagid"}).AddRow(1, 1, 3))
+	mock.ExpectQuery(regexp.QuoteMeta("-- name: CreatePostTag :one")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "postid", "tagid"}).AddRow(2, 1, 4))
+	mock.ExpectQuery(regexp.QuoteMeta("-- name: CreatePostRevision :one")).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "revision", "title", "description", "tag_ids", "editor_id", "created_at"}).AddRow(1, 1, "Upa", "Dayo", "{3,4}", 1, time.Now()))
+	mock.ExpectCommit()
+
+	traced := db.NewTracedDB(dbMock, tp)
+	ps, _ := NewPostService(post.New(traced), tag.New(traced), post_tags.New(traced), bookmark.New(traced), media.New(traced), NewTxRunner(dbMock, tp))
+
+	_, err := NewTracedPostService(ps, tp).CreatePost(context.Background(), CreatePostParams{
+		Userid:      1,
+		Title:       "Upa",
+		Description: "Dayo",
+		TagID:       []int32{3, 4},
+	})
+	if err != nil {
+		t.Fatalf("CreatePost() error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	want := []string{"sql CreatePost", "sql CreatePostTag", "sql CreatePostTag", "sql CreatePostRevision", "PostService.CreatePost"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("spans = %v, want %v", names, want)
+	}
+
+	root := spans[len(spans)-1].SpanContext.SpanID()
+	for _, s := range spans[:len(spans)-1] {
+		if s.Parent.SpanID() != root {
+			t.Errorf("span %s parent = %v, want %v", s.Name, s.Parent.SpanID(), root)
+		}
+	}
+}
+
+func Test_TracedTagService_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	tagMock := NewMockTagResource(ctrl)
+	tagMock.EXPECT().GetTags(gomock.Any()).Return(nil, errors.New("error"))
+	ts, _ := NewTagService(tagMock)
+
+	_, err := NewTracedTagService(ts, tp).GetTags(context.Background())
+	if err == nil {
+		t.Fatalf("GetTags() error = nil, want error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name != "TagService.GetTags" || spans[0].Status.Code != codes.Error {
+		t.Errorf("span = %s %v, want TagService.GetTags with error status", spans[0].Name, spans[0].Status.Code)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/tag"
+)
+
+// TrendingWindows are the window sizes trending scores are kept for.
+var TrendingWindows = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// trendingBaseline is how far before a window usage is counted to learn a
+// tag's normal rate.
+const trendingBaseline = 7 * 24 * time.Hour
+
+type TrendingService interface {
+	GetTrendingTags(ctx context.Context, arg GetTrendingTagsParams) ([]GetTrendingTagsRow, error)
+	// RefreshTrendingTags recomputes the scores of every window in
+	// TrendingWindows as of the service clock.
+	RefreshTrendingTags(ctx context.Context) error
+}
+
+type trendingService struct {
+	tr  TagResource
+	now func() time.Time
+}
+
+// NewTrendingService returns a TrendingService reading the time from now,
+// or from time.Now when now is nil.
+func NewTrendingService(TR TagResource, now func() time.Time) (TrendingService, error) {
+	if now == nil {
+		now = time.Now
+	}
+	return &trendingService{
+		tr:  TR,
+		now: now,
+	}, nil
+}
+
+func (ts *trendingService) GetTrendingTags(ctx context.Context, arg GetTrendingTagsParams) ([]GetTrendingTagsRow, error) {
+	var result []GetTrendingTagsRow = []GetTrendingTagsRow{}
+	res, err := ts.tr.GetTrendingTags(ctx, tag.GetTrendingTagsParams{
+		WindowMinutes: int32(arg.Window / time.Minute),
+		Limit:         arg.Limit,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetTrendingTags", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, GetTrendingTagsRow{
+			ID:            item.ID,
+			Tagname:       item.Tagname,
+			RecentCount:   item.RecentCount,
+			BaselineCount: item.BaselineCount,
+			Score:         item.Score,
+			ComputedAt:    item.ComputedAt,
+		})
+	}
+	return result, nil
+}
+
+func (ts *trendingService) RefreshTrendingTags(ctx context.Context) error {
+	now := ts.now().UTC()
+	for _, window := range TrendingWindows {
+		if err := ts.refreshWindow(ctx, now, window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *trendingService) refreshWindow(ctx context.Context, now time.Time, window time.Duration) error {
+	windowStart := now.Add(-window)
+	counts, err := ts.tr.GetTagUsageCounts(ctx, tag.GetTagUsageCountsParams{
+		WindowStart:   windowStart,
+		BaselineStart: windowStart.Add(-trendingBaseline),
+		WindowEnd:     now,
+	})
+	if err != nil {
+		logSQLError(ctx, "GetTagUsageCounts", err)
+		return err
+	}
+
+	arg := tag.UpsertTagTrendsParams{
+		WindowMinutes: int32(window / time.Minute),
+		ComputedAt:    now,
+	}
+	for _, c := range counts {
+		arg.TagIds = append(arg.TagIds, c.Tagid)
+		arg.RecentCounts = append(arg.RecentCounts, int32(c.RecentCount))
+		arg.BaselineCounts = append(arg.BaselineCounts, int32(c.BaselineCount))
+		arg.Scores = append(arg.Scores, trendScore(c.RecentCount, c.BaselineCount, window))
+	}
+	if len(arg.TagIds) > 0 {
+		err = ts.tr.UpsertTagTrends(ctx, arg)
+		if err != nil {
+			logSQLError(ctx, "UpsertTagTrends", err)
+			return err
+		}
+	}
+
+	// Rows this run did not touch belong to tags no longer used in the
+	// baseline or the window.
+	err = ts.tr.DeleteStaleTagTrends(ctx, tag.DeleteStaleTagTrendsParams{
+		WindowMinutes: arg.WindowMinutes,
+		ComputedAt:    now,
+	})
+	if err != nil {
+		logSQLError(ctx, "DeleteStaleTagTrends", err)
+		return err
+	}
+	return nil
+}
+
+// trendScore is the ratio of a tag's use in the window to the use its
+// baseline rate predicts for a window that long. One is added to the
+// prediction so that new tags score by their raw count instead of dividing
+// by zero.
+func trendScore(recent, baseline int64, window time.Duration) float64 {
+	expected := float64(baseline) * float64(window) / float64(trendingBaseline)
+	return float64(recent) / (expected + 1)
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/pkg/tag"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_trendScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		recent   int64
+		baseline int64
+		window   time.Duration
+		want     float64
+	}{
+		{
+			name:   "new tag scores its raw count",
+			recent: 3,
+			window: time.Hour,
+			want:   3,
+		},
+		{
+			name:     "usage at the baseline rate",
+			recent:   2,
+			baseline: 336,
+			window:   time.Hour,
+			want:     2.0 / 3,
+		},
+		{
+			name:     "usage ten times the baseline rate",
+			recent:   40,
+			baseline: 168 * 4 / 24,
+			window:   24 * time.Hour,
+			want:     40.0 / 5,
+		},
+		{
+			name:     "unused in the window",
+			baseline: 100,
+			window:   time.Hour,
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trendScore(tt.recent, tt.baseline, tt.window); got != tt.want {
+				t.Errorf("trendScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RefreshTrendingTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	clock := func() time.Time {
+		return time.Date(2024, 1, 8, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	}
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	usage := func(window time.Duration) tag.GetTagUsageCountsParams {
+		return tag.GetTagUsageCountsParams{
+			WindowStart:   now.Add(-window),
+			BaselineStart: now.Add(-window - week),
+			WindowEnd:     now,
+		}
+	}
+	stale := func(minutes int32) tag.DeleteStaleTagTrendsParams {
+		return tag.DeleteStaleTagTrendsParams{WindowMinutes: minutes, ComputedAt: now}
+	}
+
+	tests := []struct {
+		name    string
+		mock    func() *trendingService
+		wantErr bool
+	}{
+		{
+			name: "success refresh every window",
+			mock: func() *trendingService {
+				tagMock := NewMockTagResource(ctrl)
+				gomock.InOrder(
+					tagMock.EXPECT().GetTagUsageCounts(gomock.Any(), usage(time.Hour)).Return([]tag.GetTagUsageCountsRow{
+						{Tagid: 1, RecentCount: 4, BaselineCount: 168},
+						{Tagid: 2, RecentCount: 1},
+					}, nil),
+					tagMock.EXPECT().UpsertTagTrends(gomock.Any(), tag.UpsertTagTrendsParams{
+						TagIds:         []int32{1, 2},
+						WindowMinutes:  60,
+						RecentCounts:   []int32{4, 1},
+						BaselineCounts: []int32{168, 0},
+						Scores:         []float64{2, 1},
+						ComputedAt:     now,
+					}).Return(nil),
+					tagMock.EXPECT().DeleteStaleTagTrends(gomock.Any(), stale(60)).Return(nil),
+
+					tagMock.EXPECT().GetTagUsageCounts(gomock.Any(), usage(6*time.Hour)).Return(nil, nil),
+					tagMock.EXPECT().DeleteStaleTagTrends(gomock.Any(), stale(360)).Return(nil),
+
+					tagMock.EXPECT().GetTagUsageCounts(gomock.Any(), usage(24*time.Hour)).Return([]tag.GetTagUsageCountsRow{
+						{Tagid: 1, RecentCount: 14, BaselineCount: 42},
+					}, nil),
+					tagMock.EXPECT().UpsertTagTrends(gomock.Any(), tag.UpsertTagTrendsParams{
+						TagIds:         []int32{1},
+						WindowMinutes:  1440,
+						RecentCounts:   []int32{14},
+						BaselineCounts: []int32{42},
+						Scores:         []float64{2},
+						ComputedAt:     now,
+					}).Return(nil),
+					tagMock.EXPECT().DeleteStaleTagTrends(gomock.Any(), stale(1440)).Return(nil),
+				)
+
+				return &trendingService{
+					tr:  tagMock,
+					now: clock,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get tag usage counts",
+			mock: func() *trendingService {
+				tagMock := NewMockTagResource(ctrl)
+				tagMock.EXPECT().GetTagUsageCounts(gomock.Any(), usage(time.Hour)).Return(nil, errors.New("error"))
+
+				return &trendingService{
+					tr:  tagMock,
+					now: clock,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error upsert tag trends",
+			mock: func() *trendingService {
+				tagMock := NewMockTagResource(ctrl)
+				tagMock.EXPECT().GetTagUsageCounts(gomock.Any(), usage(time.Hour)).Return([]tag.GetTagUsageCountsRow{
+					{Tagid: 1, RecentCount: 1},
+				}, nil)
+				tagMock.EXPECT().UpsertTagTrends(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &trendingService{
+					tr:  tagMock,
+					now: clock,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error delete stale tag trends",
+			mock: func() *trendingService {
+				tagMock := NewMockTagResource(ctrl)
+				tagMock.EXPECT().GetTagUsageCounts(gomock.Any(), usage(time.Hour)).Return(nil, nil)
+				tagMock.EXPECT().DeleteStaleTagTrends(gomock.Any(), stale(60)).Return(errors.New("error"))
+
+				return &trendingService{
+					tr:  tagMock,
+					now: clock,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := tt.mock()
+			err := ts.RefreshTrendingTags(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefreshTrendingTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetTrendingTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		arg     GetTrendingTagsParams
+		mock    func() *trendingService
+		want    []GetTrendingTagsRow
+		wantErr bool
+	}{
+		{
+			name: "success get trending tags",
+			arg:  GetTrendingTagsParams{Window: 6 * time.Hour, Limit: 10},
+			mock: func() *trendingService {
+				tagMock := NewMockTagResource(ctrl)
+				tagMock.EXPECT().GetTrendingTags(gomock.Any(), tag.GetTrendingTagsParams{
+					WindowMinutes: 360,
+					Limit:         10,
+				}).Return([]tag.GetTrendingTagsRow{
+					{ID: 1, Tagname: "holiday", RecentCount: 4, BaselineCount: 21, Score: 2.5, ComputedAt: now},
+				}, nil)
+
+				return &trendingService{
+					tr: tagMock,
+				}
+			},
+			want: []GetTrendingTagsRow{
+				{ID: 1, Tagname: "holiday", RecentCount: 4, BaselineCount: 21, Score: 2.5, ComputedAt: now},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get trending tags",
+			arg:  GetTrendingTagsParams{Window: time.Hour, Limit: 10},
+			mock: func() *trendingService {
+				tagMock := NewMockTagResource(ctrl)
+				tagMock.EXPECT().GetTrendingTags(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &trendingService{
+					tr: tagMock,
+				}
+			},
+			want:    []GetTrendingTagsRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := tt.mock()
+			got, err := ts.GetTrendingTags(ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTrendingTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTrendingTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gadhittana01/socialmedia/db"
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/pkg/message"
+	"github.com/gadhittana01/socialmedia/pkg/notification"
+	"github.com/gadhittana01/socialmedia/pkg/post"
+	"github.com/gadhittana01/socialmedia/pkg/post_tags"
+	"github.com/gadhittana01/socialmedia/pkg/user"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TxResources are the resources given to a TxRunner body. Every statement
+// they run belongs to the transaction.
+type TxResources struct {
+	Media        MediaResource
+	Message      MessageResource
+	Notification NotificationResource
+	Post         PostResource
+	PostTag      PostTagResource
+	User         UserResource
+}
+
+type txRunner struct {
+	db *sql.DB
+	tp trace.TracerProvider
+}
+
+// NewTxRunner returns a TxRunner beginning its transactions on conn, whose
+// statements are traced with tp like any other.
+func NewTxRunner(conn *sql.DB, tp trace.TracerProvider) TxRunner {
+	return &txRunner{
+		db: conn,
+		tp: tp,
+	}
+}
+
+func (r *txRunner) InTx(ctx context.Context, fn func(tx TxResources) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logSQLError(ctx, "BeginTx", err)
+		return err
+	}
+	// Rolling back after a commit is a no-op, so this only undoes
+	// transactions that fn failed or panicked in.
+	defer tx.Rollback()
+
+	traced := db.NewTracedDB(tx, r.tp)
+	err = fn(TxResources{
+		Media:        media.New(traced),
+		Message:      message.New(traced),
+		Notification: notification.New(traced),
+		Post:         post.New(traced),
+		PostTag:      post_tags.New(traced),
+		User:         user.New(traced),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logSQLError(ctx, "Commit", err)
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/gadhittana01/socialmedia/pkg/user"
 )
@@ -9,8 +11,19 @@ import (
 type UserService interface {
 	CreateUser(ctx context.Context, fullname string) (CreateUserRow, error)
 	GetUsers(ctx context.Context) ([]GetUsersRow, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error)
-	DeleteUser(ctx context.Context, id int32) error
+	DeleteUser(ctx context.Context, arg DeleteUserParams) error
+	PatchUser(ctx context.Context, arg PatchUserParams) (PatchUserRow, error)
+	GetUser(ctx context.Context, id int32) (GetUserRow, error)
+	FollowUser(ctx context.Context, arg FollowUserParams) error
+	UnfollowUser(ctx context.Context, arg UnfollowUserParams) error
+	BlockUser(ctx context.Context, arg BlockUserParams) error
+	UnblockUser(ctx context.Context, arg UnblockUserParams) error
+	GetBlockedUsers(ctx context.Context, userID int32) ([]GetUsersRow, error)
+	MuteUser(ctx context.Context, arg MuteUserParams) error
+	UnmuteUser(ctx context.Context, arg UnmuteUserParams) error
+	GetMutedUsers(ctx context.Context, userID int32) ([]GetUsersRow, error)
 }
 
 type userService struct {
@@ -25,8 +38,9 @@ func NewUserService(UR UserResource) (UserService, error) {
 
 func (us *userService) CreateUser(ctx context.Context, fullname string) (CreateUserRow, error) {
 	var result CreateUserRow = CreateUserRow{}
-	res, err := us.ur.CreateUser(context.Background(), fullname)
+	res, err := us.ur.CreateUser(ctx, fullname)
 	if err != nil {
+		logSQLError(ctx, "CreateUser", err)
 		return result, err
 	}
 
@@ -40,8 +54,9 @@ func (us *userService) CreateUser(ctx context.Context, fullname string) (CreateU
 
 func (us *userService) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
 	var result []GetUsersRow = []GetUsersRow{}
-	res, err := us.ur.GetUsers(context.Background())
+	res, err := us.ur.GetUsers(ctx)
 	if err != nil {
+		logSQLError(ctx, "GetUsers", err)
 		return result, err
 	}
 	for _, item := range res {
@@ -53,36 +68,250 @@ func (us *userService) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
 	return result, nil
 }
 
+func (us *userService) GetUser(ctx context.Context, id int32) (GetUserRow, error) {
+	var result GetUserRow = GetUserRow{}
+	res, err := us.ur.GetUser(ctx, id)
+	if err != nil {
+		logSQLError(ctx, "GetUser", err)
+		return result, err
+	}
+	result = GetUserRow{
+		ID:       res.ID,
+		Fullname: res.Fullname,
+		Version:  res.Version,
+	}
+	return result, nil
+}
+
 func (us *userService) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
 	var result UpdateUserRow = UpdateUserRow{}
-	_, err := us.ur.GetUser(ctx, arg.ID)
+	current, err := us.ur.GetUser(ctx, arg.ID)
 	if err != nil {
+		logSQLError(ctx, "GetUser", err)
 		return result, err
 	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return result, ErrVersionConflict
+	}
 
-	res, err := us.ur.UpdateUser(context.Background(), user.UpdateUserParams{
+	res, err := us.ur.UpdateUser(ctx, user.UpdateUserParams{
 		ID:       arg.ID,
 		Fullname: arg.Fullname,
+		Version:  nullVersion(arg.Version),
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrVersionConflict
+	}
 	if err != nil {
+		logSQLError(ctx, "UpdateUser", err)
 		return result, err
 	}
 	result = UpdateUserRow{
 		ID:       res.ID,
 		Fullname: res.Fullname,
+		Version:  res.Version,
+	}
+	return result, nil
+}
+
+func (us *userService) DeleteUser(ctx context.Context, arg DeleteUserParams) error {
+	current, err := us.ur.GetUser(ctx, arg.ID)
+	if err != nil {
+		logSQLError(ctx, "GetUser", err)
+		return err
+	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return ErrVersionConflict
+	}
+
+	deleted, err := us.ur.DeleteUser(ctx, user.DeleteUserParams{
+		ID:      arg.ID,
+		Version: nullVersion(arg.Version),
+	})
+	if err != nil {
+		logSQLError(ctx, "DeleteUser", err)
+		return err
+	}
+	if deleted == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (us *userService) PatchUser(ctx context.Context, arg PatchUserParams) (PatchUserRow, error) {
+	var result PatchUserRow = PatchUserRow{}
+	current, err := us.ur.GetUser(ctx, arg.ID)
+	if err != nil {
+		logSQLError(ctx, "GetUser", err)
+		return result, err
+	}
+	if arg.Version != 0 && arg.Version != current.Version {
+		return result, ErrVersionConflict
+	}
+
+	res, err := us.ur.PatchUser(ctx, user.PatchUserParams{
+		ID:       arg.ID,
+		Fullname: nullString(arg.Fullname),
+		Version:  nullVersion(arg.Version),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrVersionConflict
+	}
+	if err != nil {
+		logSQLError(ctx, "PatchUser", err)
+		return result, err
+	}
+	result = PatchUserRow{
+		ID:       res.ID,
+		Fullname: res.Fullname,
+		Version:  res.Version,
+	}
+	return result, nil
+}
+
+func (us *userService) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	var result []SearchUsersRow = []SearchUsersRow{}
+	res, err := us.ur.SearchUsers(ctx, user.SearchUsersParams{
+		Prefix: likePrefix(arg.Query),
+		Query:  arg.Query,
+		Limit:  arg.Limit,
+	})
+	if err != nil {
+		logSQLError(ctx, "SearchUsers", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, SearchUsersRow{
+			ID:       item.ID,
+			Fullname: item.Fullname,
+		})
+	}
+	return result, nil
+}
+
+// FollowUser succeeds when the follower already follows the followee. It
+// returns sql.ErrNoRows when either user does not exist, and ErrBlocked when
+// either one blocks the other.
+func (us *userService) FollowUser(ctx context.Context, arg FollowUserParams) error {
+	if arg.FollowerID == arg.FolloweeID {
+		return ErrSelfFollow
+	}
+	followed, err := us.ur.FollowUser(ctx, user.FollowUserParams{
+		FollowerID: arg.FollowerID,
+		FolloweeID: arg.FolloweeID,
+	})
+	if isPQError(err, pqBlocked) {
+		return ErrBlocked
+	}
+	if err != nil {
+		logSQLError(ctx, "FollowUser", err)
+		return err
+	}
+	if followed == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (us *userService) UnfollowUser(ctx context.Context, arg UnfollowUserParams) error {
+	err := us.ur.UnfollowUser(ctx, user.UnfollowUserParams{
+		FollowerID: arg.FollowerID,
+		FolloweeID: arg.FolloweeID,
+	})
+	if err != nil {
+		logSQLError(ctx, "UnfollowUser", err)
+		return err
+	}
+	return nil
+}
+
+// BlockUser also removes any follow between the two users, in both
+// directions. Blocking someone already blocked succeeds. It returns
+// sql.ErrNoRows when either user does not exist.
+func (us *userService) BlockUser(ctx context.Context, arg BlockUserParams) error {
+	if arg.BlockerID == arg.BlockedID {
+		return ErrSelfBlock
+	}
+	blocked, err := us.ur.BlockUser(ctx, user.BlockUserParams{
+		BlockerID: arg.BlockerID,
+		BlockedID: arg.BlockedID,
+	})
+	if err != nil {
+		logSQLError(ctx, "BlockUser", err)
+		return err
+	}
+	if blocked == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (us *userService) UnblockUser(ctx context.Context, arg UnblockUserParams) error {
+	err := us.ur.UnblockUser(ctx, user.UnblockUserParams{
+		BlockerID: arg.BlockerID,
+		BlockedID: arg.BlockedID,
+	})
+	if err != nil {
+		logSQLError(ctx, "UnblockUser", err)
+		return err
+	}
+	return nil
+}
+
+func (us *userService) GetBlockedUsers(ctx context.Context, userID int32) ([]GetUsersRow, error) {
+	var result []GetUsersRow = []GetUsersRow{}
+	res, err := us.ur.GetBlockedUsers(ctx, userID)
+	if err != nil {
+		logSQLError(ctx, "GetBlockedUsers", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, GetUsersRow(item))
 	}
 	return result, nil
 }
 
-func (us *userService) DeleteUser(ctx context.Context, id int32) error {
-	_, err := us.ur.GetUser(ctx, id)
+// MuteUser succeeds when the user is already muted. It returns
+// sql.ErrNoRows when either user does not exist.
+func (us *userService) MuteUser(ctx context.Context, arg MuteUserParams) error {
+	if arg.MuterID == arg.MutedID {
+		return ErrSelfMute
+	}
+	muted, err := us.ur.MuteUser(ctx, user.MuteUserParams{
+		MuterID: arg.MuterID,
+		MutedID: arg.MutedID,
+	})
 	if err != nil {
+		logSQLError(ctx, "MuteUser", err)
 		return err
 	}
+	if muted == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	err = us.ur.DeleteUser(ctx, id)
+func (us *userService) UnmuteUser(ctx context.Context, arg UnmuteUserParams) error {
+	err := us.ur.UnmuteUser(ctx, user.UnmuteUserParams{
+		MuterID: arg.MuterID,
+		MutedID: arg.MutedID,
+	})
 	if err != nil {
+		logSQLError(ctx, "UnmuteUser", err)
 		return err
 	}
 	return nil
 }
+
+func (us *userService) GetMutedUsers(ctx context.Context, userID int32) ([]GetUsersRow, error) {
+	var result []GetUsersRow = []GetUsersRow{}
+	res, err := us.ur.GetMutedUsers(ctx, userID)
+	if err != nil {
+		logSQLError(ctx, "GetMutedUsers", err)
+		return result, err
+	}
+	for _, item := range res {
+		result = append(result, GetUsersRow(item))
+	}
+	return result, nil
+}
@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/gadhittana01/socialmedia/pkg/user"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 )
 
 func TestNewUserService(t *testing.T) {
@@ -204,6 +206,87 @@ func Test_GetUsers(t *testing.T) {
 	}
 }
 
+func Test_SearchUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		arg SearchUsersParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *userService
+		want    []SearchUsersRow
+		wantErr bool
+	}{
+		{
+			name: "success search users",
+			args: args{
+				ctx: ctx,
+				arg: SearchUsersParams{Query: "Giri_", Limit: 10},
+			},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+
+				m.EXPECT().SearchUsers(gomock.Any(), user.SearchUsersParams{
+					Prefix: `Giri\_%`,
+					Query:  "Giri_",
+					Limit:  10,
+				}).Return([]user.SearchUsersRow{
+					{
+						ID:       1,
+						Fullname: "Giri_Putra",
+					},
+				}, nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			want: []SearchUsersRow{
+				{
+					ID:       1,
+					Fullname: "Giri_Putra",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error search users",
+			args: args{
+				ctx: ctx,
+				arg: SearchUsersParams{Query: "Giri_", Limit: 10},
+			},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+
+				m.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			want:    []SearchUsersRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.SearchUsers(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_UpdateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ctx := context.Background()
@@ -304,6 +387,64 @@ func Test_UpdateUser(t *testing.T) {
 			want:    UpdateUserRow{},
 			wantErr: true,
 		},
+		{
+			name: "error version conflict",
+			args: args{
+				ctx: ctx,
+				arg: UpdateUserParams{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  1,
+				},
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  2,
+				}, nil)
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want:    UpdateUserRow{},
+			wantErr: true,
+		},
+		{
+			name: "error version changed before update",
+			args: args{
+				ctx: ctx,
+				arg: UpdateUserParams{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  2,
+				},
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  2,
+				}, nil)
+
+				userMock.EXPECT().UpdateUser(gomock.Any(), user.UpdateUserParams{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  sql.NullInt32{Int32: 2, Valid: true},
+				}).Return(user.UpdateUserRow{}, sql.ErrNoRows)
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want:    UpdateUserRow{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -326,7 +467,7 @@ func Test_DeleteUser(t *testing.T) {
 
 	type args struct {
 		ctx context.Context
-		id  int32
+		arg DeleteUserParams
 	}
 	tests := []struct {
 		name    string
@@ -338,7 +479,9 @@ func Test_DeleteUser(t *testing.T) {
 			name: "success delete user",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: DeleteUserParams{
+					ID: 1,
+				},
 			},
 			mock: func() *userService {
 				userMock := NewMockUserResource(ctrl)
@@ -348,7 +491,9 @@ func Test_DeleteUser(t *testing.T) {
 					Fullname: "Giri Putra Adhittana",
 				}, nil)
 
-				userMock.EXPECT().DeleteUser(gomock.Any(), int32(1)).Return(nil)
+				userMock.EXPECT().DeleteUser(gomock.Any(), user.DeleteUserParams{
+					ID: 1,
+				}).Return(int64(1), nil)
 
 				return &userService{
 					ur: userMock,
@@ -360,7 +505,9 @@ func Test_DeleteUser(t *testing.T) {
 			name: "error get user",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: DeleteUserParams{
+					ID: 1,
+				},
 			},
 			mock: func() *userService {
 				userMock := NewMockUserResource(ctrl)
@@ -377,7 +524,9 @@ func Test_DeleteUser(t *testing.T) {
 			name: "error delete user",
 			args: args{
 				ctx: ctx,
-				id:  1,
+				arg: DeleteUserParams{
+					ID: 1,
+				},
 			},
 			mock: func() *userService {
 				userMock := NewMockUserResource(ctrl)
@@ -387,7 +536,9 @@ func Test_DeleteUser(t *testing.T) {
 					Fullname: "Giri Putra Adhittana",
 				}, nil)
 
-				userMock.EXPECT().DeleteUser(gomock.Any(), int32(1)).Return(errors.New("error"))
+				userMock.EXPECT().DeleteUser(gomock.Any(), user.DeleteUserParams{
+					ID: 1,
+				}).Return(int64(0), errors.New("error"))
 
 				return &userService{
 					ur: userMock,
@@ -399,7 +550,7 @@ func Test_DeleteUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := tt.mock()
-			err := p.DeleteUser(tt.args.ctx, tt.args.id)
+			err := p.DeleteUser(tt.args.ctx, tt.args.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteUser() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -407,3 +558,693 @@ func Test_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatchUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userFullname := "Giri Putra Adhittana"
+
+	type args struct {
+		ctx context.Context
+		arg PatchUserParams
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *userService
+		want    PatchUserRow
+		wantErr bool
+	}{
+		{
+			name: "success patch user",
+			args: args{
+				ctx: ctx,
+				arg: PatchUserParams{
+					ID:       1,
+					Fullname: &userFullname,
+				},
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{
+					ID:       1,
+					Fullname: "old",
+				}, nil)
+
+				userMock.EXPECT().PatchUser(gomock.Any(), user.PatchUserParams{
+					ID:       1,
+					Fullname: sql.NullString{String: "Giri Putra Adhittana", Valid: true},
+				}).Return(user.PatchUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+				}, nil)
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want: PatchUserRow{
+				ID:       1,
+				Fullname: "Giri Putra Adhittana",
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get user",
+			args: args{
+				ctx: ctx,
+				arg: PatchUserParams{
+					ID:       1,
+					Fullname: &userFullname,
+				},
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{}, sql.ErrNoRows)
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want:    PatchUserRow{},
+			wantErr: true,
+		},
+		{
+			name: "error patch user",
+			args: args{
+				ctx: ctx,
+				arg: PatchUserParams{
+					ID: 1,
+				},
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{
+					ID:       1,
+					Fullname: "old",
+				}, nil)
+
+				userMock.EXPECT().PatchUser(gomock.Any(), user.PatchUserParams{
+					ID: 1,
+				}).Return(user.PatchUserRow{}, errors.New("error"))
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want:    PatchUserRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.PatchUser(tt.args.ctx, tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	type args struct {
+		ctx context.Context
+		id  int32
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func() *userService
+		want    GetUserRow
+		wantErr bool
+	}{
+		{
+			name: "success get user",
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{
+					ID:       1,
+					Fullname: "Giri Putra Adhittana",
+					Version:  3,
+				}, nil)
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want: GetUserRow{
+				ID:       1,
+				Fullname: "Giri Putra Adhittana",
+				Version:  3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error get user",
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			mock: func() *userService {
+				userMock := NewMockUserResource(ctrl)
+
+				userMock.EXPECT().GetUser(gomock.Any(), int32(1)).Return(user.GetUserRow{}, sql.ErrNoRows)
+
+				return &userService{
+					ur: userMock,
+				}
+			},
+			want:    GetUserRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.mock()
+			got, err := p.GetUser(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FollowUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     FollowUserParams
+		mock    func() *userService
+		wantErr error
+	}{
+		{
+			name: "success follow user",
+			arg:  FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), user.FollowUserParams{FollowerID: 1, FolloweeID: 2}).Return(int64(1), nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: nil,
+		},
+		{
+			name: "follow yourself",
+			arg:  FollowUserParams{FollowerID: 1, FolloweeID: 1},
+			mock: func() *userService {
+				return &userService{
+					ur: NewMockUserResource(ctrl),
+				}
+			},
+			wantErr: ErrSelfFollow,
+		},
+		{
+			name: "follower or followee not found",
+			arg:  FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "follow a blocked user",
+			arg:  FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(int64(0), &pq.Error{Code: pqBlocked})
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "error follow user",
+			arg:  FollowUserParams{FollowerID: 1, FolloweeID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().FollowUser(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.FollowUser(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("FollowUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_UnfollowUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     UnfollowUserParams
+		mock    func() *userService
+		wantErr bool
+	}{
+		{
+			name: "success unfollow user",
+			arg:  UnfollowUserParams{FollowerID: 1, FolloweeID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().UnfollowUser(gomock.Any(), user.UnfollowUserParams{FollowerID: 1, FolloweeID: 2}).Return(nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unfollow user",
+			arg:  UnfollowUserParams{FollowerID: 1, FolloweeID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().UnfollowUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.UnfollowUser(ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnfollowUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_BlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     BlockUserParams
+		mock    func() *userService
+		wantErr error
+	}{
+		{
+			name: "success block user",
+			arg:  BlockUserParams{BlockerID: 1, BlockedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), user.BlockUserParams{BlockerID: 1, BlockedID: 2}).Return(int64(1), nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: nil,
+		},
+		{
+			name: "block yourself",
+			arg:  BlockUserParams{BlockerID: 1, BlockedID: 1},
+			mock: func() *userService {
+				return &userService{
+					ur: NewMockUserResource(ctrl),
+				}
+			},
+			wantErr: ErrSelfBlock,
+		},
+		{
+			name: "blocker or blocked user not found",
+			arg:  BlockUserParams{BlockerID: 1, BlockedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error block user",
+			arg:  BlockUserParams{BlockerID: 1, BlockedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().BlockUser(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.BlockUser(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("BlockUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_UnblockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     UnblockUserParams
+		mock    func() *userService
+		wantErr bool
+	}{
+		{
+			name: "success unblock user",
+			arg:  UnblockUserParams{BlockerID: 1, BlockedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().UnblockUser(gomock.Any(), user.UnblockUserParams{BlockerID: 1, BlockedID: 2}).Return(nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unblock user",
+			arg:  UnblockUserParams{BlockerID: 1, BlockedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().UnblockUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.UnblockUser(ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnblockUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetBlockedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		userID  int32
+		mock    func() *userService
+		want    []GetUsersRow
+		wantErr bool
+	}{
+		{
+			name:   "success get blocked users",
+			userID: 1,
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetBlockedUsers(gomock.Any(), int32(1)).Return([]user.GetBlockedUsersRow{
+					{
+						ID:       2,
+						Fullname: "Giri Adhittana",
+					},
+				}, nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			want: []GetUsersRow{
+				{
+					ID:       2,
+					Fullname: "Giri Adhittana",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "error get blocked users",
+			userID: 1,
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetBlockedUsers(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			want:    []GetUsersRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.GetBlockedUsers(ctx, tt.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBlockedUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBlockedUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MuteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     MuteUserParams
+		mock    func() *userService
+		wantErr error
+	}{
+		{
+			name: "success mute user",
+			arg:  MuteUserParams{MuterID: 1, MutedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), user.MuteUserParams{MuterID: 1, MutedID: 2}).Return(int64(1), nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: nil,
+		},
+		{
+			name: "mute yourself",
+			arg:  MuteUserParams{MuterID: 1, MutedID: 1},
+			mock: func() *userService {
+				return &userService{
+					ur: NewMockUserResource(ctrl),
+				}
+			},
+			wantErr: ErrSelfMute,
+		},
+		{
+			name: "muter or muted user not found",
+			arg:  MuteUserParams{MuterID: 1, MutedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "error mute user",
+			arg:  MuteUserParams{MuterID: 1, MutedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().MuteUser(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.MuteUser(ctx, tt.arg)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("MuteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_UnmuteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     UnmuteUserParams
+		mock    func() *userService
+		wantErr bool
+	}{
+		{
+			name: "success unmute user",
+			arg:  UnmuteUserParams{MuterID: 1, MutedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().UnmuteUser(gomock.Any(), user.UnmuteUserParams{MuterID: 1, MutedID: 2}).Return(nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "error unmute user",
+			arg:  UnmuteUserParams{MuterID: 1, MutedID: 2},
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().UnmuteUser(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			err := s.UnmuteUser(ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmuteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_GetMutedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		userID  int32
+		mock    func() *userService
+		want    []GetUsersRow
+		wantErr bool
+	}{
+		{
+			name:   "success get muted users",
+			userID: 1,
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetMutedUsers(gomock.Any(), int32(1)).Return([]user.GetMutedUsersRow{
+					{
+						ID:       2,
+						Fullname: "Giri Adhittana",
+					},
+				}, nil)
+
+				return &userService{
+					ur: m,
+				}
+			},
+			want: []GetUsersRow{
+				{
+					ID:       2,
+					Fullname: "Giri Adhittana",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "error get muted users",
+			userID: 1,
+			mock: func() *userService {
+				m := NewMockUserResource(ctrl)
+				m.EXPECT().GetMutedUsers(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+				return &userService{
+					ur: m,
+				}
+			},
+			want:    []GetUsersRow{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.mock()
+			got, err := s.GetMutedUsers(ctx, tt.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMutedUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMutedUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
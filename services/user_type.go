@@ -18,11 +18,14 @@ type User struct {
 type UpdateUserParams struct {
 	ID       int32
 	Fullname string
+	// Version is the expected current version, or 0 to skip the check.
+	Version int32
 }
 
 type UpdateUserRow struct {
 	ID       int32  `json:"id"`
 	Fullname string `json:"fullname"`
+	Version  int32  `json:"version"`
 }
 
 type GetUsersRow struct {
@@ -30,7 +33,65 @@ type GetUsersRow struct {
 	Fullname string `json:"fullname"`
 }
 
-type GetUserRow struct {
+type PatchUserParams struct {
 	ID       int32
-	Fullname string
+	Fullname *string
+	Version  int32
+}
+
+type PatchUserRow struct {
+	ID       int32  `json:"id"`
+	Fullname string `json:"fullname"`
+	Version  int32  `json:"version"`
+}
+
+type GetUserRow struct {
+	ID       int32  `json:"id"`
+	Fullname string `json:"fullname"`
+	Version  int32  `json:"version"`
+}
+
+type DeleteUserParams struct {
+	ID      int32
+	Version int32
+}
+
+type FollowUserParams struct {
+	FollowerID int32
+	FolloweeID int32
+}
+
+type UnfollowUserParams struct {
+	FollowerID int32
+	FolloweeID int32
+}
+
+type BlockUserParams struct {
+	BlockerID int32
+	BlockedID int32
+}
+
+type UnblockUserParams struct {
+	BlockerID int32
+	BlockedID int32
+}
+
+type MuteUserParams struct {
+	MuterID int32
+	MutedID int32
+}
+
+type UnmuteUserParams struct {
+	MuterID int32
+	MutedID int32
+}
+
+type SearchUsersParams struct {
+	Query string
+	Limit int32
+}
+
+type SearchUsersRow struct {
+	ID       int32  `json:"id"`
+	Fullname string `json:"fullname"`
 }
@@ -0,0 +1,1059 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"image/color"
+	"image/png"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gadhittana01/socialmedia/migration"
+	"github.com/gadhittana01/socialmedia/pkg/bookmark"
+	"github.com/gadhittana01/socialmedia/pkg/media"
+	"github.com/gadhittana01/socialmedia/pkg/message"
+	"github.com/gadhittana01/socialmedia/pkg/notification"
+	"github.com/gadhittana01/socialmedia/pkg/post"
+	"github.com/gadhittana01/socialmedia/pkg/post_tags"
+	"github.com/gadhittana01/socialmedia/pkg/stream"
+	"github.com/gadhittana01/socialmedia/pkg/tag"
+	"github.com/gadhittana01/socialmedia/pkg/user"
+	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Test_VisibilityLeaks checks every read path against the database named by
+// SOCIALMEDIA_TEST_DSN: a followers-only post, and replies under it, must
+// reach the author's followers and nobody else. It is skipped when the
+// variable is unset.
+func Test_VisibilityLeaks(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ts, _ := NewTagService(tag.New(db))
+	bs, _ := NewBookmarkService(bookmark.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "vis" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	author, follower, stranger := newUser("author"), newUser("follower"), newUser("stranger")
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: follower, FolloweeID: author}))
+
+	tg, err := ts.CreateTag(ctx, nonce)
+	must(err)
+	must(ts.FollowTag(ctx, FollowTagParams{UserID: follower, TagID: tg.ID}))
+	must(ts.FollowTag(ctx, FollowTagParams{UserID: stranger, TagID: tg.ID}))
+
+	// The quoted post starts public so it can be quoted, then is narrowed.
+	quoted, err := ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce + " quoted", Description: nonce, TagID: []int32{tg.ID}})
+	must(err)
+	quote, err := ps.Repost(ctx, RepostParams{UserID: follower, PostID: quoted.ID, Quote: nonce + " quote"})
+	must(err)
+	followersOnly := PostVisibilityFollowers
+	_, err = ps.PatchPost(ctx, PatchPostParams{ID: quoted.ID, Visibility: &followersOnly})
+	must(err)
+
+	secret, err := ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce + " secret", Description: nonce, TagID: []int32{tg.ID}, Visibility: PostVisibilityFollowers})
+	must(err)
+	reply, err := ps.CreatePost(ctx, CreatePostParams{Userid: follower, Title: nonce + " reply", Description: nonce, InReplyToID: secret.ID})
+	must(err)
+	must(bs.AddBookmark(ctx, AddBookmarkParams{UserID: stranger, PostID: secret.ID}))
+	must(bs.AddBookmark(ctx, AddBookmarkParams{UserID: follower, PostID: secret.ID}))
+
+	hidden := []int32{quoted.ID, secret.ID, reply.ID}
+
+	// A followers-only reply under a public post only counts for viewers
+	// who may read it.
+	open, err := ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce + " open", Description: nonce})
+	must(err)
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce + " aside", Description: nonce, InReplyToID: open.ID, Visibility: PostVisibilityFollowers})
+	must(err)
+	replyCounts := func(viewer int32) []int64 {
+		thread, err := ps.GetThread(ctx, GetThreadParams{ID: open.ID, ViewerID: viewer, Depth: 1, Limit: 10})
+		must(err)
+		counts := []int64{thread.Post.ReplyCount}
+		rows, err := ps.GetPosts(ctx, GetPostsParams{UserID: author, ViewerID: viewer, Limit: 20})
+		must(err)
+		for _, r := range rows {
+			if r.ID == open.ID {
+				counts = append(counts, r.ReplyCount)
+			}
+		}
+		return counts
+	}
+
+	// seen lists every post id a viewer reaches through the listing paths,
+	// including posts embedded in quotes and reposts.
+	seen := func(viewer int32) map[int32]bool {
+		ids := map[int32]bool{}
+		addRows := func(rows []GetPostsRow) {
+			for _, r := range rows {
+				ids[r.ID] = true
+				if r.QuoteOf != nil {
+					ids[r.QuoteOf.ID] = true
+				}
+				if r.RepostOf != nil {
+					ids[r.RepostOf.ID] = true
+				}
+			}
+		}
+		for _, u := range []int32{author, follower} {
+			rows, err := ps.GetPosts(ctx, GetPostsParams{UserID: u, ViewerID: viewer, Limit: 20})
+			must(err)
+			addRows(rows)
+		}
+		found, err := ps.SearchPosts(ctx, SearchPostsParams{Query: nonce, ViewerID: viewer, Limit: 100})
+		must(err)
+		for _, r := range found {
+			ids[r.ID] = true
+		}
+		if viewer != 0 {
+			rows, err := ps.GetTagFeed(ctx, GetTagFeedParams{UserID: viewer, Limit: 100})
+			must(err)
+			addRows(rows)
+			rows, err = ps.GetBookmarks(ctx, GetBookmarksParams{UserID: viewer, Limit: 100})
+			must(err)
+			addRows(rows)
+		}
+		for _, id := range hidden {
+			if _, err := ps.GetPost(ctx, GetPostParams{ID: id, ViewerID: viewer}); err == nil {
+				ids[id] = true
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				t.Fatal(err)
+			}
+			thread, err := ps.GetThread(ctx, GetThreadParams{ID: id, ViewerID: viewer, Depth: 2, Limit: 10})
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			must(err)
+			ids[thread.Post.ID] = true
+			for _, a := range thread.Ancestors {
+				ids[a.ID] = true
+			}
+			for _, r := range thread.Post.Replies {
+				ids[r.ID] = true
+			}
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		viewer  int32
+		visible bool
+	}{
+		{name: "anonymous", viewer: 0, visible: false},
+		{name: "stranger", viewer: stranger, visible: false},
+		{name: "follower", viewer: follower, visible: true},
+		{name: "author", viewer: author, visible: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := seen(tt.viewer)
+			if !ids[quote.ID] {
+				t.Errorf("quote %d is public but was not listed", quote.ID)
+			}
+			for _, id := range hidden {
+				if ids[id] != tt.visible {
+					t.Errorf("post %d: seen = %v, want %v", id, ids[id], tt.visible)
+				}
+			}
+			want := int64(0)
+			if tt.visible {
+				want = 1
+			}
+			for _, got := range replyCounts(tt.viewer) {
+				if got != want {
+					t.Errorf("reply_count of post %d = %d, want %d", open.ID, got, want)
+				}
+			}
+		})
+	}
+}
+
+// Test_BlockAndMute checks, against the database named by
+// SOCIALMEDIA_TEST_DSN, that a block cuts every tie between two users and a
+// mute only quiets the muted user's own posts for the muter.
+func Test_BlockAndMute(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "blk" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	newPost := func(userID int32) int32 {
+		res, err := ps.CreatePost(ctx, CreatePostParams{Userid: userID, Title: nonce, Description: nonce})
+		must(err)
+		return res.ID
+	}
+	listed := func(viewer, author int32) int {
+		rows, err := ps.GetPosts(ctx, GetPostsParams{UserID: author, ViewerID: viewer, Limit: 20})
+		must(err)
+		return len(rows)
+	}
+	blocker, blocked, muter := newUser("blocker"), newUser("blocked"), newUser("muter")
+	blockerPost, blockedPost := newPost(blocker), newPost(blocked)
+	newPost(muter)
+
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: blocked, FolloweeID: blocker}))
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: blocker, FolloweeID: blocked}))
+	must(us.BlockUser(ctx, BlockUserParams{BlockerID: blocker, BlockedID: blocked}))
+	must(us.MuteUser(ctx, MuteUserParams{MuterID: muter, MutedID: blocker}))
+
+	var follows int
+	must(db.QueryRowContext(ctx, "SELECT count(*) FROM user_follows WHERE follower_id IN ($1, $2) AND followee_id IN ($1, $2)", blocker, blocked).Scan(&follows))
+	if follows != 0 {
+		t.Errorf("follows between blocked users = %d, want 0", follows)
+	}
+	if err := us.FollowUser(ctx, FollowUserParams{FollowerID: blocked, FolloweeID: blocker}); err != ErrBlocked {
+		t.Errorf("FollowUser() error = %v, want %v", err, ErrBlocked)
+	}
+	if _, err := ps.CreatePost(ctx, CreatePostParams{Userid: blocked, Title: nonce, Description: nonce, InReplyToID: blockerPost}); err != ErrBlocked {
+		t.Errorf("CreatePost() reply error = %v, want %v", err, ErrBlocked)
+	}
+	if _, err := ps.Repost(ctx, RepostParams{UserID: blocker, PostID: blockedPost}); err != ErrBlocked {
+		t.Errorf("Repost() error = %v, want %v", err, ErrBlocked)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO reactions (post_id, user_id, kind) VALUES ($1, $2, 'like')", blockerPost, blocked); !isPQError(err, pqBlocked) {
+		t.Errorf("reaction error = %v, want SQLSTATE %s", err, pqBlocked)
+	}
+
+	tests := []struct {
+		name   string
+		viewer int32
+		author int32
+		want   int
+	}{
+		{name: "blocked user does not see blocker", viewer: blocked, author: blocker, want: 0},
+		{name: "blocker does not see blocked user", viewer: blocker, author: blocked, want: 0},
+		{name: "muter does not see muted user", viewer: muter, author: blocker, want: 0},
+		{name: "muted user still sees muter", viewer: blocker, author: muter, want: 1},
+		{name: "others still see blocker", viewer: 0, author: blocker, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listed(tt.viewer, tt.author); got != tt.want {
+				t.Errorf("GetPosts() returned %d posts, want %d", got, tt.want)
+			}
+		})
+	}
+
+	replier := newUser("replier")
+	reply, err := ps.CreatePost(ctx, CreatePostParams{Userid: replier, Title: nonce, Description: nonce, InReplyToID: blockerPost})
+	must(err)
+	if _, err := ps.GetPost(ctx, GetPostParams{ID: reply.ID, ViewerID: muter}); err != nil {
+		t.Errorf("GetPost() reply in a muted user's thread error = %v, want nil", err)
+	}
+	if _, err := ps.GetPost(ctx, GetPostParams{ID: reply.ID, ViewerID: blocked}); err != sql.ErrNoRows {
+		t.Errorf("GetPost() reply in a blocker's thread error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	must(us.UnmuteUser(ctx, UnmuteUserParams{MuterID: muter, MutedID: blocker}))
+	if got := listed(muter, blocker); got != 1 {
+		t.Errorf("GetPosts() after unmute returned %d posts, want 1", got)
+	}
+	must(us.UnblockUser(ctx, UnblockUserParams{BlockerID: blocker, BlockedID: blocked}))
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: blocked, FolloweeID: blocker}))
+}
+
+// openMigratedTestDB opens the database named by SOCIALMEDIA_TEST_DSN and
+// applies every migration, skipping the test when the variable is unset.
+func openMigratedTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("SOCIALMEDIA_TEST_DSN")
+	if dsn == "" {
+		t.Skip("SOCIALMEDIA_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migration.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func mustNoError(t *testing.T) func(error) {
+	return func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test_ScheduledPosts checks, against the database named by
+// SOCIALMEDIA_TEST_DSN, that drafts and scheduled posts stay with their author
+// and that schedulers racing each other publish every due post exactly once.
+func Test_ScheduledPosts(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "sch" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	author, err := us.CreateUser(ctx, "author "+nonce)
+	must(err)
+
+	draft, err := ps.CreatePost(ctx, CreatePostParams{Userid: author.ID, Title: nonce, Description: nonce, Status: PostStatusDraft})
+	must(err)
+	var due []int32
+	for i := 0; i < 5; i++ {
+		res, err := ps.CreatePost(ctx, CreatePostParams{Userid: author.ID, Title: nonce, Description: nonce, Status: PostStatusScheduled, PublishAt: time.Now().Add(-time.Minute)})
+		must(err)
+		due = append(due, res.ID)
+	}
+
+	for _, id := range append([]int32{draft.ID}, due...) {
+		if _, err := ps.GetPost(ctx, GetPostParams{ID: id}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetPost(%d) anonymous error = %v, want %v", id, err, sql.ErrNoRows)
+		}
+		if _, err := ps.GetPost(ctx, GetPostParams{ID: id, ViewerID: author.ID}); err != nil {
+			t.Errorf("GetPost(%d) author error = %v", id, err)
+		}
+	}
+
+	counts := make(chan int, 4)
+	for i := 0; i < cap(counts); i++ {
+		go func() {
+			ss, _ := NewSchedulerService(post.New(db), nil)
+			n, err := ss.PublishDuePosts(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+			counts <- n
+		}()
+	}
+	total := 0
+	for i := 0; i < cap(counts); i++ {
+		total += <-counts
+	}
+	// Other tests may share the database, so only this test's posts are
+	// counted exactly.
+	if total < len(due) {
+		t.Errorf("published %d posts, want at least %d", total, len(due))
+	}
+	for _, id := range due {
+		res, err := ps.GetPost(ctx, GetPostParams{ID: id})
+		must(err)
+		if res.Status != PostStatusPublished {
+			t.Errorf("post %d status = %q, want %q", id, res.Status, PostStatusPublished)
+		}
+	}
+	var published int
+	must(db.QueryRowContext(ctx, "SELECT count(*) FROM posts WHERE userid = $1 AND status = 'published'", author.ID).Scan(&published))
+	if published != len(due) {
+		t.Errorf("published posts = %d, want %d", published, len(due))
+	}
+}
+
+// Test_PostRevisions checks, against the database named by
+// SOCIALMEDIA_TEST_DSN, that edits and reverts append revisions, that the
+// edited flag follows them and that stored revisions cannot be rewritten.
+func Test_PostRevisions(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ts, _ := NewTagService(tag.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "rev" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	author, err := us.CreateUser(ctx, "author "+nonce)
+	must(err)
+	admin, err := us.CreateUser(ctx, "admin "+nonce)
+	must(err)
+	_, err = db.ExecContext(ctx, "UPDATE users SET is_admin = true WHERE id = $1", admin.ID)
+	must(err)
+	first, err := ts.CreateTag(ctx, nonce+"a")
+	must(err)
+	second, err := ts.CreateTag(ctx, nonce+"b")
+	must(err)
+
+	created, err := ps.CreatePost(ctx, CreatePostParams{Userid: author.ID, Title: "first", Description: "sun\nsea", TagID: []int32{first.ID}})
+	must(err)
+	res, err := ps.GetPost(ctx, GetPostParams{ID: created.ID})
+	must(err)
+	if res.Edited {
+		t.Errorf("new post edited = true, want false")
+	}
+
+	_, err = ps.UpdatePost(ctx, UpdatePostParams{ID: created.ID, Title: "second", Description: "sun\nsand", TagID: []int32{second.ID}})
+	must(err)
+	res, err = ps.GetPost(ctx, GetPostParams{ID: created.ID})
+	must(err)
+	if !res.Edited || res.EditedAt == nil {
+		t.Errorf("updated post edited = %v at %v, want true", res.Edited, res.EditedAt)
+	}
+
+	diff, err := ps.GetPostRevisionDiff(ctx, GetPostRevisionDiffParams{ID: created.ID, From: 1, To: 2})
+	must(err)
+	if !reflect.DeepEqual(diff.TagsAdded, []int32{second.ID}) || !reflect.DeepEqual(diff.TagsRemoved, []int32{first.ID}) {
+		t.Errorf("diff tags = +%v -%v, want +[%d] -[%d]", diff.TagsAdded, diff.TagsRemoved, second.ID, first.ID)
+	}
+
+	if _, err := ps.RevertPost(ctx, RevertPostParams{ID: created.ID, Revision: 1, UserID: author.ID}); !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("RevertPost() by author error = %v, want %v", err, ErrNotAdmin)
+	}
+	reverted, err := ps.RevertPost(ctx, RevertPostParams{ID: created.ID, Revision: 1, UserID: admin.ID})
+	must(err)
+	if reverted.Title != "first" || !reflect.DeepEqual(reverted.TagID, []int32{first.ID}) {
+		t.Errorf("RevertPost() = %q %v, want %q [%d]", reverted.Title, reverted.TagID, "first", first.ID)
+	}
+
+	revs, err := ps.GetPostRevisions(ctx, GetPostRevisionsParams{ID: created.ID, Limit: 10})
+	must(err)
+	if len(revs) != 3 || revs[0].Revision != 3 || revs[0].EditorID != admin.ID || revs[2].EditorID != author.ID {
+		t.Errorf("GetPostRevisions() = %+v, want 3 revisions, newest by the admin", revs)
+	}
+
+	if _, err := db.ExecContext(ctx, "UPDATE post_revisions SET title = 'forged' WHERE post_id = $1", created.ID); err == nil {
+		t.Errorf("rewriting a revision succeeded, want an error")
+	}
+}
+
+// Test_MediaAttachments checks that media is deduplicated per user, can only
+// be attached by its uploader, and is only served to those who may see a
+// post it is attached to.
+func Test_MediaAttachments(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+	ms, _ := NewMediaService(media.New(db), testStore(t), 1<<20, 64)
+
+	must := mustNoError(t)
+	nonce := "med" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	author, err := us.CreateUser(ctx, "author "+nonce)
+	must(err)
+	stranger, err := us.CreateUser(ctx, "stranger "+nonce)
+	must(err)
+
+	file := []byte("%PDF-1.4\n" + nonce)
+	uploaded, err := ms.UploadMedia(ctx, UploadMediaParams{UserID: author.ID, Data: file})
+	must(err)
+	again, err := ms.UploadMedia(ctx, UploadMediaParams{UserID: author.ID, Data: file})
+	must(err)
+	if again.ID != uploaded.ID {
+		t.Errorf("second upload id = %d, want %d", again.ID, uploaded.ID)
+	}
+
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: stranger.ID, Title: nonce, Description: nonce, MediaIDs: []int32{uploaded.ID}})
+	if !errors.Is(err, ErrMediaNotOwned) {
+		t.Errorf("CreatePost() with media of another user error = %v, want %v", err, ErrMediaNotOwned)
+	}
+
+	if _, err := ms.GetMediaContent(ctx, GetMediaContentParams{ID: uploaded.ID, ViewerID: stranger.ID}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetMediaContent() of unattached media by a stranger error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	created, err := ps.CreatePost(ctx, CreatePostParams{Userid: author.ID, Title: nonce, Description: nonce, Visibility: PostVisibilityPrivate, MediaIDs: []int32{uploaded.ID}})
+	must(err)
+	res, err := ps.GetPost(ctx, GetPostParams{ID: created.ID, ViewerID: author.ID})
+	must(err)
+	if len(res.Media) != 1 || res.Media[0].ID != uploaded.ID {
+		t.Errorf("GetPost() media = %+v, want [%d]", res.Media, uploaded.ID)
+	}
+	if _, err := ms.GetMediaContent(ctx, GetMediaContentParams{ID: uploaded.ID, ViewerID: stranger.ID}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetMediaContent() of private post media by a stranger error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	public, err := ps.CreatePost(ctx, CreatePostParams{Userid: author.ID, Title: nonce, Description: nonce, MediaIDs: []int32{uploaded.ID}})
+	must(err)
+	content, err := ms.GetMediaContent(ctx, GetMediaContentParams{ID: uploaded.ID, ViewerID: stranger.ID})
+	must(err)
+	content.Body.Close()
+	if !reflect.DeepEqual(public.MediaIDs, []int32{uploaded.ID}) {
+		t.Errorf("CreatePost() media ids = %v, want [%d]", public.MediaIDs, uploaded.ID)
+	}
+}
+
+func Test_Profiles(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+	ms, _ := NewMediaService(media.New(db), testStore(t), 1<<20, 64)
+	now := time.Now()
+	profiles, _ := NewProfileService(user.New(db), NewTxRunner(db, noop.NewTracerProvider()), time.Hour, time.Hour, func() time.Time { return now })
+
+	must := mustNoError(t)
+	nonce := "p" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	owner, err := us.CreateUser(ctx, "owner "+nonce)
+	must(err)
+	other, err := us.CreateUser(ctx, "other "+nonce)
+	must(err)
+
+	var buf bytes.Buffer
+	must(png.Encode(&buf, testImage(4, 4, color.White)))
+	avatar, err := ms.UploadMedia(ctx, UploadMediaParams{UserID: owner.ID, Data: buf.Bytes()})
+	must(err)
+	_, err = profiles.SetProfile(ctx, SetProfileParams{UserID: other.ID, Handle: nonce + "_x", AvatarMediaID: avatar.ID})
+	if !errors.Is(err, ErrAvatarNotOwned) {
+		t.Errorf("SetProfile() with the avatar of another user error = %v, want %v", err, ErrAvatarNotOwned)
+	}
+
+	_, err = profiles.SetProfile(ctx, SetProfileParams{UserID: owner.ID, Handle: nonce, Bio: "hello", AvatarMediaID: avatar.ID})
+	must(err)
+	_, err = profiles.SetProfile(ctx, SetProfileParams{UserID: other.ID, Handle: nonce})
+	if !errors.Is(err, ErrHandleTaken) {
+		t.Errorf("SetProfile() with a taken handle error = %v, want %v", err, ErrHandleTaken)
+	}
+	content, err := ms.GetMediaContent(ctx, GetMediaContentParams{ID: avatar.ID, ViewerID: other.ID})
+	must(err)
+	content.Body.Close()
+
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: owner.ID, Title: nonce, Description: nonce})
+	must(err)
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: owner.ID, Title: nonce, Description: nonce, Visibility: PostVisibilityPrivate})
+	must(err)
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: other.ID, FolloweeID: owner.ID}))
+	got, err := profiles.GetProfile(ctx, GetProfileParams{Handle: nonce, ViewerID: other.ID})
+	must(err)
+	if got.ID != owner.ID || got.Bio != "hello" || got.AvatarMediaID != avatar.ID || got.PostCount != 1 || got.FollowerCount != 1 || got.FollowingCount != 0 {
+		t.Errorf("GetProfile() = %+v", got)
+	}
+
+	_, err = profiles.SetProfile(ctx, SetProfileParams{UserID: owner.ID, Handle: nonce + "_new"})
+	var cooldownErr *HandleCooldownError
+	if !errors.As(err, &cooldownErr) {
+		t.Errorf("SetProfile() within the cooldown error = %v, want a HandleCooldownError", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	_, err = profiles.SetProfile(ctx, SetProfileParams{UserID: owner.ID, Handle: nonce + "_new"})
+	must(err)
+	got, err = profiles.GetProfile(ctx, GetProfileParams{Handle: nonce})
+	must(err)
+	if got.Handle != nonce+"_new" {
+		t.Errorf("GetProfile() of the old handle = %q, want %q", got.Handle, nonce+"_new")
+	}
+	_, err = profiles.SetProfile(ctx, SetProfileParams{UserID: other.ID, Handle: nonce})
+	if !errors.Is(err, ErrHandleTaken) {
+		t.Errorf("SetProfile() with a redirecting handle error = %v, want %v", err, ErrHandleTaken)
+	}
+}
+
+// Test_Notifications checks, against the database named by
+// SOCIALMEDIA_TEST_DSN, that follows, reactions, replies and mentions notify
+// the right user, that reactions to one post are grouped, and that turned off
+// kinds and blocked users stay silent.
+func Test_Notifications(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+	profiles, _ := NewProfileService(user.New(db), NewTxRunner(db, noop.NewTracerProvider()), 0, 0, nil)
+	ns, _ := NewNotificationService(notification.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "n" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	react := func(postID, userID int32) {
+		_, err := db.ExecContext(ctx, "INSERT INTO reactions (post_id, user_id, kind) VALUES ($1, $2, 'like')", postID, userID)
+		must(err)
+	}
+	notifications := func(userID int32) []NotificationRow {
+		_, err := ns.ProcessEvents(ctx)
+		must(err)
+		res, err := ns.GetNotifications(ctx, GetNotificationsParams{UserID: userID, Limit: 100})
+		must(err)
+		return res
+	}
+	author, fan1, fan2, blocked := newUser("author"), newUser("fan1"), newUser("fan2"), newUser("blocked")
+	_, err := profiles.SetProfile(ctx, SetProfileParams{UserID: author, Handle: nonce})
+	must(err)
+
+	p, err := ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce, Description: nonce})
+	must(err)
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: fan1, FolloweeID: author}))
+	react(p.ID, fan1)
+	react(p.ID, fan2)
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: fan1, Title: nonce, Description: nonce, InReplyToID: p.ID})
+	must(err)
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: fan2, Title: nonce, Description: "hi @" + nonce})
+	must(err)
+
+	got := map[string]NotificationRow{}
+	for _, n := range notifications(author) {
+		got[n.Kind] = n
+	}
+	if len(got) != 4 {
+		t.Errorf("GetNotifications() kinds = %v, want follow, reaction, reply and mention", got)
+	}
+	if n := got[NotificationKindReaction]; n.PostID != p.ID || n.ActorCount != 2 || len(n.Actors) != 2 || n.Actors[0].ID != fan2 {
+		t.Errorf("reaction notification = %+v, want fan2 and fan1 grouped on post %d", n, p.ID)
+	}
+	if n := got[NotificationKindMention]; n.ActorCount != 1 || n.Actors[0].ID != fan2 {
+		t.Errorf("mention notification = %+v, want fan2", n)
+	}
+
+	read, err := ns.MarkAllRead(ctx, author)
+	must(err)
+	if read != 4 {
+		t.Errorf("MarkAllRead() = %d, want 4", read)
+	}
+
+	_, err = ns.SetPreferences(ctx, SetNotificationPreferencesParams{UserID: author, Preferences: NotificationPreferences{NotificationKindFollow: false}})
+	must(err)
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: fan2, FolloweeID: author}))
+	must(us.BlockUser(ctx, BlockUserParams{BlockerID: author, BlockedID: blocked}))
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: blocked, Title: nonce, Description: "hey @" + nonce})
+	must(err)
+	_, err = ns.ProcessEvents(ctx)
+	must(err)
+	unread, err := ns.GetNotifications(ctx, GetNotificationsParams{UserID: author, UnreadOnly: true, Limit: 100})
+	must(err)
+	if len(unread) != 0 {
+		t.Errorf("GetNotifications() after turning follows off and blocking = %+v, want none", unread)
+	}
+}
+
+// Test_Stream checks, against the database named by SOCIALMEDIA_TEST_DSN,
+// that stream events reach only the users allowed to see them, both live and
+// when replayed after a reconnect.
+func Test_Stream(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+	ns, _ := NewNotificationService(notification.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+	sts, _ := NewStreamService(stream.New(db), 64, 60)
+
+	must := mustNoError(t)
+	nonce := "st" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	author, follower, stranger := newUser("author"), newUser("follower"), newUser("stranger")
+	must(us.FollowUser(ctx, FollowUserParams{FollowerID: follower, FolloweeID: author}))
+
+	var since int64
+	must(db.QueryRowContext(ctx, "SELECT coalesce(max(id), 0) FROM stream_events").Scan(&since))
+	subscribe := func(userID int32) *Subscription {
+		sub, err := sts.Subscribe(ctx, SubscribeParams{UserID: userID})
+		must(err)
+		t.Cleanup(func() { sts.Unsubscribe(sub) })
+		return sub
+	}
+	authorSub, followerSub, strangerSub := subscribe(author), subscribe(follower), subscribe(stranger)
+
+	public, err := ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce, Description: nonce})
+	must(err)
+	_, err = ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce, Description: nonce, Visibility: PostVisibilityPrivate})
+	must(err)
+	_, err = db.ExecContext(ctx, "INSERT INTO reactions (post_id, user_id, kind) VALUES ($1, $2, 'like')", public.ID, stranger)
+	must(err)
+	_, err = ns.ProcessEvents(ctx)
+	must(err)
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM stream_events WHERE id > $1 ORDER BY id", since)
+	must(err)
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		must(rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	must(rows.Err())
+	must(sts.Dispatch(ctx, ids))
+
+	// Other tests may share the database, so only events about this test's
+	// posts and users are compared.
+	summarize := func(events []StreamEvent) []string {
+		var res []string
+		for _, e := range events {
+			var data struct {
+				ID     int32 `json:"id"`
+				PostID int32 `json:"post_id"`
+			}
+			must(json.Unmarshal(e.Data, &data))
+			switch {
+			case e.Kind == StreamEventPost && data.ID == public.ID:
+				res = append(res, "public post")
+			case e.Kind == StreamEventPost:
+				res = append(res, "other post")
+			case e.Kind == StreamEventReactionCount && data.PostID == public.ID:
+				res = append(res, "reaction count")
+			case e.Kind == StreamEventNotification:
+				res = append(res, "notification")
+			}
+		}
+		return res
+	}
+	received := func(sub *Subscription) []string {
+		var events []StreamEvent
+		for len(sub.Events) > 0 {
+			events = append(events, <-sub.Events)
+		}
+		return summarize(events)
+	}
+
+	tests := []struct {
+		name string
+		sub  *Subscription
+		want []string
+	}{
+		{name: "author", sub: authorSub, want: []string{"public post", "other post", "reaction count", "notification", "notification"}},
+		{name: "follower", sub: followerSub, want: []string{"public post", "reaction count"}},
+		{name: "stranger", sub: strangerSub, want: []string{"reaction count"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := received(tt.sub); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	resumed, err := sts.Subscribe(ctx, SubscribeParams{UserID: follower, LastEventID: since})
+	must(err)
+	sts.Unsubscribe(resumed)
+	if got, want := summarize(resumed.Backlog), []string{"public post", "reaction count"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed %v, want %v", got, want)
+	}
+}
+
+func Test_DirectMessages(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ms, _ := NewMessageService(message.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+	sts, _ := NewStreamService(stream.New(db), 64, 60)
+
+	must := mustNoError(t)
+	nonce := "dm" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	ana, budi, citra, blocker := newUser("ana"), newUser("budi"), newUser("citra"), newUser("blocker")
+	must(us.BlockUser(ctx, BlockUserParams{BlockerID: blocker, BlockedID: ana}))
+
+	var since int64
+	must(db.QueryRowContext(ctx, "SELECT coalesce(max(id), 0) FROM stream_events").Scan(&since))
+	subscribe := func(userID int32) *Subscription {
+		sub, err := sts.Subscribe(ctx, SubscribeParams{UserID: userID})
+		must(err)
+		t.Cleanup(func() { sts.Unsubscribe(sub) })
+		return sub
+	}
+	budiSub, citraSub := subscribe(budi), subscribe(citra)
+
+	direct, err := ms.StartConversation(ctx, StartConversationParams{UserID: ana, ParticipantIDs: []int32{budi}})
+	must(err)
+	again, err := ms.StartConversation(ctx, StartConversationParams{UserID: budi, ParticipantIDs: []int32{ana}})
+	must(err)
+	if again.ID != direct.ID || !again.Direct {
+		t.Errorf("restarting the 1:1 conversation gave %d, want %d", again.ID, direct.ID)
+	}
+	group, err := ms.StartConversation(ctx, StartConversationParams{UserID: ana, ParticipantIDs: []int32{budi, citra}})
+	must(err)
+	if _, err := ms.StartConversation(ctx, StartConversationParams{UserID: ana, ParticipantIDs: []int32{blocker}}); !errors.Is(err, ErrBlocked) {
+		t.Errorf("StartConversation() with a blocker error = %v, want %v", err, ErrBlocked)
+	}
+	if _, err := ms.StartConversation(ctx, StartConversationParams{UserID: citra, ParticipantIDs: []int32{budi, blocker, ana}}); !errors.Is(err, ErrBlocked) {
+		t.Errorf("StartConversation() with users blocking each other error = %v, want %v", err, ErrBlocked)
+	}
+
+	send := func(conversationID, userID int32, body string) Message {
+		res, err := ms.SendMessage(ctx, SendMessageParams{ConversationID: conversationID, UserID: userID, Body: body})
+		must(err)
+		return res
+	}
+	send(group.ID, citra, "group hello")
+	hi := send(direct.ID, ana, "hi")
+	hey := send(direct.ID, budi, "hey")
+	last := send(direct.ID, ana, "again")
+	if _, err := ms.SendMessage(ctx, SendMessageParams{ConversationID: direct.ID, UserID: citra, Body: "intruder"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("SendMessage() by an outsider error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// Sending marks what came before as read, so only "again" is unread.
+	convs, err := ms.GetConversations(ctx, GetConversationsParams{UserID: budi, Limit: 10})
+	must(err)
+	var summary []string
+	for _, c := range convs {
+		body := ""
+		if c.LastMessage != nil {
+			body = c.LastMessage.Body
+		}
+		summary = append(summary, strconv.Itoa(int(c.ID))+" "+body+" "+strconv.Itoa(int(c.UnreadCount)))
+	}
+	want := []string{
+		strconv.Itoa(int(direct.ID)) + " again 1",
+		strconv.Itoa(int(group.ID)) + " group hello 1",
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("GetConversations() = %v, want %v", summary, want)
+	}
+
+	page, err := ms.GetMessages(ctx, GetMessagesParams{ConversationID: direct.ID, UserID: budi, Limit: 2})
+	must(err)
+	older, err := ms.GetMessages(ctx, GetMessagesParams{ConversationID: direct.ID, UserID: budi, BeforeID: page[len(page)-1].ID, Limit: 2})
+	must(err)
+	var ids []int64
+	for _, m := range append(page, older...) {
+		ids = append(ids, m.ID)
+	}
+	if want := []int64{last.ID, hey.ID, hi.ID}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("GetMessages() pages = %v, want %v", ids, want)
+	}
+	if _, err := ms.GetMessages(ctx, GetMessagesParams{ConversationID: direct.ID, UserID: citra, Limit: 2}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetMessages() by an outsider error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	receipt, err := ms.MarkRead(ctx, MarkConversationReadParams{ConversationID: direct.ID, UserID: budi})
+	must(err)
+	if receipt.LastReadMessageID != last.ID {
+		t.Errorf("MarkRead() read up to %d, want %d", receipt.LastReadMessageID, last.ID)
+	}
+	receipt, err = ms.MarkRead(ctx, MarkConversationReadParams{ConversationID: direct.ID, UserID: budi, MessageID: hi.ID})
+	must(err)
+	if receipt.LastReadMessageID != last.ID {
+		t.Errorf("MarkRead() moved the receipt back to %d", receipt.LastReadMessageID)
+	}
+	conv, err := ms.GetConversation(ctx, GetConversationParams{ID: direct.ID, UserID: budi})
+	must(err)
+	if conv.UnreadCount != 0 {
+		t.Errorf("GetConversation() unread = %d, want 0", conv.UnreadCount)
+	}
+
+	must(us.BlockUser(ctx, BlockUserParams{BlockerID: citra, BlockedID: budi}))
+	if _, err := ms.SendMessage(ctx, SendMessageParams{ConversationID: group.ID, UserID: budi, Body: "still here?"}); !errors.Is(err, ErrBlocked) {
+		t.Errorf("SendMessage() next to a blocker error = %v, want %v", err, ErrBlocked)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM stream_events WHERE id > $1 ORDER BY id", since)
+	must(err)
+	var eventIDs []int64
+	for rows.Next() {
+		var id int64
+		must(rows.Scan(&id))
+		eventIDs = append(eventIDs, id)
+	}
+	must(rows.Err())
+	must(sts.Dispatch(ctx, eventIDs))
+
+	received := func(sub *Subscription) []string {
+		var res []string
+		for len(sub.Events) > 0 {
+			e := <-sub.Events
+			if e.Kind != StreamEventMessage {
+				continue
+			}
+			var data struct {
+				ID             int64   `json:"id"`
+				ConversationID int32   `json:"conversation_id"`
+				Body           *string `json:"body"`
+			}
+			must(json.Unmarshal(e.Data, &data))
+			if data.Body != nil {
+				t.Errorf("message event %d carries its body", e.ID)
+			}
+			m, err := ms.GetMessage(ctx, GetMessageParams{ConversationID: data.ConversationID, ID: data.ID, UserID: sub.UserID})
+			if errors.Is(err, sql.ErrNoRows) {
+				res = append(res, "hidden")
+				continue
+			}
+			must(err)
+			res = append(res, m.Body)
+		}
+		return res
+	}
+	// The event still names citra's message, but budi cannot read it once
+	// citra blocks them.
+	if got, want := received(budiSub), []string{"hidden", "hi", "hey", "again"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("budi received %v, want %v", got, want)
+	}
+	if got, want := received(citraSub), []string{"group hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("citra received %v, want %v", got, want)
+	}
+}
+
+func Test_Reactions(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "rct" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	reactions := func(author int32) int64 {
+		rows, err := ps.GetPosts(ctx, GetPostsParams{UserID: author, ViewerID: author, Limit: 20})
+		must(err)
+		if len(rows) != 1 {
+			t.Fatalf("GetPosts() returned %d posts, want 1", len(rows))
+		}
+		return rows[0].ReactionCount
+	}
+	author, fan, stranger := newUser("author"), newUser("fan"), newUser("stranger")
+	open, err := ps.CreatePost(ctx, CreatePostParams{Userid: author, Title: nonce, Description: nonce})
+	must(err)
+	hidden, err := ps.CreatePost(ctx, CreatePostParams{Userid: stranger, Title: nonce, Description: nonce, Visibility: PostVisibilityFollowers})
+	must(err)
+
+	must(ps.React(ctx, ReactParams{UserID: fan, PostID: open.ID, Kind: ReactionLike}))
+	must(ps.React(ctx, ReactParams{UserID: fan, PostID: open.ID, Kind: ReactionLove}))
+	if got := reactions(author); got != 1 {
+		t.Errorf("reaction_count after reacting twice = %d, want 1", got)
+	}
+	if err := ps.React(ctx, ReactParams{UserID: fan, PostID: hidden.ID, Kind: ReactionLike}); err != sql.ErrNoRows {
+		t.Errorf("React() on hidden post error = %v, want %v", err, sql.ErrNoRows)
+	}
+	must(ps.Unreact(ctx, UnreactParams{UserID: fan, PostID: open.ID}))
+	if got := reactions(author); got != 0 {
+		t.Errorf("reaction_count after unreacting = %d, want 0", got)
+	}
+}
+
+func Test_PostFilters(t *testing.T) {
+	ctx := context.Background()
+	db := openMigratedTestDB(t)
+
+	us, _ := NewUserService(user.New(db))
+	ts, _ := NewTagService(tag.New(db))
+	ps, _ := NewPostService(post.New(db), tag.New(db), post_tags.New(db), bookmark.New(db), media.New(db), NewTxRunner(db, noop.NewTracerProvider()))
+
+	must := mustNoError(t)
+	nonce := "flt" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	newUser := func(name string) int32 {
+		res, err := us.CreateUser(ctx, name+" "+nonce)
+		must(err)
+		return res.ID
+	}
+	newTag := func(name string) int32 {
+		res, err := ts.CreateTag(ctx, name+nonce)
+		must(err)
+		return res.ID
+	}
+	author, other := newUser("author"), newUser("other")
+	red, blue := newTag("red"), newTag("blue")
+
+	// Posts are backdated so the date range and order do not depend on
+	// how fast they were inserted.
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newPost := func(userID int32, day int, tagIDs ...int32) int32 {
+		res, err := ps.CreatePost(ctx, CreatePostParams{Userid: userID, Title: nonce, Description: nonce, TagID: tagIDs})
+		must(err)
+		_, err = db.ExecContext(ctx, "UPDATE posts SET created_at = $1 WHERE id = $2", base.AddDate(0, 0, day), res.ID)
+		must(err)
+		return res.ID
+	}
+	first := newPost(author, 0, red)
+	second := newPost(author, 1, red, blue)
+	third := newPost(author, 2, blue)
+	foreign := newPost(other, 1, red)
+
+	must(ps.React(ctx, ReactParams{UserID: other, PostID: second, Kind: ReactionLike}))
+	must(ps.React(ctx, ReactParams{UserID: author, PostID: second, Kind: ReactionWow}))
+	must(ps.React(ctx, ReactParams{UserID: other, PostID: third, Kind: ReactionSad}))
+
+	tests := []struct {
+		name string
+		arg  GetPostsParams
+		want []int32
+	}{
+		{
+			name: "by user",
+			arg:  GetPostsParams{UserID: author, Limit: 20},
+			want: []int32{third, second, first},
+		},
+		{
+			name: "any tag",
+			arg:  GetPostsParams{TagIDs: []int32{red}, Sort: PostSortOldest, Limit: 20},
+			want: []int32{first, second, foreign},
+		},
+		{
+			name: "all tags",
+			arg:  GetPostsParams{TagIDs: []int32{red, blue}, MatchAllTags: true, Limit: 20},
+			want: []int32{second},
+		},
+		{
+			name: "date range excludes to",
+			arg:  GetPostsParams{UserID: author, CreatedFrom: base.AddDate(0, 0, 1), CreatedTo: base.AddDate(0, 0, 2), Limit: 20},
+			want: []int32{second},
+		},
+		{
+			name: "most reacted",
+			arg:  GetPostsParams{UserID: author, Sort: PostSortMostReacted, Limit: 20},
+			want: []int32{second, third, first},
+		},
+		{
+			name: "first page",
+			arg:  GetPostsParams{UserID: author, Limit: 2},
+			want: []int32{third, second},
+		},
+		{
+			name: "page after the cursor",
+			arg:  GetPostsParams{UserID: author, CursorCreatedAt: base.AddDate(0, 0, 1), CursorID: second, Limit: 2},
+			want: []int32{first},
+		},
+		{
+			name: "oldest page after the cursor",
+			arg:  GetPostsParams{UserID: author, Sort: PostSortOldest, CursorCreatedAt: base, CursorID: first, Limit: 20},
+			want: []int32{second, third},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ps.GetPosts(ctx, tt.arg)
+			must(err)
+			got := make([]int32, 0, len(rows))
+			for _, row := range rows {
+				got = append(got, row.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPosts() ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
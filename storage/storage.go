@@ -0,0 +1,25 @@
+// Package storage keeps uploaded files outside the database. Objects are
+// addressed by slash-separated keys and never change once written, so a key
+// that already exists is left as it is.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no object is stored under a key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrInvalidKey is returned for keys that are empty, absolute or climb out
+// of the store with "..".
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+type Storage interface {
+	// Put stores size bytes read from r under key. Putting a key that is
+	// already stored succeeds without reading r.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the object stored under key, or ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
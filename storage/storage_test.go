@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_Local(t *testing.T) {
+	s, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
+
+// testStorage checks the behaviour every Storage has to share, so each
+// implementation can be run through it.
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	err := s.Put(ctx, "ab/abcdef", strings.NewReader("hello"), 5, "text/plain")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// Objects never change, so a second put of a stored key keeps the first.
+	err = s.Put(ctx, "ab/abcdef", strings.NewReader("other"), 5, "text/plain")
+	if err != nil {
+		t.Fatalf("Put() again error = %v", err)
+	}
+
+	rc, err := s.Open(ctx, "ab/abcdef")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("Open() read %q, want %q", got, "hello")
+	}
+
+	if _, err := s.Open(ctx, "ab/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() missing error = %v, want %v", err, ErrNotFound)
+	}
+
+	err = s.Put(ctx, "ab/short", strings.NewReader("hel"), 5, "text/plain")
+	if err == nil {
+		t.Errorf("Put() short body succeeded, want an error")
+	}
+	if _, err := s.Open(ctx, "ab/short"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after a failed put error = %v, want %v", err, ErrNotFound)
+	}
+
+	for _, key := range []string{"", ".", "../escape", "/abs", "a//b"} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) error = %v, want %v", key, err, ErrInvalidKey)
+		}
+		if _, err := s.Open(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Open(%q) error = %v, want %v", key, err, ErrInvalidKey)
+		}
+	}
+}
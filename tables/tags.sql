@@ -1,7 +0,0 @@
-CREATE TABLE IF NOT EXISTS tags(
-   id SERIAL PRIMARY KEY,
-   tagName VARCHAR NOT NULL,
-   created_at TIMESTAMP,
-   updated_at TIMESTAMP,
-   deleted_at TIMESTAMP
-);
\ No newline at end of file
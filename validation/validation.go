@@ -0,0 +1,160 @@
+// Package validation checks request structs against declarative rules given
+// in `validate` struct tags and reports every violation at once.
+//
+// Supported rules, separated by commas:
+//
+//	required      the value must not be the zero value
+//	max=N, min=N  length bounds for strings (in runes) and slices
+//	gte=N, lte=N  bounds for numbers
+//	oneof=a b c   the value must be one of the listed words
+//
+// Fields are reported under their JSON name.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	CodeRequired     = "required"
+	CodeTooLong      = "too_long"
+	CodeTooShort     = "too_short"
+	CodeOutOfRange   = "out_of_range"
+	CodeInvalidEnum  = "invalid_enum"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Struct validates v, a struct or a pointer to one, and returns nil when
+// every rule holds.
+func Struct(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: Struct called with %s", rv.Kind()))
+	}
+
+	var errs Errors
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := fieldName(sf)
+		for _, rule := range strings.Split(tag, ",") {
+			if fe, ok := check(name, rv.Field(i), rule); !ok {
+				errs = append(errs, fe)
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func check(name string, v reflect.Value, rule string) (FieldError, bool) {
+	key, param, _ := strings.Cut(rule, "=")
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if key == "required" {
+				return FieldError{Field: name, Code: CodeRequired, Message: name + " is required"}, false
+			}
+			return FieldError{}, true
+		}
+		v = v.Elem()
+	}
+
+	switch key {
+	case "required":
+		if v.IsZero() {
+			return FieldError{Field: name, Code: CodeRequired, Message: name + " is required"}, false
+		}
+	case "max", "min":
+		n := mustInt(rule, param)
+		var length int
+		switch v.Kind() {
+		case reflect.String:
+			length = utf8.RuneCountInString(v.String())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			length = v.Len()
+		default:
+			panic(fmt.Sprintf("validation: rule %q on %s", rule, v.Kind()))
+		}
+		if key == "max" && length > n {
+			return FieldError{Field: name, Code: CodeTooLong, Message: fmt.Sprintf("%s must be at most %d long", name, n)}, false
+		}
+		if key == "min" && length < n {
+			return FieldError{Field: name, Code: CodeTooShort, Message: fmt.Sprintf("%s must be at least %d long", name, n)}, false
+		}
+	case "gte", "lte":
+		n := mustInt(rule, param)
+		var val int64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			val = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			val = int64(v.Uint())
+		default:
+			panic(fmt.Sprintf("validation: rule %q on %s", rule, v.Kind()))
+		}
+		if key == "gte" && val < int64(n) {
+			return FieldError{Field: name, Code: CodeOutOfRange, Message: fmt.Sprintf("%s must be greater than or equal to %d", name, n)}, false
+		}
+		if key == "lte" && val > int64(n) {
+			return FieldError{Field: name, Code: CodeOutOfRange, Message: fmt.Sprintf("%s must be less than or equal to %d", name, n)}, false
+		}
+	case "oneof":
+		allowed := strings.Fields(param)
+		s := fmt.Sprint(v.Interface())
+		for _, a := range allowed {
+			if s == a {
+				return FieldError{}, true
+			}
+		}
+		return FieldError{Field: name, Code: CodeInvalidEnum, Message: fmt.Sprintf("%s must be one of %s", name, strings.Join(allowed, ", "))}, false
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule))
+	}
+	return FieldError{}, true
+}
+
+func fieldName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+func mustInt(rule, param string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid parameter in rule %q", rule))
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type sampleReq struct {
+	UserID int32    `json:"user_id" validate:"required,gte=1"`
+	Title  string   `json:"title" validate:"required,max=5"`
+	Tags   []int32  `json:"tag_ids" validate:"max=2"`
+	Sort   string   `json:"sort" validate:"oneof=newest oldest"`
+	Limit  *int     `json:"limit" validate:"gte=1,lte=100"`
+	Name   string   `json:"name" validate:"min=2"`
+	Ignore []string `json:"ignore"`
+}
+
+func Test_Struct(t *testing.T) {
+	zero, big := 0, 500
+	tests := []struct {
+		name string
+		req  sampleReq
+		want Errors
+	}{
+		{
+			name: "valid",
+			req: sampleReq{
+				UserID: 1,
+				Title:  "hello",
+				Tags:   []int32{1, 2},
+				Sort:   "newest",
+				Name:   "ab",
+			},
+		},
+		{
+			name: "multi byte title counts runes",
+			req: sampleReq{
+				UserID: 1,
+				Title:  "héllo",
+				Sort:   "oldest",
+				Name:   "ab",
+			},
+		},
+		{
+			name: "report every violation",
+			req: sampleReq{
+				Title: strings.Repeat("a", 6),
+				Tags:  []int32{1, 2, 3},
+				Sort:  "random",
+				Limit: &zero,
+				Name:  "a",
+			},
+			want: Errors{
+				{Field: "user_id", Code: CodeRequired, Message: "user_id is required"},
+				{Field: "title", Code: CodeTooLong, Message: "title must be at most 5 long"},
+				{Field: "tag_ids", Code: CodeTooLong, Message: "tag_ids must be at most 2 long"},
+				{Field: "sort", Code: CodeInvalidEnum, Message: "sort must be one of newest, oldest"},
+				{Field: "limit", Code: CodeOutOfRange, Message: "limit must be greater than or equal to 1"},
+				{Field: "name", Code: CodeTooShort, Message: "name must be at least 2 long"},
+			},
+		},
+		{
+			name: "upper bound on pointer",
+			req: sampleReq{
+				UserID: 1,
+				Title:  "a",
+				Sort:   "newest",
+				Limit:  &big,
+				Name:   "ab",
+			},
+			want: Errors{
+				{Field: "limit", Code: CodeOutOfRange, Message: "limit must be less than or equal to 100"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(&tt.req)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Struct() error = %v, want nil", err)
+				}
+				return
+			}
+			got, ok := err.(Errors)
+			if !ok {
+				t.Fatalf("Struct() error = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}